@@ -8,19 +8,29 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // ?tz= не должен зависеть от образа
 
+	"github.com/cwrk-planet/api-gateway/internal/app/attachment"
 	"github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/internal/app/export"
 	"github.com/cwrk-planet/api-gateway/internal/app/room"
 	"github.com/cwrk-planet/api-gateway/internal/config"
+	"github.com/cwrk-planet/api-gateway/internal/ratelimit"
 	httpserver "github.com/cwrk-planet/api-gateway/internal/server/http"
+	"github.com/cwrk-planet/api-gateway/internal/storage"
 	transport "github.com/cwrk-planet/api-gateway/internal/transport/http"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
 
+	"github.com/cwrk-planet/confload/pkg/confload"
 	"github.com/cwrk-planet/logger/pkg/logger"
 )
 
 func main() {
 	// 1) load config
-	cfg, err := config.Load()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, confload.ErrPrinted) {
+		os.Exit(0)
+	}
 	if err != nil {
 		println("failed to load config:", err.Error())
 		os.Exit(1)
@@ -36,6 +46,7 @@ func main() {
 		Debug:     cfg.Logging.Debug,
 	})
 	slog.Info("starting api-gateway", "version", cfg.Logging.Version)
+	slog.Info("effective config", "config", confload.Redacted(cfg)) // секреты заменены на ***
 
 	// 3) authClient init
 	authClient, err := auth.New(auth.Options{
@@ -61,10 +72,68 @@ func main() {
 	}
 	defer func() { _ = roomClient.Close() }()
 
+	// 3.2) attachments storage
+	store, err := storage.NewLocal(cfg.Attachments.StorageDir)
+	if err != nil {
+		slog.Error("attachments storage init failed", "err", err)
+		os.Exit(1)
+	}
+	attachments := attachment.New(roomClient, store, attachment.Options{
+		MaxFileSize:   cfg.Attachments.MaxFileSize,
+		AllowedTypes:  cfg.Attachments.AllowedTypes,
+		URLSecret:     cfg.Attachments.URLSecret,
+		URLTTL:        cfg.Attachments.URLTTL,
+		ThumbnailSize: cfg.Attachments.ThumbnailSize,
+		PublicBaseURL: cfg.Attachments.PublicBaseURL,
+	})
+
+	// 3.3) контекст до SIGINT/SIGTERM — на нём живут фоновые задачи и сервер (см. 6)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 3.4) выгрузка персональных данных — архивы рядом с вложениями
+	exports := export.New(authClient, roomClient, store, export.Options{
+		TTL:          cfg.Export.TTL,
+		BuildTimeout: cfg.Export.BuildTimeout,
+	})
+	go exports.Run(ctx, cfg.Export.CleanupInterval)
+
+	// 3.5) проверка токенов: без ключа gateway не видит ни пользователя, ни scopes — лимиты по IP, scopes проверяют сервисы
+	var verifier *auth.TokenVerifier
+	if cfg.Auth.PublicKeyPath != "" {
+		verifier, err = auth.NewTokenVerifier(cfg.Auth.PublicKeyPath, cfg.Auth.Issuer, cfg.Auth.Audience, cfg.Auth.ClockSkew)
+		if err != nil {
+			slog.Error("token verifier init failed", "err", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("auth.publicKeyPath is empty: per-user rate limits fall back to client IP, scopes are checked by backends only")
+	}
+
+	// 3.6) rate limit
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		var users ratelimit.UserFunc
+		if verifier != nil {
+			users = verifier.RequestUserID
+		}
+		store := ratelimit.NewMemoryStore()
+		go store.Run(ctx, cfg.RateLimit.CleanupInterval)
+		limiter = ratelimit.New(store, users, cfg.RateLimit.Policies, cfg.RateLimit.Default)
+	}
+
 	// 4) router init
+	proxies, _ := httputil.ParseTrustedProxies(cfg.HTTP.TrustedProxies) // уже проверено в Validate
 	router := transport.NewRouter(transport.Deps{
-		AuthClient: authClient,
-		RoomClient: roomClient,
+		AuthClient:        authClient,
+		RoomClient:        roomClient,
+		Attachments:       attachments,
+		AttachmentMaxSize: cfg.Attachments.MaxFileSize,
+		Exports:           exports,
+		RateLimit:         limiter,
+		Tokens:            verifier,
+		TrustedProxies:    proxies,
+		PublicBaseURL:     cfg.Attachments.PublicBaseURL,
 	})
 
 	// 5) server init
@@ -76,9 +145,6 @@ func main() {
 	}, router)
 
 	// 6) graceful shutdown
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer stop()
-
 	if err := srv.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
 		slog.Error("server stopped with error", "err", err)
 		os.Exit(1)
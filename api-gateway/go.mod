@@ -4,13 +4,16 @@ go 1.24.4
 
 require (
 	github.com/cwrk-planet/auth-service v0.0.0-20251024002527-f9b1e912bd45
+	github.com/cwrk-planet/authz v0.1.0
+	github.com/cwrk-planet/confload v0.1.0
 	github.com/cwrk-planet/logger v0.1.2
 	github.com/cwrk-planet/room-service v0.0.0-20251110183230-911b8fc7aee6
 	github.com/go-chi/chi/v5 v5.2.3
 	github.com/go-chi/cors v1.2.2
+	github.com/golang-jwt/jwt v3.2.2+incompatible
 	github.com/google/uuid v1.6.0
 	google.golang.org/grpc v1.76.0
-	gopkg.in/yaml.v3 v3.0.1
+	google.golang.org/protobuf v1.36.10
 )
 
 require (
@@ -22,10 +25,25 @@ require (
 	go.opentelemetry.io/otel/trace v1.38.0 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
-	golang.org/x/net v0.42.0 // indirect
-	golang.org/x/sys v0.35.0 // indirect
-	golang.org/x/text v0.29.0 // indirect
+	golang.org/x/net v0.45.0 // indirect
+	golang.org/x/sys v0.37.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20250929231259-57b25ae835d4 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20250929231259-57b25ae835d4 // indirect
-	google.golang.org/protobuf v1.36.10 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+// room-service живёт в этом же монорепо: новые RPC нужны сразу, без публикации версии
+replace github.com/cwrk-planet/room-service => ../room-service
+
+// нужен room-service (общий модуль событий)
+replace github.com/cwrk-planet/events => ../events
+
+// общий модуль проверки прав (его же использует room-service)
+replace github.com/cwrk-planet/authz => ../authz
+
+// общий загрузчик конфига
+replace github.com/cwrk-planet/confload => ../confload
+
+// клиент auth-service (proto) из этого же монорепо
+replace github.com/cwrk-planet/auth-service => ../auth-service
@@ -0,0 +1,293 @@
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/internal/storage"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sniffLen        = 512
+	maxFileNameLen  = 255
+	defaultFileName = "file"
+)
+
+type Options struct {
+	MaxFileSize   int64
+	AllowedTypes  []string
+	URLSecret     string
+	URLTTL        time.Duration
+	ThumbnailSize int
+	PublicBaseURL string
+}
+
+// Service — загрузка вложений: байты кладём в Storage, метаданные и квоту
+// ведёт room-service. Скачивание — только по подписанной ссылке.
+type Service struct {
+	room   approom.Client
+	store  storage.Storage
+	opts   Options
+	signer signer
+	now    func() time.Time
+}
+
+func New(room approom.Client, store storage.Storage, opts Options) *Service {
+	return &Service{
+		room:   room,
+		store:  store,
+		opts:   opts,
+		signer: signer{secret: []byte(opts.URLSecret)},
+		now:    time.Now,
+	}
+}
+
+type URLResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Download — открытый поток файла для отдачи клиенту.
+type Download struct {
+	Body        io.ReadCloser
+	ContentType string
+	FileName    string
+}
+
+// Upload сохраняет файл: тип определяем по содержимому (а не по заголовку клиента),
+// размер режем на лету, превью — best effort.
+func (s *Service) Upload(ctx context.Context, authHeader string, userID int64, roomID, fileName string, r io.Reader) (approom.CreateAttachmentResponse, error) {
+	// roomID попадает в ключ хранилища — принимаем только uuid
+	if _, err := uuid.Parse(roomID); err != nil {
+		return approom.CreateAttachmentResponse{}, fmt.Errorf("%w: bad room id", errs.ErrInvalidInput)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return approom.CreateAttachmentResponse{}, fmt.Errorf("%w: empty file", errs.ErrInvalidInput)
+		}
+		return approom.CreateAttachmentResponse{}, err
+	}
+	head = head[:n]
+
+	mimeType := sniff(head)
+	if !s.allowed(mimeType) {
+		return approom.CreateAttachmentResponse{}, fmt.Errorf("%w: %s", errs.ErrUnsupportedMedia, mimeType)
+	}
+
+	id := uuid.NewString()
+	key := objectKey(roomID, id)
+
+	// +1 байт, чтобы отличить "ровно лимит" от "больше лимита"
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), s.opts.MaxFileSize+1)
+	size, err := s.store.Put(ctx, key, body)
+	if err != nil {
+		_ = s.store.Delete(context.WithoutCancel(ctx), key)
+		return approom.CreateAttachmentResponse{}, fmt.Errorf("store attachment: %w", err)
+	}
+	if size > s.opts.MaxFileSize {
+		_ = s.store.Delete(context.WithoutCancel(ctx), key)
+		return approom.CreateAttachmentResponse{}, fmt.Errorf("%w: max %d bytes", errs.ErrTooLarge, s.opts.MaxFileSize)
+	}
+
+	var thumbKey string
+	if canThumbnail(mimeType) && s.opts.ThumbnailSize > 0 {
+		if err := s.thumbnail(ctx, key, key+".thumb.jpg"); err != nil {
+			slog.WarnContext(ctx, "attachment thumbnail failed", "id", id, "err", err)
+		} else {
+			thumbKey = key + ".thumb.jpg"
+		}
+	}
+
+	out, err := s.room.CreateAttachment(ctx, authHeader, userID, approom.CreateAttachmentRequest{
+		RoomID:     roomID,
+		ID:         id,
+		FileName:   cleanFileName(fileName),
+		MimeType:   mimeType,
+		SizeBytes:  size,
+		StorageKey: key,
+		ThumbKey:   thumbKey,
+	})
+	if err != nil {
+		// метаданных нет — байты никому не нужны
+		cctx := context.WithoutCancel(ctx)
+		_ = s.store.Delete(cctx, key)
+		if thumbKey != "" {
+			_ = s.store.Delete(cctx, thumbKey)
+		}
+		return approom.CreateAttachmentResponse{}, err
+	}
+
+	return out, nil
+}
+
+// SignURL проверяет членство (через room-service) и выдаёт короткоживущую ссылку.
+func (s *Service) SignURL(ctx context.Context, authHeader string, userID int64, roomID, id, variant string) (URLResponse, error) {
+	if variant == "" {
+		variant = VariantOriginal
+	}
+	if variant != VariantOriginal && variant != VariantThumb {
+		return URLResponse{}, fmt.Errorf("%w: variant must be orig|thumb", errs.ErrInvalidInput)
+	}
+
+	a, err := s.room.GetAttachment(ctx, authHeader, userID, roomID, id)
+	if err != nil {
+		return URLResponse{}, err
+	}
+	if variant == VariantThumb && !a.HasThumbnail {
+		return URLResponse{}, fmt.Errorf("%w: no thumbnail", errs.ErrNotFound)
+	}
+
+	l := Link{
+		ID:        a.ID,
+		RoomID:    a.RoomID,
+		UserID:    userID,
+		FileName:  a.FileName,
+		Variant:   variant,
+		ExpiresAt: s.now().Add(s.opts.URLTTL).Truncate(time.Second),
+	}
+	u := strings.TrimRight(s.opts.PublicBaseURL, "/") + "/attachments/" + url.PathEscape(a.ID) + "?" + s.signer.query(l).Encode()
+
+	return URLResponse{URL: u, ExpiresAt: l.ExpiresAt}, nil
+}
+
+// Open отдаёт файл по подписанной ссылке. Бэкенд не ходит в room-service:
+// всё нужное (комната, вариант, имя) зашито в подпись.
+func (s *Service) Open(ctx context.Context, id string, q url.Values) (Download, error) {
+	l, err := s.signer.verify(id, q, s.now())
+	if err != nil {
+		return Download{}, fmt.Errorf("%w: %v", errs.ErrForbidden, err)
+	}
+	if _, err := uuid.Parse(l.ID); err != nil {
+		return Download{}, fmt.Errorf("%w: bad id", errs.ErrInvalidInput)
+	}
+	if _, err := uuid.Parse(l.RoomID); err != nil {
+		return Download{}, fmt.Errorf("%w: bad room id", errs.ErrInvalidInput)
+	}
+
+	key := objectKey(l.RoomID, l.ID)
+	name := l.FileName
+	if l.Variant == VariantThumb {
+		key += ".thumb.jpg"
+		name = strings.TrimSuffix(name, path.Ext(name)) + ".jpg"
+	}
+
+	f, err := s.store.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+			return Download{}, fmt.Errorf("%w: attachment", errs.ErrNotFound)
+		}
+		return Download{}, err
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		_ = f.Close()
+		return Download{}, err
+	}
+
+	return Download{
+		Body: struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(head[:n]), f), f},
+		ContentType: sniff(head[:n]),
+		FileName:    name,
+	}, nil
+}
+
+func (s *Service) thumbnail(ctx context.Context, srcKey, dstKey string) error {
+	f, err := s.store.Open(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		b, err := io.ReadAll(io.LimitReader(f, s.opts.MaxFileSize))
+		if err != nil {
+			return err
+		}
+		rs = bytes.NewReader(b)
+	}
+
+	var buf bytes.Buffer
+	if err := makeThumbnail(rs, &buf, s.opts.ThumbnailSize); err != nil {
+		return err
+	}
+	_, err = s.store.Put(ctx, dstKey, &buf)
+
+	return err
+}
+
+// allowed — точное совпадение или маска вида "image/*". Пустой список — всё можно.
+func (s *Service) allowed(mimeType string) bool {
+	if len(s.opts.AllowedTypes) == 0 {
+		return true
+	}
+	for _, t := range s.opts.AllowedTypes {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == mimeType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok && strings.HasPrefix(mimeType, prefix+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func objectKey(roomID, id string) string {
+	return "rooms/" + roomID + "/" + id
+}
+
+// sniff — http.DetectContentType без параметров ("text/plain; charset=utf-8" -> "text/plain").
+func sniff(head []byte) string {
+	ct := http.DetectContentType(head)
+	if mt, _, err := mime.ParseMediaType(ct); err == nil {
+		return mt
+	}
+
+	return "application/octet-stream"
+}
+
+// cleanFileName — только базовое имя, без управляющих символов и с ограничением длины.
+func cleanFileName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Base(strings.TrimSpace(name))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return defaultFileName
+	}
+	for len(name) > maxFileNameLen {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+
+	return name
+}
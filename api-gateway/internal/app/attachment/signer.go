@@ -0,0 +1,95 @@
+package attachment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadSignature = errors.New("invalid link signature")
+	ErrLinkExpired  = errors.New("link expired")
+)
+
+const (
+	VariantOriginal = "orig"
+	VariantThumb    = "thumb"
+)
+
+// Link — то, что зашито в подписанную ссылку на скачивание.
+// Членство в комнате проверяется в момент выдачи ссылки, поэтому TTL короткий.
+type Link struct {
+	ID        string
+	RoomID    string
+	UserID    int64
+	FileName  string
+	Variant   string
+	ExpiresAt time.Time
+}
+
+type signer struct {
+	secret []byte
+}
+
+func (s signer) sign(l Link) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(strings.Join([]string{
+		l.ID,
+		l.RoomID,
+		strconv.FormatInt(l.UserID, 10),
+		l.FileName,
+		l.Variant,
+		strconv.FormatInt(l.ExpiresAt.Unix(), 10),
+	}, "\n")))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// query — параметры ссылки вместе с подписью.
+func (s signer) query(l Link) url.Values {
+	q := url.Values{}
+	q.Set("room", l.RoomID)
+	q.Set("uid", strconv.FormatInt(l.UserID, 10))
+	q.Set("name", l.FileName)
+	q.Set("v", l.Variant)
+	q.Set("exp", strconv.FormatInt(l.ExpiresAt.Unix(), 10))
+	q.Set("sig", s.sign(l))
+
+	return q
+}
+
+// verify разбирает и проверяет ссылку: подпись (constant-time) и срок жизни.
+func (s signer) verify(id string, q url.Values, now time.Time) (Link, error) {
+	uid, err := strconv.ParseInt(q.Get("uid"), 10, 64)
+	if err != nil {
+		return Link{}, ErrBadSignature
+	}
+	exp, err := strconv.ParseInt(q.Get("exp"), 10, 64)
+	if err != nil {
+		return Link{}, ErrBadSignature
+	}
+	l := Link{
+		ID:        id,
+		RoomID:    q.Get("room"),
+		UserID:    uid,
+		FileName:  q.Get("name"),
+		Variant:   q.Get("v"),
+		ExpiresAt: time.Unix(exp, 0),
+	}
+	if l.Variant != VariantOriginal && l.Variant != VariantThumb {
+		return Link{}, ErrBadSignature
+	}
+	if !hmac.Equal([]byte(s.sign(l)), []byte(q.Get("sig"))) {
+		return Link{}, ErrBadSignature
+	}
+	if !now.Before(l.ExpiresAt) {
+		return Link{}, ErrLinkExpired
+	}
+
+	return l, nil
+}
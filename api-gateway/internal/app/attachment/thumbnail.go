@@ -0,0 +1,95 @@
+package attachment
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif" // регистрируем декодеры
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// maxThumbSourcePixels — защита от "декомпрессионных бомб".
+const maxThumbSourcePixels = 40_000_000
+
+var errTooManyPixels = errors.New("image too large for thumbnail")
+
+// canThumbnail — для каких типов умеем строить превью стандартной библиотекой.
+func canThumbnail(mimeType string) bool {
+	switch mimeType {
+	case "image/png", "image/jpeg", "image/gif":
+		return true
+	default:
+		return false
+	}
+}
+
+// makeThumbnail уменьшает картинку до size px по длинной стороне и пишет JPEG.
+func makeThumbnail(src io.ReadSeeker, dst io.Writer, size int) error {
+	cfg, _, err := image.DecodeConfig(src)
+	if err != nil {
+		return err
+	}
+	if cfg.Width*cfg.Height > maxThumbSourcePixels {
+		return errTooManyPixels
+	}
+	if _, err := src.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, _, err := image.Decode(src)
+	if err != nil {
+		return err
+	}
+
+	return jpeg.Encode(dst, scaleToFit(img, size), &jpeg.Options{Quality: 80})
+}
+
+// scaleToFit — простое усреднение по блокам (box filter), без внешних зависимостей.
+// Прозрачные области кладём на белый фон: в JPEG альфы нет.
+func scaleToFit(src image.Image, size int) image.Image {
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= 0 || h <= 0 {
+		return src
+	}
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, tw, th))
+	for y := 0; y < th; y++ {
+		y0 := b.Min.Y + y*h/th
+		y1 := max(y0+1, b.Min.Y+(y+1)*h/th)
+		for x := 0; x < tw; x++ {
+			x0 := b.Min.X + x*w/tw
+			x1 := max(x0+1, b.Min.X+(x+1)*w/tw)
+
+			var r, g, bl, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					// premultiplied -> на белом фоне
+					bg := 0xffff - uint64(ca)
+					r += uint64(cr) + bg
+					g += uint64(cg) + bg
+					bl += uint64(cb) + bg
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: 0xff,
+			})
+		}
+	}
+
+	return dst
+}
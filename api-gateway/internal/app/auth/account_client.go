@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"context"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+)
+
+func (c *client) DeleteAccount(ctx context.Context, in DeleteAccountRequest) (DeleteAccountResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.DeleteAccount(rpcCtx, &authv1.DeleteAccountRequest{
+		Password:              in.Password,
+		MfaCode:               in.MfaCode,
+		PasskeyCeremonyId:     in.PasskeyCeremonyID,
+		PasskeyCredentialJson: string(in.PasskeyCredential),
+	})
+	if err != nil {
+		return DeleteAccountResponse{}, fromGRPC(err)
+	}
+
+	return DeleteAccountResponse{DeletionScheduledAt: res.GetDeletionScheduledAt()}, nil
+}
+
+func (c *client) CancelAccountDeletion(ctx context.Context) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	if _, err := c.auth.CancelAccountDeletion(rpcCtx, &authv1.CancelAccountDeletionRequest{}); err != nil {
+		return fromGRPC(err)
+	}
+	return nil
+}
+
+func (c *client) ExportMyData(ctx context.Context) (PersonalData, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ExportMyData(rpcCtx, &authv1.ExportMyDataRequest{})
+	if err != nil {
+		return PersonalData{}, fromGRPC(err)
+	}
+
+	out := PersonalData{Sessions: make([]Session, 0, len(res.GetSessions()))}
+	if u := userFromPB(res.GetUser()); u != nil {
+		out.User = *u
+	}
+	for _, s := range res.GetSessions() {
+		out.Sessions = append(out.Sessions, Session{
+			Id:        s.GetId(),
+			UserAgent: s.GetUserAgent(),
+			Ip:        s.GetIp(),
+			CreatedAt: s.GetCreatedAt(),
+			UpdatedAt: s.GetUpdatedAt(),
+			ExpiresAt: s.GetExpiresAt(),
+		})
+	}
+
+	return out, nil
+}
@@ -1,15 +1,150 @@
 package auth
 
+import "encoding/json"
+
 type LoginRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
 
 type LoginResponse struct {
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
-	ExpiresIn    int64  `json:"expiresIn"`
-	User         User   `json:"user"`
+	AccessToken  string `json:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+	ExpiresIn    int64  `json:"expiresIn,omitempty"`
+	User         *User  `json:"user,omitempty"`
+
+	// включен TOTP: токенов нет, нужен POST /auth/mfa/verify с mfaToken
+	MfaRequired  bool   `json:"mfaRequired,omitempty"`
+	MfaToken     string `json:"mfaToken,omitempty"`
+	MfaExpiresIn int64  `json:"mfaExpiresIn,omitempty"`
+}
+
+// ChangePasswordRequest - без входа: так же снимается принудительный сброс пароля от админа
+type ChangePasswordRequest struct {
+	Email           string `json:"email"`
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+	MfaCode         string `json:"mfaCode,omitempty"` // если включен TOTP
+}
+
+type VerifyMfaRequest struct {
+	MfaToken string `json:"mfaToken"`
+	Code     string `json:"code"` // 6 цифр или код восстановления
+}
+
+type EnrollTotpResponse struct {
+	OtpauthURL string `json:"otpauthUrl"`
+	Secret     string `json:"secret"`
+	QrPNG      []byte `json:"qrPng"` // base64
+}
+
+type TotpCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type ConfirmTotpResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// Passkey: options/credential - JSON WebAuthn как есть, браузер передает их в navigator.credentials.* и обратно
+type PasskeyCeremonyResponse struct {
+	CeremonyID string          `json:"ceremonyId"`
+	Options    json.RawMessage `json:"options"`
+}
+
+type FinishPasskeyRegistrationRequest struct {
+	CeremonyID string          `json:"ceremonyId"`
+	Name       string          `json:"name,omitempty"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+type FinishPasskeyLoginRequest struct {
+	CeremonyID string          `json:"ceremonyId"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+type RenamePasskeyRequest struct {
+	Name string `json:"name"`
+}
+
+type Passkey struct {
+	Id             int64    `json:"id"`
+	Name           string   `json:"name"`
+	Transports     []string `json:"transports,omitempty"`
+	BackupEligible bool     `json:"backupEligible"`
+	BackupState    bool     `json:"backupState"`
+	CreatedAt      int64    `json:"createdAt"`
+	LastUsedAt     int64    `json:"lastUsedAt,omitempty"`
+}
+
+type ListPasskeysResponse struct {
+	Passkeys []Passkey `json:"passkeys"`
+}
+
+// OIDC: фронт отправляет браузер на authorizationUrl, а code и state из redirect передает в finish
+type OidcStartResponse struct {
+	AuthorizationURL string `json:"authorizationUrl"`
+	State            string `json:"state"`
+}
+
+type OidcFinishRequest struct {
+	State string `json:"state"`
+	Code  string `json:"code"`
+}
+
+type Identity struct {
+	Id          int64  `json:"id"`
+	Provider    string `json:"provider"`
+	Subject     string `json:"subject"`
+	Email       string `json:"email,omitempty"`
+	CreatedAt   int64  `json:"createdAt"`
+	LastLoginAt int64  `json:"lastLoginAt,omitempty"`
+}
+
+type ListIdentitiesResponse struct {
+	Identities []Identity `json:"identities"`
+}
+
+type SecurityEvent struct {
+	Id        int64             `json:"id"`
+	Type      string            `json:"type"`
+	Ip        string            `json:"ip,omitempty"`
+	UserAgent string            `json:"userAgent,omitempty"`
+	RequestId string            `json:"requestId,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt int64             `json:"createdAt"`
+}
+
+type ListSecurityEventsResponse struct {
+	Events     []SecurityEvent `json:"events"`
+	NextCursor int64           `json:"nextCursor,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password"`
+	MfaCode  string `json:"mfaCode"`
+	// вместо пароля: ceremonyId из /auth/passkeys/reauth/begin и ответ navigator.credentials.get()
+	PasskeyCeremonyID string          `json:"passkeyCeremonyId"`
+	PasskeyCredential json.RawMessage `json:"passkeyCredential"`
+}
+
+type DeleteAccountResponse struct {
+	DeletionScheduledAt int64 `json:"deletionScheduledAt"`
+}
+
+type Session struct {
+	Id        int64  `json:"id"`
+	UserAgent string `json:"userAgent,omitempty"`
+	Ip        string `json:"ip,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+	UpdatedAt int64  `json:"updatedAt"`
+	ExpiresAt int64  `json:"expiresAt"`
+}
+
+// PersonalData — то, что auth-service отдаёт в выгрузку персональных данных
+type PersonalData struct {
+	User     User      `json:"user"`
+	Sessions []Session `json:"sessions"`
 }
 
 type RegisterRequest struct {
@@ -47,4 +182,6 @@ type User struct {
 	AvatarURL     string `json:"avatarUrl,omitempty"`
 	CreatedAt     int64  `json:"createdAt"`
 	UpdatedAt     int64  `json:"updatedAt"`
+
+	DeletionScheduledAt int64 `json:"deletionScheduledAt,omitempty"` // 0 — удаление не запрошено
 }
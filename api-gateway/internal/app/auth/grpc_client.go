@@ -11,15 +11,41 @@ import (
 	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 type Client interface {
 	Login(ctx context.Context, in LoginRequest) (LoginResponse, error)
 	Register(ctx context.Context, in RegisterRequest) (RegisterResponse, error)
 	Refresh(ctx context.Context, refreshToken string) (RefreshResponse, error)
+	ChangePassword(ctx context.Context, in ChangePasswordRequest) error
 	Me(ctx context.Context) (MeResponse, error)
+	VerifyMfa(ctx context.Context, in VerifyMfaRequest) (LoginResponse, error)
+	EnrollTotp(ctx context.Context) (EnrollTotpResponse, error)
+	ConfirmTotp(ctx context.Context, code string) (ConfirmTotpResponse, error)
+	DisableTotp(ctx context.Context, code string) error
+	BeginPasskeyRegistration(ctx context.Context) (PasskeyCeremonyResponse, error)
+	FinishPasskeyRegistration(ctx context.Context, in FinishPasskeyRegistrationRequest) (Passkey, error)
+	BeginPasskeyLogin(ctx context.Context) (PasskeyCeremonyResponse, error)
+	FinishPasskeyLogin(ctx context.Context, in FinishPasskeyLoginRequest) (LoginResponse, error)
+	BeginPasskeyReauth(ctx context.Context) (PasskeyCeremonyResponse, error)
+	ListPasskeys(ctx context.Context) (ListPasskeysResponse, error)
+	RenamePasskey(ctx context.Context, id int64, name string) (Passkey, error)
+	RevokePasskey(ctx context.Context, id int64) error
+	ListOidcProviders(ctx context.Context) ([]string, error)
+	BeginOidcLogin(ctx context.Context, provider string) (OidcStartResponse, error)
+	FinishOidcLogin(ctx context.Context, provider string, in OidcFinishRequest) (LoginResponse, error)
+	BeginOidcLink(ctx context.Context, provider string) (OidcStartResponse, error)
+	FinishOidcLink(ctx context.Context, provider string, in OidcFinishRequest) (Identity, error)
+	ListIdentities(ctx context.Context) (ListIdentitiesResponse, error)
+	UnlinkIdentity(ctx context.Context, provider string) error
+	ListSecurityEvents(ctx context.Context, cursor int64, limit int32) (ListSecurityEventsResponse, error)
+	DeleteAccount(ctx context.Context, in DeleteAccountRequest) (DeleteAccountResponse, error)
+	CancelAccountDeletion(ctx context.Context) error
+	ExportMyData(ctx context.Context) (PersonalData, error)
 	Close() error
 }
 
@@ -74,25 +100,112 @@ func (c *client) Login(ctx context.Context, in LoginRequest) (LoginResponse, err
 	}
 	res, err := c.auth.Login(rpcCtx, req)
 	if err != nil {
-		return LoginResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return LoginResponse{}, fromGRPC(err)
+	}
+
+	return loginFromPB(res), nil
+}
+
+func loginFromPB(res *authv1.LoginResponse) LoginResponse {
+	if res.GetMfaRequired() {
+		return LoginResponse{
+			MfaRequired:  true,
+			MfaToken:     res.GetMfaToken(),
+			MfaExpiresIn: res.GetMfaExpiresIn(),
+		}
 	}
 
 	return LoginResponse{
 		AccessToken:  res.GetAccessToken(),
 		RefreshToken: res.GetRefreshToken(),
 		ExpiresIn:    res.GetExpiresIn(),
-		User: User{
-			Id:            res.GetUser().GetId(),
-			Email:         res.GetUser().GetEmail(),
-			EmailVerified: res.GetUser().GetEmailVerified(),
-			DisplayName:   res.GetUser().GetDisplayName(),
-			AvatarURL:     res.GetUser().GetAvatarUrl(),
-			CreatedAt:     res.GetUser().GetCreatedAt(),
-			UpdatedAt:     res.GetUser().GetUpdatedAt(),
-		},
+		User:         userFromPB(res.GetUser()),
+	}
+}
+
+func (c *client) VerifyMfa(ctx context.Context, in VerifyMfaRequest) (LoginResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.VerifyMfa(rpcCtx, &authv1.VerifyMfaRequest{MfaToken: in.MfaToken, Code: in.Code})
+	if err != nil {
+		return LoginResponse{}, fromGRPC(err)
+	}
+
+	return LoginResponse{
+		AccessToken:  res.GetAccessToken(),
+		RefreshToken: res.GetRefreshToken(),
+		ExpiresIn:    res.GetExpiresIn(),
+		User:         userFromPB(res.GetUser()),
 	}, nil
 }
 
+// EnrollTotp/ConfirmTotp/DisableTotp - пользователь по Bearer в ctx (см. WithBearer)
+func (c *client) EnrollTotp(ctx context.Context) (EnrollTotpResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.EnrollTotp(rpcCtx, &authv1.EnrollTotpRequest{})
+	if err != nil {
+		return EnrollTotpResponse{}, fromGRPC(err)
+	}
+
+	return EnrollTotpResponse{
+		OtpauthURL: res.GetOtpauthUrl(),
+		Secret:     res.GetSecret(),
+		QrPNG:      res.GetQrPng(),
+	}, nil
+}
+
+func (c *client) ConfirmTotp(ctx context.Context, code string) (ConfirmTotpResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ConfirmTotp(rpcCtx, &authv1.ConfirmTotpRequest{Code: code})
+	if err != nil {
+		return ConfirmTotpResponse{}, fromGRPC(err)
+	}
+
+	return ConfirmTotpResponse{RecoveryCodes: res.GetRecoveryCodes()}, nil
+}
+
+func (c *client) DisableTotp(ctx context.Context, code string) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	if _, err := c.auth.DisableTotp(rpcCtx, &authv1.DisableTotpRequest{Code: code}); err != nil {
+		return fromGRPC(err)
+	}
+
+	return nil
+}
+
+func userFromPB(u *authv1.User) *User {
+	if u == nil {
+		return nil
+	}
+
+	return &User{
+		Id:            u.GetId(),
+		Email:         u.GetEmail(),
+		EmailVerified: u.GetEmailVerified(),
+		DisplayName:   u.GetDisplayName(),
+		AvatarURL:     u.GetAvatarUrl(),
+		CreatedAt:     u.GetCreatedAt(),
+		UpdatedAt:     u.GetUpdatedAt(),
+
+		DeletionScheduledAt: u.GetDeletionScheduledAt(),
+	}
+}
+
 func (c *client) Register(ctx context.Context, in RegisterRequest) (RegisterResponse, error) {
 	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
@@ -106,7 +219,7 @@ func (c *client) Register(ctx context.Context, in RegisterRequest) (RegisterResp
 	}
 	res, err := c.auth.Register(rpcCtx, req)
 	if err != nil {
-		return RegisterResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return RegisterResponse{}, fromGRPC(err)
 	}
 
 	return RegisterResponse{
@@ -134,7 +247,7 @@ func (c *client) Refresh(ctx context.Context, refreshToken string) (RefreshRespo
 	req := &authv1.RefreshRequest{RefreshToken: refreshToken}
 	res, err := c.auth.Refresh(rpcCtx, req)
 	if err != nil {
-		return RefreshResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return RefreshResponse{}, fromGRPC(err)
 	}
 
 	return RefreshResponse{
@@ -144,6 +257,25 @@ func (c *client) Refresh(ctx context.Context, refreshToken string) (RefreshRespo
 	}, nil
 }
 
+func (c *client) ChangePassword(ctx context.Context, in ChangePasswordRequest) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	_, err := c.auth.ChangePassword(rpcCtx, &authv1.ChangePasswordRequest{
+		Email:           in.Email,
+		CurrentPassword: in.CurrentPassword,
+		NewPassword:     in.NewPassword,
+		MfaCode:         in.MfaCode,
+	})
+	if err != nil {
+		return fromGRPC(err)
+	}
+
+	return nil
+}
+
 func (c *client) Me(ctx context.Context) (MeResponse, error) {
 	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
@@ -152,7 +284,7 @@ func (c *client) Me(ctx context.Context) (MeResponse, error) {
 
 	res, err := c.auth.Me(rpcCtx, &authv1.MeRequest{})
 	if err != nil {
-		return MeResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return MeResponse{}, fromGRPC(err)
 	}
 
 	return MeResponse{
@@ -164,10 +296,22 @@ func (c *client) Me(ctx context.Context) (MeResponse, error) {
 			AvatarURL:     res.GetUser().GetAvatarUrl(),
 			CreatedAt:     res.GetUser().GetCreatedAt(),
 			UpdatedAt:     res.GetUser().GetUpdatedAt(),
+
+			DeletionScheduledAt: res.GetUser().GetDeletionScheduledAt(),
 		},
 	}, nil
 }
 
+// fromGRPC - как errs.FromGRPC, но ResourceExhausted у auth-service — это блокировка входа
+// после неудачных попыток (429), а не превышение квоты
+func fromGRPC(err error) error {
+	if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+		return fmt.Errorf("%w: %s", errs.ErrTooManyRequests, st.Message())
+	}
+
+	return errs.FromGRPC(err)
+}
+
 // Helper: вкладывает "Authorization: Bearer <access_token>" в ctx metadata.
 func WithBearer(ctx context.Context, accessToken string) context.Context {
 	if accessToken == "" {
@@ -182,6 +326,15 @@ func withOutboundMeta(ctx context.Context, accessToken string) context.Context {
 	if rid, ok := httputil.FromContext(ctx); ok && rid != "" {
 		ctx = metadata.AppendToOutgoingContext(ctx, "x-request-id", rid)
 	}
+	// IP и UA клиента для журнала безопасности; user-agent в gRPC занят самим клиентом
+	if cm, ok := httputil.ClientFromContext(ctx); ok {
+		if cm.IP != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", cm.IP)
+		}
+		if cm.UserAgent != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-client-user-agent", cm.UserAgent)
+		}
+	}
 	// Authorization: Bearer <access_token>
 	if accessToken != "" {
 		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+accessToken)
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+)
+
+func (c *client) ListOidcProviders(ctx context.Context) ([]string, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ListOidcProviders(rpcCtx, &authv1.ListOidcProvidersRequest{})
+	if err != nil {
+		return nil, fromGRPC(err)
+	}
+
+	return res.GetProviders(), nil
+}
+
+func (c *client) BeginOidcLogin(ctx context.Context, provider string) (OidcStartResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.BeginOidcLogin(rpcCtx, &authv1.BeginOidcLoginRequest{Provider: provider})
+	if err != nil {
+		return OidcStartResponse{}, fromGRPC(err)
+	}
+
+	return OidcStartResponse{AuthorizationURL: res.GetAuthorizationUrl(), State: res.GetState()}, nil
+}
+
+func (c *client) FinishOidcLogin(ctx context.Context, provider string, in OidcFinishRequest) (LoginResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.FinishOidcLogin(rpcCtx, &authv1.FinishOidcRequest{Provider: provider, State: in.State, Code: in.Code})
+	if err != nil {
+		return LoginResponse{}, fromGRPC(err)
+	}
+
+	return loginFromPB(res), nil
+}
+
+// BeginOidcLink/FinishOidcLink/ListIdentities/UnlinkIdentity - пользователь по Bearer в ctx (см. WithBearer)
+func (c *client) BeginOidcLink(ctx context.Context, provider string) (OidcStartResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.BeginOidcLink(rpcCtx, &authv1.BeginOidcLinkRequest{Provider: provider})
+	if err != nil {
+		return OidcStartResponse{}, fromGRPC(err)
+	}
+
+	return OidcStartResponse{AuthorizationURL: res.GetAuthorizationUrl(), State: res.GetState()}, nil
+}
+
+func (c *client) FinishOidcLink(ctx context.Context, provider string, in OidcFinishRequest) (Identity, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.FinishOidcLink(rpcCtx, &authv1.FinishOidcRequest{Provider: provider, State: in.State, Code: in.Code})
+	if err != nil {
+		return Identity{}, fromGRPC(err)
+	}
+
+	return identityFromPB(res.GetIdentity()), nil
+}
+
+func (c *client) ListIdentities(ctx context.Context) (ListIdentitiesResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ListIdentities(rpcCtx, &authv1.ListIdentitiesRequest{})
+	if err != nil {
+		return ListIdentitiesResponse{}, fromGRPC(err)
+	}
+
+	out := ListIdentitiesResponse{Identities: make([]Identity, 0, len(res.GetIdentities()))}
+	for _, i := range res.GetIdentities() {
+		out.Identities = append(out.Identities, identityFromPB(i))
+	}
+
+	return out, nil
+}
+
+func (c *client) UnlinkIdentity(ctx context.Context, provider string) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	if _, err := c.auth.UnlinkIdentity(rpcCtx, &authv1.UnlinkIdentityRequest{Provider: provider}); err != nil {
+		return fromGRPC(err)
+	}
+
+	return nil
+}
+
+func identityFromPB(i *authv1.Identity) Identity {
+	return Identity{
+		Id:          i.GetId(),
+		Provider:    i.GetProvider(),
+		Subject:     i.GetSubject(),
+		Email:       i.GetEmail(),
+		CreatedAt:   i.GetCreatedAt(),
+		LastLoginAt: i.GetLastLoginAt(),
+	}
+}
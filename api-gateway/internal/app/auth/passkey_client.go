@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+)
+
+// BeginPasskeyRegistration/FinishPasskeyRegistration/ListPasskeys/RenamePasskey/RevokePasskey -
+// пользователь по Bearer в ctx (см. WithBearer)
+func (c *client) BeginPasskeyRegistration(ctx context.Context) (PasskeyCeremonyResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.BeginPasskeyRegistration(rpcCtx, &authv1.BeginPasskeyRegistrationRequest{})
+	if err != nil {
+		return PasskeyCeremonyResponse{}, fromGRPC(err)
+	}
+
+	return PasskeyCeremonyResponse{
+		CeremonyID: res.GetCeremonyId(),
+		Options:    json.RawMessage(res.GetOptionsJson()),
+	}, nil
+}
+
+func (c *client) FinishPasskeyRegistration(ctx context.Context, in FinishPasskeyRegistrationRequest) (Passkey, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.FinishPasskeyRegistration(rpcCtx, &authv1.FinishPasskeyRegistrationRequest{
+		CeremonyId:     in.CeremonyID,
+		Name:           in.Name,
+		CredentialJson: string(in.Credential),
+	})
+	if err != nil {
+		return Passkey{}, fromGRPC(err)
+	}
+
+	return passkeyFromPB(res.GetPasskey()), nil
+}
+
+func (c *client) BeginPasskeyLogin(ctx context.Context) (PasskeyCeremonyResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.BeginPasskeyLogin(rpcCtx, &authv1.BeginPasskeyLoginRequest{})
+	if err != nil {
+		return PasskeyCeremonyResponse{}, fromGRPC(err)
+	}
+
+	return PasskeyCeremonyResponse{
+		CeremonyID: res.GetCeremonyId(),
+		Options:    json.RawMessage(res.GetOptionsJson()),
+	}, nil
+}
+
+// BeginPasskeyReauth - повторная проверка passkey перед DeleteAccount, пользователь по Bearer в ctx
+func (c *client) BeginPasskeyReauth(ctx context.Context) (PasskeyCeremonyResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.BeginPasskeyReauth(rpcCtx, &authv1.BeginPasskeyReauthRequest{})
+	if err != nil {
+		return PasskeyCeremonyResponse{}, fromGRPC(err)
+	}
+
+	return PasskeyCeremonyResponse{
+		CeremonyID: res.GetCeremonyId(),
+		Options:    json.RawMessage(res.GetOptionsJson()),
+	}, nil
+}
+
+func (c *client) FinishPasskeyLogin(ctx context.Context, in FinishPasskeyLoginRequest) (LoginResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.FinishPasskeyLogin(rpcCtx, &authv1.FinishPasskeyLoginRequest{
+		CeremonyId:     in.CeremonyID,
+		CredentialJson: string(in.Credential),
+	})
+	if err != nil {
+		return LoginResponse{}, fromGRPC(err)
+	}
+
+	return LoginResponse{
+		AccessToken:  res.GetAccessToken(),
+		RefreshToken: res.GetRefreshToken(),
+		ExpiresIn:    res.GetExpiresIn(),
+		User:         userFromPB(res.GetUser()),
+	}, nil
+}
+
+func (c *client) ListPasskeys(ctx context.Context) (ListPasskeysResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ListPasskeys(rpcCtx, &authv1.ListPasskeysRequest{})
+	if err != nil {
+		return ListPasskeysResponse{}, fromGRPC(err)
+	}
+
+	out := ListPasskeysResponse{Passkeys: make([]Passkey, 0, len(res.GetPasskeys()))}
+	for _, p := range res.GetPasskeys() {
+		out.Passkeys = append(out.Passkeys, passkeyFromPB(p))
+	}
+
+	return out, nil
+}
+
+func (c *client) RenamePasskey(ctx context.Context, id int64, name string) (Passkey, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.RenamePasskey(rpcCtx, &authv1.RenamePasskeyRequest{Id: id, Name: name})
+	if err != nil {
+		return Passkey{}, fromGRPC(err)
+	}
+
+	return passkeyFromPB(res.GetPasskey()), nil
+}
+
+func (c *client) RevokePasskey(ctx context.Context, id int64) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	if _, err := c.auth.RevokePasskey(rpcCtx, &authv1.RevokePasskeyRequest{Id: id}); err != nil {
+		return fromGRPC(err)
+	}
+
+	return nil
+}
+
+func passkeyFromPB(p *authv1.Passkey) Passkey {
+	return Passkey{
+		Id:             p.GetId(),
+		Name:           p.GetName(),
+		Transports:     p.GetTransports(),
+		BackupEligible: p.GetBackupEligible(),
+		BackupState:    p.GetBackupState(),
+		CreatedAt:      p.GetCreatedAt(),
+		LastUsedAt:     p.GetLastUsedAt(),
+	}
+}
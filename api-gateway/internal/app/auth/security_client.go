@@ -0,0 +1,34 @@
+package auth
+
+import (
+	"context"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+)
+
+func (c *client) ListSecurityEvents(ctx context.Context, cursor int64, limit int32) (ListSecurityEventsResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	rpcCtx = withOutboundMeta(rpcCtx, "")
+
+	res, err := c.auth.ListSecurityEvents(rpcCtx, &authv1.ListSecurityEventsRequest{Cursor: cursor, Limit: limit})
+	if err != nil {
+		return ListSecurityEventsResponse{}, fromGRPC(err)
+	}
+
+	out := ListSecurityEventsResponse{Events: make([]SecurityEvent, 0, len(res.GetEvents())), NextCursor: res.GetNextCursor()}
+	for _, e := range res.GetEvents() {
+		out.Events = append(out.Events, SecurityEvent{
+			Id:        e.GetId(),
+			Type:      e.GetType(),
+			Ip:        e.GetIp(),
+			UserAgent: e.GetUserAgent(),
+			RequestId: e.GetRequestId(),
+			Details:   e.GetDetails(),
+			CreatedAt: e.GetCreatedAt(),
+		})
+	}
+
+	return out, nil
+}
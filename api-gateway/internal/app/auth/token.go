@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
+)
+
+var ErrInvalidToken = errors.New("invalid access token")
+
+// TokenVerifier — проверка access-токена auth-service на стороне gateway (RS256, публичный ключ).
+// Нужна там, где gateway сам решает по пользователю (rate limit, scopes), без похода в auth-service.
+// Сама проверка — в общем authz, чтобы gateway и room-service понимали токен одинаково
+type TokenVerifier struct {
+	v *authz.Verifier
+}
+
+func NewTokenVerifier(publicKeyPath, issuer, audience string, clockSkew time.Duration) (*TokenVerifier, error) {
+	v, err := authz.NewVerifierFromPEM(publicKeyPath, issuer, audience, clockSkew)
+	if err != nil {
+		return nil, err
+	}
+	return &TokenVerifier{v: v}, nil
+}
+
+// Authz — для authz.Middleware / RequireScopes.
+func (v *TokenVerifier) Authz() *authz.Verifier { return v.v }
+
+// UserID — sub проверенного токена.
+func (v *TokenVerifier) UserID(token string) (int64, error) {
+	p, err := v.v.Verify(token)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	return p.UserID, nil
+}
+
+// RequestUserID — пользователь из "Authorization: Bearer"; ok=false — токена нет или он невалиден.
+func (v *TokenVerifier) RequestUserID(r *http.Request) (int64, bool) {
+	p, err := v.v.FromRequest(r)
+	if err != nil {
+		return 0, false
+	}
+	return p.UserID, true
+}
@@ -0,0 +1,287 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/internal/storage"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+
+	"github.com/google/uuid"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusReady   Status = "ready"
+	StatusFailed  Status = "failed"
+)
+
+// messagesPage — сколько сообщений просим у room-service за раз.
+const messagesPage = 500
+
+var ErrNotReady = fmt.Errorf("%w: export is not ready", errs.ErrConflict)
+
+// Job — задача выгрузки персональных данных. Живут в памяти gateway: после рестарта
+// незавершённые теряются, пользователь просто запускает новую.
+type Job struct {
+	ID        string     `json:"id"`
+	Status    Status     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	SizeBytes int64      `json:"size_bytes,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // когда архив удалится
+
+	userID int64
+}
+
+type Options struct {
+	TTL          time.Duration // сколько хранится готовый архив
+	BuildTimeout time.Duration // на сбор одного архива
+}
+
+// Service — ZIP с профилем и сессиями (auth-service), комнатами и сообщениями (room-service).
+// Архив кладём в тот же Storage, что и вложения, под exports/.
+type Service struct {
+	auth  appauth.Client
+	room  approom.Client
+	store storage.Storage
+	opts  Options
+	now   func() time.Time
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func New(auth appauth.Client, room approom.Client, store storage.Storage, opts Options) *Service {
+	if opts.TTL <= 0 {
+		opts.TTL = 24 * time.Hour
+	}
+	if opts.BuildTimeout <= 0 {
+		opts.BuildTimeout = 5 * time.Minute
+	}
+	return &Service{
+		auth:  auth,
+		room:  room,
+		store: store,
+		opts:  opts,
+		now:   time.Now,
+		jobs:  make(map[string]*Job),
+	}
+}
+
+// Start — запустить выгрузку. Пока предыдущая ещё собирается, возвращаем её.
+func (s *Service) Start(ctx context.Context, accessToken string) (Job, error) {
+	// заодно проверяет токен и даёт id пользователя
+	data, err := s.auth.ExportMyData(appauth.WithBearer(ctx, accessToken))
+	if err != nil {
+		return Job{}, err
+	}
+	userID := data.User.Id
+
+	s.mu.Lock()
+	for _, j := range s.jobs {
+		if j.userID == userID && j.Status == StatusPending {
+			s.mu.Unlock()
+			return *j, nil
+		}
+	}
+	j := &Job{ID: uuid.NewString(), Status: StatusPending, CreatedAt: s.now(), userID: userID}
+	s.jobs[j.ID] = j
+	out := *j
+	s.mu.Unlock()
+
+	bctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.opts.BuildTimeout)
+	go func() {
+		defer cancel()
+		s.build(bctx, j.ID, "Bearer "+accessToken, data)
+	}()
+
+	return out, nil
+}
+
+// Get — статус своей задачи; чужие и несуществующие одинаково ErrNotFound.
+func (s *Service) Get(ctx context.Context, accessToken, id string) (Job, error) {
+	userID, err := s.userID(ctx, accessToken)
+	if err != nil {
+		return Job{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	j, ok := s.jobs[id]
+	if !ok || j.userID != userID {
+		return Job{}, fmt.Errorf("%w: export", errs.ErrNotFound)
+	}
+	return *j, nil
+}
+
+// Open — готовый архив на скачивание.
+func (s *Service) Open(ctx context.Context, accessToken, id string) (io.ReadCloser, error) {
+	j, err := s.Get(ctx, accessToken, id)
+	if err != nil {
+		return nil, err
+	}
+	if j.Status != StatusReady {
+		return nil, ErrNotReady
+	}
+	f, err := s.store.Open(ctx, objectKey(j.ID))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("%w: export", errs.ErrNotFound)
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Run — раз в every удаляет просроченные архивы и задачи.
+func (s *Service) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.cleanup(ctx)
+		}
+	}
+}
+
+func (s *Service) cleanup(ctx context.Context) {
+	now := s.now()
+	var expired []string
+	s.mu.Lock()
+	for id, j := range s.jobs {
+		if j.ExpiresAt != nil && !now.Before(*j.ExpiresAt) {
+			delete(s.jobs, id)
+			if j.Status == StatusReady {
+				expired = append(expired, id)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, id := range expired {
+		if err := s.store.Delete(ctx, objectKey(id)); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			slog.WarnContext(ctx, "export cleanup failed", "id", id, "err", err)
+		}
+	}
+}
+
+func (s *Service) build(ctx context.Context, id, authHeader string, data appauth.PersonalData) {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(s.writeZip(ctx, pw, authHeader, data))
+	}()
+	size, err := s.store.Put(ctx, objectKey(id), pr)
+	_ = pr.CloseWithError(err) // отпустить writeZip, если Put упал раньше
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := s.jobs[id]
+	exp := s.now().Add(s.opts.TTL)
+	j.ExpiresAt = &exp
+	if err != nil {
+		_ = s.store.Delete(context.WithoutCancel(ctx), objectKey(id))
+		slog.WarnContext(ctx, "export failed", "id", id, "user_id", j.userID, "err", err)
+		j.Status = StatusFailed
+		j.Error = "export failed, try again later"
+		return
+	}
+	j.Status = StatusReady
+	j.SizeBytes = size
+}
+
+func (s *Service) writeZip(ctx context.Context, w io.Writer, authHeader string, data appauth.PersonalData) error {
+	zw := zip.NewWriter(w)
+	userID := data.User.Id
+
+	if err := writeJSON(zw, "profile.json", data.User); err != nil {
+		return err
+	}
+	if err := writeJSON(zw, "sessions.json", data.Sessions); err != nil {
+		return err
+	}
+
+	memberships, err := s.room.ListMyMemberships(ctx, authHeader, userID)
+	if err != nil {
+		return fmt.Errorf("memberships: %w", err)
+	}
+	if err := writeJSON(zw, "memberships.json", memberships); err != nil {
+		return err
+	}
+
+	// сообщений может быть много — пишем массив постранично, не собирая в память
+	f, err := zw.Create("messages.json")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, "["); err != nil {
+		return err
+	}
+	cursor, first := "", true
+	for {
+		page, err := s.room.ListMyMessages(ctx, authHeader, userID, cursor, messagesPage)
+		if err != nil {
+			return fmt.Errorf("messages: %w", err)
+		}
+		for _, m := range page.Items {
+			b, err := json.Marshal(m)
+			if err != nil {
+				return err
+			}
+			if !first {
+				if _, err := io.WriteString(f, ","); err != nil {
+					return err
+				}
+			}
+			first = false
+			if _, err := f.Write(b); err != nil {
+				return err
+			}
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if _, err := io.WriteString(f, "]"); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+func (s *Service) userID(ctx context.Context, accessToken string) (int64, error) {
+	me, err := s.auth.Me(appauth.WithBearer(ctx, accessToken))
+	if err != nil {
+		return 0, err
+	}
+	return me.User.Id, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func objectKey(id string) string {
+	return "exports/" + id + ".zip"
+}
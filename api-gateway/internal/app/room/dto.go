@@ -3,15 +3,52 @@ package room
 import "time"
 
 type CreateRoomRequest struct {
-	Name string `json:"name"`
-	Max  int64  `json:"max,omitempty"`
+	Name        string   `json:"name"`
+	Max         int64    `json:"max,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Topic       string   `json:"topic,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// UpdateRoomRequest — PATCH: отсутствующие поля не меняются, "" очищает.
+type UpdateRoomRequest struct {
+	Name        *string   `json:"name,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Topic       *string   `json:"topic,omitempty"`
+	Language    *string   `json:"language,omitempty"`
+	Tags        *[]string `json:"tags,omitempty"`
+}
+
+// ListRoomsQuery — фильтры GET /rooms.
+type ListRoomsQuery struct {
+	Limit     int64
+	Cursor    string
+	Query     string
+	Tag       string
+	Language  string
+	FreeSeats bool
+	Live      bool
+	Sort      string // created | activity
 }
 
 type RoomItem struct {
 	ID              string    `json:"id"`
 	Name            string    `json:"name"`
 	MaxParticipants int64     `json:"max_participants"`
+	OwnerID         string    `json:"owner_id,omitempty"`
+	ParentID        string    `json:"parent_id,omitempty"` // у breakout-групп
 	CreatedAt       time.Time `json:"created_at"`
+
+	Description string   `json:"description,omitempty"`
+	Topic       string   `json:"topic,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Tags        []string `json:"tags"`
+
+	// заполненность — только в списке
+	Participants *int64     `json:"participants,omitempty"`
+	Online       *int64     `json:"online,omitempty"`
+	ActivityAt   *time.Time `json:"activity_at,omitempty"`
 }
 
 type RoomsListResponse struct {
@@ -22,12 +59,27 @@ type RoomsListResponse struct {
 type JoinRoomResponse struct {
 	RoomID string `json:"room_id"`
 	PeerID string `json:"peer_id"`
+
+	// Lobby — поставлен в лобби и ждёт допуска (в WS придёт lobby_state).
+	Lobby         bool `json:"lobby,omitempty"`
+	LobbyPosition int  `json:"lobby_position,omitempty"`
 }
 
 type ParticipantItem struct {
 	UserID   string    `json:"user_id"`
 	JoinedAt time.Time `json:"joined_at"`
 	LastSeen time.Time `json:"last_seen"`
+
+	Presence PresenceItem `json:"presence"`
+}
+
+type PresenceItem struct {
+	Status       string     `json:"status"` // online|away|busy|offline
+	Mic          bool       `json:"mic"`
+	Cam          bool       `json:"cam"`
+	Screen       bool       `json:"screen"`
+	HandRaisedAt *time.Time `json:"hand_raised_at,omitempty"`
+	StatusText   string     `json:"status_text,omitempty"`
 }
 
 type ParticipantsResponse struct {
@@ -41,9 +93,216 @@ type ChatMessageItem struct {
 	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
 	ReplyTo   string    `json:"reply_to,omitempty"`
+
+	Attachments []string `json:"attachments,omitempty"`
+}
+
+// MembershipItem — комната пользователя в выгрузке персональных данных.
+type MembershipItem struct {
+	RoomID    string     `json:"room_id"`
+	RoomName  string     `json:"room_name"`
+	Owner     bool       `json:"owner,omitempty"`
+	Moderator bool       `json:"moderator,omitempty"`
+	JoinedAt  *time.Time `json:"joined_at,omitempty"`
+	LastSeen  *time.Time `json:"last_seen,omitempty"`
 }
 
 type ChatHistoryResponse struct {
 	Items      []ChatMessageItem `json:"items"`
 	NextCursor string            `json:"next_cursor,omitempty"`
 }
+
+type CreateAttachmentRequest struct {
+	RoomID     string
+	ID         string
+	FileName   string
+	MimeType   string
+	SizeBytes  int64
+	StorageKey string
+	ThumbKey   string
+}
+
+type AttachmentItem struct {
+	ID           string    `json:"id"`
+	RoomID       string    `json:"room_id"`
+	UserID       string    `json:"user_id"`
+	MessageID    string    `json:"message_id,omitempty"`
+	FileName     string    `json:"file_name"`
+	MimeType     string    `json:"mime_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	HasThumbnail bool      `json:"has_thumbnail"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	StorageKey string `json:"-"`
+	ThumbKey   string `json:"-"`
+}
+
+type CreateAttachmentResponse struct {
+	Attachment AttachmentItem `json:"attachment"`
+	UsedBytes  int64          `json:"used_bytes"`
+	QuotaBytes int64          `json:"quota_bytes,omitempty"`
+}
+
+type PollOptionItem struct {
+	Idx     int    `json:"idx"`
+	Text    string `json:"text"`
+	Correct bool   `json:"correct,omitempty"`
+	Votes   int64  `json:"votes"`
+}
+
+type PollVoteItem struct {
+	UserID  string    `json:"user_id"`
+	Options []int     `json:"options"`
+	Correct bool      `json:"correct,omitempty"`
+	VotedAt time.Time `json:"voted_at"`
+}
+
+type PollExportItem struct {
+	ID        string           `json:"id"`
+	Question  string           `json:"question"`
+	Multi     bool             `json:"multi"`
+	Quiz      bool             `json:"quiz"`
+	Status    string           `json:"status"`
+	CreatedAt time.Time        `json:"created_at"`
+	ClosedAt  *time.Time       `json:"closed_at,omitempty"`
+	Voters    int64            `json:"voters"`
+	Options   []PollOptionItem `json:"options"`
+	Votes     []PollVoteItem   `json:"votes"`
+}
+
+type PollsExportResponse struct {
+	Items []PollExportItem `json:"items"`
+}
+
+type SetScheduleRequest struct {
+	StartsAt      time.Time `json:"starts_at"`
+	DurationSec   int64     `json:"duration_sec"`
+	RRule         string    `json:"rrule,omitempty"`    // напр. FREQ=WEEKLY;BYDAY=TU, пусто — разовое занятие
+	Timezone      string    `json:"timezone,omitempty"` // IANA, по умолчанию UTC
+	OpenBeforeSec int64     `json:"open_before_sec,omitempty"`
+}
+
+type ScheduleItem struct {
+	RoomID        string    `json:"room_id"`
+	RoomName      string    `json:"room_name"`
+	StartsAt      time.Time `json:"starts_at"`
+	DurationSec   int64     `json:"duration_sec"`
+	RRule         string    `json:"rrule,omitempty"`
+	Timezone      string    `json:"timezone"`
+	OpenBeforeSec int64     `json:"open_before_sec"`
+	CreatedBy     string    `json:"created_by,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type SessionItem struct {
+	RoomID   string    `json:"room_id"`
+	RoomName string    `json:"room_name"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+}
+
+type SessionsResponse struct {
+	Timezone string        `json:"timezone"`
+	Items    []SessionItem `json:"items"`
+}
+
+// CalendarFeedItem — лента .ics по ссылке с токеном; room_id пустой — по всем своим комнатам.
+type CalendarFeedItem struct {
+	ID        string    `json:"id"`
+	RoomID    string    `json:"room_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateCalendarFeedRequest struct {
+	RoomID string `json:"room_id"`
+}
+
+// CreateCalendarFeedResponse — token и url отдаются только при создании.
+type CreateCalendarFeedResponse struct {
+	Feed  CalendarFeedItem `json:"feed"`
+	Token string           `json:"token"`
+	URL   string           `json:"url"`
+}
+
+type CalendarFeedsResponse struct {
+	Items []CalendarFeedItem `json:"items"`
+}
+
+type LobbyItem struct {
+	UserID   string    `json:"user_id"`
+	Position int       `json:"position"`
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+type LobbyResponse struct {
+	Enabled          bool        `json:"enabled"`
+	SessionStartedAt *time.Time  `json:"session_started_at,omitempty"`
+	Queue            []LobbyItem `json:"queue"`
+}
+
+type SetLobbyRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// AdmitLobbyRequest — user_id либо all=true (начать занятие и пустить всех).
+type AdmitLobbyRequest struct {
+	UserID string `json:"user_id,omitempty"`
+	All    bool   `json:"all,omitempty"`
+}
+
+type AdmitLobbyResponse struct {
+	Admitted []string `json:"admitted"`
+}
+
+type BreakoutRoomItem struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	UserIDs []string `json:"user_ids"`
+}
+
+type BreakoutResponse struct {
+	ParentID string             `json:"parent_id"`
+	EndsAt   time.Time          `json:"ends_at"`
+	Rooms    []BreakoutRoomItem `json:"rooms"`
+}
+
+type CreateWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+type WebhookItem struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateWebhookResponse struct {
+	Webhook WebhookItem `json:"webhook"`
+	Secret  string      `json:"secret"` // показывается один раз
+}
+
+type WebhooksResponse struct {
+	Items []WebhookItem `json:"items"`
+}
+
+type WebhookDeliveryItem struct {
+	ID            string     `json:"id"`
+	WebhookID     string     `json:"webhook_id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"` // pending|delivered|dead
+	Attempts      int32      `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastStatus    int32      `json:"last_status,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Items      []WebhookDeliveryItem `json:"items"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
@@ -15,17 +15,42 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Client — API для HTTP-слоя gateway.
 type Client interface {
 	CreateRoom(ctx context.Context, authHeader string, userID int64, in CreateRoomRequest) (RoomItem, error)
-	ListRooms(ctx context.Context, authHeader string, userID int64, limit int64, cursor string) (RoomsListResponse, error)
+	ListRooms(ctx context.Context, authHeader string, userID int64, q ListRoomsQuery) (RoomsListResponse, error)
 	GetRoom(ctx context.Context, authHeader string, userID int64, id string) (RoomItem, error)
+	UpdateRoom(ctx context.Context, authHeader string, userID int64, id string, in UpdateRoomRequest) (RoomItem, error)
 	Join(ctx context.Context, authHeader string, userID int64, id string) (JoinRoomResponse, error)
 	Leave(ctx context.Context, authHeader string, userID int64, id string) error
 	Participants(ctx context.Context, authHeader string, userID int64, id string) (ParticipantsResponse, error)
 	ChatHistory(ctx context.Context, authHeader string, userID int64, roomID string, after string, limit int32) (ChatHistoryResponse, error)
+	CreateAttachment(ctx context.Context, authHeader string, userID int64, in CreateAttachmentRequest) (CreateAttachmentResponse, error)
+	GetAttachment(ctx context.Context, authHeader string, userID int64, roomID, id string) (AttachmentItem, error)
+	SetModerator(ctx context.Context, authHeader string, userID int64, roomID, targetUserID string, grant bool) error
+	ExportPolls(ctx context.Context, authHeader string, userID int64, roomID string) (PollsExportResponse, error)
+	SetSchedule(ctx context.Context, authHeader string, userID int64, roomID string, in SetScheduleRequest) (ScheduleItem, error)
+	GetSchedule(ctx context.Context, authHeader string, userID int64, roomID string) (ScheduleItem, error)
+	DeleteSchedule(ctx context.Context, authHeader string, userID int64, roomID string) error
+	ListSessions(ctx context.Context, authHeader string, userID int64, roomID string, from, to time.Time, limit int32) ([]SessionItem, error)
+	CreateCalendarFeed(ctx context.Context, authHeader string, userID int64, roomID string) (CreateCalendarFeedResponse, error)
+	ListCalendarFeeds(ctx context.Context, authHeader string, userID int64) (CalendarFeedsResponse, error)
+	DeleteCalendarFeed(ctx context.Context, authHeader string, userID int64, id string) error
+	FeedSessions(ctx context.Context, token string, from, to time.Time) ([]SessionItem, error)
+	SetLobby(ctx context.Context, authHeader string, userID int64, roomID string, enabled bool) error
+	GetLobby(ctx context.Context, authHeader string, userID int64, roomID string) (LobbyResponse, error)
+	AdmitLobby(ctx context.Context, authHeader string, userID int64, roomID string, in AdmitLobbyRequest) (AdmitLobbyResponse, error)
+	GetBreakout(ctx context.Context, authHeader string, userID int64, roomID string) (BreakoutResponse, error)
+	CreateWebhook(ctx context.Context, authHeader string, userID int64, in CreateWebhookRequest) (CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context, authHeader string, userID int64) (WebhooksResponse, error)
+	DeleteWebhook(ctx context.Context, authHeader string, userID int64, id string) error
+	ListWebhookDeliveries(ctx context.Context, authHeader string, userID int64, webhookID, status, cursor string, limit int64) (WebhookDeliveriesResponse, error)
+	RedeliverWebhook(ctx context.Context, authHeader string, userID int64, deliveryID string) (WebhookDeliveryItem, error)
+	ListMyMemberships(ctx context.Context, authHeader string, userID int64) ([]MembershipItem, error)
+	ListMyMessages(ctx context.Context, authHeader string, userID int64, cursor string, limit int32) (ChatHistoryResponse, error)
 	Close() error
 }
 
@@ -90,27 +115,40 @@ func (c *client) CreateRoom(ctx context.Context, authHeader string, userID int64
 	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
 
 	req := &roomv1.CreateRoomRequest{
-		Name: in.Name,
-		Max:  in.Max,
+		Name:        in.Name,
+		Max:         in.Max,
+		Description: in.Description,
+		Topic:       in.Topic,
+		Language:    in.Language,
+		Tags:        in.Tags,
 	}
 	res, err := c.room.CreateRoom(rpcCtx, req)
 	if err != nil {
-		return RoomItem{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return RoomItem{}, errs.FromGRPC(err)
 	}
 
 	return mapRoom(res.GetRoom()), nil
 }
 
-func (c *client) ListRooms(ctx context.Context, authHeader string, userID int64, limit int64, cursor string) (RoomsListResponse, error) {
+func (c *client) ListRooms(ctx context.Context, authHeader string, userID int64, q ListRoomsQuery) (RoomsListResponse, error) {
 	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
 
 	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
 
-	req := &roomv1.ListRoomsRequest{Limit: int32(limit), Cursor: cursor}
+	req := &roomv1.ListRoomsRequest{
+		Limit:     int32(q.Limit),
+		Cursor:    q.Cursor,
+		Query:     q.Query,
+		Tag:       q.Tag,
+		Language:  q.Language,
+		FreeSeats: q.FreeSeats,
+		Live:      q.Live,
+		Sort:      q.Sort,
+	}
 	res, err := c.room.ListRooms(rpcCtx, req)
 	if err != nil {
-		return RoomsListResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return RoomsListResponse{}, errs.FromGRPC(err)
 	}
 
 	out := RoomsListResponse{
@@ -124,6 +162,29 @@ func (c *client) ListRooms(ctx context.Context, authHeader string, userID int64,
 	return out, nil
 }
 
+func (c *client) UpdateRoom(ctx context.Context, authHeader string, userID int64, id string, in UpdateRoomRequest) (RoomItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	req := &roomv1.UpdateRoomRequest{
+		Id:          id,
+		Name:        in.Name,
+		Description: in.Description,
+		Topic:       in.Topic,
+		Language:    in.Language,
+	}
+	if in.Tags != nil {
+		req.SetTags, req.Tags = true, *in.Tags
+	}
+	res, err := c.room.UpdateRoom(rpcCtx, req)
+	if err != nil {
+		return RoomItem{}, errs.FromGRPC(err)
+	}
+
+	return mapRoom(res.GetRoom()), nil
+}
+
 func (c *client) GetRoom(ctx context.Context, authHeader string, userID int64, id string) (RoomItem, error) {
 	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
 	defer cancel()
@@ -132,7 +193,7 @@ func (c *client) GetRoom(ctx context.Context, authHeader string, userID int64, i
 
 	res, err := c.room.GetRoom(rpcCtx, &roomv1.GetRoomRequest{Id: id})
 	if err != nil {
-		return RoomItem{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return RoomItem{}, errs.FromGRPC(err)
 	}
 
 	return mapRoom(res.GetRoom()), nil
@@ -146,7 +207,7 @@ func (c *client) Join(ctx context.Context, authHeader string, userID int64, id s
 
 	res, err := c.room.JoinRoom(rpcCtx, &roomv1.JoinRoomRequest{Id: id})
 	if err != nil {
-		return JoinRoomResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return JoinRoomResponse{}, errs.FromGRPC(err)
 	}
 
 	return mapJoin(res), nil
@@ -160,7 +221,7 @@ func (c *client) Leave(ctx context.Context, authHeader string, userID int64, id
 
 	_, err := c.room.LeaveRoom(rpcCtx, &roomv1.LeaveRoomRequest{Id: id})
 	if err != nil {
-		return fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return errs.FromGRPC(err)
 	}
 
 	return nil
@@ -174,7 +235,7 @@ func (c *client) Participants(ctx context.Context, authHeader string, userID int
 
 	res, err := c.room.ListParticipants(rpcCtx, &roomv1.ListParticipantsRequest{Id: id})
 	if err != nil {
-		return ParticipantsResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return ParticipantsResponse{}, errs.FromGRPC(err)
 	}
 	out := ParticipantsResponse{Items: make([]ParticipantItem, 0, len(res.GetItems()))}
 	for _, p := range res.GetItems() {
@@ -183,6 +244,19 @@ func (c *client) Participants(ctx context.Context, authHeader string, userID int
 			JoinedAt: p.GetJoinedAt().AsTime(),
 			LastSeen: p.GetLastSeen().AsTime(),
 		}
+		if pr := p.GetPresence(); pr != nil {
+			item.Presence = PresenceItem{
+				Status:     pr.GetStatus(),
+				Mic:        pr.GetMic(),
+				Cam:        pr.GetCam(),
+				Screen:     pr.GetScreen(),
+				StatusText: pr.GetStatusText(),
+			}
+			if ts := pr.GetHandRaisedAt(); ts != nil {
+				t := ts.AsTime()
+				item.Presence.HandRaisedAt = &t
+			}
+		}
 		out.Items = append(out.Items, item)
 	}
 
@@ -201,31 +275,564 @@ func (c *client) ChatHistory(ctx context.Context, authHeader string, userID int6
 	}
 	res, err := c.room.GetChatHistory(rpcCtx, req)
 	if err != nil {
-		return ChatHistoryResponse{}, fmt.Errorf("%w: %v", errs.ErrUpstream, err)
+		return ChatHistoryResponse{}, errs.FromGRPC(err)
 	}
 	out := ChatHistoryResponse{
 		Items:      make([]ChatMessageItem, 0, len(res.GetItems())),
 		NextCursor: res.GetNextCursor(),
 	}
 	for _, m := range res.GetItems() {
-		item := ChatMessageItem{
-			ID:     m.GetId(),
-			RoomID: m.GetRoomId(),
-			UserID: m.GetUserId(),
-			Text:   m.GetText(),
+		out.Items = append(out.Items, mapChatMessage(m))
+	}
+
+	return out, nil
+}
+
+// ListMyMemberships — комнаты пользователя для выгрузки персональных данных.
+func (c *client) ListMyMemberships(ctx context.Context, authHeader string, userID int64) ([]MembershipItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ListMyMemberships(rpcCtx, &roomv1.ListMyMembershipsRequest{})
+	if err != nil {
+		return nil, errs.FromGRPC(err)
+	}
+	out := make([]MembershipItem, 0, len(res.GetItems()))
+	for _, m := range res.GetItems() {
+		item := MembershipItem{
+			RoomID:    m.GetRoomId(),
+			RoomName:  m.GetRoomName(),
+			Owner:     m.GetOwner(),
+			Moderator: m.GetModerator(),
 		}
-		if ts := m.GetCreatedAt(); ts != nil {
-			item.CreatedAt = ts.AsTime()
+		if ts := m.GetJoinedAt(); ts != nil {
+			t := ts.AsTime()
+			item.JoinedAt = &t
 		}
-		if rt := m.GetReplyTo(); strings.TrimSpace(rt) != "" {
-			item.ReplyTo = rt
+		if ts := m.GetLastSeen(); ts != nil {
+			t := ts.AsTime()
+			item.LastSeen = &t
 		}
-		out.Items = append(out.Items, item)
+		out = append(out, item)
 	}
+	return out, nil
+}
 
+// ListMyMessages — свои сообщения по всем комнатам, новые первыми.
+func (c *client) ListMyMessages(ctx context.Context, authHeader string, userID int64, cursor string, limit int32) (ChatHistoryResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ListMyMessages(rpcCtx, &roomv1.ListMyMessagesRequest{Cursor: cursor, Limit: limit})
+	if err != nil {
+		return ChatHistoryResponse{}, errs.FromGRPC(err)
+	}
+	out := ChatHistoryResponse{
+		Items:      make([]ChatMessageItem, 0, len(res.GetItems())),
+		NextCursor: res.GetNextCursor(),
+	}
+	for _, m := range res.GetItems() {
+		out.Items = append(out.Items, mapChatMessage(m))
+	}
 	return out, nil
 }
 
+func mapChatMessage(m *roomv1.ChatMessage) ChatMessageItem {
+	item := ChatMessageItem{
+		ID:     m.GetId(),
+		RoomID: m.GetRoomId(),
+		UserID: m.GetUserId(),
+		Text:   m.GetText(),
+	}
+	if ts := m.GetCreatedAt(); ts != nil {
+		item.CreatedAt = ts.AsTime()
+	}
+	if rt := m.GetReplyTo(); strings.TrimSpace(rt) != "" {
+		item.ReplyTo = rt
+	}
+	item.Attachments = m.GetAttachmentIds()
+	return item
+}
+
+func (c *client) CreateAttachment(ctx context.Context, authHeader string, userID int64, in CreateAttachmentRequest) (CreateAttachmentResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.CreateAttachment(rpcCtx, &roomv1.CreateAttachmentRequest{
+		RoomId:     in.RoomID,
+		Id:         in.ID,
+		FileName:   in.FileName,
+		MimeType:   in.MimeType,
+		SizeBytes:  in.SizeBytes,
+		StorageKey: in.StorageKey,
+		ThumbKey:   in.ThumbKey,
+	})
+	if err != nil {
+		return CreateAttachmentResponse{}, errs.FromGRPC(err)
+	}
+
+	return CreateAttachmentResponse{
+		Attachment: mapAttachment(res.GetAttachment()),
+		UsedBytes:  res.GetUsedBytes(),
+		QuotaBytes: res.GetQuotaBytes(),
+	}, nil
+}
+
+func (c *client) GetAttachment(ctx context.Context, authHeader string, userID int64, roomID, id string) (AttachmentItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.GetAttachment(rpcCtx, &roomv1.GetAttachmentRequest{RoomId: roomID, Id: id})
+	if err != nil {
+		return AttachmentItem{}, errs.FromGRPC(err)
+	}
+
+	return mapAttachment(res.GetAttachment()), nil
+}
+
+func (c *client) SetModerator(ctx context.Context, authHeader string, userID int64, roomID, targetUserID string, grant bool) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	_, err := c.room.SetModerator(rpcCtx, &roomv1.SetModeratorRequest{
+		RoomId: roomID,
+		UserId: targetUserID,
+		Grant:  grant,
+	})
+	if err != nil {
+		return errs.FromGRPC(err)
+	}
+
+	return nil
+}
+
+func (c *client) ExportPolls(ctx context.Context, authHeader string, userID int64, roomID string) (PollsExportResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ExportPolls(rpcCtx, &roomv1.ExportPollsRequest{RoomId: roomID})
+	if err != nil {
+		return PollsExportResponse{}, errs.FromGRPC(err)
+	}
+
+	out := PollsExportResponse{Items: make([]PollExportItem, 0, len(res.GetPolls()))}
+	for _, p := range res.GetPolls() {
+		it := PollExportItem{
+			ID:        p.GetId(),
+			Question:  p.GetQuestion(),
+			Multi:     p.GetMulti(),
+			Quiz:      p.GetQuiz(),
+			Status:    p.GetStatus(),
+			CreatedAt: p.GetCreatedAt().AsTime(),
+			Voters:    p.GetVoters(),
+			Options:   make([]PollOptionItem, 0, len(p.GetOptions())),
+			Votes:     make([]PollVoteItem, 0, len(p.GetVotes())),
+		}
+		if p.GetClosedAt() != nil {
+			t := p.GetClosedAt().AsTime()
+			it.ClosedAt = &t
+		}
+		for _, o := range p.GetOptions() {
+			it.Options = append(it.Options, PollOptionItem{
+				Idx:     int(o.GetIdx()),
+				Text:    o.GetText(),
+				Correct: o.GetCorrect(),
+				Votes:   o.GetVotes(),
+			})
+		}
+		for _, v := range p.GetVotes() {
+			opts := make([]int, 0, len(v.GetOptions()))
+			for _, i := range v.GetOptions() {
+				opts = append(opts, int(i))
+			}
+			it.Votes = append(it.Votes, PollVoteItem{
+				UserID:  v.GetUserId(),
+				Options: opts,
+				Correct: v.GetCorrect(),
+				VotedAt: v.GetVotedAt().AsTime(),
+			})
+		}
+		out.Items = append(out.Items, it)
+	}
+
+	return out, nil
+}
+
+func (c *client) SetSchedule(ctx context.Context, authHeader string, userID int64, roomID string, in SetScheduleRequest) (ScheduleItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.SetSchedule(rpcCtx, &roomv1.SetScheduleRequest{
+		RoomId:        roomID,
+		StartsAt:      timestamppb.New(in.StartsAt),
+		DurationSec:   in.DurationSec,
+		Rrule:         in.RRule,
+		Timezone:      in.Timezone,
+		OpenBeforeSec: in.OpenBeforeSec,
+	})
+	if err != nil {
+		return ScheduleItem{}, errs.FromGRPC(err)
+	}
+
+	return mapSchedule(res.GetSchedule()), nil
+}
+
+func (c *client) GetSchedule(ctx context.Context, authHeader string, userID int64, roomID string) (ScheduleItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.GetSchedule(rpcCtx, &roomv1.GetScheduleRequest{RoomId: roomID})
+	if err != nil {
+		return ScheduleItem{}, errs.FromGRPC(err)
+	}
+
+	return mapSchedule(res.GetSchedule()), nil
+}
+
+func (c *client) DeleteSchedule(ctx context.Context, authHeader string, userID int64, roomID string) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	if _, err := c.room.DeleteSchedule(rpcCtx, &roomv1.DeleteScheduleRequest{RoomId: roomID}); err != nil {
+		return errs.FromGRPC(err)
+	}
+
+	return nil
+}
+
+// ListSessions — roomID пустой: занятия всех комнат.
+func (c *client) ListSessions(ctx context.Context, authHeader string, userID int64, roomID string, from, to time.Time, limit int32) ([]SessionItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	req := &roomv1.ListSessionsRequest{RoomId: roomID, Limit: limit}
+	if !from.IsZero() {
+		req.From = timestamppb.New(from)
+	}
+	if !to.IsZero() {
+		req.To = timestamppb.New(to)
+	}
+	res, err := c.room.ListSessions(rpcCtx, req)
+	if err != nil {
+		return nil, errs.FromGRPC(err)
+	}
+
+	out := make([]SessionItem, 0, len(res.GetItems()))
+	for _, it := range res.GetItems() {
+		out = append(out, SessionItem{
+			RoomID:   it.GetRoomId(),
+			RoomName: it.GetRoomName(),
+			StartsAt: it.GetStartsAt().AsTime(),
+			EndsAt:   it.GetEndsAt().AsTime(),
+		})
+	}
+
+	return out, nil
+}
+
+func (c *client) CreateCalendarFeed(ctx context.Context, authHeader string, userID int64, roomID string) (CreateCalendarFeedResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.CreateCalendarFeed(rpcCtx, &roomv1.CreateCalendarFeedRequest{RoomId: roomID})
+	if err != nil {
+		return CreateCalendarFeedResponse{}, errs.FromGRPC(err)
+	}
+	return CreateCalendarFeedResponse{Feed: mapFeed(res.GetFeed()), Token: res.GetToken()}, nil
+}
+
+func (c *client) ListCalendarFeeds(ctx context.Context, authHeader string, userID int64) (CalendarFeedsResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ListCalendarFeeds(rpcCtx, &roomv1.ListCalendarFeedsRequest{})
+	if err != nil {
+		return CalendarFeedsResponse{}, errs.FromGRPC(err)
+	}
+	out := CalendarFeedsResponse{Items: make([]CalendarFeedItem, 0, len(res.GetItems()))}
+	for _, f := range res.GetItems() {
+		out.Items = append(out.Items, mapFeed(f))
+	}
+	return out, nil
+}
+
+func (c *client) DeleteCalendarFeed(ctx context.Context, authHeader string, userID int64, id string) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	if _, err := c.room.DeleteCalendarFeed(rpcCtx, &roomv1.DeleteCalendarFeedRequest{Id: id}); err != nil {
+		return errs.FromGRPC(err)
+	}
+	return nil
+}
+
+// FeedSessions — занятия ленты по токену из ссылки, без bearer и x-user-id.
+func (c *client) FeedSessions(ctx context.Context, token string, from, to time.Time) ([]SessionItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, "", 0)
+
+	res, err := c.room.GetFeedSessions(rpcCtx, &roomv1.GetFeedSessionsRequest{
+		Token: token,
+		From:  timestamppb.New(from),
+		To:    timestamppb.New(to),
+	})
+	if err != nil {
+		return nil, errs.FromGRPC(err)
+	}
+
+	out := make([]SessionItem, 0, len(res.GetItems()))
+	for _, it := range res.GetItems() {
+		out = append(out, SessionItem{
+			RoomID:   it.GetRoomId(),
+			RoomName: it.GetRoomName(),
+			StartsAt: it.GetStartsAt().AsTime(),
+			EndsAt:   it.GetEndsAt().AsTime(),
+		})
+	}
+
+	return out, nil
+}
+
+func mapFeed(f *roomv1.CalendarFeed) CalendarFeedItem {
+	return CalendarFeedItem{ID: f.GetId(), RoomID: f.GetRoomId(), CreatedAt: f.GetCreatedAt().AsTime()}
+}
+
+func (c *client) SetLobby(ctx context.Context, authHeader string, userID int64, roomID string, enabled bool) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	if _, err := c.room.SetLobby(rpcCtx, &roomv1.SetLobbyRequest{RoomId: roomID, Enabled: enabled}); err != nil {
+		return errs.FromGRPC(err)
+	}
+
+	return nil
+}
+
+func (c *client) GetLobby(ctx context.Context, authHeader string, userID int64, roomID string) (LobbyResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.GetLobby(rpcCtx, &roomv1.GetLobbyRequest{RoomId: roomID})
+	if err != nil {
+		return LobbyResponse{}, errs.FromGRPC(err)
+	}
+
+	out := LobbyResponse{Enabled: res.GetEnabled(), Queue: make([]LobbyItem, 0, len(res.GetQueue()))}
+	if res.GetSessionStartedAt() != nil {
+		t := res.GetSessionStartedAt().AsTime()
+		out.SessionStartedAt = &t
+	}
+	for i, e := range res.GetQueue() {
+		out.Queue = append(out.Queue, LobbyItem{
+			UserID:   e.GetUserId(),
+			Position: i + 1,
+			QueuedAt: e.GetQueuedAt().AsTime(),
+		})
+	}
+
+	return out, nil
+}
+
+func (c *client) AdmitLobby(ctx context.Context, authHeader string, userID int64, roomID string, in AdmitLobbyRequest) (AdmitLobbyResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.AdmitLobby(rpcCtx, &roomv1.AdmitLobbyRequest{RoomId: roomID, UserId: in.UserID, All: in.All})
+	if err != nil {
+		return AdmitLobbyResponse{}, errs.FromGRPC(err)
+	}
+
+	return AdmitLobbyResponse{Admitted: res.GetAdmitted()}, nil
+}
+
+func (c *client) GetBreakout(ctx context.Context, authHeader string, userID int64, roomID string) (BreakoutResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.GetBreakout(rpcCtx, &roomv1.GetBreakoutRequest{RoomId: roomID})
+	if err != nil {
+		return BreakoutResponse{}, errs.FromGRPC(err)
+	}
+
+	out := BreakoutResponse{
+		ParentID: res.GetParentId(),
+		EndsAt:   res.GetEndsAt().AsTime(),
+		Rooms:    make([]BreakoutRoomItem, 0, len(res.GetRooms())),
+	}
+	for _, r := range res.GetRooms() {
+		out.Rooms = append(out.Rooms, BreakoutRoomItem{
+			ID:      r.GetId(),
+			Name:    r.GetName(),
+			UserIDs: append([]string{}, r.GetUserIds()...),
+		})
+	}
+
+	return out, nil
+}
+
+func (c *client) CreateWebhook(ctx context.Context, authHeader string, userID int64, in CreateWebhookRequest) (CreateWebhookResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.CreateWebhook(rpcCtx, &roomv1.CreateWebhookRequest{Url: in.URL, Events: in.Events})
+	if err != nil {
+		return CreateWebhookResponse{}, errs.FromGRPC(err)
+	}
+	return CreateWebhookResponse{Webhook: mapWebhook(res.GetWebhook()), Secret: res.GetSecret()}, nil
+}
+
+func (c *client) ListWebhooks(ctx context.Context, authHeader string, userID int64) (WebhooksResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ListWebhooks(rpcCtx, &roomv1.ListWebhooksRequest{})
+	if err != nil {
+		return WebhooksResponse{}, errs.FromGRPC(err)
+	}
+	out := WebhooksResponse{Items: make([]WebhookItem, 0, len(res.GetItems()))}
+	for _, w := range res.GetItems() {
+		out.Items = append(out.Items, mapWebhook(w))
+	}
+	return out, nil
+}
+
+func (c *client) DeleteWebhook(ctx context.Context, authHeader string, userID int64, id string) error {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	if _, err := c.room.DeleteWebhook(rpcCtx, &roomv1.DeleteWebhookRequest{Id: id}); err != nil {
+		return errs.FromGRPC(err)
+	}
+	return nil
+}
+
+func (c *client) ListWebhookDeliveries(ctx context.Context, authHeader string, userID int64, webhookID, status, cursor string, limit int64) (WebhookDeliveriesResponse, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.ListWebhookDeliveries(rpcCtx, &roomv1.ListWebhookDeliveriesRequest{
+		WebhookId: webhookID,
+		Status:    status,
+		Limit:     limit,
+		Cursor:    cursor,
+	})
+	if err != nil {
+		return WebhookDeliveriesResponse{}, errs.FromGRPC(err)
+	}
+	out := WebhookDeliveriesResponse{
+		Items:      make([]WebhookDeliveryItem, 0, len(res.GetItems())),
+		NextCursor: res.GetNextCursor(),
+	}
+	for _, d := range res.GetItems() {
+		out.Items = append(out.Items, mapWebhookDelivery(d))
+	}
+	return out, nil
+}
+
+func (c *client) RedeliverWebhook(ctx context.Context, authHeader string, userID int64, deliveryID string) (WebhookDeliveryItem, error) {
+	rpcCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	rpcCtx = withOutboundMeta(rpcCtx, authHeader, userID)
+
+	res, err := c.room.RedeliverWebhook(rpcCtx, &roomv1.RedeliverWebhookRequest{DeliveryId: deliveryID})
+	if err != nil {
+		return WebhookDeliveryItem{}, errs.FromGRPC(err)
+	}
+	return mapWebhookDelivery(res.GetDelivery()), nil
+}
+
+func mapWebhook(in *roomv1.Webhook) WebhookItem {
+	return WebhookItem{
+		ID:        in.GetId(),
+		URL:       in.GetUrl(),
+		Events:    append([]string{}, in.GetEvents()...),
+		Active:    in.GetActive(),
+		CreatedAt: in.GetCreatedAt().AsTime(),
+	}
+}
+
+func mapWebhookDelivery(in *roomv1.WebhookDelivery) WebhookDeliveryItem {
+	out := WebhookDeliveryItem{
+		ID:            in.GetId(),
+		WebhookID:     in.GetWebhookId(),
+		EventID:       in.GetEventId(),
+		EventType:     in.GetEventType(),
+		Status:        in.GetStatus(),
+		Attempts:      in.GetAttempts(),
+		NextAttemptAt: in.GetNextAttemptAt().AsTime(),
+		LastStatus:    in.GetLastStatus(),
+		LastError:     in.GetLastError(),
+		CreatedAt:     in.GetCreatedAt().AsTime(),
+	}
+	if in.GetDeliveredAt() != nil {
+		t := in.GetDeliveredAt().AsTime()
+		out.DeliveredAt = &t
+	}
+	return out
+}
+
+func mapSchedule(in *roomv1.Schedule) ScheduleItem {
+	if in == nil {
+		return ScheduleItem{}
+	}
+	return ScheduleItem{
+		RoomID:        in.GetRoomId(),
+		RoomName:      in.GetRoomName(),
+		StartsAt:      in.GetStartsAt().AsTime(),
+		DurationSec:   in.GetDurationSec(),
+		RRule:         in.GetRrule(),
+		Timezone:      in.GetTimezone(),
+		OpenBeforeSec: in.GetOpenBeforeSec(),
+		CreatedBy:     in.GetCreatedBy(),
+		UpdatedAt:     in.GetUpdatedAt().AsTime(),
+	}
+}
+
+func mapAttachment(in *roomv1.Attachment) AttachmentItem {
+	if in == nil {
+		return AttachmentItem{}
+	}
+	out := AttachmentItem{
+		ID:           in.GetId(),
+		RoomID:       in.GetRoomId(),
+		UserID:       in.GetUserId(),
+		MessageID:    in.GetMessageId(),
+		FileName:     in.GetFileName(),
+		MimeType:     in.GetMimeType(),
+		SizeBytes:    in.GetSizeBytes(),
+		HasThumbnail: in.GetThumbKey() != "",
+		StorageKey:   in.GetStorageKey(),
+		ThumbKey:     in.GetThumbKey(),
+	}
+	if ts := in.GetCreatedAt(); ts != nil {
+		out.CreatedAt = ts.AsTime()
+	}
+
+	return out
+}
+
 func mapRoom(in *roomv1.Room) RoomItem {
 	if in == nil {
 		return RoomItem{}
@@ -234,10 +841,20 @@ func mapRoom(in *roomv1.Room) RoomItem {
 		ID:              in.GetId(),
 		Name:            in.GetName(),
 		MaxParticipants: in.GetMaxParticipants(),
+		OwnerID:         in.GetOwnerId(),
+		ParentID:        in.GetParentId(),
+		Description:     in.GetDescription(),
+		Topic:           in.GetTopic(),
+		Language:        in.GetLanguage(),
+		Tags:            append([]string{}, in.GetTags()...),
 	}
 	if ts := in.GetCreatedAt(); ts != nil {
 		out.CreatedAt = ts.AsTime()
 	}
+	if ts := in.GetActivityAt(); ts != nil {
+		participants, online, at := in.GetParticipants(), in.GetOnline(), ts.AsTime()
+		out.Participants, out.Online, out.ActivityAt = &participants, &online, &at
+	}
 
 	return out
 }
@@ -249,6 +866,8 @@ func mapJoin(in *roomv1.JoinRoomResponse) JoinRoomResponse {
 	}
 	out.RoomID = in.GetRoomId()
 	out.PeerID = in.GetPeerId()
+	out.Lobby = in.GetLobby()
+	out.LobbyPosition = int(in.GetLobbyPosition())
 
 	return out
 }
@@ -1,24 +1,31 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cwrk-planet/api-gateway/internal/ratelimit"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+
+	"github.com/cwrk-planet/confload/pkg/confload"
 )
 
 type HTTP struct {
-	Addr         string        `yaml:"addr"`         // ":8080"
-	ReadTimeout  time.Duration `yaml:"readTimeout"`  // "15s"
-	WriteTimeout time.Duration `yaml:"writeTimeout"` // "30s"
-	IdleTimeout  time.Duration `yaml:"idleTimeout"`  // "60s"
+	Addr         string        `yaml:"addr" default:":8080"`
+	ReadTimeout  time.Duration `yaml:"readTimeout" default:"15s"`
+	WriteTimeout time.Duration `yaml:"writeTimeout" default:"30s"`
+	IdleTimeout  time.Duration `yaml:"idleTimeout" default:"60s"`
+
+	// TrustedProxies — IP/CIDR балансировщиков перед gateway: только от них берём адрес клиента
+	// из X-Forwarded-For / X-Real-IP. Пусто — заголовки игнорируются, клиент — RemoteAddr.
+	TrustedProxies []string `yaml:"trustedProxies"`
 }
 
 type Upstream struct {
-	AuthTarget     string `yaml:"authTarget"`
-	RoomGRPCTarget string `yaml:"roomGRPCTarget"`
+	AuthTarget     string `yaml:"authTarget" default:"localhost:50051"`
+	RoomGRPCTarget string `yaml:"roomGRPCTarget" default:"http://localhost:9092"`
 }
 
 type Logging struct {
@@ -30,42 +37,124 @@ type Logging struct {
 	Debug     bool   `yaml:"debug"`     // включает подробные логи
 }
 
-type Config struct {
-	HTTP     HTTP     `yaml:"http"`
-	Logging  Logging  `yaml:"logging"`
-	Upstream Upstream `yaml:"upstream"`
+// Attachments — загрузка файлов в чат.
+type Attachments struct {
+	StorageDir    string        `yaml:"storageDir" default:"data/attachments"`
+	MaxFileSize   int64         `yaml:"maxFileSize" default:"26214400"`                       // байты, 25 MiB
+	AllowedTypes  []string      `yaml:"allowedTypes"`                                         // "image/png", "image/*", ...
+	URLSecret     string        `yaml:"urlSecret" secret:"true" env:"ATTACHMENTS_URL_SECRET"` // HMAC для подписанных ссылок, не короче 32 байт
+	URLTTL        time.Duration `yaml:"urlTTL" default:"15m"`
+	ThumbnailSize int           `yaml:"thumbnailSize" default:"320"` // px по длинной стороне
+	PublicBaseURL string        `yaml:"publicBaseURL"`               // "http://localhost:8080"
 }
 
-func Load() (*Config, error) {
-	path := os.Getenv("CONFIG_PATH")
-	if path == "" {
-		path = filepath.Join("internal", "config", "config.yaml")
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("read config: %w", err)
-	}
+// Export — выгрузка персональных данных (ZIP в storage вложений).
+type Export struct {
+	TTL             time.Duration `yaml:"ttl" default:"24h"` // сколько хранится готовый архив
+	BuildTimeout    time.Duration `yaml:"buildTimeout" default:"5m"`
+	CleanupInterval time.Duration `yaml:"cleanupInterval" default:"10m"`
+}
+
+// Auth — проверка access-токенов auth-service на стороне gateway (rate limit по пользователю).
+type Auth struct {
+	PublicKeyPath string        `yaml:"publicKeyPath"` // публичный ключ auth-service; пусто — токены не проверяются
+	Issuer        string        `yaml:"issuer"`        // = security.jwt.issuer auth-service, обязательно вместе с ключом
+	Audience      string        `yaml:"audience"`      // = security.jwt.audience auth-service, обязательно вместе с ключом
+	ClockSkew     time.Duration `yaml:"clockSkew" default:"30s"`
+}
+
+// RateLimit — лимиты запросов: первая политика, под маршруты которой попал запрос, иначе default.
+type RateLimit struct {
+	Enabled         bool               `yaml:"enabled"`
+	Policies        []ratelimit.Policy `yaml:"policies"`
+	Default         *ratelimit.Policy  `yaml:"default"`                      // nil — остальные маршруты без лимита
+	CleanupInterval time.Duration      `yaml:"cleanupInterval" default:"1m"` // чистка полных корзин
+}
 
+type Config struct {
+	HTTP        HTTP        `yaml:"http"`
+	Logging     Logging     `yaml:"logging"`
+	Upstream    Upstream    `yaml:"upstream"`
+	Attachments Attachments `yaml:"attachments"`
+	Export      Export      `yaml:"export"`
+	Auth        Auth        `yaml:"auth"`
+	RateLimit   RateLimit   `yaml:"rateLimit"`
+}
+
+// Load — defaults из тегов, internal/config/config.yaml (если есть), env GATEWAY_*, флаги из args; см. confload.
+func Load(args []string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal yaml: %w", err)
+	if err := confload.Load(&cfg, confload.Options{
+		Name:        "api-gateway",
+		EnvPrefix:   "GATEWAY",
+		DefaultPath: filepath.Join("internal", "config", "config.yaml"),
+		Args:        args,
+	}); err != nil {
+		return nil, err
 	}
+	return &cfg, nil
+}
 
-	if cfg.HTTP.Addr == "" {
-		cfg.HTTP.Addr = ":8080"
+// Validate вызывается из Load после всех источников; ошибки собираются все разом.
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := httputil.ParseTrustedProxies(c.HTTP.TrustedProxies); err != nil {
+		errs = append(errs, fmt.Errorf("http.trustedProxies: %w", err))
 	}
-	if cfg.Upstream.RoomGRPCTarget == "" {
-		cfg.Upstream.RoomGRPCTarget = "http://localhost:9092"
+	if len(c.Attachments.URLSecret) < 32 {
+		errs = append(errs, errors.New("attachments.urlSecret must be at least 32 bytes"))
 	}
-	if cfg.HTTP.ReadTimeout == 0 {
-		cfg.HTTP.ReadTimeout = 15 * time.Second
+	if c.Auth.PublicKeyPath != "" && (c.Auth.Issuer == "" || c.Auth.Audience == "") {
+		errs = append(errs, errors.New("auth.issuer and auth.audience are required with auth.publicKeyPath"))
 	}
-	if cfg.HTTP.WriteTimeout == 0 {
-		cfg.HTTP.WriteTimeout = 30 * time.Second
+	if c.Attachments.MaxFileSize <= 0 || c.Attachments.ThumbnailSize <= 0 {
+		errs = append(errs, errors.New("attachments.maxFileSize and thumbnailSize must be > 0"))
 	}
-	if cfg.HTTP.IdleTimeout == 0 {
-		cfg.HTTP.IdleTimeout = 60 * time.Second
+	// дефолты в тегах, поэтому 0 здесь — только явный: без таймаутов сервер висит, тикер с 0 паникует
+	for _, d := range []struct {
+		name string
+		v    time.Duration
+	}{
+		{"http.readTimeout", c.HTTP.ReadTimeout},
+		{"http.writeTimeout", c.HTTP.WriteTimeout},
+		{"http.idleTimeout", c.HTTP.IdleTimeout},
+		{"attachments.urlTTL", c.Attachments.URLTTL},
+		{"export.ttl", c.Export.TTL},
+		{"export.buildTimeout", c.Export.BuildTimeout},
+		{"export.cleanupInterval", c.Export.CleanupInterval},
+		{"auth.clockSkew", c.Auth.ClockSkew},
+		{"rateLimit.cleanupInterval", c.RateLimit.CleanupInterval},
+	} {
+		if d.v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be > 0", d.name))
+		}
 	}
+	errs = append(errs, c.RateLimit.compile())
+	return errors.Join(errs...)
+}
 
-	return &cfg, nil
+func (rl *RateLimit) compile() error {
+	seen := make(map[string]struct{}, len(rl.Policies))
+	for i := range rl.Policies {
+		p := &rl.Policies[i]
+		if err := p.Compile(); err != nil {
+			return fmt.Errorf("rateLimit.policies[%d]: %w", i, err)
+		}
+		if len(p.Routes) == 0 {
+			return fmt.Errorf("rateLimit.policies[%d] %s: routes are required", i, p.Name)
+		}
+		if _, ok := seen[p.Name]; ok {
+			return fmt.Errorf("rateLimit.policies: duplicate name %q", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+	}
+	if rl.Default != nil {
+		if rl.Default.Name == "" {
+			rl.Default.Name = "default"
+		}
+		if err := rl.Default.Compile(); err != nil {
+			return fmt.Errorf("rateLimit.default: %w", err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,61 @@
+// Package ratelimit — ограничение частоты запросов в api-gateway: token bucket
+// по ключу (политика + маршрут + IP или пользователь), политики из config.yaml.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Bucket — параметры корзины: Burst токенов, пополнение Rate токенов в секунду.
+type Bucket struct {
+	Burst int
+	Rate  float64
+}
+
+// Result — итог попытки взять токен.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // через сколько корзина снова полная
+	RetryAfter time.Duration // через сколько появится токен (только если !Allowed)
+}
+
+// Store — где живут корзины. Memory — на один инстанс; для нескольких инстансов
+// нужна общая реализация (Redis и т.п.) с той же семантикой.
+type Store interface {
+	Take(ctx context.Context, key string, b Bucket, now time.Time) (Result, error)
+}
+
+// state — корзина в хранилище: сколько токенов было на момент last.
+type state struct {
+	tokens float64
+	last   time.Time
+}
+
+// take — общая арифметика token bucket, пригодится и другим реализациям Store.
+func take(st *state, b Bucket, now time.Time) Result {
+	if st.last.IsZero() {
+		st.tokens = float64(b.Burst)
+	} else if el := now.Sub(st.last).Seconds(); el > 0 {
+		st.tokens = math.Min(float64(b.Burst), st.tokens+el*b.Rate)
+	}
+	st.last = now
+
+	res := Result{Limit: b.Burst}
+	if st.tokens >= 1 {
+		st.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - st.tokens) / b.Rate)
+	}
+	res.Remaining = int(st.tokens)
+	res.Reset = seconds((float64(b.Burst) - st.tokens) / b.Rate)
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
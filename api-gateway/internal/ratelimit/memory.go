@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore — корзины в памяти процесса.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memBucket
+}
+
+type memBucket struct {
+	state
+	full time.Time // когда корзина снова полная — после этого её можно забыть
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memBucket)}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, b Bucket, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mb, ok := s.buckets[key]
+	if !ok {
+		mb = &memBucket{}
+		s.buckets[key] = mb
+	}
+	res := take(&mb.state, b, now)
+	mb.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// Run — раз в every выкидывает полные корзины (они ничем не отличаются от новых).
+func (s *MemoryStore) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case now := <-t.C:
+			s.sweep(now)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, mb := range s.buckets {
+		if !now.Before(mb.full) {
+			delete(s.buckets, k)
+		}
+	}
+}
+
+// Len — сколько корзин сейчас в памяти.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
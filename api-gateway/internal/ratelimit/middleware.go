@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// Заголовки по draft-ietf-httpapi-ratelimit-headers.
+const (
+	HeaderLimit      = "RateLimit-Limit"
+	HeaderRemaining  = "RateLimit-Remaining"
+	HeaderReset      = "RateLimit-Reset"
+	HeaderPolicy     = "RateLimit-Policy"
+	HeaderRetryAfter = "Retry-After"
+)
+
+// UserFunc — пользователь из проверенного токена запроса; ok=false — анонимный запрос.
+type UserFunc func(r *http.Request) (uid int64, ok bool)
+
+// Limiter — middleware: первая подошедшая политика, иначе политика по умолчанию
+// (nil — остальные маршруты без лимита).
+type Limiter struct {
+	store    Store
+	user     UserFunc
+	policies []*Policy
+	def      *Policy
+	now      func() time.Time
+}
+
+// New — политики должны быть уже скомпилированы (Policy.Compile).
+func New(store Store, user UserFunc, policies []Policy, def *Policy) *Limiter {
+	l := &Limiter{store: store, user: user, def: def, now: time.Now}
+	for i := range policies {
+		l.policies = append(l.policies, &policies[i])
+	}
+	return l
+}
+
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+		p, route := l.policyFor(r)
+		if p == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := p.Name + "|" + route + "|" + l.subject(p, r)
+		res, err := l.store.Take(r.Context(), key, p.Bucket(), l.now())
+		if err != nil {
+			// хранилище недоступно — лучше пропустить, чем положить весь API
+			slog.Warn("ratelimit: store failed", "policy", p.Name, "err", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set(HeaderLimit, strconv.Itoa(res.Limit))
+		h.Set(HeaderRemaining, strconv.Itoa(res.Remaining))
+		h.Set(HeaderReset, strconv.Itoa(ceilSeconds(res.Reset)))
+		h.Set(HeaderPolicy, fmt.Sprintf("%d;w=%d", p.Requests, ceilSeconds(p.Per)))
+		if !res.Allowed {
+			retry := max(ceilSeconds(res.RetryAfter), 1)
+			h.Set(HeaderRetryAfter, strconv.Itoa(retry))
+			httputil.Error(r.Context(), w, http.StatusTooManyRequests, "rate limit exceeded", map[string]any{
+				"policy":      p.Name,
+				"retry_after": retry,
+			})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (l *Limiter) policyFor(r *http.Request) (*Policy, string) {
+	for _, p := range l.policies {
+		if route, ok := p.match(r); ok {
+			return p, route
+		}
+	}
+	if l.def != nil {
+		return l.def, "*"
+	}
+	return nil, ""
+}
+
+func (l *Limiter) subject(p *Policy, r *http.Request) string {
+	if p.By == ByUser && l.user != nil {
+		if uid, ok := l.user(r); ok {
+			return "user:" + strconv.FormatInt(uid, 10)
+		}
+	}
+	return "ip:" + clientIP(r)
+}
+
+// clientIP — RemoteAddr (после httputil.MiddlewareRealIP там уже адрес клиента, иногда без порта).
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// KeyBy — по чему считаются запросы.
+type KeyBy string
+
+const (
+	ByIP   KeyBy = "ip"   // адрес клиента
+	ByUser KeyBy = "user" // пользователь из проверенного токена; без токена — по IP
+)
+
+// Policy — лимит для набора маршрутов: Requests запросов за Per, всплеск до Burst.
+// Маршрут — "METHOD /path", сегмент {x} — любой, /* в конце — любой хвост; без метода — все методы.
+type Policy struct {
+	Name     string        `yaml:"name"`
+	Routes   []string      `yaml:"routes"`
+	By       KeyBy         `yaml:"by"`
+	Requests int           `yaml:"requests"`
+	Per      time.Duration `yaml:"per"`
+	Burst    int           `yaml:"burst"` // 0 — равен Requests
+
+	routes []route
+}
+
+type route struct {
+	pattern string
+	method  string
+	parts   []string
+	tail    bool
+}
+
+// Bucket — параметры корзины политики.
+func (p *Policy) Bucket() Bucket {
+	return Bucket{Burst: p.Burst, Rate: float64(p.Requests) / p.Per.Seconds()}
+}
+
+// Compile — проверка и дефолты; вызывается при загрузке конфига.
+func (p *Policy) Compile() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("rate limit policy: name is required")
+	}
+	if p.Requests <= 0 || p.Per <= 0 {
+		return fmt.Errorf("rate limit policy %s: requests and per must be > 0", p.Name)
+	}
+	if p.Burst < 0 {
+		return fmt.Errorf("rate limit policy %s: burst must be >= 0", p.Name)
+	}
+	if p.Burst == 0 {
+		p.Burst = p.Requests
+	}
+	switch p.By {
+	case "":
+		p.By = ByIP
+	case ByIP, ByUser:
+	default:
+		return fmt.Errorf("rate limit policy %s: unknown by %q (ip|user)", p.Name, p.By)
+	}
+
+	p.routes = p.routes[:0]
+	for _, raw := range p.Routes {
+		rt := route{pattern: strings.TrimSpace(raw)}
+		path := rt.pattern
+		if m, rest, ok := strings.Cut(path, " "); ok {
+			rt.method, path = strings.ToUpper(m), strings.TrimSpace(rest)
+		}
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("rate limit policy %s: route %q must start with /", p.Name, raw)
+		}
+		if prefix, ok := strings.CutSuffix(path, "/*"); ok {
+			rt.tail, path = true, prefix
+		}
+		rt.parts = splitPath(path)
+		p.routes = append(p.routes, rt)
+	}
+	return nil
+}
+
+// match — шаблон маршрута, под который попал запрос.
+func (p *Policy) match(r *http.Request) (string, bool) {
+	parts := splitPath(r.URL.Path)
+	for _, rt := range p.routes {
+		if rt.method != "" && rt.method != r.Method {
+			continue
+		}
+		if len(parts) < len(rt.parts) || (!rt.tail && len(parts) != len(rt.parts)) {
+			continue
+		}
+		ok := true
+		for i, seg := range rt.parts {
+			if seg != parts[i] && !(strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return rt.pattern, true
+		}
+	}
+	return "", false
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
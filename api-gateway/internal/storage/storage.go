@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("storage: object not found")
+	ErrInvalidKey = errors.New("storage: invalid key")
+)
+
+// Storage — хранилище байтов вложений и выгрузок. Ключи вида "rooms/<room_id>/<id>", "exports/<id>.zip".
+// Первый бэкенд — локальный диск, дальше можно добавить S3 и т.п.
+type Storage interface {
+	// Put читает r до EOF и возвращает число записанных байт.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
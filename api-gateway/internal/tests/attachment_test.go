@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/internal/app/attachment"
+	"github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/internal/storage"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+
+	"github.com/google/uuid"
+)
+
+// attachmentRoom — room-service в памяти: метаданные вложений, пользователь 2 в комнате не состоит
+type attachmentRoom struct {
+	room.Client
+	items map[string]room.CreateAttachmentRequest
+}
+
+func (r *attachmentRoom) CreateAttachment(_ context.Context, _ string, userID int64, in room.CreateAttachmentRequest) (room.CreateAttachmentResponse, error) {
+	if userID != 1 {
+		return room.CreateAttachmentResponse{}, errs.ErrForbidden
+	}
+	r.items[in.ID] = in
+	return room.CreateAttachmentResponse{Attachment: room.AttachmentItem{ID: in.ID, RoomID: in.RoomID, FileName: in.FileName, MimeType: in.MimeType, SizeBytes: in.SizeBytes, HasThumbnail: in.ThumbKey != ""}}, nil
+}
+
+func (r *attachmentRoom) GetAttachment(_ context.Context, _ string, userID int64, roomID, id string) (room.AttachmentItem, error) {
+	in, ok := r.items[id]
+	if !ok || in.RoomID != roomID || userID != 1 {
+		return room.AttachmentItem{}, errs.ErrNotFound
+	}
+	return room.AttachmentItem{ID: in.ID, RoomID: in.RoomID, FileName: in.FileName, MimeType: in.MimeType, HasThumbnail: in.ThumbKey != ""}, nil
+}
+
+func newAttachments(t *testing.T, ttl time.Duration) (*attachment.Service, *attachmentRoom, storage.Storage) {
+	t.Helper()
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := &attachmentRoom{items: map[string]room.CreateAttachmentRequest{}}
+	svc := attachment.New(rm, store, attachment.Options{
+		MaxFileSize:   64 << 10,
+		AllowedTypes:  []string{"image/*", "text/plain"},
+		URLSecret:     strings.Repeat("s", 32),
+		URLTTL:        ttl,
+		ThumbnailSize: 32,
+		PublicBaseURL: "http://gw.test/",
+	})
+	return svc, rm, store
+}
+
+func pngBytes(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		img.Set(x, x%h, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// signedQuery — id и параметры из выданной ссылки
+func signedQuery(t *testing.T, raw string) (string, url.Values) {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimPrefix(u.Path, "/attachments/"), u.Query()
+}
+
+func TestAttachment_UploadThumbnailAndSignedDownload(t *testing.T) {
+	svc, _, _ := newAttachments(t, time.Minute)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+
+	// тип определяется по содержимому, имя чистится от пути
+	res, err := svc.Upload(ctx, "", 1, roomID, `C:\Users\me\photo.png`, bytes.NewReader(pngBytes(t, 200, 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := res.Attachment
+	if a.MimeType != "image/png" || a.FileName != "photo.png" || !a.HasThumbnail {
+		t.Fatalf("attachment = %+v", a)
+	}
+
+	link, err := svc.SignURL(ctx, "", 1, roomID, a.ID, attachment.VariantThumb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(link.URL, "http://gw.test/attachments/"+a.ID+"?") {
+		t.Fatalf("url = %s", link.URL)
+	}
+	id, q := signedQuery(t, link.URL)
+	d, err := svc.Open(ctx, id, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer d.Body.Close()
+	thumb, err := jpeg.Decode(d.Body)
+	if err != nil {
+		t.Fatalf("thumbnail is not jpeg: %v", err)
+	}
+	if b := thumb.Bounds(); b.Dx() != 32 || b.Dy() != 16 || d.FileName != "photo.jpg" {
+		t.Fatalf("thumbnail %v %q, want 32x16 photo.jpg", b, d.FileName)
+	}
+
+	// подпись покрывает все параметры
+	q.Set("uid", "2")
+	if _, err := svc.Open(ctx, id, q); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("tampered link: err = %v, want forbidden", err)
+	}
+	// ссылку выдают только участнику комнаты
+	if _, err := svc.SignURL(ctx, "", 2, roomID, a.ID, ""); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("foreign sign: err = %v", err)
+	}
+}
+
+func TestAttachment_Rejects(t *testing.T) {
+	svc, rm, _ := newAttachments(t, time.Minute)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+
+	if _, err := svc.Upload(ctx, "", 1, "../etc", "a.txt", strings.NewReader("hi")); !errors.Is(err, errs.ErrInvalidInput) {
+		t.Fatalf("bad room id: err = %v", err)
+	}
+	// PDF не в списке разрешенных, хотя клиент назвал файл .png
+	if _, err := svc.Upload(ctx, "", 1, roomID, "x.png", strings.NewReader("%PDF-1.7\n...")); !errors.Is(err, errs.ErrUnsupportedMedia) {
+		t.Fatalf("pdf: err = %v", err)
+	}
+	if _, err := svc.Upload(ctx, "", 1, roomID, "big.txt", strings.NewReader(strings.Repeat("a", 64<<10+1))); !errors.Is(err, errs.ErrTooLarge) {
+		t.Fatalf("too large: err = %v", err)
+	}
+	// не участник: room-service отказал, метаданных нет
+	if _, err := svc.Upload(ctx, "", 2, roomID, "a.txt", strings.NewReader("hi")); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("non-member: err = %v", err)
+	}
+	if len(rm.items) != 0 {
+		t.Fatalf("rejected uploads left metadata: %v", rm.items)
+	}
+}
+
+func TestAttachment_LinkExpires(t *testing.T) {
+	svc, _, _ := newAttachments(t, -time.Second)
+	ctx := context.Background()
+	roomID := uuid.NewString()
+
+	res, err := svc.Upload(ctx, "", 1, roomID, "notes.txt", strings.NewReader("конспект"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Attachment.HasThumbnail {
+		t.Fatal("text must not get a thumbnail")
+	}
+	link, err := svc.SignURL(ctx, "", 1, roomID, res.Attachment.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, q := signedQuery(t, link.URL)
+	if _, err := svc.Open(ctx, id, q); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("expired link: err = %v", err)
+	}
+
+	// и с живой ссылкой файл отдается как есть
+	svc2, _, _ := newAttachments(t, time.Minute)
+	res, err = svc2.Upload(ctx, "", 1, roomID, "notes.txt", strings.NewReader("конспект"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, _ = svc2.SignURL(ctx, "", 1, roomID, res.Attachment.ID, "")
+	id, q = signedQuery(t, link.URL)
+	d, err := svc2.Open(ctx, id, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(d.Body)
+	_ = d.Body.Close()
+	if string(b) != "конспект" || d.ContentType != "text/plain" {
+		t.Fatalf("download = %q %s", b, d.ContentType)
+	}
+}
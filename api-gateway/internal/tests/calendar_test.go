@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/internal/app/room"
+	transport "github.com/cwrk-planet/api-gateway/internal/transport/http"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+)
+
+// feedRoom — room-service в памяти: ленты .ics по токену, у пользователя 1 одно занятие
+type feedRoom struct {
+	room.Client
+	feeds map[string]int64 // token -> user
+}
+
+func (r *feedRoom) CreateCalendarFeed(_ context.Context, _ string, userID int64, roomID string) (room.CreateCalendarFeedResponse, error) {
+	token := "tok" + string(rune('a'+len(r.feeds)))
+	r.feeds[token] = userID
+	return room.CreateCalendarFeedResponse{Feed: room.CalendarFeedItem{ID: "feed-" + token, RoomID: roomID}, Token: token}, nil
+}
+
+func (r *feedRoom) DeleteCalendarFeed(_ context.Context, _ string, userID int64, id string) error {
+	token := strings.TrimPrefix(id, "feed-")
+	if uid, ok := r.feeds[token]; !ok || uid != userID {
+		return errs.ErrNotFound
+	}
+	delete(r.feeds, token)
+	return nil
+}
+
+func (r *feedRoom) FeedSessions(_ context.Context, token string, _, _ time.Time) ([]room.SessionItem, error) {
+	if _, ok := r.feeds[token]; !ok {
+		return nil, errs.ErrNotFound
+	}
+	start := time.Date(2026, 3, 3, 18, 0, 0, 0, time.UTC)
+	return []room.SessionItem{{RoomID: "r-1", RoomName: "алгоритмы", StartsAt: start, EndsAt: start.Add(time.Hour)}}, nil
+}
+
+// календарь забирает ленту по ссылке без Authorization; после удаления ленты ссылка не работает
+func TestCalendarFeed_FetchWithoutBearer(t *testing.T) {
+	rm := &feedRoom{feeds: map[string]int64{}}
+	router := transport.NewRouter(transport.Deps{RoomClient: rm, PublicBaseURL: "https://cwrk.test/"})
+
+	do := func(method, path, body string, authed bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		if authed {
+			req.Header.Set("Authorization", "Bearer t")
+			req.Header.Set("X-User-ID", "1")
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPost, "/calendar-feeds/", `{"room_id":"r-1"}`, false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("create without bearer: %d", rec.Code)
+	}
+	rec := do(http.MethodPost, "/calendar-feeds/", `{"room_id":"r-1"}`, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("create: %d %s", rec.Code, rec.Body.String())
+	}
+	var created struct {
+		Data room.CreateCalendarFeedResponse `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	feedURL := created.Data.URL
+	if feedURL != "https://cwrk.test/calendar/"+created.Data.Token+".ics" {
+		t.Fatalf("feed url = %q", feedURL)
+	}
+	path := strings.TrimPrefix(feedURL, "https://cwrk.test")
+
+	rec = do(http.MethodGet, path, "", false)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("fetch: %d %s", rec.Code, rec.Body.String())
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Fatalf("content type = %q", ct)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "BEGIN:VCALENDAR") || !strings.Contains(body, "DTSTART:20260303T180000Z") {
+		t.Fatalf("ics:\n%s", body)
+	}
+
+	if rec := do(http.MethodGet, "/calendar/forged.ics", "", false); rec.Code != http.StatusNotFound {
+		t.Fatalf("forged token: %d", rec.Code)
+	}
+
+	if rec := do(http.MethodDelete, "/calendar-feeds/"+created.Data.Feed.ID, "", true); rec.Code != http.StatusOK {
+		t.Fatalf("delete: %d %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodGet, path, "", false); rec.Code != http.StatusNotFound {
+		t.Fatalf("revoked feed: %d", rec.Code)
+	}
+}
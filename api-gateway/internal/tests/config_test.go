@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cwrk-planet/api-gateway/internal/config"
+
+	"github.com/cwrk-planet/confload/pkg/confload"
+)
+
+const testURLSecret = "0123456789abcdef0123456789abcdef" // ровно 32 байта
+
+func TestConfig_URLSecretLength(t *testing.T) {
+	t.Setenv("ATTACHMENTS_URL_SECRET", testURLSecret[:31])
+	if _, err := config.Load(nil); err == nil || !strings.Contains(err.Error(), "attachments.urlSecret") {
+		t.Fatalf("31-byte secret: err = %v", err)
+	}
+
+	t.Setenv("ATTACHMENTS_URL_SECRET", testURLSecret)
+	cfg, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := confload.Redacted(cfg); strings.Contains(s, testURLSecret) {
+		t.Fatalf("urlSecret leaked:\n%s", s)
+	}
+}
+
+func TestConfig_ExplicitZeroDurations(t *testing.T) {
+	t.Setenv("ATTACHMENTS_URL_SECRET", testURLSecret)
+
+	_, err := config.Load([]string{
+		"-http.writeTimeout=0s",
+		"-attachments.urlTTL=0s",
+		"-export.cleanupInterval=0s",
+		"-rateLimit.cleanupInterval=0s",
+	})
+	var list confload.Errors
+	if !errors.As(err, &list) {
+		t.Fatalf("err = %v, want confload.Errors", err)
+	}
+	for _, want := range []string{"http.writeTimeout", "attachments.urlTTL", "export.cleanupInterval", "rateLimit.cleanupInterval"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "http.readTimeout") {
+		t.Errorf("default http.readTimeout must pass:\n%v", err)
+	}
+}
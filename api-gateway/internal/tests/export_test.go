@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/internal/app/export"
+	"github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/internal/storage"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// exportAuth — пользователь по токену: "Bearer u<id>"
+type exportAuth struct {
+	auth.Client
+}
+
+func (a exportAuth) user(ctx context.Context) (auth.User, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		switch v[0] {
+		case "Bearer u1":
+			return auth.User{Id: 1, Email: "one@example.com"}, nil
+		case "Bearer u2":
+			return auth.User{Id: 2, Email: "two@example.com"}, nil
+		}
+	}
+	return auth.User{}, errs.ErrUnauthorized
+}
+
+func (a exportAuth) Me(ctx context.Context) (auth.MeResponse, error) {
+	u, err := a.user(ctx)
+	return auth.MeResponse{User: u}, err
+}
+
+func (a exportAuth) ExportMyData(ctx context.Context) (auth.PersonalData, error) {
+	u, err := a.user(ctx)
+	return auth.PersonalData{User: u, Sessions: []auth.Session{{Id: 10, Ip: "10.0.0.1"}}}, err
+}
+
+// exportRoom — 3 сообщения пользователя 1, по 2 на страницу
+type exportRoom struct {
+	room.Client
+}
+
+func (exportRoom) ListMyMemberships(_ context.Context, authHeader string, userID int64) ([]room.MembershipItem, error) {
+	return []room.MembershipItem{{RoomID: "r-1", RoomName: "demo", Owner: true}}, nil
+}
+
+func (exportRoom) ListMyMessages(_ context.Context, _ string, _ int64, cursor string, _ int32) (room.ChatHistoryResponse, error) {
+	if cursor == "" {
+		return room.ChatHistoryResponse{Items: []room.ChatMessageItem{{ID: "m3"}, {ID: "m2"}}, NextCursor: "c1"}, nil
+	}
+	return room.ChatHistoryResponse{Items: []room.ChatMessageItem{{ID: "m1"}}}, nil
+}
+
+func TestExport_BuildsZip(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := export.New(exportAuth{}, exportRoom{}, store, export.Options{TTL: time.Hour})
+	ctx := context.Background()
+
+	job, err := svc.Start(ctx, "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for job.Status == export.StatusPending {
+		if time.Now().After(deadline) {
+			t.Fatal("export is still pending")
+		}
+		time.Sleep(10 * time.Millisecond)
+		if job, err = svc.Get(ctx, "u1", job.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if job.Status != export.StatusReady || job.ExpiresAt == nil {
+		t.Fatalf("job = %+v, want ready", job)
+	}
+
+	// чужой архив не видно
+	if _, err := svc.Get(ctx, "u2", job.ID); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("foreign get: err = %v, want not found", err)
+	}
+	if _, err := svc.Open(ctx, "u2", job.ID); !errors.Is(err, errs.ErrNotFound) {
+		t.Fatalf("foreign open: err = %v, want not found", err)
+	}
+
+	f, err := svc.Open(ctx, "u1", job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string][]byte{}
+	for _, zf := range zr.File {
+		r, err := zf.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[zf.Name], _ = io.ReadAll(r)
+		_ = r.Close()
+	}
+
+	var profile auth.User
+	if err := json.Unmarshal(files["profile.json"], &profile); err != nil || profile.Id != 1 {
+		t.Fatalf("profile.json = %s (%v)", files["profile.json"], err)
+	}
+	if !strings.Contains(string(files["sessions.json"]), "10.0.0.1") {
+		t.Fatalf("sessions.json = %s", files["sessions.json"])
+	}
+	if !strings.Contains(string(files["memberships.json"]), `"room_name": "demo"`) {
+		t.Fatalf("memberships.json = %s", files["memberships.json"])
+	}
+	var msgs []room.ChatMessageItem
+	if err := json.Unmarshal(files["messages.json"], &msgs); err != nil {
+		t.Fatalf("messages.json = %s (%v)", files["messages.json"], err)
+	}
+	if len(msgs) != 3 || msgs[0].ID != "m3" || msgs[2].ID != "m1" {
+		t.Fatalf("messages = %+v, want m3, m2, m1", msgs)
+	}
+}
+
+func TestExport_Unauthorized(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := export.New(exportAuth{}, exportRoom{}, store, export.Options{})
+	if _, err := svc.Start(context.Background(), "nobody"); !errors.Is(err, errs.ErrUnauthorized) {
+		t.Fatalf("err = %v, want unauthorized", err)
+	}
+}
@@ -0,0 +1,249 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/internal/ratelimit"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+func compile(t *testing.T, ps ...ratelimit.Policy) []ratelimit.Policy {
+	t.Helper()
+	for i := range ps {
+		if err := ps[i].Compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return ps
+}
+
+// headerUser — пользователь из X-Test-User (в тестах вместо проверки токена).
+func headerUser(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(r.Header.Get("X-Test-User"), 10, 64)
+	return id, err == nil
+}
+
+func doRequest(h http.Handler, method, path, ip, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	req.RemoteAddr = ip + ":12345"
+	if user != "" {
+		req.Header.Set("X-Test-User", user)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func newLimited(t *testing.T, def *ratelimit.Policy, ps ...ratelimit.Policy) http.Handler {
+	t.Helper()
+	if def != nil {
+		if err := def.Compile(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l := ratelimit.New(ratelimit.NewMemoryStore(), headerUser, compile(t, ps...), def)
+	return l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func TestRateLimit_LoginByIP(t *testing.T) {
+	h := newLimited(t, nil, ratelimit.Policy{
+		Name: "login", Routes: []string{"POST /auth/login"}, By: ratelimit.ByIP, Requests: 2, Per: time.Minute,
+	})
+
+	for i := range 2 {
+		rec := doRequest(h, http.MethodPost, "/auth/login", "10.0.0.1", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: status %d", i, rec.Code)
+		}
+		if got, want := rec.Header().Get(ratelimit.HeaderRemaining), strconv.Itoa(1-i); got != want {
+			t.Fatalf("request %d: remaining %s, want %s", i, got, want)
+		}
+	}
+
+	rec := doRequest(h, http.MethodPost, "/auth/login", "10.0.0.1", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("third request: status %d", rec.Code)
+	}
+	retry, _ := strconv.Atoi(rec.Header().Get(ratelimit.HeaderRetryAfter))
+	if retry < 1 || retry > 30 {
+		t.Fatalf("Retry-After = %q", rec.Header().Get(ratelimit.HeaderRetryAfter))
+	}
+	if rec.Header().Get(ratelimit.HeaderLimit) != "2" || rec.Header().Get(ratelimit.HeaderPolicy) != "2;w=60" {
+		t.Fatalf("headers: %v", rec.Header())
+	}
+	var body struct {
+		Error struct {
+			Message string         `json:"message"`
+			Meta    map[string]any `json:"meta"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error.Message != "rate limit exceeded" || body.Error.Meta["policy"] != "login" {
+		t.Fatalf("body: %+v", body)
+	}
+
+	// другой адрес и другой маршрут — свои корзины
+	if rec := doRequest(h, http.MethodPost, "/auth/login", "10.0.0.2", ""); rec.Code != http.StatusOK {
+		t.Fatalf("other ip: status %d", rec.Code)
+	}
+	if rec := doRequest(h, http.MethodPost, "/auth/register", "10.0.0.1", ""); rec.Code != http.StatusOK || rec.Header().Get(ratelimit.HeaderLimit) != "" {
+		t.Fatalf("unlimited route: status %d, headers %v", rec.Code, rec.Header())
+	}
+}
+
+func TestRateLimit_ByUserWithIPFallback(t *testing.T) {
+	h := newLimited(t, nil, ratelimit.Policy{
+		Name: "rooms", Routes: []string{"POST /rooms", "POST /rooms/{id}/attachments"}, By: ratelimit.ByUser, Requests: 1, Per: time.Minute,
+	})
+
+	if rec := doRequest(h, http.MethodPost, "/rooms", "10.0.0.1", "7"); rec.Code != http.StatusOK {
+		t.Fatalf("first: %d", rec.Code)
+	}
+	// тот же пользователь с другого адреса — та же корзина
+	if rec := doRequest(h, http.MethodPost, "/rooms/", "10.0.0.9", "7"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("same user: %d", rec.Code)
+	}
+	// другой пользователь с того же адреса — своя
+	if rec := doRequest(h, http.MethodPost, "/rooms", "10.0.0.1", "8"); rec.Code != http.StatusOK {
+		t.Fatalf("other user: %d", rec.Code)
+	}
+	// без токена — по IP
+	if rec := doRequest(h, http.MethodPost, "/rooms", "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("anonymous: %d", rec.Code)
+	}
+	if rec := doRequest(h, http.MethodPost, "/rooms", "10.0.0.1", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("anonymous again: %d", rec.Code)
+	}
+	// шаблон с {id}: маршрут — отдельный ключ
+	if rec := doRequest(h, http.MethodPost, "/rooms/r-1/attachments", "10.0.0.1", "7"); rec.Code != http.StatusOK {
+		t.Fatalf("attachments: %d", rec.Code)
+	}
+	if rec := doRequest(h, http.MethodGet, "/rooms", "10.0.0.1", "7"); rec.Code != http.StatusOK {
+		t.Fatalf("other method: %d", rec.Code)
+	}
+}
+
+func TestRateLimit_DefaultPolicy(t *testing.T) {
+	h := newLimited(t, &ratelimit.Policy{By: ratelimit.ByIP, Requests: 1, Per: time.Minute, Name: "default"})
+
+	if rec := doRequest(h, http.MethodGet, "/rooms", "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("first: %d", rec.Code)
+	}
+	if rec := doRequest(h, http.MethodGet, "/sessions", "10.0.0.1", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("default is shared across routes: %d", rec.Code)
+	}
+	// preflight не считается
+	if rec := doRequest(h, http.MethodOptions, "/rooms", "10.0.0.1", ""); rec.Code != http.StatusOK {
+		t.Fatalf("options: %d", rec.Code)
+	}
+}
+
+func TestMemoryStore_RefillAndSweep(t *testing.T) {
+	s := ratelimit.NewMemoryStore()
+	b := ratelimit.Bucket{Burst: 2, Rate: 1} // 1 токен в секунду
+	now := time.Unix(1700000000, 0)
+	ctx := context.Background()
+
+	for range 2 {
+		if res, _ := s.Take(ctx, "k", b, now); !res.Allowed {
+			t.Fatal("burst must be allowed")
+		}
+	}
+	res, _ := s.Take(ctx, "k", b, now)
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 2*time.Second {
+		t.Fatalf("empty bucket: %+v", res)
+	}
+	if res, _ := s.Take(ctx, "k", b, now.Add(1500*time.Millisecond)); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("after refill: %+v", res)
+	}
+
+	sweepCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go s.Run(sweepCtx, 10*time.Millisecond)
+	// корзина полная через ~1.5s от "now" из прошлого — для реальных часов давно полная
+	deadline := time.Now().Add(time.Second)
+	for s.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("full buckets must be swept")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestPolicy_CompileErrors(t *testing.T) {
+	for _, p := range []ratelimit.Policy{
+		{Name: "", Requests: 1, Per: time.Second},
+		{Name: "x", Requests: 0, Per: time.Second},
+		{Name: "x", Requests: 1, Per: time.Second, By: "session"},
+		{Name: "x", Requests: 1, Per: time.Second, Routes: []string{"POST rooms"}},
+	} {
+		if err := p.Compile(); err == nil {
+			t.Fatalf("expected error for %+v", p)
+		}
+	}
+}
+
+// Подставленный X-Forwarded-For не даёт новую корзину: заголовкам верим только от доверенного прокси,
+// и из цепочки берём самый правый адрес не из доверенных.
+func TestRateLimit_SpoofedForwardedForIgnored(t *testing.T) {
+	trusted, err := httputil.ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := httputil.MiddlewareRealIP(trusted)(newLimited(t, nil, ratelimit.Policy{
+		Name: "login", Routes: []string{"POST /auth/login"}, By: ratelimit.ByIP, Requests: 2, Per: time.Minute,
+	}))
+	login := func(peer string, headers ...string) int {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+		req.RemoteAddr = peer + ":12345"
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Add(headers[i], headers[i+1])
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// напрямую: каждый раз новый X-Forwarded-For / X-Real-IP — корзина всё равно одна
+	for i, hdr := range [][]string{
+		{"X-Forwarded-For", "198.51.100.1"},
+		{"X-Real-IP", "198.51.100.2"},
+		{"True-Client-IP", "198.51.100.3", "X-Forwarded-For", "198.51.100.4"},
+	} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code := login("203.0.113.5", hdr...); code != want {
+			t.Fatalf("direct request %d: status %d, want %d", i, code, want)
+		}
+	}
+
+	// через доверенный прокси клиенты различаются; левую часть цепочки клиент пишет сам
+	for i, xff := range []string{"198.51.100.9", "6.6.6.6, 198.51.100.9", "7.7.7.7, 198.51.100.9, 10.1.2.3"} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code := login("10.0.0.1", "X-Forwarded-For", xff); code != want {
+			t.Fatalf("proxied request %d (%s): status %d, want %d", i, xff, code, want)
+		}
+	}
+	if code := login("192.0.2.7", "X-Real-IP", "198.51.100.10"); code != http.StatusOK {
+		t.Fatalf("other client behind proxy: status %d", code)
+	}
+
+	if _, err := httputil.ParseTrustedProxies([]string{"10.0.0.0/33"}); err == nil {
+		t.Fatal("bad CIDR must be rejected")
+	}
+}
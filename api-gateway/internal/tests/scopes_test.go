@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/internal/app/room"
+	transport "github.com/cwrk-planet/api-gateway/internal/transport/http"
+)
+
+// без rooms:create gateway не пускает в POST /rooms, до room-service запрос не доходит
+func TestRouter_CreateRoomRequiresScope(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.NewTokenVerifier(writePublicKey(t, key), "cwrk-auth", "cwrk", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	router := transport.NewRouter(transport.Deps{Tokens: tokens})
+
+	sign := func(scope string) string { return signToken(t, key, accessClaims("5", scope)) }
+
+	for name, tc := range map[string]struct {
+		header string
+		want   int
+	}{
+		"no token": {"", http.StatusUnauthorized},
+		"no scope": {"Bearer " + sign("admin:users"), http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/rooms/", strings.NewReader(`{"name":"x"}`))
+		if tc.header != "" {
+			req.Header.Set("Authorization", tc.header)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: want %d, got %d (%s)", name, tc.want, rec.Code, rec.Body.String())
+		}
+	}
+}
+
+// identityRoom — room-service в памяти: запоминает, от чьего имени пришел GetRoom
+type identityRoom struct {
+	room.Client
+	calls []int64
+}
+
+func (r *identityRoom) GetRoom(_ context.Context, _ string, userID int64, id string) (room.RoomItem, error) {
+	r.calls = append(r.calls, userID)
+	return room.RoomItem{ID: id}, nil
+}
+
+// с ключом на gateway пользователь — sub токена; чужой X-User-ID не подменяет его, а отклоняется
+func TestRouter_UserFromTokenNotHeader(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := auth.NewTokenVerifier(writePublicKey(t, key), "cwrk-auth", "cwrk", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rm := &identityRoom{}
+	router := transport.NewRouter(transport.Deps{Tokens: tokens, RoomClient: rm})
+	token := "Bearer " + signToken(t, key, accessClaims("5", ""))
+
+	for name, tc := range map[string]struct {
+		auth, uid string
+		want      int
+		wantUID   int64
+	}{
+		"token only":        {token, "", http.StatusOK, 5},
+		"matching header":   {token, "5", http.StatusOK, 5},
+		"forged header":     {token, "7", http.StatusUnauthorized, 0},
+		"header, bad token": {"Bearer forged", "7", http.StatusUnauthorized, 0},
+	} {
+		rm.calls = nil
+		req := httptest.NewRequest(http.MethodGet, "/rooms/r-1", nil)
+		req.Header.Set("Authorization", tc.auth)
+		if tc.uid != "" {
+			req.Header.Set("X-User-ID", tc.uid)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: want %d, got %d (%s)", name, tc.want, rec.Code, rec.Body.String())
+		}
+		if tc.wantUID == 0 && len(rm.calls) != 0 {
+			t.Errorf("%s: room-service must not be called, got %v", name, rm.calls)
+		}
+		if tc.wantUID != 0 && (len(rm.calls) != 1 || rm.calls[0] != tc.wantUID) {
+			t.Errorf("%s: want GetRoom as %d, got %v", name, tc.wantUID, rm.calls)
+		}
+	}
+}
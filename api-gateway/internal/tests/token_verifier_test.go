@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/authz/pkg/authz"
+
+	"github.com/golang-jwt/jwt"
+)
+
+func writePublicKey(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "public.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// accessClaims - access-токен платформы, как его выпускает auth-service
+func accessClaims(sub, scope string) authz.Claims {
+	now := time.Now()
+	return authz.Claims{
+		StandardClaims: jwt.StandardClaims{Subject: sub, Issuer: "cwrk-auth", Audience: "cwrk", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()},
+		TokenUse:       authz.TokenUseAccess,
+		Scope:          scope,
+	}
+}
+
+func signToken(t *testing.T, key *rsa.PrivateKey, c authz.Claims) string {
+	t.Helper()
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodRS256, c).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
+
+func TestTokenVerifier(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.NewTokenVerifier(writePublicKey(t, key), "cwrk-auth", "", 30*time.Second); err == nil {
+		t.Fatal("verifier without audience must not be built")
+	}
+	v, err := auth.NewTokenVerifier(writePublicKey(t, key), "cwrk-auth", "cwrk", 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	valid := accessClaims("42", "")
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "bearer "+signToken(t, key, valid))
+	if uid, ok := v.RequestUserID(req); !ok || uid != 42 {
+		t.Fatalf("valid token: uid=%d ok=%v", uid, ok)
+	}
+
+	// истёк, но в пределах clockSkew — ещё принимаем
+	skewed := valid
+	skewed.ExpiresAt = now.Add(-10 * time.Second).Unix()
+	if _, err := v.UserID(signToken(t, key, skewed)); err != nil {
+		t.Fatalf("token within clock skew: %v", err)
+	}
+
+	expired := valid
+	expired.ExpiresAt = now.Add(-time.Minute).Unix()
+	wrongIss := valid
+	wrongIss.Issuer = "someone"
+	idToken := valid
+	idToken.TokenUse = "id"
+	for name, tok := range map[string]string{
+		"expired":   signToken(t, key, expired),
+		"issuer":    signToken(t, key, wrongIss),
+		"id token":  signToken(t, key, idToken),
+		"other key": signToken(t, other, valid),
+		"garbage":   "not.a.token",
+	} {
+		if _, err := v.UserID(tok); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	req.Header.Del("Authorization")
+	if _, ok := v.RequestUserID(req); ok {
+		t.Fatal("no header must be anonymous")
+	}
+}
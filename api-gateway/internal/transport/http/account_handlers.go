@@ -0,0 +1,126 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	appexport "github.com/cwrk-planet/api-gateway/internal/app/export"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AccountHandlers — удаление аккаунта и выгрузка персональных данных.
+type AccountHandlers struct {
+	Auth    appauth.Client
+	Exports *appexport.Service
+}
+
+// POST /auth/account/delete {"password","mfaCode"} или {"passkeyCeremonyId","passkeyCredential"} — удаление после grace-периода
+func (h *AccountHandlers) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	var in appauth.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	in.MfaCode = strings.TrimSpace(in.MfaCode)
+	in.PasskeyCeremonyID = strings.TrimSpace(in.PasskeyCeremonyID)
+
+	out, err := h.Auth.DeleteAccount(ctx, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "delete account failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.JSON(w, http.StatusAccepted, map[string]any{"data": out})
+}
+
+// POST /auth/account/delete/cancel
+func (h *AccountHandlers) CancelAccountDeletion(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Auth.CancelAccountDeletion(ctx); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "cancel account deletion failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// POST /auth/account/export — запустить сборку архива
+func (h *AccountHandlers) StartExport(w http.ResponseWriter, r *http.Request) {
+	token, ok := accessToken(w, r)
+	if !ok {
+		return
+	}
+	job, err := h.Exports.Start(r.Context(), token)
+	if err != nil {
+		httputil.Error(r.Context(), w, errs.ToHTTP(err), "start export failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.JSON(w, http.StatusAccepted, map[string]any{"data": job})
+}
+
+// GET /auth/account/export/{id}
+func (h *AccountHandlers) GetExport(w http.ResponseWriter, r *http.Request) {
+	token, ok := accessToken(w, r)
+	if !ok {
+		return
+	}
+	job, err := h.Exports.Get(r.Context(), token, chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.Error(r.Context(), w, errs.ToHTTP(err), "get export failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, job)
+}
+
+// GET /auth/account/export/{id}/download — только с Authorization, ссылок не подписываем
+func (h *AccountHandlers) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	token, ok := accessToken(w, r)
+	if !ok {
+		return
+	}
+	id := chi.URLParam(r, "id")
+	body, err := h.Exports.Open(r.Context(), token, id)
+	if err != nil {
+		httputil.Error(r.Context(), w, errs.ToHTTP(err), "download export failed", map[string]any{"reason": err.Error()})
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "export-" + id + ".zip"}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, body); err != nil {
+		slog.WarnContext(r.Context(), "export download aborted", "err", err)
+	}
+}
+
+// accessToken — как bearerContext, но отдаёт сам токен. false - ответ с ошибкой уже записан
+func accessToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return "", false
+	}
+	return strings.TrimPrefix(auth, "Bearer "), true
+}
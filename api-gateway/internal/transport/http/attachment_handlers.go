@@ -0,0 +1,137 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	appattachment "github.com/cwrk-planet/api-gateway/internal/app/attachment"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+type AttachmentHandlers struct {
+	Attachments *appattachment.Service
+	MaxFileSize int64
+}
+
+// POST /rooms/{id}/attachments  (multipart/form-data, поле "file")
+func (h *AttachmentHandlers) Upload(w http.ResponseWriter, r *http.Request) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	uid, ok := userID64(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	roomID := chi.URLParam(r, "id")
+	if strings.TrimSpace(roomID) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "id is required", nil)
+		return
+	}
+
+	// запас на заголовки multipart; точный лимит файла проверяет сервис
+	r.Body = http.MaxBytesReader(w, r.Body, h.MaxFileSize+1<<20)
+	mr, err := r.MultipartReader()
+	if err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "multipart/form-data expected", nil)
+		return
+	}
+
+	// читаем поток без буферизации в память/temp-файлы
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			h.fail(w, r, err)
+			return
+		}
+		if part.FormName() != "file" {
+			_ = part.Close()
+			continue
+		}
+
+		out, err := h.Attachments.Upload(r.Context(), auth, uid, roomID, part.FileName(), part)
+		_ = part.Close()
+		if err != nil {
+			h.fail(w, r, err)
+			return
+		}
+
+		httputil.JSON(w, http.StatusCreated, map[string]any{"data": out})
+		return
+	}
+
+	httputil.Error(r.Context(), w, http.StatusBadRequest, "file is required", nil)
+}
+
+// GET /rooms/{id}/attachments/{aid}/url?variant=orig|thumb
+func (h *AttachmentHandlers) SignedURL(w http.ResponseWriter, r *http.Request) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	uid, ok := userID64(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	roomID, id := chi.URLParam(r, "id"), chi.URLParam(r, "aid")
+	if strings.TrimSpace(roomID) == "" || strings.TrimSpace(id) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "id is required", nil)
+		return
+	}
+
+	out, err := h.Attachments.SignURL(r.Context(), auth, uid, roomID, id, r.URL.Query().Get("variant"))
+	if err != nil {
+		httputil.Error(r.Context(), w, errs.ToHTTP(err), "sign attachment url failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// GET /attachments/{aid}?room=&uid=&v=&name=&exp=&sig=
+// Без Authorization: доступ даёт подпись (ссылку можно вставить в <img src>).
+func (h *AttachmentHandlers) Download(w http.ResponseWriter, r *http.Request) {
+	d, err := h.Attachments.Open(r.Context(), chi.URLParam(r, "aid"), r.URL.Query())
+	if err != nil {
+		httputil.Error(r.Context(), w, errs.ToHTTP(err), "download failed", nil)
+		return
+	}
+	defer d.Body.Close()
+
+	w.Header().Set("Content-Type", d.ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	// картинки показываем inline, остальное — только скачиванием (html/svg не исполняются)
+	disposition := "attachment"
+	if strings.HasPrefix(d.ContentType, "image/") && d.ContentType != "image/svg+xml" {
+		disposition = "inline"
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": d.FileName}))
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := io.Copy(w, d.Body); err != nil {
+		slog.WarnContext(r.Context(), "attachment download aborted", "err", err)
+	}
+}
+
+func (h *AttachmentHandlers) fail(w http.ResponseWriter, r *http.Request, err error) {
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		err = errs.ErrTooLarge
+	}
+	httputil.Error(r.Context(), w, errs.ToHTTP(err), "upload attachment failed", map[string]any{"reason": err.Error()})
+}
@@ -1,8 +1,10 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
@@ -54,6 +56,26 @@ func (h *AuthHandlers) Register(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, out)
 }
 
+// ChangePassword - после смены все сессии завершены, нужен новый вход
+func (h *AuthHandlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	var in appauth.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	if strings.TrimSpace(in.Email) == "" || in.CurrentPassword == "" || in.NewPassword == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "email, currentPassword and newPassword are required", nil)
+		return
+	}
+	if err := h.Auth.ChangePassword(r.Context(), in); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "password change failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]bool{"changed": true})
+}
+
 func (h *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 	var in appauth.RefreshRequest
 	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
@@ -76,14 +98,10 @@ func (h *AuthHandlers) Refresh(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *AuthHandlers) Me(w http.ResponseWriter, r *http.Request) {
-	// Достаём Bearer токен из заголовка Authorization и прокидываем в gRPC metadata.
-	authz := r.Header.Get("Authorization")
-	parts := strings.SplitN(authz, " ", 2)
-	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || strings.TrimSpace(parts[1]) == "" {
-		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+	ctx, ok := bearerContext(w, r)
+	if !ok {
 		return
 	}
-	ctx := appauth.WithBearer(r.Context(), strings.TrimSpace(parts[1]))
 
 	out, err := h.Auth.Me(ctx)
 	if err != nil {
@@ -93,3 +111,49 @@ func (h *AuthHandlers) Me(w http.ResponseWriter, r *http.Request) {
 	}
 	httputil.OK(w, out)
 }
+
+// ListSecurityEvents - свои входы, смены пароля, MFA и т.п., новые первыми; ?cursor= из nextCursor
+func (h *AuthHandlers) ListSecurityEvents(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	qs := r.URL.Query()
+	var cursor int64
+	if s := qs.Get("cursor"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid cursor", nil)
+			return
+		}
+		cursor = n
+	}
+	var limit int32
+	if s := qs.Get("limit"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil && n > 0 {
+			limit = int32(n)
+		}
+	}
+
+	out, err := h.Auth.ListSecurityEvents(ctx, cursor, limit)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list security events failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// bearerContext достаёт Bearer токен из заголовка Authorization и прокидывает в gRPC metadata.
+// false - ответ с ошибкой уже записан
+func bearerContext(w http.ResponseWriter, r *http.Request) (context.Context, bool) {
+	authz := r.Header.Get("Authorization")
+	parts := strings.SplitN(authz, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || strings.TrimSpace(parts[1]) == "" {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return nil, false
+	}
+
+	return appauth.WithBearer(r.Context(), strings.TrimSpace(parts[1])), true
+}
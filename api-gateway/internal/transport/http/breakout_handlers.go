@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// GET /rooms/{id}/breakouts — текущие группы и распределение ({id} — основная комната или группа).
+// Запуск, переводы и завершение — через WS (breakout_start/assign/join/end).
+func (h *RoomHandlers) GetBreakout(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.GetBreakout(r.Context(), auth, uid, id)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "get breakout failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
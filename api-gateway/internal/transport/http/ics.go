@@ -0,0 +1,64 @@
+package http
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+)
+
+const icsTime = "20060102T150405Z"
+
+// writeICS — минимальный VCALENDAR (RFC 5545) с событием на каждое занятие.
+func writeICS(w io.Writer, calName string, items []approom.SessionItem, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(s string) { _, _ = bw.WriteString(foldICS(s)) }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//cwrkPlanet//rooms//RU")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICS(calName))
+	for _, it := range items {
+		// UID стабилен между выгрузками: календарь обновит событие, а не задвоит
+		line("BEGIN:VEVENT")
+		line("UID:" + it.RoomID + "-" + strconv.FormatInt(it.StartsAt.Unix(), 10) + "@cwrk-planet")
+		line("DTSTAMP:" + now.UTC().Format(icsTime))
+		line("DTSTART:" + it.StartsAt.UTC().Format(icsTime))
+		line("DTEND:" + it.EndsAt.UTC().Format(icsTime))
+		line("SUMMARY:" + escapeICS(it.RoomName))
+		line("X-CWRK-ROOM-ID:" + it.RoomID)
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`)
+
+func escapeICS(s string) string {
+	return icsEscaper.Replace(s)
+}
+
+// foldICS — строки длиннее 75 октетов переносятся (CRLF + пробел), не разрывая UTF-8.
+func foldICS(s string) string {
+	const limit = 75
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > limit {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// PUT /rooms/{id}/lobby {"enabled":true} — включить/выключить лобби (модераторы)
+func (h *RoomHandlers) SetLobby(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.SetLobbyRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	if err := h.Room.SetLobby(r.Context(), auth, uid, id, in.Enabled); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "set lobby failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]any{"enabled": in.Enabled})
+}
+
+// GET /rooms/{id}/lobby — настройки и очередь (модераторы)
+func (h *RoomHandlers) GetLobby(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.GetLobby(r.Context(), auth, uid, id)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "get lobby failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// POST /rooms/{id}/lobby/admit {"user_id":"7"} | {"all":true}
+func (h *RoomHandlers) AdmitLobby(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.AdmitLobbyRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+		return
+	}
+	if !in.All && strings.TrimSpace(in.UserID) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "user_id or all is required", nil)
+		return
+	}
+
+	out, err := h.Room.AdmitLobby(r.Context(), auth, uid, id, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "admit failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// VerifyMfa — второй шаг входа: mfaToken из /auth/login + код.
+func (h *AuthHandlers) VerifyMfa(w http.ResponseWriter, r *http.Request) {
+	var in appauth.VerifyMfaRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	in.MfaToken = strings.TrimSpace(in.MfaToken)
+	in.Code = strings.TrimSpace(in.Code)
+	if in.MfaToken == "" || in.Code == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "mfaToken and code are required", nil)
+		return
+	}
+	out, err := h.Auth.VerifyMfa(r.Context(), in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "mfa verify failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) EnrollTotp(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.EnrollTotp(ctx)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "totp enroll failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) ConfirmTotp(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	code, ok := decodeTotpCode(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.ConfirmTotp(ctx, code)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "totp confirm failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) DisableTotp(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	code, ok := decodeTotpCode(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Auth.DisableTotp(ctx, code); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "totp disable failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]bool{"disabled": true})
+}
+
+func decodeTotpCode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var in appauth.TotpCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return "", false
+	}
+	code := strings.TrimSpace(in.Code)
+	if code == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "code is required", nil)
+		return "", false
+	}
+
+	return code, true
+}
@@ -0,0 +1,131 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ListOidcProviders — провайдеры для кнопок «Войти через ...».
+func (h *AuthHandlers) ListOidcProviders(w http.ResponseWriter, r *http.Request) {
+	out, err := h.Auth.ListOidcProviders(r.Context())
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list providers failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string][]string{"providers": out})
+}
+
+func (h *AuthHandlers) BeginOidcLogin(w http.ResponseWriter, r *http.Request) {
+	out, err := h.Auth.BeginOidcLogin(r.Context(), chi.URLParam(r, "provider"))
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "oidc login failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// FinishOidcLogin — code и state из redirect провайдера; ответ как у /auth/login.
+func (h *AuthHandlers) FinishOidcLogin(w http.ResponseWriter, r *http.Request) {
+	in, ok := decodeOidcFinish(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.FinishOidcLogin(r.Context(), chi.URLParam(r, "provider"), in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "oidc login failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) BeginOidcLink(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.BeginOidcLink(ctx, chi.URLParam(r, "provider"))
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "oidc link failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) FinishOidcLink(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	in, ok := decodeOidcFinish(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.FinishOidcLink(ctx, chi.URLParam(r, "provider"), in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "oidc link failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.ListIdentities(ctx)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list identities failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) UnlinkIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Auth.UnlinkIdentity(ctx, chi.URLParam(r, "provider")); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "unlink identity failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]bool{"unlinked": true})
+}
+
+func decodeOidcFinish(w http.ResponseWriter, r *http.Request) (appauth.OidcFinishRequest, bool) {
+	var in appauth.OidcFinishRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return in, false
+	}
+	in.State = strings.TrimSpace(in.State)
+	in.Code = strings.TrimSpace(in.Code)
+	if in.State == "" || in.Code == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "state and code are required", nil)
+		return in, false
+	}
+
+	return in, true
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// BeginPasskeyRegistration — options для navigator.credentials.create().
+func (h *AuthHandlers) BeginPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.BeginPasskeyRegistration(ctx)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "passkey registration failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) FinishPasskeyRegistration(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	var in appauth.FinishPasskeyRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	in.CeremonyID = strings.TrimSpace(in.CeremonyID)
+	if in.CeremonyID == "" || len(in.Credential) == 0 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "ceremonyId and credential are required", nil)
+		return
+	}
+	out, err := h.Auth.FinishPasskeyRegistration(ctx, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "passkey registration failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.JSON(w, http.StatusCreated, map[string]any{"data": out})
+}
+
+// BeginPasskeyLogin — options для navigator.credentials.get(), без email (discoverable credentials).
+func (h *AuthHandlers) BeginPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	out, err := h.Auth.BeginPasskeyLogin(r.Context())
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "passkey login failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// BeginPasskeyReauth — options для navigator.credentials.get() перед удалением аккаунта, только свои passkeys.
+func (h *AuthHandlers) BeginPasskeyReauth(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.BeginPasskeyReauth(ctx)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "passkey reauth failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) FinishPasskeyLogin(w http.ResponseWriter, r *http.Request) {
+	var in appauth.FinishPasskeyLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	in.CeremonyID = strings.TrimSpace(in.CeremonyID)
+	if in.CeremonyID == "" || len(in.Credential) == 0 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "ceremonyId and credential are required", nil)
+		return
+	}
+	out, err := h.Auth.FinishPasskeyLogin(r.Context(), in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "passkey login failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) ListPasskeys(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	out, err := h.Auth.ListPasskeys(ctx)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list passkeys failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) RenamePasskey(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	id, ok := passkeyID(w, r)
+	if !ok {
+		return
+	}
+	var in appauth.RenamePasskeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid JSON", nil)
+		return
+	}
+	out, err := h.Auth.RenamePasskey(ctx, id, in.Name)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "rename passkey failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+func (h *AuthHandlers) RevokePasskey(w http.ResponseWriter, r *http.Request) {
+	ctx, ok := bearerContext(w, r)
+	if !ok {
+		return
+	}
+	id, ok := passkeyID(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Auth.RevokePasskey(ctx, id); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "revoke passkey failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]bool{"revoked": true})
+}
+
+func passkeyID(w http.ResponseWriter, r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid passkey id", nil)
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,89 @@
+package http
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// GET /rooms/{id}/polls/export?format=json|csv — результаты опросов (только модераторам)
+func (h *RoomHandlers) ExportPolls(w http.ResponseWriter, r *http.Request) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	uid, ok := userID64(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	if strings.TrimSpace(id) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "id is required", nil)
+		return
+	}
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format != "" && format != "json" && format != "csv" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "format must be json or csv", nil)
+		return
+	}
+
+	out, err := h.Room.ExportPolls(r.Context(), auth, uid, id)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "export polls failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	if format != "csv" {
+		httputil.OK(w, out)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="polls-`+id+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	_ = writePollsCSV(w, out)
+}
+
+// writePollsCSV — одна строка на голос; опросы без голосов идут одной строкой с пустым user_id.
+func writePollsCSV(w http.ResponseWriter, in approom.PollsExportResponse) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"poll_id", "question", "quiz", "status", "user_id", "options", "correct", "voted_at"})
+
+	for _, p := range in.Items {
+		texts := make(map[int]string, len(p.Options))
+		for _, o := range p.Options {
+			texts[o.Idx] = o.Text
+		}
+		base := []string{p.ID, p.Question, strconv.FormatBool(p.Quiz), p.Status}
+
+		if len(p.Votes) == 0 {
+			_ = cw.Write(append(base, "", "", "", ""))
+			continue
+		}
+		for _, v := range p.Votes {
+			picked := make([]string, 0, len(v.Options))
+			for _, i := range v.Options {
+				picked = append(picked, texts[i])
+			}
+			correct := ""
+			if p.Quiz {
+				correct = strconv.FormatBool(v.Correct)
+			}
+			_ = cw.Write(append(base, v.UserID, strings.Join(picked, "; "), correct, v.VotedAt.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
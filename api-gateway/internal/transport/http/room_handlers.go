@@ -1,6 +1,7 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -11,10 +12,12 @@ import (
 	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
 	"github.com/cwrk-planet/api-gateway/pkg/errs"
 	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+	"github.com/cwrk-planet/authz/pkg/authz"
 )
 
 type RoomHandlers struct {
-	Room approom.Client
+	Room          approom.Client
+	PublicBaseURL string // внешний адрес gateway — для ссылок на ленты .ics
 }
 
 func bearer(r *http.Request) (string, bool) {
@@ -27,17 +30,39 @@ func bearer(r *http.Request) (string, bool) {
 	return "Bearer " + strings.TrimSpace(parts[1]), true
 }
 
+type tokenIdentityKey struct{}
+
+// tokenIdentity — токены проверяет сам gateway: дальше пользователь берется только из Principal
+func tokenIdentity(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), tokenIdentityKey{}, true)))
+	})
+}
+
+// userID64 — кто делает запрос. Если gateway проверяет токены — uid из Principal, а X-User-ID,
+// если передан, обязан с ним совпасть: чужой заголовок — отказ. Без ключа — заголовок как есть, решит room-service
 func userID64(r *http.Request) (int64, bool) {
-	s := strings.TrimSpace(r.Header.Get("X-User-ID"))
-	if s == "" {
-		return 0, false
+	var (
+		hdr    int64
+		hasHdr bool
+	)
+	if s := strings.TrimSpace(r.Header.Get("X-User-ID")); s != "" {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return 0, false
+		}
+		hdr, hasHdr = id, true
 	}
-	id, err := strconv.ParseInt(s, 10, 64)
-	if err != nil {
-		return 0, false
+
+	if verified, _ := r.Context().Value(tokenIdentityKey{}).(bool); verified {
+		p, ok := authz.FromContext(r.Context())
+		if !ok || (hasHdr && hdr != p.UserID) {
+			return 0, false
+		}
+		return p.UserID, true
 	}
 
-	return id, true
+	return hdr, hasHdr
 }
 
 // POST /rooms
@@ -51,11 +76,7 @@ func (h *RoomHandlers) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		httputil.Error(r.Context(), w, http.StatusBadRequest, "name is required", nil)
 		return
 	}
-	// todo: перенести в конфиг
-	if in.Max != 0 && (in.Max < 1 || in.Max > 10) {
-		httputil.Error(r.Context(), w, http.StatusBadRequest, "max must be in [1..10]", nil)
-		return
-	}
+	// max проверяет room-service: предел зависит от тарифа владельца (400 с reason)
 	auth, ok := bearer(r)
 	if !ok {
 		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
@@ -77,7 +98,7 @@ func (h *RoomHandlers) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, out)
 }
 
-// GET /rooms?limit=&cursor=
+// GET /rooms?limit=&cursor=&q=&tag=&lang=&free=true&live=true&sort=created|activity
 func (h *RoomHandlers) ListRooms(w http.ResponseWriter, r *http.Request) {
 	auth, ok := bearer(r)
 	if !ok {
@@ -90,15 +111,23 @@ func (h *RoomHandlers) ListRooms(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var limit int64
-	if s := r.URL.Query().Get("limit"); s != "" {
+	qs := r.URL.Query()
+	q := approom.ListRoomsQuery{
+		Cursor:    qs.Get("cursor"),
+		Query:     qs.Get("q"),
+		Tag:       qs.Get("tag"),
+		Language:  qs.Get("lang"),
+		FreeSeats: qs.Get("free") == "true",
+		Live:      qs.Get("live") == "true",
+		Sort:      qs.Get("sort"),
+	}
+	if s := qs.Get("limit"); s != "" {
 		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
-			limit = n
+			q.Limit = n
 		}
 	}
-	cursor := r.URL.Query().Get("cursor")
 
-	out, err := h.Room.ListRooms(r.Context(), auth, uid, limit, cursor)
+	out, err := h.Room.ListRooms(r.Context(), auth, uid, q)
 	if err != nil {
 		status := errs.ToHTTP(err)
 		httputil.Error(r.Context(), w, status, "list rooms failed", map[string]any{"reason": err.Error()})
@@ -136,6 +165,28 @@ func (h *RoomHandlers) GetRoom(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, out)
 }
 
+// PATCH /rooms/{id} — название, описание, тема, язык, теги (владелец и модераторы)
+func (h *RoomHandlers) UpdateRoom(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.UpdateRoomRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	out, err := h.Room.UpdateRoom(r.Context(), auth, uid, id, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "update room failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
 // POST /rooms/{id}/join
 func (h *RoomHandlers) Join(w http.ResponseWriter, r *http.Request) {
 	auth, ok := bearer(r)
@@ -219,6 +270,35 @@ func (h *RoomHandlers) Participants(w http.ResponseWriter, r *http.Request) {
 	httputil.OK(w, out)
 }
 
+// PUT /rooms/{id}/moderators/{uid}    — назначить модератора (только владелец)
+// DELETE /rooms/{id}/moderators/{uid} — снять
+func (h *RoomHandlers) SetModerator(w http.ResponseWriter, r *http.Request) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	uid, ok := userID64(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	id, target := chi.URLParam(r, "id"), chi.URLParam(r, "uid")
+	if strings.TrimSpace(id) == "" || strings.TrimSpace(target) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "id and uid are required", nil)
+		return
+	}
+
+	grant := r.Method == http.MethodPut
+	if err := h.Room.SetModerator(r.Context(), auth, uid, id, target, grant); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "set moderator failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]any{"user_id": target, "moderator": grant})
+}
+
 // GET /rooms/{id}/chat?after=&limit=
 func (h *RoomHandlers) ChatHistory(w http.ResponseWriter, r *http.Request) {
 	auth, ok := bearer(r)
@@ -2,11 +2,16 @@ package http
 
 import (
 	"net/http"
+	"net/netip"
 	"time"
 
+	appattachment "github.com/cwrk-planet/api-gateway/internal/app/attachment"
 	appauth "github.com/cwrk-planet/api-gateway/internal/app/auth"
+	appexport "github.com/cwrk-planet/api-gateway/internal/app/export"
 	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/internal/ratelimit"
 	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+	"github.com/cwrk-planet/authz/pkg/authz"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -16,27 +21,47 @@ import (
 type Deps struct {
 	AuthClient appauth.Client
 	RoomClient approom.Client
+
+	Attachments       *appattachment.Service
+	AttachmentMaxSize int64
+
+	Exports *appexport.Service
+
+	RateLimit *ratelimit.Limiter     // nil — без лимитов
+	Tokens    *appauth.TokenVerifier // nil — scopes проверяет только room-service
+
+	TrustedProxies []netip.Prefix // чьим X-Forwarded-For / X-Real-IP верить; пусто — ничьим
+
+	PublicBaseURL string // внешний адрес gateway для ссылок (ленты .ics)
 }
 
 func NewRouter(d Deps) http.Handler {
 	r := chi.NewRouter()
 
-	r.Use(middleware.RealIP)
+	r.Use(httputil.MiddlewareRealIP(d.TrustedProxies))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Compress(5))
 	r.Use(middleware.Timeout(60 * time.Second))
 	r.Use(httputil.MiddlewareRequestID)
+	r.Use(httputil.MiddlewareClientMeta)
 	r.Use(httputil.MiddlewareLogging)
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://localhost:5173", "http://127.0.0.1:5173"},
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "X-User-ID"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
+	if d.RateLimit != nil {
+		r.Use(d.RateLimit.Middleware)
+	}
+	if d.Tokens != nil {
+		r.Use(d.Tokens.Authz().Middleware, tokenIdentity)
+	}
+
 	// health
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		httputil.OK(w, map[string]string{"status": "ok"})
@@ -44,27 +69,110 @@ func NewRouter(d Deps) http.Handler {
 
 	// Auth endpoints
 	ah := &AuthHandlers{Auth: d.AuthClient}
+	ach := &AccountHandlers{Auth: d.AuthClient, Exports: d.Exports}
 	r.Route("/auth", func(r chi.Router) {
 		r.Post("/login", ah.Login)
 		r.Post("/register", ah.Register)
 		r.Post("/refresh", ah.Refresh)
+		r.Post("/password/change", ah.ChangePassword)
 		r.Get("/me", ah.Me)
+		r.Get("/security-events", ah.ListSecurityEvents)
+
+		r.Post("/mfa/verify", ah.VerifyMfa)
+		r.Post("/mfa/totp/enroll", ah.EnrollTotp)
+		r.Post("/mfa/totp/confirm", ah.ConfirmTotp)
+		r.Post("/mfa/totp/disable", ah.DisableTotp)
+
+		r.Post("/passkeys/registration/begin", ah.BeginPasskeyRegistration)
+		r.Post("/passkeys/registration/finish", ah.FinishPasskeyRegistration)
+		r.Post("/passkeys/login/begin", ah.BeginPasskeyLogin)
+		r.Post("/passkeys/login/finish", ah.FinishPasskeyLogin)
+		r.Post("/passkeys/reauth/begin", ah.BeginPasskeyReauth)
+		r.Get("/passkeys", ah.ListPasskeys)
+		r.Patch("/passkeys/{id}", ah.RenamePasskey)
+		r.Delete("/passkeys/{id}", ah.RevokePasskey)
+
+		r.Get("/oidc/providers", ah.ListOidcProviders)
+		r.Post("/oidc/{provider}/login/begin", ah.BeginOidcLogin)
+		r.Post("/oidc/{provider}/login/finish", ah.FinishOidcLogin)
+		r.Post("/oidc/{provider}/link/begin", ah.BeginOidcLink)
+		r.Post("/oidc/{provider}/link/finish", ah.FinishOidcLink)
+		r.Get("/identities", ah.ListIdentities)
+		r.Delete("/identities/{provider}", ah.UnlinkIdentity)
+
+		r.Post("/account/delete", ach.DeleteAccount)
+		r.Post("/account/delete/cancel", ach.CancelAccountDeletion)
+		r.Post("/account/export", ach.StartExport)
+		r.Get("/account/export/{id}", ach.GetExport)
+		r.Get("/account/export/{id}/download", ach.DownloadExport)
 	})
 
+	ath := &AttachmentHandlers{Attachments: d.Attachments, MaxFileSize: d.AttachmentMaxSize}
+
 	// Room endpoints
-	rh := &RoomHandlers{Room: d.RoomClient}
+	rh := &RoomHandlers{Room: d.RoomClient, PublicBaseURL: d.PublicBaseURL}
 	r.Route("/rooms", func(rt chi.Router) {
-		rt.Post("/", rh.CreateRoom)
+		rt.With(requireScopes(d.Tokens, authz.ScopeRoomsCreate)).Post("/", rh.CreateRoom)
 		rt.Get("/", rh.ListRooms)
 
 		rt.Route("/{id}", func(rr chi.Router) {
 			rr.Get("/", rh.GetRoom)
+			rr.Patch("/", rh.UpdateRoom)
 			rr.Post("/join", rh.Join)
 			rr.Post("/leave", rh.Leave)
 			rr.Get("/participants", rh.Participants)
 			rr.Get("/chat", rh.ChatHistory)
+			rr.Put("/moderators/{uid}", rh.SetModerator)
+			rr.Delete("/moderators/{uid}", rh.SetModerator)
+			rr.Get("/polls/export", rh.ExportPolls)
+			rr.Put("/schedule", rh.SetSchedule)
+			rr.Get("/schedule", rh.GetSchedule)
+			rr.Delete("/schedule", rh.DeleteSchedule)
+			rr.Get("/sessions", rh.ListSessions)
+			rr.Put("/lobby", rh.SetLobby)
+			rr.Get("/lobby", rh.GetLobby)
+			rr.Post("/lobby/admit", rh.AdmitLobby)
+			rr.Get("/breakouts", rh.GetBreakout)
+
+			rr.Post("/attachments", ath.Upload)
+			rr.Get("/attachments/{aid}/url", ath.SignedURL)
 		})
 	})
 
+	// вебхуки владельца
+	r.Route("/webhooks", func(rt chi.Router) {
+		rt.Post("/", rh.CreateWebhook)
+		rt.Get("/", rh.ListWebhooks)
+		rt.Delete("/{id}", rh.DeleteWebhook)
+		rt.Get("/{id}/deliveries", rh.ListWebhookDeliveries)
+		rt.Post("/deliveries/{id}/redeliver", rh.RedeliverWebhook)
+	})
+
+	// расписание по всем комнатам
+	r.Get("/sessions", rh.ListSessions)
+
+	// ленты .ics: ссылки выдаются по bearer, сама лента открывается токеном из ссылки
+	r.Route("/calendar-feeds", func(rt chi.Router) {
+		rt.Post("/", rh.CreateCalendarFeed)
+		rt.Get("/", rh.ListCalendarFeeds)
+		rt.Delete("/{id}", rh.DeleteCalendarFeed)
+	})
+	r.Get("/calendar/{token}.ics", rh.SessionsICS)
+
+	// скачивание по подписанной ссылке
+	r.Get("/attachments/{aid}", ath.Download)
+
 	return r
 }
+
+// requireScopes — без проверки токенов на gateway пропускаем, решит room-service
+func requireScopes(tokens *appauth.TokenVerifier, scopes ...string) func(http.Handler) http.Handler {
+	if tokens == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return authz.RequireScopes(denyScopes, scopes...)
+}
+
+func denyScopes(w http.ResponseWriter, r *http.Request, status int, err error) {
+	httputil.Error(r.Context(), w, status, err.Error(), nil)
+}
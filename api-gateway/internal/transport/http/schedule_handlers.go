@@ -0,0 +1,258 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// окно .ics-ленты: немного прошлого, чтобы календарь не терял недавние занятия.
+// Вместе — не больше окна выборки room-service (366 дней)
+const (
+	icsPast   = 30 * 24 * time.Hour
+	icsFuture = 330 * 24 * time.Hour
+)
+
+// PUT /rooms/{id}/schedule — задать расписание (модераторы)
+func (h *RoomHandlers) SetSchedule(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.SetScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+		return
+	}
+	if in.StartsAt.IsZero() || in.DurationSec <= 0 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "starts_at and duration_sec are required", nil)
+		return
+	}
+
+	out, err := h.Room.SetSchedule(r.Context(), auth, uid, id, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "set schedule failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// GET /rooms/{id}/schedule
+func (h *RoomHandlers) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.GetSchedule(r.Context(), auth, uid, id)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "get schedule failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// DELETE /rooms/{id}/schedule — снять расписание, комната открыта всегда
+func (h *RoomHandlers) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Room.DeleteSchedule(r.Context(), auth, uid, id); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "delete schedule failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]string{"status": "deleted"})
+}
+
+// GET /rooms/{id}/sessions?tz=&from=&to=&limit= — ближайшие занятия комнаты
+// GET /sessions?tz=&from=&to=&limit=            — по своим комнатам (участник, владелец, модератор)
+// Время отдаётся в часовом поясе tz (IANA, по умолчанию UTC).
+func (h *RoomHandlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	auth, ok := bearer(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	uid, ok := userID64(r)
+	if !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	q := r.URL.Query()
+
+	tz := strings.TrimSpace(q.Get("tz"))
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid tz", map[string]any{"reason": err.Error()})
+		return
+	}
+	from, ok1 := parseTimeParam(q.Get("from"))
+	to, ok2 := parseTimeParam(q.Get("to"))
+	if !ok1 || !ok2 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "from/to must be RFC3339", nil)
+		return
+	}
+	var limit int32
+	if s := q.Get("limit"); s != "" {
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			limit = int32(n)
+		}
+	}
+
+	items, err := h.Room.ListSessions(r.Context(), auth, uid, chi.URLParam(r, "id"), from, to, limit)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list sessions failed", map[string]any{"reason": err.Error()})
+		return
+	}
+	for i := range items {
+		items[i].StartsAt = items[i].StartsAt.In(loc)
+		items[i].EndsAt = items[i].EndsAt.In(loc)
+	}
+
+	httputil.OK(w, approom.SessionsResponse{Timezone: loc.String(), Items: items})
+}
+
+// POST /calendar-feeds {"room_id":"..."} — ссылка на ленту .ics для календаря; room_id пустой — все свои комнаты.
+// Календарь не шлёт Authorization, поэтому доступ — по токену в ссылке; token и url отдаются только здесь.
+func (h *RoomHandlers) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	auth, uid, ok := h.userRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.CreateCalendarFeedRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+			return
+		}
+	}
+
+	out, err := h.Room.CreateCalendarFeed(r.Context(), auth, uid, strings.TrimSpace(in.RoomID))
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "create calendar feed failed", map[string]any{"reason": err.Error()})
+		return
+	}
+	out.URL = strings.TrimRight(h.PublicBaseURL, "/") + "/calendar/" + url.PathEscape(out.Token) + ".ics"
+
+	httputil.OK(w, out)
+}
+
+// GET /calendar-feeds — свои ленты, без токенов
+func (h *RoomHandlers) ListCalendarFeeds(w http.ResponseWriter, r *http.Request) {
+	auth, uid, ok := h.userRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.ListCalendarFeeds(r.Context(), auth, uid)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list calendar feeds failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// DELETE /calendar-feeds/{id} — отозвать ссылку
+func (h *RoomHandlers) DeleteCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	auth, uid, ok := h.userRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Room.DeleteCalendarFeed(r.Context(), auth, uid, chi.URLParam(r, "id")); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "delete calendar feed failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]string{"status": "deleted"})
+}
+
+// GET /calendar/{token}.ics — лента для календаря, без bearer: пользователь и комната — из ленты по токену.
+// Повторы разворачиваются на сервере (в часовом поясе расписания) в отдельные
+// события в UTC: так не нужен VTIMEZONE, а календарь при обновлении ленты видит свежее окно.
+func (h *RoomHandlers) SessionsICS(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	if strings.TrimSpace(token) == "" {
+		httputil.Error(r.Context(), w, http.StatusNotFound, "calendar feed not found", nil)
+		return
+	}
+
+	now := time.Now()
+	items, err := h.Room.FeedSessions(r.Context(), token, now.Add(-icsPast), now.Add(icsFuture))
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "export sessions failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	name := "cwrkPlanet"
+	if len(items) > 0 && allSameRoom(items) {
+		name = items[0].RoomName
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="sessions.ics"`)
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.WriteHeader(http.StatusOK)
+	_ = writeICS(w, name, items, now)
+}
+
+func allSameRoom(items []approom.SessionItem) bool {
+	for _, it := range items[1:] {
+		if it.RoomID != items[0].RoomID {
+			return false
+		}
+	}
+	return true
+}
+
+// roomRequest — общий разбор bearer / X-User-ID / {id}; при ошибке ответ уже отправлен.
+func (h *RoomHandlers) roomRequest(w http.ResponseWriter, r *http.Request) (auth string, uid int64, id string, ok bool) {
+	if auth, ok = bearer(r); !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	if uid, ok = userID64(r); !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	if id = chi.URLParam(r, "id"); strings.TrimSpace(id) == "" {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "id is required", nil)
+		return "", 0, "", false
+	}
+	return auth, uid, id, true
+}
+
+// parseTimeParam — пустая строка допустима (значение по умолчанию на стороне room-service).
+func parseTimeParam(s string) (time.Time, bool) {
+	if s = strings.TrimSpace(s); s == "" {
+		return time.Time{}, true
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	return t, err == nil
+}
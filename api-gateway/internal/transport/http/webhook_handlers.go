@@ -0,0 +1,129 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	approom "github.com/cwrk-planet/api-gateway/internal/app/room"
+	"github.com/cwrk-planet/api-gateway/pkg/errs"
+	"github.com/cwrk-planet/api-gateway/pkg/httputil"
+)
+
+// POST /webhooks {"url":"https://...","events":["room.created","chat.message"]}
+// secret в ответе — единственный раз, им проверяется X-Webhook-Signature.
+func (h *RoomHandlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	auth, uid, ok := h.userRequest(w, r)
+	if !ok {
+		return
+	}
+	var in approom.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid json", map[string]any{"reason": err.Error()})
+		return
+	}
+	if strings.TrimSpace(in.URL) == "" || len(in.Events) == 0 {
+		httputil.Error(r.Context(), w, http.StatusBadRequest, "url and events are required", nil)
+		return
+	}
+
+	out, err := h.Room.CreateWebhook(r.Context(), auth, uid, in)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "create webhook failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// GET /webhooks
+func (h *RoomHandlers) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	auth, uid, ok := h.userRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.ListWebhooks(r.Context(), auth, uid)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list webhooks failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// DELETE /webhooks/{id}
+func (h *RoomHandlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Room.DeleteWebhook(r.Context(), auth, uid, id); err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "delete webhook failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, map[string]any{"deleted": true})
+}
+
+// GET /webhooks/{id}/deliveries?status=pending|delivered|dead&limit=&cursor=
+func (h *RoomHandlers) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+	q := r.URL.Query()
+	var limit int64
+	if s := q.Get("limit"); s != "" {
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < 0 {
+			httputil.Error(r.Context(), w, http.StatusBadRequest, "invalid limit", nil)
+			return
+		}
+		limit = n
+	}
+
+	out, err := h.Room.ListWebhookDeliveries(r.Context(), auth, uid, id, q.Get("status"), q.Get("cursor"), limit)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "list deliveries failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// POST /webhooks/deliveries/{id}/redeliver — в том числе dead, попытки считаются заново.
+func (h *RoomHandlers) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	auth, uid, id, ok := h.roomRequest(w, r)
+	if !ok {
+		return
+	}
+
+	out, err := h.Room.RedeliverWebhook(r.Context(), auth, uid, id)
+	if err != nil {
+		status := errs.ToHTTP(err)
+		httputil.Error(r.Context(), w, status, "redeliver failed", map[string]any{"reason": err.Error()})
+		return
+	}
+
+	httputil.OK(w, out)
+}
+
+// userRequest — как roomRequest, но без {id}.
+func (h *RoomHandlers) userRequest(w http.ResponseWriter, r *http.Request) (auth string, uid int64, ok bool) {
+	if auth, ok = bearer(r); !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid Authorization header", nil)
+		return
+	}
+	if uid, ok = userID64(r); !ok {
+		httputil.Error(r.Context(), w, http.StatusUnauthorized, "missing or invalid X-User-ID header", nil)
+		return
+	}
+	return auth, uid, true
+}
@@ -9,6 +9,13 @@ var (
 	ErrInvalidInput = errors.New("invalid input")
 	ErrUnauthorized = errors.New("unauthorized")
 	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+
+	ErrTooLarge         = errors.New("payload too large")
+	ErrUnsupportedMedia = errors.New("unsupported media type")
+	ErrQuotaExceeded    = errors.New("quota exceeded")
+	ErrTooManyRequests  = errors.New("too many requests")
 
 	ErrUpstream    = errors.New("upstream error")
 	ErrUnavailable = errors.New("service unavailable")
@@ -22,6 +29,16 @@ func ToHTTP(err error) int {
 		return http.StatusUnauthorized
 	case errors.Is(err, ErrForbidden):
 		return http.StatusForbidden
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, ErrTooLarge), errors.Is(err, ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err, ErrUnsupportedMedia):
+		return http.StatusUnsupportedMediaType
 	case errors.Is(err, ErrUnavailable):
 		return http.StatusServiceUnavailable
 	case errors.Is(err, ErrUpstream):
@@ -0,0 +1,42 @@
+package errs
+
+import (
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FromGRPC переводит gRPC-статус апстрима в наши sentinel-ошибки,
+// чтобы ToHTTP отдал осмысленный HTTP-код, а не всегда 502.
+func FromGRPC(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+
+	var target error
+	switch st.Code() {
+	case codes.InvalidArgument, codes.OutOfRange:
+		target = ErrInvalidInput
+	case codes.Unauthenticated:
+		target = ErrUnauthorized
+	case codes.PermissionDenied:
+		target = ErrForbidden
+	case codes.NotFound:
+		target = ErrNotFound
+	case codes.AlreadyExists, codes.FailedPrecondition, codes.Aborted:
+		target = ErrConflict
+	case codes.ResourceExhausted:
+		target = ErrQuotaExceeded
+	case codes.Unavailable, codes.DeadlineExceeded:
+		target = ErrUnavailable
+	default:
+		target = ErrUpstream
+	}
+
+	return fmt.Errorf("%w: %s", target, st.Message())
+}
@@ -0,0 +1,35 @@
+package httputil
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const ctxKeyClient ctxKey = "client"
+
+// ClientMeta — адрес и User-Agent клиента; уходят в auth-service для журнала безопасности
+type ClientMeta struct {
+	IP        string
+	UserAgent string
+}
+
+// MiddlewareClientMeta — ставить после MiddlewareRealIP, тогда RemoteAddr уже адрес клиента.
+func MiddlewareClientMeta(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := strings.TrimSpace(r.RemoteAddr)
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+
+		ctx := context.WithValue(r.Context(), ctxKeyClient, ClientMeta{IP: ip, UserAgent: r.UserAgent()})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ClientFromContext — достать ClientMeta из контекста.
+func ClientFromContext(ctx context.Context) (ClientMeta, bool) {
+	v, ok := ctx.Value(ctxKeyClient).(ClientMeta)
+	return v, ok
+}
@@ -58,7 +58,10 @@ func (w *logResponseWriter) Write(b []byte) (int, error) {
 	if w.status == 0 {
 		w.status = http.StatusOK
 	}
-	w.body.Write(b)
+	// файлы (вложения) в лог не тащим
+	if strings.Contains(strings.ToLower(w.Header().Get("Content-Type")), "json") {
+		w.body.Write(b)
+	}
 	n, err := w.ResponseWriter.Write(b)
 	w.bytes += n
 
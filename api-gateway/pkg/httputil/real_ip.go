@@ -0,0 +1,94 @@
+package httputil
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// ParseTrustedProxies — адреса и подсети ("10.0.0.0/8", "127.0.0.1") доверенных прокси.
+func ParseTrustedProxies(list []string) ([]netip.Prefix, error) {
+	out := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if p, err := netip.ParsePrefix(s); err == nil {
+			out = append(out, p.Masked())
+			continue
+		}
+		a, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: not an IP or CIDR", s)
+		}
+		a = a.Unmap()
+		out = append(out, netip.PrefixFrom(a, a.BitLen()))
+	}
+	return out, nil
+}
+
+// MiddlewareRealIP — вместо middleware.RealIP: True-Client-IP, X-Real-IP и X-Forwarded-For
+// читаем, только если запрос пришёл напрямую от доверенного прокси, иначе клиент подставит
+// любой адрес (и обойдёт лимиты по IP). В X-Forwarded-For берём самый правый адрес,
+// который не из доверенных: левее него значения пишет уже сам клиент.
+// trusted пустой — заголовкам не верим, RemoteAddr не трогаем.
+func MiddlewareRealIP(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	isTrusted := func(a netip.Addr) bool {
+		for _, p := range trusted {
+			if p.Contains(a) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if peer, ok := parseIP(r.RemoteAddr); ok && isTrusted(peer) {
+				if ip, ok := forwardedFor(r.Header, isTrusted); ok {
+					r.RemoteAddr = ip.String()
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func forwardedFor(h http.Header, isTrusted func(netip.Addr) bool) (netip.Addr, bool) {
+	for _, name := range []string{"True-Client-IP", "X-Real-IP"} {
+		if ip, ok := parseIP(h.Get(name)); ok {
+			return ip, true
+		}
+	}
+
+	var hops []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(v, ",")...)
+	}
+	var last netip.Addr
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip, ok := parseIP(hops[i])
+		if !ok {
+			break // мусор в цепочке — дальше влево не верим
+		}
+		last = ip
+		if !isTrusted(ip) {
+			return ip, true
+		}
+	}
+	// вся цепочка из доверенных — берём самый левый разобранный
+	return last, last.IsValid()
+}
+
+// parseIP — адрес из "ip", "ip:port" или "[ipv6]:port".
+func parseIP(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	a, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return a.Unmap(), true
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,20 +17,21 @@ import (
 	"github.com/cwrk-planet/auth-service/internal/service"
 	grpcsrv "github.com/cwrk-planet/auth-service/internal/transport/grpc"
 	handler "github.com/cwrk-planet/auth-service/internal/transport/http"
+	"github.com/cwrk-planet/auth-service/internal/transport/oauth"
+	"github.com/cwrk-planet/confload/pkg/confload"
 	"github.com/cwrk-planet/logger/pkg/logger"
 )
 
 func main() {
 	// Config init
-	cfg, err := config.LoadConfig("config/config.yaml")
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, confload.ErrPrinted) {
+		os.Exit(0)
+	}
 	if err != nil {
 		println("failed to load config:", err.Error())
 		os.Exit(1)
 	}
-	if err := cfg.Validate(); err != nil {
-		println("failed to validate config:", err.Error())
-		os.Exit(1)
-	}
 
 	// Logger init
 	logger.Init(logger.Config{
@@ -40,6 +43,7 @@ func main() {
 		Debug:     cfg.Logging.Debug,
 	})
 	slog.Info("starting auth-service", "version", cfg.Logging.Version)
+	slog.Info("effective config", "config", confload.Redacted(cfg)) // секреты заменены на ***
 
 	// PostgreSQL init
 	ctx := context.Background()
@@ -55,9 +59,27 @@ func main() {
 	usersRepo := postgres.NewUserRepoFromPool(pool)
 	sessionsRepo := postgres.NewSessionRepoFromPool(pool)
 
-	passCfg := security.BcryptConfig{
-		Cost:      cfg.Security.Password.BcryptCost,
+	passCfg := security.PasswordConfig{
+		Algorithm:  cfg.Security.Password.Algorithm,
+		BcryptCost: cfg.Security.Password.BcryptCost,
+		Argon2: security.Argon2Params{
+			Memory:      cfg.Security.Password.Argon2.MemoryKiB,
+			Iterations:  cfg.Security.Password.Argon2.Iterations,
+			Parallelism: cfg.Security.Password.Argon2.Parallelism,
+		},
 		MinLength: cfg.Security.Password.MinLength,
+		MaxLength: cfg.Security.Password.MaxLength,
+
+		MaxConcurrent: cfg.Security.Password.MaxConcurrent,
+	}
+	if path := cfg.Security.Password.BreachedListPath; path != "" {
+		list, err := security.OpenHashListFile(path)
+		if err != nil {
+			slog.Error("failed to open breached passwords list", slog.Any("err", err))
+			os.Exit(1)
+		}
+		defer func() { _ = list.Close() }()
+		passCfg.Breached = list
 	}
 
 	private, err := security.LoadRSAPrivateKeyFromPEM(cfg.Security.JWT.PrivateKeyPath)
@@ -88,6 +110,114 @@ func main() {
 		passCfg,
 		time.Now,
 	)
+	authSvc.SetTxRunner(postgres.NewTxRunner(pool))
+	authSvc.SetRBAC(postgres.NewRoleRepoFromPool(pool), service.RBACConfig{
+		DefaultRole: cfg.Security.RBAC.DefaultRole,
+	})
+	authSvc.SetAdmin(postgres.NewAdminAuditRepoFromPool(pool))
+	authSvc.SetAuditLog(postgres.NewAuthEventRepoFromPool(pool))
+	authSvc.SetAccountDeletionGrace(cfg.Account.DeletionGrace)
+	authSvc.SetLoginGuard(postgres.NewLoginFailuresRepoFromPool(pool), service.LoginGuardConfig{
+		MaxFailures:   cfg.Security.Login.MaxFailures,
+		IPMaxFailures: cfg.Security.Login.IPMaxFailures,
+		Window:        cfg.Security.Login.Window,
+		Lockout:       cfg.Security.Login.Lockout,
+		BaseDelay:     cfg.Security.Login.BaseDelay,
+		MaxDelay:      cfg.Security.Login.MaxDelay,
+	})
+	cleanupEvery := cfg.Security.Login.CleanupInterval
+	if cleanupEvery <= 0 {
+		cleanupEvery = time.Hour
+	}
+	go authSvc.RunCleanup(ctx, cleanupEvery)
+
+	var secretBox *security.SecretBox
+	if cfg.Security.MFA.EncryptionKey != "" {
+		secretBox, err = security.NewSecretBoxFromBase64(cfg.Security.MFA.EncryptionKey)
+		if err != nil {
+			slog.Error("failed to init mfa encryption", slog.Any("err", err))
+			os.Exit(1)
+		}
+	} else {
+		slog.Warn("security.mfa.encryptionKey is not set; TOTP enrollment is disabled")
+	}
+	authSvc.SetMFA(
+		postgres.NewMFARepoFromPool(pool),
+		postgres.NewMFAChallengeRepoFromPool(pool),
+		secretBox,
+		service.MFAConfig{
+			Issuer:        cfg.Security.MFA.Issuer,
+			ChallengeTTL:  cfg.Security.MFA.ChallengeTTL,
+			MaxAttempts:   cfg.Security.MFA.MaxAttempts,
+			RecoveryCodes: cfg.Security.MFA.RecoveryCodes,
+		},
+	)
+
+	if cfg.Security.WebAuthn.RPID != "" {
+		err := authSvc.SetWebAuthn(
+			postgres.NewPasskeyRepoFromPool(pool),
+			postgres.NewWebAuthnCeremonyRepoFromPool(pool),
+			service.WebAuthnConfig{
+				RPID:          cfg.Security.WebAuthn.RPID,
+				RPDisplayName: cfg.Security.WebAuthn.RPDisplayName,
+				RPOrigins:     cfg.Security.WebAuthn.RPOrigins,
+				CeremonyTTL:   cfg.Security.WebAuthn.CeremonyTTL,
+				MaxPasskeys:   cfg.Security.WebAuthn.MaxPasskeys,
+			},
+		)
+		if err != nil {
+			slog.Error("failed to init webauthn", slog.Any("err", err))
+			os.Exit(1)
+		}
+	}
+
+	if len(cfg.Security.OIDC.Providers) > 0 {
+		providers := make([]service.OIDCProviderConfig, 0, len(cfg.Security.OIDC.Providers))
+		for _, p := range cfg.Security.OIDC.Providers {
+			providers = append(providers, service.OIDCProviderConfig{
+				Name:         p.Name,
+				Issuer:       p.Issuer,
+				ClientID:     p.ClientID,
+				ClientSecret: p.ClientSecret,
+				RedirectURL:  p.RedirectURL,
+				Scopes:       p.Scopes,
+			})
+		}
+		err := authSvc.SetOIDC(
+			postgres.NewIdentityRepoFromPool(pool),
+			postgres.NewOIDCStateRepoFromPool(pool),
+			service.OIDCConfig{
+				Providers:    providers,
+				StateTTL:     cfg.Security.OIDC.StateTTL,
+				ReauthMaxAge: cfg.Security.OIDC.ReauthMaxAge,
+				HTTPClient:   &http.Client{Timeout: 10 * time.Second},
+			},
+		)
+		if err != nil {
+			slog.Error("failed to init oidc", slog.Any("err", err))
+			os.Exit(1)
+		}
+	}
+
+	var mounts []func(m *http.ServeMux)
+	if cfg.Security.OAuthProvider.Issuer != "" {
+		err := authSvc.SetOAuthProvider(
+			postgres.NewOAuthClientRepoFromPool(pool),
+			postgres.NewOAuthGrantRepoFromPool(pool),
+			service.OAuthProviderConfig{
+				Issuer:     cfg.Security.OAuthProvider.Issuer,
+				CodeTTL:    cfg.Security.OAuthProvider.CodeTTL,
+				AccessTTL:  cfg.Security.OAuthProvider.AccessTTL,
+				RefreshTTL: cfg.Security.OAuthProvider.RefreshTTL,
+				SSOTTL:     cfg.Security.OAuthProvider.SSOTTL,
+			},
+		)
+		if err != nil {
+			slog.Error("failed to init oauth provider", slog.Any("err", err))
+			os.Exit(1)
+		}
+		mounts = append(mounts, oauth.New(authSvc).Mount)
+	}
 
 	// gRPC server init
 	grpcServer, err := grpcsrv.New(cfg.Server.GRPCAddr, authSvc)
@@ -101,7 +231,7 @@ func main() {
 	}
 
 	// HTTP gateway init
-	httpServer, err := handler.New(cfg.Server.HTTPAddr, cfg.Server.GRPCAddr)
+	httpServer, err := handler.New(cfg.Server.HTTPAddr, cfg.Server.GRPCAddr, mounts...)
 	if err != nil {
 		slog.Error("failed to init http gateway", slog.Any("err", err))
 		os.Exit(1)
@@ -2,32 +2,32 @@ package config
 
 import (
 	"errors"
-	"os"
-	"strings"
+	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/cwrk-planet/auth-service/internal/pg"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cwrk-planet/confload/pkg/confload"
 )
 
 type Server struct {
-	GRPCAddr        string        `yaml:"grpcAddr"`
-	HTTPAddr        string        `yaml:"httpAddr"`
-	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	GRPCAddr        string        `yaml:"grpcAddr" default:":50051"`
+	HTTPAddr        string        `yaml:"httpAddr" default:":8081"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" default:"10s"`
 }
 
 type Logging struct {
-	Env       string `yaml:"env"`
-	Service   string `yaml:"service"`
-	Version   string `yaml:"version"`
-	Backend   string `yaml:"backend"`
+	Env       string `yaml:"env" default:"dev"`
+	Service   string `yaml:"service" default:"auth-service"`
+	Version   string `yaml:"version" default:"v0.1.0"`
+	Backend   string `yaml:"backend" default:"std"`
 	AddSource bool   `yaml:"addSource"`
 	Debug     bool   `yaml:"debug"`
 }
 
 type Postgres struct {
-	DSN               string        `yaml:"dsn"`
+	DSN               string        `yaml:"dsn" secret:"true" required:"true" env:"PG_DSN"` // лучше из env: AUTH_POSTGRES_DSN(_FILE) или PG_DSN
 	MaxConns          int32         `yaml:"maxConns"`
 	MinConns          int32         `yaml:"minConns"`
 	MaxConnLifetime   time.Duration `yaml:"maxConnLifetime"`
@@ -36,14 +36,6 @@ type Postgres struct {
 	ApplicationName   string        `yaml:"applicationName"`
 }
 
-func (p Postgres) Validate() error {
-	if p.DSN == "" {
-		return errors.New("postgres.DSN is required")
-	}
-
-	return nil
-}
-
 func (p Postgres) ToPGConfig() pg.Config {
 	return pg.Config{
 		DSN:               p.DSN,
@@ -56,18 +48,53 @@ func (p Postgres) ToPGConfig() pg.Config {
 	}
 }
 
+// Password - хеширование и политика паролей. Старые хеши пересчитываются по этим настройкам при входе
 type Password struct {
-	MinLength  int `yaml:"minLength"`
-	BcryptCost int `yaml:"bcryptCost"`
+	MinLength  int    `yaml:"minLength" default:"6"`
+	MaxLength  int    `yaml:"maxLength"`  // символов, по умолчанию 128
+	Algorithm  string `yaml:"algorithm"`  // argon2id (по умолчанию) | bcrypt
+	BcryptCost int    `yaml:"bcryptCost"` // для algorithm: bcrypt
+	Argon2     Argon2 `yaml:"argon2"`
+
+	// файл SHA-1 утекших паролей "<hash>:<count>", отсортированный по hash
+	// (выгрузка Pwned Passwords); пусто - не проверять
+	BreachedListPath string `yaml:"breachedListPath"`
+
+	// одновременных хешей/сравнений; сверх лимита - 503, клиент повторит. 0 - GOMAXPROCS
+	MaxConcurrent int `yaml:"maxConcurrent"`
+}
+
+// Argon2 - параметры argon2id; нули - значения по умолчанию (64 MiB, 3 прохода, 2 потока)
+type Argon2 struct {
+	MemoryKiB   uint32 `yaml:"memoryKiB"`
+	Iterations  uint32 `yaml:"iterations"`
+	Parallelism uint8  `yaml:"parallelism"`
 }
 
 func (p Password) Validate() error {
 	if p.MinLength < 6 {
 		return errors.New("security.password.minLength must be >= 6")
 	}
+	if p.MaxLength != 0 && (p.MaxLength < p.MinLength || p.MaxLength > 1024) {
+		return errors.New("security.password.maxLength must be in [minLength..1024]")
+	}
+	switch p.Algorithm {
+	case "", "argon2id", "bcrypt":
+	default:
+		return fmt.Errorf("security.password.algorithm %q is not supported (argon2id|bcrypt)", p.Algorithm)
+	}
 	if p.BcryptCost != 0 && (p.BcryptCost < 4 || p.BcryptCost > 18) {
 		return errors.New("security.password.bcryptCost must be in [4..18]")
 	}
+	if p.Argon2.MemoryKiB != 0 && p.Argon2.MemoryKiB < 8*1024 {
+		return errors.New("security.password.argon2.memoryKiB must be >= 8192")
+	}
+	if p.Argon2.Iterations > 100 || p.Argon2.Parallelism > 64 {
+		return errors.New("security.password.argon2: iterations must be <= 100, parallelism <= 64")
+	}
+	if p.MaxConcurrent < 0 {
+		return errors.New("security.password.maxConcurrent must be >= 0")
+	}
 
 	return nil
 }
@@ -77,7 +104,7 @@ type JWT struct {
 	PrivateKeyPath string        `yaml:"privateKeyPath"` // обязательно
 	PublicKeyPath  string        `yaml:"publicKeyPath"`  // обязательно
 	Issuer         string        `yaml:"issuer"`         // обязательно
-	Audience       string        `yaml:"audience"`       // по желанию, но пока особо не проверятся
+	Audience       string        `yaml:"audience"`       // обязательно, то же значение в auth.audience gateway и room-service
 	AccessTTL      time.Duration `yaml:"accessTTL"`      // напр. 15m
 	ClockSkew      time.Duration `yaml:"clockSkew"`      // напр. 30s
 }
@@ -95,6 +122,9 @@ func (j JWT) Validate() error {
 	if j.Issuer == "" {
 		return errors.New("security.jwt.issuer is required")
 	}
+	if j.Audience == "" {
+		return errors.New("security.jwt.audience is required")
+	}
 	if j.AccessTTL <= 0 {
 		return errors.New("security.jwt.accessTTL must be > 0")
 	}
@@ -105,17 +135,175 @@ func (j JWT) Validate() error {
 	return nil
 }
 
+// Login - защита от перебора паролей; нули - значения по умолчанию (см. service.LoginGuardConfig)
+type Login struct {
+	MaxFailures     int           `yaml:"maxFailures"`     // неудач по аккаунту до блокировки, по умолчанию 5
+	IPMaxFailures   int           `yaml:"ipMaxFailures"`   // неудач с одного IP до блокировки, по умолчанию 50
+	Window          time.Duration `yaml:"window"`          // неудачи старше забываются, по умолчанию 15m
+	Lockout         time.Duration `yaml:"lockout"`         // длительность блокировки, по умолчанию 15m
+	BaseDelay       time.Duration `yaml:"baseDelay"`       // пауза после первой неудачи, дальше удваивается, по умолчанию 1s
+	MaxDelay        time.Duration `yaml:"maxDelay"`        // потолок паузы, по умолчанию 30s
+	CleanupInterval time.Duration `yaml:"cleanupInterval"` // чистка старых счетчиков, по умолчанию 1h
+}
+
+func (l Login) Validate() error {
+	if l.MaxFailures < 0 || l.IPMaxFailures < 0 {
+		return errors.New("security.login.maxFailures and ipMaxFailures must be >= 0")
+	}
+	if l.Window < 0 || l.Lockout < 0 || l.BaseDelay < 0 || l.MaxDelay < 0 || l.CleanupInterval < 0 {
+		return errors.New("security.login durations must be >= 0")
+	}
+	if l.MaxDelay > 0 && l.MaxDelay < l.BaseDelay {
+		return errors.New("security.login.maxDelay must be >= baseDelay")
+	}
+
+	return nil
+}
+
+// MFA - второй фактор (TOTP); без encryptionKey подключить TOTP нельзя
+type MFA struct {
+	Issuer        string        `yaml:"issuer"`                      // название в приложении-аутентификаторе, по умолчанию cwrk-planet
+	EncryptionKey string        `yaml:"encryptionKey" secret:"true"` // 32 байта в base64, шифрует TOTP-секреты в БД
+	ChallengeTTL  time.Duration `yaml:"challengeTTL"`                // сколько живет mfa_token, по умолчанию 5m
+	MaxAttempts   int           `yaml:"maxAttempts"`                 // неверных кодов на один mfa_token, по умолчанию 5
+	RecoveryCodes int           `yaml:"recoveryCodes"`               // кодов восстановления, по умолчанию 10
+}
+
+func (m MFA) Validate() error {
+	if m.ChallengeTTL < 0 || m.ChallengeTTL > time.Hour {
+		return errors.New("security.mfa.challengeTTL must be in [0..1h]")
+	}
+	if m.MaxAttempts < 0 || m.RecoveryCodes < 0 || m.RecoveryCodes > 50 {
+		return errors.New("security.mfa.maxAttempts must be >= 0, recoveryCodes in [0..50]")
+	}
+
+	return nil
+}
+
+// WebAuthn - вход по passkey; пустой rpId - выключено
+type WebAuthn struct {
+	RPID          string        `yaml:"rpId"`          // домен без схемы и порта, напр. localhost
+	RPDisplayName string        `yaml:"rpDisplayName"` // по умолчанию cwrk-planet
+	RPOrigins     []string      `yaml:"rpOrigins"`     // напр. http://localhost:5173
+	CeremonyTTL   time.Duration `yaml:"ceremonyTTL"`   // по умолчанию 5m
+	MaxPasskeys   int           `yaml:"maxPasskeys"`   // на пользователя, по умолчанию 10
+}
+
+func (w WebAuthn) Validate() error {
+	if w.RPID == "" {
+		return nil
+	}
+	if len(w.RPOrigins) == 0 {
+		return errors.New("security.webauthn.rpOrigins is required when rpId is set")
+	}
+	if w.CeremonyTTL < 0 || w.MaxPasskeys < 0 {
+		return errors.New("security.webauthn.ceremonyTTL and maxPasskeys must be >= 0")
+	}
+
+	return nil
+}
+
+// OIDCProvider - внешний провайдер для входа (Google, GitLab, Keycloak...)
+type OIDCProvider struct {
+	Name         string   `yaml:"name"`   // в URL: /auth/oidc/{name}/...
+	Issuer       string   `yaml:"issuer"` // напр. https://accounts.google.com
+	ClientID     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret" secret:"true"`
+	RedirectURL  string   `yaml:"redirectUrl"` // страница фронта, куда провайдер вернет code и state
+	Scopes       []string `yaml:"scopes"`      // по умолчанию openid, email, profile
+}
+
+// OIDC - вход через внешних провайдеров; пустой providers - выключено
+type OIDC struct {
+	Providers []OIDCProvider `yaml:"providers"`
+	StateTTL  time.Duration  `yaml:"stateTTL"` // по умолчанию 10m
+	// вход через провайдера не старше этого подтверждает удаление аккаунта без пароля и TOTP, по умолчанию 5m
+	ReauthMaxAge time.Duration `yaml:"reauthMaxAge"`
+}
+
+func (o OIDC) Validate() error {
+	seen := make(map[string]struct{}, len(o.Providers))
+	for _, p := range o.Providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "" {
+			return errors.New("security.oidc.providers: name, issuer, clientId and redirectUrl are required")
+		}
+		if _, ok := seen[p.Name]; ok {
+			return fmt.Errorf("security.oidc.providers: duplicate name %q", p.Name)
+		}
+		seen[p.Name] = struct{}{}
+	}
+	if o.StateTTL < 0 {
+		return errors.New("security.oidc.stateTTL must be >= 0")
+	}
+	if o.ReauthMaxAge < 0 {
+		return errors.New("security.oidc.reauthMaxAge must be >= 0")
+	}
+
+	return nil
+}
+
+// OAuthProvider - auth-service как OIDC провайдер для других приложений (клиенты - в oauth_clients); пустой issuer - выключено
+type OAuthProvider struct {
+	Issuer     string        `yaml:"issuer"`     // внешний URL auth-service, напр. https://auth.cwrk.dev
+	CodeTTL    time.Duration `yaml:"codeTTL"`    // по умолчанию 1m
+	AccessTTL  time.Duration `yaml:"accessTTL"`  // по умолчанию 15m
+	RefreshTTL time.Duration `yaml:"refreshTTL"` // по умолчанию 30d
+	SSOTTL     time.Duration `yaml:"ssoTTL"`     // по умолчанию 1h
+}
+
+func (o OAuthProvider) Validate() error {
+	if o.Issuer == "" {
+		return nil
+	}
+	u, err := url.Parse(o.Issuer)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.RawQuery != "" || u.Fragment != "" {
+		return errors.New("security.oauthProvider.issuer must be an absolute http(s) URL without query and fragment")
+	}
+	if o.CodeTTL < 0 || o.AccessTTL < 0 || o.RefreshTTL < 0 || o.SSOTTL < 0 {
+		return errors.New("security.oauthProvider TTLs must be >= 0")
+	}
+
+	return nil
+}
+
+// RBAC - роли платформы (таблицы roles и user_roles)
+type RBAC struct {
+	DefaultRole string `yaml:"defaultRole"` // выдается при регистрации, по умолчанию user
+}
+
 type Security struct {
 	Password Password `yaml:"password"`
 	JWT      JWT      `yaml:"jwt"`
+	Login    Login    `yaml:"login"`
+	MFA      MFA      `yaml:"mfa"`
+	WebAuthn WebAuthn `yaml:"webauthn"`
+	OIDC     OIDC     `yaml:"oidc"`
+
+	OAuthProvider OAuthProvider `yaml:"oauthProvider"`
+	RBAC          RBAC          `yaml:"rbac"`
 }
 
+// Validate - ошибки всех разделов разом
 func (s Security) Validate() error {
-	if err := s.Password.Validate(); err != nil {
-		return err
-	}
-	if err := s.JWT.Validate(); err != nil {
-		return err
+	return errors.Join(
+		s.Password.Validate(),
+		s.JWT.Validate(),
+		s.Login.Validate(),
+		s.MFA.Validate(),
+		s.WebAuthn.Validate(),
+		s.OIDC.Validate(),
+		s.OAuthProvider.Validate(),
+	)
+}
+
+// Account - удаление аккаунта по запросу пользователя
+type Account struct {
+	DeletionGrace time.Duration `yaml:"deletionGrace"` // сколько можно отменить удаление, по умолчанию 336h (14 дней)
+}
+
+func (a Account) Validate() error {
+	if a.DeletionGrace < 0 {
+		return errors.New("account.deletionGrace must be >= 0")
 	}
 
 	return nil
@@ -124,34 +312,28 @@ func (s Security) Validate() error {
 type Config struct {
 	Server   Server   `yaml:"server"`
 	Security Security `yaml:"security"`
+	Account  Account  `yaml:"account"`
 	Postgres Postgres `yaml:"postgres"`
 	Logging  Logging  `yaml:"logging"`
 }
 
+// Validate вызывается из Load после всех источников; postgres.dsn проверяет сам confload (required)
 func (c *Config) Validate() error {
-	if err := c.Security.Validate(); err != nil {
-		return err
-	}
-	if err := c.Postgres.Validate(); err != nil {
-		return err
-	}
-
-	return nil
+	return errors.Join(
+		c.Security.Validate(),
+		c.Account.Validate(),
+	)
 }
 
-func LoadConfig(path ...string) (*Config, error) {
-	filename := "config/config.yaml"
-	if len(path) > 0 && strings.TrimSpace(path[0]) != "" {
-		filename = path[0]
-	}
-
-	data, err := os.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
+// Load - defaults из тегов, config/config.yaml (если есть), env AUTH_*, флаги из args; см. confload
+func Load(args []string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
+	if err := confload.Load(&cfg, confload.Options{
+		Name:        "auth-service",
+		EnvPrefix:   "AUTH",
+		DefaultPath: "config/config.yaml",
+		Args:        args,
+	}); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// AdminAction - что сделал админ; пишется в admin_audit_log
+type AdminAction string
+
+const (
+	AdminActionDisable       AdminAction = "user.disable"
+	AdminActionEnable        AdminAction = "user.enable"
+	AdminActionForceLogout   AdminAction = "user.force_logout"
+	AdminActionPasswordReset AdminAction = "user.force_password_reset"
+	AdminActionSetRoles      AdminAction = "user.set_roles"
+	AdminActionDelete        AdminAction = "user.delete"
+)
+
+type AdminAuditID int64
+
+// AdminAuditEntry - запись журнала действий админов
+type AdminAuditEntry struct {
+	ID           AdminAuditID
+	ActorID      UserID
+	Action       AdminAction
+	TargetUserID *UserID           // nil - действие не над пользователем
+	Details      map[string]string // причина, новые роли и т.п.
+	CreatedAt    time.Time
+}
+
+// AdminAuditFilter - выборка журнала, новые записи первыми
+type AdminAuditFilter struct {
+	ActorID      *UserID
+	TargetUserID *UserID
+	BeforeID     AdminAuditID // 0 - с самого нового
+	Limit        int
+}
+
+// UserSearch - поиск пользователей по подстроке email или имени, по возрастанию id
+type UserSearch struct {
+	Query   string // пусто - все
+	AfterID UserID // курсор: последний id предыдущей страницы
+	Limit   int
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"net/netip"
+	"time"
+)
+
+// AuthEventType - тип события безопасности; пишется в auth_audit_events
+type AuthEventType string
+
+const (
+	AuthEventRegister             AuthEventType = "register"
+	AuthEventLogin                AuthEventType = "login"
+	AuthEventLoginFailed          AuthEventType = "login.failed"
+	AuthEventRefresh              AuthEventType = "refresh"
+	AuthEventRefreshReuse         AuthEventType = "refresh.reuse_detected"
+	AuthEventPasswordChange       AuthEventType = "password.change"
+	AuthEventPasswordChangeFailed AuthEventType = "password.change_failed"
+	AuthEventTOTPEnabled          AuthEventType = "mfa.totp.enabled"
+	AuthEventTOTPDisabled         AuthEventType = "mfa.totp.disabled"
+	AuthEventPasskeyAdded         AuthEventType = "mfa.passkey.added"
+	AuthEventPasskeyRevoked       AuthEventType = "mfa.passkey.revoked"
+	AuthEventIdentityLinked       AuthEventType = "identity.linked"
+	AuthEventIdentityUnlinked     AuthEventType = "identity.unlinked"
+
+	AuthEventAccountDeletionRequested AuthEventType = "account.deletion_requested"
+	AuthEventAccountDeletionCancelled AuthEventType = "account.deletion_cancelled"
+	AuthEventAccountDeletionFailed    AuthEventType = "account.deletion_failed"
+	AuthEventAccountDeleted           AuthEventType = "account.deleted"
+)
+
+// AdminAuthEvent - действие админа в журнале безопасности: admin.user.disable и т.п.
+func AdminAuthEvent(a AdminAction) AuthEventType {
+	return AuthEventType("admin." + string(a))
+}
+
+type AuthEventID int64
+
+// AuthEvent - запись журнала безопасности
+type AuthEvent struct {
+	ID        AuthEventID
+	Type      AuthEventType
+	UserID    *UserID // nil - пользователь неизвестен
+	ActorID   *UserID // админ, для admin.*
+	IP        *netip.Addr
+	UserAgent *string
+	RequestID *string
+	Details   map[string]string // способ входа, причина отказа и т.п.
+	CreatedAt time.Time
+}
+
+// AuthEventFilter - выборка журнала, новые записи первыми
+type AuthEventFilter struct {
+	UserID   *UserID
+	Types    []AuthEventType // пусто - все
+	IP       *netip.Addr
+	BeforeID AuthEventID // 0 - с самого нового
+	Limit    int
+}
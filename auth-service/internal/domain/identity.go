@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type IdentityID int64
+
+// Identity - внешний аккаунт (OIDC-провайдер), привязанный к пользователю
+type Identity struct {
+	ID          IdentityID
+	UserID      UserID
+	Provider    string
+	Subject     string
+	Email       *string
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// OIDCState - вход через провайдера, который ждет возврата с кодом.
+// UserID != nil - это не вход, а привязка аккаунта к UserID
+type OIDCState struct {
+	StateHash    string
+	Provider     string
+	Nonce        string
+	CodeVerifier string
+	UserID       *UserID
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+func (s *OIDCState) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// LoginScope - по чему считаем неудачные входы
+type LoginScope string
+
+const (
+	LoginScopeAccount LoginScope = "account" // ключ - email
+	LoginScopeIP      LoginScope = "ip"      // ключ - IP клиента
+)
+
+// LoginFailures - счетчик неудачных входов по одному ключу
+type LoginFailures struct {
+	Scope        LoginScope
+	Key          string
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+// Locked - заблокирован ли вход на момент now
+func (f *LoginFailures) Locked(now time.Time) bool {
+	return f.LockedUntil != nil && now.Before(*f.LockedUntil)
+}
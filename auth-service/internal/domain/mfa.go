@@ -0,0 +1,27 @@
+package domain
+
+import "time"
+
+// TOTPState - состояние TOTP у пользователя
+type TOTPState struct {
+	SecretEnc *string    // зашифрованный секрет
+	EnabledAt *time.Time // nil - не подключен или подключение не подтверждено
+	LastStep  int64      // последний принятый шаг
+}
+
+func (t *TOTPState) Enabled() bool {
+	return t != nil && t.EnabledAt != nil && t.SecretEnc != nil
+}
+
+// MFAChallenge - незавершенный вход: пароль проверен, ждем второй фактор
+type MFAChallenge struct {
+	TokenHash string
+	UserID    UserID
+	ExpiresAt time.Time
+	Attempts  int
+	CreatedAt time.Time
+}
+
+func (c *MFAChallenge) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// OAuthClient - приложение, которое входит через auth-service (мы - OIDC провайдер)
+type OAuthClient struct {
+	ID           string
+	SecretHash   *string // nil - публичный клиент, без секрета, только с PKCE
+	Name         string
+	RedirectURIs []string
+	Scopes       []string // какие scopes клиенту вообще можно запрашивать
+	CreatedAt    time.Time
+}
+
+func (c *OAuthClient) IsPublic() bool { return c.SecretHash == nil }
+
+// AllowsRedirect - redirect_uri сравниваем целиком, без префиксов и шаблонов
+func (c *OAuthClient) AllowsRedirect(uri string) bool {
+	return slices.Contains(c.RedirectURIs, uri)
+}
+
+func (c *OAuthClient) AllowsScopes(scopes []string) bool {
+	for _, s := range scopes {
+		if !slices.Contains(c.Scopes, s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// OAuthCode - authorization code до обмена на токены
+type OAuthCode struct {
+	CodeHash      string
+	ClientID      string
+	UserID        UserID
+	RedirectURI   string
+	Scopes        []string
+	Nonce         *string
+	CodeChallenge *string
+	AuthTime      time.Time
+	ExpiresAt     time.Time
+	CreatedAt     time.Time
+}
+
+func (c *OAuthCode) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
+
+// OAuthRefreshToken - refresh-токен клиента (в БД - только хеш)
+type OAuthRefreshToken struct {
+	TokenHash string
+	ClientID  string
+	UserID    UserID
+	Scopes    []string
+	AuthTime  time.Time
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (t *OAuthRefreshToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
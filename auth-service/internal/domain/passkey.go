@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+type PasskeyID int64
+
+// Passkey - WebAuthn-ключ пользователя
+type Passkey struct {
+	ID              PasskeyID
+	UserID          UserID
+	CredentialID    []byte
+	PublicKey       []byte // COSE
+	AttestationType string
+	Transports      []string
+	AAGUID          []byte
+	SignCount       uint32
+	BackupEligible  bool
+	BackupState     bool
+	Name            string
+	CreatedAt       time.Time
+	LastUsedAt      *time.Time
+}
+
+type WebAuthnCeremonyKind string
+
+const (
+	WebAuthnRegistration WebAuthnCeremonyKind = "registration"
+	WebAuthnLogin        WebAuthnCeremonyKind = "login"
+	WebAuthnReauth       WebAuthnCeremonyKind = "reauth" // повторная проверка перед удалением аккаунта
+)
+
+// WebAuthnCeremony - незавершенная регистрация или вход; Session - webauthn.SessionData в JSON
+type WebAuthnCeremony struct {
+	IDHash    string
+	Kind      WebAuthnCeremonyKind
+	UserID    *UserID
+	Session   []byte
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (c *WebAuthnCeremony) IsExpired(now time.Time) bool {
+	return !now.Before(c.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Role - роль платформы и ее права (scopes в access-токене)
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+	CreatedAt   time.Time
+}
+
+// Grants - имена ролей и объединение их прав, без повторов и в стабильном порядке
+func Grants(roles []Role) (names, scopes []string) {
+	for _, r := range roles {
+		names = append(names, r.Name)
+		for _, p := range r.Permissions {
+			if !slices.Contains(scopes, p) {
+				scopes = append(scopes, p)
+			}
+		}
+	}
+	slices.Sort(names)
+	slices.Sort(scopes)
+
+	return names, scopes
+}
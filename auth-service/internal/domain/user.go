@@ -18,6 +18,10 @@ type User struct {
 	AvatarURL     *string
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
+
+	DisabledAt            *time.Time // заблокирован админом: ни входа, ни refresh
+	PasswordResetRequired bool       // вход по паролю - только после смены пароля
+	DeletionScheduledAt   *time.Time // пользователь запросил удаление; до этого момента можно отменить
 }
 
 // Создает нового пользователя
@@ -69,6 +73,10 @@ func (u *User) VeriyEmail(now time.Time) {
 	u.UpdatedAt = now
 }
 
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
 // Options конструктора
 type UserOption func(*User)
 
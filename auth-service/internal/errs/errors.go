@@ -8,6 +8,10 @@ var (
 	ErrEmptyTokenHash     = errors.New("empty token hash")
 	ErrPastExpiry         = errors.New("expires_at is in the past")
 	ErrPasswordTooShort   = errors.New("password too short")
+	ErrPasswordTooLong    = errors.New("password too long")
+	ErrPasswordIsEmail    = errors.New("password must not be the email")
+	ErrPasswordBreached   = errors.New("password has appeared in a data breach, choose another one")
+	ErrPasswordHashBusy   = errors.New("too many password checks in progress, retry later")
 	ErrInvalidToken       = errors.New("invalid token")
 	ErrInvalidIssuer      = errors.New("invalid issuer")
 	ErrInvalidAudience    = errors.New("invalid audience")
@@ -15,4 +19,70 @@ var (
 	ErrInvalidSubject     = errors.New("invalid subject")
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrSessionExpired     = errors.New("session expired")
+	ErrTooManyAttempts    = errors.New("too many login attempts, try again later")
+	ErrMFAUnavailable     = errors.New("mfa is not configured")
+	ErrMFAAlreadyEnabled  = errors.New("totp is already enabled")
+	ErrMFANotEnabled      = errors.New("totp is not enabled")
+	ErrMFANotEnrolled     = errors.New("totp enrollment not started")
+	ErrInvalidMFACode     = errors.New("invalid mfa code")
+	ErrInvalidMFAToken    = errors.New("invalid or expired mfa token")
+
+	ErrWebAuthnUnavailable = errors.New("webauthn is not configured")
+	ErrInvalidCeremony     = errors.New("invalid or expired webauthn ceremony")
+	ErrWebAuthnFailed      = errors.New("webauthn verification failed")
+	ErrPasskeyLimit        = errors.New("too many passkeys")
+	ErrInvalidPasskeyName  = errors.New("invalid passkey name")
+
+	ErrUnknownProvider      = errors.New("unknown identity provider")
+	ErrInvalidOIDCState     = errors.New("invalid or expired oidc state")
+	ErrOIDCFailed           = errors.New("oidc verification failed")
+	ErrOIDCEmailNotVerified = errors.New("email is not verified by identity provider")
+	ErrAccountLinkRequired  = errors.New("account with this email already exists, sign in and link the provider")
+	ErrIdentityLinked       = errors.New("identity is already linked to another account")
+	ErrLastLoginMethod      = errors.New("cannot remove the last sign-in method")
+
+	ErrOAuthUnavailable = errors.New("oauth provider is not configured")
+
+	ErrAccountDisabled       = errors.New("account is disabled")
+	ErrPasswordResetRequired = errors.New("password change required")
+	ErrAdminUnavailable      = errors.New("admin api is not configured")
+	ErrForbidden             = errors.New("admin:users permission required")
+	ErrSelfAdminAction       = errors.New("cannot apply this action to your own account")
+
+	ErrAuditUnavailable = errors.New("security audit log is not configured")
+
+	ErrDeletionNotScheduled = errors.New("account deletion is not scheduled")
+	ErrReauthUnavailable    = errors.New("confirm with a password, a passkey or a recent provider login")
 )
+
+// Коды ошибок OAuth 2.0 (RFC 6749, 5.2 и 4.1.2.1) и OIDC; уходят клиенту как есть
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthUnauthorizedClient      = "unauthorized_client"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthAccessDenied            = "access_denied"
+	OAuthLoginRequired           = "login_required"
+	OAuthConsentRequired         = "consent_required"
+	OAuthInvalidToken            = "invalid_token"
+)
+
+// OAuthError - ошибка для OAuth-клиента: код из списка выше + описание
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func NewOAuthError(code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description}
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type AdminAuditRepository interface {
+	// Добавляет запись журнала; журнал только дописывается
+	Add(ctx context.Context, e *domain.AdminAuditEntry) (domain.AdminAuditID, error)
+	// Записи по фильтру, новые первыми
+	List(ctx context.Context, f domain.AdminAuditFilter) ([]domain.AdminAuditEntry, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type AuthEventRepository interface {
+	// Добавляет событие; журнал только дописывается
+	Add(ctx context.Context, e *domain.AuthEvent) (domain.AuthEventID, error)
+	// События по фильтру, новые первыми
+	List(ctx context.Context, f domain.AuthEventFilter) ([]domain.AuthEvent, error)
+}
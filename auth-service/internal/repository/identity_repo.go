@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type IdentityRepository interface {
+	// Привязывает внешний аккаунт; (provider, subject) или (user, provider) уже есть - ErrAlreadyExists
+	Create(ctx context.Context, i *domain.Identity) (domain.IdentityID, error)
+	// Ищет привязку по subject у провайдера
+	GetBySubject(ctx context.Context, provider, subject string) (*domain.Identity, error)
+	// Все привязки пользователя
+	ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Identity, error)
+	// Отмечает вход через провайдера
+	TouchLogin(ctx context.Context, id domain.IdentityID, now time.Time) error
+	// Отвязывает провайдера от пользователя; привязки нет - ErrNotFound
+	Delete(ctx context.Context, userID domain.UserID, provider string) error
+}
+
+type OIDCStateRepository interface {
+	// Сохраняет state
+	Create(ctx context.Context, s *domain.OIDCState) error
+	// Забирает (и удаляет) state: каждый код обменивается только один раз
+	Take(ctx context.Context, stateHash string) (*domain.OIDCState, error)
+	// Очистка просроченных на момент now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
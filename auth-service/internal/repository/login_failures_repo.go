@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type LoginFailuresRepository interface {
+	// Счетчик по ключу; если записи нет - ErrNotFound
+	Get(ctx context.Context, scope domain.LoginScope, key string) (*domain.LoginFailures, error)
+	// Увеличивает счетчик; если последняя неудача раньше resetBefore - начинает с 1
+	RegisterFailure(ctx context.Context, scope domain.LoginScope, key string, now, resetBefore time.Time) (*domain.LoginFailures, error)
+	// Блокирует вход по ключу до until
+	Lock(ctx context.Context, scope domain.LoginScope, key string, until time.Time) error
+	// Сбрасывает счетчик (после успешного входа)
+	Reset(ctx context.Context, scope domain.LoginScope, key string) error
+	// Удаляет старые записи без активной блокировки
+	DeleteStale(ctx context.Context, before time.Time) (int64, error)
+}
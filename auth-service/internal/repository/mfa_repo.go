@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type MFARepository interface {
+	// Состояние TOTP пользователя
+	GetTOTP(ctx context.Context, userID domain.UserID) (*domain.TOTPState, error)
+	// Сохраняет новый (еще не подтвержденный) секрет
+	SetPendingTOTP(ctx context.Context, userID domain.UserID, secretEnc string, now time.Time) error
+	// Подтверждает подключение TOTP, step - шаг первого кода
+	EnableTOTP(ctx context.Context, userID domain.UserID, step int64, now time.Time) error
+	// Отключает TOTP и стирает секрет
+	DisableTOTP(ctx context.Context, userID domain.UserID, now time.Time) error
+	// Запоминает принятый шаг; false - шаг не новее уже принятого (повтор кода)
+	AdvanceTOTPStep(ctx context.Context, userID domain.UserID, step int64) (bool, error)
+	// Заменяет коды восстановления новыми (хеши)
+	ReplaceRecoveryCodes(ctx context.Context, userID domain.UserID, hashes []string, now time.Time) error
+	// Погашает код восстановления; false - кода нет или он уже использован
+	UseRecoveryCode(ctx context.Context, userID domain.UserID, hash string, now time.Time) (bool, error)
+	// Удаляет все коды восстановления
+	DeleteRecoveryCodes(ctx context.Context, userID domain.UserID) error
+}
+
+type MFAChallengeRepository interface {
+	// Создает challenge второго шага входа
+	Create(ctx context.Context, c *domain.MFAChallenge) error
+	// Ищет challenge по хешу mfa-токена
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error)
+	// +1 к неудачным попыткам, возвращает новое значение
+	IncrementAttempts(ctx context.Context, tokenHash string) (int, error)
+	// Удаляет challenge
+	Delete(ctx context.Context, tokenHash string) error
+	// Очистка просроченных на момент now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type OAuthClientRepository interface {
+	// Ищет клиента по client_id
+	Get(ctx context.Context, id string) (*domain.OAuthClient, error)
+}
+
+type OAuthGrantRepository interface {
+	// Сохраняет authorization code
+	CreateCode(ctx context.Context, c *domain.OAuthCode) error
+	// Забирает (и удаляет) code: обменять можно только один раз
+	TakeCode(ctx context.Context, codeHash string) (*domain.OAuthCode, error)
+	// Сохраняет refresh-токен клиента
+	CreateRefreshToken(ctx context.Context, t *domain.OAuthRefreshToken) error
+	// Забирает (и удаляет) refresh-токен: при обновлении выдаем новый
+	TakeRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error)
+	// Scopes, на которые пользователь уже согласился для клиента; согласия нет - ErrNotFound
+	GetConsent(ctx context.Context, userID domain.UserID, clientID string) ([]string, error)
+	// Сохраняет (заменяет) согласие
+	SaveConsent(ctx context.Context, userID domain.UserID, clientID string, scopes []string, now time.Time) error
+	// Очистка просроченных codes и refresh-токенов на момент now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
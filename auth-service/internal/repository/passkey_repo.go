@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type PasskeyRepository interface {
+	// Сохраняет новый passkey; credential_id уже есть - ErrAlreadyExists
+	Create(ctx context.Context, p *domain.Passkey) (domain.PasskeyID, error)
+	// Все passkeys пользователя, старые первыми
+	ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Passkey, error)
+	// Ищет passkey по credential_id
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*domain.Passkey, error)
+	// Сохраняет счетчик подписей и backup state после входа
+	UpdateUsage(ctx context.Context, id domain.PasskeyID, signCount uint32, backupState bool, now time.Time) error
+	// Переименовывает passkey пользователя; чужой или несуществующий - ErrNotFound
+	Rename(ctx context.Context, userID domain.UserID, id domain.PasskeyID, name string) error
+	// Удаляет (отзывает) passkey пользователя; чужой или несуществующий - ErrNotFound
+	Delete(ctx context.Context, userID domain.UserID, id domain.PasskeyID) error
+}
+
+type WebAuthnCeremonyRepository interface {
+	// Сохраняет церемонию
+	Create(ctx context.Context, c *domain.WebAuthnCeremony) error
+	// Забирает (и удаляет) церемонию: каждую можно завершить только один раз
+	Take(ctx context.Context, idHash string) (*domain.WebAuthnCeremony, error)
+	// Очистка просроченных на момент now
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
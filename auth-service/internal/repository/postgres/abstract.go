@@ -24,6 +24,19 @@ type querier interface {
 }
 
 func (r *UserRepo) getOne(ctx context.Context, sql string, arg any) (*domain.User, error) {
+	u, err := scanUser(r.q.QueryRow(ctx, sql, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+
+	return u, nil
+}
+
+// scanUser - строка users в порядке колонок из QueryGetUserByID
+func scanUser(row pgx.Row) (*domain.User, error) {
 	var (
 		id            int64
 		email         string
@@ -33,9 +46,12 @@ func (r *UserRepo) getOne(ctx context.Context, sql string, arg any) (*domain.Use
 		avatarURL     *string
 		createdAt     time.Time
 		updatedAt     time.Time
+		disabledAt    *time.Time
+		resetRequired bool
+		deletionAt    *time.Time
 	)
 
-	err := r.q.QueryRow(ctx, sql, arg).Scan(
+	err := row.Scan(
 		&id,
 		&email,
 		&emailVerified,
@@ -44,23 +60,26 @@ func (r *UserRepo) getOne(ctx context.Context, sql string, arg any) (*domain.Use
 		&avatarURL,
 		&createdAt,
 		&updatedAt,
+		&disabledAt,
+		&resetRequired,
+		&deletionAt,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
-		return nil, mapPgError(err)
+		return nil, err
 	}
 
 	return &domain.User{
-		ID:            domain.UserID(id),
-		Email:         email,
-		EmailVerified: emailVerified,
-		PasswordHash:  passwordHash,
-		DisplayName:   displayName,
-		AvatarURL:     avatarURL,
-		CreatedAt:     createdAt,
-		UpdatedAt:     updatedAt,
+		ID:                    domain.UserID(id),
+		Email:                 email,
+		EmailVerified:         emailVerified,
+		PasswordHash:          passwordHash,
+		DisplayName:           displayName,
+		AvatarURL:             avatarURL,
+		CreatedAt:             createdAt,
+		UpdatedAt:             updatedAt,
+		DisabledAt:            disabledAt,
+		PasswordResetRequired: resetRequired,
+		DeletionScheduledAt:   deletionAt,
 	}, nil
 }
 
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AdminAuditRepo struct {
+	q querier
+}
+
+func NewAdminAuditRepoFromPool(q querier) *AdminAuditRepo {
+	return &AdminAuditRepo{q: q}
+}
+
+func NewAdminAuditRepoFromTx(tx pgx.Tx) *AdminAuditRepo {
+	return &AdminAuditRepo{q: tx}
+}
+
+func (r *AdminAuditRepo) Add(ctx context.Context, e *domain.AdminAuditEntry) (domain.AdminAuditID, error) {
+	details := e.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	var id int64
+	err := r.q.QueryRow(ctx, queries.QueryAddAdminAudit, e.ActorID, string(e.Action), e.TargetUserID, details, e.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+
+	return domain.AdminAuditID(id), nil
+}
+
+func (r *AdminAuditRepo) List(ctx context.Context, f domain.AdminAuditFilter) ([]domain.AdminAuditEntry, error) {
+	rows, err := r.q.Query(ctx, queries.QueryListAdminAudit, f.ActorID, f.TargetUserID, f.BeforeID, f.Limit)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.AdminAuditEntry
+	for rows.Next() {
+		var (
+			e      domain.AdminAuditEntry
+			action string
+		)
+		if err := rows.Scan(&e.ID, &e.ActorID, &action, &e.TargetUserID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, mapPgError(err)
+		}
+		e.Action = domain.AdminAction(action)
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
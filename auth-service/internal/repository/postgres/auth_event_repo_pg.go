@@ -0,0 +1,91 @@
+package postgres
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type AuthEventRepo struct {
+	q querier
+}
+
+func NewAuthEventRepoFromPool(q querier) *AuthEventRepo {
+	return &AuthEventRepo{q: q}
+}
+
+func NewAuthEventRepoFromTx(tx pgx.Tx) *AuthEventRepo {
+	return &AuthEventRepo{q: tx}
+}
+
+func (r *AuthEventRepo) Add(ctx context.Context, e *domain.AuthEvent) (domain.AuthEventID, error) {
+	details := e.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	var id int64
+	err := r.q.QueryRow(ctx, queries.QueryAddAuthEvent,
+		string(e.Type),
+		e.UserID,
+		e.ActorID,
+		ipText(e.IP),
+		toNullStringPtr(e.UserAgent),
+		toNullStringPtr(e.RequestID),
+		details,
+		e.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+
+	return domain.AuthEventID(id), nil
+}
+
+func (r *AuthEventRepo) List(ctx context.Context, f domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	var types []string
+	for _, t := range f.Types {
+		types = append(types, string(t))
+	}
+	rows, err := r.q.Query(ctx, queries.QueryListAuthEvents, f.UserID, types, ipText(f.IP), f.BeforeID, f.Limit)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.AuthEvent
+	for rows.Next() {
+		var (
+			e       domain.AuthEvent
+			typ     string
+			ipValue *string
+		)
+		if err := rows.Scan(&e.ID, &typ, &e.UserID, &e.ActorID, &ipValue, &e.UserAgent, &e.RequestID, &e.Details, &e.CreatedAt); err != nil {
+			return nil, mapPgError(err)
+		}
+		e.Type = domain.AuthEventType(typ)
+		if ipValue != nil {
+			if addr, perr := netip.ParseAddr(*ipValue); perr == nil {
+				e.IP = &addr
+			}
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
+
+func ipText(ip *netip.Addr) *string {
+	if ip == nil || !ip.IsValid() {
+		return nil
+	}
+	s := ip.String()
+
+	return &s
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type IdentityRepo struct {
+	q querier
+}
+
+func NewIdentityRepoFromPool(q querier) *IdentityRepo {
+	return &IdentityRepo{q: q}
+}
+
+func NewIdentityRepoFromTx(tx pgx.Tx) *IdentityRepo {
+	return &IdentityRepo{q: tx}
+}
+
+func (r *IdentityRepo) Create(ctx context.Context, i *domain.Identity) (domain.IdentityID, error) {
+	var id int64
+	err := r.q.QueryRow(ctx, queries.QueryCreateIdentity, i.UserID, i.Provider, i.Subject, i.Email, i.CreatedAt).Scan(&id)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return domain.IdentityID(id), nil
+}
+
+func (r *IdentityRepo) GetBySubject(ctx context.Context, provider, subject string) (*domain.Identity, error) {
+	i, err := scanIdentity(r.q.QueryRow(ctx, queries.QueryGetIdentityBySubject, provider, subject))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	return i, nil
+}
+
+func (r *IdentityRepo) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Identity, error) {
+	rows, err := r.q.Query(ctx, queries.QueryListIdentitiesByUser, userID)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.Identity
+	for rows.Next() {
+		i, err := scanIdentity(rows)
+		if err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, *i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+	return out, nil
+}
+
+func (r *IdentityRepo) TouchLogin(ctx context.Context, id domain.IdentityID, now time.Time) error {
+	tag, err := r.q.Exec(ctx, queries.QueryTouchIdentityLogin, id, now)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *IdentityRepo) Delete(ctx context.Context, userID domain.UserID, provider string) error {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteIdentity, userID, provider)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanIdentity(row pgx.Row) (*domain.Identity, error) {
+	var (
+		i      domain.Identity
+		id     int64
+		userID int64
+	)
+	if err := row.Scan(&id, &userID, &i.Provider, &i.Subject, &i.Email, &i.CreatedAt, &i.LastLoginAt); err != nil {
+		return nil, err
+	}
+	i.ID = domain.IdentityID(id)
+	i.UserID = domain.UserID(userID)
+
+	return &i, nil
+}
+
+type OIDCStateRepo struct {
+	q querier
+}
+
+func NewOIDCStateRepoFromPool(q querier) *OIDCStateRepo {
+	return &OIDCStateRepo{q: q}
+}
+
+func NewOIDCStateRepoFromTx(tx pgx.Tx) *OIDCStateRepo {
+	return &OIDCStateRepo{q: tx}
+}
+
+func (r *OIDCStateRepo) Create(ctx context.Context, s *domain.OIDCState) error {
+	_, err := r.q.Exec(ctx, queries.QueryCreateOIDCState, s.StateHash, s.Provider, s.Nonce, s.CodeVerifier, s.UserID, s.ExpiresAt, s.CreatedAt)
+	if err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+// Take — DELETE ... RETURNING: повторный возврат с тем же state не пройдет.
+func (r *OIDCStateRepo) Take(ctx context.Context, stateHash string) (*domain.OIDCState, error) {
+	var (
+		s      domain.OIDCState
+		userID *int64
+	)
+	err := r.q.QueryRow(ctx, queries.QueryTakeOIDCState, stateHash).Scan(
+		&s.StateHash,
+		&s.Provider,
+		&s.Nonce,
+		&s.CodeVerifier,
+		&userID,
+		&s.ExpiresAt,
+		&s.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	if userID != nil {
+		uid := domain.UserID(*userID)
+		s.UserID = &uid
+	}
+
+	return &s, nil
+}
+
+func (r *OIDCStateRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteExpiredOIDCStates, now)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return int64(tag.RowsAffected()), nil
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type LoginFailuresRepo struct {
+	q querier
+}
+
+func NewLoginFailuresRepoFromPool(q querier) *LoginFailuresRepo {
+	return &LoginFailuresRepo{q: q}
+}
+
+func NewLoginFailuresRepoFromTx(tx pgx.Tx) *LoginFailuresRepo {
+	return &LoginFailuresRepo{q: tx}
+}
+
+// Get — счетчик неудачных входов по ключу.
+func (r *LoginFailuresRepo) Get(ctx context.Context, scope domain.LoginScope, key string) (*domain.LoginFailures, error) {
+	return r.scanOne(r.q.QueryRow(ctx, queries.QueryGetLoginFailures, scope, key))
+}
+
+// RegisterFailure — +1 к счетчику (или 1, если прошлая неудача старше resetBefore).
+func (r *LoginFailuresRepo) RegisterFailure(ctx context.Context, scope domain.LoginScope, key string, now, resetBefore time.Time) (*domain.LoginFailures, error) {
+	return r.scanOne(r.q.QueryRow(ctx, queries.QueryRegisterLoginFailure, scope, key, now, resetBefore))
+}
+
+func (r *LoginFailuresRepo) Lock(ctx context.Context, scope domain.LoginScope, key string, until time.Time) error {
+	tag, err := r.q.Exec(ctx, queries.QueryLockLogin, scope, key, until)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *LoginFailuresRepo) Reset(ctx context.Context, scope domain.LoginScope, key string) error {
+	if _, err := r.q.Exec(ctx, queries.QueryResetLoginFailures, scope, key); err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *LoginFailuresRepo) DeleteStale(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteStaleLoginFailures, before)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return int64(tag.RowsAffected()), nil
+}
+
+func (r *LoginFailuresRepo) scanOne(row pgx.Row) (*domain.LoginFailures, error) {
+	var (
+		f     domain.LoginFailures
+		scope string
+	)
+	if err := row.Scan(&scope, &f.Key, &f.Failures, &f.LastFailedAt, &f.LockedUntil); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	f.Scope = domain.LoginScope(scope)
+
+	return &f, nil
+}
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type MFARepo struct {
+	q querier
+}
+
+func NewMFARepoFromPool(q querier) *MFARepo {
+	return &MFARepo{q: q}
+}
+
+func NewMFARepoFromTx(tx pgx.Tx) *MFARepo {
+	return &MFARepo{q: tx}
+}
+
+func (r *MFARepo) GetTOTP(ctx context.Context, userID domain.UserID) (*domain.TOTPState, error) {
+	var st domain.TOTPState
+	err := r.q.QueryRow(ctx, queries.QueryGetUserTOTP, userID).Scan(&st.SecretEnc, &st.EnabledAt, &st.LastStep)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	return &st, nil
+}
+
+func (r *MFARepo) SetPendingTOTP(ctx context.Context, userID domain.UserID, secretEnc string, now time.Time) error {
+	return r.execOne(ctx, queries.QuerySetPendingTOTP, userID, secretEnc, now)
+}
+
+func (r *MFARepo) EnableTOTP(ctx context.Context, userID domain.UserID, step int64, now time.Time) error {
+	return r.execOne(ctx, queries.QueryEnableTOTP, userID, step, now)
+}
+
+func (r *MFARepo) DisableTOTP(ctx context.Context, userID domain.UserID, now time.Time) error {
+	return r.execOne(ctx, queries.QueryDisableTOTP, userID, now)
+}
+
+// AdvanceTOTPStep — условный UPDATE: из двух параллельных запросов с одним кодом пройдет только один.
+func (r *MFARepo) AdvanceTOTPStep(ctx context.Context, userID domain.UserID, step int64) (bool, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryAdvanceTOTPStep, userID, step)
+	if err != nil {
+		return false, mapPgError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ReplaceRecoveryCodes — атомарно только внутри транзакции (см. TxRunner).
+func (r *MFARepo) ReplaceRecoveryCodes(ctx context.Context, userID domain.UserID, hashes []string, now time.Time) error {
+	if err := r.DeleteRecoveryCodes(ctx, userID); err != nil {
+		return err
+	}
+	for _, h := range hashes {
+		if _, err := r.q.Exec(ctx, queries.QueryInsertRecoveryCode, userID, h, now); err != nil {
+			return mapPgError(err)
+		}
+	}
+	return nil
+}
+
+func (r *MFARepo) UseRecoveryCode(ctx context.Context, userID domain.UserID, hash string, now time.Time) (bool, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryUseRecoveryCode, userID, hash, now)
+	if err != nil {
+		return false, mapPgError(err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func (r *MFARepo) DeleteRecoveryCodes(ctx context.Context, userID domain.UserID) error {
+	if _, err := r.q.Exec(ctx, queries.QueryDeleteRecoveryCodes, userID); err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *MFARepo) execOne(ctx context.Context, sql string, args ...any) error {
+	tag, err := r.q.Exec(ctx, sql, args...)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+type MFAChallengeRepo struct {
+	q querier
+}
+
+func NewMFAChallengeRepoFromPool(q querier) *MFAChallengeRepo {
+	return &MFAChallengeRepo{q: q}
+}
+
+func NewMFAChallengeRepoFromTx(tx pgx.Tx) *MFAChallengeRepo {
+	return &MFAChallengeRepo{q: tx}
+}
+
+func (r *MFAChallengeRepo) Create(ctx context.Context, c *domain.MFAChallenge) error {
+	_, err := r.q.Exec(ctx, queries.QueryCreateMFAChallenge, c.TokenHash, c.UserID, c.ExpiresAt, c.CreatedAt)
+	if err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *MFAChallengeRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.MFAChallenge, error) {
+	var (
+		c      domain.MFAChallenge
+		userID int64
+	)
+	err := r.q.QueryRow(ctx, queries.QueryGetMFAChallenge, tokenHash).Scan(
+		&c.TokenHash,
+		&userID,
+		&c.ExpiresAt,
+		&c.Attempts,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	c.UserID = domain.UserID(userID)
+
+	return &c, nil
+}
+
+func (r *MFAChallengeRepo) IncrementAttempts(ctx context.Context, tokenHash string) (int, error) {
+	var n int
+	if err := r.q.QueryRow(ctx, queries.QueryIncrementMFAChallengeAttempts, tokenHash).Scan(&n); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+		return 0, mapPgError(err)
+	}
+	return n, nil
+}
+
+func (r *MFAChallengeRepo) Delete(ctx context.Context, tokenHash string) error {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteMFAChallenge, tokenHash)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MFAChallengeRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteExpiredMFAChallenges, now)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return int64(tag.RowsAffected()), nil
+}
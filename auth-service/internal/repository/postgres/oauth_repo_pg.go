@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type OAuthClientRepo struct {
+	q querier
+}
+
+func NewOAuthClientRepoFromPool(q querier) *OAuthClientRepo {
+	return &OAuthClientRepo{q: q}
+}
+
+func (r *OAuthClientRepo) Get(ctx context.Context, id string) (*domain.OAuthClient, error) {
+	var c domain.OAuthClient
+	err := r.q.QueryRow(ctx, queries.QueryGetOAuthClient, id).Scan(
+		&c.ID,
+		&c.SecretHash,
+		&c.Name,
+		&c.RedirectURIs,
+		&c.Scopes,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	return &c, nil
+}
+
+type OAuthGrantRepo struct {
+	q querier
+}
+
+func NewOAuthGrantRepoFromPool(q querier) *OAuthGrantRepo {
+	return &OAuthGrantRepo{q: q}
+}
+
+func NewOAuthGrantRepoFromTx(tx pgx.Tx) *OAuthGrantRepo {
+	return &OAuthGrantRepo{q: tx}
+}
+
+func (r *OAuthGrantRepo) CreateCode(ctx context.Context, c *domain.OAuthCode) error {
+	_, err := r.q.Exec(
+		ctx,
+		queries.QueryCreateOAuthCode,
+		c.CodeHash,
+		c.ClientID,
+		c.UserID,
+		c.RedirectURI,
+		c.Scopes,
+		c.Nonce,
+		c.CodeChallenge,
+		c.AuthTime,
+		c.ExpiresAt,
+		c.CreatedAt,
+	)
+	if err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+// TakeCode — DELETE ... RETURNING: из двух параллельных обменов пройдет только один.
+func (r *OAuthGrantRepo) TakeCode(ctx context.Context, codeHash string) (*domain.OAuthCode, error) {
+	var (
+		c      domain.OAuthCode
+		userID int64
+	)
+	err := r.q.QueryRow(ctx, queries.QueryTakeOAuthCode, codeHash).Scan(
+		&c.CodeHash,
+		&c.ClientID,
+		&userID,
+		&c.RedirectURI,
+		&c.Scopes,
+		&c.Nonce,
+		&c.CodeChallenge,
+		&c.AuthTime,
+		&c.ExpiresAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	c.UserID = domain.UserID(userID)
+
+	return &c, nil
+}
+
+func (r *OAuthGrantRepo) CreateRefreshToken(ctx context.Context, t *domain.OAuthRefreshToken) error {
+	_, err := r.q.Exec(
+		ctx,
+		queries.QueryCreateOAuthRefreshToken,
+		t.TokenHash,
+		t.ClientID,
+		t.UserID,
+		t.Scopes,
+		t.AuthTime,
+		t.ExpiresAt,
+		t.CreatedAt,
+	)
+	if err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *OAuthGrantRepo) TakeRefreshToken(ctx context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	var (
+		t      domain.OAuthRefreshToken
+		userID int64
+	)
+	err := r.q.QueryRow(ctx, queries.QueryTakeOAuthRefreshToken, tokenHash).Scan(
+		&t.TokenHash,
+		&t.ClientID,
+		&userID,
+		&t.Scopes,
+		&t.AuthTime,
+		&t.ExpiresAt,
+		&t.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	t.UserID = domain.UserID(userID)
+
+	return &t, nil
+}
+
+func (r *OAuthGrantRepo) GetConsent(ctx context.Context, userID domain.UserID, clientID string) ([]string, error) {
+	var scopes []string
+	if err := r.q.QueryRow(ctx, queries.QueryGetOAuthConsent, userID, clientID).Scan(&scopes); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	return scopes, nil
+}
+
+func (r *OAuthGrantRepo) SaveConsent(ctx context.Context, userID domain.UserID, clientID string, scopes []string, now time.Time) error {
+	if _, err := r.q.Exec(ctx, queries.QuerySaveOAuthConsent, userID, clientID, scopes, now); err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *OAuthGrantRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	codes, err := r.q.Exec(ctx, queries.QueryDeleteExpiredOAuthCodes, now)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	tokens, err := r.q.Exec(ctx, queries.QueryDeleteExpiredOAuthRefreshTokens, now)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return codes.RowsAffected() + tokens.RowsAffected(), nil
+}
@@ -0,0 +1,207 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type PasskeyRepo struct {
+	q querier
+}
+
+func NewPasskeyRepoFromPool(q querier) *PasskeyRepo {
+	return &PasskeyRepo{q: q}
+}
+
+func NewPasskeyRepoFromTx(tx pgx.Tx) *PasskeyRepo {
+	return &PasskeyRepo{q: tx}
+}
+
+func (r *PasskeyRepo) Create(ctx context.Context, p *domain.Passkey) (domain.PasskeyID, error) {
+	transports := p.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+	var id int64
+	err := r.q.QueryRow(
+		ctx,
+		queries.QueryCreatePasskey,
+		p.UserID,
+		p.CredentialID,
+		p.PublicKey,
+		p.AttestationType,
+		transports,
+		p.AAGUID,
+		int64(p.SignCount),
+		p.BackupEligible,
+		p.BackupState,
+		p.Name,
+		p.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return domain.PasskeyID(id), nil
+}
+
+func (r *PasskeyRepo) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Passkey, error) {
+	rows, err := r.q.Query(ctx, queries.QueryListPasskeysByUser, userID)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.Passkey
+	for rows.Next() {
+		p, err := scanPasskey(rows)
+		if err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+	return out, nil
+}
+
+func (r *PasskeyRepo) GetByCredentialID(ctx context.Context, credentialID []byte) (*domain.Passkey, error) {
+	p, err := scanPasskey(r.q.QueryRow(ctx, queries.QueryGetPasskeyByCredentialID, credentialID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	return p, nil
+}
+
+func (r *PasskeyRepo) UpdateUsage(ctx context.Context, id domain.PasskeyID, signCount uint32, backupState bool, now time.Time) error {
+	tag, err := r.q.Exec(ctx, queries.QueryUpdatePasskeyUsage, id, int64(signCount), backupState, now)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) Rename(ctx context.Context, userID domain.UserID, id domain.PasskeyID, name string) error {
+	tag, err := r.q.Exec(ctx, queries.QueryRenamePasskey, userID, id, name)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *PasskeyRepo) Delete(ctx context.Context, userID domain.UserID, id domain.PasskeyID) error {
+	tag, err := r.q.Exec(ctx, queries.QueryDeletePasskey, userID, id)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanPasskey(row pgx.Row) (*domain.Passkey, error) {
+	var (
+		p         domain.Passkey
+		id        int64
+		userID    int64
+		signCount int64
+	)
+	err := row.Scan(
+		&id,
+		&userID,
+		&p.CredentialID,
+		&p.PublicKey,
+		&p.AttestationType,
+		&p.Transports,
+		&p.AAGUID,
+		&signCount,
+		&p.BackupEligible,
+		&p.BackupState,
+		&p.Name,
+		&p.CreatedAt,
+		&p.LastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = domain.PasskeyID(id)
+	p.UserID = domain.UserID(userID)
+	p.SignCount = uint32(signCount)
+
+	return &p, nil
+}
+
+type WebAuthnCeremonyRepo struct {
+	q querier
+}
+
+func NewWebAuthnCeremonyRepoFromPool(q querier) *WebAuthnCeremonyRepo {
+	return &WebAuthnCeremonyRepo{q: q}
+}
+
+func NewWebAuthnCeremonyRepoFromTx(tx pgx.Tx) *WebAuthnCeremonyRepo {
+	return &WebAuthnCeremonyRepo{q: tx}
+}
+
+func (r *WebAuthnCeremonyRepo) Create(ctx context.Context, c *domain.WebAuthnCeremony) error {
+	_, err := r.q.Exec(ctx, queries.QueryCreateWebAuthnCeremony, c.IDHash, string(c.Kind), c.UserID, c.Session, c.ExpiresAt, c.CreatedAt)
+	if err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+// Take — DELETE ... RETURNING: из двух параллельных завершений пройдет только одно.
+func (r *WebAuthnCeremonyRepo) Take(ctx context.Context, idHash string) (*domain.WebAuthnCeremony, error) {
+	var (
+		c      domain.WebAuthnCeremony
+		kind   string
+		userID *int64
+	)
+	err := r.q.QueryRow(ctx, queries.QueryTakeWebAuthnCeremony, idHash).Scan(
+		&c.IDHash,
+		&kind,
+		&userID,
+		&c.Session,
+		&c.ExpiresAt,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+	c.Kind = domain.WebAuthnCeremonyKind(kind)
+	if userID != nil {
+		uid := domain.UserID(*userID)
+		c.UserID = &uid
+	}
+
+	return &c, nil
+}
+
+func (r *WebAuthnCeremonyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteExpiredWebAuthnCeremonies, now)
+	if err != nil {
+		return 0, mapPgError(err)
+	}
+	return int64(tag.RowsAffected()), nil
+}
@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/repository/queries"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type RoleRepo struct {
+	q querier
+}
+
+func NewRoleRepoFromPool(q querier) *RoleRepo {
+	return &RoleRepo{q: q}
+}
+
+func NewRoleRepoFromTx(tx pgx.Tx) *RoleRepo {
+	return &RoleRepo{q: tx}
+}
+
+func (r *RoleRepo) List(ctx context.Context) ([]domain.Role, error) {
+	return r.list(ctx, queries.QueryListRoles)
+}
+
+func (r *RoleRepo) ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Role, error) {
+	return r.list(ctx, queries.QueryListUserRoles, userID)
+}
+
+func (r *RoleRepo) Assign(ctx context.Context, userID domain.UserID, role string, now time.Time) error {
+	if _, err := r.q.Exec(ctx, queries.QueryAssignRole, userID, role, now); err != nil {
+		return mapRoleError(err)
+	}
+	return nil
+}
+
+func (r *RoleRepo) NamesByUsers(ctx context.Context, userIDs []domain.UserID) (map[domain.UserID][]string, error) {
+	rows, err := r.q.Query(ctx, queries.QueryUserRoleNames, userIDs)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	out := make(map[domain.UserID][]string, len(userIDs))
+	for rows.Next() {
+		var (
+			id   int64
+			role string
+		)
+		if err := rows.Scan(&id, &role); err != nil {
+			return nil, mapPgError(err)
+		}
+		out[domain.UserID(id)] = append(out[domain.UserID(id)], role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
+
+// Replace - два запроса; атомарность - за вызывающим (NewRoleRepoFromTx)
+func (r *RoleRepo) Replace(ctx context.Context, userID domain.UserID, roles []string, now time.Time) error {
+	if roles == nil {
+		roles = []string{}
+	}
+	if _, err := r.q.Exec(ctx, queries.QueryRevokeOtherRoles, userID, roles); err != nil {
+		return mapPgError(err)
+	}
+	if _, err := r.q.Exec(ctx, queries.QueryAssignRoles, userID, roles, now); err != nil {
+		return mapRoleError(err)
+	}
+	return nil
+}
+
+// mapRoleError - роли нет в roles (нарушение FK) - ErrNotFound
+func mapRoleError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "user_roles_role_fkey" {
+		return repository.ErrNotFound
+	}
+	return mapPgError(err)
+}
+
+func (r *RoleRepo) list(ctx context.Context, query string, args ...any) ([]domain.Role, error) {
+	rows, err := r.q.Query(ctx, query, args...)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.Role
+	for rows.Next() {
+		var role domain.Role
+		if err := rows.Scan(&role.Name, &role.Description, &role.Permissions, &role.CreatedAt); err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
@@ -54,6 +54,41 @@ func (r *SessionRepo) Create(ctx context.Context, s *domain.Session) (domain.Ses
 
 // GetByTokenHash — ищет сессию по точному хешу refresh-токена.
 func (r *SessionRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error) {
+	s, err := scanSession(r.q.QueryRow(ctx, queries.QueryGetSessionByTokenHash, strings.TrimSpace(tokenHash)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, repository.ErrNotFound
+		}
+		return nil, mapPgError(err)
+	}
+
+	return s, nil
+}
+
+// ListByUser — живые сессии пользователя, новые первыми.
+func (r *SessionRepo) ListByUser(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.Session, error) {
+	rows, err := r.q.Query(ctx, queries.QueryListSessionsByUser, userID, now)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.Session
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
+
+func scanSession(row pgx.Row) (*domain.Session, error) {
 	var (
 		id        int64
 		userID    int64
@@ -64,22 +99,8 @@ func (r *SessionRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*do
 		userAgent *string
 		ipText    *string
 	)
-
-	err := r.q.QueryRow(ctx, queries.QueryGetSessionByTokenHash, strings.TrimSpace(tokenHash)).Scan(
-		&id,
-		&userID,
-		&hash,
-		&expiresAt,
-		&createdAt,
-		&updatedAt,
-		&userAgent,
-		&ipText,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, repository.ErrNotFound
-		}
-		return nil, mapPgError(err)
+	if err := row.Scan(&id, &userID, &hash, &expiresAt, &createdAt, &updatedAt, &userAgent, &ipText); err != nil {
+		return nil, err
 	}
 
 	var ipPtr *netip.Addr
@@ -112,6 +133,19 @@ func (r *SessionRepo) DeleteByID(ctx context.Context, id domain.SessionID) error
 	return nil
 }
 
+// DeleteByTokenHash — одна строка DELETE: из параллельных обменов одного refresh-токена
+// строку получит только один, остальные — ErrNotFound.
+func (r *SessionRepo) DeleteByTokenHash(ctx context.Context, tokenHash string) error {
+	tag, err := r.q.Exec(ctx, queries.QueryDeleteSessionByTokenHash, strings.TrimSpace(tokenHash))
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
 func (r *SessionRepo) DeleteByUser(ctx context.Context, userID domain.UserID) (int64, error) {
 	const sql = `DELETE FROM auth_sessions WHERE user_id = $1;`
 	tag, err := r.q.Exec(ctx, sql, userID)
@@ -128,3 +162,22 @@ func (r *SessionRepo) DeleteExpired(ctx context.Context, now time.Time) (int64,
 	}
 	return int64(tag.RowsAffected()), nil
 }
+
+func (r *SessionRepo) AddRotated(ctx context.Context, tokenHash string, userID domain.UserID, expiresAt time.Time) error {
+	if _, err := r.q.Exec(ctx, queries.QueryAddRotatedToken, strings.TrimSpace(tokenHash), userID, expiresAt); err != nil {
+		return mapPgError(err)
+	}
+	return nil
+}
+
+func (r *SessionRepo) GetRotatedOwner(ctx context.Context, tokenHash string) (domain.UserID, error) {
+	var userID int64
+	err := r.q.QueryRow(ctx, queries.QueryGetRotatedTokenOwner, strings.TrimSpace(tokenHash)).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, repository.ErrNotFound
+		}
+		return 0, mapPgError(err)
+	}
+	return domain.UserID(userID), nil
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/auth-service/internal/pg"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// EventSource - источник событий auth-service в outbox
+const EventSource = "auth-service"
+
+type OutboxRepo struct {
+	q querier
+}
+
+func NewOutboxRepoFromTx(tx pgx.Tx) *OutboxRepo {
+	return &OutboxRepo{q: tx}
+}
+
+func (r *OutboxRepo) Add(ctx context.Context, e events.Event) error {
+	if _, err := outbox.Add(ctx, r.q, EventSource, e); err != nil {
+		return mapPgError(err)
+	}
+
+	return nil
+}
+
+type TxRunner struct {
+	pool *pgxpool.Pool
+}
+
+func NewTxRunner(pool *pgxpool.Pool) *TxRunner {
+	return &TxRunner{pool: pool}
+}
+
+func (r *TxRunner) InTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	return pg.WithTx(ctx, r.pool, func(tx pgx.Tx) error {
+		return fn(txRepos{tx: tx})
+	})
+}
+
+type txRepos struct {
+	tx pgx.Tx
+}
+
+func (t txRepos) Users() repository.UserRepository    { return NewUserRepoFromTx(t.tx) }
+func (t txRepos) Outbox() repository.OutboxRepository { return NewOutboxRepoFromTx(t.tx) }
+func (t txRepos) MFA() repository.MFARepository       { return NewMFARepoFromTx(t.tx) }
+func (t txRepos) Identities() repository.IdentityRepository {
+	return NewIdentityRepoFromTx(t.tx)
+}
+func (t txRepos) Roles() repository.RoleRepository { return NewRoleRepoFromTx(t.tx) }
+func (t txRepos) AdminAudit() repository.AdminAuditRepository {
+	return NewAdminAuditRepoFromTx(t.tx)
+}
+func (t txRepos) AuthEvents() repository.AuthEventRepository {
+	return NewAuthEventRepoFromTx(t.tx)
+}
+func (t txRepos) Sessions() repository.SessionRepository {
+	return NewSessionRepoFromTx(t.tx)
+}
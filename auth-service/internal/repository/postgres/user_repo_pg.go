@@ -80,6 +80,15 @@ func (r *UserRepo) UpdatePasswordHash(ctx context.Context, id domain.UserID, new
 	return nil
 }
 
+func (r *UserRepo) RehashPassword(ctx context.Context, id domain.UserID, oldHash, newHash string) (bool, error) {
+	tag, err := r.q.Exec(ctx, queries.QueryRehashPassword, id, oldHash, strings.TrimSpace(newHash))
+	if err != nil {
+		return false, mapPgError(err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
 func (r *UserRepo) UpdateProfile(ctx context.Context, id domain.UserID, displayName *string, avatarURL *string, now time.Time) error {
 	setParts := make([]string, 0, 3)
 	args := make([]any, 0, 4)
@@ -130,3 +139,85 @@ func (r *UserRepo) MarkEmailVerified(ctx context.Context, id domain.UserID, now
 
 	return nil
 }
+
+func (r *UserRepo) Search(ctx context.Context, q domain.UserSearch) ([]domain.User, error) {
+	pattern := ""
+	if t := strings.TrimSpace(q.Query); t != "" {
+		pattern = "%" + escapeLike(t) + "%"
+	}
+	rows, err := r.q.Query(ctx, queries.QuerySearchUsers, pattern, q.AfterID, q.Limit)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, *u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
+
+func (r *UserRepo) SetDisabled(ctx context.Context, id domain.UserID, disabledAt *time.Time, now time.Time) error {
+	return r.execOne(ctx, queries.QuerySetUserDisabled, id, disabledAt, now)
+}
+
+func (r *UserRepo) RequirePasswordReset(ctx context.Context, id domain.UserID, now time.Time) error {
+	return r.execOne(ctx, queries.QueryRequirePasswordReset, id, now)
+}
+
+func (r *UserRepo) ScheduleDeletion(ctx context.Context, id domain.UserID, at *time.Time, now time.Time) error {
+	return r.execOne(ctx, queries.QueryScheduleUserDeletion, id, at, now)
+}
+
+func (r *UserRepo) ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserID, error) {
+	rows, err := r.q.Query(ctx, queries.QueryListDueUserDeletions, now, limit)
+	if err != nil {
+		return nil, mapPgError(err)
+	}
+	defer rows.Close()
+
+	var out []domain.UserID
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, mapPgError(err)
+		}
+		out = append(out, domain.UserID(id))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapPgError(err)
+	}
+
+	return out, nil
+}
+
+func (r *UserRepo) Delete(ctx context.Context, id domain.UserID) error {
+	return r.execOne(ctx, queries.QueryDeleteUser, id)
+}
+
+// execOne - UPDATE/DELETE одной строки; не нашли - ErrNotFound
+func (r *UserRepo) execOne(ctx context.Context, sql string, args ...any) error {
+	tag, err := r.q.Exec(ctx, sql, args...)
+	if err != nil {
+		return mapPgError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+
+	return nil
+}
+
+// escapeLike - %, _ и \ из запроса ищутся как обычные символы
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
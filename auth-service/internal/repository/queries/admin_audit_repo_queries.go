@@ -0,0 +1,19 @@
+package queries
+
+const (
+	QueryAddAdminAudit = `
+		INSERT INTO admin_audit_log (actor_id, action, target_user_id, details, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`
+	// $1 - actor_id, $2 - target_user_id (NULL - любой), $3 - id < $3 (0 - без курсора)
+	QueryListAdminAudit = `
+		SELECT id, actor_id, action, target_user_id, details, created_at
+		FROM admin_audit_log
+		WHERE ($1::bigint IS NULL OR actor_id = $1)
+		  AND ($2::bigint IS NULL OR target_user_id = $2)
+		  AND ($3::bigint = 0 OR id < $3)
+		ORDER BY id DESC
+		LIMIT $4;
+	`
+)
@@ -0,0 +1,23 @@
+package queries
+
+const (
+	QueryAddAuthEvent = `
+		INSERT INTO auth_audit_events (type, user_id, actor_id, ip, user_agent, request_id, details, created_at)
+		VALUES ($1, $2, $3, $4::inet, $5, $6, $7, $8)
+		RETURNING id;
+	`
+	// $1 - user_id (NULL - любой), $2 - типы (NULL - все), $3 - ip (NULL - любой), $4 - id < $4 (0 - без курсора)
+	QueryListAuthEvents = `
+		SELECT
+			id, type, user_id, actor_id,
+			CASE WHEN ip IS NULL THEN NULL ELSE host(ip) END AS ip_text,
+			user_agent, request_id, details, created_at
+		FROM auth_audit_events
+		WHERE ($1::bigint IS NULL OR user_id = $1)
+		  AND ($2::text[] IS NULL OR type = ANY($2))
+		  AND ($3::inet IS NULL OR ip = $3)
+		  AND ($4::bigint = 0 OR id < $4)
+		ORDER BY id DESC
+		LIMIT $5;
+	`
+)
@@ -0,0 +1,33 @@
+package queries
+
+const (
+	QueryCreateIdentity = `
+		INSERT INTO user_identities (user_id, provider, subject, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`
+	QueryGetIdentityBySubject = `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE provider = $1 AND subject = $2;
+	`
+	QueryListIdentitiesByUser = `
+		SELECT id, user_id, provider, subject, email, created_at, last_login_at
+		FROM user_identities
+		WHERE user_id = $1
+		ORDER BY created_at, id;
+	`
+	QueryTouchIdentityLogin = `UPDATE user_identities SET last_login_at = $2 WHERE id = $1;`
+	QueryDeleteIdentity     = `DELETE FROM user_identities WHERE user_id = $1 AND provider = $2;`
+
+	QueryCreateOIDCState = `
+		INSERT INTO oidc_auth_states (state_hash, provider, nonce, code_verifier, user_id, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	QueryTakeOIDCState = `
+		DELETE FROM oidc_auth_states
+		WHERE state_hash = $1
+		RETURNING state_hash, provider, nonce, code_verifier, user_id, expires_at, created_at;
+	`
+	QueryDeleteExpiredOIDCStates = `DELETE FROM oidc_auth_states WHERE expires_at <= $1;`
+)
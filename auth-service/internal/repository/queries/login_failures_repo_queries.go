@@ -0,0 +1,34 @@
+package queries
+
+const (
+	QueryGetLoginFailures = `
+		SELECT scope, key, failures, last_failed_at, locked_until
+		FROM auth_login_failures
+		WHERE scope = $1 AND key = $2;
+	`
+	QueryRegisterLoginFailure = `
+		INSERT INTO auth_login_failures (scope, key, failures, last_failed_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET failures = CASE
+				WHEN auth_login_failures.last_failed_at < $4 THEN 1
+				ELSE auth_login_failures.failures + 1
+			END,
+			locked_until = CASE
+				WHEN auth_login_failures.last_failed_at < $4 THEN NULL
+				ELSE auth_login_failures.locked_until
+			END,
+			last_failed_at = EXCLUDED.last_failed_at
+		RETURNING scope, key, failures, last_failed_at, locked_until;
+	`
+	QueryLockLogin = `
+		UPDATE auth_login_failures
+		SET locked_until = $3
+		WHERE scope = $1 AND key = $2;
+	`
+	QueryResetLoginFailures       = `DELETE FROM auth_login_failures WHERE scope = $1 AND key = $2;`
+	QueryDeleteStaleLoginFailures = `
+		DELETE FROM auth_login_failures
+		WHERE last_failed_at < $1 AND (locked_until IS NULL OR locked_until < $1);
+	`
+)
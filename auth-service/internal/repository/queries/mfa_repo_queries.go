@@ -0,0 +1,57 @@
+package queries
+
+const (
+	QueryGetUserTOTP = `
+		SELECT totp_secret_enc, totp_enabled_at, totp_last_step
+		FROM users
+		WHERE id = $1;
+	`
+	QuerySetPendingTOTP = `
+		UPDATE users
+		SET totp_secret_enc = $2, totp_enabled_at = NULL, totp_last_step = 0, updated_at = $3
+		WHERE id = $1;
+	`
+	QueryEnableTOTP = `
+		UPDATE users
+		SET totp_enabled_at = $3, totp_last_step = $2, updated_at = $3
+		WHERE id = $1 AND totp_secret_enc IS NOT NULL;
+	`
+	QueryDisableTOTP = `
+		UPDATE users
+		SET totp_secret_enc = NULL, totp_enabled_at = NULL, totp_last_step = 0, updated_at = $2
+		WHERE id = $1;
+	`
+	QueryAdvanceTOTPStep = `
+		UPDATE users
+		SET totp_last_step = $2
+		WHERE id = $1 AND totp_last_step < $2;
+	`
+	QueryDeleteRecoveryCodes = `DELETE FROM auth_recovery_codes WHERE user_id = $1;`
+	QueryInsertRecoveryCode  = `
+		INSERT INTO auth_recovery_codes (user_id, code_hash, created_at)
+		VALUES ($1, $2, $3);
+	`
+	QueryUseRecoveryCode = `
+		UPDATE auth_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL;
+	`
+
+	QueryCreateMFAChallenge = `
+		INSERT INTO auth_mfa_challenges (token_hash, user_id, expires_at, attempts, created_at)
+		VALUES ($1, $2, $3, 0, $4);
+	`
+	QueryGetMFAChallenge = `
+		SELECT token_hash, user_id, expires_at, attempts, created_at
+		FROM auth_mfa_challenges
+		WHERE token_hash = $1;
+	`
+	QueryIncrementMFAChallengeAttempts = `
+		UPDATE auth_mfa_challenges
+		SET attempts = attempts + 1
+		WHERE token_hash = $1
+		RETURNING attempts;
+	`
+	QueryDeleteMFAChallenge         = `DELETE FROM auth_mfa_challenges WHERE token_hash = $1;`
+	QueryDeleteExpiredMFAChallenges = `DELETE FROM auth_mfa_challenges WHERE expires_at <= $1;`
+)
@@ -0,0 +1,41 @@
+package queries
+
+const (
+	QueryGetOAuthClient = `
+		SELECT id, secret_hash, name, redirect_uris, scopes, created_at
+		FROM oauth_clients
+		WHERE id = $1;
+	`
+
+	QueryCreateOAuthCode = `
+		INSERT INTO oauth_authorization_codes (
+			code_hash, client_id, user_id, redirect_uri, scopes, nonce, code_challenge, auth_time, expires_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);
+	`
+	QueryTakeOAuthCode = `
+		DELETE FROM oauth_authorization_codes
+		WHERE code_hash = $1
+		RETURNING code_hash, client_id, user_id, redirect_uri, scopes, nonce, code_challenge, auth_time, expires_at, created_at;
+	`
+
+	QueryCreateOAuthRefreshToken = `
+		INSERT INTO oauth_refresh_tokens (token_hash, client_id, user_id, scopes, auth_time, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7);
+	`
+	QueryTakeOAuthRefreshToken = `
+		DELETE FROM oauth_refresh_tokens
+		WHERE token_hash = $1
+		RETURNING token_hash, client_id, user_id, scopes, auth_time, expires_at, created_at;
+	`
+
+	QueryGetOAuthConsent  = `SELECT scopes FROM oauth_consents WHERE user_id = $1 AND client_id = $2;`
+	QuerySaveOAuthConsent = `
+		INSERT INTO oauth_consents (user_id, client_id, scopes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (user_id, client_id) DO UPDATE
+		SET scopes = EXCLUDED.scopes, updated_at = EXCLUDED.updated_at;
+	`
+
+	QueryDeleteExpiredOAuthCodes         = `DELETE FROM oauth_authorization_codes WHERE expires_at <= $1;`
+	QueryDeleteExpiredOAuthRefreshTokens = `DELETE FROM oauth_refresh_tokens WHERE expires_at <= $1;`
+)
@@ -0,0 +1,42 @@
+package queries
+
+const (
+	QueryCreatePasskey = `
+		INSERT INTO webauthn_credentials (
+			user_id, credential_id, public_key, attestation_type, transports, aaguid,
+			sign_count, backup_eligible, backup_state, name, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id;
+	`
+	QueryListPasskeysByUser = `
+		SELECT id, user_id, credential_id, public_key, attestation_type, transports, aaguid,
+			sign_count, backup_eligible, backup_state, name, created_at, last_used_at
+		FROM webauthn_credentials
+		WHERE user_id = $1
+		ORDER BY created_at, id;
+	`
+	QueryGetPasskeyByCredentialID = `
+		SELECT id, user_id, credential_id, public_key, attestation_type, transports, aaguid,
+			sign_count, backup_eligible, backup_state, name, created_at, last_used_at
+		FROM webauthn_credentials
+		WHERE credential_id = $1;
+	`
+	QueryUpdatePasskeyUsage = `
+		UPDATE webauthn_credentials
+		SET sign_count = $2, backup_state = $3, last_used_at = $4
+		WHERE id = $1;
+	`
+	QueryRenamePasskey = `UPDATE webauthn_credentials SET name = $3 WHERE user_id = $1 AND id = $2;`
+	QueryDeletePasskey = `DELETE FROM webauthn_credentials WHERE user_id = $1 AND id = $2;`
+
+	QueryCreateWebAuthnCeremony = `
+		INSERT INTO webauthn_ceremonies (id_hash, kind, user_id, session, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6);
+	`
+	QueryTakeWebAuthnCeremony = `
+		DELETE FROM webauthn_ceremonies
+		WHERE id_hash = $1
+		RETURNING id_hash, kind, user_id, session, expires_at, created_at;
+	`
+	QueryDeleteExpiredWebAuthnCeremonies = `DELETE FROM webauthn_ceremonies WHERE expires_at <= $1;`
+)
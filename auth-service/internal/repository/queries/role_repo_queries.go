@@ -0,0 +1,36 @@
+package queries
+
+const (
+	QueryListRoles = `
+		SELECT name, description, permissions, created_at
+		FROM roles
+		ORDER BY name;
+	`
+	QueryListUserRoles = `
+		SELECT r.name, r.description, r.permissions, r.created_at
+		FROM user_roles ur
+		JOIN roles r ON r.name = ur.role
+		WHERE ur.user_id = $1
+		ORDER BY r.name;
+	`
+	QueryAssignRole = `
+		INSERT INTO user_roles (user_id, role, granted_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, role) DO NOTHING;
+	`
+	QueryUserRoleNames = `
+		SELECT user_id, role
+		FROM user_roles
+		WHERE user_id = ANY($1)
+		ORDER BY user_id, role;
+	`
+	QueryRevokeOtherRoles = `
+		DELETE FROM user_roles
+		WHERE user_id = $1 AND role <> ALL($2);
+	`
+	QueryAssignRoles = `
+		INSERT INTO user_roles (user_id, role, granted_at)
+		SELECT $1, unnest($2::text[]), $3
+		ON CONFLICT (user_id, role) DO NOTHING;
+	`
+)
@@ -16,7 +16,26 @@ const (
 		WHERE token_hash = $1
 		LIMIT 1;
 	`
+	QueryListSessionsByUser = `
+		SELECT
+			id, user_id, token_hash, expires_at, created_at, updated_at,
+			user_agent,
+			CASE WHEN ip IS NULL THEN NULL ELSE ip::text END AS ip_text
+		FROM auth_sessions
+		WHERE user_id = $1 AND expires_at > $2
+		ORDER BY created_at DESC, id DESC;
+	`
 	QueryDeleteSessionByID           = `DELETE FROM auth_sessions WHERE id = $1;`
+	QueryDeleteSessionByTokenHash    = `DELETE FROM auth_sessions WHERE token_hash = $1;`
 	QueryDeleteSessionByUser         = `DELETE FROM auth_sessions WHERE user_id = $1;`
-	QueryDeleteSessionsExpiredByTime = `DELETE FROM auth_sessions WHERE expires_at <= $1;`
+	QueryDeleteSessionsExpiredByTime = `
+		WITH rotated AS (DELETE FROM auth_rotated_refresh_tokens WHERE expires_at <= $1)
+		DELETE FROM auth_sessions WHERE expires_at <= $1;
+	`
+	QueryAddRotatedToken = `
+		INSERT INTO auth_rotated_refresh_tokens (token_hash, user_id, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token_hash) DO NOTHING;
+	`
+	QueryGetRotatedTokenOwner = `SELECT user_id FROM auth_rotated_refresh_tokens WHERE token_hash = $1;`
 )
@@ -7,24 +7,64 @@ const (
 		RETURNING id;
 	`
 	QueryGetUserByID = `
-		SELECT id, email, email_verified, password_hash, display_name, avatar_url, created_at, updated_at
+		SELECT id, email, email_verified, password_hash, display_name, avatar_url, created_at, updated_at,
+		       disabled_at, password_reset_required, deletion_scheduled_at
 		FROM users
 		WHERE id = $1;
 	`
 	QueryGetUserByEmail = `
-		SELECT id, email, email_verified, password_hash, display_name, avatar_url, created_at, updated_at
+		SELECT id, email, email_verified, password_hash, display_name, avatar_url, created_at, updated_at,
+		       disabled_at, password_reset_required, deletion_scheduled_at
 		FROM users
 		WHERE email = $1;
 	`
+	// $1 - шаблон ILIKE ('' - без фильтра)
+	QuerySearchUsers = `
+		SELECT id, email, email_verified, password_hash, display_name, avatar_url, created_at, updated_at,
+		       disabled_at, password_reset_required, deletion_scheduled_at
+		FROM users
+		WHERE ($1 = '' OR email::text ILIKE $1 OR display_name ILIKE $1)
+		  AND id > $2
+		ORDER BY id
+		LIMIT $3;
+	`
 	QueryExistsUserByEmail  = `SELECT 1 FROM users WHERE email = $1;`
 	QueryUpdatePasswordHash = `
 		UPDATE users
-		SET password_hash = $2, updated_at = $3
+		SET password_hash = $2, password_reset_required = FALSE, updated_at = $3
 		WHERE id = $1;
 	`
+	QueryRehashPassword = `
+		UPDATE users
+		SET password_hash = $3
+		WHERE id = $1 AND password_hash = $2;
+	`
 	QueryUpdateEmailVerified = `
 		UPDATE users
 		SET email_verified = TRUE, updated_at = $2
 		WHERE id = $1;
 	`
+	QuerySetUserDisabled = `
+		UPDATE users
+		SET disabled_at = $2, updated_at = $3
+		WHERE id = $1;
+	`
+	QueryRequirePasswordReset = `
+		UPDATE users
+		SET password_reset_required = TRUE, updated_at = $2
+		WHERE id = $1;
+	`
+	QueryScheduleUserDeletion = `
+		UPDATE users
+		SET deletion_scheduled_at = $2, updated_at = $3
+		WHERE id = $1;
+	`
+	QueryListDueUserDeletions = `
+		SELECT id
+		FROM users
+		WHERE deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at, id
+		LIMIT $2;
+	`
+	QueryDeleteUser = `DELETE FROM users WHERE id = $1;`
 )
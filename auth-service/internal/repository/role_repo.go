@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+)
+
+type RoleRepository interface {
+	// Все роли
+	List(ctx context.Context) ([]domain.Role, error)
+	// Роли пользователя
+	ListByUser(ctx context.Context, userID domain.UserID) ([]domain.Role, error)
+	// Выдает роль; уже выдана - ничего не делает, роли нет - ErrNotFound
+	Assign(ctx context.Context, userID domain.UserID, role string, now time.Time) error
+	// Имена ролей нескольких пользователей разом (страница поиска в админке)
+	NamesByUsers(ctx context.Context, userIDs []domain.UserID) (map[domain.UserID][]string, error)
+	// Оставляет пользователю ровно эти роли; неизвестная роль - ErrNotFound
+	Replace(ctx context.Context, userID domain.UserID, roles []string, now time.Time) error
+}
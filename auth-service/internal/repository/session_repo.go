@@ -12,10 +12,18 @@ type SessionRepository interface {
 	Create(ctx context.Context, s *domain.Session) (domain.SessionID, error)
 	// Ищет сессию по хешу refresh - токена
 	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Session, error)
+	// Активные (не просроченные на now) сессии пользователя, новые первыми
+	ListByUser(ctx context.Context, userID domain.UserID, now time.Time) ([]domain.Session, error)
 	// Удаляет запись сессии
 	DeleteByID(ctx context.Context, id domain.SessionID) error
+	// Удаляет сессию по хешу refresh-токена; ErrNotFound - ее уже нет (обменяна параллельно или отозвана)
+	DeleteByTokenHash(ctx context.Context, tokenHash string) error
 	// Удаляет все сессии пользователя
 	DeleteByUser(ctx context.Context, userID domain.UserID) (int64, error)
-	// Очистка просроченных сессий на момент now
+	// Очистка просроченных сессий (и обмененных токенов) на момент now
 	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+	// Запоминает хеш refresh-токена, который только что обменяли на новый
+	AddRotated(ctx context.Context, tokenHash string, userID domain.UserID, expiresAt time.Time) error
+	// Владелец уже обмененного refresh-токена; ErrNotFound - токен не обменивали
+	GetRotatedOwner(ctx context.Context, tokenHash string) (domain.UserID, error)
 }
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+// OutboxRepository — доменные события в outbox (таблица из миграций room-service,
+// доставкой занимается relay room-service).
+type OutboxRepository interface {
+	Add(ctx context.Context, e events.Event) error
+}
+
+// Tx — репозитории в рамках одной транзакции
+type Tx interface {
+	Users() UserRepository
+	Sessions() SessionRepository
+	Outbox() OutboxRepository
+	MFA() MFARepository
+	Identities() IdentityRepository
+	Roles() RoleRepository
+	AdminAudit() AdminAuditRepository
+	AuthEvents() AuthEventRepository
+}
+
+// TxRunner — атомарные операции над несколькими репозиториями
+type TxRunner interface {
+	InTx(ctx context.Context, fn func(tx Tx) error) error
+}
@@ -13,6 +13,20 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	UpdatePasswordHash(ctx context.Context, id domain.UserID, newHash string, now time.Time) error
+	// Пересчитанный хеш того же пароля; false - хеш уже сменился (пароль поменяли параллельно)
+	RehashPassword(ctx context.Context, id domain.UserID, oldHash, newHash string) (bool, error)
 	UpdateProfile(ctx context.Context, id domain.UserID, displayName *string, avatarURL *string, now time.Time) error
 	MarkEmailVerified(ctx context.Context, id domain.UserID, now time.Time) error
+	// Поиск по подстроке email или имени, по возрастанию id (для админки)
+	Search(ctx context.Context, q domain.UserSearch) ([]domain.User, error)
+	// Блокирует (disabledAt != nil) или разблокирует пользователя
+	SetDisabled(ctx context.Context, id domain.UserID, disabledAt *time.Time, now time.Time) error
+	// Требует сменить пароль перед следующим входом по паролю; снимается в UpdatePasswordHash
+	RequirePasswordReset(ctx context.Context, id domain.UserID, now time.Time) error
+	// Назначает удаление аккаунта на at (nil - отменить)
+	ScheduleDeletion(ctx context.Context, id domain.UserID, at *time.Time, now time.Time) error
+	// Аккаунты, срок удаления которых наступил к now
+	ListDueDeletions(ctx context.Context, now time.Time, limit int) ([]domain.UserID, error)
+	// Удаляет пользователя; сессии, MFA, passkeys, привязки и роли уходят каскадом по FK
+	Delete(ctx context.Context, id domain.UserID) error
 }
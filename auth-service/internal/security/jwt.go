@@ -2,10 +2,15 @@ package security
 
 import (
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cwrk-planet/auth-service/internal/domain"
@@ -39,12 +44,19 @@ func (s *JWTSigner) TTL() time.Duration {
 	return s.ttl
 }
 
+// TokenUseAccess - claim token_use access-токена платформы (authz.TokenUseAccess): api-gateway и room-service
+// принимают только его, остальные токены, подписанные тем же ключом, отличаются token_use
+const TokenUseAccess = "access"
+
 type AccessClaims struct {
-	jwt.StandardClaims // включает поля Issuer, Audience, ExpiresAt, NotBefore, IssuedAt, Subject
-	// todo: скорее всего надо буде добавить поля roles, emails и т.п.
+	jwt.StandardClaims          // включает поля Issuer, Audience, ExpiresAt, NotBefore, IssuedAt, Subject
+	TokenUse           string   `json:"token_use"`
+	Roles              []string `json:"roles,omitempty"`
+	Scope              string   `json:"scope,omitempty"` // права через пробел, как scope в OAuth 2.0
 }
 
-func (s *JWTSigner) SignAccessToken(userID domain.UserID, now time.Time) (string, error) {
+// SignAccessToken выпускает JWT с sub=userID, ролями и правами, exp=now+ttl
+func (s *JWTSigner) SignAccessToken(userID domain.UserID, roles, scopes []string, now time.Time) (string, error) {
 	claims := AccessClaims{
 		StandardClaims: jwt.StandardClaims{
 			Subject:   fmt.Sprint(int64(userID)),
@@ -54,13 +66,72 @@ func (s *JWTSigner) SignAccessToken(userID domain.UserID, now time.Time) (string
 			NotBefore: now.Add(-s.clockSkew).Unix(),
 			ExpiresAt: now.Add(s.ttl).Unix(),
 		},
+		TokenUse: TokenUseAccess,
+		Roles:    roles,
+		Scope:    strings.Join(scopes, " "),
 	}
+
+	return s.Sign(claims)
+}
+
+// Sign подписывает произвольные claims тем же ключом (ID token, токены OAuth-клиентов)
+func (s *JWTSigner) Sign(claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.KeyID()
 
 	return token.SignedString(s.private)
 }
 
-// SignAccessToken выпускает JWT с sub=userID и exp=now+ttl
+// Verify проверяет подпись RS256 и exp/nbf; iss и aud проверяет вызывающий
+func (s *JWTSigner) Verify(tokenStr string, claims jwt.Claims) error {
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, errs.ErrInvalidToken
+		}
+		return s.public, nil
+	})
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errs.ErrInvalidToken
+	}
+
+	return nil
+}
+
+// KeyID - kid ключа: RFC 7638 thumbprint, меняется вместе с ключом
+func (s *JWTSigner) KeyID() string {
+	// порядок полей по RFC 7638 - лексикографический
+	thumb := fmt.Sprintf(`{"e":"%s","kty":"RSA","n":"%s"}`, s.jwkE(), s.jwkN())
+	sum := sha256.Sum256([]byte(thumb))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// JWKS - публичный ключ в формате JSON Web Key Set
+func (s *JWTSigner) JWKS() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"use": "sig",
+			"alg": jwt.SigningMethodRS256.Alg(),
+			"kid": s.KeyID(),
+			"n":   s.jwkN(),
+			"e":   s.jwkE(),
+		}},
+	})
+}
+
+func (s *JWTSigner) jwkN() string {
+	return base64.RawURLEncoding.EncodeToString(s.public.N.Bytes())
+}
+
+func (s *JWTSigner) jwkE() string {
+	return base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.public.E)).Bytes())
+}
+
+// ParseAndValidate проверяет подпись, iss, aud и сроки access-токена
 func (s *JWTSigner) ParseAndValidate(tokenStr string) (*AccessClaims, error) {
 	claims := &AccessClaims{}
 	token, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
@@ -72,7 +143,7 @@ func (s *JWTSigner) ParseAndValidate(tokenStr string) (*AccessClaims, error) {
 	if err != nil {
 		return nil, err
 	}
-	if !token.Valid {
+	if !token.Valid || claims.TokenUse != TokenUseAccess {
 		return nil, errs.ErrInvalidToken
 	}
 
@@ -1,41 +1,226 @@
 package security
 
 import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+
 	"github.com/cwrk-planet/auth-service/internal/errs"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-// todo: такое надо перенести в config
-type BcryptConfig struct {
-	Cost      int // по умолчанию 10/12
-	MinLength int // по умолчанию 6/8
+// Алгоритмы хеширования паролей. Хеш сам говорит, чем и с какими параметрами он посчитан:
+// argon2id - в формате PHC ($argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>), bcrypt - свой $2a$/$2b$
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+)
+
+// bcryptMaxBytes - дальше bcrypt молча обрезает пароль, поэтому длиннее не принимаем
+const bcryptMaxBytes = 72
+
+// Argon2Params - параметры argon2id; нули - значения по умолчанию (рекомендация OWASP)
+type Argon2Params struct {
+	Memory      uint32 // KiB, по умолчанию 64 MiB
+	Iterations  uint32 // по умолчанию 3
+	Parallelism uint8  // по умолчанию 2
+	SaltLength  uint32 // байт, по умолчанию 16
+	KeyLength   uint32 // байт, по умолчанию 32
+}
+
+func (p Argon2Params) withDefaults() Argon2Params {
+	if p.Memory == 0 {
+		p.Memory = 64 * 1024
+	}
+	if p.Iterations == 0 {
+		p.Iterations = 3
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = 2
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = 16
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = 32
+	}
+	return p
+}
+
+// PasswordConfig - чем хешировать новые пароли и политика паролей
+type PasswordConfig struct {
+	Algorithm  string // argon2id (по умолчанию) | bcrypt
+	BcryptCost int    // по умолчанию bcrypt.DefaultCost
+	Argon2     Argon2Params
+
+	MinLength int               // символов, по умолчанию 6
+	MaxLength int               // символов, по умолчанию 128
+	Breached  BreachedPasswords // nil - не проверяем
+
+	MaxConcurrent int // одновременных хешей/сравнений, по умолчанию GOMAXPROCS
+}
+
+// ConcurrencyLimit - сколько хешей/сравнений можно считать одновременно
+func (c *PasswordConfig) ConcurrencyLimit() int {
+	if c == nil || c.MaxConcurrent <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return c.MaxConcurrent
+}
+
+func (c *PasswordConfig) algorithm() string {
+	if c == nil || c.Algorithm == "" {
+		return AlgArgon2id
+	}
+	return c.Algorithm
+}
+
+func (c *PasswordConfig) bcryptCost() int {
+	if c == nil || c.BcryptCost <= 0 {
+		return bcrypt.DefaultCost
+	}
+	return c.BcryptCost
 }
 
-func HashPassword(plain string, cfg *BcryptConfig) (string, error) {
-	minLen := 6
-	cost := bcrypt.DefaultCost
+func (c *PasswordConfig) argon2() Argon2Params {
+	if c == nil {
+		return Argon2Params{}.withDefaults()
+	}
+	return c.Argon2.withDefaults()
+}
 
-	if cfg != nil {
-		if cfg.MinLength > 0 {
-			minLen = cfg.MinLength
+// HashPassword - хеш по текущему алгоритму из cfg. Политику не проверяет (см. CheckPasswordPolicy)
+func HashPassword(plain string, cfg *PasswordConfig) (string, error) {
+	switch cfg.algorithm() {
+	case AlgArgon2id:
+		return hashArgon2id(plain, cfg.argon2())
+	case AlgBcrypt:
+		if len(plain) > bcryptMaxBytes {
+			return "", errs.ErrPasswordTooLong
 		}
-		if cfg.Cost > 0 {
-			cost = cfg.Cost
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), cfg.bcryptCost())
+		if err != nil {
+			return "", err
 		}
+		return string(hash), nil
+	default:
+		return "", fmt.Errorf("unknown password algorithm %q", cfg.algorithm())
 	}
+}
 
-	if len(plain) < minLen {
-		return "", errs.ErrPasswordTooShort
+// ComparePassword - nil, если пароль подходит к хешу любого поддерживаемого алгоритма
+func ComparePassword(hash, plain string) error {
+	if strings.HasPrefix(hash, "$"+AlgArgon2id+"$") {
+		p, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return err
+		}
+		got := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(got, key) != 1 {
+			return errs.ErrInvalidCredentials
+		}
+		return nil
 	}
 
-	hash, err := bcrypt.GenerateFromPassword([]byte(plain), cost)
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+}
+
+// NeedsRehash - хеш посчитан другим алгоритмом или с другими параметрами, чем в cfg.
+// Проверять после успешного ComparePassword: тогда есть пароль, чтобы пересчитать
+func NeedsRehash(hash string, cfg *PasswordConfig) bool {
+	if !HasUsablePassword(hash) {
+		return false
+	}
+	switch cfg.algorithm() {
+	case AlgArgon2id:
+		p, _, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return true
+		}
+		want := cfg.argon2()
+		return p.Memory != want.Memory || p.Iterations != want.Iterations ||
+			p.Parallelism != want.Parallelism || uint32(len(key)) != want.KeyLength
+	case AlgBcrypt:
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != cfg.bcryptCost()
+	default:
+		return false
+	}
+}
+
+// DummyHash - хеш случайной строки тем же алгоритмом и с той же стоимостью, что и у настоящих паролей.
+// Сравнение с ним тратит столько же времени, поэтому по ответу Login нельзя понять, есть ли такой email
+func DummyHash(cfg *PasswordConfig) (string, error) {
+	plain, err := RandomStringURLSafe(32)
 	if err != nil {
 		return "", err
 	}
 
-	return string(hash), nil
+	return HashPassword(plain, cfg)
 }
 
-func ComparePassword(hash, plain string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain))
+func hashArgon2id(plain string, p Argon2Params) (string, error) {
+	salt, err := RandomBytes(int(p.SaltLength))
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+
+	return fmt.Sprintf("$%s$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		AlgArgon2id, argon2.Version, p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+var errBadArgon2Hash = errors.New("malformed argon2id hash")
+
+func decodeArgon2id(hash string) (p Argon2Params, salt, key []byte, err error) {
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != AlgArgon2id {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return p, nil, nil, errBadArgon2Hash
+	}
+	p.SaltLength, p.KeyLength = uint32(len(salt)), uint32(len(key))
+
+	return p, salt, key, nil
+}
+
+// unusablePrefix - таким хешом не может быть ни один настоящий хеш (они начинаются с "$")
+const unusablePrefix = "!"
+
+// UnusablePasswordHash - "пароль" для пользователей, пришедших через внешнего провайдера:
+// войти по нему нельзя, но колонка password_hash остается заполненной
+func UnusablePasswordHash() (string, error) {
+	s, err := RandomStringURLSafe(16)
+	if err != nil {
+		return "", err
+	}
+
+	return unusablePrefix + s, nil
+}
+
+// HasUsablePassword - false, если пароль не задавался (см. UnusablePasswordHash)
+func HasUsablePassword(hash string) bool {
+	return hash != "" && !strings.HasPrefix(hash, unusablePrefix)
 }
@@ -0,0 +1,201 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cwrk-planet/auth-service/internal/errs"
+)
+
+const (
+	defaultMinLength = 6
+	defaultMaxLength = 128
+)
+
+// CheckPasswordPolicy - длина, пароль не совпадает с email и не найден среди утекших.
+// Длина считается в символах, а не байтах
+func CheckPasswordPolicy(ctx context.Context, plain, email string, cfg *PasswordConfig) error {
+	minLen, maxLen := defaultMinLength, defaultMaxLength
+	var breached BreachedPasswords
+	if cfg != nil {
+		if cfg.MinLength > 0 {
+			minLen = cfg.MinLength
+		}
+		if cfg.MaxLength > 0 {
+			maxLen = cfg.MaxLength
+		}
+		breached = cfg.Breached
+	}
+
+	n := utf8.RuneCountInString(plain)
+	if n < minLen {
+		return errs.ErrPasswordTooShort
+	}
+	if n > maxLen || (cfg.algorithm() == AlgBcrypt && len(plain) > bcryptMaxBytes) {
+		return errs.ErrPasswordTooLong
+	}
+	if email = strings.TrimSpace(email); email != "" && strings.EqualFold(strings.TrimSpace(plain), email) {
+		return errs.ErrPasswordIsEmail
+	}
+	if breached != nil {
+		found, err := IsBreachedPassword(ctx, breached, plain)
+		if err != nil {
+			return fmt.Errorf("breached password check: %w", err)
+		}
+		if found {
+			return errs.ErrPasswordBreached
+		}
+	}
+
+	return nil
+}
+
+// BreachedPasswords - список утекших паролей в стиле k-anonymity (Pwned Passwords):
+// по первым 5 hex-символам SHA-1 отдает хвосты (35 символов) всех хешей с этим префиксом.
+// Сам пароль и его полный хеш источнику не передаются
+type BreachedPasswords interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+const breachedPrefixLen = 5
+
+// IsBreachedPassword - есть ли SHA-1 пароля в списке
+func IsBreachedPassword(ctx context.Context, list BreachedPasswords, plain string) (bool, error) {
+	sum := sha1.Sum([]byte(plain))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := list.Range(ctx, h[:breachedPrefixLen])
+	if err != nil {
+		return false, err
+	}
+	for _, s := range suffixes {
+		if strings.EqualFold(s, h[breachedPrefixLen:]) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// HashListFile - локальный файл строк "<SHA-1 hex>[:<count>]", отсортированный по хешу
+// (как выгрузка Pwned Passwords). Файл бывает на десятки гигабайт, поэтому в память не читается:
+// начало диапазона ищется бинарным поиском по смещениям
+type HashListFile struct {
+	f    *os.File
+	size int64
+}
+
+func OpenHashListFile(path string) (*HashListFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &HashListFile{f: f, size: st.Size()}, nil
+}
+
+func (l *HashListFile) Close() error {
+	return l.f.Close()
+}
+
+func (l *HashListFile) Range(ctx context.Context, prefix string) ([]string, error) {
+	prefix = strings.ToUpper(prefix)
+	if len(prefix) != breachedPrefixLen {
+		return nil, errors.New("hash prefix must be 5 hex chars")
+	}
+
+	// ищем наименьшее смещение, с которого первая целая строка >= prefix
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		mid := lo + (hi-lo)/2
+		line, _, err := l.lineFrom(mid)
+		if err != nil {
+			return nil, err
+		}
+		if line != nil && compareHashPrefix(line, prefix) < 0 {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+
+	_, start, err := l.lineFrom(lo)
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(io.NewSectionReader(l.f, start, l.size-start))
+	var out []string
+	for {
+		line, err := readHashLine(r)
+		if line == nil {
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return out, nil
+		}
+		if compareHashPrefix(line, prefix) != 0 {
+			return out, nil
+		}
+		hash, _, _ := bytes.Cut(line, []byte(":"))
+		out = append(out, strings.ToUpper(string(hash[breachedPrefixLen:])))
+	}
+}
+
+// lineFrom - первая целая строка, начинающаяся не раньше off, и ее смещение; nil - до конца файла строк нет
+func (l *HashListFile) lineFrom(off int64) ([]byte, int64, error) {
+	start := off
+	if off > 0 {
+		// off попал в середину строки - пропускаем ее остаток
+		start = off - 1
+	}
+	r := bufio.NewReader(io.NewSectionReader(l.f, start, l.size-start))
+	if off > 0 {
+		skipped, err := r.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, l.size, nil
+			}
+			return nil, 0, err
+		}
+		start += int64(len(skipped))
+	}
+	line, err := readHashLine(r)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, 0, err
+	}
+
+	return line, start, nil
+}
+
+func readHashLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadBytes('\n')
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return nil, err
+	}
+	return line, nil
+}
+
+func compareHashPrefix(line []byte, prefix string) int {
+	if len(line) > len(prefix) {
+		line = line[:len(prefix)]
+	}
+	return strings.Compare(strings.ToUpper(string(line)), prefix)
+}
@@ -3,6 +3,7 @@ package security
 import (
 	"crypto/rand"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/hex"
 	"io"
@@ -34,3 +35,8 @@ func SHA256Hex(data []byte) string {
 func SHA256HexOfString(s string) string {
 	return SHA256Hex([]byte(s))
 }
+
+// ConstantTimeEqual сравнивает строки за время, не зависящее от совпавшего префикса
+func ConstantTimeEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
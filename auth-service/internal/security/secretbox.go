@@ -0,0 +1,62 @@
+package security
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidSecretBoxKey = errors.New("secretbox key must be 32 bytes (base64)")
+
+// SecretBox шифрует секреты, которые лежат в БД (напр. TOTP), AES-256-GCM.
+// Формат: base64(nonce || ciphertext)
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBoxFromBase64 - ключ из конфига, 32 байта в base64
+func NewSecretBoxFromBase64(key string) (*SecretBox, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil || len(raw) != 32 {
+		return nil, ErrInvalidSecretBoxKey
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SecretBox{aead: aead}, nil
+}
+
+func (b *SecretBox) Seal(plain string) (string, error) {
+	nonce, err := RandomBytes(b.aead.NonceSize())
+	if err != nil {
+		return "", err
+	}
+	out := b.aead.Seal(nonce, nonce, []byte(plain), nil)
+
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+func (b *SecretBox) Open(enc string) (string, error) {
+	raw, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil {
+		return "", err
+	}
+	n := b.aead.NonceSize()
+	if len(raw) < n {
+		return "", errors.New("secretbox: ciphertext too short")
+	}
+	plain, err := b.aead.Open(nil, raw[:n], raw[n:], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plain), nil
+}
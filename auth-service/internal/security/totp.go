@@ -0,0 +1,100 @@
+package security
+
+import (
+	"bytes"
+	"encoding/base32"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+// параметры TOTP как у большинства приложений-аутентификаторов (RFC 6238: SHA1, 6 цифр, 30s)
+const (
+	totpPeriod = 30
+	totpSkew   = 1 // принимаем соседний шаг: часы телефона могут отставать
+	qrSize     = 256
+)
+
+var totpOpts = totp.ValidateOpts{
+	Period:    totpPeriod,
+	Digits:    otp.DigitsSix,
+	Algorithm: otp.AlgorithmSHA1,
+}
+
+// TOTPKey - новый секрет для подключения аутентификатора
+type TOTPKey struct {
+	Secret string // base32, для ручного ввода
+	URL    string // otpauth://totp/...
+	QRPNG  []byte // QR с URL
+}
+
+func NewTOTPKey(issuer, account string) (*TOTPKey, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      issuer,
+		AccountName: account,
+		Period:      totpPeriod,
+		Digits:      totpOpts.Digits,
+		Algorithm:   totpOpts.Algorithm,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := key.Image(qrSize, qrSize)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+
+	return &TOTPKey{Secret: key.Secret(), URL: key.URL(), QRPNG: buf.Bytes()}, nil
+}
+
+// ValidateTOTP проверяет код на шагах now-1..now+1 и возвращает номер совпавшего шага.
+// Шаги <= lastStep не принимаются, чтобы один код нельзя было использовать дважды
+func ValidateTOTP(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpOpts.Digits.Length() {
+		return 0, false
+	}
+
+	cur := now.Unix() / totpPeriod
+	for step := cur - totpSkew; step <= cur+totpSkew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		want, err := totp.GenerateCodeCustom(secret, time.Unix(step*totpPeriod, 0), totpOpts)
+		if err != nil {
+			return 0, false
+		}
+		if ConstantTimeEqual(want, code) {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// NewRecoveryCode - одноразовый код восстановления вида "abcde-fghij" (50 бит)
+func NewRecoveryCode() (string, error) {
+	b, err := RandomBytes(7)
+	if err != nil {
+		return "", err
+	}
+	s := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+
+	return s[:5] + "-" + s[5:], nil
+}
+
+// HashRecoveryCode - хеш для хранения; регистр и дефис не важны
+func HashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.ReplaceAll(code, "-", "")
+
+	return SHA256HexOfString(code)
+}
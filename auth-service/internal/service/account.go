@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+const (
+	defaultDeletionGrace = 14 * 24 * time.Hour
+	deletionBatch        = 100
+)
+
+// SetAccountDeletionGrace - сколько аккаунт ждет удаления после запроса (по умолчанию 14 дней)
+func (s *AuthService) SetAccountDeletionGrace(grace time.Duration) {
+	s.deletionGrace = grace
+}
+
+// PersonalData - то, что auth-service хранит о пользователе: для выгрузки данных
+type PersonalData struct {
+	User     *domain.User
+	Sessions []domain.Session
+}
+
+// Reauth - чем пользователь подтверждает удаление аккаунта: паролем (и вторым фактором, если включен)
+// или подписью passkey по церемонии BeginPasskeyReauth
+type Reauth struct {
+	Password          string
+	MFACode           string
+	PasskeyCeremonyID string
+	PasskeyCredential []byte // ответ navigator.credentials.get()
+}
+
+// RequestAccountDeletion - повторная проверка (см. reauthenticate), затем отсрочка:
+// до возвращенного момента вход работает и удаление можно отменить. Повторный запрос срок не сдвигает
+func (s *AuthService) RequestAccountDeletion(ctx context.Context, userID domain.UserID, in Reauth, meta *LoginMeta) (time.Time, error) {
+	now := s.now()
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := s.reauthenticate(ctx, u, in, meta, now); err != nil {
+		return time.Time{}, err
+	}
+	if u.DeletionScheduledAt != nil {
+		return *u.DeletionScheduledAt, nil
+	}
+
+	grace := s.deletionGrace
+	if grace <= 0 {
+		grace = defaultDeletionGrace
+	}
+	at := now.Add(grace)
+	if err := s.users.ScheduleDeletion(ctx, u.ID, &at, now); err != nil {
+		slog.Error("auth.account.scheduleDeletion failed", slog.Any("err", err))
+		return time.Time{}, err
+	}
+	slog.Info("auth.account.deletionRequested", "user_id", int64(u.ID), "scheduled_at", at)
+	s.recordUser(ctx, meta, domain.AuthEventAccountDeletionRequested, u.ID, map[string]string{"scheduled_at": at.UTC().Format(time.RFC3339)})
+
+	return at, nil
+}
+
+// CancelAccountDeletion - отмена, пока срок не наступил
+func (s *AuthService) CancelAccountDeletion(ctx context.Context, userID domain.UserID, meta *LoginMeta) error {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if u.DeletionScheduledAt == nil {
+		return errs.ErrDeletionNotScheduled
+	}
+	if err := s.users.ScheduleDeletion(ctx, u.ID, nil, s.now()); err != nil {
+		slog.Error("auth.account.cancelDeletion failed", slog.Any("err", err))
+		return err
+	}
+	s.recordUser(ctx, meta, domain.AuthEventAccountDeletionCancelled, u.ID, nil)
+
+	return nil
+}
+
+// PersonalData - профиль и живые сессии пользователя
+func (s *AuthService) PersonalData(ctx context.Context, userID domain.UserID) (*PersonalData, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.sessions.ListByUser(ctx, userID, s.now())
+	if err != nil {
+		slog.Error("auth.account.listSessions failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &PersonalData{User: u, Sessions: sessions}, nil
+}
+
+// reauthenticate - подпись passkey, если пришла (с UV это уже два фактора, как и при входе), иначе пароль,
+// если он задан, и второй фактор, если включен. Без пароля и TOTP (только вход через провайдера) годится
+// недавний вход через провайдера (OIDCConfig.ReauthMaxAge)
+func (s *AuthService) reauthenticate(ctx context.Context, u *domain.User, in Reauth, meta *LoginMeta, now time.Time) error {
+	if in.PasskeyCeremonyID != "" {
+		return s.checkPasskeyReauth(ctx, u.ID, in.PasskeyCeremonyID, in.PasskeyCredential, meta, domain.AuthEventAccountDeletionFailed)
+	}
+
+	hasPassword := security.HasUsablePassword(u.PasswordHash)
+	mfa, err := s.mfaRequired(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if !hasPassword && !mfa {
+		recent, err := s.recentOIDCLogin(ctx, u.ID, now)
+		if err != nil {
+			return err
+		}
+		if !recent {
+			return errs.ErrReauthUnavailable
+		}
+		return nil
+	}
+
+	if hasPassword {
+		if _, err := s.checkPassword(ctx, u.Email, in.Password, meta, now, domain.AuthEventAccountDeletionFailed); err != nil {
+			return err
+		}
+	}
+	if mfa {
+		ok, err := s.checkSecondFactor(ctx, u.ID, in.MFACode, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if s.guard != nil {
+				s.guard.fail(ctx, loginKeys(u.Email, meta), now)
+			}
+			s.recordFailure(ctx, meta, domain.AuthEventAccountDeletionFailed, u, u.Email, errs.ErrInvalidMFACode)
+			return errs.ErrInvalidMFACode
+		}
+	}
+
+	return nil
+}
+
+// purgeDeletedAccounts - удаляет аккаунты, у которых прошла отсрочка (из RunCleanup)
+func (s *AuthService) purgeDeletedAccounts(ctx context.Context, now time.Time) {
+	ids, err := s.users.ListDueDeletions(ctx, now, deletionBatch)
+	if err != nil {
+		slog.Error("auth.cleanup.listDueDeletions failed", slog.Any("err", err))
+		return
+	}
+	for _, id := range ids {
+		if err := s.deleteUser(ctx, id, now, nil); err != nil {
+			slog.Error("auth.cleanup.deleteAccount failed", slog.Any("err", err), "user_id", int64(id))
+			continue
+		}
+		slog.Info("auth.account.deleted", "user_id", int64(id))
+		s.recordUser(ctx, nil, domain.AuthEventAccountDeleted, id, nil)
+	}
+}
+
+// deleteUser - удаляет пользователя и пишет user.deleted в outbox одной транзакцией. Сессии, MFA, привязки,
+// участие в комнатах уходят каскадом по FK, сообщения чата остаются с user_id = NULL.
+// audit - запись в журналы действия админа в той же транзакции (nil - удаление не админом)
+func (s *AuthService) deleteUser(ctx context.Context, userID domain.UserID, now time.Time, audit func(adminJournal) error) error {
+	if s.tx == nil {
+		if err := s.users.Delete(ctx, userID); err != nil {
+			return err
+		}
+		if audit != nil {
+			return audit(s.adminJournal(nil))
+		}
+		return nil
+	}
+
+	return s.tx.InTx(ctx, func(tx repository.Tx) error {
+		if err := tx.Users().Delete(ctx, userID); err != nil {
+			return err
+		}
+		if audit != nil {
+			if err := audit(s.adminJournal(tx)); err != nil {
+				return err
+			}
+		}
+
+		return tx.Outbox().Add(ctx, events.UserDeleted{UserID: int64(userID), DeletedAt: now})
+	})
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
+)
+
+const (
+	adminDefaultLimit = 50
+	adminMaxLimit     = 200
+)
+
+// AdminUser - пользователь в админке: профиль и роли
+type AdminUser struct {
+	User  *domain.User
+	Roles []string
+}
+
+type adminDeps struct {
+	audit repository.AdminAuditRepository
+}
+
+// adminJournal - куда пишется действие админа. Таблиц две, и у них разные читатели:
+// admin_audit_log - журнал админки (AdminAuditLog, выборка по админу и по цели),
+// auth_audit_events - общий журнал безопасности с IP/UA запроса, его видит и сам пользователь (без actor_id).
+// Обе записи делаются в одной транзакции с самим изменением, поэтому журналы не расходятся
+type adminJournal struct {
+	log    repository.AdminAuditRepository
+	events repository.AuthEventRepository // nil - журнал безопасности не включен
+}
+
+// SetAdmin - включает AdminService; нужен и SetRBAC: право admin:users проверяется по ролям из БД
+func (s *AuthService) SetAdmin(audit repository.AdminAuditRepository) {
+	s.admin = &adminDeps{audit: audit}
+}
+
+// AdminSearchUsers - поиск по подстроке email или имени; cursor - последний id прошлой страницы,
+// next = 0 - страниц больше нет
+func (s *AuthService) AdminSearchUsers(ctx context.Context, actor domain.UserID, query string, cursor domain.UserID, limit int) (users []AdminUser, next domain.UserID, err error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, 0, err
+	}
+	limit = adminLimit(limit)
+
+	// на один больше: так понятно, есть ли следующая страница
+	list, err := s.users.Search(ctx, domain.UserSearch{Query: query, AfterID: cursor, Limit: limit + 1})
+	if err != nil {
+		slog.Error("auth.admin.searchUsers failed", slog.Any("err", err))
+		return nil, 0, err
+	}
+	if len(list) > limit {
+		list = list[:limit]
+		next = list[limit-1].ID
+	}
+
+	ids := make([]domain.UserID, 0, len(list))
+	for _, u := range list {
+		ids = append(ids, u.ID)
+	}
+	roles, err := s.rbac.roles.NamesByUsers(ctx, ids)
+	if err != nil {
+		slog.Error("auth.admin.namesByUsers failed", slog.Any("err", err))
+		return nil, 0, err
+	}
+
+	users = make([]AdminUser, 0, len(list))
+	for i := range list {
+		users = append(users, AdminUser{User: &list[i], Roles: roles[list[i].ID]})
+	}
+
+	return users, next, nil
+}
+
+func (s *AuthService) AdminGetUser(ctx context.Context, actor, userID domain.UserID) (*AdminUser, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, userID)
+}
+
+// AdminDisableUser - блокирует вход и refresh и завершает все сессии.
+// Уже выданные access-токены живут до своего exp
+func (s *AuthService) AdminDisableUser(ctx context.Context, actor, userID domain.UserID, reason string) (*AdminUser, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+	if actor == userID {
+		return nil, errs.ErrSelfAdminAction
+	}
+
+	now := s.now()
+	err := s.adminTx(ctx, func(users repository.UserRepository, _ repository.RoleRepository, audit adminJournal) error {
+		if err := users.SetDisabled(ctx, userID, &now, now); err != nil {
+			return err
+		}
+		return s.auditAdmin(ctx, audit, actor, domain.AdminActionDisable, userID, adminDetails("reason", strings.TrimSpace(reason)), now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, userID)
+}
+
+func (s *AuthService) AdminEnableUser(ctx context.Context, actor, userID domain.UserID) (*AdminUser, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	err := s.adminTx(ctx, func(users repository.UserRepository, _ repository.RoleRepository, audit adminJournal) error {
+		if err := users.SetDisabled(ctx, userID, nil, now); err != nil {
+			return err
+		}
+		return s.auditAdmin(ctx, audit, actor, domain.AdminActionEnable, userID, nil, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, userID)
+}
+
+// AdminForceLogout - завершает все сессии пользователя; возвращает, сколько их было
+func (s *AuthService) AdminForceLogout(ctx context.Context, actor, userID domain.UserID) (int64, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return 0, err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return 0, err
+	}
+
+	now := s.now()
+	n, err := s.sessions.DeleteByUser(ctx, userID)
+	if err != nil {
+		slog.Error("auth.admin.forceLogout failed", slog.Any("err", err))
+		return 0, err
+	}
+	if err := s.auditAdmin(ctx, s.adminJournal(nil), actor, domain.AdminActionForceLogout, userID, nil, now); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+// AdminForcePasswordReset - вход по паролю закрыт до ChangePassword, сессии завершаются
+func (s *AuthService) AdminForcePasswordReset(ctx context.Context, actor, userID domain.UserID) (*AdminUser, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	err := s.adminTx(ctx, func(users repository.UserRepository, _ repository.RoleRepository, audit adminJournal) error {
+		if err := users.RequirePasswordReset(ctx, userID, now); err != nil {
+			return err
+		}
+		return s.auditAdmin(ctx, audit, actor, domain.AdminActionPasswordReset, userID, nil, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.revokeSessions(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, userID)
+}
+
+// AdminSetRoles - оставляет пользователю ровно эти роли; в токенах - со следующего refresh.
+// Снять admin:users с самого себя нельзя, чтобы не остаться без админов по ошибке
+func (s *AuthService) AdminSetRoles(ctx context.Context, actor, userID domain.UserID, roles []string) (*AdminUser, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+	if _, err := s.users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, r := range roles {
+		if r = strings.TrimSpace(r); r != "" && !slices.Contains(names, r) {
+			names = append(names, r)
+		}
+	}
+	slices.Sort(names)
+
+	if actor == userID {
+		all, err := s.rbac.roles.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		_, scopes := domain.Grants(slices.DeleteFunc(all, func(r domain.Role) bool { return !slices.Contains(names, r.Name) }))
+		if !slices.Contains(scopes, authz.ScopeAdminUsers) {
+			return nil, errs.ErrSelfAdminAction
+		}
+	}
+
+	now := s.now()
+	err := s.adminTx(ctx, func(_ repository.UserRepository, rr repository.RoleRepository, audit adminJournal) error {
+		if err := rr.Replace(ctx, userID, names, now); err != nil {
+			return err
+		}
+		return s.auditAdmin(ctx, audit, actor, domain.AdminActionSetRoles, userID, adminDetails("roles", strings.Join(names, ",")), now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.adminUser(ctx, userID)
+}
+
+// AdminDeleteUser - удаление без возврата: сессии, MFA, passkeys, привязки, роли и согласия OAuth уходят по FK.
+// В журнале остается email удаленного
+func (s *AuthService) AdminDeleteUser(ctx context.Context, actor, userID domain.UserID) error {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return err
+	}
+	if actor == userID {
+		return errs.ErrSelfAdminAction
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	return s.deleteUser(ctx, userID, now, func(audit adminJournal) error {
+		return s.auditAdmin(ctx, audit, actor, domain.AdminActionDelete, userID, adminDetails("email", u.Email), now)
+	})
+}
+
+// AdminRoles - все роли с правами
+func (s *AuthService) AdminRoles(ctx context.Context, actor domain.UserID) ([]domain.Role, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, err
+	}
+
+	return s.rbac.roles.List(ctx)
+}
+
+// AdminAuditLog - журнал действий админов, новые первыми; next = 0 - дальше записей нет
+func (s *AuthService) AdminAuditLog(ctx context.Context, actor domain.UserID, f domain.AdminAuditFilter) (entries []domain.AdminAuditEntry, next domain.AdminAuditID, err error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, 0, err
+	}
+	f.Limit = adminLimit(f.Limit)
+	limit := f.Limit
+	f.Limit++
+
+	entries, err = s.admin.audit.List(ctx, f)
+	if err != nil {
+		slog.Error("auth.admin.auditLog failed", slog.Any("err", err))
+		return nil, 0, err
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+		next = entries[limit-1].ID
+	}
+
+	return entries, next, nil
+}
+
+// requireAdmin - право admin:users по текущим ролям из БД, а не из токена:
+// токен мог выйти до того, как роль сняли или админа заблокировали
+func (s *AuthService) requireAdmin(ctx context.Context, actor domain.UserID) error {
+	if s.admin == nil || s.rbac == nil {
+		return errs.ErrAdminUnavailable
+	}
+	u, err := s.users.GetByID(ctx, actor)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errs.ErrForbidden
+		}
+		return err
+	}
+	if u.IsDisabled() {
+		return errs.ErrForbidden
+	}
+	_, scopes, err := s.UserGrants(ctx, actor)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(scopes, authz.ScopeAdminUsers) {
+		return errs.ErrForbidden
+	}
+
+	return nil
+}
+
+// adminTx - изменение и запись в оба журнала одной транзакцией (если транзакции включены)
+func (s *AuthService) adminTx(ctx context.Context, fn func(users repository.UserRepository, roles repository.RoleRepository, audit adminJournal) error) error {
+	if s.tx == nil {
+		return fn(s.users, s.rbac.roles, s.adminJournal(nil))
+	}
+
+	return s.tx.InTx(ctx, func(tx repository.Tx) error {
+		return fn(tx.Users(), tx.Roles(), s.adminJournal(tx))
+	})
+}
+
+// adminJournal - журналы в транзакции tx; tx == nil - без транзакции
+func (s *AuthService) adminJournal(tx repository.Tx) adminJournal {
+	j := adminJournal{log: s.admin.audit}
+	if s.audit != nil {
+		j.events = s.audit.events
+	}
+	if tx != nil {
+		j.log = tx.AdminAudit()
+		if j.events != nil {
+			j.events = tx.AuthEvents()
+		}
+	}
+
+	return j
+}
+
+// auditAdmin - запись в admin_audit_log и auth_audit_events. В отличие от record ошибка журнала
+// безопасности здесь не глотается: без записи откатывается и само действие
+func (s *AuthService) auditAdmin(ctx context.Context, audit adminJournal, actor domain.UserID, action domain.AdminAction, target domain.UserID, details map[string]string, now time.Time) error {
+	_, err := audit.log.Add(ctx, &domain.AdminAuditEntry{
+		ActorID:      actor,
+		Action:       action,
+		TargetUserID: &target,
+		Details:      details,
+		CreatedAt:    now,
+	})
+	if err != nil {
+		slog.Error("auth.admin.audit failed", slog.Any("err", err), "action", string(action))
+		return err
+	}
+	if audit.events != nil {
+		e := s.authEvent(ctx, nil, domain.AuthEvent{Type: domain.AdminAuthEvent(action), UserID: &target, ActorID: &actor, Details: details, CreatedAt: now})
+		if _, err := audit.events.Add(ctx, &e); err != nil {
+			slog.Error("auth.admin.auditEvent failed", slog.Any("err", err), "action", string(action))
+			return err
+		}
+	}
+	slog.Info("auth.admin."+string(action), "actor_id", int64(actor), "user_id", int64(target))
+
+	return nil
+}
+
+func (s *AuthService) adminUser(ctx context.Context, userID domain.UserID) (*AdminUser, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, _, err := s.UserGrants(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminUser{User: u, Roles: roles}, nil
+}
+
+func (s *AuthService) revokeSessions(ctx context.Context, userID domain.UserID) error {
+	if _, err := s.sessions.DeleteByUser(ctx, userID); err != nil {
+		slog.Error("auth.admin.deleteSessions failed", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// adminDetails - пустые значения в журнал не пишем
+func adminDetails(key, value string) map[string]string {
+	if value == "" {
+		return nil
+	}
+
+	return map[string]string{key: value}
+}
+
+func adminLimit(n int) int {
+	switch {
+	case n <= 0:
+		return adminDefaultLimit
+	case n > adminMaxLimit:
+		return adminMaxLimit
+	default:
+		return n
+	}
+}
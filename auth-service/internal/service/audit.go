@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+)
+
+const (
+	activityDefaultLimit = 20
+	activityMaxLimit     = 100
+)
+
+type auditDeps struct {
+	events repository.AuthEventRepository
+}
+
+// SetAuditLog - журнал событий безопасности (auth_audit_events); без него события только в логах
+func (s *AuthService) SetAuditLog(events repository.AuthEventRepository) {
+	s.audit = &auditDeps{events: events}
+}
+
+type loginMetaKey struct{}
+
+// WithLoginMeta - IP, UA и request id запроса для журнала: не у всех методов сервиса есть параметр meta
+func WithLoginMeta(ctx context.Context, meta *LoginMeta) context.Context {
+	if meta == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, loginMetaKey{}, meta)
+}
+
+func loginMetaFrom(ctx context.Context) *LoginMeta {
+	meta, _ := ctx.Value(loginMetaKey{}).(*LoginMeta)
+	return meta
+}
+
+// SecurityEvents - последние события пользователя, новые первыми; next = 0 - дальше событий нет.
+// Кто из админов что сделал, пользователю не показываем
+func (s *AuthService) SecurityEvents(ctx context.Context, userID domain.UserID, cursor domain.AuthEventID, limit int) (events []domain.AuthEvent, next domain.AuthEventID, err error) {
+	if s.audit == nil {
+		return nil, 0, errs.ErrAuditUnavailable
+	}
+	if limit <= 0 {
+		limit = activityDefaultLimit
+	}
+	limit = min(limit, activityMaxLimit)
+
+	events, next, err = s.listEvents(ctx, domain.AuthEventFilter{UserID: &userID, BeforeID: cursor, Limit: limit})
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range events {
+		events[i].ActorID = nil
+	}
+
+	return events, next, nil
+}
+
+// AdminSecurityEvents - журнал всех пользователей с фильтрами
+func (s *AuthService) AdminSecurityEvents(ctx context.Context, actor domain.UserID, f domain.AuthEventFilter) ([]domain.AuthEvent, domain.AuthEventID, error) {
+	if err := s.requireAdmin(ctx, actor); err != nil {
+		return nil, 0, err
+	}
+	if s.audit == nil {
+		return nil, 0, errs.ErrAuditUnavailable
+	}
+	f.Limit = adminLimit(f.Limit)
+
+	return s.listEvents(ctx, f)
+}
+
+func (s *AuthService) listEvents(ctx context.Context, f domain.AuthEventFilter) (events []domain.AuthEvent, next domain.AuthEventID, err error) {
+	limit := f.Limit
+	f.Limit++
+
+	events, err = s.audit.events.List(ctx, f)
+	if err != nil {
+		slog.Error("auth.audit.list failed", slog.Any("err", err))
+		return nil, 0, err
+	}
+	if len(events) > limit {
+		events = events[:limit]
+		next = events[limit-1].ID
+	}
+
+	return events, next, nil
+}
+
+// record - событие в журнал. Ошибка записи не должна ломать вход, поэтому только в лог.
+// meta == nil - берем из контекста (см. WithLoginMeta)
+func (s *AuthService) record(ctx context.Context, meta *LoginMeta, e domain.AuthEvent) {
+	if s.audit == nil {
+		return
+	}
+
+	e = s.authEvent(ctx, meta, e)
+	if _, err := s.audit.events.Add(ctx, &e); err != nil {
+		slog.Error("auth.audit.add failed", slog.Any("err", err), "type", string(e.Type))
+	}
+}
+
+// authEvent - дополняет событие адресом, UA и request id запроса и временем
+func (s *AuthService) authEvent(ctx context.Context, meta *LoginMeta, e domain.AuthEvent) domain.AuthEvent {
+	if meta == nil {
+		meta = loginMetaFrom(ctx)
+	}
+	if meta != nil {
+		e.IP, e.UserAgent, e.RequestID = meta.IP, meta.UserAgent, meta.RequestID
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = s.now()
+	}
+
+	return e
+}
+
+// recordUser - событие конкретного пользователя
+func (s *AuthService) recordUser(ctx context.Context, meta *LoginMeta, typ domain.AuthEventType, userID domain.UserID, details map[string]string) {
+	s.record(ctx, meta, domain.AuthEvent{Type: typ, UserID: &userID, Details: details})
+}
+
+// recordFailure - неудачная попытка; u == nil - email не найден, тогда в журнале только сам email
+func (s *AuthService) recordFailure(ctx context.Context, meta *LoginMeta, typ domain.AuthEventType, u *domain.User, email string, err error) {
+	e := domain.AuthEvent{Type: typ, Details: map[string]string{"reason": err.Error()}}
+	if u != nil {
+		e.UserID = &u.ID
+	}
+	if email != "" {
+		e.Details["email"] = email
+	}
+	s.record(ctx, meta, e)
+}
@@ -5,12 +5,16 @@ import (
 	"errors"
 	"log/slog"
 	"net/netip"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/cwrk-planet/auth-service/internal/domain"
 	"github.com/cwrk-planet/auth-service/internal/errs"
 	"github.com/cwrk-planet/auth-service/internal/repository"
 	"github.com/cwrk-planet/auth-service/internal/security"
+
+	"github.com/cwrk-planet/events/pkg/events"
 )
 
 type RegisterResult struct {
@@ -23,6 +27,11 @@ type LoginResult struct {
 	User         *domain.User
 	AccessToken  string
 	RefreshToken string
+
+	// включен второй фактор: токенов нет, нужен VerifyMFA с MFAToken
+	MFARequired  bool
+	MFAToken     string
+	MFAExpiresIn time.Duration
 }
 
 type RefreshResult struct {
@@ -36,8 +45,26 @@ type AuthService struct {
 	sessions   repository.SessionRepository
 	jwt        *security.JWTSigner
 	refreshTTL time.Duration
-	passPolicy security.BcryptConfig
+	passPolicy security.PasswordConfig
 	now        func() time.Time
+
+	tx    repository.TxRunner // опционально, см. SetTxRunner
+	guard *loginGuard         // опционально, см. SetLoginGuard
+	mfa   *mfaDeps            // опционально, см. SetMFA
+
+	webauthn *webauthnDeps // опционально, см. SetWebAuthn
+	oidc     *oidcDeps     // опционально, см. SetOIDC
+	oauth    *oauthDeps    // опционально, см. SetOAuthProvider
+	rbac     *rbacDeps     // опционально, см. SetRBAC
+	admin    *adminDeps    // опционально, см. SetAdmin
+	audit    *auditDeps    // опционально, см. SetAuditLog
+
+	deletionGrace time.Duration // см. SetAccountDeletionGrace
+
+	hashSlots chan struct{} // семафор на хеширование паролей, см. withHashSlot
+
+	dummyOnce sync.Once
+	dummyHash string // для сравнения, когда email не найден
 }
 
 func NewAuthService(
@@ -45,7 +72,7 @@ func NewAuthService(
 	sessions repository.SessionRepository,
 	jwt *security.JWTSigner,
 	refreshTTL time.Duration,
-	passPolicy security.BcryptConfig,
+	passPolicy security.PasswordConfig,
 	now func() time.Time,
 ) *AuthService {
 	if now == nil {
@@ -59,10 +86,15 @@ func NewAuthService(
 		refreshTTL: refreshTTL,
 		passPolicy: passPolicy,
 		now:        now,
+		hashSlots:  make(chan struct{}, passPolicy.ConcurrencyLimit()),
 	}
 }
 
 func (s *AuthService) Register(ctx context.Context, email, password string, displayName *string) (*RegisterResult, error) {
+	if err := security.CheckPasswordPolicy(ctx, password, email, &s.passPolicy); err != nil {
+		return nil, err
+	}
+
 	exists, err := s.users.ExistsByEmail(ctx, email)
 	if err != nil {
 		slog.Error("auth.register.existsByEmail failed by checking:", slog.Any("err", err))
@@ -73,7 +105,7 @@ func (s *AuthService) Register(ctx context.Context, email, password string, disp
 		return nil, repository.ErrAlreadyExists
 	}
 
-	hash, err := security.HashPassword(password, &s.passPolicy)
+	hash, err := s.hashPassword(password)
 	if err != nil {
 		slog.Error("auth.register.hashPassword failed", slog.Any("err", err))
 		return nil, err
@@ -91,15 +123,16 @@ func (s *AuthService) Register(ctx context.Context, email, password string, disp
 		return nil, err
 	}
 
-	id, err := s.users.Create(ctx, u)
+	id, err := s.createUser(ctx, u, nil)
 	if err != nil {
 		slog.Error("auth.register.createUserProfile failed", slog.Any("err", err))
 		return nil, err
 	}
 	u.ID = id
+	s.recordUser(ctx, nil, domain.AuthEventRegister, u.ID, nil)
 
-	// todo: добавить передачу LoginMeta и oldSessionID
-	access, refresh, err := s.issueTokens(ctx, u.ID, nil, nil)
+	// у Register нет параметра meta: IP и UA для сессии берем из контекста
+	access, refresh, err := s.issueTokens(ctx, u.ID, loginMetaFrom(ctx), nil)
 	if err != nil {
 		slog.Error("auth.register.generateIssueToken failed", slog.Any("err", err))
 		return nil, err
@@ -112,21 +145,106 @@ func (s *AuthService) Register(ctx context.Context, email, password string, disp
 	}, nil
 }
 
-// Login аутентифицирует по email+пароль и выпускает пару токенов
+// SetTxRunner - включает транзакции и доменные события (user.registered в outbox)
+func (s *AuthService) SetTxRunner(r repository.TxRunner) {
+	s.tx = r
+}
+
+// SetLoginGuard - включает учет неудачных входов (пауза между попытками и временная блокировка)
+func (s *AuthService) SetLoginGuard(repo repository.LoginFailuresRepository, cfg LoginGuardConfig) {
+	s.guard = &loginGuard{repo: repo, cfg: cfg.withDefaults()}
+}
+
+// RunCleanup - периодически удаляет просроченные сессии, аккаунты с истекшей отсрочкой удаления, старые счетчики неудачных входов, просроченные mfa_token,
+// незавершенные церемонии WebAuthn и входы через OIDC, просроченные коды и refresh-токены OAuth-клиентов, до отмены ctx
+func (s *AuthService) RunCleanup(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			now := s.now()
+			if _, err := s.sessions.DeleteExpired(ctx, now); err != nil {
+				slog.Error("auth.cleanup.deleteExpiredSessions failed", slog.Any("err", err))
+			}
+			s.purgeDeletedAccounts(ctx, now)
+			if s.guard != nil {
+				if _, err := s.guard.repo.DeleteStale(ctx, now.Add(-s.guard.cfg.Window)); err != nil {
+					slog.Error("auth.cleanup.deleteStaleLoginFailures failed", slog.Any("err", err))
+				}
+			}
+			if s.mfa != nil {
+				if _, err := s.mfa.challenges.DeleteExpired(ctx, now); err != nil {
+					slog.Error("auth.cleanup.deleteExpiredMfaChallenges failed", slog.Any("err", err))
+				}
+			}
+			if s.webauthn != nil {
+				if _, err := s.webauthn.ceremonies.DeleteExpired(ctx, now); err != nil {
+					slog.Error("auth.cleanup.deleteExpiredWebAuthnCeremonies failed", slog.Any("err", err))
+				}
+			}
+			if s.oidc != nil {
+				if _, err := s.oidc.states.DeleteExpired(ctx, now); err != nil {
+					slog.Error("auth.cleanup.deleteExpiredOidcStates failed", slog.Any("err", err))
+				}
+			}
+			if s.oauth != nil {
+				if _, err := s.oauth.grants.DeleteExpired(ctx, now); err != nil {
+					slog.Error("auth.cleanup.deleteExpiredOauthGrants failed", slog.Any("err", err))
+				}
+			}
+		}
+	}
+}
+
+// Login аутентифицирует по email+пароль и выпускает пару токенов.
+// Неизвестный email и неверный пароль неотличимы: одна и та же ошибка и одно и то же время ответа
 func (s *AuthService) Login(ctx context.Context, email, password string, meta *LoginMeta) (*LoginResult, error) {
-	u, err := s.users.GetByEmail(ctx, email)
+	return s.login(ctx, email, password, meta, true)
+}
+
+// Authenticate - то же, что Login, но без сессии и токенов: только проверка (страница входа OAuth)
+func (s *AuthService) Authenticate(ctx context.Context, email, password string, meta *LoginMeta) (*LoginResult, error) {
+	return s.login(ctx, email, password, meta, false)
+}
+
+func (s *AuthService) login(ctx context.Context, email, password string, meta *LoginMeta, issue bool) (*LoginResult, error) {
+	email = normalizeLoginEmail(email)
+	now := s.now()
+
+	u, err := s.checkPassword(ctx, email, password, meta, now, domain.AuthEventLoginFailed)
 	if err != nil {
-		if errors.Is(err, repository.ErrNotFound) {
-			slog.Error("auth.login.getByEmail failed", slog.Any("err", err))
-			return nil, errs.ErrInvalidCredentials
-		}
 		return nil, err
 	}
+	// пароль верный - только теперь можно сказать, что вход закрыт
+	if u.IsDisabled() {
+		s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, email, errs.ErrAccountDisabled)
+		return nil, errs.ErrAccountDisabled
+	}
+	if u.PasswordResetRequired {
+		s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, email, errs.ErrPasswordResetRequired)
+		return nil, errs.ErrPasswordResetRequired
+	}
+	s.upgradePasswordHash(ctx, u, password)
 
-	if err := security.ComparePassword(u.PasswordHash, password); err != nil {
-		slog.Error("auth.login.comparePassword failed", slog.Any("err", err))
+	// со вторым фактором счетчик неудач сбросится только после VerifyMFA
+	mfa, err := s.mfaRequired(ctx, u.ID)
+	if err != nil {
+		slog.Error("auth.login.mfaRequired failed", slog.Any("err", err))
 		return nil, err
 	}
+	if mfa {
+		return s.startMFA(ctx, u, now)
+	}
+	if s.guard != nil {
+		s.guard.success(ctx, email)
+	}
+	if !issue {
+		s.recordUser(ctx, meta, domain.AuthEventLogin, u.ID, loginDetails("password", issue))
+		return &LoginResult{User: u}, nil
+	}
 
 	// todo: добавить реальную передачу oldSessionID
 	access, refresh, err := s.issueTokens(ctx, u.ID, meta, nil)
@@ -134,6 +252,7 @@ func (s *AuthService) Login(ctx context.Context, email, password string, meta *L
 		slog.Error("auth.login.generateIssueToken failed", slog.Any("err", err))
 		return nil, err
 	}
+	s.recordUser(ctx, meta, domain.AuthEventLogin, u.ID, loginDetails("password", issue))
 
 	return &LoginResult{
 		User:         u,
@@ -142,6 +261,128 @@ func (s *AuthService) Login(ctx context.Context, email, password string, meta *L
 	}, nil
 }
 
+// checkPassword - email (уже нормализованный) и пароль с учетом неудачных входов.
+// Неизвестный email и неверный пароль неотличимы: одна и та же ошибка и одно и то же время ответа.
+// Неудача пишется в журнал как failed
+func (s *AuthService) checkPassword(ctx context.Context, email, password string, meta *LoginMeta, now time.Time, failed domain.AuthEventType) (*domain.User, error) {
+	var keys []loginKey
+	if s.guard != nil {
+		keys = loginKeys(email, meta)
+		if err := s.guard.check(ctx, keys, now); err != nil {
+			s.recordFailure(ctx, meta, failed, nil, email, err)
+			return nil, err
+		}
+	}
+
+	u, err := s.users.GetByEmail(ctx, email)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		slog.Error("auth.login.getByEmail failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	// без пароля (вход только через провайдера) сравниваем с dummy: по времени ответа это не отличить.
+	// Нет свободного слота - это не неудачная попытка, счетчики не трогаем
+	var hash string
+	var cmpErr error
+	if err := s.withHashSlot(func() error {
+		hash = s.dummyPasswordHash()
+		if u != nil && security.HasUsablePassword(u.PasswordHash) {
+			hash = u.PasswordHash
+		}
+		cmpErr = security.ComparePassword(hash, password)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if u == nil || cmpErr != nil || hash != u.PasswordHash {
+		if s.guard != nil {
+			s.guard.fail(ctx, keys, now)
+		}
+		s.recordFailure(ctx, meta, failed, u, email, errs.ErrInvalidCredentials)
+		return nil, errs.ErrInvalidCredentials
+	}
+
+	return u, nil
+}
+
+// upgradePasswordHash - пароль только что подошел: если хеш посчитан старым алгоритмом или
+// с другой стоимостью, пересчитываем по текущему конфигу. Ошибки входу не мешают
+func (s *AuthService) upgradePasswordHash(ctx context.Context, u *domain.User, password string) {
+	if !security.NeedsRehash(u.PasswordHash, &s.passPolicy) {
+		return
+	}
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		slog.Error("auth.login.rehashPassword failed", slog.Any("err", err))
+		return
+	}
+	ok, err := s.users.RehashPassword(ctx, u.ID, u.PasswordHash, hash)
+	if err != nil {
+		slog.Error("auth.login.rehashPassword failed", slog.Any("err", err))
+		return
+	}
+	if ok {
+		u.PasswordHash = hash
+	}
+}
+
+// ChangePassword - смена пароля по текущему и коду второго фактора, если он включен.
+// Так же выполняется принудительный сброс от админа. Все сессии завершаются: дальше вход с новым паролем
+func (s *AuthService) ChangePassword(ctx context.Context, email, current, next, mfaCode string, meta *LoginMeta) error {
+	email = normalizeLoginEmail(email)
+	now := s.now()
+
+	u, err := s.checkPassword(ctx, email, current, meta, now, domain.AuthEventPasswordChangeFailed)
+	if err != nil {
+		return err
+	}
+	if u.IsDisabled() {
+		s.recordFailure(ctx, meta, domain.AuthEventPasswordChangeFailed, u, email, errs.ErrAccountDisabled)
+		return errs.ErrAccountDisabled
+	}
+
+	mfa, err := s.mfaRequired(ctx, u.ID)
+	if err != nil {
+		slog.Error("auth.changePassword.mfaRequired failed", slog.Any("err", err))
+		return err
+	}
+	if mfa {
+		ok, err := s.checkSecondFactor(ctx, u.ID, mfaCode, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			if s.guard != nil {
+				s.guard.fail(ctx, loginKeys(email, meta), now)
+			}
+			s.recordFailure(ctx, meta, domain.AuthEventPasswordChangeFailed, u, email, errs.ErrInvalidMFACode)
+			return errs.ErrInvalidMFACode
+		}
+	}
+	if s.guard != nil {
+		s.guard.success(ctx, email)
+	}
+
+	if err := security.CheckPasswordPolicy(ctx, next, u.Email, &s.passPolicy); err != nil {
+		return err
+	}
+	hash, err := s.hashPassword(next)
+	if err != nil {
+		return err
+	}
+	if err := s.users.UpdatePasswordHash(ctx, u.ID, hash, now); err != nil {
+		slog.Error("auth.changePassword.updatePasswordHash failed", slog.Any("err", err))
+		return err
+	}
+	if _, err := s.sessions.DeleteByUser(ctx, u.ID); err != nil {
+		slog.Error("auth.changePassword.deleteSessions failed", slog.Any("err", err))
+		return err
+	}
+	s.recordUser(ctx, meta, domain.AuthEventPasswordChange, u.ID, nil)
+
+	return nil
+}
+
 // Refresh по refresh-токену выдает новую пару; старую запись удаляет
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string, meta *LoginMeta) (*RefreshResult, error) {
 	hash := security.SHA256HexOfString(refreshToken)
@@ -149,6 +390,7 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string, meta *Lo
 	if err != nil {
 		if errors.Is(err, repository.ErrNotFound) {
 			slog.Error("auth.refresh.getByTokenHash failed", slog.Any("err", err))
+			s.detectRefreshReuse(ctx, hash, meta)
 			return nil, errs.ErrInvalidCredentials
 		}
 		return nil, err
@@ -161,11 +403,17 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string, meta *Lo
 		return nil, errs.ErrSessionExpired
 	}
 
-	access, newRefresh, err := s.issueTokens(ctx, sess.UserID, meta, &sess.ID)
+	access, newRefresh, err := s.issueTokens(ctx, sess.UserID, meta, sess)
+	if errors.Is(err, errRefreshConsumed) {
+		// тот же токен только что обменял параллельный запрос - это повтор, как и обмен уже обмененного
+		s.revokeOnReuse(ctx, sess.UserID, meta)
+		return nil, errs.ErrInvalidCredentials
+	}
 	if err != nil {
 		slog.Error("auth.refresh.generateIssueToken failed", slog.Any("err", err))
 		return nil, err
 	}
+	s.recordUser(ctx, meta, domain.AuthEventRefresh, sess.UserID, nil)
 
 	return &RefreshResult{
 		UserID:       sess.UserID,
@@ -187,10 +435,46 @@ func (s *AuthService) Me(ctx context.Context, userID domain.UserID) (*domain.Use
 	return user, nil
 }
 
+// detectRefreshReuse - предъявили уже обмененный refresh-токен: им пользуется кто-то еще
+// (украли и успели обменять раньше владельца или наоборот). Какая из копий настоящая, не понять,
+// поэтому завершаем все сессии пользователя
+func (s *AuthService) detectRefreshReuse(ctx context.Context, hash string, meta *LoginMeta) {
+	userID, err := s.sessions.GetRotatedOwner(ctx, hash)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			slog.Error("auth.refresh.getRotatedOwner failed", slog.Any("err", err))
+		}
+		return
+	}
+
+	s.revokeOnReuse(ctx, userID, meta)
+}
+
+// revokeOnReuse - refresh-токен пользователя предъявлен повторно: завершаем все его сессии
+func (s *AuthService) revokeOnReuse(ctx context.Context, userID domain.UserID, meta *LoginMeta) {
+	n, err := s.sessions.DeleteByUser(ctx, userID)
+	if err != nil {
+		slog.Error("auth.refresh.reuse.deleteSessions failed", slog.Any("err", err))
+	}
+	slog.Warn("auth.refresh.reuseDetected", "user_id", int64(userID), "revoked_sessions", n)
+	s.recordUser(ctx, meta, domain.AuthEventRefreshReuse, userID, map[string]string{"revoked_sessions": strconv.FormatInt(n, 10)})
+}
+
+// loginDetails - способ входа; issue == false - вход на странице OAuth, без своих токенов
+func loginDetails(method string, issue bool) map[string]string {
+	d := map[string]string{"method": method}
+	if !issue {
+		d["via"] = "oauth"
+	}
+
+	return d
+}
+
 // Метаданные для записи сессии
 type LoginMeta struct {
 	UserAgent *string
 	IP        *netip.Addr
+	RequestID *string // для журнала безопасности
 }
 
 func (s *AuthService) AccessTTL() time.Duration { return s.jwt.TTL() }
@@ -206,12 +490,25 @@ func (s *AuthService) UserIDFromAccessToken(token string) (domain.UserID, error)
 }
 
 // issueTokens: создает refresh-сессию и подпистывает токен
-// Если oldSessionID != nil - сначала удаляет старую запись
-func (s *AuthService) issueTokens(ctx context.Context, userID domain.UserID, meta *LoginMeta, oldSessionID *domain.SessionID) (access string, refresh string, err error) {
+// Если old != nil - это обмен: см. rotateSession
+func (s *AuthService) issueTokens(ctx context.Context, userID domain.UserID, meta *LoginMeta, old *domain.Session) (access string, refresh string, err error) {
 	now := s.now()
 
-	// access
-	access, err = s.jwt.SignAccessToken(userID, now)
+	// заблокированному - ничего; это же закрывает Refresh, вход по passkey и через OIDC
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	if u.IsDisabled() {
+		return "", "", errs.ErrAccountDisabled
+	}
+
+	// access: роли и права читаем при каждой выдаче, так что изменения доходят не позже следующего refresh
+	roles, scopes, err := s.UserGrants(ctx, userID)
+	if err != nil {
+		return "", "", err
+	}
+	access, err = s.jwt.SignAccessToken(userID, roles, scopes, now)
 	if err != nil {
 		return "", "", err
 	}
@@ -239,8 +536,11 @@ func (s *AuthService) issueTokens(ctx context.Context, userID domain.UserID, met
 		}
 	}
 
-	if oldSessionID != nil {
-		_ = s.sessions.DeleteByID(ctx, *oldSessionID)
+	if old != nil {
+		if err := s.rotateSession(ctx, old, sess); err != nil {
+			return "", "", err
+		}
+		return access, refresh, nil
 	}
 
 	if _, err := s.sessions.Create(ctx, sess); err != nil {
@@ -249,3 +549,118 @@ func (s *AuthService) issueTokens(ctx context.Context, userID domain.UserID, met
 
 	return access, refresh, nil
 }
+
+// errRefreshConsumed - сессию refresh-токена уже удалил параллельный обмен
+var errRefreshConsumed = errors.New("refresh token already consumed")
+
+// rotateSession - обмен refresh-токена одной транзакцией: старая сессия удаляется одним DELETE по token_hash,
+// и только если строка была, создается новая и старый хеш запоминается для обнаружения повтора.
+// Проверка GetByTokenHash до этого не защищает от гонки: два запроса с одним токеном проходят ее оба
+func (s *AuthService) rotateSession(ctx context.Context, old, next *domain.Session) error {
+	rotate := func(sessions repository.SessionRepository) error {
+		if err := sessions.DeleteByTokenHash(ctx, old.TokenHash); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errRefreshConsumed
+			}
+			return err
+		}
+		if _, err := sessions.Create(ctx, next); err != nil {
+			return err
+		}
+		return sessions.AddRotated(ctx, old.TokenHash, old.UserID, old.ExpiresAt)
+	}
+
+	if s.tx == nil {
+		return rotate(s.sessions)
+	}
+	return s.tx.InTx(ctx, func(tx repository.Tx) error {
+		return rotate(tx.Sessions())
+	})
+}
+
+// withHashSlot - fn под семафором hashSlots. argon2id держит memoryKiB памяти на каждый вызов, поэтому
+// без лимита поток входов съедает память сервиса. Слотов нет - сразу ErrPasswordHashBusy, клиент повторит
+func (s *AuthService) withHashSlot(fn func() error) error {
+	select {
+	case s.hashSlots <- struct{}{}:
+	default:
+		slog.Warn("auth.password.hashSlots exhausted", slog.Int("limit", cap(s.hashSlots)))
+		return errs.ErrPasswordHashBusy
+	}
+	defer func() { <-s.hashSlots }()
+
+	return fn()
+}
+
+// hashPassword - security.HashPassword по текущему конфигу под семафором
+func (s *AuthService) hashPassword(plain string) (string, error) {
+	var hash string
+	err := s.withHashSlot(func() (err error) {
+		hash, err = security.HashPassword(plain, &s.passPolicy)
+		return err
+	})
+
+	return hash, err
+}
+
+// dummyPasswordHash - хеш для "холостого" сравнения, считается один раз
+func (s *AuthService) dummyPasswordHash() string {
+	s.dummyOnce.Do(func() {
+		hash, err := security.DummyHash(&s.passPolicy)
+		if err != nil {
+			slog.Error("auth.login.dummyHash failed", slog.Any("err", err))
+			return
+		}
+		s.dummyHash = hash
+	})
+
+	return s.dummyHash
+}
+
+// createUser - пользователь, роль по умолчанию, привязка к провайдеру (если ident != nil)
+// и user.registered в outbox одной транзакцией: либо есть все, либо ничего
+func (s *AuthService) createUser(ctx context.Context, u *domain.User, ident *domain.Identity) (domain.UserID, error) {
+	if s.tx == nil {
+		id, err := s.users.Create(ctx, u)
+		if err != nil {
+			return id, err
+		}
+		if s.rbac != nil {
+			if err := s.assignDefaultRole(ctx, s.rbac.roles, id, u.CreatedAt); err != nil {
+				return id, err
+			}
+		}
+		if ident == nil {
+			return id, nil
+		}
+		ident.UserID = id
+		ident.ID, err = s.oidc.identities.Create(ctx, ident)
+		return id, err
+	}
+
+	var id domain.UserID
+	err := s.tx.InTx(ctx, func(tx repository.Tx) error {
+		var err error
+		if id, err = tx.Users().Create(ctx, u); err != nil {
+			return err
+		}
+		if err := s.assignDefaultRole(ctx, tx.Roles(), id, u.CreatedAt); err != nil {
+			return err
+		}
+		if ident != nil {
+			ident.UserID = id
+			if ident.ID, err = tx.Identities().Create(ctx, ident); err != nil {
+				return err
+			}
+		}
+
+		return tx.Outbox().Add(ctx, events.UserRegistered{
+			UserID:      int64(id),
+			Email:       u.Email,
+			DisplayName: u.DisplayName,
+			CreatedAt:   u.CreatedAt,
+		})
+	})
+
+	return id, err
+}
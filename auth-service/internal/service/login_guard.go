@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+)
+
+// LoginGuardConfig - защита от перебора паролей.
+// После каждой неудачи следующая попытка разрешена не раньше чем через BaseDelay*2^(n-1) (но не больше MaxDelay),
+// после MaxFailures неудач подряд вход блокируется на Lockout
+type LoginGuardConfig struct {
+	MaxFailures   int           // неудач по аккаунту до блокировки, по умолчанию 5
+	IPMaxFailures int           // неудач с одного IP до блокировки, по умолчанию 50
+	Window        time.Duration // неудачи старше забываются, по умолчанию 15m
+	Lockout       time.Duration // на сколько блокируем, по умолчанию 15m
+	BaseDelay     time.Duration // пауза после первой неудачи, по умолчанию 1s
+	MaxDelay      time.Duration // потолок паузы, по умолчанию 30s
+}
+
+func (c LoginGuardConfig) withDefaults() LoginGuardConfig {
+	if c.MaxFailures <= 0 {
+		c.MaxFailures = 5
+	}
+	if c.IPMaxFailures <= 0 {
+		c.IPMaxFailures = 50
+	}
+	if c.Window <= 0 {
+		c.Window = 15 * time.Minute
+	}
+	if c.Lockout <= 0 {
+		c.Lockout = 15 * time.Minute
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = time.Second
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = 30 * time.Second
+	}
+	if c.MaxDelay < c.BaseDelay {
+		c.MaxDelay = c.BaseDelay
+	}
+
+	return c
+}
+
+// loginGuard - счетчики неудачных входов по аккаунту и по IP (в Postgres, чтобы переживали рестарт и были общими для реплик)
+type loginGuard struct {
+	repo repository.LoginFailuresRepository
+	cfg  LoginGuardConfig
+}
+
+type loginKey struct {
+	scope domain.LoginScope
+	key   string
+}
+
+func loginKeys(email string, meta *LoginMeta) []loginKey {
+	keys := []loginKey{{scope: domain.LoginScopeAccount, key: email}}
+	if meta != nil && meta.IP != nil {
+		keys = append(keys, loginKey{scope: domain.LoginScopeIP, key: meta.IP.String()})
+	}
+
+	return keys
+}
+
+func (g *loginGuard) limit(scope domain.LoginScope) int {
+	if scope == domain.LoginScopeIP {
+		return g.cfg.IPMaxFailures
+	}
+
+	return g.cfg.MaxFailures
+}
+
+// delay - сколько ждать после n неудач подряд
+func (g *loginGuard) delay(n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	d := g.cfg.BaseDelay
+	for i := 1; i < n && d < g.cfg.MaxDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, g.cfg.MaxDelay)
+}
+
+// check - ErrTooManyAttempts, если ключ заблокирован или с прошлой неудачи прошло меньше паузы
+func (g *loginGuard) check(ctx context.Context, keys []loginKey, now time.Time) error {
+	for _, k := range keys {
+		f, err := g.repo.Get(ctx, k.scope, k.key)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return err
+		}
+		if f.Locked(now) {
+			return errs.ErrTooManyAttempts
+		}
+		if f.LastFailedAt.Before(now.Add(-g.cfg.Window)) {
+			continue
+		}
+		// пауза считается только для неудач, которые еще не привели к блокировке
+		if f.LockedUntil == nil && now.Before(f.LastFailedAt.Add(g.delay(f.Failures))) {
+			slog.Info("auth.login.throttled", "scope", string(k.scope), "key", k.key, "failures", f.Failures)
+			return errs.ErrTooManyAttempts
+		}
+	}
+
+	return nil
+}
+
+// fail - учитывает неудачу и при превышении лимита блокирует ключ
+func (g *loginGuard) fail(ctx context.Context, keys []loginKey, now time.Time) {
+	for _, k := range keys {
+		f, err := g.repo.RegisterFailure(ctx, k.scope, k.key, now, now.Add(-g.cfg.Window))
+		if err != nil {
+			slog.Error("auth.login.registerFailure failed", slog.Any("err", err))
+			continue
+		}
+		if f.Failures < g.limit(k.scope) {
+			continue
+		}
+
+		until := now.Add(g.cfg.Lockout)
+		if err := g.repo.Lock(ctx, k.scope, k.key, until); err != nil {
+			slog.Error("auth.login.lock failed", slog.Any("err", err))
+			continue
+		}
+		slog.Warn("auth.login.locked",
+			"scope", string(k.scope),
+			"key", k.key,
+			"failures", f.Failures,
+			"locked_until", until,
+		)
+	}
+}
+
+// success - сбрасывает счетчик аккаунта; счетчик IP не трогаем, иначе перебор
+// можно "разбавлять" входом в свой аккаунт
+func (g *loginGuard) success(ctx context.Context, email string) {
+	if err := g.repo.Reset(ctx, domain.LoginScopeAccount, email); err != nil {
+		slog.Error("auth.login.resetFailures failed", slog.Any("err", err))
+	}
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
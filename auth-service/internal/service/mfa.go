@@ -0,0 +1,369 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+)
+
+// MFAConfig - второй фактор (TOTP)
+type MFAConfig struct {
+	Issuer        string        // название в приложении-аутентификаторе, по умолчанию cwrk-planet
+	ChallengeTTL  time.Duration // сколько живет mfa_token, по умолчанию 5m
+	MaxAttempts   int           // неверных кодов на один mfa_token, по умолчанию 5
+	RecoveryCodes int           // сколько кодов восстановления выдавать, по умолчанию 10
+}
+
+func (c MFAConfig) withDefaults() MFAConfig {
+	if c.Issuer == "" {
+		c.Issuer = "cwrk-planet"
+	}
+	if c.ChallengeTTL <= 0 {
+		c.ChallengeTTL = 5 * time.Minute
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.RecoveryCodes <= 0 {
+		c.RecoveryCodes = 10
+	}
+
+	return c
+}
+
+type mfaDeps struct {
+	repo       repository.MFARepository
+	challenges repository.MFAChallengeRepository
+	box        *security.SecretBox // nil - ключ шифрования не задан, TOTP недоступен
+	cfg        MFAConfig
+}
+
+// SetMFA - включает двухфакторный вход. box == nil: подключить TOTP нельзя,
+// но уже выданные коды восстановления продолжают работать
+func (s *AuthService) SetMFA(repo repository.MFARepository, challenges repository.MFAChallengeRepository, box *security.SecretBox, cfg MFAConfig) {
+	s.mfa = &mfaDeps{repo: repo, challenges: challenges, box: box, cfg: cfg.withDefaults()}
+}
+
+// EnrollTOTP создает новый (еще не подтвержденный) секрет
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID domain.UserID) (*security.TOTPKey, error) {
+	if s.mfa == nil || s.mfa.box == nil {
+		return nil, errs.ErrMFAUnavailable
+	}
+	st, err := s.mfa.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if st.Enabled() {
+		return nil, errs.ErrMFAAlreadyEnabled
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := security.NewTOTPKey(s.mfa.cfg.Issuer, u.Email)
+	if err != nil {
+		slog.Error("auth.mfa.enroll.newKey failed", slog.Any("err", err))
+		return nil, err
+	}
+	enc, err := s.mfa.box.Seal(key.Secret)
+	if err != nil {
+		slog.Error("auth.mfa.enroll.seal failed", slog.Any("err", err))
+		return nil, err
+	}
+	if err := s.mfa.repo.SetPendingTOTP(ctx, userID, enc, s.now()); err != nil {
+		slog.Error("auth.mfa.enroll.setPending failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// ConfirmTOTP включает TOTP по первому коду и возвращает коды восстановления (в открытом виде - только здесь)
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID domain.UserID, code string) ([]string, error) {
+	if s.mfa == nil || s.mfa.box == nil {
+		return nil, errs.ErrMFAUnavailable
+	}
+	st, err := s.mfa.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if st.Enabled() {
+		return nil, errs.ErrMFAAlreadyEnabled
+	}
+	if st.SecretEnc == nil {
+		return nil, errs.ErrMFANotEnrolled
+	}
+	secret, err := s.mfa.box.Open(*st.SecretEnc)
+	if err != nil {
+		slog.Error("auth.mfa.confirm.open failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	now := s.now()
+	step, ok := security.ValidateTOTP(secret, code, now, 0)
+	if !ok {
+		return nil, errs.ErrInvalidMFACode
+	}
+
+	codes := make([]string, 0, s.mfa.cfg.RecoveryCodes)
+	hashes := make([]string, 0, s.mfa.cfg.RecoveryCodes)
+	for range s.mfa.cfg.RecoveryCodes {
+		c, err := security.NewRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, security.HashRecoveryCode(c))
+	}
+
+	err = s.inMFATx(ctx, func(repo repository.MFARepository) error {
+		if err := repo.EnableTOTP(ctx, userID, step, now); err != nil {
+			return err
+		}
+		return repo.ReplaceRecoveryCodes(ctx, userID, hashes, now)
+	})
+	if err != nil {
+		slog.Error("auth.mfa.confirm.enable failed", slog.Any("err", err))
+		return nil, err
+	}
+	slog.Info("auth.mfa.totp.enabled", "user_id", int64(userID))
+	s.recordUser(ctx, nil, domain.AuthEventTOTPEnabled, userID, nil)
+
+	return codes, nil
+}
+
+// DisableTOTP выключает TOTP; нужен текущий код или код восстановления
+func (s *AuthService) DisableTOTP(ctx context.Context, userID domain.UserID, code string) error {
+	if s.mfa == nil {
+		return errs.ErrMFAUnavailable
+	}
+	ok, err := s.checkSecondFactor(ctx, userID, code, s.now())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errs.ErrInvalidMFACode
+	}
+
+	err = s.inMFATx(ctx, func(repo repository.MFARepository) error {
+		if err := repo.DisableTOTP(ctx, userID, s.now()); err != nil {
+			return err
+		}
+		return repo.DeleteRecoveryCodes(ctx, userID)
+	})
+	if err != nil {
+		slog.Error("auth.mfa.disable failed", slog.Any("err", err))
+		return err
+	}
+	slog.Info("auth.mfa.totp.disabled", "user_id", int64(userID))
+	s.recordUser(ctx, nil, domain.AuthEventTOTPDisabled, userID, nil)
+
+	return nil
+}
+
+// VerifyMFA - второй шаг входа: mfa_token из Login + код
+func (s *AuthService) VerifyMFA(ctx context.Context, mfaToken, code string, meta *LoginMeta) (*LoginResult, error) {
+	return s.verifyMFA(ctx, mfaToken, code, meta, true)
+}
+
+// AuthenticateMFA - второй шаг Authenticate, тоже без токенов
+func (s *AuthService) AuthenticateMFA(ctx context.Context, mfaToken, code string, meta *LoginMeta) (*LoginResult, error) {
+	return s.verifyMFA(ctx, mfaToken, code, meta, false)
+}
+
+func (s *AuthService) verifyMFA(ctx context.Context, mfaToken, code string, meta *LoginMeta, issue bool) (*LoginResult, error) {
+	if s.mfa == nil {
+		return nil, errs.ErrMFAUnavailable
+	}
+	now := s.now()
+
+	hash := security.SHA256HexOfString(mfaToken)
+	c, err := s.mfa.challenges.GetByTokenHash(ctx, hash)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if c.IsExpired(now) {
+		_ = s.mfa.challenges.Delete(ctx, hash)
+		return nil, errs.ErrInvalidMFAToken
+	}
+
+	u, err := s.users.GetByID(ctx, c.UserID)
+	if err != nil {
+		slog.Error("auth.mfa.verify.getUserByID failed", slog.Any("err", err))
+		return nil, err
+	}
+	if u.IsDisabled() {
+		_ = s.mfa.challenges.Delete(ctx, hash)
+		s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, "", errs.ErrAccountDisabled)
+		return nil, errs.ErrAccountDisabled
+	}
+
+	// неверные коды считаются как неудачные входы: иначе с известным паролем
+	// можно перебирать коды, каждый раз получая новый mfa_token
+	email := normalizeLoginEmail(u.Email)
+	var keys []loginKey
+	if s.guard != nil {
+		keys = loginKeys(email, meta)
+		if err := s.guard.check(ctx, keys, now); err != nil {
+			s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, "", err)
+			return nil, err
+		}
+	}
+
+	ok, err := s.checkSecondFactor(ctx, u.ID, code, now)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if s.guard != nil {
+			s.guard.fail(ctx, keys, now)
+		}
+		n, err := s.mfa.challenges.IncrementAttempts(ctx, hash)
+		if err == nil && n >= s.mfa.cfg.MaxAttempts {
+			_ = s.mfa.challenges.Delete(ctx, hash)
+			slog.Warn("auth.mfa.challenge.exhausted", "user_id", int64(u.ID), "attempts", n)
+		}
+		s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, "", errs.ErrInvalidMFACode)
+		return nil, errs.ErrInvalidMFACode
+	}
+
+	// mfa_token одноразовый: из параллельных запросов пройдет тот, кто удалил запись
+	if err := s.mfa.challenges.Delete(ctx, hash); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.ErrInvalidMFAToken
+		}
+		return nil, err
+	}
+	if s.guard != nil {
+		s.guard.success(ctx, email)
+	}
+	if !issue {
+		s.recordUser(ctx, meta, domain.AuthEventLogin, u.ID, loginDetails("mfa", issue))
+		return &LoginResult{User: u}, nil
+	}
+
+	access, refresh, err := s.issueTokens(ctx, u.ID, meta, nil)
+	if err != nil {
+		slog.Error("auth.mfa.verify.generateIssueToken failed", slog.Any("err", err))
+		return nil, err
+	}
+	s.recordUser(ctx, meta, domain.AuthEventLogin, u.ID, loginDetails("mfa", issue))
+
+	return &LoginResult{
+		User:         u,
+		AccessToken:  access,
+		RefreshToken: refresh,
+	}, nil
+}
+
+// startMFA - пароль верный, но нужен второй фактор: вместо токенов выдаем mfa_token
+func (s *AuthService) startMFA(ctx context.Context, u *domain.User, now time.Time) (*LoginResult, error) {
+	token, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &domain.MFAChallenge{
+		TokenHash: security.SHA256HexOfString(token),
+		UserID:    u.ID,
+		ExpiresAt: now.Add(s.mfa.cfg.ChallengeTTL),
+		CreatedAt: now,
+	}
+	if err := s.mfa.challenges.Create(ctx, c); err != nil {
+		slog.Error("auth.login.createMfaChallenge failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &LoginResult{
+		User:         u,
+		MFARequired:  true,
+		MFAToken:     token,
+		MFAExpiresIn: s.mfa.cfg.ChallengeTTL,
+	}, nil
+}
+
+// mfaRequired - включен ли у пользователя второй фактор
+func (s *AuthService) mfaRequired(ctx context.Context, userID domain.UserID) (bool, error) {
+	if s.mfa == nil {
+		return false, nil
+	}
+	st, err := s.mfa.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return st.Enabled(), nil
+}
+
+// checkSecondFactor - 6 цифр проверяются как TOTP, остальное - как код восстановления
+func (s *AuthService) checkSecondFactor(ctx context.Context, userID domain.UserID, code string, now time.Time) (bool, error) {
+	st, err := s.mfa.repo.GetTOTP(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if !st.Enabled() {
+		return false, errs.ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if !isTOTPCode(code) {
+		used, err := s.mfa.repo.UseRecoveryCode(ctx, userID, security.HashRecoveryCode(code), now)
+		if err != nil {
+			return false, err
+		}
+		if used {
+			slog.Info("auth.mfa.recoveryCode.used", "user_id", int64(userID))
+		}
+		return used, nil
+	}
+
+	if s.mfa.box == nil {
+		return false, errs.ErrMFAUnavailable
+	}
+	secret, err := s.mfa.box.Open(*st.SecretEnc)
+	if err != nil {
+		slog.Error("auth.mfa.open failed", slog.Any("err", err))
+		return false, err
+	}
+	step, ok := security.ValidateTOTP(secret, code, now, st.LastStep)
+	if !ok {
+		return false, nil
+	}
+
+	return s.mfa.repo.AdvanceTOTPStep(ctx, userID, step)
+}
+
+// inMFATx - несколько изменений MFA атомарно (если транзакции включены)
+func (s *AuthService) inMFATx(ctx context.Context, fn func(repo repository.MFARepository) error) error {
+	if s.tx == nil {
+		return fn(s.mfa.repo)
+	}
+
+	return s.tx.InTx(ctx, func(tx repository.Tx) error {
+		return fn(tx.MFA())
+	})
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
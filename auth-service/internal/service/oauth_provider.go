@@ -0,0 +1,529 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// OAuthProviderConfig - auth-service как OpenID Connect провайдер для других внутренних приложений
+type OAuthProviderConfig struct {
+	Issuer     string        // внешний URL auth-service, он же iss в токенах и база для discovery
+	CodeTTL    time.Duration // сколько живет authorization code, по умолчанию 1m
+	AccessTTL  time.Duration // access-токен клиента, по умолчанию 15m
+	RefreshTTL time.Duration // refresh-токен клиента, по умолчанию 30d
+	SSOTTL     time.Duration // сколько помним вход на странице authorize, по умолчанию 1h
+}
+
+func (c OAuthProviderConfig) withDefaults() OAuthProviderConfig {
+	c.Issuer = strings.TrimRight(c.Issuer, "/")
+	if c.CodeTTL <= 0 {
+		c.CodeTTL = time.Minute
+	}
+	if c.AccessTTL <= 0 {
+		c.AccessTTL = 15 * time.Minute
+	}
+	if c.RefreshTTL <= 0 {
+		c.RefreshTTL = 30 * 24 * time.Hour
+	}
+	if c.SSOTTL <= 0 {
+		c.SSOTTL = time.Hour
+	}
+
+	return c
+}
+
+const (
+	scopeOpenID  = "openid"
+	scopeEmail   = "email"
+	scopeProfile = "profile"
+
+	ssoAudience = "sso"
+
+	// token_use: ключ общий с access-токенами платформы (security.TokenUseAccess), поэтому каждый токен
+	// провайдера помечен своим значением - authz.Verifier в gateway и room-service их не примет
+	tokenUseOAuthAccess = "oauth_access"
+	tokenUseID          = "id"
+	tokenUseSSO         = "sso"
+)
+
+type oauthDeps struct {
+	clients repository.OAuthClientRepository
+	grants  repository.OAuthGrantRepository
+	cfg     OAuthProviderConfig
+}
+
+// SetOAuthProvider - включает OIDC-эндпоинты (authorize, token, userinfo) для клиентов из oauth_clients
+func (s *AuthService) SetOAuthProvider(clients repository.OAuthClientRepository, grants repository.OAuthGrantRepository, cfg OAuthProviderConfig) error {
+	cfg = cfg.withDefaults()
+	if cfg.Issuer == "" {
+		return fmt.Errorf("oauth provider: issuer is required")
+	}
+
+	s.oauth = &oauthDeps{clients: clients, grants: grants, cfg: cfg}
+	return nil
+}
+
+// OAuthIssuer - iss провайдера; пустая строка - провайдер выключен
+func (s *AuthService) OAuthIssuer() string {
+	if s.oauth == nil {
+		return ""
+	}
+	return s.oauth.cfg.Issuer
+}
+
+// JWKS - публичный ключ подписи токенов (наших и выданных клиентам)
+func (s *AuthService) JWKS() ([]byte, error) {
+	return s.jwt.JWKS()
+}
+
+// AuthorizeRequest - параметры /oauth/authorize
+type AuthorizeRequest struct {
+	ClientID            string
+	RedirectURI         string
+	ResponseType        string
+	Scope               string
+	State               string
+	Nonce               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+}
+
+// OAuthTokens - ответ token endpoint
+type OAuthTokens struct {
+	AccessToken  string
+	IDToken      string
+	RefreshToken string
+	ExpiresIn    time.Duration
+	Scope        string
+}
+
+// TokenRequest - параметры /oauth/token; ClientSecret пустой у публичных клиентов
+type TokenRequest struct {
+	GrantType    string
+	ClientID     string
+	ClientSecret string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+}
+
+// OAuthClient проверяет client_id и redirect_uri. Пока они не проверены, ошибку
+// нельзя отдавать редиректом на redirect_uri - ее показывают пользователю
+func (s *AuthService) OAuthClient(ctx context.Context, clientID, redirectURI string) (*domain.OAuthClient, error) {
+	if s.oauth == nil {
+		return nil, errs.ErrOAuthUnavailable
+	}
+	c, err := s.oauth.clients.Get(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidClient, "unknown client_id")
+		}
+		return nil, err
+	}
+	if !c.AllowsRedirect(redirectURI) {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidRequest, "redirect_uri is not registered for this client")
+	}
+
+	return c, nil
+}
+
+// CheckAuthorize - остальные параметры запроса; возвращает запрошенные scopes.
+// Ошибки отсюда уже можно отдавать клиенту редиректом
+func (s *AuthService) CheckAuthorize(c *domain.OAuthClient, req *AuthorizeRequest) ([]string, error) {
+	if req.ResponseType != "code" {
+		return nil, errs.NewOAuthError(errs.OAuthUnsupportedResponseType, "only response_type=code is supported")
+	}
+	scopes := parseScopes(req.Scope)
+	if !slices.Contains(scopes, scopeOpenID) {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidScope, "openid scope is required")
+	}
+	if !c.AllowsScopes(scopes) {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidScope, "scope is not allowed for this client")
+	}
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidRequest, "only code_challenge_method=S256 is supported")
+	}
+	if req.CodeChallenge == "" && c.IsPublic() {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidRequest, "code_challenge is required for public clients")
+	}
+
+	return scopes, nil
+}
+
+// NeedsConsent - пользователь еще не разрешал клиенту хотя бы один из scopes
+func (s *AuthService) NeedsConsent(ctx context.Context, userID domain.UserID, clientID string, scopes []string) (bool, error) {
+	granted, err := s.oauth.grants.GetConsent(ctx, userID, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return true, nil
+		}
+		return false, err
+	}
+	for _, sc := range scopes {
+		if !slices.Contains(granted, sc) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Authorize - пользователь вошел и согласился: запоминаем согласие и выдаем одноразовый code
+func (s *AuthService) Authorize(ctx context.Context, userID domain.UserID, authTime time.Time, c *domain.OAuthClient, req *AuthorizeRequest, scopes []string) (string, error) {
+	now := s.now()
+
+	granted, err := s.oauth.grants.GetConsent(ctx, userID, c.ID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return "", err
+	}
+	for _, sc := range scopes {
+		if !slices.Contains(granted, sc) {
+			granted = append(granted, sc)
+		}
+	}
+	if err := s.oauth.grants.SaveConsent(ctx, userID, c.ID, granted, now); err != nil {
+		slog.Error("auth.oauth.authorize.saveConsent failed", slog.Any("err", err))
+		return "", err
+	}
+
+	code, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return "", err
+	}
+	rec := &domain.OAuthCode{
+		CodeHash:    security.SHA256HexOfString(code),
+		ClientID:    c.ID,
+		UserID:      userID,
+		RedirectURI: req.RedirectURI,
+		Scopes:      scopes,
+		AuthTime:    authTime,
+		ExpiresAt:   now.Add(s.oauth.cfg.CodeTTL),
+		CreatedAt:   now,
+	}
+	if req.Nonce != "" {
+		rec.Nonce = &req.Nonce
+	}
+	if req.CodeChallenge != "" {
+		rec.CodeChallenge = &req.CodeChallenge
+	}
+	if err := s.oauth.grants.CreateCode(ctx, rec); err != nil {
+		slog.Error("auth.oauth.authorize.createCode failed", slog.Any("err", err))
+		return "", err
+	}
+	slog.Info("auth.oauth.authorized", "user_id", int64(userID), "client_id", c.ID)
+
+	return code, nil
+}
+
+// Token - token endpoint: authorization_code (с PKCE) и refresh_token
+func (s *AuthService) Token(ctx context.Context, req *TokenRequest) (*OAuthTokens, error) {
+	if s.oauth == nil {
+		return nil, errs.ErrOAuthUnavailable
+	}
+	c, err := s.authenticateOAuthClient(ctx, req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	switch req.GrantType {
+	case "authorization_code":
+		return s.exchangeOAuthCode(ctx, c, req)
+	case "refresh_token":
+		return s.refreshOAuthToken(ctx, c, req)
+	default:
+		return nil, errs.NewOAuthError(errs.OAuthUnsupportedGrantType, "")
+	}
+}
+
+// UserInfo - claims пользователя по access-токену клиента
+func (s *AuthService) UserInfo(ctx context.Context, accessToken string) (map[string]any, error) {
+	if s.oauth == nil {
+		return nil, errs.ErrOAuthUnavailable
+	}
+	claims := &oauthAccessClaims{}
+	if err := s.jwt.Verify(accessToken, claims); err != nil || claims.TokenUse != tokenUseOAuthAccess || claims.Issuer != s.oauth.cfg.Issuer || claims.ClientID == "" {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidToken, "")
+	}
+	id, err := security.SubjectAsUserID(&security.AccessClaims{StandardClaims: claims.StandardClaims})
+	if err != nil {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidToken, "")
+	}
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidToken, "")
+		}
+		return nil, err
+	}
+
+	out := map[string]any{"sub": claims.Subject}
+	addUserClaims(out, u, parseScopes(claims.Scope))
+
+	return out, nil
+}
+
+// SSOToken - подписанная кука входа на странице authorize: чтобы не спрашивать пароль у каждого клиента
+func (s *AuthService) SSOToken(userID domain.UserID, authTime time.Time) (string, error) {
+	now := s.now()
+	return s.jwt.Sign(ssoClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   fmt.Sprint(int64(userID)),
+			Issuer:    s.oauth.cfg.Issuer,
+			Audience:  ssoAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(s.oauth.cfg.SSOTTL).Unix(),
+		},
+		TokenUse: tokenUseSSO,
+		AuthTime: authTime.Unix(),
+	})
+}
+
+// SSOTTL - время жизни куки входа
+func (s *AuthService) SSOTTL() time.Duration {
+	return s.oauth.cfg.SSOTTL
+}
+
+// SSOUser - пользователь и время входа из куки; пользователь удален или заблокирован - ErrInvalidToken
+func (s *AuthService) SSOUser(ctx context.Context, token string) (*domain.User, time.Time, error) {
+	claims := &ssoClaims{}
+	if err := s.jwt.Verify(token, claims); err != nil {
+		return nil, time.Time{}, errs.ErrInvalidToken
+	}
+	if claims.TokenUse != tokenUseSSO || claims.Issuer != s.oauth.cfg.Issuer || claims.Audience != ssoAudience {
+		return nil, time.Time{}, errs.ErrInvalidToken
+	}
+	id, err := security.SubjectAsUserID(&security.AccessClaims{StandardClaims: claims.StandardClaims})
+	if err != nil {
+		return nil, time.Time{}, errs.ErrInvalidToken
+	}
+	u, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, time.Time{}, errs.ErrInvalidToken
+		}
+		return nil, time.Time{}, err
+	}
+	if u.IsDisabled() {
+		return nil, time.Time{}, errs.ErrInvalidToken
+	}
+
+	return u, time.Unix(claims.AuthTime, 0), nil
+}
+
+func (s *AuthService) exchangeOAuthCode(ctx context.Context, c *domain.OAuthClient, req *TokenRequest) (*OAuthTokens, error) {
+	// code одноразовый: забираем сразу, даже если дальше что-то не сойдется
+	code, err := s.oauth.grants.TakeCode(ctx, security.SHA256HexOfString(req.Code))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "invalid authorization code")
+		}
+		return nil, err
+	}
+	now := s.now()
+	if code.IsExpired(now) || code.ClientID != c.ID {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "invalid authorization code")
+	}
+	if code.RedirectURI != req.RedirectURI {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "redirect_uri mismatch")
+	}
+	if code.CodeChallenge != nil && !verifyPKCE(*code.CodeChallenge, req.CodeVerifier) {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "invalid code_verifier")
+	}
+
+	var nonce string
+	if code.Nonce != nil {
+		nonce = *code.Nonce
+	}
+
+	return s.issueOAuthTokens(ctx, c, code.UserID, code.Scopes, code.AuthTime, nonce, now)
+}
+
+func (s *AuthService) refreshOAuthToken(ctx context.Context, c *domain.OAuthClient, req *TokenRequest) (*OAuthTokens, error) {
+	// ротация: старый токен удаляется, выдаем новый
+	t, err := s.oauth.grants.TakeRefreshToken(ctx, security.SHA256HexOfString(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "invalid refresh token")
+		}
+		return nil, err
+	}
+	now := s.now()
+	if t.IsExpired(now) || t.ClientID != c.ID {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "invalid refresh token")
+	}
+
+	// можно сузить scopes, но не расширить
+	scopes := t.Scopes
+	if req.Scope != "" {
+		scopes = parseScopes(req.Scope)
+		for _, sc := range scopes {
+			if !slices.Contains(t.Scopes, sc) {
+				return nil, errs.NewOAuthError(errs.OAuthInvalidScope, "scope exceeds the original grant")
+			}
+		}
+	}
+
+	return s.issueOAuthTokens(ctx, c, t.UserID, scopes, t.AuthTime, "", now)
+}
+
+func (s *AuthService) issueOAuthTokens(ctx context.Context, c *domain.OAuthClient, userID domain.UserID, scopes []string, authTime time.Time, nonce string, now time.Time) (*OAuthTokens, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "user not found")
+		}
+		return nil, err
+	}
+	if u.IsDisabled() {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidGrant, "user is disabled")
+	}
+
+	cfg := s.oauth.cfg
+	sub := fmt.Sprint(int64(userID))
+	scope := strings.Join(scopes, " ")
+
+	access, err := s.jwt.Sign(oauthAccessClaims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   sub,
+			Issuer:    cfg.Issuer,
+			Audience:  c.ID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(cfg.AccessTTL).Unix(),
+		},
+		TokenUse: tokenUseOAuthAccess,
+		Scope:    scope,
+		ClientID: c.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idClaims := jwt.MapClaims{
+		"iss":       cfg.Issuer,
+		"sub":       sub,
+		"aud":       c.ID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(cfg.AccessTTL).Unix(),
+		"auth_time": authTime.Unix(),
+		"token_use": tokenUseID,
+	}
+	if nonce != "" {
+		idClaims["nonce"] = nonce
+	}
+	addUserClaims(idClaims, u, scopes)
+	idToken, err := s.jwt.Sign(idClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return nil, err
+	}
+	err = s.oauth.grants.CreateRefreshToken(ctx, &domain.OAuthRefreshToken{
+		TokenHash: security.SHA256HexOfString(refresh),
+		ClientID:  c.ID,
+		UserID:    userID,
+		Scopes:    scopes,
+		AuthTime:  authTime,
+		ExpiresAt: now.Add(cfg.RefreshTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		slog.Error("auth.oauth.token.createRefreshToken failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &OAuthTokens{
+		AccessToken:  access,
+		IDToken:      idToken,
+		RefreshToken: refresh,
+		ExpiresIn:    cfg.AccessTTL,
+		Scope:        scope,
+	}, nil
+}
+
+// authenticateOAuthClient - у конфиденциального клиента секрет обязателен, у публичного его нет (вместо него PKCE)
+func (s *AuthService) authenticateOAuthClient(ctx context.Context, clientID, secret string) (*domain.OAuthClient, error) {
+	c, err := s.oauth.clients.Get(ctx, clientID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidClient, "")
+		}
+		return nil, err
+	}
+	if c.IsPublic() {
+		if secret != "" {
+			return nil, errs.NewOAuthError(errs.OAuthInvalidClient, "")
+		}
+		return c, nil
+	}
+	if !security.ConstantTimeEqual(security.SHA256HexOfString(secret), *c.SecretHash) {
+		return nil, errs.NewOAuthError(errs.OAuthInvalidClient, "")
+	}
+
+	return c, nil
+}
+
+// oauthAccessClaims - access-токен клиента; aud = client_id, отличается от наших токенов iss и client_id
+type oauthAccessClaims struct {
+	jwt.StandardClaims
+	TokenUse string `json:"token_use"`
+	Scope    string `json:"scope"`
+	ClientID string `json:"client_id"`
+}
+
+type ssoClaims struct {
+	jwt.StandardClaims
+	TokenUse string `json:"token_use"`
+	AuthTime int64  `json:"auth_time"`
+}
+
+// addUserClaims - email и профиль отдаем, только если клиент их запросил
+func addUserClaims(claims map[string]any, u *domain.User, scopes []string) {
+	if slices.Contains(scopes, scopeEmail) {
+		claims["email"] = u.Email
+		claims["email_verified"] = u.EmailVerified
+	}
+	if slices.Contains(scopes, scopeProfile) && u.DisplayName != nil {
+		claims["name"] = *u.DisplayName
+		claims["display_name"] = *u.DisplayName
+	}
+}
+
+func parseScopes(scope string) []string {
+	var out []string
+	for _, s := range strings.Fields(scope) {
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+
+	return out
+}
+
+// verifyPKCE - только S256: BASE64URL(SHA256(verifier)) == challenge
+func verifyPKCE(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+
+	return security.ConstantTimeEqual(base64.RawURLEncoding.EncodeToString(sum[:]), challenge)
+}
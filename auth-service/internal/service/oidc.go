@@ -0,0 +1,497 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProviderConfig - внешний провайдер (Google, GitLab, Keycloak...), с которым мы - relying party
+type OIDCProviderConfig struct {
+	Name         string // короткое имя в URL и в user_identities.provider, напр. google
+	Issuer       string // по нему discovery: {issuer}/.well-known/openid-configuration
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string   // куда провайдер вернет code и state (страница фронта)
+	Scopes       []string // по умолчанию openid, email, profile
+}
+
+// OIDCConfig - вход через внешних провайдеров
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration // сколько ждем возврата от провайдера, по умолчанию 10m
+	// вход через провайдера не старше этого подтверждает удаление аккаунта без пароля и TOTP, по умолчанию 5m
+	ReauthMaxAge time.Duration
+	HTTPClient   *http.Client // запросы к провайдерам; nil - http.DefaultClient
+}
+
+func (c OIDCConfig) withDefaults() OIDCConfig {
+	if c.StateTTL <= 0 {
+		c.StateTTL = 10 * time.Minute
+	}
+	if c.ReauthMaxAge <= 0 {
+		c.ReauthMaxAge = 5 * time.Minute
+	}
+	if c.HTTPClient == nil {
+		c.HTTPClient = http.DefaultClient
+	}
+
+	return c
+}
+
+// OIDCStart - куда отправить браузер; state фронт сверяет с тем, что вернется в redirect
+type OIDCStart struct {
+	AuthURL string
+	State   string
+}
+
+type oidcDeps struct {
+	identities repository.IdentityRepository
+	states     repository.OIDCStateRepository
+	cfg        OIDCConfig
+	providers  map[string]*oidcProvider
+	names      []string
+}
+
+// oidcProvider - discovery делаем при первом обращении, а не на старте:
+// недоступный провайдер не должен мешать запуску сервиса
+type oidcProvider struct {
+	cfg    OIDCProviderConfig
+	client *http.Client
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// SetOIDC - включает вход и привязку аккаунтов через внешних OIDC-провайдеров
+func (s *AuthService) SetOIDC(identities repository.IdentityRepository, states repository.OIDCStateRepository, cfg OIDCConfig) error {
+	cfg = cfg.withDefaults()
+	deps := &oidcDeps{
+		identities: identities,
+		states:     states,
+		cfg:        cfg,
+		providers:  make(map[string]*oidcProvider, len(cfg.Providers)),
+	}
+	for _, p := range cfg.Providers {
+		if p.Name == "" || p.Issuer == "" || p.ClientID == "" {
+			return fmt.Errorf("oidc: provider %q: name, issuer and clientId are required", p.Name)
+		}
+		if _, ok := deps.providers[p.Name]; ok {
+			return fmt.Errorf("oidc: duplicate provider %q", p.Name)
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+		}
+		deps.providers[p.Name] = &oidcProvider{cfg: p, client: cfg.HTTPClient}
+		deps.names = append(deps.names, p.Name)
+	}
+
+	s.oidc = deps
+	return nil
+}
+
+// OIDCProviders - имена настроенных провайдеров (для кнопок на странице входа)
+func (s *AuthService) OIDCProviders() []string {
+	if s.oidc == nil {
+		return nil
+	}
+	return s.oidc.names
+}
+
+// BeginOIDCLogin - ссылка на провайдера для входа (или регистрации)
+func (s *AuthService) BeginOIDCLogin(ctx context.Context, provider string) (*OIDCStart, error) {
+	return s.beginOIDC(ctx, provider, nil)
+}
+
+// BeginOIDCLink - ссылка на провайдера для привязки аккаунта к userID
+func (s *AuthService) BeginOIDCLink(ctx context.Context, userID domain.UserID, provider string) (*OIDCStart, error) {
+	return s.beginOIDC(ctx, provider, &userID)
+}
+
+// FinishOIDCLogin - возврат от провайдера: находит пользователя по привязке или создает нового.
+// Пользователь с таким email уже есть, но не привязан - ErrAccountLinkRequired: автоматически не связываем,
+// иначе любой, кто заведет у провайдера чужой email, получит чужой аккаунт
+func (s *AuthService) FinishOIDCLogin(ctx context.Context, provider, state, code string, meta *LoginMeta) (*LoginResult, error) {
+	p, st, err := s.takeOIDCState(ctx, provider, state)
+	if err != nil {
+		return nil, err
+	}
+	if st.UserID != nil {
+		return nil, errs.ErrInvalidOIDCState
+	}
+	claims, err := p.exchange(ctx, code, st)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	var u *domain.User
+	ident, err := s.oidc.identities.GetBySubject(ctx, provider, claims.Subject)
+	switch {
+	case err == nil:
+		if u, err = s.users.GetByID(ctx, ident.UserID); err != nil {
+			slog.Error("auth.oidc.login.getUserByID failed", slog.Any("err", err))
+			return nil, err
+		}
+		if err := s.oidc.identities.TouchLogin(ctx, ident.ID, now); err != nil {
+			slog.Error("auth.oidc.login.touchIdentity failed", slog.Any("err", err))
+		}
+	case errors.Is(err, repository.ErrNotFound):
+		if u, err = s.registerFromOIDC(ctx, provider, claims, now); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	// второй фактор спрашиваем так же, как после пароля
+	mfa, err := s.mfaRequired(ctx, u.ID)
+	if err != nil {
+		slog.Error("auth.oidc.login.mfaRequired failed", slog.Any("err", err))
+		return nil, err
+	}
+	if mfa {
+		return s.startMFA(ctx, u, now)
+	}
+
+	access, refresh, err := s.issueTokens(ctx, u.ID, meta, nil)
+	if err != nil {
+		slog.Error("auth.oidc.login.generateIssueToken failed", slog.Any("err", err))
+		if errors.Is(err, errs.ErrAccountDisabled) {
+			s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, "", err)
+		}
+		return nil, err
+	}
+	s.recordUser(ctx, meta, domain.AuthEventLogin, u.ID, map[string]string{"method": "oidc", "provider": provider})
+
+	return &LoginResult{
+		User:         u,
+		AccessToken:  access,
+		RefreshToken: refresh,
+	}, nil
+}
+
+// FinishOIDCLink - возврат от провайдера после BeginOIDCLink. Завершить привязку может только тот же
+// пользователь, что ее начал: иначе можно подсунуть жертве ссылку и привязать ее аккаунт провайдера к своему
+func (s *AuthService) FinishOIDCLink(ctx context.Context, userID domain.UserID, provider, state, code string) (*domain.Identity, error) {
+	p, st, err := s.takeOIDCState(ctx, provider, state)
+	if err != nil {
+		return nil, err
+	}
+	if st.UserID == nil || *st.UserID != userID {
+		return nil, errs.ErrInvalidOIDCState
+	}
+	claims, err := p.exchange(ctx, code, st)
+	if err != nil {
+		return nil, err
+	}
+
+	ident, err := s.oidc.identities.GetBySubject(ctx, provider, claims.Subject)
+	switch {
+	case err == nil:
+		if ident.UserID != userID {
+			return nil, errs.ErrIdentityLinked
+		}
+		return ident, nil
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	ident = newIdentity(userID, provider, claims, s.now())
+	if ident.ID, err = s.oidc.identities.Create(ctx, ident); err != nil {
+		slog.Error("auth.oidc.link.createIdentity failed", slog.Any("err", err))
+		return nil, err
+	}
+	slog.Info("auth.oidc.linked", "user_id", int64(userID), "provider", provider)
+	s.recordUser(ctx, nil, domain.AuthEventIdentityLinked, userID, map[string]string{"provider": provider})
+
+	return ident, nil
+}
+
+// ListIdentities - привязанные внешние аккаунты пользователя
+func (s *AuthService) ListIdentities(ctx context.Context, userID domain.UserID) ([]domain.Identity, error) {
+	if s.oidc == nil {
+		return nil, nil
+	}
+	return s.oidc.identities.ListByUser(ctx, userID)
+}
+
+// UnlinkIdentity - отвязывает провайдера. Последний способ входа (нет пароля, passkeys
+// и других привязок) не отвязываем - пользователь потеряет доступ к аккаунту
+func (s *AuthService) UnlinkIdentity(ctx context.Context, userID domain.UserID, provider string) error {
+	if s.oidc == nil {
+		return repository.ErrNotFound
+	}
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !security.HasUsablePassword(u.PasswordHash) {
+		idents, err := s.oidc.identities.ListByUser(ctx, userID)
+		if err != nil {
+			return err
+		}
+		others := 0
+		for _, i := range idents {
+			if i.Provider != provider {
+				others++
+			}
+		}
+		if others == 0 {
+			hasPasskey, err := s.hasPasskeys(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if !hasPasskey {
+				return errs.ErrLastLoginMethod
+			}
+		}
+	}
+
+	if err := s.oidc.identities.Delete(ctx, userID, provider); err != nil {
+		return err
+	}
+	slog.Info("auth.oidc.unlinked", "user_id", int64(userID), "provider", provider)
+	s.recordUser(ctx, nil, domain.AuthEventIdentityUnlinked, userID, map[string]string{"provider": provider})
+
+	return nil
+}
+
+func (s *AuthService) beginOIDC(ctx context.Context, provider string, userID *domain.UserID) (*OIDCStart, error) {
+	p, err := s.oidcProvider(provider)
+	if err != nil {
+		return nil, err
+	}
+	conf, _, err := p.oauth2(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	now := s.now()
+	st := &domain.OIDCState{
+		StateHash:    security.SHA256HexOfString(state),
+		Provider:     provider,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		UserID:       userID,
+		ExpiresAt:    now.Add(s.oidc.cfg.StateTTL),
+		CreatedAt:    now,
+	}
+	if err := s.oidc.states.Create(ctx, st); err != nil {
+		slog.Error("auth.oidc.createState failed", slog.Any("err", err))
+		return nil, err
+	}
+
+	return &OIDCStart{
+		AuthURL: conf.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)),
+		State:   state,
+	}, nil
+}
+
+// takeOIDCState - state одноразовый: забираем сразу, даже если обмен кода дальше не пройдет
+func (s *AuthService) takeOIDCState(ctx context.Context, provider, state string) (*oidcProvider, *domain.OIDCState, error) {
+	p, err := s.oidcProvider(provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	st, err := s.oidc.states.Take(ctx, security.SHA256HexOfString(state))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, errs.ErrInvalidOIDCState
+		}
+		return nil, nil, err
+	}
+	if st.Provider != provider || st.IsExpired(s.now()) {
+		return nil, nil, errs.ErrInvalidOIDCState
+	}
+
+	return p, st, nil
+}
+
+func (s *AuthService) oidcProvider(name string) (*oidcProvider, error) {
+	if s.oidc == nil {
+		return nil, errs.ErrUnknownProvider
+	}
+	p, ok := s.oidc.providers[name]
+	if !ok {
+		return nil, errs.ErrUnknownProvider
+	}
+
+	return p, nil
+}
+
+// registerFromOIDC - новый пользователь без пароля, сразу с привязкой
+func (s *AuthService) registerFromOIDC(ctx context.Context, provider string, claims *oidcClaims, now time.Time) (*domain.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, errs.ErrOIDCEmailNotVerified
+	}
+	exists, err := s.users.ExistsByEmail(ctx, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errs.ErrAccountLinkRequired
+	}
+
+	hash, err := security.UnusablePasswordHash()
+	if err != nil {
+		return nil, err
+	}
+	var opts []domain.UserOption
+	if claims.Name != "" {
+		opts = append(opts, domain.WithDisplayName(claims.Name))
+	}
+	if claims.Picture != "" {
+		opts = append(opts, domain.WithAvatarURL(claims.Picture))
+	}
+	u, err := domain.NewUser(claims.Email, hash, now, opts...)
+	if err != nil {
+		return nil, err
+	}
+	u.VeriyEmail(now)
+
+	ident := newIdentity(0, provider, claims, now)
+	ident.LastLoginAt = &now
+	if u.ID, err = s.createUser(ctx, u, ident); err != nil {
+		slog.Error("auth.oidc.register.createUser failed", slog.Any("err", err))
+		return nil, err
+	}
+	slog.Info("auth.oidc.registered", "user_id", int64(u.ID), "provider", provider)
+	s.recordUser(ctx, nil, domain.AuthEventRegister, u.ID, map[string]string{"provider": provider})
+
+	return u, nil
+}
+
+// recentOIDCLogin - входил ли пользователь через провайдера за последние ReauthMaxAge
+func (s *AuthService) recentOIDCLogin(ctx context.Context, userID domain.UserID, now time.Time) (bool, error) {
+	if s.oidc == nil {
+		return false, nil
+	}
+	identities, err := s.oidc.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	for _, i := range identities {
+		if i.LastLoginAt != nil && !i.LastLoginAt.Before(now.Add(-s.oidc.cfg.ReauthMaxAge)) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (s *AuthService) hasPasskeys(ctx context.Context, userID domain.UserID) (bool, error) {
+	if s.webauthn == nil {
+		return false, nil
+	}
+	passkeys, err := s.webauthn.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return len(passkeys) > 0, nil
+}
+
+func newIdentity(userID domain.UserID, provider string, claims *oidcClaims, now time.Time) *domain.Identity {
+	i := &domain.Identity{
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   claims.Subject,
+		CreatedAt: now,
+	}
+	if claims.Email != "" {
+		email := claims.Email
+		i.Email = &email
+	}
+
+	return i
+}
+
+// oidcClaims - то, что берем из ID token
+type oidcClaims struct {
+	Subject       string `json:"sub"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Picture       string `json:"picture"`
+}
+
+func (p *oidcProvider) oauth2(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider == nil {
+		prov, err := oidc.NewProvider(oidc.ClientContext(ctx, p.client), p.cfg.Issuer)
+		if err != nil {
+			slog.Error("auth.oidc.discovery failed", "provider", p.cfg.Name, slog.Any("err", err))
+			return nil, nil, fmt.Errorf("oidc discovery %s: %w", p.cfg.Name, err)
+		}
+		p.provider = prov
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		Endpoint:     p.provider.Endpoint(),
+		RedirectURL:  p.cfg.RedirectURL,
+		Scopes:       p.cfg.Scopes,
+	}, p.provider, nil
+}
+
+// exchange - code -> токены (с PKCE verifier), проверка подписи, iss, aud, exp и nonce ID token
+func (p *oidcProvider) exchange(ctx context.Context, code string, st *domain.OIDCState) (*oidcClaims, error) {
+	conf, prov, err := p.oauth2(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = oidc.ClientContext(ctx, p.client)
+
+	tok, err := conf.Exchange(ctx, code, oauth2.VerifierOption(st.CodeVerifier))
+	if err != nil {
+		slog.Info("auth.oidc.exchange failed", "provider", p.cfg.Name, slog.Any("err", err))
+		return nil, fmt.Errorf("%w: %v", errs.ErrOIDCFailed, err)
+	}
+	raw, ok := tok.Extra("id_token").(string)
+	if !ok || raw == "" {
+		return nil, fmt.Errorf("%w: no id_token in token response", errs.ErrOIDCFailed)
+	}
+	idToken, err := prov.Verifier(&oidc.Config{ClientID: p.cfg.ClientID}).Verify(ctx, raw)
+	if err != nil {
+		slog.Info("auth.oidc.verify failed", "provider", p.cfg.Name, slog.Any("err", err))
+		return nil, fmt.Errorf("%w: %v", errs.ErrOIDCFailed, err)
+	}
+	if !security.ConstantTimeEqual(idToken.Nonce, st.Nonce) {
+		return nil, fmt.Errorf("%w: nonce mismatch", errs.ErrOIDCFailed)
+	}
+
+	var claims oidcClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrOIDCFailed, err)
+	}
+	claims.Subject = idToken.Subject
+	claims.Email = strings.TrimSpace(claims.Email)
+
+	return &claims, nil
+}
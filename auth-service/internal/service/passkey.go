@@ -0,0 +1,525 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const maxPasskeyNameLen = 64
+
+// WebAuthnConfig - вход по passkey
+type WebAuthnConfig struct {
+	RPID          string        // домен сайта без схемы и порта, напр. cwrk.example.com
+	RPDisplayName string        // по умолчанию cwrk-planet
+	RPOrigins     []string      // откуда разрешены церемонии, напр. https://cwrk.example.com
+	CeremonyTTL   time.Duration // сколько ждем ответа аутентификатора, по умолчанию 5m
+	MaxPasskeys   int           // на пользователя, по умолчанию 10
+}
+
+func (c WebAuthnConfig) withDefaults() WebAuthnConfig {
+	if c.RPDisplayName == "" {
+		c.RPDisplayName = "cwrk-planet"
+	}
+	if c.CeremonyTTL <= 0 {
+		c.CeremonyTTL = 5 * time.Minute
+	}
+	if c.MaxPasskeys <= 0 {
+		c.MaxPasskeys = 10
+	}
+
+	return c
+}
+
+type webauthnDeps struct {
+	wa         *webauthn.WebAuthn
+	passkeys   repository.PasskeyRepository
+	ceremonies repository.WebAuthnCeremonyRepository
+	cfg        WebAuthnConfig
+}
+
+// SetWebAuthn - включает регистрацию и вход по passkey
+func (s *AuthService) SetWebAuthn(passkeys repository.PasskeyRepository, ceremonies repository.WebAuthnCeremonyRepository, cfg WebAuthnConfig) error {
+	cfg = cfg.withDefaults()
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.CeremonyTTL},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.CeremonyTTL},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("webauthn: %w", err)
+	}
+
+	s.webauthn = &webauthnDeps{wa: wa, passkeys: passkeys, ceremonies: ceremonies, cfg: cfg}
+	return nil
+}
+
+// BeginPasskeyRegistration - options для navigator.credentials.create() и id церемонии
+func (s *AuthService) BeginPasskeyRegistration(ctx context.Context, userID domain.UserID) (ceremonyID string, options []byte, err error) {
+	if s.webauthn == nil {
+		return "", nil, errs.ErrWebAuthnUnavailable
+	}
+	user, err := s.webauthnUser(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(user.passkeys) >= s.webauthn.cfg.MaxPasskeys {
+		return "", nil, errs.ErrPasskeyLimit
+	}
+
+	creds := webauthn.Credentials(user.WebAuthnCredentials())
+	creation, session, err := s.webauthn.wa.BeginRegistration(user,
+		webauthn.WithExclusions(creds.CredentialDescriptors()),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+	)
+	if err != nil {
+		slog.Error("auth.passkey.beginRegistration failed", slog.Any("err", err))
+		return "", nil, err
+	}
+
+	ceremonyID, err = s.saveCeremony(ctx, domain.WebAuthnRegistration, &userID, session)
+	if err != nil {
+		return "", nil, err
+	}
+	options, err = json.Marshal(creation)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ceremonyID, options, nil
+}
+
+// FinishPasskeyRegistration проверяет ответ аутентификатора и сохраняет passkey
+func (s *AuthService) FinishPasskeyRegistration(ctx context.Context, userID domain.UserID, ceremonyID, name string, credential []byte) (*domain.Passkey, error) {
+	if s.webauthn == nil {
+		return nil, errs.ErrWebAuthnUnavailable
+	}
+	name, err := passkeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	session, err := s.takeCeremony(ctx, ceremonyID, domain.WebAuthnRegistration, &userID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.webauthnUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBytes(credential)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+	cred, err := s.webauthn.wa.CreateCredential(user, *session, parsed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+
+	p := passkeyFromCredential(cred)
+	p.UserID = userID
+	p.Name = name
+	p.CreatedAt = s.now()
+	if p.ID, err = s.webauthn.passkeys.Create(ctx, p); err != nil {
+		slog.Error("auth.passkey.create failed", slog.Any("err", err))
+		return nil, err
+	}
+	slog.Info("auth.passkey.registered", "user_id", int64(userID), "passkey_id", int64(p.ID))
+	s.recordUser(ctx, nil, domain.AuthEventPasskeyAdded, userID, map[string]string{"passkey_id": strconv.FormatInt(int64(p.ID), 10), "name": p.Name})
+
+	return p, nil
+}
+
+// BeginPasskeyLogin - options для navigator.credentials.get(); пользователя определит сам passkey
+func (s *AuthService) BeginPasskeyLogin(ctx context.Context) (ceremonyID string, options []byte, err error) {
+	if s.webauthn == nil {
+		return "", nil, errs.ErrWebAuthnUnavailable
+	}
+	assertion, session, err := s.webauthn.wa.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		slog.Error("auth.passkey.beginLogin failed", slog.Any("err", err))
+		return "", nil, err
+	}
+
+	ceremonyID, err = s.saveCeremony(ctx, domain.WebAuthnLogin, nil, session)
+	if err != nil {
+		return "", nil, err
+	}
+	options, err = json.Marshal(assertion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ceremonyID, options, nil
+}
+
+// FinishPasskeyLogin проверяет подпись и выпускает ту же пару токенов, что и Login.
+// Второй фактор не спрашиваем: passkey с проверкой пользователя (UV) - уже два фактора
+func (s *AuthService) FinishPasskeyLogin(ctx context.Context, ceremonyID string, credential []byte, meta *LoginMeta) (*LoginResult, error) {
+	if s.webauthn == nil {
+		return nil, errs.ErrWebAuthnUnavailable
+	}
+	session, err := s.takeCeremony(ctx, ceremonyID, domain.WebAuthnLogin, nil)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credential)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+
+	var found *webauthnUser
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		p, err := s.webauthn.passkeys.GetByCredentialID(ctx, rawID)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(userHandle, webauthnUserHandle(p.UserID)) {
+			return nil, errors.New("user handle does not match credential owner")
+		}
+		u, err := s.webauthnUser(ctx, p.UserID)
+		if err != nil {
+			return nil, err
+		}
+		found = u
+		return u, nil
+	}
+
+	cred, err := s.webauthn.wa.ValidateDiscoverableLogin(handler, *session, parsed)
+	if err != nil {
+		slog.Info("auth.passkey.login failed", slog.Any("err", err))
+		var u *domain.User
+		if found != nil {
+			u = found.user
+		}
+		s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, u, "", errs.ErrWebAuthnFailed)
+		return nil, fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+	if err := s.usePasskey(ctx, found, cred, parsed, meta, domain.AuthEventLoginFailed); err != nil {
+		return nil, err
+	}
+
+	access, refresh, err := s.issueTokens(ctx, found.user.ID, meta, nil)
+	if err != nil {
+		slog.Error("auth.passkey.generateIssueToken failed", slog.Any("err", err))
+		if errors.Is(err, errs.ErrAccountDisabled) {
+			s.recordFailure(ctx, meta, domain.AuthEventLoginFailed, found.user, "", err)
+		}
+		return nil, err
+	}
+	s.recordUser(ctx, meta, domain.AuthEventLogin, found.user.ID, map[string]string{"method": "passkey"})
+
+	return &LoginResult{
+		User:         found.user,
+		AccessToken:  access,
+		RefreshToken: refresh,
+	}, nil
+}
+
+// BeginPasskeyReauth - options для повторной проверки перед удалением аккаунта:
+// годится только passkey этого пользователя, проверка пользователя (UV) обязательна
+func (s *AuthService) BeginPasskeyReauth(ctx context.Context, userID domain.UserID) (ceremonyID string, options []byte, err error) {
+	if s.webauthn == nil {
+		return "", nil, errs.ErrWebAuthnUnavailable
+	}
+	u, err := s.webauthnUser(ctx, userID)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(u.passkeys) == 0 {
+		return "", nil, errs.ErrReauthUnavailable
+	}
+	assertion, session, err := s.webauthn.wa.BeginLogin(u,
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		slog.Error("auth.passkey.beginReauth failed", slog.Any("err", err))
+		return "", nil, err
+	}
+
+	ceremonyID, err = s.saveCeremony(ctx, domain.WebAuthnReauth, &userID, session)
+	if err != nil {
+		return "", nil, err
+	}
+	options, err = json.Marshal(assertion)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return ceremonyID, options, nil
+}
+
+// checkPasskeyReauth - ответ аутентификатора на церемонию BeginPasskeyReauth того же пользователя
+func (s *AuthService) checkPasskeyReauth(ctx context.Context, userID domain.UserID, ceremonyID string, credential []byte, meta *LoginMeta, failed domain.AuthEventType) error {
+	if s.webauthn == nil {
+		return errs.ErrWebAuthnUnavailable
+	}
+	session, err := s.takeCeremony(ctx, ceremonyID, domain.WebAuthnReauth, &userID)
+	if err != nil {
+		return err
+	}
+	parsed, err := protocol.ParseCredentialRequestResponseBytes(credential)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+	u, err := s.webauthnUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	cred, err := s.webauthn.wa.ValidateLogin(u, *session, parsed)
+	if err != nil {
+		slog.Info("auth.passkey.reauth failed", slog.Any("err", err))
+		s.recordFailure(ctx, meta, failed, u.user, "", errs.ErrWebAuthnFailed)
+		return fmt.Errorf("%w: %v", errs.ErrWebAuthnFailed, err)
+	}
+
+	return s.usePasskey(ctx, u, cred, parsed, meta, failed)
+}
+
+// usePasskey - подпись проверена: отказ при подозрении на копию ключа, иначе запоминаем счетчик и время
+func (s *AuthService) usePasskey(ctx context.Context, u *webauthnUser, cred *webauthn.Credential, parsed *protocol.ParsedCredentialAssertionData, meta *LoginMeta, failed domain.AuthEventType) error {
+	p := u.passkey(cred.ID)
+	if p == nil {
+		return errs.ErrWebAuthnFailed
+	}
+	// счетчик подписей не вырос - у ключа, возможно, есть копия
+	if cred.Authenticator.CloneWarning {
+		slog.Warn("auth.passkey.cloneWarning",
+			"user_id", int64(p.UserID),
+			"passkey_id", int64(p.ID),
+			"stored_sign_count", p.SignCount,
+			"sign_count", parsed.Response.AuthenticatorData.Counter,
+		)
+		s.recordFailure(ctx, meta, failed, u.user, "", errors.New("passkey clone warning"))
+		return errs.ErrWebAuthnFailed
+	}
+
+	if err := s.webauthn.passkeys.UpdateUsage(ctx, p.ID, cred.Authenticator.SignCount, cred.Flags.BackupState, s.now()); err != nil {
+		slog.Error("auth.passkey.updateUsage failed", slog.Any("err", err))
+		return err
+	}
+
+	return nil
+}
+
+// ListPasskeys - passkeys пользователя
+func (s *AuthService) ListPasskeys(ctx context.Context, userID domain.UserID) ([]domain.Passkey, error) {
+	if s.webauthn == nil {
+		return nil, errs.ErrWebAuthnUnavailable
+	}
+	return s.webauthn.passkeys.ListByUser(ctx, userID)
+}
+
+func (s *AuthService) RenamePasskey(ctx context.Context, userID domain.UserID, id domain.PasskeyID, name string) (*domain.Passkey, error) {
+	if s.webauthn == nil {
+		return nil, errs.ErrWebAuthnUnavailable
+	}
+	name, err := passkeyName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webauthn.passkeys.Rename(ctx, userID, id, name); err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.webauthn.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range passkeys {
+		if passkeys[i].ID == id {
+			return &passkeys[i], nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+// RevokePasskey - удаляет passkey, войти им больше нельзя
+func (s *AuthService) RevokePasskey(ctx context.Context, userID domain.UserID, id domain.PasskeyID) error {
+	if s.webauthn == nil {
+		return errs.ErrWebAuthnUnavailable
+	}
+	if err := s.webauthn.passkeys.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	slog.Info("auth.passkey.revoked", "user_id", int64(userID), "passkey_id", int64(id))
+	s.recordUser(ctx, nil, domain.AuthEventPasskeyRevoked, userID, map[string]string{"passkey_id": strconv.FormatInt(int64(id), 10)})
+
+	return nil
+}
+
+func (s *AuthService) saveCeremony(ctx context.Context, kind domain.WebAuthnCeremonyKind, userID *domain.UserID, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	id, err := security.RandomStringURLSafe(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	c := &domain.WebAuthnCeremony{
+		IDHash:    security.SHA256HexOfString(id),
+		Kind:      kind,
+		UserID:    userID,
+		Session:   data,
+		ExpiresAt: now.Add(s.webauthn.cfg.CeremonyTTL),
+		CreatedAt: now,
+	}
+	if err := s.webauthn.ceremonies.Create(ctx, c); err != nil {
+		slog.Error("auth.passkey.createCeremony failed", slog.Any("err", err))
+		return "", err
+	}
+
+	return id, nil
+}
+
+// takeCeremony - церемония одноразовая: забираем ее сразу, даже если дальше проверка не пройдет
+func (s *AuthService) takeCeremony(ctx context.Context, id string, kind domain.WebAuthnCeremonyKind, userID *domain.UserID) (*webauthn.SessionData, error) {
+	c, err := s.webauthn.ceremonies.Take(ctx, security.SHA256HexOfString(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, errs.ErrInvalidCeremony
+		}
+		return nil, err
+	}
+	if c.Kind != kind || c.IsExpired(s.now()) {
+		return nil, errs.ErrInvalidCeremony
+	}
+	if userID != nil && (c.UserID == nil || *c.UserID != *userID) {
+		return nil, errs.ErrInvalidCeremony
+	}
+
+	var session webauthn.SessionData
+	if err := json.Unmarshal(c.Session, &session); err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (s *AuthService) webauthnUser(ctx context.Context, userID domain.UserID) (*webauthnUser, error) {
+	u, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	passkeys, err := s.webauthn.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webauthnUser{user: u, passkeys: passkeys}, nil
+}
+
+// webauthnUser - пользователь глазами go-webauthn
+type webauthnUser struct {
+	user     *domain.User
+	passkeys []domain.Passkey
+}
+
+func (u *webauthnUser) WebAuthnID() []byte { return webauthnUserHandle(u.user.ID) }
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if u.user.DisplayName != nil && *u.user.DisplayName != "" {
+		return *u.user.DisplayName
+	}
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	out := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, p := range u.passkeys {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(p.Transports))
+		for _, t := range p.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(t))
+		}
+		out = append(out, webauthn.Credential{
+			ID:              p.CredentialID,
+			PublicKey:       p.PublicKey,
+			AttestationType: p.AttestationType,
+			Transport:       transports,
+			Flags: webauthn.CredentialFlags{
+				BackupEligible: p.BackupEligible,
+				BackupState:    p.BackupState,
+			},
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    p.AAGUID,
+				SignCount: p.SignCount,
+			},
+		})
+	}
+
+	return out
+}
+
+func (u *webauthnUser) passkey(credentialID []byte) *domain.Passkey {
+	for i := range u.passkeys {
+		if bytes.Equal(u.passkeys[i].CredentialID, credentialID) {
+			return &u.passkeys[i]
+		}
+	}
+
+	return nil
+}
+
+// webauthnUserHandle - user.id для аутентификатора; это не PII, а тот же id, что и в sub токена
+func webauthnUserHandle(id domain.UserID) []byte {
+	return []byte(strconv.FormatInt(int64(id), 10))
+}
+
+func passkeyFromCredential(c *webauthn.Credential) *domain.Passkey {
+	transports := make([]string, 0, len(c.Transport))
+	for _, t := range c.Transport {
+		transports = append(transports, string(t))
+	}
+
+	return &domain.Passkey{
+		CredentialID:    c.ID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transports:      transports,
+		AAGUID:          c.Authenticator.AAGUID,
+		SignCount:       c.Authenticator.SignCount,
+		BackupEligible:  c.Flags.BackupEligible,
+		BackupState:     c.Flags.BackupState,
+	}
+}
+
+func passkeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "Passkey", nil
+	}
+	if utf8.RuneCountInString(name) > maxPasskeyNameLen {
+		return "", errs.ErrInvalidPasskeyName
+	}
+
+	return name, nil
+}
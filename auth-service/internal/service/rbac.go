@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+)
+
+// RBACConfig - роли платформы
+type RBACConfig struct {
+	DefaultRole string // выдается при регистрации, по умолчанию user
+}
+
+func (c RBACConfig) withDefaults() RBACConfig {
+	if c.DefaultRole == "" {
+		c.DefaultRole = "user"
+	}
+
+	return c
+}
+
+type rbacDeps struct {
+	roles repository.RoleRepository
+	cfg   RBACConfig
+}
+
+// SetRBAC - роли и права в access-токенах; без него токены выходят без roles и scope
+func (s *AuthService) SetRBAC(roles repository.RoleRepository, cfg RBACConfig) {
+	s.rbac = &rbacDeps{roles: roles, cfg: cfg.withDefaults()}
+}
+
+// UserGrants - роли пользователя и объединение их прав
+func (s *AuthService) UserGrants(ctx context.Context, userID domain.UserID) (roles, scopes []string, err error) {
+	if s.rbac == nil {
+		return nil, nil, nil
+	}
+	list, err := s.rbac.roles.ListByUser(ctx, userID)
+	if err != nil {
+		slog.Error("auth.rbac.listByUser failed", slog.Any("err", err))
+		return nil, nil, err
+	}
+	roles, scopes = domain.Grants(list)
+
+	return roles, scopes, nil
+}
+
+// assignDefaultRole - роль по умолчанию новому пользователю
+func (s *AuthService) assignDefaultRole(ctx context.Context, repo repository.RoleRepository, userID domain.UserID, now time.Time) error {
+	if s.rbac == nil {
+		return nil
+	}
+
+	return repo.Assign(ctx, userID, s.rbac.cfg.DefaultRole, now)
+}
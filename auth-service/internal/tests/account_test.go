@@ -0,0 +1,226 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+func TestAccount_DeletionScheduleAndCancel(t *testing.T) {
+	env, events := newAuditEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, err := env.svc.RequestAccountDeletion(ctx, u.ID, service.Reauth{Password: "wrong-password"}, nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	at, err := env.svc.RequestAccountDeletion(ctx, u.ID, service.Reauth{Password: "password123"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(at); d < 13*24*time.Hour || d > 14*24*time.Hour {
+		t.Fatalf("unexpected grace period, deletion at %v", at)
+	}
+	// повторный запрос срок не сдвигает
+	again, err := env.svc.RequestAccountDeletion(ctx, u.ID, service.Reauth{Password: "password123"}, nil)
+	if err != nil || !again.Equal(at) {
+		t.Fatalf("repeat request: %v %v", again, err)
+	}
+	// во время отсрочки вход работает
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); err != nil {
+		t.Fatalf("login during grace period: %v", err)
+	}
+
+	if err := env.svc.CancelAccountDeletion(ctx, u.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := env.users.GetByID(ctx, u.ID); got.DeletionScheduledAt != nil {
+		t.Fatalf("deletion is still scheduled at %v", got.DeletionScheduledAt)
+	}
+	if err := env.svc.CancelAccountDeletion(ctx, u.ID, nil); !errors.Is(err, errs.ErrDeletionNotScheduled) {
+		t.Fatalf("want ErrDeletionNotScheduled, got %v", err)
+	}
+
+	if n := len(events.byType(domain.AuthEventAccountDeletionFailed)); n != 1 {
+		t.Fatalf("want 1 failed confirmation, got %d", n)
+	}
+	if n := len(events.byType(domain.AuthEventAccountDeletionRequested)); n != 1 {
+		t.Fatalf("want 1 deletion request, got %d", n)
+	}
+	if n := len(events.byType(domain.AuthEventAccountDeletionCancelled)); n != 1 {
+		t.Fatalf("want 1 cancellation, got %d", n)
+	}
+}
+
+func TestAccount_DeletedAfterGracePeriod(t *testing.T) {
+	env, events := newAuditEnv(t)
+	env.svc.SetAccountDeletionGrace(time.Nanosecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, err := env.svc.RequestAccountDeletion(ctx, u.ID, service.Reauth{Password: "password123"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	go env.svc.RunCleanup(ctx, time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := env.users.GetByID(ctx, u.ID); errors.Is(err, repository.ErrNotFound) {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("account was not deleted")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	if _, err := env.svc.Login(context.Background(), "ann@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	if got := events.byType(domain.AuthEventAccountDeleted); len(got) != 1 || *got[0].UserID != u.ID {
+		t.Fatalf("unexpected deletion events %+v", got)
+	}
+}
+
+func TestAccount_DeletionWithoutPassword(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	hash, err := security.UnusablePasswordHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := domain.NewUser("oidc@cwrk.test", hash, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := env.users.Create(ctx, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ни пароля, ни TOTP - подтвердить удаление нечем
+	if _, err := env.svc.RequestAccountDeletion(ctx, id, service.Reauth{}, nil); !errors.Is(err, errs.ErrReauthUnavailable) {
+		t.Fatalf("want ErrReauthUnavailable, got %v", err)
+	}
+}
+
+func TestAccount_DeletionWithPasskey(t *testing.T) {
+	env, _ := newWebAuthnEnv(t)
+	ctx := context.Background()
+
+	// только passkey, без пароля и TOTP
+	hash, err := security.UnusablePasswordHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := domain.NewUser("passkey@cwrk.test", hash, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := env.users.Create(ctx, u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newSoftAuthenticator(t)
+	ceremony, options, err := env.svc.BeginPasskeyRegistration(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.FinishPasskeyRegistration(ctx, id, ceremony, "Phone", a.create(t, options)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.svc.RequestAccountDeletion(ctx, id, service.Reauth{}, nil); !errors.Is(err, errs.ErrReauthUnavailable) {
+		t.Fatalf("without passkey: want ErrReauthUnavailable, got %v", err)
+	}
+
+	reauth := func() service.Reauth {
+		t.Helper()
+		ceremony, options, err := env.svc.BeginPasskeyReauth(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a.signCount++
+		return service.Reauth{PasskeyCeremonyID: ceremony, PasskeyCredential: a.get(t, options)}
+	}
+
+	// чужой ключ не подходит
+	stranger := newSoftAuthenticator(t)
+	in := reauth()
+	ceremony, options, err = env.svc.BeginPasskeyReauth(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.RequestAccountDeletion(ctx, id, service.Reauth{PasskeyCeremonyID: ceremony, PasskeyCredential: stranger.get(t, options)}, nil); !errors.Is(err, errs.ErrWebAuthnFailed) {
+		t.Fatalf("foreign passkey: want ErrWebAuthnFailed, got %v", err)
+	}
+	// церемония привязана к пользователю
+	bob := env.register(t, "bob@cwrk.test")
+	if _, err := env.svc.RequestAccountDeletion(ctx, bob.ID, in, nil); !errors.Is(err, errs.ErrInvalidCeremony) {
+		t.Fatalf("other user's ceremony: want ErrInvalidCeremony, got %v", err)
+	}
+	if _, _, err := env.svc.BeginPasskeyReauth(ctx, bob.ID); !errors.Is(err, errs.ErrReauthUnavailable) {
+		t.Fatalf("user without passkeys: want ErrReauthUnavailable, got %v", err)
+	}
+
+	in = reauth()
+	if _, err := env.svc.RequestAccountDeletion(ctx, id, in, nil); err != nil {
+		t.Fatal(err)
+	}
+	// церемония одноразовая
+	if _, err := env.svc.RequestAccountDeletion(ctx, id, in, nil); !errors.Is(err, errs.ErrInvalidCeremony) {
+		t.Fatalf("reused ceremony: want ErrInvalidCeremony, got %v", err)
+	}
+}
+
+func TestAccount_DeletionAfterRecentOIDCLogin(t *testing.T) {
+	env, m, idents := newOIDCEnv(t)
+	ctx := context.Background()
+
+	res, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// вход через провайдера старше ReauthMaxAge (5m по умолчанию) не годится
+	old := time.Now().Add(-10 * time.Minute)
+	idents.items[0].LastLoginAt = &old
+	if _, err := env.svc.RequestAccountDeletion(ctx, res.User.ID, service.Reauth{}, nil); !errors.Is(err, errs.ErrReauthUnavailable) {
+		t.Fatalf("stale provider login: want ErrReauthUnavailable, got %v", err)
+	}
+
+	if _, err := oidcLogin(t, env, m, ann); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.RequestAccountDeletion(ctx, res.User.ID, service.Reauth{}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAccount_PersonalData(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test") // Register уже создал одну сессию
+
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := env.svc.PersonalData(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.User.Email != "ann@cwrk.test" || len(data.Sessions) != 2 || data.Sessions[0].ID < data.Sessions[1].ID {
+		t.Fatalf("unexpected personal data %+v", data)
+	}
+}
@@ -0,0 +1,265 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+)
+
+// newAdminEnv - окружение с включенной админкой и одним админом
+func newAdminEnv(t *testing.T) (*testEnv, *memAdminAudit, *domain.User) {
+	t.Helper()
+	env := newTestEnv(t)
+	audit := &memAdminAudit{}
+	env.svc.SetAdmin(audit)
+
+	admin := env.register(t, "root@cwrk.test")
+	if err := env.roles.Assign(context.Background(), admin.ID, "admin", admin.CreatedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	return env, audit, admin
+}
+
+func TestAdmin_RequiresPermission(t *testing.T) {
+	env, _, _ := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, _, err := env.svc.AdminSearchUsers(ctx, u.ID, "", 0, 0); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("want ErrForbidden, got %v", err)
+	}
+	if _, err := env.svc.AdminDisableUser(ctx, u.ID, u.ID+100, ""); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("want ErrForbidden, got %v", err)
+	}
+}
+
+func TestAdmin_SearchPagination(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	ctx := context.Background()
+	for _, email := range []string{"ann@cwrk.test", "bob@cwrk.test", "anna@other.test"} {
+		env.register(t, email)
+	}
+
+	page, next, err := env.svc.AdminSearchUsers(ctx, admin.ID, "ANN", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].User.Email != "ann@cwrk.test" || !slices.Contains(page[0].Roles, "user") || next == 0 {
+		t.Fatalf("first page: %+v next=%d", page, next)
+	}
+	page, next, err = env.svc.AdminSearchUsers(ctx, admin.ID, "ann", next, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].User.Email != "anna@other.test" || next != 0 {
+		t.Fatalf("second page: %+v next=%d", page, next)
+	}
+}
+
+func TestAdmin_DisableBlocksLoginAndRefresh(t *testing.T) {
+	env, audit, admin := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := env.svc.AdminDisableUser(ctx, admin.ID, u.ID, "spam")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.User.IsDisabled() {
+		t.Fatal("user is not disabled")
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrAccountDisabled) {
+		t.Fatalf("login: want ErrAccountDisabled, got %v", err)
+	}
+	// сессии завершены при блокировке
+	if _, err := env.svc.Refresh(ctx, login.RefreshToken, nil); err == nil {
+		t.Fatal("refresh after disable must fail")
+	}
+	// неверный пароль не должен выдавать, что аккаунт заблокирован
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "wrong-password", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("wrong password: want ErrInvalidCredentials, got %v", err)
+	}
+
+	if _, err := env.svc.AdminEnableUser(ctx, admin.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); err != nil {
+		t.Fatalf("login after enable: %v", err)
+	}
+
+	if len(audit.items) != 2 || audit.items[0].Action != domain.AdminActionDisable || audit.items[0].Details["reason"] != "spam" ||
+		audit.items[1].Action != domain.AdminActionEnable || audit.items[1].ActorID != admin.ID {
+		t.Fatalf("unexpected audit log %+v", audit.items)
+	}
+}
+
+func TestAdmin_DisableBlocksRefreshOfLiveSession(t *testing.T) {
+	env, _, _ := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// заблокировали в обход сервиса: сессия осталась, но refresh все равно отказывает
+	now := u.CreatedAt
+	if err := env.users.SetDisabled(ctx, u.ID, &now, now); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.Refresh(ctx, login.RefreshToken, nil); !errors.Is(err, errs.ErrAccountDisabled) {
+		t.Fatalf("want ErrAccountDisabled, got %v", err)
+	}
+}
+
+func TestAdmin_ForcePasswordReset(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, err := env.svc.AdminForcePasswordReset(ctx, admin.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrPasswordResetRequired) {
+		t.Fatalf("want ErrPasswordResetRequired, got %v", err)
+	}
+
+	if err := env.svc.ChangePassword(ctx, "ann@cwrk.test", "wrong-password", "new-password1", "", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	if err := env.svc.ChangePassword(ctx, "ann@cwrk.test", "password123", "new-password1", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("old password: want ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "new-password1", nil); err != nil {
+		t.Fatalf("new password: %v", err)
+	}
+}
+
+func TestAdmin_ForceLogout(t *testing.T) {
+	env, audit, admin := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test") // Register уже создал одну сессию
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := env.svc.AdminForceLogout(ctx, admin.ID, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("want 2 revoked sessions, got %d", n)
+	}
+	if _, err := env.svc.Refresh(ctx, login.RefreshToken, nil); err == nil {
+		t.Fatal("refresh after force logout must fail")
+	}
+	if last := audit.items[len(audit.items)-1]; last.Action != domain.AdminActionForceLogout {
+		t.Fatalf("unexpected audit entry %+v", last)
+	}
+}
+
+func TestAdmin_SetRoles(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	got, err := env.svc.AdminSetRoles(ctx, admin.ID, u.ID, []string{"admin", " user", "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.Roles, []string{"admin", "user"}) {
+		t.Fatalf("unexpected roles %v", got.Roles)
+	}
+	if _, err := env.svc.AdminSetRoles(ctx, admin.ID, u.ID, []string{"superuser"}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("unknown role: want ErrNotFound, got %v", err)
+	}
+
+	// новый админ может снять права со старого, а со своей учетки - нет
+	if _, err := env.svc.AdminSetRoles(ctx, u.ID, u.ID, []string{"user"}); !errors.Is(err, errs.ErrSelfAdminAction) {
+		t.Fatalf("want ErrSelfAdminAction, got %v", err)
+	}
+	if _, err := env.svc.AdminSetRoles(ctx, u.ID, admin.ID, []string{"user"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := env.svc.AdminSearchUsers(ctx, admin.ID, "", 0, 0); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("demoted admin: want ErrForbidden, got %v", err)
+	}
+}
+
+func TestAdmin_DeleteUser(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if err := env.svc.AdminDeleteUser(ctx, admin.ID, admin.ID); !errors.Is(err, errs.ErrSelfAdminAction) {
+		t.Fatalf("want ErrSelfAdminAction, got %v", err)
+	}
+	if err := env.svc.AdminDeleteUser(ctx, admin.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.AdminGetUser(ctx, admin.ID, u.ID); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+
+	target := u.ID
+	entries, next, err := env.svc.AdminAuditLog(ctx, admin.ID, domain.AdminAuditFilter{TargetUserID: &target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Action != domain.AdminActionDelete || entries[0].Details["email"] != "ann@cwrk.test" || next != 0 {
+		t.Fatalf("unexpected audit log %+v next=%d", entries, next)
+	}
+}
+
+func TestAdmin_AuditJournalsInOneTx(t *testing.T) {
+	env, audit, admin := newAdminEnv(t)
+	events := &memAuthEvents{}
+	env.svc.SetAuditLog(events)
+	target := env.register(t, "user@cwrk.test")
+	tx := &memTxRunner{env: env, audit: audit, events: events}
+	env.svc.SetTxRunner(tx)
+	ctx := context.Background()
+
+	if _, err := env.svc.AdminDisableUser(ctx, admin.ID, target.ID, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	if tx.commits != 1 || len(audit.items) != 1 {
+		t.Fatalf("commits = %d, admin audit = %+v", tx.commits, audit.items)
+	}
+	ev := events.byType(domain.AdminAuthEvent(domain.AdminActionDisable))
+	if len(ev) != 1 || *ev[0].ActorID != admin.ID || *ev[0].UserID != target.ID || ev[0].Details["reason"] != "spam" {
+		t.Fatalf("security events = %+v", ev)
+	}
+
+	// журнал безопасности не записался - не остается и записи в журнале админов
+	tx.failEvents = true
+	if _, err := env.svc.AdminEnableUser(ctx, admin.ID, target.ID); !errors.Is(err, errTxEventsDown) {
+		t.Fatalf("err = %v", err)
+	}
+	if tx.commits != 1 || len(audit.items) != 1 || len(events.byType(domain.AdminAuthEvent(domain.AdminActionEnable))) != 0 {
+		t.Fatalf("rolled back action left records: audit %+v", audit.items)
+	}
+
+	tx.failEvents = false
+	if err := env.svc.AdminDeleteUser(ctx, admin.ID, target.ID); err != nil {
+		t.Fatal(err)
+	}
+	if tx.commits != 2 || len(events.byType(domain.AdminAuthEvent(domain.AdminActionDelete))) != 1 {
+		t.Fatalf("delete: commits = %d", tx.commits)
+	}
+}
@@ -0,0 +1,227 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+func newAuditEnv(t *testing.T) (*testEnv, *memAuthEvents) {
+	t.Helper()
+	env := newTestEnv(t)
+	events := &memAuthEvents{}
+	env.svc.SetAuditLog(events)
+
+	return env, events
+}
+
+func TestAudit_LoginSuccessAndFailure(t *testing.T) {
+	env, events := newAuditEnv(t)
+	u := env.register(t, "ann@cwrk.test")
+
+	ip := netip.MustParseAddr("203.0.113.7")
+	ua, rid := "curl/8", "req-1"
+	ctx := service.WithLoginMeta(context.Background(), &service.LoginMeta{IP: &ip, UserAgent: &ua, RequestID: &rid})
+
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "wrong-password", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := env.svc.Login(ctx, "nobody@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := events.byType(domain.AuthEventRegister); len(got) != 1 || *got[0].UserID != u.ID {
+		t.Fatalf("unexpected register events %+v", got)
+	}
+
+	failed := events.byType(domain.AuthEventLoginFailed)
+	if len(failed) != 2 {
+		t.Fatalf("want 2 failed logins, got %+v", failed)
+	}
+	if failed[0].UserID == nil || *failed[0].UserID != u.ID || failed[0].IP == nil || *failed[0].IP != ip ||
+		*failed[0].UserAgent != ua || *failed[0].RequestID != rid {
+		t.Fatalf("unexpected failed login %+v", failed[0])
+	}
+	// неизвестный email: пользователя нет, остается только email
+	if failed[1].UserID != nil || failed[1].Details["email"] != "nobody@cwrk.test" {
+		t.Fatalf("unexpected failed login %+v", failed[1])
+	}
+
+	ok := events.byType(domain.AuthEventLogin)
+	if len(ok) != 1 || *ok[0].UserID != u.ID || ok[0].Details["method"] != "password" || *ok[0].RequestID != rid {
+		t.Fatalf("unexpected login events %+v", ok)
+	}
+}
+
+func TestAudit_RefreshReuseRevokesSessions(t *testing.T) {
+	env, events := newAuditEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := env.svc.Refresh(ctx, login.RefreshToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// старый токен предъявили повторно - все сессии пользователя завершены
+	if _, err := env.svc.Refresh(ctx, login.RefreshToken, nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("want ErrInvalidCredentials, got %v", err)
+	}
+	if _, err := env.svc.Refresh(ctx, rotated.RefreshToken, nil); err == nil {
+		t.Fatal("refresh after reuse detection must fail")
+	}
+
+	if got := events.byType(domain.AuthEventRefresh); len(got) != 1 {
+		t.Fatalf("want 1 refresh event, got %+v", got)
+	}
+	reuse := events.byType(domain.AuthEventRefreshReuse)
+	if len(reuse) != 1 || *reuse[0].UserID != u.ID || reuse[0].Details["revoked_sessions"] != "2" {
+		t.Fatalf("unexpected reuse events %+v", reuse)
+	}
+}
+
+func TestAudit_ConcurrentRefreshSingleWinner(t *testing.T) {
+	env, events := newAuditEnv(t)
+	env.svc.SetTxRunner(&memTxRunner{env: env, audit: &memAdminAudit{}, events: events})
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// один токен обменивают параллельно - проверку GetByTokenHash проходят все, DELETE удается только одному
+	const n = 8
+	var (
+		wg, arrived sync.WaitGroup
+		mu          sync.Mutex
+		winners     []*service.RefreshResult
+	)
+	arrived.Add(n)
+	env.sessions.afterGet = func() { arrived.Done(); arrived.Wait() }
+	start := make(chan struct{})
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			res, err := env.svc.Refresh(ctx, login.RefreshToken, nil)
+			if err != nil {
+				if !errors.Is(err, errs.ErrInvalidCredentials) {
+					t.Errorf("want ErrInvalidCredentials, got %v", err)
+				}
+				return
+			}
+			mu.Lock()
+			winners = append(winners, res)
+			mu.Unlock()
+		}()
+	}
+	close(start)
+	wg.Wait()
+	env.sessions.afterGet = nil
+
+	if len(winners) != 1 {
+		t.Fatalf("want exactly 1 successful refresh, got %d", len(winners))
+	}
+	if got := events.byType(domain.AuthEventRefresh); len(got) != 1 {
+		t.Fatalf("want 1 refresh event, got %+v", got)
+	}
+	// проигравшие - это повтор: сессии пользователя завершены, включая выданную победителю
+	if len(events.byType(domain.AuthEventRefreshReuse)) == 0 {
+		t.Fatal("want refresh reuse event")
+	}
+	if _, err := env.svc.Refresh(ctx, winners[0].RefreshToken, nil); err == nil {
+		t.Fatal("refresh after reuse detection must fail")
+	}
+	if list, _ := env.sessions.ListByUser(ctx, u.ID, time.Now()); len(list) != 0 {
+		t.Fatalf("want no sessions after reuse, got %d", len(list))
+	}
+}
+
+func TestAudit_UserActivityHidesActor(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	events := &memAuthEvents{}
+	env.svc.SetAuditLog(events)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, err := env.svc.AdminForcePasswordReset(ctx, admin.ID, u.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.svc.ChangePassword(ctx, "ann@cwrk.test", "password123", "new-password1", "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	page, next, err := env.svc.SecurityEvents(ctx, u.ID, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 2 || page[0].Type != domain.AuthEventPasswordChange || page[1].Type != domain.AdminAuthEvent(domain.AdminActionPasswordReset) || next == 0 {
+		t.Fatalf("first page: %+v next=%d", page, next)
+	}
+	if page[1].ActorID != nil {
+		t.Fatalf("actor must be hidden from user: %+v", page[1])
+	}
+	page, next, err = env.svc.SecurityEvents(ctx, u.ID, next, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page) != 1 || page[0].Type != domain.AuthEventRegister || next != 0 {
+		t.Fatalf("second page: %+v next=%d", page, next)
+	}
+}
+
+func TestAudit_AdminSearch(t *testing.T) {
+	env, _, admin := newAdminEnv(t)
+	events := &memAuthEvents{}
+	env.svc.SetAuditLog(events)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, _, err := env.svc.AdminSecurityEvents(ctx, u.ID, domain.AuthEventFilter{}); !errors.Is(err, errs.ErrForbidden) {
+		t.Fatalf("want ErrForbidden, got %v", err)
+	}
+	if _, err := env.svc.AdminDisableUser(ctx, admin.ID, u.ID, "spam"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil); !errors.Is(err, errs.ErrAccountDisabled) {
+		t.Fatalf("want ErrAccountDisabled, got %v", err)
+	}
+
+	got, _, err := env.svc.AdminSecurityEvents(ctx, admin.ID, domain.AuthEventFilter{
+		UserID: &u.ID,
+		Types:  []domain.AuthEventType{domain.AdminAuthEvent(domain.AdminActionDisable), domain.AuthEventLoginFailed},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Type != domain.AuthEventLoginFailed ||
+		got[1].ActorID == nil || *got[1].ActorID != admin.ID || got[1].Details["reason"] != "spam" {
+		t.Fatalf("unexpected events %+v", got)
+	}
+}
+
+func TestAudit_Unavailable(t *testing.T) {
+	env := newTestEnv(t)
+	u := env.register(t, "ann@cwrk.test")
+
+	if _, _, err := env.svc.SecurityEvents(context.Background(), u.ID, 0, 0); !errors.Is(err, errs.ErrAuditUnavailable) {
+		t.Fatalf("want ErrAuditUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/config"
+
+	"github.com/cwrk-planet/confload/pkg/confload"
+)
+
+func TestConfig_EnvAndFlags(t *testing.T) {
+	dsnFile := filepath.Join(t.TempDir(), "pg_dsn")
+	if err := os.WriteFile(dsnFile, []byte("postgres://auth:secret@db/auth\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AUTH_POSTGRES_DSN_FILE", dsnFile)
+	t.Setenv("AUTH_SECURITY_JWT_ALG", "RS256")
+	t.Setenv("AUTH_SECURITY_JWT_ACCESS_TTL", "15m")
+
+	cfg, err := config.Load([]string{
+		"-security.jwt.privateKeyPath=auth_private.pem",
+		"-security.jwt.publicKeyPath=auth_public.pem",
+		"-security.jwt.issuer=auth-service",
+		"-security.jwt.audience=cwrk-planet",
+		"-security.jwt.accessTTL=5m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Postgres.DSN != "postgres://auth:secret@db/auth" {
+		t.Errorf("dsn = %q", cfg.Postgres.DSN)
+	}
+	if cfg.Security.JWT.AccessTTL != 5*time.Minute {
+		t.Errorf("accessTTL = %v, flag must win over env", cfg.Security.JWT.AccessTTL)
+	}
+	if cfg.Server.GRPCAddr != ":50051" || cfg.Security.Password.MinLength != 6 {
+		t.Errorf("defaults are not applied: %+v %+v", cfg.Server, cfg.Security.Password)
+	}
+
+	if s := confload.Redacted(cfg); strings.Contains(s, "secret@db") {
+		t.Fatalf("dsn leaked:\n%s", s)
+	}
+}
+
+func TestConfig_AllErrorsAtOnce(t *testing.T) {
+	t.Setenv("PG_DSN", "postgres://auth:auth@db/auth")
+
+	_, err := config.Load([]string{"-security.password.algorithm=md5", "-account.deletionGrace=-1h"})
+	var list confload.Errors
+	if !errors.As(err, &list) {
+		t.Fatalf("err = %v, want confload.Errors", err)
+	}
+	for _, want := range []string{"security.password.algorithm", "security.jwt.alg is required", "account.deletionGrace"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+}
@@ -0,0 +1,1165 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/repository"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+// in-memory репозитории: сервис целиком, без Postgres
+
+type memUsers struct {
+	mu    sync.Mutex
+	next  domain.UserID
+	items map[domain.UserID]*domain.User
+}
+
+func newMemUsers() *memUsers { return &memUsers{items: map[domain.UserID]*domain.User{}} }
+
+func (r *memUsers) Create(_ context.Context, u *domain.User) (domain.UserID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, x := range r.items {
+		if x.Email == u.Email {
+			return 0, repository.ErrAlreadyExists
+		}
+	}
+	r.next++
+	cp := *u
+	cp.ID = r.next
+	r.items[cp.ID] = &cp
+
+	return cp.ID, nil
+}
+
+func (r *memUsers) GetByID(_ context.Context, id domain.UserID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.items[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	cp := *u
+
+	return &cp, nil
+}
+
+func (r *memUsers) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.items {
+		if strings.EqualFold(u.Email, email) {
+			cp := *u
+			return &cp, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+func (r *memUsers) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, email)
+	if err == repository.ErrNotFound {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (r *memUsers) UpdatePasswordHash(_ context.Context, id domain.UserID, newHash string, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.PasswordHash, u.PasswordResetRequired, u.UpdatedAt = newHash, false, now })
+}
+
+func (r *memUsers) UpdateProfile(_ context.Context, id domain.UserID, displayName *string, avatarURL *string, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.DisplayName, u.AvatarURL, u.UpdatedAt = displayName, avatarURL, now })
+}
+
+func (r *memUsers) MarkEmailVerified(_ context.Context, id domain.UserID, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.EmailVerified, u.UpdatedAt = true, now })
+}
+
+func (r *memUsers) Search(_ context.Context, q domain.UserSearch) ([]domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	query := strings.ToLower(q.Query)
+	var out []domain.User
+	for _, u := range r.items {
+		name := ""
+		if u.DisplayName != nil {
+			name = strings.ToLower(*u.DisplayName)
+		}
+		if u.ID > q.AfterID && (strings.Contains(strings.ToLower(u.Email), query) || strings.Contains(name, query)) {
+			out = append(out, *u)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.User) int { return int(a.ID - b.ID) })
+	if len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+
+	return out, nil
+}
+
+func (r *memUsers) SetDisabled(_ context.Context, id domain.UserID, disabledAt *time.Time, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.DisabledAt, u.UpdatedAt = disabledAt, now })
+}
+
+func (r *memUsers) RequirePasswordReset(_ context.Context, id domain.UserID, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.PasswordResetRequired, u.UpdatedAt = true, now })
+}
+
+func (r *memUsers) ScheduleDeletion(_ context.Context, id domain.UserID, at *time.Time, now time.Time) error {
+	return r.update(id, func(u *domain.User) { u.DeletionScheduledAt, u.UpdatedAt = at, now })
+}
+
+func (r *memUsers) ListDueDeletions(_ context.Context, now time.Time, limit int) ([]domain.UserID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.UserID
+	for _, u := range r.items {
+		if u.DeletionScheduledAt != nil && !u.DeletionScheduledAt.After(now) && len(out) < limit {
+			out = append(out, u.ID)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *memUsers) Delete(_ context.Context, id domain.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.items, id)
+
+	return nil
+}
+
+func (r *memUsers) RehashPassword(_ context.Context, id domain.UserID, oldHash, newHash string) (bool, error) {
+	var ok bool
+	err := r.update(id, func(u *domain.User) {
+		if ok = u.PasswordHash == oldHash; ok {
+			u.PasswordHash = newHash
+		}
+	})
+	return ok, err
+}
+
+func (r *memUsers) update(id domain.UserID, fn func(u *domain.User)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.items[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	fn(u)
+
+	return nil
+}
+
+type memSessions struct {
+	mu       sync.Mutex
+	next     domain.SessionID
+	items    map[domain.SessionID]*domain.Session
+	rotated  map[string]domain.UserID
+	afterGet func() // вызывается после GetByTokenHash - тест гонки выравнивает по нему запросы
+}
+
+func newMemSessions() *memSessions {
+	return &memSessions{items: map[domain.SessionID]*domain.Session{}, rotated: map[string]domain.UserID{}}
+}
+
+func (r *memSessions) Create(_ context.Context, s *domain.Session) (domain.SessionID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.next++
+	cp := *s
+	cp.ID = r.next
+	r.items[cp.ID] = &cp
+
+	return cp.ID, nil
+}
+
+func (r *memSessions) GetByTokenHash(_ context.Context, tokenHash string) (*domain.Session, error) {
+	if r.afterGet != nil {
+		defer r.afterGet()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.items {
+		if s.TokenHash == tokenHash {
+			cp := *s
+			return &cp, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+func (r *memSessions) ListByUser(_ context.Context, userID domain.UserID, now time.Time) ([]domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Session
+	for _, s := range r.items {
+		if s.UserID == userID && !s.IsExpired(now) {
+			out = append(out, *s)
+		}
+	}
+	slices.SortFunc(out, func(a, b domain.Session) int { return int(b.ID - a.ID) })
+
+	return out, nil
+}
+
+func (r *memSessions) DeleteByID(_ context.Context, id domain.SessionID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.items, id)
+
+	return nil
+}
+
+func (r *memSessions) DeleteByTokenHash(_ context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, s := range r.items {
+		if s.TokenHash == tokenHash {
+			delete(r.items, id)
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+func (r *memSessions) DeleteByUser(_ context.Context, userID domain.UserID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, s := range r.items {
+		if s.UserID == userID {
+			delete(r.items, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (r *memSessions) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, s := range r.items {
+		if s.IsExpired(now) {
+			delete(r.items, id)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (r *memSessions) AddRotated(_ context.Context, tokenHash string, userID domain.UserID, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.rotated[tokenHash]; !ok {
+		r.rotated[tokenHash] = userID
+	}
+
+	return nil
+}
+
+func (r *memSessions) GetRotatedOwner(_ context.Context, tokenHash string) (domain.UserID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	uid, ok := r.rotated[tokenHash]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+
+	return uid, nil
+}
+
+type memPasskeys struct {
+	mu    sync.Mutex
+	next  domain.PasskeyID
+	items []domain.Passkey
+}
+
+func (r *memPasskeys) Create(_ context.Context, p *domain.Passkey) (domain.PasskeyID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, x := range r.items {
+		if bytes.Equal(x.CredentialID, p.CredentialID) {
+			return 0, repository.ErrAlreadyExists
+		}
+	}
+	r.next++
+	cp := *p
+	cp.ID = r.next
+	r.items = append(r.items, cp)
+
+	return cp.ID, nil
+}
+
+func (r *memPasskeys) ListByUser(_ context.Context, userID domain.UserID) ([]domain.Passkey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Passkey
+	for _, p := range r.items {
+		if p.UserID == userID {
+			out = append(out, p)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *memPasskeys) GetByCredentialID(_ context.Context, credentialID []byte) (*domain.Passkey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, p := range r.items {
+		if bytes.Equal(p.CredentialID, credentialID) {
+			return &p, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+func (r *memPasskeys) UpdateUsage(_ context.Context, id domain.PasskeyID, signCount uint32, backupState bool, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id {
+			r.items[i].SignCount = signCount
+			r.items[i].BackupState = backupState
+			r.items[i].LastUsedAt = &now
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+func (r *memPasskeys) Rename(_ context.Context, userID domain.UserID, id domain.PasskeyID, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id && r.items[i].UserID == userID {
+			r.items[i].Name = name
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+func (r *memPasskeys) Delete(_ context.Context, userID domain.UserID, id domain.PasskeyID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id && r.items[i].UserID == userID {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+type memCeremonies struct {
+	mu    sync.Mutex
+	items map[string]domain.WebAuthnCeremony
+}
+
+func newMemCeremonies() *memCeremonies {
+	return &memCeremonies{items: map[string]domain.WebAuthnCeremony{}}
+}
+
+func (r *memCeremonies) Create(_ context.Context, c *domain.WebAuthnCeremony) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[c.IDHash] = *c
+
+	return nil
+}
+
+func (r *memCeremonies) Take(_ context.Context, idHash string) (*domain.WebAuthnCeremony, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.items[idHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(r.items, idHash)
+
+	return &c, nil
+}
+
+func (r *memCeremonies) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, c := range r.items {
+		if c.IsExpired(now) {
+			delete(r.items, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+type testEnv struct {
+	svc      *service.AuthService
+	users    *memUsers
+	sessions *memSessions
+	roles    *memRoles
+	key      *rsa.PrivateKey // ключ подписи access-токенов
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := security.NewJWTSigner(key, &key.PublicKey, "auth-test", "cwrk-test", 15*time.Minute, time.Minute)
+
+	env := &testEnv{users: newMemUsers(), sessions: newMemSessions(), roles: newMemRoles(), key: key}
+	env.svc = service.NewAuthService(env.users, env.sessions, signer, 24*time.Hour, testPasswordConfig(), time.Now)
+	env.svc.SetRBAC(env.roles, service.RBACConfig{})
+
+	return env
+}
+
+// register - пользователь с паролем password123
+func (e *testEnv) register(t *testing.T, email string) *domain.User {
+	t.Helper()
+	res, err := e.svc.Register(context.Background(), email, "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return res.User
+}
+
+type memIdentities struct {
+	mu    sync.Mutex
+	next  domain.IdentityID
+	items []domain.Identity
+}
+
+func (r *memIdentities) Create(_ context.Context, i *domain.Identity) (domain.IdentityID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, x := range r.items {
+		if (x.Provider == i.Provider && x.Subject == i.Subject) || (x.UserID == i.UserID && x.Provider == i.Provider) {
+			return 0, repository.ErrAlreadyExists
+		}
+	}
+	r.next++
+	cp := *i
+	cp.ID = r.next
+	r.items = append(r.items, cp)
+
+	return cp.ID, nil
+}
+
+func (r *memIdentities) GetBySubject(_ context.Context, provider, subject string) (*domain.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.items {
+		if i.Provider == provider && i.Subject == subject {
+			return &i, nil
+		}
+	}
+
+	return nil, repository.ErrNotFound
+}
+
+func (r *memIdentities) ListByUser(_ context.Context, userID domain.UserID) ([]domain.Identity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Identity
+	for _, i := range r.items {
+		if i.UserID == userID {
+			out = append(out, i)
+		}
+	}
+
+	return out, nil
+}
+
+func (r *memIdentities) TouchLogin(_ context.Context, id domain.IdentityID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].ID == id {
+			r.items[i].LastLoginAt = &now
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+func (r *memIdentities) Delete(_ context.Context, userID domain.UserID, provider string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.items {
+		if r.items[i].UserID == userID && r.items[i].Provider == provider {
+			r.items = append(r.items[:i], r.items[i+1:]...)
+			return nil
+		}
+	}
+
+	return repository.ErrNotFound
+}
+
+type memOIDCStates struct {
+	mu    sync.Mutex
+	items map[string]domain.OIDCState
+}
+
+func newMemOIDCStates() *memOIDCStates {
+	return &memOIDCStates{items: map[string]domain.OIDCState{}}
+}
+
+func (r *memOIDCStates) Create(_ context.Context, s *domain.OIDCState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[s.StateHash] = *s
+
+	return nil
+}
+
+func (r *memOIDCStates) Take(_ context.Context, stateHash string) (*domain.OIDCState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.items[stateHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(r.items, stateHash)
+
+	return &s, nil
+}
+
+func (r *memOIDCStates) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, s := range r.items {
+		if s.IsExpired(now) {
+			delete(r.items, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+type memOAuthClients struct {
+	items map[string]domain.OAuthClient
+}
+
+func (r *memOAuthClients) Get(_ context.Context, id string) (*domain.OAuthClient, error) {
+	c, ok := r.items[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	return &c, nil
+}
+
+type consentKey struct {
+	userID   domain.UserID
+	clientID string
+}
+
+type memOAuthGrants struct {
+	mu       sync.Mutex
+	codes    map[string]domain.OAuthCode
+	refresh  map[string]domain.OAuthRefreshToken
+	consents map[consentKey][]string
+}
+
+func newMemOAuthGrants() *memOAuthGrants {
+	return &memOAuthGrants{
+		codes:    map[string]domain.OAuthCode{},
+		refresh:  map[string]domain.OAuthRefreshToken{},
+		consents: map[consentKey][]string{},
+	}
+}
+
+func (r *memOAuthGrants) CreateCode(_ context.Context, c *domain.OAuthCode) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.codes[c.CodeHash] = *c
+
+	return nil
+}
+
+func (r *memOAuthGrants) TakeCode(_ context.Context, codeHash string) (*domain.OAuthCode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.codes[codeHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(r.codes, codeHash)
+
+	return &c, nil
+}
+
+func (r *memOAuthGrants) CreateRefreshToken(_ context.Context, t *domain.OAuthRefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.refresh[t.TokenHash] = *t
+
+	return nil
+}
+
+func (r *memOAuthGrants) TakeRefreshToken(_ context.Context, tokenHash string) (*domain.OAuthRefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.refresh[tokenHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	delete(r.refresh, tokenHash)
+
+	return &t, nil
+}
+
+func (r *memOAuthGrants) GetConsent(_ context.Context, userID domain.UserID, clientID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.consents[consentKey{userID, clientID}]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	return append([]string(nil), s...), nil
+}
+
+func (r *memOAuthGrants) SaveConsent(_ context.Context, userID domain.UserID, clientID string, scopes []string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.consents[consentKey{userID, clientID}] = append([]string(nil), scopes...)
+
+	return nil
+}
+
+func (r *memOAuthGrants) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, c := range r.codes {
+		if c.IsExpired(now) {
+			delete(r.codes, k)
+			n++
+		}
+	}
+	for k, t := range r.refresh {
+		if t.IsExpired(now) {
+			delete(r.refresh, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+type memRoles struct {
+	mu     sync.Mutex
+	roles  map[string]domain.Role
+	assign map[domain.UserID][]string
+}
+
+func newMemRoles() *memRoles {
+	return &memRoles{
+		roles: map[string]domain.Role{
+			"user":  {Name: "user", Permissions: []string{"rooms:create"}},
+			"admin": {Name: "admin", Permissions: []string{"rooms:create", "admin:users"}},
+		},
+		assign: map[domain.UserID][]string{},
+	}
+}
+
+func (r *memRoles) List(_ context.Context) ([]domain.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Role
+	for _, role := range r.roles {
+		out = append(out, role)
+	}
+
+	return out, nil
+}
+
+func (r *memRoles) ListByUser(_ context.Context, userID domain.UserID) ([]domain.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.Role
+	for _, name := range r.assign[userID] {
+		out = append(out, r.roles[name])
+	}
+
+	return out, nil
+}
+
+func (r *memRoles) Assign(_ context.Context, userID domain.UserID, role string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.roles[role]; !ok {
+		return repository.ErrNotFound
+	}
+	if !slices.Contains(r.assign[userID], role) {
+		r.assign[userID] = append(r.assign[userID], role)
+	}
+
+	return nil
+}
+
+func (r *memRoles) NamesByUsers(_ context.Context, userIDs []domain.UserID) (map[domain.UserID][]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := map[domain.UserID][]string{}
+	for _, id := range userIDs {
+		if names := r.assign[id]; len(names) > 0 {
+			out[id] = slices.Sorted(slices.Values(names))
+		}
+	}
+
+	return out, nil
+}
+
+func (r *memRoles) Replace(_ context.Context, userID domain.UserID, roles []string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, role := range roles {
+		if _, ok := r.roles[role]; !ok {
+			return repository.ErrNotFound
+		}
+	}
+	r.assign[userID] = slices.Clone(roles)
+
+	return nil
+}
+
+type memAdminAudit struct {
+	mu    sync.Mutex
+	items []domain.AdminAuditEntry
+}
+
+func (r *memAdminAudit) Add(_ context.Context, e *domain.AdminAuditEntry) (domain.AdminAuditID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *e
+	cp.ID = domain.AdminAuditID(len(r.items) + 1)
+	r.items = append(r.items, cp)
+
+	return cp.ID, nil
+}
+
+func (r *memAdminAudit) List(_ context.Context, f domain.AdminAuditFilter) ([]domain.AdminAuditEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.AdminAuditEntry
+	for i := len(r.items) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		e := r.items[i]
+		if f.BeforeID > 0 && e.ID >= f.BeforeID {
+			continue
+		}
+		if f.ActorID != nil && e.ActorID != *f.ActorID {
+			continue
+		}
+		if f.TargetUserID != nil && (e.TargetUserID == nil || *e.TargetUserID != *f.TargetUserID) {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+type memAuthEvents struct {
+	mu    sync.Mutex
+	items []domain.AuthEvent
+}
+
+func (r *memAuthEvents) Add(_ context.Context, e *domain.AuthEvent) (domain.AuthEventID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *e
+	cp.ID = domain.AuthEventID(len(r.items) + 1)
+	r.items = append(r.items, cp)
+
+	return cp.ID, nil
+}
+
+func (r *memAuthEvents) List(_ context.Context, f domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.AuthEvent
+	for i := len(r.items) - 1; i >= 0 && len(out) < f.Limit; i-- {
+		e := r.items[i]
+		if f.BeforeID > 0 && e.ID >= f.BeforeID {
+			continue
+		}
+		if f.UserID != nil && (e.UserID == nil || *e.UserID != *f.UserID) {
+			continue
+		}
+		if len(f.Types) > 0 && !slices.Contains(f.Types, e.Type) {
+			continue
+		}
+		if f.IP != nil && (e.IP == nil || *e.IP != *f.IP) {
+			continue
+		}
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+// byType - события заданного типа в порядке записи
+func (r *memAuthEvents) byType(typ domain.AuthEventType) []domain.AuthEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []domain.AuthEvent
+	for _, e := range r.items {
+		if e.Type == typ {
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
+
+// testPasswordConfig - argon2id с минимальной стоимостью, чтобы тесты не тормозили
+func testPasswordConfig() security.PasswordConfig {
+	return security.PasswordConfig{
+		Argon2:    security.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1},
+		MinLength: 8,
+	}
+}
+
+// memLoginFailures - как auth_login_failures: неудача старше resetBefore начинает счетчик заново и снимает блокировку
+type memLoginFailures struct {
+	mu    sync.Mutex
+	items map[string]domain.LoginFailures
+}
+
+func newMemLoginFailures() *memLoginFailures {
+	return &memLoginFailures{items: map[string]domain.LoginFailures{}}
+}
+
+func loginFailuresKey(scope domain.LoginScope, key string) string { return string(scope) + "|" + key }
+
+func (r *memLoginFailures) Get(_ context.Context, scope domain.LoginScope, key string) (*domain.LoginFailures, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.items[loginFailuresKey(scope, key)]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	return &f, nil
+}
+
+func (r *memLoginFailures) RegisterFailure(_ context.Context, scope domain.LoginScope, key string, now, resetBefore time.Time) (*domain.LoginFailures, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := loginFailuresKey(scope, key)
+	f, ok := r.items[k]
+	if !ok || f.LastFailedAt.Before(resetBefore) {
+		f = domain.LoginFailures{Scope: scope, Key: key}
+	}
+	f.Failures++
+	f.LastFailedAt = now
+	r.items[k] = f
+
+	return &f, nil
+}
+
+func (r *memLoginFailures) Lock(_ context.Context, scope domain.LoginScope, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := loginFailuresKey(scope, key)
+	f, ok := r.items[k]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	f.LockedUntil = &until
+	r.items[k] = f
+
+	return nil
+}
+
+func (r *memLoginFailures) Reset(_ context.Context, scope domain.LoginScope, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.items, loginFailuresKey(scope, key))
+
+	return nil
+}
+
+func (r *memLoginFailures) DeleteStale(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, f := range r.items {
+		if f.LastFailedAt.Before(before) && (f.LockedUntil == nil || f.LockedUntil.Before(before)) {
+			delete(r.items, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// memMFA - TOTP в строке пользователя и коды восстановления, как в Postgres
+type memMFA struct {
+	mu       sync.Mutex
+	totp     map[domain.UserID]domain.TOTPState
+	recovery map[domain.UserID]map[string]bool // хеш -> использован
+}
+
+func newMemMFA() *memMFA {
+	return &memMFA{totp: map[domain.UserID]domain.TOTPState{}, recovery: map[domain.UserID]map[string]bool{}}
+}
+
+func (r *memMFA) GetTOTP(_ context.Context, userID domain.UserID) (*domain.TOTPState, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.totp[userID]
+
+	return &st, nil
+}
+
+func (r *memMFA) SetPendingTOTP(_ context.Context, userID domain.UserID, secretEnc string, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.totp[userID] = domain.TOTPState{SecretEnc: &secretEnc}
+
+	return nil
+}
+
+func (r *memMFA) EnableTOTP(_ context.Context, userID domain.UserID, step int64, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.totp[userID]
+	if st.SecretEnc == nil {
+		return repository.ErrNotFound
+	}
+	st.EnabledAt, st.LastStep = &now, step
+	r.totp[userID] = st
+
+	return nil
+}
+
+func (r *memMFA) DisableTOTP(_ context.Context, userID domain.UserID, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.totp, userID)
+
+	return nil
+}
+
+func (r *memMFA) AdvanceTOTPStep(_ context.Context, userID domain.UserID, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	st := r.totp[userID]
+	if st.LastStep >= step {
+		return false, nil
+	}
+	st.LastStep = step
+	r.totp[userID] = st
+
+	return true, nil
+}
+
+func (r *memMFA) ReplaceRecoveryCodes(_ context.Context, userID domain.UserID, hashes []string, _ time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	codes := make(map[string]bool, len(hashes))
+	for _, h := range hashes {
+		codes[h] = false
+	}
+	r.recovery[userID] = codes
+
+	return nil
+}
+
+func (r *memMFA) UseRecoveryCode(_ context.Context, userID domain.UserID, hash string, _ time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	used, ok := r.recovery[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recovery[userID][hash] = true
+
+	return true, nil
+}
+
+func (r *memMFA) DeleteRecoveryCodes(_ context.Context, userID domain.UserID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.recovery, userID)
+
+	return nil
+}
+
+type memMFAChallenges struct {
+	mu    sync.Mutex
+	items map[string]domain.MFAChallenge
+}
+
+func newMemMFAChallenges() *memMFAChallenges {
+	return &memMFAChallenges{items: map[string]domain.MFAChallenge{}}
+}
+
+func (r *memMFAChallenges) Create(_ context.Context, c *domain.MFAChallenge) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items[c.TokenHash] = *c
+
+	return nil
+}
+
+func (r *memMFAChallenges) GetByTokenHash(_ context.Context, tokenHash string) (*domain.MFAChallenge, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.items[tokenHash]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+
+	return &c, nil
+}
+
+func (r *memMFAChallenges) IncrementAttempts(_ context.Context, tokenHash string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.items[tokenHash]
+	if !ok {
+		return 0, repository.ErrNotFound
+	}
+	c.Attempts++
+	r.items[tokenHash] = c
+
+	return c.Attempts, nil
+}
+
+func (r *memMFAChallenges) Delete(_ context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.items[tokenHash]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(r.items, tokenHash)
+
+	return nil
+}
+
+func (r *memMFAChallenges) DeleteExpired(_ context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for k, c := range r.items {
+		if c.IsExpired(now) {
+			delete(r.items, k)
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// memTxRunner - транзакция над журналами: записи admin_audit_log и auth_audit_events копятся и попадают
+// в audit и events только при успехе fn. Users, Roles и Sessions пишут сразу - проверяем только журналы.
+// Транзакции идут по одной - как блокировка строки в Postgres
+type memTxRunner struct {
+	mu         sync.Mutex
+	env        *testEnv
+	audit      *memAdminAudit
+	events     *memAuthEvents
+	failEvents bool // запись в журнал безопасности внутри транзакции падает
+	commits    int
+}
+
+var errTxEventsDown = errors.New("auth_audit_events unavailable")
+
+func (r *memTxRunner) InTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tx := &memTx{r: r, log: &memAdminAudit{}, events: &memAuthEvents{}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	for _, e := range tx.log.items {
+		_, _ = r.audit.Add(ctx, &e)
+	}
+	for _, e := range tx.events.items {
+		_, _ = r.events.Add(ctx, &e)
+	}
+	r.commits++
+
+	return nil
+}
+
+type memTx struct {
+	r      *memTxRunner
+	log    *memAdminAudit
+	events *memAuthEvents
+}
+
+func (t *memTx) Users() repository.UserRepository            { return t.r.env.users }
+func (t *memTx) Roles() repository.RoleRepository            { return t.r.env.roles }
+func (t *memTx) Sessions() repository.SessionRepository      { return t.r.env.sessions }
+func (t *memTx) Outbox() repository.OutboxRepository         { return memOutbox{} }
+func (t *memTx) MFA() repository.MFARepository               { return nil }
+func (t *memTx) Identities() repository.IdentityRepository   { return nil }
+func (t *memTx) AdminAudit() repository.AdminAuditRepository { return t.log }
+func (t *memTx) AuthEvents() repository.AuthEventRepository {
+	if t.r.failEvents {
+		return failingAuthEvents{}
+	}
+	return t.events
+}
+
+type memOutbox struct{}
+
+func (memOutbox) Add(context.Context, events.Event) error { return nil }
+
+type failingAuthEvents struct{}
+
+func (failingAuthEvents) Add(context.Context, *domain.AuthEvent) (domain.AuthEventID, error) {
+	return 0, errTxEventsDown
+}
+
+func (failingAuthEvents) List(context.Context, domain.AuthEventFilter) ([]domain.AuthEvent, error) {
+	return nil, errTxEventsDown
+}
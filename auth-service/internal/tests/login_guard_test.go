@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+// guardEnv - сервис с защитой от перебора и ручными часами
+type guardEnv struct {
+	*testEnv
+	now time.Time
+}
+
+func newGuardEnv(t *testing.T, cfg service.LoginGuardConfig) *guardEnv {
+	t.Helper()
+	env := &guardEnv{testEnv: newTestEnv(t), now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	env.svc = newClockedService(env.testEnv, testPasswordConfig(), func() time.Time { return env.now })
+	env.svc.SetLoginGuard(newMemLoginFailures(), cfg)
+
+	return env
+}
+
+func newClockedService(e *testEnv, pass security.PasswordConfig, now func() time.Time) *service.AuthService {
+	signer := security.NewJWTSigner(e.key, &e.key.PublicKey, "auth-test", "cwrk-test", 15*time.Minute, time.Minute)
+	svc := service.NewAuthService(e.users, e.sessions, signer, 24*time.Hour, pass, now)
+	svc.SetRBAC(e.roles, service.RBACConfig{})
+
+	return svc
+}
+
+func (e *guardEnv) login(email, password, ip string) error {
+	var meta *service.LoginMeta
+	if ip != "" {
+		addr := netip.MustParseAddr(ip)
+		meta = &service.LoginMeta{IP: &addr}
+	}
+	_, err := e.svc.Login(context.Background(), email, password, meta)
+
+	return err
+}
+
+func expectErr(t *testing.T, step string, err, want error) {
+	t.Helper()
+	if want == nil && err != nil || want != nil && !errors.Is(err, want) {
+		t.Fatalf("%s: err = %v, want %v", step, err, want)
+	}
+}
+
+func TestLoginGuard_AccountLockout(t *testing.T) {
+	env := newGuardEnv(t, service.LoginGuardConfig{
+		MaxFailures: 3,
+		Lockout:     10 * time.Minute,
+		BaseDelay:   time.Second,
+		MaxDelay:    4 * time.Second,
+	})
+	env.register(t, "a@example.com")
+
+	for i := range 3 {
+		expectErr(t, "wrong password", env.login("a@example.com", "wrong-password", ""), errs.ErrInvalidCredentials)
+		if i < 2 {
+			env.now = env.now.Add(4 * time.Second)
+		}
+	}
+
+	// третья неудача блокирует аккаунт: и верный пароль не пускает, пока не выйдет Lockout
+	env.now = env.now.Add(5 * time.Minute)
+	expectErr(t, "locked", env.login("A@example.com ", "password123", ""), errs.ErrTooManyAttempts)
+	// попытки во время блокировки ее не продлевают и не считаются
+	env.now = env.now.Add(5 * time.Minute)
+	expectErr(t, "after lockout", env.login("a@example.com", "password123", ""), nil)
+
+	// успешный вход сбросил счетчик: снова три попытки до блокировки
+	for range 2 {
+		env.now = env.now.Add(4 * time.Second)
+		expectErr(t, "wrong password after reset", env.login("a@example.com", "wrong-password", ""), errs.ErrInvalidCredentials)
+	}
+	env.now = env.now.Add(4 * time.Second)
+	expectErr(t, "not locked yet", env.login("a@example.com", "password123", ""), nil)
+}
+
+func TestLoginGuard_DelayDoublesUpToMax(t *testing.T) {
+	env := newGuardEnv(t, service.LoginGuardConfig{
+		MaxFailures: 100,
+		BaseDelay:   time.Second,
+		MaxDelay:    4 * time.Second,
+	})
+	env.register(t, "a@example.com")
+
+	for i, delay := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second} {
+		expectErr(t, "wrong password", env.login("a@example.com", "wrong-password", ""), errs.ErrInvalidCredentials)
+
+		env.now = env.now.Add(delay - time.Millisecond)
+		if err := env.login("a@example.com", "password123", ""); !errors.Is(err, errs.ErrTooManyAttempts) {
+			t.Fatalf("failure %d: attempt before %v pause: err = %v", i+1, delay, err)
+		}
+		env.now = env.now.Add(time.Millisecond)
+	}
+
+	// за паузой пускает, и с верным паролем вход проходит
+	expectErr(t, "after pause", env.login("a@example.com", "password123", ""), nil)
+	expectErr(t, "counter reset", env.login("a@example.com", "password123", ""), nil)
+}
+
+func TestLoginGuard_IPThreshold(t *testing.T) {
+	env := newGuardEnv(t, service.LoginGuardConfig{
+		MaxFailures:   100,
+		IPMaxFailures: 4,
+		Lockout:       time.Hour,
+		BaseDelay:     time.Second,
+		MaxDelay:      time.Second,
+	})
+	env.register(t, "victim@example.com")
+
+	// перебор по разным аккаунтам: счетчик каждого email остается малым, срабатывает счетчик IP
+	for _, email := range []string{"a@example.com", "b@example.com", "victim@example.com", "c@example.com"} {
+		expectErr(t, email, env.login(email, "wrong-password", "203.0.113.7"), errs.ErrInvalidCredentials)
+		env.now = env.now.Add(time.Second)
+	}
+
+	expectErr(t, "ip locked", env.login("victim@example.com", "password123", "203.0.113.7"), errs.ErrTooManyAttempts)
+	expectErr(t, "other ip", env.login("victim@example.com", "password123", "198.51.100.1"), nil)
+	// успешный вход с другого адреса блокировку IP не снимает
+	expectErr(t, "ip still locked", env.login("d@example.com", "password123", "203.0.113.7"), errs.ErrTooManyAttempts)
+}
+
+func TestLogin_UnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	env := newTestEnv(t)
+	// заметная стоимость argon2, чтобы по времени было видно, что сравнение с dummy-хешем выполняется
+	pass := testPasswordConfig()
+	pass.Argon2 = security.Argon2Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1}
+	env.svc = newClockedService(env, pass, time.Now)
+	env.register(t, "a@example.com")
+	ctx := context.Background()
+
+	measure := func(email string) (error, time.Duration) {
+		var (
+			lastErr error
+			best    time.Duration
+		)
+		for i := range 3 {
+			start := time.Now()
+			_, lastErr = env.svc.Login(ctx, email, "wrong-password", nil)
+			if d := time.Since(start); i == 0 || d < best {
+				best = d
+			}
+		}
+		return lastErr, best
+	}
+
+	wrongErr, wrongTook := measure("a@example.com")
+	unknownErr, unknownTook := measure("nobody@example.com")
+
+	if !errors.Is(wrongErr, errs.ErrInvalidCredentials) || unknownErr != wrongErr {
+		t.Fatalf("errors differ: wrong password %v, unknown email %v", wrongErr, unknownErr)
+	}
+	if unknownTook < wrongTook/3 {
+		t.Fatalf("unknown email answered in %v, wrong password in %v: dummy hash was not compared", unknownTook, wrongTook)
+	}
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	// RFC 6238, приложение B (SHA1): ключ "12345678901234567890", у нас последние 6 цифр из 8
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	for _, v := range []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	} {
+		step, ok := security.ValidateTOTP(secret, v.code, time.Unix(v.unix, 0), 0)
+		if !ok || step != v.unix/30 {
+			t.Fatalf("T=%d code %s: step=%d ok=%v", v.unix, v.code, step, ok)
+		}
+	}
+
+	now := time.Unix(1111111111, 0)
+	if _, ok := security.ValidateTOTP(secret, "050472", now, 0); ok {
+		t.Fatal("wrong code accepted")
+	}
+	// соседний шаг принимаем (часы телефона), через один - уже нет
+	if _, ok := security.ValidateTOTP(secret, "050471", now.Add(30*time.Second), 0); !ok {
+		t.Fatal("previous step must be accepted")
+	}
+	if _, ok := security.ValidateTOTP(secret, "050471", now.Add(60*time.Second), 0); ok {
+		t.Fatal("code two steps old accepted")
+	}
+	// шаг не новее lastStep - повтор
+	if _, ok := security.ValidateTOTP(secret, "050471", now, 1111111111/30); ok {
+		t.Fatal("replayed step accepted")
+	}
+}
+
+func TestSecretBox_RoundTripAndTamper(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	box, err := security.NewSecretBoxFromBase64(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := box.Seal("JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := box.Seal("JBSWY3DPEHPK3PXP")
+	if enc == again {
+		t.Fatal("nonce must be random")
+	}
+	if plain, err := box.Open(enc); err != nil || plain != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Open = %q, %v", plain, err)
+	}
+
+	raw, _ := base64.StdEncoding.DecodeString(enc)
+	for _, i := range []int{0, len(raw) / 2, len(raw) - 1} { // nonce, шифртекст, тег
+		tampered := append([]byte(nil), raw...)
+		tampered[i] ^= 1
+		if _, err := box.Open(base64.StdEncoding.EncodeToString(tampered)); err == nil {
+			t.Fatalf("tampered byte %d accepted", i)
+		}
+	}
+	if _, err := box.Open(base64.StdEncoding.EncodeToString(raw[:8])); err == nil {
+		t.Fatal("truncated ciphertext accepted")
+	}
+
+	other, _ := security.NewSecretBoxFromBase64(base64.StdEncoding.EncodeToString([]byte("fedcba9876543210fedcba9876543210")))
+	if _, err := other.Open(enc); err == nil {
+		t.Fatal("opened with another key")
+	}
+	if _, err := security.NewSecretBoxFromBase64(base64.StdEncoding.EncodeToString([]byte("short"))); !errors.Is(err, security.ErrInvalidSecretBoxKey) {
+		t.Fatalf("short key: %v", err)
+	}
+}
+
+// mfaEnv - сервис с TOTP и ручными часами (коды зависят от времени)
+type mfaEnv struct {
+	*testEnv
+	now    time.Time
+	user   *domain.User
+	secret string
+	codes  []string // коды восстановления
+}
+
+func newMFAEnv(t *testing.T, cfg service.MFAConfig) *mfaEnv {
+	t.Helper()
+	env := &mfaEnv{testEnv: newTestEnv(t), now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	env.svc = newClockedService(env.testEnv, testPasswordConfig(), func() time.Time { return env.now })
+	box, err := security.NewSecretBoxFromBase64(base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.svc.SetMFA(newMemMFA(), newMemMFAChallenges(), box, cfg)
+
+	ctx := context.Background()
+	env.user = env.register(t, "mfa@example.com")
+	key, err := env.svc.EnrollTOTP(ctx, env.user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.secret = key.Secret
+	if env.codes, err = env.svc.ConfirmTOTP(ctx, env.user.ID, env.code(env.now)); err != nil {
+		t.Fatal(err)
+	}
+
+	return env
+}
+
+func (e *mfaEnv) code(at time.Time) string {
+	c, err := totp.GenerateCodeCustom(e.secret, at, totp.ValidateOpts{Period: 30, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1})
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// mfaToken - первый шаг входа, пароль верный
+func (e *mfaEnv) mfaToken(t *testing.T) string {
+	t.Helper()
+	res, err := e.svc.Login(context.Background(), "mfa@example.com", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.MFARequired || res.MFAToken == "" || res.AccessToken != "" {
+		t.Fatalf("login must stop at the second factor: %+v", res)
+	}
+
+	return res.MFAToken
+}
+
+func (e *mfaEnv) verify(t *testing.T, token, code string) error {
+	t.Helper()
+	res, err := e.svc.VerifyMFA(context.Background(), token, code, nil)
+	if err == nil && res.AccessToken == "" {
+		t.Fatal("no tokens after the second factor")
+	}
+
+	return err
+}
+
+func TestMFA_TOTPReplayRejected(t *testing.T) {
+	env := newMFAEnv(t, service.MFAConfig{})
+
+	// код, которым подтвердили подключение, уже израсходован
+	expectErr(t, "confirm code reused", env.verify(t, env.mfaToken(t), env.code(env.now)), errs.ErrInvalidMFACode)
+
+	env.now = env.now.Add(30 * time.Second)
+	code := env.code(env.now)
+	expectErr(t, "fresh code", env.verify(t, env.mfaToken(t), code), nil)
+	expectErr(t, "same code again", env.verify(t, env.mfaToken(t), code), errs.ErrInvalidMFACode)
+
+	env.now = env.now.Add(30 * time.Second)
+	expectErr(t, "next step", env.verify(t, env.mfaToken(t), env.code(env.now)), nil)
+}
+
+func TestMFA_MaxAttemptsPerToken(t *testing.T) {
+	env := newMFAEnv(t, service.MFAConfig{MaxAttempts: 3})
+	wrong := env.code(env.now.Add(time.Hour))
+
+	env.now = env.now.Add(30 * time.Second)
+	token := env.mfaToken(t)
+	for range 2 {
+		expectErr(t, "wrong code", env.verify(t, token, wrong), errs.ErrInvalidMFACode)
+	}
+	expectErr(t, "correct code within limit", env.verify(t, token, env.code(env.now)), nil)
+	// mfa_token одноразовый
+	expectErr(t, "token reused", env.verify(t, token, env.code(env.now.Add(30*time.Second))), errs.ErrInvalidMFAToken)
+
+	env.now = env.now.Add(30 * time.Second)
+	token = env.mfaToken(t)
+	for range 3 {
+		expectErr(t, "wrong code", env.verify(t, token, wrong), errs.ErrInvalidMFACode)
+	}
+	expectErr(t, "token exhausted", env.verify(t, token, env.code(env.now)), errs.ErrInvalidMFAToken)
+
+	// лимит на токен, а не на пользователя: новый вход - новые попытки
+	expectErr(t, "new token", env.verify(t, env.mfaToken(t), env.code(env.now)), nil)
+}
+
+func TestMFA_RecoveryCodesSingleUse(t *testing.T) {
+	env := newMFAEnv(t, service.MFAConfig{RecoveryCodes: 3})
+	if len(env.codes) != 3 {
+		t.Fatalf("recovery codes = %v", env.codes)
+	}
+
+	// регистр, пробелы и дефис не важны
+	first := " " + strings.ToUpper(strings.ReplaceAll(env.codes[0], "-", "")) + " "
+	expectErr(t, "recovery code", env.verify(t, env.mfaToken(t), first), nil)
+	expectErr(t, "recovery code reused", env.verify(t, env.mfaToken(t), env.codes[0]), errs.ErrInvalidMFACode)
+	expectErr(t, "another recovery code", env.verify(t, env.mfaToken(t), env.codes[1]), nil)
+
+	// после отключения TOTP старые коды недействительны
+	if err := env.svc.DisableTOTP(context.Background(), env.user.ID, env.codes[2]); err != nil {
+		t.Fatal(err)
+	}
+	res, err := env.svc.Login(context.Background(), "mfa@example.com", "password123", nil)
+	if err != nil || res.MFARequired {
+		t.Fatalf("login after disable: %+v, %v", res, err)
+	}
+}
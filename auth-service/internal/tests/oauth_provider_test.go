@@ -0,0 +1,446 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+	"github.com/cwrk-planet/auth-service/internal/transport/oauth"
+	"github.com/cwrk-planet/authz/pkg/authz"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+const (
+	wikiClientID     = "wiki"
+	wikiClientSecret = "wiki-secret"
+	wikiRedirect     = "https://wiki.cwrk.test/callback"
+	spaClientID      = "lms-spa"
+	spaRedirect      = "https://lms.cwrk.test/callback"
+)
+
+type oauthEnv struct {
+	*testEnv
+	srv     *httptest.Server
+	browser *http.Client // с куками, редиректы не проходит
+	grants  *memOAuthGrants
+}
+
+func newOAuthEnv(t *testing.T) *oauthEnv {
+	t.Helper()
+	env := &oauthEnv{testEnv: newTestEnv(t), grants: newMemOAuthGrants()}
+
+	mux := http.NewServeMux()
+	env.srv = httptest.NewServer(mux)
+	t.Cleanup(env.srv.Close)
+
+	secret := security.SHA256HexOfString(wikiClientSecret)
+	clients := &memOAuthClients{items: map[string]domain.OAuthClient{
+		wikiClientID: {ID: wikiClientID, SecretHash: &secret, Name: "Wiki", RedirectURIs: []string{wikiRedirect}, Scopes: []string{"openid", "email", "profile"}},
+		spaClientID:  {ID: spaClientID, Name: "LMS", RedirectURIs: []string{spaRedirect}, Scopes: []string{"openid", "email"}},
+	}}
+	if err := env.svc.SetOAuthProvider(clients, env.grants, service.OAuthProviderConfig{Issuer: env.srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	oauth.New(env.svc).Mount(mux)
+
+	jar, _ := cookiejar.New(nil)
+	env.browser = &http.Client{
+		Jar: jar,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return env
+}
+
+func (e *oauthEnv) wikiConfig(t *testing.T) (*oauth2.Config, *oidc.Provider) {
+	t.Helper()
+	p, err := oidc.NewProvider(context.Background(), e.srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &oauth2.Config{
+		ClientID:     wikiClientID,
+		ClientSecret: wikiClientSecret,
+		RedirectURL:  wikiRedirect,
+		Endpoint:     p.Endpoint(),
+		Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+	}, p
+}
+
+// get - GET authorize как браузер: 200 (страница) или 302 (редирект к клиенту)
+func (e *oauthEnv) get(t *testing.T, authURL string) *http.Response {
+	t.Helper()
+	resp, err := e.browser.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	return resp
+}
+
+// post - отправка формы со страницы authorize: параметры клиента + action и поля шага
+func (e *oauthEnv) post(t *testing.T, authURL string, fields url.Values) *http.Response {
+	t.Helper()
+	u, _ := url.Parse(authURL)
+	form := u.Query()
+	for k, v := range fields {
+		form[k] = v
+	}
+	resp, err := e.browser.PostForm(e.srv.URL+"/oauth/authorize", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	return resp
+}
+
+// login - вход на странице authorize и согласие; возвращает redirect к клиенту
+func (e *oauthEnv) login(t *testing.T, authURL, email string) *url.URL {
+	t.Helper()
+	if resp := e.get(t, authURL); resp.StatusCode != http.StatusOK {
+		t.Fatalf("authorize: want login page, got %d", resp.StatusCode)
+	}
+	resp := e.post(t, authURL, url.Values{"action": {"login"}, "email": {email}, "password": {"password123"}})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("login: want consent page, got %d", resp.StatusCode)
+	}
+	resp = e.post(t, authURL, url.Values{"action": {"consent"}})
+
+	return redirectTarget(t, resp)
+}
+
+func redirectTarget(t *testing.T, resp *http.Response) *url.URL {
+	t.Helper()
+	if resp.StatusCode != http.StatusFound && resp.StatusCode != http.StatusSeeOther {
+		t.Fatalf("want redirect, got %d", resp.StatusCode)
+	}
+	u, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u
+}
+
+func (e *oauthEnv) registerNamed(t *testing.T, email, name string) {
+	t.Helper()
+	if _, err := e.svc.Register(context.Background(), email, "password123", &name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOAuthProvider_CodeFlowWithPKCE(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.registerNamed(t, "ann@cwrk.test", "Ann")
+	cfg, provider := env.wikiConfig(t)
+	ctx := context.Background()
+
+	verifier := oauth2.GenerateVerifier()
+	authURL := cfg.AuthCodeURL("st-1", oidc.Nonce("n-1"), oauth2.S256ChallengeOption(verifier))
+	back := env.login(t, authURL, "ann@cwrk.test")
+	if back.Query().Get("state") != "st-1" || back.Query().Get("code") == "" {
+		t.Fatalf("unexpected redirect %s", back)
+	}
+
+	tok, err := cfg.Exchange(ctx, back.Query().Get("code"), oauth2.VerifierOption(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawID, _ := tok.Extra("id_token").(string)
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: wikiClientID}).Verify(ctx, rawID)
+	if err != nil {
+		t.Fatalf("id_token: %v", err)
+	}
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		DisplayName   string `json:"display_name"`
+		AuthTime      int64  `json:"auth_time"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+	if idToken.Nonce != "n-1" || claims.Email != "ann@cwrk.test" || claims.DisplayName != "Ann" || claims.AuthTime == 0 {
+		t.Fatalf("unexpected id_token claims: nonce=%q %+v", idToken.Nonce, claims)
+	}
+
+	info, err := provider.UserInfo(ctx, oauth2.StaticTokenSource(tok))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Subject != idToken.Subject || info.Email != "ann@cwrk.test" {
+		t.Fatalf("unexpected userinfo %+v", info)
+	}
+
+	// наш access-токен для API cwrkPlanet клиентскому не подходит и наоборот
+	if _, err := env.svc.UserIDFromAccessToken(tok.AccessToken); err == nil {
+		t.Fatal("client access token accepted as cwrkPlanet access token")
+	}
+}
+
+// ID token, access-токен клиента и SSO-кука подписаны ключом платформы. authz.Verifier не принимает их, даже
+// если iss/aud совпали бы с его настройками: у них другой token_use
+func TestOAuthProvider_TokensRejectedByPlatformVerifier(t *testing.T) {
+	env := newOAuthEnv(t)
+	u := env.register(t, "ann@cwrk.test")
+	cfg, _ := env.wikiConfig(t)
+
+	verifier := oauth2.GenerateVerifier()
+	back := env.login(t, cfg.AuthCodeURL("st-1", oauth2.S256ChallengeOption(verifier)), "ann@cwrk.test")
+	tok, err := cfg.Exchange(context.Background(), back.Query().Get("code"), oauth2.VerifierOption(verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawID, _ := tok.Extra("id_token").(string)
+	sso, err := env.svc.SSOToken(u.ID, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	platform := authz.NewVerifier(&env.key.PublicKey, "auth-test", "cwrk-test", 0)
+	sameAud := authz.NewVerifier(&env.key.PublicKey, env.srv.URL, wikiClientID, 0)
+	ssoAud := authz.NewVerifier(&env.key.PublicKey, env.srv.URL, "sso", 0)
+	for name, tc := range map[string]struct {
+		v     *authz.Verifier
+		token string
+	}{
+		"id token":                 {platform, rawID},
+		"id token, same iss/aud":   {sameAud, rawID},
+		"client access":            {platform, tok.AccessToken},
+		"client access, same aud":  {sameAud, tok.AccessToken},
+		"sso cookie":               {platform, sso},
+		"sso cookie, same iss/aud": {ssoAud, sso},
+	} {
+		if _, err := tc.v.Verify(tc.token); err != authz.ErrUnauthenticated {
+			t.Errorf("%s: want ErrUnauthenticated, got %v", name, err)
+		}
+	}
+
+	// и обратно: SSO-кука не сходит за access-токен клиента
+	if _, err := env.svc.UserInfo(context.Background(), sso); err == nil {
+		t.Fatal("sso cookie accepted by userinfo")
+	}
+}
+
+func TestOAuthProvider_SSOSkipsLoginAndConsent(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.register(t, "ann@cwrk.test")
+	cfg, _ := env.wikiConfig(t)
+
+	env.login(t, cfg.AuthCodeURL("st-1"), "ann@cwrk.test")
+
+	back := redirectTarget(t, env.get(t, cfg.AuthCodeURL("st-2")))
+	if back.Query().Get("code") == "" || back.Query().Get("state") != "st-2" {
+		t.Fatalf("want immediate code, got %s", back)
+	}
+
+	// prompt=login - спрашиваем пароль даже при живой куке
+	if resp := env.get(t, cfg.AuthCodeURL("st-3", oauth2.SetAuthURLParam("prompt", "login"))); resp.StatusCode != http.StatusOK {
+		t.Fatalf("prompt=login: want login page, got %d", resp.StatusCode)
+	}
+}
+
+func TestOAuthProvider_PromptNoneWithoutSession(t *testing.T) {
+	env := newOAuthEnv(t)
+	cfg, _ := env.wikiConfig(t)
+
+	back := redirectTarget(t, env.get(t, cfg.AuthCodeURL("st-1", oauth2.SetAuthURLParam("prompt", "none"))))
+	if back.Query().Get("error") != "login_required" || back.Query().Get("state") != "st-1" {
+		t.Fatalf("unexpected redirect %s", back)
+	}
+}
+
+func TestOAuthProvider_DenyConsent(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.register(t, "ann@cwrk.test")
+	cfg, _ := env.wikiConfig(t)
+	authURL := cfg.AuthCodeURL("st-1")
+
+	env.post(t, authURL, url.Values{"action": {"login"}, "email": {"ann@cwrk.test"}, "password": {"password123"}})
+	back := redirectTarget(t, env.post(t, authURL, url.Values{"action": {"deny"}}))
+	if back.Query().Get("error") != "access_denied" {
+		t.Fatalf("unexpected redirect %s", back)
+	}
+}
+
+func TestOAuthProvider_WrongPasswordShowsPage(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.register(t, "ann@cwrk.test")
+	cfg, _ := env.wikiConfig(t)
+
+	resp := env.post(t, cfg.AuthCodeURL("st-1"), url.Values{"action": {"login"}, "email": {"ann@cwrk.test"}, "password": {"wrong-password"}})
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Location") != "" {
+		t.Fatalf("want login page again, got %d", resp.StatusCode)
+	}
+	if len(env.browser.Jar.Cookies(mustParse(t, env.srv.URL+"/oauth/"))) != 0 {
+		t.Fatal("sso cookie set after failed login")
+	}
+}
+
+func TestOAuthProvider_UnregisteredRedirectNotFollowed(t *testing.T) {
+	env := newOAuthEnv(t)
+	cfg, _ := env.wikiConfig(t)
+	cfg.RedirectURL = "https://evil.test/callback"
+
+	resp := env.get(t, cfg.AuthCodeURL("st-1"))
+	if resp.StatusCode != http.StatusBadRequest || resp.Header.Get("Location") != "" {
+		t.Fatalf("want error page, got %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+}
+
+func TestOAuthProvider_PublicClientRequiresPKCE(t *testing.T) {
+	env := newOAuthEnv(t)
+	q := url.Values{
+		"client_id":     {spaClientID},
+		"redirect_uri":  {spaRedirect},
+		"response_type": {"code"},
+		"scope":         {"openid email"},
+		"state":         {"st-1"},
+	}
+
+	back := redirectTarget(t, env.get(t, env.srv.URL+"/oauth/authorize?"+q.Encode()))
+	if back.Query().Get("error") != "invalid_request" {
+		t.Fatalf("unexpected redirect %s", back)
+	}
+
+	// scope, которого у клиента нет
+	q.Set("code_challenge", "x")
+	q.Set("code_challenge_method", "S256")
+	q.Set("scope", "openid profile")
+	back = redirectTarget(t, env.get(t, env.srv.URL+"/oauth/authorize?"+q.Encode()))
+	if back.Query().Get("error") != "invalid_scope" {
+		t.Fatalf("unexpected redirect %s", back)
+	}
+}
+
+func TestOAuthProvider_CodeChecks(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.register(t, "ann@cwrk.test")
+	cfg, _ := env.wikiConfig(t)
+	ctx := context.Background()
+
+	verifier := oauth2.GenerateVerifier()
+	code := func(state string) string {
+		u := redirectTarget(t, env.get(t, cfg.AuthCodeURL(state, oauth2.S256ChallengeOption(verifier))))
+		return u.Query().Get("code")
+	}
+	env.login(t, cfg.AuthCodeURL("st-0"), "ann@cwrk.test")
+
+	// неверный code_verifier
+	if _, err := cfg.Exchange(ctx, code("st-1"), oauth2.VerifierOption(oauth2.GenerateVerifier())); !isOAuthError(err, "invalid_grant") {
+		t.Fatalf("wrong verifier: %v", err)
+	}
+	// без code_verifier
+	if _, err := cfg.Exchange(ctx, code("st-2")); !isOAuthError(err, "invalid_grant") {
+		t.Fatalf("missing verifier: %v", err)
+	}
+
+	// повторный обмен того же code
+	c := code("st-3")
+	if _, err := cfg.Exchange(ctx, c, oauth2.VerifierOption(verifier)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfg.Exchange(ctx, c, oauth2.VerifierOption(verifier)); !isOAuthError(err, "invalid_grant") {
+		t.Fatalf("code reuse: %v", err)
+	}
+
+	// redirect_uri при обмене должен совпасть с тем, что был в authorize
+	other := *cfg
+	other.RedirectURL = wikiRedirect + "?x=1"
+	if _, err := other.Exchange(ctx, code("st-4"), oauth2.VerifierOption(verifier)); !isOAuthError(err, "invalid_grant") {
+		t.Fatalf("redirect mismatch: %v", err)
+	}
+
+	// неверный секрет
+	bad := *cfg
+	bad.ClientSecret = "nope"
+	if _, err := bad.Exchange(ctx, code("st-5"), oauth2.VerifierOption(verifier)); !isOAuthError(err, "invalid_client") {
+		t.Fatalf("bad secret: %v", err)
+	}
+}
+
+func TestOAuthProvider_RefreshRotation(t *testing.T) {
+	env := newOAuthEnv(t)
+	env.register(t, "ann@cwrk.test")
+	cfg, provider := env.wikiConfig(t)
+	ctx := context.Background()
+
+	back := env.login(t, cfg.AuthCodeURL("st-1"), "ann@cwrk.test")
+	tok, err := cfg.Exchange(ctx, back.Query().Get("code"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	form := url.Values{"grant_type": {"refresh_token"}, "refresh_token": {tok.RefreshToken}}
+	res := env.tokenRequest(t, form)
+	if res["error"] != nil || res["refresh_token"] == tok.RefreshToken {
+		t.Fatalf("refresh: %v", res)
+	}
+	if _, err := provider.Verifier(&oidc.Config{ClientID: wikiClientID}).Verify(ctx, res["id_token"].(string)); err != nil {
+		t.Fatalf("refreshed id_token: %v", err)
+	}
+
+	// старый refresh-токен после ротации недействителен
+	if res := env.tokenRequest(t, form); res["error"] != "invalid_grant" {
+		t.Fatalf("reused refresh token: %v", res)
+	}
+
+	// расширить scopes при обновлении нельзя
+	form = url.Values{"grant_type": {"refresh_token"}, "refresh_token": {res["refresh_token"].(string)}, "scope": {"openid email phone"}}
+	if res := env.tokenRequest(t, form); res["error"] != "invalid_scope" {
+		t.Fatalf("widened scope: %v", res)
+	}
+}
+
+func (e *oauthEnv) tokenRequest(t *testing.T, form url.Values) map[string]any {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, e.srv.URL+"/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(wikiClientID, wikiClientSecret)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Cache-Control") != "no-store" {
+		t.Fatal("token response must not be cached")
+	}
+
+	var out map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func isOAuthError(err error, code string) bool {
+	re, ok := err.(*oauth2.RetrieveError)
+	return ok && re.ErrorCode == code
+}
+
+func mustParse(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return u
+}
@@ -0,0 +1,439 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	testClientID     = "cwrk-client"
+	testClientSecret = "cwrk-secret"
+	testRedirectURL  = "https://cwrk.test/oidc/callback"
+)
+
+// mockOIDC - минимальный OIDC-провайдер: discovery, JWKS и token endpoint с PKCE.
+// Шаг "пользователь вошел у провайдера" - authorize(): выдает code для параметров из authorization_url
+type mockOIDC struct {
+	srv *httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+type mockUser struct {
+	sub           string
+	email         string
+	emailVerified bool
+	name          string
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &mockOIDC{key: key, codes: map[string]mockGrant{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                m.srv.URL,
+			"authorization_endpoint":                m.srv.URL + "/authorize",
+			"token_endpoint":                        m.srv.URL + "/token",
+			"jwks_uri":                              m.srv.URL + "/jwks",
+			"response_types_supported":              []string{"code"},
+			"subject_types_supported":               []string{"public"},
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+			"code_challenge_methods_supported":      []string{"S256"},
+		})
+	})
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("POST /token", m.token)
+	m.srv = httptest.NewServer(mux)
+	t.Cleanup(m.srv.Close)
+
+	return m
+}
+
+func (m *mockOIDC) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if id != testClientID || secret != testClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	m.mu.Lock()
+	g, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, g.claims)
+	tok.Header["kid"] = "k1"
+	idToken, err := tok.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "provider-access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+// authorize - пользователь вошел у провайдера и согласился: code для redirect
+func (m *mockOIDC) authorize(t *testing.T, authURL string, u mockUser) string {
+	t.Helper()
+	q := m.authParams(t, authURL)
+	now := time.Now()
+
+	return m.grant(q.Get("code_challenge"), jwt.MapClaims{
+		"iss":            m.srv.URL,
+		"aud":            testClientID,
+		"sub":            u.sub,
+		"email":          u.email,
+		"email_verified": u.emailVerified,
+		"name":           u.name,
+		"nonce":          q.Get("nonce"),
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	})
+}
+
+func (m *mockOIDC) grant(challenge string, claims jwt.MapClaims) string {
+	code, _ := security.RandomStringURLSafe(16)
+	m.mu.Lock()
+	m.codes[code] = mockGrant{challenge: challenge, claims: claims}
+	m.mu.Unlock()
+
+	return code
+}
+
+func (m *mockOIDC) authParams(t *testing.T, authURL string) url.Values {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("client_id") != testClientID || q.Get("redirect_uri") != testRedirectURL || q.Get("code_challenge_method") != "S256" {
+		t.Fatalf("unexpected authorization url: %s", authURL)
+	}
+
+	return q
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newOIDCEnv(t *testing.T) (*testEnv, *mockOIDC, *memIdentities) {
+	t.Helper()
+	env := newTestEnv(t)
+	m := newMockOIDC(t)
+	idents := &memIdentities{}
+	err := env.svc.SetOIDC(idents, newMemOIDCStates(), service.OIDCConfig{
+		Providers: []service.OIDCProviderConfig{{
+			Name:         "mock",
+			Issuer:       m.srv.URL,
+			ClientID:     testClientID,
+			ClientSecret: testClientSecret,
+			RedirectURL:  testRedirectURL,
+		}},
+		HTTPClient: m.srv.Client(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env, m, idents
+}
+
+var ann = mockUser{sub: "ann-sub", email: "ann@example.com", emailVerified: true, name: "Ann"}
+
+func oidcLogin(t *testing.T, env *testEnv, m *mockOIDC, u mockUser) (*service.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	start, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env.svc.FinishOIDCLogin(ctx, "mock", start.State, m.authorize(t, start.AuthURL, u), nil)
+}
+
+func TestOIDC_SignUpAndLogin(t *testing.T) {
+	env, m, idents := newOIDCEnv(t)
+
+	res, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" || res.User.Email != "ann@example.com" || !res.User.EmailVerified {
+		t.Fatalf("first login = %+v", res)
+	}
+	if len(idents.items) != 1 || idents.items[0].Subject != "ann-sub" || idents.items[0].UserID != res.User.ID {
+		t.Fatalf("identities = %+v", idents.items)
+	}
+	// пароля нет: войти по паролю нельзя
+	if _, err := env.svc.Login(context.Background(), "ann@example.com", "", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("password login err = %v", err)
+	}
+
+	again, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.User.ID != res.User.ID || len(env.users.items) != 1 {
+		t.Fatalf("second login created another user: %d != %d", again.User.ID, res.User.ID)
+	}
+}
+
+func TestOIDC_StateIsSingleUse(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	start, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", "forged", m.authorize(t, start.AuthURL, ann), nil); !errors.Is(err, errs.ErrInvalidOIDCState) {
+		t.Fatalf("forged state err = %v", err)
+	}
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, m.authorize(t, start.AuthURL, ann), nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, m.authorize(t, start.AuthURL, ann), nil); !errors.Is(err, errs.ErrInvalidOIDCState) {
+		t.Fatalf("reused state err = %v", err)
+	}
+}
+
+func TestOIDC_NonceMismatch(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	start, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := m.authParams(t, start.AuthURL)
+	code := m.grant(q.Get("code_challenge"), jwt.MapClaims{
+		"iss": m.srv.URL, "aud": testClientID, "sub": "ann-sub",
+		"email": "ann@example.com", "email_verified": true,
+		"nonce": "replayed", "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, code, nil); !errors.Is(err, errs.ErrOIDCFailed) {
+		t.Fatalf("err = %v, want ErrOIDCFailed", err)
+	}
+}
+
+func TestOIDC_PKCE(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	start, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// перехваченный code, выданный под чужой code_challenge
+	other, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := m.authorize(t, other.AuthURL, ann)
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, code, nil); !errors.Is(err, errs.ErrOIDCFailed) {
+		t.Fatalf("err = %v, want ErrOIDCFailed", err)
+	}
+}
+
+func TestOIDC_WrongAudience(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	start, err := env.svc.BeginOIDCLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := m.authParams(t, start.AuthURL)
+	code := m.grant(q.Get("code_challenge"), jwt.MapClaims{
+		"iss": m.srv.URL, "aud": "someone-else", "sub": "ann-sub",
+		"email": "ann@example.com", "email_verified": true,
+		"nonce": q.Get("nonce"), "iat": time.Now().Unix(), "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, code, nil); !errors.Is(err, errs.ErrOIDCFailed) {
+		t.Fatalf("err = %v, want ErrOIDCFailed", err)
+	}
+}
+
+func TestOIDC_UnverifiedEmail(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	u := ann
+	u.emailVerified = false
+	if _, err := oidcLogin(t, env, m, u); !errors.Is(err, errs.ErrOIDCEmailNotVerified) {
+		t.Fatalf("err = %v, want ErrOIDCEmailNotVerified", err)
+	}
+}
+
+func TestOIDC_LinkExistingAccount(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+	local := env.register(t, "ann@example.com")
+
+	// email уже занят: сам не связываем
+	if _, err := oidcLogin(t, env, m, ann); !errors.Is(err, errs.ErrAccountLinkRequired) {
+		t.Fatalf("err = %v, want ErrAccountLinkRequired", err)
+	}
+
+	start, err := env.svc.BeginOIDCLink(ctx, local.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// state привязки нельзя использовать для входа
+	if _, err := env.svc.FinishOIDCLogin(ctx, "mock", start.State, m.authorize(t, start.AuthURL, ann), nil); !errors.Is(err, errs.ErrInvalidOIDCState) {
+		t.Fatalf("err = %v, want ErrInvalidOIDCState", err)
+	}
+
+	start, err = env.svc.BeginOIDCLink(ctx, local.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ident, err := env.svc.FinishOIDCLink(ctx, local.ID, "mock", start.State, m.authorize(t, start.AuthURL, ann))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ident.UserID != local.ID || ident.Subject != "ann-sub" {
+		t.Fatalf("identity = %+v", ident)
+	}
+
+	res, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.User.ID != local.ID {
+		t.Fatalf("logged in as %d, want %d", res.User.ID, local.ID)
+	}
+}
+
+func TestOIDC_LinkFinishedByAnotherUser(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+	attacker := env.register(t, "mallory@example.com")
+	victim := env.register(t, "ann@example.com")
+
+	start, err := env.svc.BeginOIDCLink(ctx, attacker.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.FinishOIDCLink(ctx, victim.ID, "mock", start.State, m.authorize(t, start.AuthURL, ann)); !errors.Is(err, errs.ErrInvalidOIDCState) {
+		t.Fatalf("err = %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestOIDC_IdentityLinkedElsewhere(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	owner, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob := env.register(t, "bob@example.com")
+	start, err := env.svc.BeginOIDCLink(ctx, bob.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.svc.FinishOIDCLink(ctx, bob.ID, "mock", start.State, m.authorize(t, start.AuthURL, ann)); !errors.Is(err, errs.ErrIdentityLinked) {
+		t.Fatalf("err = %v, want ErrIdentityLinked", err)
+	}
+	if idents, _ := env.svc.ListIdentities(ctx, owner.User.ID); len(idents) != 1 {
+		t.Fatalf("owner identities = %+v", idents)
+	}
+}
+
+func TestOIDC_Unlink(t *testing.T) {
+	env, m, _ := newOIDCEnv(t)
+	ctx := context.Background()
+
+	// единственный способ входа не отвязываем
+	social, err := oidcLogin(t, env, m, ann)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.svc.UnlinkIdentity(ctx, social.User.ID, "mock"); !errors.Is(err, errs.ErrLastLoginMethod) {
+		t.Fatalf("err = %v, want ErrLastLoginMethod", err)
+	}
+
+	// с паролем - можно
+	bob := env.register(t, "bob@example.com")
+	start, err := env.svc.BeginOIDCLink(ctx, bob.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bobSocial := mockUser{sub: "bob-sub", email: "bob@example.com", emailVerified: true}
+	if _, err := env.svc.FinishOIDCLink(ctx, bob.ID, "mock", start.State, m.authorize(t, start.AuthURL, bobSocial)); err != nil {
+		t.Fatal(err)
+	}
+	if err := env.svc.UnlinkIdentity(ctx, bob.ID, "mock"); err != nil {
+		t.Fatal(err)
+	}
+	if idents, _ := env.svc.ListIdentities(ctx, bob.ID); len(idents) != 0 {
+		t.Fatalf("identities after unlink = %+v", idents)
+	}
+}
+
+func TestOIDC_UnknownProvider(t *testing.T) {
+	env, _, _ := newOIDCEnv(t)
+	if _, err := env.svc.BeginOIDCLogin(context.Background(), "nope"); !errors.Is(err, errs.ErrUnknownProvider) {
+		t.Fatalf("err = %v, want ErrUnknownProvider", err)
+	}
+}
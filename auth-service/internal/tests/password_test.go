@@ -0,0 +1,239 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/security"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestPassword_Argon2idEncoded(t *testing.T) {
+	cfg := testPasswordConfig()
+	hash, err := security.HashPassword("correct horse", &cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Fatalf("hash = %q, want PHC argon2id", hash)
+	}
+	if err := security.ComparePassword(hash, "correct horse"); err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if err := security.ComparePassword(hash, "correct horsE"); err == nil {
+		t.Fatal("wrong password accepted")
+	}
+	if security.NeedsRehash(hash, &cfg) {
+		t.Fatal("fresh hash needs rehash")
+	}
+
+	stronger := cfg
+	stronger.Argon2.Iterations = 2
+	if !security.NeedsRehash(hash, &stronger) {
+		t.Fatal("changed argon2 params must trigger rehash")
+	}
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := security.ComparePassword(string(legacy), "correct horse"); err != nil {
+		t.Fatalf("bcrypt compare: %v", err)
+	}
+	if !security.NeedsRehash(string(legacy), &cfg) {
+		t.Fatal("bcrypt hash must be upgraded to argon2id")
+	}
+}
+
+func TestPassword_BcryptTooLong(t *testing.T) {
+	cfg := security.PasswordConfig{Algorithm: security.AlgBcrypt, BcryptCost: 4, MaxLength: 200}
+	long := strings.Repeat("x", 73)
+	if _, err := security.HashPassword(long, &cfg); !errors.Is(err, errs.ErrPasswordTooLong) {
+		t.Fatalf("hash err = %v, want ErrPasswordTooLong", err)
+	}
+	if err := security.CheckPasswordPolicy(context.Background(), long, "", &cfg); !errors.Is(err, errs.ErrPasswordTooLong) {
+		t.Fatalf("policy err = %v, want ErrPasswordTooLong", err)
+	}
+}
+
+func TestLogin_UpgradesLegacyHash(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "legacy@example.com")
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password123"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.users.UpdatePasswordHash(ctx, u.ID, string(legacy), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := env.svc.Login(ctx, "legacy@example.com", "password123", nil); err != nil {
+		t.Fatal(err)
+	}
+	got, err := env.users.GetByEmail(ctx, "legacy@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(got.PasswordHash, "$argon2id$") {
+		t.Fatalf("hash after login = %q, want argon2id", got.PasswordHash)
+	}
+
+	// новый хеш рабочий, неверный пароль его не трогает
+	if _, err := env.svc.Login(ctx, "legacy@example.com", "wrong-password", nil); !errors.Is(err, errs.ErrInvalidCredentials) {
+		t.Fatalf("wrong password: err = %v", err)
+	}
+	if _, err := env.svc.Login(ctx, "legacy@example.com", "password123", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogin_HashSlotsExhausted(t *testing.T) {
+	env := newTestEnv(t)
+	env.register(t, "busy@example.com")
+
+	// один слот и заметно долгий argon2id: из одновременных входов часть упрется в лимит
+	pass := testPasswordConfig()
+	pass.Argon2 = security.Argon2Params{Memory: 16 * 1024, Iterations: 2, Parallelism: 1}
+	pass.MaxConcurrent = 1
+	svc := newClockedService(env, pass, time.Now)
+
+	const workers = 8
+	var busy, ok int
+	for round := 0; round < 5 && (busy == 0 || ok == 0); round++ {
+		start := make(chan struct{})
+		results := make(chan error, workers)
+		for range workers {
+			go func() {
+				<-start
+				_, err := svc.Login(context.Background(), "busy@example.com", "password123", nil)
+				results <- err
+			}()
+		}
+		close(start)
+		for range workers {
+			switch err := <-results; {
+			case err == nil:
+				ok++
+			case errors.Is(err, errs.ErrPasswordHashBusy):
+				busy++
+			default:
+				t.Fatalf("login: %v", err)
+			}
+		}
+	}
+	if busy == 0 || ok == 0 {
+		t.Fatalf("ok = %d, busy = %d, want both > 0", ok, busy)
+	}
+
+	// слот освободился - вход снова проходит
+	if _, err := svc.Login(context.Background(), "busy@example.com", "password123", nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	breached := []string{"password123", "qwertyuiop", "letmein2024"}
+	list := writeHashList(t, breached, 500)
+	defer list.Close()
+
+	cfg := testPasswordConfig()
+	cfg.MaxLength = 20
+	cfg.Breached = list
+	env := newTestEnv(t)
+	svc := service.NewAuthService(env.users, env.sessions, nil, 24*time.Hour, cfg, time.Now)
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		email, password string
+		want            error
+	}{
+		{"a@example.com", "short", errs.ErrPasswordTooShort},
+		{"a@example.com", strings.Repeat("ы", 21), errs.ErrPasswordTooLong},
+		{"Same@Example.com", "same@example.com", errs.ErrPasswordIsEmail},
+		{"a@example.com", "qwertyuiop", errs.ErrPasswordBreached},
+		{"a@example.com", "letmein2024", errs.ErrPasswordBreached},
+	} {
+		if _, err := svc.Register(ctx, tc.email, tc.password, nil); !errors.Is(err, tc.want) {
+			t.Fatalf("register(%q): err = %v, want %v", tc.password, err, tc.want)
+		}
+	}
+
+	// 20 символов кириллицей - 40 байт, но длина считается в символах
+	if err := security.CheckPasswordPolicy(ctx, strings.Repeat("ы", 20), "a@example.com", &cfg); err != nil {
+		t.Fatalf("20 runes: %v", err)
+	}
+	if err := security.CheckPasswordPolicy(ctx, "not-in-the-list", "a@example.com", &cfg); err != nil {
+		t.Fatalf("clean password: %v", err)
+	}
+}
+
+func TestHashListFile_Range(t *testing.T) {
+	list := writeHashList(t, nil, 2000)
+	defer list.Close()
+
+	// файл - "<hash>:<count>" по возрастанию; каждый хеш должен находиться по своему префиксу
+	data, err := os.ReadFile(list.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\r\n") {
+		h, _, _ := strings.Cut(line, ":")
+		got, err := list.Range(context.Background(), strings.ToLower(h[:5]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(got, h[5:]) {
+			t.Fatalf("%s not found in range %v", h, got)
+		}
+	}
+
+	got, err := list.Range(context.Background(), "FFFFF")
+	if err != nil || len(got) != 0 {
+		t.Fatalf("FFFFF: %v %v", got, err)
+	}
+}
+
+type hashList struct {
+	*security.HashListFile
+	Path string
+}
+
+// writeHashList - passwords и n случайных хешей, отсортированные, с CRLF как в выгрузке Pwned Passwords
+func writeHashList(t *testing.T, passwords []string, n int) hashList {
+	t.Helper()
+	var lines []string
+	for _, p := range passwords {
+		sum := sha1.Sum([]byte(p))
+		lines = append(lines, strings.ToUpper(hex.EncodeToString(sum[:]))+":42")
+	}
+	for i := 0; i < n; i++ {
+		sum := sha1.Sum([]byte(fmt.Sprintf("filler-%d", i)))
+		lines = append(lines, fmt.Sprintf("%s:%d", strings.ToUpper(hex.EncodeToString(sum[:])), i+1))
+	}
+	slices.Sort(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := security.OpenHashListFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hashList{HashListFile: f, Path: path}
+}
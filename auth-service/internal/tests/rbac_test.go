@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
+)
+
+// access-токены auth-service должен принимать authz.Verifier в gateway и room-service
+func (e *testEnv) principal(t *testing.T, access string) *authz.Principal {
+	t.Helper()
+	p, err := authz.NewVerifier(&e.key.PublicKey, "auth-test", "cwrk-test", 0).Verify(access)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestRBAC_DefaultRoleInAccessToken(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+
+	res, err := env.svc.Register(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := env.principal(t, res.AccessToken)
+	if p.UserID != int64(res.User.ID) || !p.HasRole("user") || !p.HasScope(authz.ScopeRoomsCreate) {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+	if err := p.Check(authz.ScopeAdminUsers); err == nil {
+		t.Fatal("regular user has admin:users")
+	}
+}
+
+func TestRBAC_RoleChangeVisibleAfterRefresh(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	u := env.register(t, "ann@cwrk.test")
+
+	login, err := env.svc.Login(ctx, "ann@cwrk.test", "password123", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.roles.Assign(ctx, u.ID, "admin", u.CreatedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	ref, err := env.svc.Refresh(ctx, login.RefreshToken, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := env.principal(t, ref.AccessToken)
+	if !p.HasRole("admin") || !p.HasRole("user") || p.Check(authz.ScopeAdminUsers, authz.ScopeRoomsCreate) != nil {
+		t.Fatalf("unexpected principal %+v", p)
+	}
+	if len(p.Scopes) != 2 {
+		t.Fatalf("scopes must be deduplicated: %v", p.Scopes)
+	}
+}
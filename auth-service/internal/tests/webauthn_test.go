@@ -0,0 +1,308 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+const (
+	testRPID   = "cwrk.test"
+	testOrigin = "https://cwrk.test"
+)
+
+// softAuthenticator - программный аутентификатор: один ES256-ключ, attestation "none"
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	credID    []byte
+	userID    []byte
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credID := make([]byte, 16)
+	_, _ = rand.Read(credID)
+
+	return &softAuthenticator{key: key, credID: credID}
+}
+
+var b64 = base64.RawURLEncoding
+
+// create - ответ navigator.credentials.create() на options из BeginPasskeyRegistration
+func (a *softAuthenticator) create(t *testing.T, options []byte) []byte {
+	t.Helper()
+	var opts struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+			User      struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		t.Fatal(err)
+	}
+	userID, err := b64.DecodeString(opts.PublicKey.User.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.userID = userID
+
+	clientData := clientDataJSON("webauthn.create", opts.PublicKey.Challenge)
+
+	// authData: rpIdHash | flags UP+UV+AT | counter | aaguid | len(credId) | credId | COSE key
+	rpHash := sha256.Sum256([]byte(testRPID))
+	authData := append([]byte{}, rpHash[:]...)
+	authData = append(authData, 0x45)
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.credID)))
+	authData = append(authData, a.credID...)
+	authData = append(authData, a.coseKey()...)
+
+	// {"fmt": "none", "attStmt": {}, "authData": ...}
+	att := []byte{0xa3}
+	att = append(att, cborText("fmt")...)
+	att = append(att, cborText("none")...)
+	att = append(att, cborText("attStmt")...)
+	att = append(att, 0xa0)
+	att = append(att, cborText("authData")...)
+	att = append(att, cborBytes(authData)...)
+
+	return mustJSON(t, map[string]any{
+		"id":    b64.EncodeToString(a.credID),
+		"rawId": b64.EncodeToString(a.credID),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(clientData),
+			"attestationObject": b64.EncodeToString(att),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// get - ответ navigator.credentials.get() на options из BeginPasskeyLogin
+func (a *softAuthenticator) get(t *testing.T, options []byte) []byte {
+	t.Helper()
+	var opts struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	if err := json.Unmarshal(options, &opts); err != nil {
+		t.Fatal(err)
+	}
+	clientData := clientDataJSON("webauthn.get", opts.PublicKey.Challenge)
+
+	rpHash := sha256.Sum256([]byte(testRPID))
+	authData := append([]byte{}, rpHash[:]...)
+	authData = append(authData, 0x05) // UP+UV
+	authData = binary.BigEndian.AppendUint32(authData, a.signCount)
+
+	cdHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), cdHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return mustJSON(t, map[string]any{
+		"id":    b64.EncodeToString(a.credID),
+		"rawId": b64.EncodeToString(a.credID),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    b64.EncodeToString(clientData),
+			"authenticatorData": b64.EncodeToString(authData),
+			"signature":         b64.EncodeToString(sig),
+			"userHandle":        b64.EncodeToString(a.userID),
+		},
+	})
+}
+
+// coseKey - EC2 P-256 ES256: {1: 2, 3: -7, -1: 1, -2: x, -3: y}
+func (a *softAuthenticator) coseKey() []byte {
+	x := a.key.PublicKey.X.FillBytes(make([]byte, 32))
+	y := a.key.PublicKey.Y.FillBytes(make([]byte, 32))
+	out := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21}
+	out = append(out, cborBytes(x)...)
+	out = append(out, 0x22)
+
+	return append(out, cborBytes(y)...)
+}
+
+func clientDataJSON(typ, challenge string) []byte {
+	b, _ := json.Marshal(map[string]any{"type": typ, "challenge": challenge, "origin": testOrigin, "crossOrigin": false})
+	return b
+}
+
+func cborHead(major byte, n int) []byte {
+	switch {
+	case n < 24:
+		return []byte{major<<5 | byte(n)}
+	case n < 256:
+		return []byte{major<<5 | 24, byte(n)}
+	default:
+		return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+	}
+}
+
+func cborBytes(b []byte) []byte { return append(cborHead(2, len(b)), b...) }
+func cborText(s string) []byte  { return append(cborHead(3, len(s)), s...) }
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+func newWebAuthnEnv(t *testing.T) (*testEnv, *memPasskeys) {
+	t.Helper()
+	env := newTestEnv(t)
+	passkeys := &memPasskeys{}
+	err := env.svc.SetWebAuthn(passkeys, newMemCeremonies(), service.WebAuthnConfig{
+		RPID:      testRPID,
+		RPOrigins: []string{testOrigin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env, passkeys
+}
+
+func registerPasskey(t *testing.T, env *testEnv, a *softAuthenticator, email string) {
+	t.Helper()
+	ctx := context.Background()
+	u := env.register(t, email)
+
+	id, options, err := env.svc.BeginPasskeyRegistration(ctx, u.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := env.svc.FinishPasskeyRegistration(ctx, u.ID, id, "Laptop", a.create(t, options))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "Laptop" || p.UserID != u.ID {
+		t.Fatalf("passkey = %+v", p)
+	}
+}
+
+func passkeyLogin(t *testing.T, env *testEnv, a *softAuthenticator) (*service.LoginResult, error) {
+	t.Helper()
+	ctx := context.Background()
+	id, options, err := env.svc.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return env.svc.FinishPasskeyLogin(ctx, id, a.get(t, options), nil)
+}
+
+func TestPasskey_RegisterAndLogin(t *testing.T) {
+	env, passkeys := newWebAuthnEnv(t)
+	a := newSoftAuthenticator(t)
+	registerPasskey(t, env, a, "ann@example.com")
+
+	a.signCount = 1
+	res, err := passkeyLogin(t, env, a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.AccessToken == "" || res.RefreshToken == "" || res.User.Email != "ann@example.com" {
+		t.Fatalf("login result = %+v", res)
+	}
+	uid, err := env.svc.UserIDFromAccessToken(res.AccessToken)
+	if err != nil || uid != res.User.ID {
+		t.Fatalf("access token subject = %v, %v", uid, err)
+	}
+	if p := passkeys.items[0]; p.SignCount != 1 || p.LastUsedAt == nil {
+		t.Fatalf("usage not saved: %+v", p)
+	}
+}
+
+func TestPasskey_SignCountRegression(t *testing.T) {
+	env, _ := newWebAuthnEnv(t)
+	a := newSoftAuthenticator(t)
+	registerPasskey(t, env, a, "ann@example.com")
+
+	a.signCount = 5
+	if _, err := passkeyLogin(t, env, a); err != nil {
+		t.Fatal(err)
+	}
+	// копия ключа со старым счетчиком
+	a.signCount = 3
+	if _, err := passkeyLogin(t, env, a); !errors.Is(err, errs.ErrWebAuthnFailed) {
+		t.Fatalf("err = %v, want ErrWebAuthnFailed", err)
+	}
+}
+
+func TestPasskey_Revoked(t *testing.T) {
+	env, passkeys := newWebAuthnEnv(t)
+	a := newSoftAuthenticator(t)
+	registerPasskey(t, env, a, "ann@example.com")
+
+	p := passkeys.items[0]
+	if err := env.svc.RevokePasskey(context.Background(), p.UserID, p.ID); err != nil {
+		t.Fatal(err)
+	}
+	a.signCount = 1
+	if _, err := passkeyLogin(t, env, a); !errors.Is(err, errs.ErrWebAuthnFailed) {
+		t.Fatalf("err = %v, want ErrWebAuthnFailed", err)
+	}
+}
+
+func TestPasskey_CeremonyIsSingleUse(t *testing.T) {
+	env, _ := newWebAuthnEnv(t)
+	a := newSoftAuthenticator(t)
+	registerPasskey(t, env, a, "ann@example.com")
+
+	ctx := context.Background()
+	id, options, err := env.svc.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.signCount = 1
+	if _, err := env.svc.FinishPasskeyLogin(ctx, id, a.get(t, options), nil); err != nil {
+		t.Fatal(err)
+	}
+	a.signCount = 2
+	if _, err := env.svc.FinishPasskeyLogin(ctx, id, a.get(t, options), nil); !errors.Is(err, errs.ErrInvalidCeremony) {
+		t.Fatalf("err = %v, want ErrInvalidCeremony", err)
+	}
+}
+
+func TestPasskey_RegistrationCeremonyBoundToUser(t *testing.T) {
+	env, _ := newWebAuthnEnv(t)
+	ctx := context.Background()
+	ann := env.register(t, "ann@example.com")
+	bob := env.register(t, "bob@example.com")
+
+	id, options, err := env.svc.BeginPasskeyRegistration(ctx, ann.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := newSoftAuthenticator(t)
+	if _, err := env.svc.FinishPasskeyRegistration(ctx, bob.ID, id, "", a.create(t, options)); !errors.Is(err, errs.ErrInvalidCeremony) {
+		t.Fatalf("err = %v, want ErrInvalidCeremony", err)
+	}
+}
@@ -34,10 +34,12 @@ func New(addr string, svc *service.AuthService) (*Server, error) {
 			recoveryUnaryInterceptor(),
 			requestIDInterceptor(),
 			loggingUnaryInterceptor(),
+			handler.LoginMetaInterceptor(),
 		),
 	)
 
 	authv1.RegisterAuthServiceServer(gs, handler.NewAuthHandler(svc))
+	authv1.RegisterAdminServiceServer(gs, handler.NewAdminHandler(svc))
 
 	return &Server{
 		addr: addr,
@@ -177,15 +179,26 @@ func requestIDInterceptor() grpc.UnaryServerInterceptor {
 */
 
 var redactedKeys = map[string]struct{}{
-	"password":      {},
-	"password_hash": {},
-	"refresh":       {},
-	"refresh_token": {},
-	"access":        {},
-	"access_token":  {},
-	"token":         {},
-	"jwt":           {},
-	"authorization": {},
+	"password":          {},
+	"password_hash":     {},
+	"current_password":  {},
+	"new_password":      {},
+	"mfa_code":          {},
+	"refresh":           {},
+	"refresh_token":     {},
+	"access":            {},
+	"access_token":      {},
+	"token":             {},
+	"jwt":               {},
+	"authorization":     {},
+	"code":              {},
+	"mfa_token":         {},
+	"secret":            {},
+	"otpauth_url":       {},
+	"qr_png":            {},
+	"recovery_codes":    {},
+	"state":             {},
+	"authorization_url": {},
 }
 
 // marshalRedacted JSON с редактированием чувствительных полей
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+)
+
+// DeleteAccount: пароль (+ mfa_code) или passkey - resp: когда аккаунт будет удален
+func (h *AuthHandler) DeleteAccount(ctx context.Context, req *authv1.DeleteAccountRequest) (*authv1.DeleteAccountResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	at, err := h.svc.RequestAccountDeletion(ctx, uid, service.Reauth{
+		Password:          req.GetPassword(),
+		MFACode:           req.GetMfaCode(),
+		PasskeyCeremonyID: strings.TrimSpace(req.GetPasskeyCeremonyId()),
+		PasskeyCredential: []byte(req.GetPasskeyCredentialJson()),
+	}, extractLoginMeta(ctx))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.DeleteAccountResponse{DeletionScheduledAt: at.Unix()}, nil
+}
+
+func (h *AuthHandler) CancelAccountDeletion(ctx context.Context, _ *authv1.CancelAccountDeletionRequest) (*authv1.CancelAccountDeletionResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.svc.CancelAccountDeletion(ctx, uid, extractLoginMeta(ctx)); err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.CancelAccountDeletionResponse{}, nil
+}
+
+// ExportMyData: профиль и активные сессии; остальное (комнаты, сообщения) отдает room-service
+func (h *AuthHandler) ExportMyData(ctx context.Context, _ *authv1.ExportMyDataRequest) (*authv1.ExportMyDataResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := h.svc.PersonalData(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	out := &authv1.ExportMyDataResponse{User: toUserPB(data.User), Sessions: make([]*authv1.Session, 0, len(data.Sessions))}
+	for i := range data.Sessions {
+		out.Sessions = append(out.Sessions, toSessionPB(&data.Sessions[i]))
+	}
+
+	return out, nil
+}
+
+func toSessionPB(s *domain.Session) *authv1.Session {
+	out := &authv1.Session{
+		Id:        int64(s.ID),
+		CreatedAt: s.CreatedAt.Unix(),
+		UpdatedAt: s.UpdatedAt.Unix(),
+		ExpiresAt: s.ExpiresAt.Unix(),
+	}
+	if s.UserAgent != nil {
+		out.UserAgent = *s.UserAgent
+	}
+	if s.IP != nil {
+		out.Ip = s.IP.String()
+	}
+
+	return out
+}
@@ -0,0 +1,288 @@
+package handler
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/service"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type AdminHandler struct {
+	authv1.UnimplementedAdminServiceServer
+	svc *service.AuthService
+}
+
+func NewAdminHandler(svc *service.AuthService) *AdminHandler {
+	return &AdminHandler{svc: svc}
+}
+
+// SearchUsers: подстрока email или имени, постранично по id
+func (h *AdminHandler) SearchUsers(ctx context.Context, req *authv1.SearchUsersRequest) (*authv1.SearchUsersResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	users, next, err := h.svc.AdminSearchUsers(ctx, actor, strings.TrimSpace(req.GetQuery()), domain.UserID(req.GetCursor()), int(req.GetLimit()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	out := make([]*authv1.AdminUser, 0, len(users))
+	for i := range users {
+		out = append(out, toAdminUserPB(&users[i]))
+	}
+
+	return &authv1.SearchUsersResponse{Users: out, NextCursor: int64(next)}, nil
+}
+
+func (h *AdminHandler) GetUser(ctx context.Context, req *authv1.GetUserRequest) (*authv1.AdminUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	u, err := h.svc.AdminGetUser(ctx, actor, domain.UserID(req.GetUserId()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.AdminUserResponse{User: toAdminUserPB(u)}, nil
+}
+
+// DisableUser: вход и refresh закрыты, сессии завершены
+func (h *AdminHandler) DisableUser(ctx context.Context, req *authv1.DisableUserRequest) (*authv1.AdminUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	u, err := h.svc.AdminDisableUser(ctx, actor, domain.UserID(req.GetUserId()), req.GetReason())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.AdminUserResponse{User: toAdminUserPB(u)}, nil
+}
+
+func (h *AdminHandler) EnableUser(ctx context.Context, req *authv1.EnableUserRequest) (*authv1.AdminUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	u, err := h.svc.AdminEnableUser(ctx, actor, domain.UserID(req.GetUserId()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.AdminUserResponse{User: toAdminUserPB(u)}, nil
+}
+
+func (h *AdminHandler) ForceLogout(ctx context.Context, req *authv1.ForceLogoutRequest) (*authv1.ForceLogoutResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	n, err := h.svc.AdminForceLogout(ctx, actor, domain.UserID(req.GetUserId()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.ForceLogoutResponse{RevokedSessions: n}, nil
+}
+
+func (h *AdminHandler) ForcePasswordReset(ctx context.Context, req *authv1.ForcePasswordResetRequest) (*authv1.AdminUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	u, err := h.svc.AdminForcePasswordReset(ctx, actor, domain.UserID(req.GetUserId()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.AdminUserResponse{User: toAdminUserPB(u)}, nil
+}
+
+// SetUserRoles: пустой список снимает все роли
+func (h *AdminHandler) SetUserRoles(ctx context.Context, req *authv1.SetUserRolesRequest) (*authv1.AdminUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	u, err := h.svc.AdminSetRoles(ctx, actor, domain.UserID(req.GetUserId()), req.GetRoles())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.AdminUserResponse{User: toAdminUserPB(u)}, nil
+}
+
+func (h *AdminHandler) ListRoles(ctx context.Context, req *authv1.ListRolesRequest) (*authv1.ListRolesResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := h.svc.AdminRoles(ctx, actor)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	out := make([]*authv1.Role, 0, len(roles))
+	for _, r := range roles {
+		out = append(out, &authv1.Role{Name: r.Name, Description: r.Description, Permissions: r.Permissions})
+	}
+
+	return &authv1.ListRolesResponse{Roles: out}, nil
+}
+
+func (h *AdminHandler) DeleteUser(ctx context.Context, req *authv1.DeleteUserRequest) (*authv1.DeleteUserResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := h.svc.AdminDeleteUser(ctx, actor, domain.UserID(req.GetUserId())); err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.DeleteUserResponse{}, nil
+}
+
+// ListAdminActions: журнал, новые первыми; фильтры по админу и по пользователю
+func (h *AdminHandler) ListAdminActions(ctx context.Context, req *authv1.ListAdminActionsRequest) (*authv1.ListAdminActionsResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f := domain.AdminAuditFilter{BeforeID: domain.AdminAuditID(req.GetCursor()), Limit: int(req.GetLimit())}
+	if id := domain.UserID(req.GetActorId()); id > 0 {
+		f.ActorID = &id
+	}
+	if id := domain.UserID(req.GetTargetUserId()); id > 0 {
+		f.TargetUserID = &id
+	}
+
+	entries, next, err := h.svc.AdminAuditLog(ctx, actor, f)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	out := make([]*authv1.AdminAction, 0, len(entries))
+	for i := range entries {
+		out = append(out, toAdminActionPB(&entries[i]))
+	}
+
+	return &authv1.ListAdminActionsResponse{Actions: out, NextCursor: int64(next)}, nil
+}
+
+// SearchSecurityEvents: журнал безопасности всех пользователей; фильтры по пользователю, типам и IP
+func (h *AdminHandler) SearchSecurityEvents(ctx context.Context, req *authv1.SearchSecurityEventsRequest) (*authv1.SearchSecurityEventsResponse, error) {
+	actor, err := h.adminID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f := domain.AuthEventFilter{BeforeID: domain.AuthEventID(req.GetCursor()), Limit: int(req.GetLimit())}
+	if id := domain.UserID(req.GetUserId()); id > 0 {
+		f.UserID = &id
+	}
+	for _, t := range req.GetTypes() {
+		if t = strings.TrimSpace(t); t != "" {
+			f.Types = append(f.Types, domain.AuthEventType(t))
+		}
+	}
+	if s := strings.TrimSpace(req.GetIp()); s != "" {
+		ip, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid ip")
+		}
+		f.IP = &ip
+	}
+
+	events, next, err := h.svc.AdminSecurityEvents(ctx, actor, f)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.SearchSecurityEventsResponse{Events: toSecurityEventsPB(events), NextCursor: int64(next)}, nil
+}
+
+// adminID - только Authorization: Bearer; x-user-id от гейтвея здесь не годится,
+// иначе любой, кто достучится до gRPC-порта, назовется админом
+func (h *AdminHandler) adminID(ctx context.Context) (domain.UserID, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authz := firstNonEmpty(md, "authorization")
+	if !strings.HasPrefix(strings.ToLower(authz), "bearer ") {
+		return 0, status.Error(codes.Unauthenticated, "missing access token")
+	}
+	uid, err := h.svc.UserIDFromAccessToken(strings.TrimSpace(authz[len("bearer "):]))
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid access token")
+	}
+
+	return uid, nil
+}
+
+func toAdminUserPB(u *service.AdminUser) *authv1.AdminUser {
+	out := &authv1.AdminUser{
+		User:                  toUserPB(u.User),
+		Roles:                 u.Roles,
+		PasswordResetRequired: u.User.PasswordResetRequired,
+	}
+	if u.User.DisabledAt != nil {
+		out.DisabledAt = u.User.DisabledAt.Unix()
+	}
+
+	return out
+}
+
+func toAdminActionPB(e *domain.AdminAuditEntry) *authv1.AdminAction {
+	out := &authv1.AdminAction{
+		Id:        int64(e.ID),
+		ActorId:   int64(e.ActorID),
+		Action:    string(e.Action),
+		Details:   e.Details,
+		CreatedAt: e.CreatedAt.Unix(),
+	}
+	if e.TargetUserID != nil {
+		out.TargetUserId = int64(*e.TargetUserID)
+	}
+
+	return out
+}
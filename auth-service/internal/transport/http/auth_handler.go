@@ -15,6 +15,7 @@ import (
 
 	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -69,14 +70,25 @@ func (h *AuthHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*aut
 		return nil, mapError(err)
 	}
 
-	expires := int64(h.svc.AccessTTL().Seconds())
+	return h.toLoginResponsePB(res), nil
+}
+
+// toLoginResponsePB - ответ Login; со вторым фактором вместо токенов mfa_token
+func (h *AuthHandler) toLoginResponsePB(res *service.LoginResult) *authv1.LoginResponse {
+	if res.MFARequired {
+		return &authv1.LoginResponse{
+			MfaRequired:  true,
+			MfaToken:     res.MFAToken,
+			MfaExpiresIn: int64(res.MFAExpiresIn.Seconds()),
+		}
+	}
 
 	return &authv1.LoginResponse{
 		AccessToken:  res.AccessToken,
 		RefreshToken: res.RefreshToken,
-		ExpiresIn:    expires,
+		ExpiresIn:    int64(h.svc.AccessTTL().Seconds()),
 		User:         toUserPB(res.User),
-	}, nil
+	}
 }
 
 // Refresh: refresh_token - resp: new access, new refresh
@@ -100,36 +112,81 @@ func (h *AuthHandler) Refresh(ctx context.Context, req *authv1.RefreshRequest) (
 	}, nil
 }
 
+// ChangePassword: email, текущий и новый пароль (+ код второго фактора, если включен); все сессии завершаются.
+// Так же снимается принудительный сброс пароля от админа
+func (h *AuthHandler) ChangePassword(ctx context.Context, req *authv1.ChangePasswordRequest) (*authv1.ChangePasswordResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetEmail()) == "" || req.GetCurrentPassword() == "" || req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email, current_password and new_password are required")
+	}
+	meta := extractLoginMeta(ctx)
+
+	err := h.svc.ChangePassword(ctx, strings.TrimSpace(req.GetEmail()), req.GetCurrentPassword(), req.GetNewPassword(), req.GetMfaCode(), meta)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.ChangePasswordResponse{}, nil
+}
+
 // Me: получить профиль по user_id, который кладёт API-Gateway после валидации access-JWT.
 func (h *AuthHandler) Me(ctx context.Context, req *authv1.MeRequest) (*authv1.MeResponse, error) {
-	// Попытка №1: x-user-id из метаданных которые кладет API-Gateway
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	u, err := h.svc.Me(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.MeResponse{User: toUserPB(u)}, nil
+}
+
+// ListSecurityEvents: последние события безопасности текущего пользователя
+func (h *AuthHandler) ListSecurityEvents(ctx context.Context, req *authv1.ListSecurityEventsRequest) (*authv1.ListSecurityEventsResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events, next, err := h.svc.SecurityEvents(ctx, uid, domain.AuthEventID(req.GetCursor()), int(req.GetLimit()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.ListSecurityEventsResponse{Events: toSecurityEventsPB(events), NextCursor: int64(next)}, nil
+}
+
+// GetJwks: публичный ключ подписи access-токенов, чтобы другие сервисы проверяли их сами
+func (h *AuthHandler) GetJwks(ctx context.Context, req *authv1.GetJwksRequest) (*authv1.GetJwksResponse, error) {
+	b, err := h.svc.JWKS()
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.GetJwksResponse{JwksJson: string(b)}, nil
+}
+
+// currentUserID: x-user-id из метаданных (кладет API-Gateway), иначе Authorization: Bearer <accessToken>
+func (h *AuthHandler) currentUserID(ctx context.Context) (domain.UserID, error) {
 	if uid, ok := userIDFromMD(ctx); ok {
-		u, err := h.svc.Me(ctx, uid)
-		if err != nil {
-			return nil, mapError(err)
-		}
-		return &authv1.MeResponse{User: toUserPB(u)}, nil
+		return uid, nil
 	}
 
-	// Попытка №2: Authorization: Bearer <accessToken>
 	if md, ok := metadata.FromIncomingContext(ctx); ok {
 		authz := firstNonEmpty(md, "authorization")
 		if strings.HasPrefix(strings.ToLower(authz), "bearer ") {
 			token := strings.TrimSpace(authz[len("bearer "):])
 			if token != "" {
 				if uid, err := h.svc.UserIDFromAccessToken(token); err == nil {
-					u, err := h.svc.Me(ctx, uid)
-					if err != nil {
-						return nil, mapError(err)
-					}
-					return &authv1.MeResponse{User: toUserPB(u)}, nil
+					return uid, nil
 				}
-				return nil, status.Error(codes.Unauthenticated, "invalid access token")
+				return 0, status.Error(codes.Unauthenticated, "invalid access token")
 			}
 		}
 	}
 
-	return nil, status.Error(codes.Unauthenticated, "missing user id (x-user-id)")
+	return 0, status.Error(codes.Unauthenticated, "missing user id (x-user-id)")
 }
 
 // ---- helpers ----
@@ -146,7 +203,7 @@ func toUserPB(u *domain.User) *authv1.User {
 		av = *u.AvatarURL
 	}
 
-	return &authv1.User{
+	out := &authv1.User{
 		Id:            int64(u.ID),
 		Email:         u.Email,
 		EmailVerified: u.EmailVerified,
@@ -155,6 +212,41 @@ func toUserPB(u *domain.User) *authv1.User {
 		CreatedAt:     u.CreatedAt.Unix(),
 		UpdatedAt:     u.UpdatedAt.Unix(),
 	}
+	if u.DeletionScheduledAt != nil {
+		out.DeletionScheduledAt = u.DeletionScheduledAt.Unix()
+	}
+
+	return out
+}
+
+func toSecurityEventsPB(events []domain.AuthEvent) []*authv1.SecurityEvent {
+	out := make([]*authv1.SecurityEvent, 0, len(events))
+	for _, e := range events {
+		ev := &authv1.SecurityEvent{
+			Id:        int64(e.ID),
+			Type:      string(e.Type),
+			Details:   e.Details,
+			CreatedAt: e.CreatedAt.Unix(),
+		}
+		if e.UserID != nil {
+			ev.UserId = int64(*e.UserID)
+		}
+		if e.ActorID != nil {
+			ev.ActorId = int64(*e.ActorID)
+		}
+		if e.IP != nil {
+			ev.Ip = e.IP.String()
+		}
+		if e.UserAgent != nil {
+			ev.UserAgent = *e.UserAgent
+		}
+		if e.RequestID != nil {
+			ev.RequestId = *e.RequestID
+		}
+		out = append(out, ev)
+	}
+
+	return out
 }
 
 func mapError(err error) error {
@@ -169,6 +261,46 @@ func mapError(err error) error {
 		return status.Error(codes.Unauthenticated, err.Error())
 	case errors.Is(err, errs.ErrSessionExpired):
 		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errs.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, errs.ErrPasswordHashBusy):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, errs.ErrInvalidMFACode), errors.Is(err, errs.ErrInvalidMFAToken):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errs.ErrInvalidCeremony), errors.Is(err, errs.ErrWebAuthnFailed):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errs.ErrPasswordTooShort),
+		errors.Is(err, errs.ErrPasswordTooLong),
+		errors.Is(err, errs.ErrPasswordIsEmail),
+		errors.Is(err, errs.ErrPasswordBreached),
+		errors.Is(err, errs.ErrInvalidPasskeyName):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errs.ErrWebAuthnUnavailable), errors.Is(err, errs.ErrPasskeyLimit):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errs.ErrUnknownProvider):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errs.ErrInvalidOIDCState), errors.Is(err, errs.ErrOIDCFailed):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, errs.ErrIdentityLinked):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errs.ErrOIDCEmailNotVerified),
+		errors.Is(err, errs.ErrAccountLinkRequired),
+		errors.Is(err, errs.ErrLastLoginMethod):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errs.ErrAccountDisabled), errors.Is(err, errs.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, errs.ErrPasswordResetRequired),
+		errors.Is(err, errs.ErrSelfAdminAction),
+		errors.Is(err, errs.ErrAdminUnavailable),
+		errors.Is(err, errs.ErrAuditUnavailable),
+		errors.Is(err, errs.ErrDeletionNotScheduled),
+		errors.Is(err, errs.ErrReauthUnavailable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errs.ErrMFAUnavailable),
+		errors.Is(err, errs.ErrMFAAlreadyEnabled),
+		errors.Is(err, errs.ErrMFANotEnabled),
+		errors.Is(err, errs.ErrMFANotEnrolled):
+		return status.Error(codes.FailedPrecondition, err.Error())
 	default:
 		return status.Error(codes.Internal, "internal error")
 	}
@@ -192,15 +324,27 @@ func userIDFromMD(ctx context.Context) (domain.UserID, bool) {
 	return 0, false
 }
 
-// extractLoginMeta собирает метаданные для записи сессии: UserAgent и IP.
+// LoginMetaInterceptor кладет метаданные запроса в контекст для журнала безопасности:
+// не у всех методов сервиса есть параметр meta (EnrollTotp, действия админов и т.п.)
+func LoginMetaInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+		return next(service.WithLoginMeta(ctx, extractLoginMeta(ctx)), req)
+	}
+}
+
+// extractLoginMeta собирает метаданные для записи сессии и журнала: UserAgent, IP и x-request-id.
 func extractLoginMeta(ctx context.Context) *service.LoginMeta {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil
 	}
+	// UA клиента: от api-gateway, от grpc-gateway, иначе самого gRPC-клиента
 	var ua *string
-	if v := firstNonEmpty(md, "user-agent"); v != "" {
-		ua = &v
+	for _, key := range []string{"x-client-user-agent", "grpcgateway-user-agent", "user-agent"} {
+		if v := firstNonEmpty(md, key); v != "" {
+			ua = &v
+			break
+		}
 	}
 
 	// Источники IP в приоритете: x-forwarded-for, x-real-ip, peer addr.
@@ -224,11 +368,16 @@ func extractLoginMeta(ctx context.Context) *service.LoginMeta {
 		}
 	}
 
-	if ua == nil && ip == nil {
+	var reqID *string
+	if v := firstNonEmpty(md, "x-request-id"); v != "" {
+		reqID = &v
+	}
+
+	if ua == nil && ip == nil && reqID == nil {
 		return nil
 	}
 
-	return &service.LoginMeta{UserAgent: ua, IP: ip}
+	return &service.LoginMeta{UserAgent: ua, IP: ip, RequestID: reqID}
 }
 
 func firstNonEmpty(md metadata.MD, key string) string {
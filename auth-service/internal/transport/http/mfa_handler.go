@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// VerifyMfa: mfa_token из Login + код - resp: access, refresh, user
+func (h *AuthHandler) VerifyMfa(ctx context.Context, req *authv1.VerifyMfaRequest) (*authv1.VerifyMfaResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetMfaToken()) == "" || strings.TrimSpace(req.GetCode()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "mfa_token and code are required")
+	}
+	meta := extractLoginMeta(ctx)
+
+	res, err := h.svc.VerifyMFA(ctx, strings.TrimSpace(req.GetMfaToken()), req.GetCode(), meta)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.VerifyMfaResponse{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int64(h.svc.AccessTTL().Seconds()),
+		User:         toUserPB(res.User),
+	}, nil
+}
+
+// EnrollTotp: новый секрет - resp: otpauth URI, секрет и QR (PNG)
+func (h *AuthHandler) EnrollTotp(ctx context.Context, req *authv1.EnrollTotpRequest) (*authv1.EnrollTotpResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := h.svc.EnrollTOTP(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.EnrollTotpResponse{
+		OtpauthUrl: key.URL,
+		Secret:     key.Secret,
+		QrPng:      key.QRPNG,
+	}, nil
+}
+
+// ConfirmTotp: первый код из приложения - resp: коды восстановления
+func (h *AuthHandler) ConfirmTotp(ctx context.Context, req *authv1.ConfirmTotpRequest) (*authv1.ConfirmTotpResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetCode()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recovery, err := h.svc.ConfirmTOTP(ctx, uid, req.GetCode())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.ConfirmTotpResponse{RecoveryCodes: recovery}, nil
+}
+
+// DisableTotp: текущий код или код восстановления
+func (h *AuthHandler) DisableTotp(ctx context.Context, req *authv1.DisableTotpRequest) (*authv1.DisableTotpResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetCode()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.svc.DisableTOTP(ctx, uid, req.GetCode()); err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.DisableTotpResponse{}, nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListOidcProviders: resp: имена настроенных провайдеров
+func (h *AuthHandler) ListOidcProviders(ctx context.Context, req *authv1.ListOidcProvidersRequest) (*authv1.ListOidcProvidersResponse, error) {
+	return &authv1.ListOidcProvidersResponse{Providers: h.svc.OIDCProviders()}, nil
+}
+
+// BeginOidcLogin: provider - resp: authorization_url и state
+func (h *AuthHandler) BeginOidcLogin(ctx context.Context, req *authv1.BeginOidcLoginRequest) (*authv1.BeginOidcResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetProvider()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider is required")
+	}
+
+	start, err := h.svc.BeginOIDCLogin(ctx, strings.TrimSpace(req.GetProvider()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.BeginOidcResponse{AuthorizationUrl: start.AuthURL, State: start.State}, nil
+}
+
+// FinishOidcLogin: provider, state, code - resp: как у Login
+func (h *AuthHandler) FinishOidcLogin(ctx context.Context, req *authv1.FinishOidcRequest) (*authv1.LoginResponse, error) {
+	if err := validateFinishOidc(req); err != nil {
+		return nil, err
+	}
+	meta := extractLoginMeta(ctx)
+
+	res, err := h.svc.FinishOIDCLogin(ctx, strings.TrimSpace(req.GetProvider()), req.GetState(), req.GetCode(), meta)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return h.toLoginResponsePB(res), nil
+}
+
+// BeginOidcLink: provider - resp: authorization_url и state для привязки к текущему пользователю
+func (h *AuthHandler) BeginOidcLink(ctx context.Context, req *authv1.BeginOidcLinkRequest) (*authv1.BeginOidcResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetProvider()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	start, err := h.svc.BeginOIDCLink(ctx, uid, strings.TrimSpace(req.GetProvider()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.BeginOidcResponse{AuthorizationUrl: start.AuthURL, State: start.State}, nil
+}
+
+// FinishOidcLink: provider, state, code - resp: привязанный аккаунт
+func (h *AuthHandler) FinishOidcLink(ctx context.Context, req *authv1.FinishOidcRequest) (*authv1.FinishOidcLinkResponse, error) {
+	if err := validateFinishOidc(req); err != nil {
+		return nil, err
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ident, err := h.svc.FinishOIDCLink(ctx, uid, strings.TrimSpace(req.GetProvider()), req.GetState(), req.GetCode())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.FinishOidcLinkResponse{Identity: toIdentityPB(ident)}, nil
+}
+
+func (h *AuthHandler) ListIdentities(ctx context.Context, req *authv1.ListIdentitiesRequest) (*authv1.ListIdentitiesResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	idents, err := h.svc.ListIdentities(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	out := make([]*authv1.Identity, 0, len(idents))
+	for i := range idents {
+		out = append(out, toIdentityPB(&idents[i]))
+	}
+
+	return &authv1.ListIdentitiesResponse{Identities: out}, nil
+}
+
+func (h *AuthHandler) UnlinkIdentity(ctx context.Context, req *authv1.UnlinkIdentityRequest) (*authv1.UnlinkIdentityResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetProvider()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "provider is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.svc.UnlinkIdentity(ctx, uid, strings.TrimSpace(req.GetProvider())); err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.UnlinkIdentityResponse{}, nil
+}
+
+func validateFinishOidc(req *authv1.FinishOidcRequest) error {
+	if req == nil || strings.TrimSpace(req.GetProvider()) == "" || req.GetState() == "" || req.GetCode() == "" {
+		return status.Error(codes.InvalidArgument, "provider, state and code are required")
+	}
+
+	return nil
+}
+
+func toIdentityPB(i *domain.Identity) *authv1.Identity {
+	if i == nil {
+		return nil
+	}
+	var email string
+	if i.Email != nil {
+		email = *i.Email
+	}
+	var lastLogin int64
+	if i.LastLoginAt != nil {
+		lastLogin = i.LastLoginAt.Unix()
+	}
+
+	return &authv1.Identity{
+		Id:          int64(i.ID),
+		Provider:    i.Provider,
+		Subject:     i.Subject,
+		Email:       email,
+		CreatedAt:   i.CreatedAt.Unix(),
+		LastLoginAt: lastLogin,
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+
+	authv1 "github.com/cwrk-planet/auth-service/proto/gen/auth/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// BeginPasskeyRegistration: resp: ceremony_id и options для navigator.credentials.create()
+func (h *AuthHandler) BeginPasskeyRegistration(ctx context.Context, req *authv1.BeginPasskeyRegistrationRequest) (*authv1.BeginPasskeyRegistrationResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, options, err := h.svc.BeginPasskeyRegistration(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.BeginPasskeyRegistrationResponse{CeremonyId: id, OptionsJson: string(options)}, nil
+}
+
+// FinishPasskeyRegistration: ceremony_id + ответ аутентификатора - resp: сохраненный passkey
+func (h *AuthHandler) FinishPasskeyRegistration(ctx context.Context, req *authv1.FinishPasskeyRegistrationRequest) (*authv1.FinishPasskeyRegistrationResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetCeremonyId()) == "" || strings.TrimSpace(req.GetCredentialJson()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "ceremony_id and credential_json are required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := h.svc.FinishPasskeyRegistration(ctx, uid, strings.TrimSpace(req.GetCeremonyId()), req.GetName(), []byte(req.GetCredentialJson()))
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.FinishPasskeyRegistrationResponse{Passkey: toPasskeyPB(p)}, nil
+}
+
+// BeginPasskeyReauth: resp: ceremony_id и options для navigator.credentials.get() перед DeleteAccount
+func (h *AuthHandler) BeginPasskeyReauth(ctx context.Context, req *authv1.BeginPasskeyReauthRequest) (*authv1.BeginPasskeyReauthResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, options, err := h.svc.BeginPasskeyReauth(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.BeginPasskeyReauthResponse{CeremonyId: id, OptionsJson: string(options)}, nil
+}
+
+// BeginPasskeyLogin: resp: ceremony_id и options для navigator.credentials.get()
+func (h *AuthHandler) BeginPasskeyLogin(ctx context.Context, req *authv1.BeginPasskeyLoginRequest) (*authv1.BeginPasskeyLoginResponse, error) {
+	id, options, err := h.svc.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.BeginPasskeyLoginResponse{CeremonyId: id, OptionsJson: string(options)}, nil
+}
+
+// FinishPasskeyLogin: ceremony_id + подпись аутентификатора - resp: access, refresh, user
+func (h *AuthHandler) FinishPasskeyLogin(ctx context.Context, req *authv1.FinishPasskeyLoginRequest) (*authv1.FinishPasskeyLoginResponse, error) {
+	if req == nil || strings.TrimSpace(req.GetCeremonyId()) == "" || strings.TrimSpace(req.GetCredentialJson()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "ceremony_id and credential_json are required")
+	}
+	meta := extractLoginMeta(ctx)
+
+	res, err := h.svc.FinishPasskeyLogin(ctx, strings.TrimSpace(req.GetCeremonyId()), []byte(req.GetCredentialJson()), meta)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.FinishPasskeyLoginResponse{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+		ExpiresIn:    int64(h.svc.AccessTTL().Seconds()),
+		User:         toUserPB(res.User),
+	}, nil
+}
+
+func (h *AuthHandler) ListPasskeys(ctx context.Context, req *authv1.ListPasskeysRequest) (*authv1.ListPasskeysResponse, error) {
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := h.svc.ListPasskeys(ctx, uid)
+	if err != nil {
+		return nil, mapError(err)
+	}
+	out := make([]*authv1.Passkey, 0, len(passkeys))
+	for i := range passkeys {
+		out = append(out, toPasskeyPB(&passkeys[i]))
+	}
+
+	return &authv1.ListPasskeysResponse{Passkeys: out}, nil
+}
+
+func (h *AuthHandler) RenamePasskey(ctx context.Context, req *authv1.RenamePasskeyRequest) (*authv1.RenamePasskeyResponse, error) {
+	if req == nil || req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := h.svc.RenamePasskey(ctx, uid, domain.PasskeyID(req.GetId()), req.GetName())
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.RenamePasskeyResponse{Passkey: toPasskeyPB(p)}, nil
+}
+
+func (h *AuthHandler) RevokePasskey(ctx context.Context, req *authv1.RevokePasskeyRequest) (*authv1.RevokePasskeyResponse, error) {
+	if req == nil || req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	uid, err := h.currentUserID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.svc.RevokePasskey(ctx, uid, domain.PasskeyID(req.GetId())); err != nil {
+		return nil, mapError(err)
+	}
+
+	return &authv1.RevokePasskeyResponse{}, nil
+}
+
+func toPasskeyPB(p *domain.Passkey) *authv1.Passkey {
+	if p == nil {
+		return nil
+	}
+	var lastUsed int64
+	if p.LastUsedAt != nil {
+		lastUsed = p.LastUsedAt.Unix()
+	}
+
+	return &authv1.Passkey{
+		Id:             int64(p.ID),
+		Name:           p.Name,
+		Transports:     p.Transports,
+		BackupEligible: p.BackupEligible,
+		BackupState:    p.BackupState,
+		CreatedAt:      p.CreatedAt.Unix(),
+		LastUsedAt:     lastUsed,
+	}
+}
@@ -29,7 +29,8 @@ type Server struct {
 	cancel   context.CancelFunc
 }
 
-func New(httpAddr, grpcAddr string) (*Server, error) {
+// New - grpc-gateway поверх grpcAddr; mounts добавляют обычные HTTP-маршруты мимо gateway (напр. OIDC провайдер)
+func New(httpAddr, grpcAddr string, mounts ...func(m *http.ServeMux)) (*Server, error) {
 	mux := runtime.NewServeMux(
 		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
 			MarshalOptions: protojson.MarshalOptions{
@@ -62,6 +63,11 @@ func New(httpAddr, grpcAddr string) (*Server, error) {
 		cancel()
 		return nil, err
 	}
+	if err := authv1.RegisterAdminServiceHandler(ctx, mux, conn); err != nil {
+		_ = conn.Close()
+		cancel()
+		return nil, err
+	}
 
 	root := chain(
 		mux,
@@ -78,6 +84,9 @@ func New(httpAddr, grpcAddr string) (*Server, error) {
 			w.WriteHeader(http.StatusOK)
 			_, _ = w.Write([]byte("ready"))
 		})
+		for _, mount := range mounts {
+			mount(m)
+		}
 	})
 
 	s := &http.Server{
@@ -223,15 +232,18 @@ func (w *loggingResponseWriter) Write(b []byte) (int, error) {
 // --- Redaction / Body reading ---
 
 var redactKeys = map[string]struct{}{
-	"password":      {},
-	"password_hash": {},
-	"refresh":       {},
-	"refresh_token": {},
-	"access":        {},
-	"access_token":  {},
-	"token":         {},
-	"jwt":           {},
-	"authorization": {},
+	"password":         {},
+	"password_hash":    {},
+	"current_password": {},
+	"new_password":     {},
+	"mfa_code":         {},
+	"refresh":          {},
+	"refresh_token":    {},
+	"access":           {},
+	"access_token":     {},
+	"token":            {},
+	"jwt":              {},
+	"authorization":    {},
 }
 
 func redactJSON(b []byte) string {
@@ -0,0 +1,302 @@
+package oauth
+
+import (
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+// authorize - страница входа и согласия.
+// GET приходит от клиента; POST - это наша же форма (action: login, mfa, consent, deny),
+// параметры запроса клиента едут в скрытых полях. Кука SameSite=Lax не уходит с чужих сайтов,
+// поэтому согласие подделать кросс-сайтовым POST нельзя
+func (h *Handler) authorize(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := r.ParseForm(); err != nil {
+		h.renderError(w, "Некорректный запрос")
+		return
+	}
+	req := &service.AuthorizeRequest{
+		ClientID:            r.Form.Get("client_id"),
+		RedirectURI:         r.Form.Get("redirect_uri"),
+		ResponseType:        r.Form.Get("response_type"),
+		Scope:               r.Form.Get("scope"),
+		State:               r.Form.Get("state"),
+		Nonce:               r.Form.Get("nonce"),
+		CodeChallenge:       r.Form.Get("code_challenge"),
+		CodeChallengeMethod: r.Form.Get("code_challenge_method"),
+	}
+	prompt := r.Form.Get("prompt")
+
+	// пока redirect_uri не проверен, никуда не редиректим
+	ctx := r.Context()
+	client, err := h.svc.OAuthClient(ctx, req.ClientID, req.RedirectURI)
+	if err != nil {
+		var oe *errs.OAuthError
+		if errors.As(err, &oe) {
+			h.renderError(w, "Приложение не найдено или адрес возврата не разрешен")
+			return
+		}
+		slog.Error("oauth.authorize.client failed", slog.Any("err", err))
+		h.renderError(w, "Внутренняя ошибка, попробуйте позже")
+		return
+	}
+	scopes, err := h.svc.CheckAuthorize(client, req)
+	if err != nil {
+		h.redirectError(w, r, req, err)
+		return
+	}
+
+	p := &page{Client: client, Req: req, Prompt: prompt, Scopes: scopes}
+
+	var (
+		user     *domain.User
+		authTime time.Time
+	)
+	if c, err := r.Cookie(ssoCookie); err == nil && (prompt != "login" || r.Method == http.MethodPost) {
+		if u, at, err := h.svc.SSOUser(ctx, c.Value); err == nil {
+			user, authTime = u, at
+		}
+	}
+
+	if r.Method == http.MethodPost {
+		switch r.PostForm.Get("action") {
+		case "login":
+			res, err := h.svc.Authenticate(ctx, r.PostForm.Get("email"), r.PostForm.Get("password"), loginMeta(r))
+			if err != nil {
+				p.Email = r.PostForm.Get("email")
+				h.renderLoginError(w, p, err)
+				return
+			}
+			if res.MFARequired {
+				p.MFAToken = res.MFAToken
+				h.render(w, http.StatusOK, p)
+				return
+			}
+			user, authTime = res.User, time.Now()
+		case "mfa":
+			res, err := h.svc.AuthenticateMFA(ctx, r.PostForm.Get("mfa_token"), r.PostForm.Get("code"), loginMeta(r))
+			if err != nil {
+				if errors.Is(err, errs.ErrInvalidMFACode) {
+					p.MFAToken = r.PostForm.Get("mfa_token")
+				}
+				h.renderLoginError(w, p, err)
+				return
+			}
+			user, authTime = res.User, time.Now()
+		case "consent":
+			if user == nil {
+				h.render(w, http.StatusOK, p)
+				return
+			}
+			h.issueCode(w, r, user, authTime, client, req, scopes)
+			return
+		case "deny":
+			h.redirectError(w, r, req, errs.NewOAuthError(errs.OAuthAccessDenied, "user denied the request"))
+			return
+		}
+		if user != nil {
+			if err := h.setSSOCookie(w, user, authTime); err != nil {
+				slog.Error("oauth.authorize.setSSOCookie failed", slog.Any("err", err))
+				h.renderError(w, "Внутренняя ошибка, попробуйте позже")
+				return
+			}
+		}
+	}
+
+	if user == nil {
+		if prompt == "none" {
+			h.redirectError(w, r, req, errs.NewOAuthError(errs.OAuthLoginRequired, ""))
+			return
+		}
+		h.render(w, http.StatusOK, p)
+		return
+	}
+
+	need, err := h.svc.NeedsConsent(ctx, user.ID, client.ID, scopes)
+	if err != nil {
+		slog.Error("oauth.authorize.needsConsent failed", slog.Any("err", err))
+		h.renderError(w, "Внутренняя ошибка, попробуйте позже")
+		return
+	}
+	if need || prompt == "consent" {
+		if prompt == "none" {
+			h.redirectError(w, r, req, errs.NewOAuthError(errs.OAuthConsentRequired, ""))
+			return
+		}
+		p.User = user
+		h.render(w, http.StatusOK, p)
+		return
+	}
+
+	h.issueCode(w, r, user, authTime, client, req, scopes)
+}
+
+func (h *Handler) issueCode(w http.ResponseWriter, r *http.Request, u *domain.User, authTime time.Time, c *domain.OAuthClient, req *service.AuthorizeRequest, scopes []string) {
+	code, err := h.svc.Authorize(r.Context(), u.ID, authTime, c, req, scopes)
+	if err != nil {
+		h.renderError(w, "Внутренняя ошибка, попробуйте позже")
+		return
+	}
+	h.redirect(w, r, req, url.Values{"code": {code}})
+}
+
+// redirectError - ошибка клиенту через redirect_uri (RFC 6749, 4.1.2.1)
+func (h *Handler) redirectError(w http.ResponseWriter, r *http.Request, req *service.AuthorizeRequest, err error) {
+	var oe *errs.OAuthError
+	if !errors.As(err, &oe) {
+		slog.Error("oauth.authorize failed", slog.Any("err", err))
+		oe = errs.NewOAuthError("server_error", "")
+	}
+	v := url.Values{"error": {oe.Code}}
+	if oe.Description != "" {
+		v.Set("error_description", oe.Description)
+	}
+	h.redirect(w, r, req, v)
+}
+
+func (h *Handler) redirect(w http.ResponseWriter, r *http.Request, req *service.AuthorizeRequest, v url.Values) {
+	u, err := url.Parse(req.RedirectURI)
+	if err != nil {
+		h.renderError(w, "Некорректный адрес возврата")
+		return
+	}
+	if req.State != "" {
+		v.Set("state", req.State)
+	}
+	q := u.Query()
+	for k, vals := range v {
+		q[k] = vals
+	}
+	u.RawQuery = q.Encode()
+
+	code := http.StatusFound
+	if r.Method == http.MethodPost {
+		code = http.StatusSeeOther
+	}
+	http.Redirect(w, r, u.String(), code)
+}
+
+func (h *Handler) renderLoginError(w http.ResponseWriter, p *page, err error) {
+	switch {
+	case errors.Is(err, errs.ErrInvalidCredentials):
+		p.Error = "Неверный email или пароль"
+	case errors.Is(err, errs.ErrInvalidMFACode):
+		p.Error = "Неверный код"
+	case errors.Is(err, errs.ErrInvalidMFAToken):
+		p.Error = "Время на ввод кода истекло, войдите еще раз"
+	case errors.Is(err, errs.ErrTooManyAttempts):
+		p.Error = "Слишком много попыток, попробуйте позже"
+	case errors.Is(err, errs.ErrAccountDisabled):
+		p.Error = "Аккаунт заблокирован"
+	case errors.Is(err, errs.ErrPasswordResetRequired):
+		p.Error = "Нужно сменить пароль, после этого войдите снова"
+	default:
+		slog.Error("oauth.authorize.login failed", slog.Any("err", err))
+		p.Error = "Внутренняя ошибка, попробуйте позже"
+	}
+	h.render(w, http.StatusOK, p)
+}
+
+func (h *Handler) renderError(w http.ResponseWriter, msg string) {
+	h.render(w, http.StatusBadRequest, &page{Error: msg, Fatal: true})
+}
+
+func (h *Handler) render(w http.ResponseWriter, code int, p *page) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := pageTmpl.Execute(w, p); err != nil {
+		slog.Error("oauth.authorize.render failed", slog.Any("err", err))
+	}
+}
+
+// page - одна страница на все шаги: вход, код второго фактора, согласие, ошибка
+type page struct {
+	Client   *domain.OAuthClient
+	Req      *service.AuthorizeRequest
+	Prompt   string
+	Scopes   []string
+	User     *domain.User // есть - показываем согласие
+	MFAToken string       // есть - спрашиваем код
+	Email    string
+	Error    string
+	Fatal    bool // дальше идти некуда, формы нет
+}
+
+var scopeTitles = map[string]string{
+	"openid":  "Идентификатор вашего аккаунта",
+	"email":   "Адрес электронной почты",
+	"profile": "Имя в профиле",
+}
+
+var pageTmpl = template.Must(template.New("authorize").Funcs(template.FuncMap{
+	"scopeTitle": func(s string) string {
+		if t, ok := scopeTitles[s]; ok {
+			return t
+		}
+		return s
+	},
+}).Parse(`<!doctype html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Вход - cwrkPlanet</title>
+<style>
+body{font-family:system-ui,sans-serif;background:#f4f5f7;margin:0;display:flex;justify-content:center;padding-top:10vh}
+main{background:#fff;border-radius:8px;padding:24px 32px;width:340px;box-shadow:0 1px 4px rgba(0,0,0,.1)}
+input{display:block;width:100%;box-sizing:border-box;margin:6px 0 12px;padding:8px}
+button{padding:8px 16px;margin-right:8px}
+.error{color:#b00020}
+</style>
+</head>
+<body>
+<main>
+{{- if .Fatal}}
+<h1>Ошибка</h1>
+<p class="error">{{.Error}}</p>
+{{- else}}
+<h1>cwrkPlanet</h1>
+{{- if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post" action="/oauth/authorize">
+<input type="hidden" name="client_id" value="{{.Req.ClientID}}">
+<input type="hidden" name="redirect_uri" value="{{.Req.RedirectURI}}">
+<input type="hidden" name="response_type" value="{{.Req.ResponseType}}">
+<input type="hidden" name="scope" value="{{.Req.Scope}}">
+<input type="hidden" name="state" value="{{.Req.State}}">
+<input type="hidden" name="nonce" value="{{.Req.Nonce}}">
+<input type="hidden" name="code_challenge" value="{{.Req.CodeChallenge}}">
+<input type="hidden" name="code_challenge_method" value="{{.Req.CodeChallengeMethod}}">
+<input type="hidden" name="prompt" value="{{.Prompt}}">
+{{- if .User}}
+<p><b>{{.Client.Name}}</b> запрашивает доступ к вашему аккаунту {{.User.Email}}:</p>
+<ul>{{range .Scopes}}<li>{{scopeTitle .}}</li>{{end}}</ul>
+<button type="submit" name="action" value="consent">Разрешить</button>
+<button type="submit" name="action" value="deny">Отказать</button>
+{{- else if .MFAToken}}
+<p>Введите код из приложения-аутентификатора или код восстановления</p>
+<input type="hidden" name="mfa_token" value="{{.MFAToken}}">
+<input name="code" autocomplete="one-time-code" autofocus required>
+<button type="submit" name="action" value="mfa">Продолжить</button>
+{{- else}}
+<p>Вход в <b>{{.Client.Name}}</b> через аккаунт cwrkPlanet</p>
+<input type="email" name="email" value="{{.Email}}" placeholder="Email" autocomplete="username" autofocus required>
+<input type="password" name="password" placeholder="Пароль" autocomplete="current-password" required>
+<button type="submit" name="action" value="login">Войти</button>
+{{- end}}
+</form>
+{{- end}}
+</main>
+</body>
+</html>
+`))
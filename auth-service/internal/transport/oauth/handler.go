@@ -0,0 +1,239 @@
+// Package oauth - HTTP-эндпоинты OpenID Connect провайдера: discovery, authorize, token, userinfo, jwks.
+// Это обычный HTTP (form + redirect), а не gRPC: так их ждут готовые OIDC-библиотеки клиентов
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/auth-service/internal/domain"
+	"github.com/cwrk-planet/auth-service/internal/errs"
+	"github.com/cwrk-planet/auth-service/internal/service"
+)
+
+const ssoCookie = "cwrk_sso"
+
+type Handler struct {
+	svc          *service.AuthService
+	secureCookie bool // кука только по https; выключается для локальной разработки на http
+}
+
+func New(svc *service.AuthService) *Handler {
+	return &Handler{
+		svc:          svc,
+		secureCookie: strings.HasPrefix(svc.OAuthIssuer(), "https://"),
+	}
+}
+
+// Mount - регистрирует маршруты; подходит для handler.New(..., h.Mount)
+func (h *Handler) Mount(m *http.ServeMux) {
+	m.HandleFunc("GET /.well-known/openid-configuration", withCORS(h.discovery))
+	m.HandleFunc("GET /oauth/jwks", withCORS(h.jwks))
+	m.HandleFunc("GET /oauth/authorize", h.authorize)
+	m.HandleFunc("POST /oauth/authorize", h.authorize)
+	m.HandleFunc("POST /oauth/token", withCORS(h.token))
+	m.HandleFunc("GET /oauth/userinfo", withCORS(h.userinfo))
+	m.HandleFunc("POST /oauth/userinfo", withCORS(h.userinfo))
+	m.HandleFunc("OPTIONS /oauth/", withCORS(func(w http.ResponseWriter, r *http.Request) {}))
+}
+
+func (h *Handler) discovery(w http.ResponseWriter, r *http.Request) {
+	iss := h.svc.OAuthIssuer()
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                iss,
+		"authorization_endpoint":                iss + "/oauth/authorize",
+		"token_endpoint":                        iss + "/oauth/token",
+		"userinfo_endpoint":                     iss + "/oauth/userinfo",
+		"jwks_uri":                              iss + "/oauth/jwks",
+		"response_types_supported":              []string{"code"},
+		"grant_types_supported":                 []string{"authorization_code", "refresh_token"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post", "none"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"claims_supported":                      []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "email", "email_verified", "name", "display_name"},
+	})
+}
+
+func (h *Handler) jwks(w http.ResponseWriter, r *http.Request) {
+	b, err := h.svc.JWKS()
+	if err != nil {
+		slog.Error("oauth.jwks failed", slog.Any("err", err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_, _ = w.Write(b)
+}
+
+// token - секрет клиента из Basic auth или из формы (client_secret_post)
+func (h *Handler) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, errs.NewOAuthError(errs.OAuthInvalidRequest, "malformed form"))
+		return
+	}
+	req := &service.TokenRequest{
+		GrantType:    r.PostForm.Get("grant_type"),
+		ClientID:     r.PostForm.Get("client_id"),
+		ClientSecret: r.PostForm.Get("client_secret"),
+		Code:         r.PostForm.Get("code"),
+		RedirectURI:  r.PostForm.Get("redirect_uri"),
+		CodeVerifier: r.PostForm.Get("code_verifier"),
+		RefreshToken: r.PostForm.Get("refresh_token"),
+		Scope:        r.PostForm.Get("scope"),
+	}
+	if id, secret, ok := r.BasicAuth(); ok {
+		// RFC 6749, 2.3.1: id и секрет в Basic дополнительно url-encoded
+		req.ClientID, _ = url.QueryUnescape(id)
+		req.ClientSecret, _ = url.QueryUnescape(secret)
+	}
+
+	res, err := h.svc.Token(r.Context(), req)
+	if err != nil {
+		writeOAuthError(w, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  res.AccessToken,
+		"token_type":    "Bearer",
+		"expires_in":    int64(res.ExpiresIn.Seconds()),
+		"id_token":      res.IDToken,
+		"refresh_token": res.RefreshToken,
+		"scope":         res.Scope,
+	})
+}
+
+func (h *Handler) userinfo(w http.ResponseWriter, r *http.Request) {
+	token := ""
+	if authz := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(authz), "bearer ") {
+		token = strings.TrimSpace(authz[len("bearer "):])
+	}
+	if token == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer`)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := h.svc.UserInfo(r.Context(), token)
+	if err != nil {
+		var oe *errs.OAuthError
+		if errors.As(err, &oe) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="`+oe.Code+`"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		slog.Error("oauth.userinfo failed", slog.Any("err", err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, claims)
+}
+
+// ---- helpers ----
+
+// writeOAuthError - ответ token endpoint по RFC 6749, 5.2
+func writeOAuthError(w http.ResponseWriter, err error) {
+	var oe *errs.OAuthError
+	if !errors.As(err, &oe) {
+		if !errors.Is(err, errs.ErrOAuthUnavailable) {
+			slog.Error("oauth.token failed", slog.Any("err", err))
+		}
+		oe = errs.NewOAuthError("server_error", "")
+	}
+
+	code := http.StatusBadRequest
+	switch oe.Code {
+	case errs.OAuthInvalidClient:
+		code = http.StatusUnauthorized
+		w.Header().Set("WWW-Authenticate", `Basic realm="oauth"`)
+	case "server_error":
+		code = http.StatusInternalServerError
+	}
+
+	body := map[string]string{"error": oe.Code}
+	if oe.Description != "" {
+		body["error_description"] = oe.Description
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, code, body)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// withCORS - эти эндпоинты зовут и из браузера (SPA с PKCE); кук здесь нет, поэтому * безопасно
+func withCORS(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// loginMeta - для учета неудачных входов и как в gRPC: X-Forwarded-For, X-Real-IP, адрес соединения
+func loginMeta(r *http.Request) *service.LoginMeta {
+	meta := &service.LoginMeta{}
+	if ua := r.UserAgent(); ua != "" {
+		meta.UserAgent = &ua
+	}
+	if id := r.Header.Get("X-Request-ID"); id != "" {
+		meta.RequestID = &id
+	}
+
+	candidates := []string{
+		strings.TrimSpace(strings.Split(r.Header.Get("X-Forwarded-For"), ",")[0]),
+		strings.TrimSpace(r.Header.Get("X-Real-IP")),
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		candidates = append(candidates, host)
+	}
+	for _, c := range candidates {
+		if ip, err := netip.ParseAddr(c); err == nil {
+			ip = ip.Unmap()
+			meta.IP = &ip
+			break
+		}
+	}
+
+	return meta
+}
+
+func (h *Handler) setSSOCookie(w http.ResponseWriter, u *domain.User, authTime time.Time) error {
+	token, err := h.svc.SSOToken(u.ID, authTime)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     ssoCookie,
+		Value:    token,
+		Path:     "/oauth/",
+		MaxAge:   int(h.svc.SSOTTL().Seconds()),
+		HttpOnly: true,
+		Secure:   h.secureCookie,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	return nil
+}
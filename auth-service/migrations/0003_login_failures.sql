@@ -0,0 +1,13 @@
+-- неудачные попытки входа: по аккаунту (email) и по IP
+CREATE TABLE IF NOT EXISTS auth_login_failures (
+    scope            TEXT         NOT NULL,               -- account | ip
+    key              TEXT         NOT NULL,               -- email в нижнем регистре или IP
+    failures         INT          NOT NULL DEFAULT 0,
+    last_failed_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    locked_until     TIMESTAMPTZ,
+
+    PRIMARY KEY (scope, key),
+    CONSTRAINT login_failures_scope_valid CHECK (scope IN ('account', 'ip'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_failures_last_failed_at ON auth_login_failures (last_failed_at);
@@ -0,0 +1,27 @@
+-- TOTP (RFC 6238): секрет хранится зашифрованным (security.mfa.encryptionKey)
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS totp_secret_enc  TEXT,                       -- есть, но enabled_at NULL — подключение не подтверждено
+    ADD COLUMN IF NOT EXISTS totp_enabled_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS totp_last_step   BIGINT NOT NULL DEFAULT 0;  -- последний принятый шаг, защита от повтора кода
+
+-- одноразовые коды восстановления, храним только хеш
+CREATE TABLE IF NOT EXISTS auth_recovery_codes (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash        TEXT         NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    used_at          TIMESTAMPTZ,
+
+    CONSTRAINT recovery_code_unique UNIQUE (user_id, code_hash)
+);
+
+-- второй шаг входа: Login выдает mfa_token, VerifyMfa меняет его (+ код) на пару токенов
+CREATE TABLE IF NOT EXISTS auth_mfa_challenges (
+    token_hash       TEXT         PRIMARY KEY,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    attempts         INT          NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_expires_at ON auth_mfa_challenges (expires_at);
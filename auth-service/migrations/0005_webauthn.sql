@@ -0,0 +1,35 @@
+-- passkeys (WebAuthn): у пользователя может быть несколько, у каждого свое имя
+CREATE TABLE IF NOT EXISTS webauthn_credentials (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    credential_id    BYTEA        NOT NULL,
+    public_key       BYTEA        NOT NULL,                -- COSE
+    attestation_type TEXT         NOT NULL DEFAULT '',
+    transports       TEXT[]       NOT NULL DEFAULT '{}',
+    aaguid           BYTEA,
+    sign_count       BIGINT       NOT NULL DEFAULT 0,      -- счетчик подписей, откат назад — признак клона
+    backup_eligible  BOOLEAN      NOT NULL DEFAULT FALSE,
+    backup_state     BOOLEAN      NOT NULL DEFAULT FALSE,
+    name             TEXT         NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    last_used_at     TIMESTAMPTZ,
+
+    CONSTRAINT webauthn_credential_id_unique UNIQUE (credential_id),
+    CONSTRAINT webauthn_name_not_empty CHECK (length(trim(name)) > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_credentials_user_id ON webauthn_credentials (user_id);
+
+-- незавершенные церемонии регистрации/входа (challenge и прочее из webauthn.SessionData)
+CREATE TABLE IF NOT EXISTS webauthn_ceremonies (
+    id_hash          TEXT         PRIMARY KEY,
+    kind             TEXT         NOT NULL,                -- registration | login
+    user_id          BIGINT       REFERENCES users(id) ON DELETE CASCADE, -- NULL для входа по passkey
+    session          JSONB        NOT NULL,
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT webauthn_ceremony_kind_valid CHECK (kind IN ('registration', 'login'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_webauthn_ceremonies_expires_at ON webauthn_ceremonies (expires_at);
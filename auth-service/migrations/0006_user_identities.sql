@@ -0,0 +1,28 @@
+-- внешние аккаунты (OIDC): subject у провайдера -> наш пользователь
+CREATE TABLE IF NOT EXISTS user_identities (
+    id               BIGSERIAL PRIMARY KEY,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider         TEXT         NOT NULL,                -- имя из security.oidc.providers
+    subject          TEXT         NOT NULL,                -- claim sub из ID token
+    email            TEXT,                                 -- email у провайдера на момент привязки
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    last_login_at    TIMESTAMPTZ,
+
+    CONSTRAINT user_identities_subject_unique UNIQUE (provider, subject),
+    CONSTRAINT user_identities_user_provider_unique UNIQUE (user_id, provider)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities (user_id);
+
+-- начатые входы через провайдера: state, nonce и PKCE verifier до возврата с кодом
+CREATE TABLE IF NOT EXISTS oidc_auth_states (
+    state_hash       TEXT         PRIMARY KEY,
+    provider         TEXT         NOT NULL,
+    nonce            TEXT         NOT NULL,
+    code_verifier    TEXT         NOT NULL,
+    user_id          BIGINT       REFERENCES users(id) ON DELETE CASCADE, -- не NULL - привязка к этому пользователю
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_auth_states_expires_at ON oidc_auth_states (expires_at);
@@ -0,0 +1,54 @@
+-- auth-service как OpenID Connect провайдер для внутренних сервисов (вики, LMS...)
+
+-- зарегистрированные приложения; secret_hash NULL - публичный клиент (SPA, мобильное), только с PKCE
+CREATE TABLE IF NOT EXISTS oauth_clients (
+    id               TEXT         PRIMARY KEY,             -- client_id
+    secret_hash      TEXT,                                 -- sha256 hex от client_secret
+    name             TEXT         NOT NULL,                -- показывается на странице согласия
+    redirect_uris    TEXT[]       NOT NULL,                -- точное совпадение
+    scopes           TEXT[]       NOT NULL DEFAULT '{openid,email,profile}',
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT oauth_clients_redirect_uris_not_empty CHECK (cardinality(redirect_uris) > 0)
+);
+
+-- выданные, но еще не обмененные authorization codes
+CREATE TABLE IF NOT EXISTS oauth_authorization_codes (
+    code_hash        TEXT         PRIMARY KEY,
+    client_id        TEXT         NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    redirect_uri     TEXT         NOT NULL,
+    scopes           TEXT[]       NOT NULL,
+    nonce            TEXT,
+    code_challenge   TEXT,                                 -- S256
+    auth_time        TIMESTAMPTZ  NOT NULL,
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_authorization_codes_expires_at ON oauth_authorization_codes (expires_at);
+
+-- refresh-токены клиентов; отдельно от sessions, чтобы не смешивать с входом в сам cwrkPlanet
+CREATE TABLE IF NOT EXISTS oauth_refresh_tokens (
+    token_hash       TEXT         PRIMARY KEY,
+    client_id        TEXT         NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scopes           TEXT[]       NOT NULL,
+    auth_time        TIMESTAMPTZ  NOT NULL,
+    expires_at       TIMESTAMPTZ  NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_oauth_refresh_tokens_user_id ON oauth_refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS idx_oauth_refresh_tokens_expires_at ON oauth_refresh_tokens (expires_at);
+
+-- согласия: пользователь уже разрешил клиенту эти scopes, повторно не спрашиваем
+CREATE TABLE IF NOT EXISTS oauth_consents (
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id        TEXT         NOT NULL REFERENCES oauth_clients(id) ON DELETE CASCADE,
+    scopes           TEXT[]       NOT NULL,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, client_id)
+);
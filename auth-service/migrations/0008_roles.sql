@@ -0,0 +1,27 @@
+-- RBAC платформы: роль = набор прав (scopes), в access-токен идут роли и объединение их прав
+CREATE TABLE IF NOT EXISTS roles (
+    name             TEXT         PRIMARY KEY,             -- user, moderator, admin...
+    description      TEXT         NOT NULL DEFAULT '',
+    permissions      TEXT[]       NOT NULL DEFAULT '{}',   -- напр. rooms:create, admin:users
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role             TEXT         NOT NULL REFERENCES roles(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    granted_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles (role);
+
+INSERT INTO roles (name, description, permissions) VALUES
+    ('user',  'Обычный пользователь', '{rooms:create}'),
+    ('admin', 'Администратор платформы', '{rooms:create,admin:users}')
+ON CONFLICT (name) DO NOTHING;
+
+-- у всех существующих пользователей - роль по умолчанию
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'user' FROM users
+ON CONFLICT DO NOTHING;
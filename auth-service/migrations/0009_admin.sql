@@ -0,0 +1,22 @@
+-- администрирование пользователей: блокировка, принудительная смена пароля, журнал действий админов
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS disabled_at             TIMESTAMPTZ,                    -- не NULL - вход и refresh запрещены
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN      NOT NULL DEFAULT FALSE; -- вход по паролю только после ChangePassword
+
+-- поиск по подстроке email / имени
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin ((email::text) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_users_display_name_trgm ON users USING gin (display_name gin_trgm_ops);
+
+-- без FK: запись остается после удаления и пользователя, и самого админа
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id               BIGSERIAL    PRIMARY KEY,
+    actor_id         BIGINT       NOT NULL,
+    action           TEXT         NOT NULL,                 -- user.disable, user.delete, ...
+    target_user_id   BIGINT,
+    details          JSONB        NOT NULL DEFAULT '{}',    -- напр. причина блокировки, новые роли
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_target ON admin_audit_log (target_user_id, id);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_log_actor ON admin_audit_log (actor_id, id);
@@ -0,0 +1,37 @@
+-- журнал событий безопасности: входы, refresh, смена пароля, MFA, действия админов.
+-- Только дописывается; без FK - события остаются и после удаления пользователя
+CREATE TABLE IF NOT EXISTS auth_audit_events (
+    id               BIGSERIAL    PRIMARY KEY,
+    type             TEXT         NOT NULL,                 -- login, login.failed, refresh.reuse_detected, admin.user.disable, ...
+    user_id          BIGINT,                                -- NULL - вход с неизвестным email
+    actor_id         BIGINT,                                -- админ для admin.*
+    ip               INET,
+    user_agent       TEXT,
+    request_id       TEXT,
+    details          JSONB        NOT NULL DEFAULT '{}',    -- напр. способ входа, причина отказа
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_audit_events_user ON auth_audit_events (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_events_type ON auth_audit_events (type, id);
+CREATE INDEX IF NOT EXISTS idx_auth_audit_events_ip ON auth_audit_events (ip, id);
+
+CREATE OR REPLACE FUNCTION auth_audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'auth_audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_auth_audit_events_append_only ON auth_audit_events;
+CREATE TRIGGER trg_auth_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON auth_audit_events
+    FOR EACH ROW EXECUTE FUNCTION auth_audit_events_append_only();
+
+-- хеши уже обмененных refresh-токенов: повторное предъявление значит, что токен утек
+CREATE TABLE IF NOT EXISTS auth_rotated_refresh_tokens (
+    token_hash       TEXT         PRIMARY KEY,
+    user_id          BIGINT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at       TIMESTAMPTZ  NOT NULL                  -- после этого токен не принят бы и так
+);
+
+CREATE INDEX IF NOT EXISTS idx_auth_rotated_refresh_tokens_expires ON auth_rotated_refresh_tokens (expires_at);
@@ -0,0 +1,6 @@
+-- удаление аккаунта по запросу пользователя: до deletion_scheduled_at можно отменить,
+-- потом RunCleanup удаляет пользователя и пишет user.deleted в outbox
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users (deletion_scheduled_at)
+    WHERE deletion_scheduled_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- повторная проверка passkey перед удалением аккаунта (церемония привязана к пользователю)
+ALTER TABLE webauthn_ceremonies DROP CONSTRAINT IF EXISTS webauthn_ceremony_kind_valid;
+ALTER TABLE webauthn_ceremonies
+    ADD CONSTRAINT webauthn_ceremony_kind_valid CHECK (kind IN ('registration', 'login', 'reauth'));
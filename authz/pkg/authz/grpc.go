@@ -0,0 +1,78 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Rules - какие scopes нужны методу; ключ - полное имя, напр. /room.v1.RoomService/CreateRoom
+type Rules map[string][]string
+
+// UnaryServerInterceptor - проверяет authorization из metadata и кладет Principal в контекст.
+// Невалидный токен - Unauthenticated; метод из rules без токена или без scopes - Unauthenticated/PermissionDenied.
+// Методы не из rules без токена пропускаются: их проверяет сам обработчик
+func UnaryServerInterceptor(v *Verifier, rules Rules) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authorize(ctx, v, rules, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor - то же для стримов
+func StreamServerInterceptor(v *Verifier, rules Rules) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authorize(ss.Context(), v, rules, info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &principalStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// StatusError - ошибка Require/Check как gRPC status
+func StatusError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func authorize(ctx context.Context, v *Verifier, rules Rules, method string) (context.Context, error) {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if vals := md.Get("authorization"); len(vals) > 0 && vals[0] != "" {
+			p, err := v.VerifyHeader(vals[0])
+			if err != nil {
+				return nil, StatusError(err)
+			}
+			ctx = WithPrincipal(ctx, p)
+		}
+	}
+	if scopes, ok := rules[method]; ok {
+		if err := Require(ctx, scopes...); err != nil {
+			return nil, StatusError(err)
+		}
+	}
+
+	return ctx, nil
+}
+
+type principalStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *principalStream) Context() context.Context { return s.ctx }
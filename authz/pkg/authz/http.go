@@ -0,0 +1,47 @@
+package authz
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// DenyFunc - как ответить на отказ; status 401 или 403. nil - JSON {"error": "..."}
+type DenyFunc func(w http.ResponseWriter, r *http.Request, status int, err error)
+
+// Middleware - если в запросе валидный токен, кладет Principal в контекст. Сам не отказывает:
+// публичные маршруты работают и без токена, отказ - в RequireScopes
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if p, err := v.FromRequest(r); err == nil {
+			r = r.WithContext(WithPrincipal(r.Context(), p))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireScopes - маршрут только для пользователей со всеми scopes (после Middleware)
+func RequireScopes(deny DenyFunc, scopes ...string) func(http.Handler) http.Handler {
+	if deny == nil {
+		deny = denyJSON
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := Require(r.Context(), scopes...); err != nil {
+				status := http.StatusForbidden
+				if errors.Is(err, ErrUnauthenticated) {
+					status = http.StatusUnauthorized
+				}
+				deny(w, r, status, err)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func denyJSON(w http.ResponseWriter, _ *http.Request, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,65 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated = errors.New("missing or invalid access token")
+	ErrForbidden       = errors.New("insufficient scope")
+)
+
+// Principal - кто делает запрос: из проверенного access-токена auth-service
+type Principal struct {
+	UserID int64
+	Roles  []string
+	Scopes []string
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+// Check - nil, если есть все scopes; иначе ErrForbidden с первым недостающим
+func (p *Principal) Check(scopes ...string) error {
+	if p == nil {
+		return ErrUnauthenticated
+	}
+	for _, s := range scopes {
+		if !p.HasScope(s) {
+			return fmt.Errorf("%w: %s required", ErrForbidden, s)
+		}
+	}
+
+	return nil
+}
+
+type principalKey struct{}
+
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// Require - для проверок внутри обработчиков: ErrUnauthenticated без Principal, ErrForbidden без scope
+func Require(ctx context.Context, scopes ...string) error {
+	p, _ := FromContext(ctx)
+	return p.Check(scopes...)
+}
+
+// parseScope - scope в токене одной строкой через пробел, как в OAuth 2.0
+func parseScope(scope string) []string {
+	return strings.Fields(scope)
+}
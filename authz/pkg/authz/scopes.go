@@ -0,0 +1,14 @@
+package authz
+
+// Права платформы. Роли и их права живут в auth-service (таблица roles),
+// в access-токен попадает объединение прав всех ролей пользователя (claim scope)
+const (
+	ScopeRoomsCreate = "rooms:create" // создавать комнаты
+	ScopeAdminUsers  = "admin:users"  // управлять пользователями
+)
+
+// KnownScopes - полный список прав; роль с правом не из списка auth-service не сохранит
+var KnownScopes = []string{
+	ScopeRoomsCreate,
+	ScopeAdminUsers,
+}
@@ -0,0 +1,101 @@
+package authz
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// TokenUseAccess - token_use access-токена платформы. Тем же ключом auth-service подписывает и токены
+// OAuth-клиентов, ID token, SSO-куку - у них другой token_use, и Verify их не принимает
+const TokenUseAccess = "access"
+
+// Claims - access-токен auth-service
+type Claims struct {
+	jwt.StandardClaims
+	TokenUse string   `json:"token_use"`
+	Roles    []string `json:"roles,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+}
+
+// Verifier - проверка access-токенов auth-service без похода в него: RS256 и публичный ключ
+type Verifier struct {
+	public    *rsa.PublicKey
+	issuer    string // обязательно совпадает с iss токена
+	audience  string // обязательно входит в aud токена
+	clockSkew time.Duration
+}
+
+// NewVerifier - issuer и audience проверяются всегда: с пустыми не пройдет ни один токен
+func NewVerifier(public *rsa.PublicKey, issuer, audience string, clockSkew time.Duration) *Verifier {
+	return &Verifier{public: public, issuer: issuer, audience: audience, clockSkew: clockSkew}
+}
+
+// NewVerifierFromPEM - публичный ключ из PEM-файла; issuer и audience обязательны
+func NewVerifierFromPEM(path, issuer, audience string, clockSkew time.Duration) (*Verifier, error) {
+	if issuer == "" || audience == "" {
+		return nil, errors.New("issuer and audience are required")
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %w", err)
+	}
+	pub, err := jwt.ParseRSAPublicKeyFromPEM(b)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	return NewVerifier(pub, issuer, audience, clockSkew), nil
+}
+
+// Verify - Principal из токена; любая проблема с токеном - ErrUnauthenticated
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		if t.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, ErrUnauthenticated
+		}
+		return v.public, nil
+	})
+	// exp/nbf проверяем сами, с допуском clockSkew, как auth-service
+	var ve *jwt.ValidationError
+	if err != nil && !(errors.As(err, &ve) && ve.Errors&^(jwt.ValidationErrorExpired|jwt.ValidationErrorNotValidYet) == 0) {
+		return nil, ErrUnauthenticated
+	}
+	now := time.Now()
+	if now.After(time.Unix(claims.ExpiresAt, 0).Add(v.clockSkew)) || now.Before(time.Unix(claims.NotBefore, 0).Add(-v.clockSkew)) {
+		return nil, ErrUnauthenticated
+	}
+	// без этих проверок прошел бы любой токен с ключом auth-service, например ID token OAuth-клиента
+	if claims.TokenUse != TokenUseAccess || !claims.VerifyIssuer(v.issuer, true) || !claims.VerifyAudience(v.audience, true) {
+		return nil, ErrUnauthenticated
+	}
+	id, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || id <= 0 {
+		return nil, ErrUnauthenticated
+	}
+
+	return &Principal{UserID: id, Roles: claims.Roles, Scopes: parseScope(claims.Scope)}, nil
+}
+
+// VerifyHeader - то же для значения заголовка "Authorization: Bearer <token>"
+func (v *Verifier) VerifyHeader(authorization string) (*Principal, error) {
+	parts := strings.SplitN(authorization, " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") || strings.TrimSpace(parts[1]) == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	return v.Verify(strings.TrimSpace(parts[1]))
+}
+
+// FromRequest - Principal из заголовка Authorization запроса
+func (v *Verifier) FromRequest(r *http.Request) (*Principal, error) {
+	return v.VerifyHeader(r.Header.Get("Authorization"))
+}
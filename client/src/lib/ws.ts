@@ -18,12 +18,9 @@ const ROOM_WS_BASE = (import.meta as any).env?.VITE_ROOM_WS_BASE?.replace(/\/+$/
 
 function buildDirectWsUrl(roomId: string) {
   const at = tokenVault.getAccessToken() || "";
-  const uid = tokenVault.getUserId();
   if (!ROOM_WS_BASE) throw new Error("VITE_ROOM_WS_BASE is not set");
-  const qs = new URLSearchParams({
-    access_token: at,
-    user_id: uid != null ? String(uid) : "",
-  }).toString();
+  // пользователя room-service берет из токена
+  const qs = new URLSearchParams({ access_token: at }).toString();
   return `${ROOM_WS_BASE}/ws/rooms/${encodeURIComponent(roomId)}?${qs}`;
 }
 
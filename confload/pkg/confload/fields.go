@@ -0,0 +1,276 @@
+package confload
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// field - лист конфига: скаляр или то, что задается только в YAML (settable == false)
+type field struct {
+	path     []string // имена из yaml-тегов: postgres, dsn
+	v        reflect.Value
+	def      string
+	env      []string // дополнительные имена переменных из тега env
+	secret   bool
+	required bool
+	settable bool
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	textType     = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func collect(v reflect.Value, path []string) []*field {
+	var out []*field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, inline, skip := yamlName(sf)
+		if skip {
+			continue
+		}
+		fv := v.Field(i)
+		p := path
+		if !inline {
+			p = append(append([]string(nil), path...), name)
+		}
+		if isNested(sf.Type) {
+			out = append(out, collect(fv, p)...)
+			continue
+		}
+		f := &field{
+			path:     p,
+			v:        fv,
+			def:      sf.Tag.Get("default"),
+			secret:   sf.Tag.Get("secret") == "true",
+			required: sf.Tag.Get("required") == "true",
+			settable: settable(sf.Type),
+		}
+		if e := sf.Tag.Get("env"); e != "" {
+			f.env = strings.Split(e, ",")
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func yamlName(sf reflect.StructField) (name string, inline, skip bool) {
+	tag := sf.Tag.Get("yaml")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	inline = strings.Contains(","+opts+",", ",inline,")
+	if name == "" {
+		name = strings.ToLower(sf.Name) // как yaml.v3
+	}
+	return name, inline, false
+}
+
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textType)
+}
+
+func settable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Slice:
+		return scalar(t.Elem())
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String && scalar(t.Elem())
+	default:
+		return scalar(t)
+	}
+}
+
+func scalar(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (f *field) key() string {
+	return strings.Join(f.path, ".")
+}
+
+func (f *field) envNames(prefix string) []string {
+	return append([]string{envName(prefix, f.path)}, f.env...)
+}
+
+func (f *field) sources(prefix string) string {
+	return "yaml " + f.key() + ", env " + envName(prefix, f.path) + " or -" + f.key()
+}
+
+// fromEnv - первая заданная переменная из envNames; <ИМЯ>_FILE читается из файла
+func (f *field) fromEnv(lookup func(string) (string, bool), prefix string) error {
+	for _, name := range f.envNames(prefix) {
+		val, ok := lookup(name)
+		file, fok := lookup(name + "_FILE")
+		fok = fok && file != ""
+		if ok && fok {
+			return fmt.Errorf("%s and %s_FILE are both set", name, name)
+		}
+		if fok {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("%s_FILE: %w", name, err)
+			}
+			// файлы секретов обычно заканчиваются переводом строки
+			val, ok = strings.TrimRight(string(b), "\r\n"), true
+			name += "_FILE"
+		}
+		if !ok {
+			continue
+		}
+		if err := f.set(val); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (f *field) set(s string) error {
+	t := f.v.Type()
+	switch t.Kind() {
+	case reflect.Slice:
+		if scalar(t) { // []byte и подобные с UnmarshalText
+			return setScalar(f.v, s)
+		}
+		parts := splitList(s)
+		out := reflect.MakeSlice(t, len(parts), len(parts))
+		for i, p := range parts {
+			if err := setScalar(out.Index(i), p); err != nil {
+				return err
+			}
+		}
+		f.v.Set(out)
+		return nil
+	case reflect.Map:
+		out := reflect.MakeMap(t)
+		for _, p := range splitList(s) {
+			k, val, ok := strings.Cut(p, "=")
+			if !ok {
+				return fmt.Errorf("%q: want key=value", p)
+			}
+			ev := reflect.New(t.Elem()).Elem()
+			if err := setScalar(ev, strings.TrimSpace(val)); err != nil {
+				return err
+			}
+			out.SetMapIndex(reflect.ValueOf(strings.TrimSpace(k)).Convert(t.Key()), ev)
+		}
+		f.v.Set(out)
+		return nil
+	default:
+		return setScalar(f.v, s)
+	}
+}
+
+func splitList(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	return parts
+}
+
+var errBadValue = errors.New("invalid value")
+
+func setScalar(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("%w %q: want duration like 30s, 5m", errBadValue, s)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("%w %q: want true|false", errBadValue, s)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w %q: want integer", errBadValue, s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w %q: want non-negative integer", errBadValue, s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%w %q: want number", errBadValue, s)
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// envName - AUTH + [security, jwt, accessTTL] -> AUTH_SECURITY_JWT_ACCESS_TTL
+func envName(prefix string, path []string) string {
+	parts := make([]string, 0, len(path)+1)
+	if prefix != "" {
+		parts = append(parts, strings.ToUpper(prefix))
+	}
+	for _, p := range path {
+		parts = append(parts, snake(p))
+	}
+	return strings.Join(parts, "_")
+}
+
+// snake - maxConnLifetime -> MAX_CONN_LIFETIME, roomGRPCTarget -> ROOM_GRPC_TARGET
+func snake(s string) string {
+	r := []rune(s)
+	var b strings.Builder
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			nextLower := i+1 < len(r) && unicode.IsLower(r[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		if c == '-' || c == '.' {
+			c = '_'
+		}
+		b.WriteRune(unicode.ToUpper(c))
+	}
+	return b.String()
+}
@@ -0,0 +1,264 @@
+package confload
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrPrinted - Load вывел справку (-help) или конфиг (-print-config), сервис должен просто выйти
+var ErrPrinted = errors.New("confload: printed, exit")
+
+type Options struct {
+	Name        string   // имя в справке, по умолчанию os.Args[0]
+	EnvPrefix   string   // AUTH -> AUTH_POSTGRES_DSN
+	DefaultPath string   // YAML, если путь не задан -config / <PREFIX>_CONFIG_PATH / CONFIG_PATH; может не существовать
+	Args        []string // обычно os.Args[1:]
+
+	LookupEnv func(string) (string, bool) // nil - os.LookupEnv
+	Output    io.Writer                   // куда писать -help и -print-config, nil - os.Stdout
+}
+
+// Validator - конфиг проверяет себя сам после всех источников. Можно менять поля (производные значения)
+type Validator interface {
+	Validate() error
+}
+
+// Load заполняет dst (указатель на структуру) по слоям, каждый следующий перекрывает предыдущий:
+//
+//  1. теги `default:"..."`;
+//  2. YAML-файл;
+//  3. переменные окружения: <PREFIX>_<ПУТЬ_В_YAML>, напр. AUTH_POSTGRES_DSN для postgres.dsn,
+//     плюс имена из тега `env:"A,B"`; <ИМЯ>_FILE - значение из файла (секреты из docker/k8s secrets);
+//  4. флаги: -postgres.dsn=...
+//
+// Поля с `required:"true"` должны оказаться непустыми. Ошибки всех слоев и Validate
+// возвращаются разом в Errors. Поля с `secret:"true"` в Redacted заменяются на ***.
+// Через env и флаги задаются скаляры, time.Duration, encoding.TextUnmarshaler,
+// слайсы скаляров ("a,b") и map[string]скаляр ("k=v,k2=v2"); остальное - только в YAML
+func Load(dst any, opts Options) error {
+	root := reflect.ValueOf(dst)
+	if root.Kind() != reflect.Pointer || root.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("confload: dst must be a pointer to struct, got %T", dst)
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	if opts.Name == "" && len(os.Args) > 0 {
+		opts.Name = os.Args[0]
+	}
+
+	fields := collect(root.Elem(), nil)
+	var errs Errors
+
+	for _, f := range fields {
+		if f.def == "" {
+			continue
+		}
+		if err := f.set(f.def); err != nil {
+			// ошибка в самом теге - баг в коде, а не в конфиге
+			return fmt.Errorf("confload: default of %s: %w", f.key(), err)
+		}
+	}
+
+	fs, path, printConfig, flagVals := newFlagSet(opts, fields)
+	if err := fs.Parse(opts.Args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ErrPrinted
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+
+	if err := loadYAML(dst, configPath(opts, *path)); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, f := range fields {
+		if !f.settable {
+			continue
+		}
+		if err := f.fromEnv(opts.LookupEnv, opts.EnvPrefix); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		f, ok := flagVals[fl.Name]
+		if !ok {
+			return
+		}
+		if err := f.set(fl.Value.String()); err != nil {
+			errs = append(errs, fmt.Errorf("-%s: %w", fl.Name, err))
+		}
+	})
+
+	for _, f := range fields {
+		if f.required && f.v.IsZero() {
+			errs = append(errs, fmt.Errorf("%s is required (%s)", f.key(), f.sources(opts.EnvPrefix)))
+		}
+	}
+
+	// Validate имеет смысл, только когда значения разобрались
+	if len(errs) == 0 {
+		if v, ok := dst.(Validator); ok {
+			errs = append(errs, v.Validate())
+		}
+	}
+
+	err := errs.Err()
+	if *printConfig {
+		if _, werr := io.WriteString(opts.Output, Redacted(dst)); werr != nil {
+			return werr
+		}
+		if err == nil {
+			return ErrPrinted
+		}
+	}
+
+	return err
+}
+
+func configPath(opts Options, fromFlag string) (p explicitPath) {
+	if fromFlag != "" {
+		return explicitPath{fromFlag, true}
+	}
+	for _, name := range []string{envName(opts.EnvPrefix, []string{"config", "path"}), "CONFIG_PATH"} {
+		if v, ok := opts.LookupEnv(name); ok && v != "" {
+			return explicitPath{v, true}
+		}
+	}
+	return explicitPath{opts.DefaultPath, false}
+}
+
+type explicitPath struct {
+	name     string
+	explicit bool // задан явно - файла нет, значит ошибка; путь по умолчанию может отсутствовать
+}
+
+func loadYAML(dst any, p explicitPath) error {
+	if p.name == "" {
+		return nil
+	}
+	f, err := os.Open(p.name)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !p.explicit {
+			return nil
+		}
+		return fmt.Errorf("read config: %w", err)
+	}
+	defer f.Close()
+
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true) // опечатка в ключе не должна молча превращаться в значение по умолчанию
+	if err := dec.Decode(dst); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	return nil
+}
+
+func newFlagSet(opts Options, fields []*field) (*flag.FlagSet, *string, *bool, map[string]*field) {
+	fs := flag.NewFlagSet(opts.Name, flag.ContinueOnError)
+	fs.SetOutput(opts.Output)
+	path := fs.String("config", "", "путь к YAML-конфигу (env "+envName(opts.EnvPrefix, []string{"config", "path"})+" или CONFIG_PATH)")
+	printConfig := fs.Bool("print-config", false, "напечатать итоговый конфиг (секреты скрыты) и выйти")
+
+	vals := make(map[string]*field, len(fields))
+	for _, f := range fields {
+		if !f.settable {
+			continue
+		}
+		name := f.key()
+		vals[name] = f
+		usage := "env " + strings.Join(f.envNames(opts.EnvPrefix), ", ")
+		if f.def != "" {
+			usage += ", по умолчанию " + f.def
+		}
+		if f.secret {
+			usage += " (секрет, можно <ENV>_FILE)"
+		}
+		fs.Var(&rawValue{bool: f.v.Kind() == reflect.Bool}, name, usage)
+	}
+
+	return fs, path, printConfig, vals
+}
+
+// rawValue - флаг запоминает строку, разбирается она после YAML и env, чтобы флаг побеждал
+type rawValue struct {
+	s    string
+	bool bool
+}
+
+func (r *rawValue) String() string     { return r.s }
+func (r *rawValue) Set(s string) error { r.s = s; return nil }
+func (r *rawValue) IsBoolFlag() bool   { return r.bool }
+
+// Errors - все ошибки конфигурации разом
+type Errors []error
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	b.WriteString("invalid config:")
+	for _, err := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e Errors) Unwrap() []error { return e }
+
+// Err - nil, если ошибок нет. Вложенные errors.Join и Errors раскладываются в плоский список
+func (e Errors) Err() error {
+	var flat Errors
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		if isJoined(err) {
+			for _, err := range err.(interface{ Unwrap() []error }).Unwrap() {
+				walk(err)
+			}
+			return
+		}
+		flat = append(flat, err)
+	}
+	for _, err := range e {
+		walk(err)
+	}
+	if len(flat) == 0 {
+		return nil
+	}
+	return flat
+}
+
+// isJoined - Errors или errors.Join; fmt.Errorf с несколькими %w не раскладываем, иначе потеряется его текст
+func isJoined(err error) bool {
+	if _, ok := err.(Errors); ok {
+		return true
+	}
+	j, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return false
+	}
+	var msgs []string
+	for _, e := range j.Unwrap() {
+		if e != nil {
+			msgs = append(msgs, e.Error())
+		}
+	}
+	return strings.Join(msgs, "\n") == err.Error()
+}
@@ -0,0 +1,120 @@
+package confload
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "***"
+
+// Redacted - итоговый конфиг в YAML, непустые поля с `secret:"true"` заменены на ***.
+// cfg не меняется: секреты скрываются в копии, включая структуры в слайсах и по указателям
+func Redacted(cfg any) string {
+	v := redactValue(reflect.ValueOf(cfg))
+	if !v.IsValid() {
+		return ""
+	}
+	b, err := yaml.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprintf("# config is not printable: %v\n", err)
+	}
+	return string(b)
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(redactValue(v.Elem()))
+		return out
+	case reflect.Slice:
+		if v.IsNil() || !hasSecrets(v.Type().Elem()) {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(redactValue(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() || !hasSecrets(v.Type().Elem()) {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		it := v.MapRange()
+		for it.Next() {
+			out.SetMapIndex(it.Key(), redactValue(it.Value()))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v) // неэкспортируемые поля как есть, yaml их все равно не печатает
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			fv := out.Field(i)
+			if sf.Tag.Get("secret") == "true" {
+				if !fv.IsZero() {
+					redactField(fv)
+				}
+				continue
+			}
+			fv.Set(redactValue(v.Field(i)))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// redactField - строку заменяем на ***, остальное (числа, слайсы) просто обнуляем
+func redactField(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(redacted)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				out.Index(i).SetString(redacted)
+			}
+			v.Set(out)
+			return
+		}
+		v.Set(reflect.Zero(v.Type()))
+	default:
+		v.Set(reflect.Zero(v.Type()))
+	}
+}
+
+// hasSecrets - есть ли в типе поля с тегом secret (чтобы не копировать большие слайсы зря)
+func hasSecrets(t reflect.Type) bool {
+	return hasSecretsSeen(t, map[reflect.Type]bool{})
+}
+
+func hasSecretsSeen(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+		return hasSecretsSeen(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if sf.IsExported() && (sf.Tag.Get("secret") == "true" || hasSecretsSeen(sf.Type, seen)) {
+				return true
+			}
+		}
+	}
+	return false
+}
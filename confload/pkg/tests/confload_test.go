@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/confload/pkg/confload"
+)
+
+type provider struct {
+	Name         string `yaml:"name"`
+	ClientSecret string `yaml:"clientSecret" secret:"true"`
+}
+
+type testConfig struct {
+	Server struct {
+		Addr            string        `yaml:"addr" default:":8080"`
+		ShutdownTimeout time.Duration `yaml:"shutdownTimeout" default:"10s"`
+	} `yaml:"server"`
+	Postgres struct {
+		DSN      string `yaml:"dsn" secret:"true" required:"true" env:"PG_DSN"`
+		MaxConns int32  `yaml:"maxConns" default:"4"`
+	} `yaml:"postgres"`
+	Debug     bool             `yaml:"debug"`
+	Origins   []string         `yaml:"origins"`
+	Plans     map[string]int64 `yaml:"plans"`
+	Providers []provider       `yaml:"providers"`
+
+	validate func() error
+}
+
+func (c *testConfig) Validate() error {
+	if c.validate != nil {
+		return c.validate()
+	}
+	return nil
+}
+
+func env(kv map[string]string) func(string) (string, bool) {
+	return func(k string) (string, bool) {
+		v, ok := kv[k]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestLoad_Layers(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+server:
+  addr: ":9000"
+postgres:
+  dsn: postgres://from-yaml
+  maxConns: 10
+origins: [a, b]
+`)
+	var cfg testConfig
+	err := confload.Load(&cfg, confload.Options{
+		EnvPrefix:   "SVC",
+		DefaultPath: path,
+		Args:        []string{"-postgres.maxConns=20", "-debug"},
+		LookupEnv: env(map[string]string{
+			"SVC_POSTGRES_DSN":       "postgres://from-env",
+			"SVC_POSTGRES_MAX_CONNS": "15",
+			"SVC_PLANS":              "free=10, pro=50",
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Server.Addr != ":9000" {
+		t.Errorf("addr = %q, want yaml :9000", cfg.Server.Addr)
+	}
+	if cfg.Server.ShutdownTimeout != 10*time.Second {
+		t.Errorf("shutdownTimeout = %v, want default 10s", cfg.Server.ShutdownTimeout)
+	}
+	if cfg.Postgres.DSN != "postgres://from-env" {
+		t.Errorf("dsn = %q, want env", cfg.Postgres.DSN)
+	}
+	if cfg.Postgres.MaxConns != 20 {
+		t.Errorf("maxConns = %d, want flag 20", cfg.Postgres.MaxConns)
+	}
+	if !cfg.Debug {
+		t.Error("debug flag is not applied")
+	}
+	if strings.Join(cfg.Origins, ",") != "a,b" {
+		t.Errorf("origins = %v", cfg.Origins)
+	}
+	if cfg.Plans["free"] != 10 || cfg.Plans["pro"] != 50 {
+		t.Errorf("plans = %v", cfg.Plans)
+	}
+}
+
+func TestLoad_SecretFromFile(t *testing.T) {
+	secret := writeFile(t, "dsn", "postgres://from-file\n")
+
+	var cfg testConfig
+	err := confload.Load(&cfg, confload.Options{
+		EnvPrefix: "SVC",
+		LookupEnv: env(map[string]string{"PG_DSN_FILE": secret}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Postgres.DSN != "postgres://from-file" {
+		t.Fatalf("dsn = %q, want value from PG_DSN_FILE without newline", cfg.Postgres.DSN)
+	}
+
+	// и значение, и файл - непонятно, что выбрать
+	err = confload.Load(&testConfig{}, confload.Options{
+		EnvPrefix: "SVC",
+		LookupEnv: env(map[string]string{"SVC_POSTGRES_DSN": "x", "SVC_POSTGRES_DSN_FILE": secret}),
+	})
+	if err == nil || !strings.Contains(err.Error(), "both set") {
+		t.Fatalf("err = %v, want both set", err)
+	}
+}
+
+func TestLoad_AggregatesErrors(t *testing.T) {
+	path := writeFile(t, "config.yaml", "server:\n  adr: typo\n")
+	errValidate := errors.New("webhooks.retryMax must be >= webhooks.retryBase")
+
+	cfg := testConfig{validate: func() error { return errValidate }}
+	err := confload.Load(&cfg, confload.Options{
+		EnvPrefix: "SVC",
+		Args:      []string{"-config", path},
+		LookupEnv: env(map[string]string{
+			"SVC_SERVER_SHUTDOWN_TIMEOUT": "soon",
+			"SVC_POSTGRES_MAX_CONNS":      "many",
+		}),
+	})
+	var list confload.Errors
+	if !errors.As(err, &list) {
+		t.Fatalf("err = %v, want confload.Errors", err)
+	}
+	// опечатка в yaml, две плохие переменные, нет dsn; Validate не зовется, пока значения не разобрались
+	if len(list) != 4 {
+		t.Fatalf("got %d errors, want 4:\n%v", len(list), err)
+	}
+	for _, want := range []string{"field adr not found", "SVC_SERVER_SHUTDOWN_TIMEOUT", "SVC_POSTGRES_MAX_CONNS", "postgres.dsn is required"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not mention %q:\n%v", want, err)
+		}
+	}
+
+	// ошибки Validate (в т.ч. errors.Join) попадают в общий список
+	cfg = testConfig{validate: func() error { return errors.Join(errValidate, errors.New("rooms.plans must contain default plan")) }}
+	err = confload.Load(&cfg, confload.Options{
+		EnvPrefix: "SVC",
+		LookupEnv: env(map[string]string{"PG_DSN": "postgres://x"}),
+	})
+	if !errors.As(err, &list) || len(list) != 2 || !errors.Is(err, errValidate) {
+		t.Fatalf("err = %v, want 2 validation errors", err)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	opts := confload.Options{
+		DefaultPath: filepath.Join(t.TempDir(), "nope.yaml"),
+		LookupEnv:   env(map[string]string{"PG_DSN": "postgres://x"}),
+	}
+	// файла по умолчанию может не быть - все придет из env
+	if err := confload.Load(&testConfig{}, opts); err != nil {
+		t.Fatalf("default path: %v", err)
+	}
+	// а явно указанный должен быть
+	opts.Args = []string{"-config", opts.DefaultPath}
+	if err := confload.Load(&testConfig{}, opts); err == nil {
+		t.Fatal("explicit missing config must fail")
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	var out bytes.Buffer
+	cfg := testConfig{Providers: []provider{{Name: "google", ClientSecret: "g-secret"}, {Name: "empty"}}}
+	err := confload.Load(&cfg, confload.Options{
+		Args:      []string{"-print-config"},
+		LookupEnv: env(map[string]string{"PG_DSN": "postgres://user:pass@db/app"}),
+		Output:    &out,
+	})
+	if !errors.Is(err, confload.ErrPrinted) {
+		t.Fatalf("err = %v, want ErrPrinted", err)
+	}
+
+	s := out.String()
+	if strings.Contains(s, "pass@db") || strings.Contains(s, "g-secret") {
+		t.Fatalf("secret leaked:\n%s", s)
+	}
+	for _, want := range []string{"dsn: '***'", "clientSecret: '***'", "clientSecret: \"\"", "shutdownTimeout: 10s", "addr: :8080"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("printed config has no %q:\n%s", want, s)
+		}
+	}
+	// сам конфиг не тронут
+	if cfg.Postgres.DSN != "postgres://user:pass@db/app" || cfg.Providers[0].ClientSecret != "g-secret" {
+		t.Fatalf("Redacted changed the config: %+v", cfg)
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	var out bytes.Buffer
+	err := confload.Load(&testConfig{}, confload.Options{
+		Name:      "svc",
+		EnvPrefix: "SVC",
+		Args:      []string{"-h"},
+		LookupEnv: env(nil),
+		Output:    &out,
+	})
+	if !errors.Is(err, confload.ErrPrinted) {
+		t.Fatalf("err = %v, want ErrPrinted", err)
+	}
+	for _, want := range []string{"-postgres.dsn", "SVC_POSTGRES_DSN, PG_DSN", "-server.shutdownTimeout", "SVC_SERVER_SHUTDOWN_TIMEOUT"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("help has no %q:\n%s", want, out.String())
+		}
+	}
+	if strings.Contains(out.String(), "-providers") {
+		t.Errorf("slice of structs is yaml-only, must not be a flag:\n%s", out.String())
+	}
+}
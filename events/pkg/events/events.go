@@ -0,0 +1,59 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrTypeMismatch = errors.New("events: type mismatch")
+
+// Event — доменное событие. Type — имя для подписчиков (room.created),
+// Key — сущность, к которой относится (room_id / user_id): по нему sink-и раскладывают события.
+type Event interface {
+	EventType() string
+	EventKey() string
+}
+
+// Envelope — событие в outbox и в sink-ах: тип + JSON-представление типизированной структуры.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Source     string          `json:"source"` // сервис-источник
+	Key        string          `json:"key,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// New — конверт с новым id; время — момент вызова.
+func New(source string, e Event) (Envelope, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("events: marshal %s: %w", e.EventType(), err)
+	}
+	return Envelope{
+		ID:         uuid.NewString(),
+		Type:       e.EventType(),
+		Source:     source,
+		Key:        e.EventKey(),
+		OccurredAt: time.Now().UTC(),
+		Payload:    payload,
+	}, nil
+}
+
+// Decode — типизированное событие из конверта.
+//
+//	ev, err := events.Decode[events.ParticipantJoined](env)
+func Decode[T Event](env Envelope) (T, error) {
+	var out T
+	if out.EventType() != env.Type {
+		return out, fmt.Errorf("%w: %s is not %s", ErrTypeMismatch, env.Type, out.EventType())
+	}
+	if err := json.Unmarshal(env.Payload, &out); err != nil {
+		return out, fmt.Errorf("events: unmarshal %s: %w", env.Type, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Sink — куда relay публикует события из outbox. Name хранится в outbox
+// (кому уже доставлено), поэтому должно быть стабильным между рестартами.
+// Доставка at-least-once: после сбоя Publish повторится, получателям стоит дедуплицировать по Envelope.ID.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, env Envelope) error
+}
+
+// Flusher — sink с буферизацией: relay зовёт Flush после пачки, до отметки о доставке.
+type Flusher interface {
+	Flush(ctx context.Context) error
+}
+
+// MemorySink — для тестов: складывает события в память.
+type MemorySink struct {
+	name string
+
+	mu     sync.Mutex
+	events []Envelope
+	err    error
+	notify chan struct{}
+}
+
+func NewMemorySink(name string) *MemorySink {
+	return &MemorySink{name: name, notify: make(chan struct{}, 1)}
+}
+
+func (s *MemorySink) Name() string { return s.name }
+
+func (s *MemorySink) Publish(_ context.Context, env Envelope) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.events = append(s.events, env)
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// FailWith — следующие Publish вернут err (nil — снова работать).
+func (s *MemorySink) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *MemorySink) Events() []Envelope {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.events)
+}
+
+// Wait — дождаться, пока наберётся n событий (или ctx).
+func (s *MemorySink) Wait(ctx context.Context, n int) ([]Envelope, error) {
+	for {
+		if evs := s.Events(); len(evs) >= n {
+			return evs, nil
+		}
+		select {
+		case <-s.notify:
+		case <-ctx.Done():
+			return s.Events(), ctx.Err()
+		}
+	}
+}
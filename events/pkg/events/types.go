@@ -0,0 +1,77 @@
+package events
+
+import (
+	"strconv"
+	"time"
+)
+
+// Типы событий. Имена совпадают с типами вебхуков.
+const (
+	TypeUserRegistered    = "user.registered"
+	TypeUserDeleted       = "user.deleted"
+	TypeRoomCreated       = "room.created"
+	TypeParticipantJoined = "participant.joined"
+	TypeParticipantLeft   = "participant.left"
+	TypeChatMessage       = "chat.message"
+)
+
+type UserRegistered struct {
+	UserID      int64     `json:"user_id"`
+	Email       string    `json:"email"`
+	DisplayName *string   `json:"display_name,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (UserRegistered) EventType() string  { return TypeUserRegistered }
+func (e UserRegistered) EventKey() string { return strconv.FormatInt(e.UserID, 10) }
+
+// UserDeleted — аккаунт удален (сам пользователь после отсрочки или админ). Строки с FK на users
+// к этому моменту уже удалены каскадом, сообщения чата обезличены (user_id = NULL).
+type UserDeleted struct {
+	UserID    int64     `json:"user_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+func (UserDeleted) EventType() string  { return TypeUserDeleted }
+func (e UserDeleted) EventKey() string { return strconv.FormatInt(e.UserID, 10) }
+
+type RoomCreated struct {
+	RoomID          string    `json:"room_id"`
+	Name            string    `json:"name"`
+	OwnerID         *int64    `json:"owner_id,omitempty"`
+	MaxParticipants int64     `json:"max_participants"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+func (RoomCreated) EventType() string  { return TypeRoomCreated }
+func (e RoomCreated) EventKey() string { return e.RoomID }
+
+type ParticipantJoined struct {
+	RoomID   string    `json:"room_id"`
+	UserID   int64     `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func (ParticipantJoined) EventType() string  { return TypeParticipantJoined }
+func (e ParticipantJoined) EventKey() string { return e.RoomID }
+
+type ParticipantLeft struct {
+	RoomID string    `json:"room_id"`
+	UserID int64     `json:"user_id"`
+	LeftAt time.Time `json:"left_at"`
+}
+
+func (ParticipantLeft) EventType() string  { return TypeParticipantLeft }
+func (e ParticipantLeft) EventKey() string { return e.RoomID }
+
+type ChatMessage struct {
+	MessageID   string    `json:"message_id"`
+	RoomID      string    `json:"room_id"`
+	UserID      int64     `json:"user_id"`
+	Text        string    `json:"text"`
+	Attachments []string  `json:"attachments,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (ChatMessage) EventType() string  { return TypeChatMessage }
+func (e ChatMessage) EventKey() string { return e.RoomID }
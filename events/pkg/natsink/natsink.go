@@ -0,0 +1,53 @@
+// Package natsink — sink relay-а, публикующий события в NATS. Отдельным пакетом,
+// чтобы сервисы, которые только пишут в outbox, не тянули клиента NATS.
+package natsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cwrk-planet/events/pkg/events"
+
+	"github.com/nats-io/nats.go"
+)
+
+// Sink — публикует конверт JSON-ом в subject "<prefix>.<type>" (cwrk.events.room.created),
+// заголовок Nats-Msg-Id = Envelope.ID — JetStream по нему отбрасывает повторы.
+type Sink struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+func New(conn *nats.Conn, prefix string) *Sink {
+	if prefix = strings.Trim(prefix, "."); prefix == "" {
+		prefix = "cwrk.events"
+	}
+	return &Sink{conn: conn, prefix: prefix}
+}
+
+func (s *Sink) Name() string { return "nats" }
+
+func (s *Sink) Subject(eventType string) string {
+	return s.prefix + "." + eventType
+}
+
+func (s *Sink) Publish(_ context.Context, env events.Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(s.Subject(env.Type))
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, env.ID)
+	if err := s.conn.PublishMsg(msg); err != nil {
+		return fmt.Errorf("nats publish %s: %w", msg.Subject, err)
+	}
+	return nil
+}
+
+// Flush — PublishMsg только буферизует; без подтверждения сервера отмечать доставку нельзя.
+func (s *Sink) Flush(ctx context.Context) error {
+	return s.conn.FlushWithContext(ctx)
+}
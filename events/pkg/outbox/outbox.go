@@ -0,0 +1,57 @@
+// Package outbox — transactional outbox: событие пишется в таблицу outbox той же транзакцией,
+// что и изменение состояния, а Relay потом доставляет его в sink-и (WS-хаб, вебхуки, NATS).
+// Таблица — миграция room-service 0014_outbox.sql.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/cwrk-planet/events/pkg/events"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer — pgx.Tx (или пул, если транзакции нет).
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Add — событие в outbox в рамках q.
+func Add(ctx context.Context, q Execer, source string, e events.Event) (events.Envelope, error) {
+	env, err := events.New(source, e)
+	if err != nil {
+		return events.Envelope{}, err
+	}
+	return env, Write(ctx, q, env)
+}
+
+func Write(ctx context.Context, q Execer, env events.Envelope) error {
+	_, err := q.Exec(ctx, `
+		INSERT INTO outbox (id, type, source, key, payload, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, env.ID, env.Type, env.Source, env.Key, []byte(env.Payload), env.OccurredAt)
+	return err
+}
+
+// Record — событие, захваченное relay-ем.
+type Record struct {
+	events.Envelope
+	Delivered []string // имена sink-ов, которым уже доставлено
+	Attempts  int
+}
+
+// Result — итог попытки по одной записи.
+type Result struct {
+	ID          string
+	Delivered   []string
+	Published   bool // доставлено во все sink-и
+	NextAttempt time.Time
+	LastError   string
+}
+
+type Store interface {
+	// Claim — забрать недоставленные события по порядку записи; lease — сколько они "в работе".
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error)
+	Save(ctx context.Context, res Result) error
+}
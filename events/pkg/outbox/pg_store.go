@@ -0,0 +1,85 @@
+package outbox
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PgStore struct {
+	db *pgxpool.Pool
+}
+
+func NewPgStore(db *pgxpool.Pool) *PgStore {
+	return &PgStore{db: db}
+}
+
+// Claim — SKIP LOCKED + lease: несколько инстансов relay не берут одно и то же.
+func (s *PgStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]Record, error) {
+	rows, err := s.db.Query(ctx, `
+		WITH due AS (
+		  SELECT seq
+		  FROM outbox
+		  WHERE published_at IS NULL AND next_attempt_at <= now()
+		  ORDER BY seq
+		  LIMIT $1
+		  FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due
+		WHERE o.seq = due.seq
+		RETURNING o.seq, o.id, o.type, o.source, o.key, o.payload, o.occurred_at, o.delivered_to, o.attempts
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type row struct {
+		seq int64
+		rec Record
+	}
+	var list []row
+	for rows.Next() {
+		var r row
+		var payload []byte
+		if err := rows.Scan(&r.seq, &r.rec.ID, &r.rec.Type, &r.rec.Source, &r.rec.Key, &payload,
+			&r.rec.OccurredAt, &r.rec.Delivered, &r.rec.Attempts); err != nil {
+			return nil, err
+		}
+		r.rec.Payload = payload
+		list = append(list, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING порядок не гарантирует
+	slices.SortFunc(list, func(a, b row) int { return cmp.Compare(a.seq, b.seq) })
+	out := make([]Record, len(list))
+	for i, r := range list {
+		out[i] = r.rec
+	}
+	return out, nil
+}
+
+func (s *PgStore) Save(ctx context.Context, res Result) error {
+	if res.Published {
+		_, err := s.db.Exec(ctx, `
+			UPDATE outbox
+			SET delivered_to=$2, published_at=now(), last_error=NULL
+			WHERE id=$1
+		`, res.ID, res.Delivered)
+		return err
+	}
+	_, err := s.db.Exec(ctx, `
+		UPDATE outbox
+		SET delivered_to=$2, attempts=attempts+1, next_attempt_at=$3, last_error=$4
+		WHERE id=$1
+	`, res.ID, res.Delivered, res.NextAttempt, res.LastError)
+	return err
+}
@@ -0,0 +1,161 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+const (
+	defaultBatch = 100
+	defaultLease = time.Minute
+	flushTimeout = 5 * time.Second
+	maxBackoff   = 5 * time.Minute
+)
+
+// Relay — переносит события из outbox в sink-и. Каждому sink-у событие доставляется
+// (at-least-once) независимо: упавший NATS не задерживает WS-хаб и не дублирует в него повторы.
+type Relay struct {
+	store Store
+	sinks []events.Sink
+	batch int
+	lease time.Duration
+	kick  chan struct{}
+	now   func() time.Time
+}
+
+func NewRelay(store Store, sinks ...events.Sink) *Relay {
+	return &Relay{
+		store: store,
+		sinks: sinks,
+		batch: defaultBatch,
+		lease: defaultLease,
+		kick:  make(chan struct{}, 1),
+		now:   time.Now,
+	}
+}
+
+// Kick — разбудить relay сразу после коммита, не дожидаясь тика (события хаба не должны ждать).
+func (r *Relay) Kick() {
+	select {
+	case r.kick <- struct{}{}:
+	default:
+	}
+}
+
+// Run — до отмены ctx: проход по тику every или по Kick.
+func (r *Relay) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-r.kick:
+		case <-ctx.Done():
+			return
+		}
+		for {
+			n, err := r.RunOnce(ctx)
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.Warn("outbox: relay pass failed", "err", err)
+				}
+				break
+			}
+			if n < r.batch {
+				break
+			}
+		}
+	}
+}
+
+// RunOnce — одна пачка; возвращает, сколько событий было захвачено.
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	recs, err := r.store.Claim(ctx, r.batch, r.lease)
+	if err != nil || len(recs) == 0 {
+		return 0, err
+	}
+
+	type state struct {
+		fresh []string // доставлено в этом проходе
+		errs  []string
+	}
+	st := make([]state, len(recs))
+	for i, rec := range recs {
+		for _, s := range r.sinks {
+			if slices.Contains(rec.Delivered, s.Name()) {
+				continue
+			}
+			if err := s.Publish(ctx, rec.Envelope); err != nil {
+				st[i].errs = append(st[i].errs, s.Name()+": "+err.Error())
+				continue
+			}
+			st[i].fresh = append(st[i].fresh, s.Name())
+		}
+	}
+
+	// буферизующие sink-и: не подтвердил flush — доставки в этом проходе не считаются
+	for _, s := range r.sinks {
+		f, ok := s.(events.Flusher)
+		if !ok {
+			continue
+		}
+		fctx, cancel := context.WithTimeout(ctx, flushTimeout)
+		err := f.Flush(fctx)
+		cancel()
+		if err == nil {
+			continue
+		}
+		for i := range st {
+			if j := slices.Index(st[i].fresh, s.Name()); j >= 0 {
+				st[i].fresh = slices.Delete(st[i].fresh, j, j+1)
+				st[i].errs = append(st[i].errs, s.Name()+": flush: "+err.Error())
+			}
+		}
+	}
+
+	now := r.now()
+	for i, rec := range recs {
+		res := Result{
+			ID:        rec.ID,
+			Delivered: append(slices.Clone(rec.Delivered), st[i].fresh...),
+		}
+		res.Published = r.allDelivered(res.Delivered)
+		if !res.Published {
+			res.NextAttempt = now.Add(backoff(rec.Attempts))
+			res.LastError = strings.Join(st[i].errs, "; ")
+			slog.Warn("outbox: delivery failed", "event", rec.ID, "type", rec.Type, "attempts", rec.Attempts+1, "err", res.LastError)
+		}
+		// записываем даже при отменённом ctx, иначе доставленное уйдёт повторно
+		sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), flushTimeout)
+		err := r.store.Save(sctx, res)
+		cancel()
+		if err != nil {
+			return len(recs), err
+		}
+	}
+	return len(recs), nil
+}
+
+func (r *Relay) allDelivered(delivered []string) bool {
+	for _, s := range r.sinks {
+		if !slices.Contains(delivered, s.Name()) {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff — 1s, 2s, 4s... до maxBackoff.
+func backoff(attempts int) time.Duration {
+	d := time.Second
+	for i := 0; i < attempts && d < maxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, maxBackoff)
+}
@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+func TestEnvelope_DecodeRoundTrip(t *testing.T) {
+	name := "Алиса"
+	in := events.UserRegistered{UserID: 42, Email: "a@example.com", DisplayName: &name, CreatedAt: time.Unix(1700000000, 0).UTC()}
+
+	env, err := events.New("auth-service", in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if env.ID == "" || env.Type != events.TypeUserRegistered || env.Source != "auth-service" || env.Key != "42" {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+
+	out, err := events.Decode[events.UserRegistered](env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.UserID != 42 || out.Email != in.Email || out.DisplayName == nil || *out.DisplayName != name || !out.CreatedAt.Equal(in.CreatedAt) {
+		t.Fatalf("decoded %+v, want %+v", out, in)
+	}
+
+	if _, err := events.Decode[events.RoomCreated](env); !errors.Is(err, events.ErrTypeMismatch) {
+		t.Fatalf("decode as other type: err = %v, want ErrTypeMismatch", err)
+	}
+}
+
+func TestMemorySink_WaitAndFail(t *testing.T) {
+	s := events.NewMemorySink("mem")
+	env, _ := events.New("room-service", events.ParticipantLeft{RoomID: "r-1", UserID: 7})
+
+	s.FailWith(errors.New("down"))
+	if err := s.Publish(context.Background(), env); err == nil {
+		t.Fatal("expected error from failing sink")
+	}
+	s.FailWith(nil)
+
+	go func() { _ = s.Publish(context.Background(), env) }()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	got, err := s.Wait(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != env.ID {
+		t.Fatalf("got %+v", got)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/natsink"
+	"github.com/cwrk-planet/events/pkg/outbox"
+
+	"github.com/nats-io/nats-server/v2/server"
+	natsserver "github.com/nats-io/nats-server/v2/test"
+	"github.com/nats-io/nats.go"
+)
+
+// startNATS — настоящий nats-server в процессе на случайном порту.
+func startNATS(t *testing.T) *server.Server {
+	t.Helper()
+	opts := natsserver.DefaultTestOptions
+	opts.Port = -1
+	srv := natsserver.RunServer(&opts)
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func TestNATSSink_PublishesEnvelope(t *testing.T) {
+	srv := startNATS(t)
+
+	sub, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+	msgs := make(chan *nats.Msg, 4)
+	if _, err := sub.ChanSubscribe("cwrk.events.>", msgs); err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pub.Close()
+	sink := natsink.New(pub, "")
+
+	env := mustEnv(t, events.ParticipantJoined{RoomID: "r-1", UserID: 7, JoinedAt: time.Unix(1700000000, 0).UTC()})
+	store := newMemStore(env)
+	if _, err := outbox.NewRelay(store, sink).RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.get(env.ID).published {
+		t.Fatalf("event not published: %+v", store.get(env.ID))
+	}
+
+	select {
+	case m := <-msgs:
+		if m.Subject != "cwrk.events.participant.joined" {
+			t.Fatalf("subject = %q", m.Subject)
+		}
+		if got := m.Header.Get(nats.MsgIdHdr); got != env.ID {
+			t.Fatalf("%s = %q, want %q", nats.MsgIdHdr, got, env.ID)
+		}
+		var got events.Envelope
+		if err := json.Unmarshal(m.Data, &got); err != nil {
+			t.Fatal(err)
+		}
+		ev, err := events.Decode[events.ParticipantJoined](got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev.RoomID != "r-1" || ev.UserID != 7 {
+			t.Fatalf("decoded %+v", ev)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestNATSSink_ClosedConnectionFails(t *testing.T) {
+	srv := startNATS(t)
+	nc, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	nc.Close()
+
+	env := mustEnv(t, events.RoomCreated{RoomID: "r-1", Name: "demo"})
+	store := newMemStore(env)
+	mem := events.NewMemorySink("mem")
+	if _, err := outbox.NewRelay(store, mem, natsink.New(nc, "")).RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r := store.get(env.ID)
+	if r.published || len(r.rec.Delivered) != 1 || r.rec.Delivered[0] != "mem" {
+		t.Fatalf("nats failure must keep event pending for nats only: %+v", r)
+	}
+}
@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
+)
+
+// memStore — outbox в памяти: Claim отдаёт неопубликованные записи, у которых подошло время.
+type memStore struct {
+	mu   sync.Mutex
+	now  time.Time
+	recs []*storeRec
+}
+
+type storeRec struct {
+	rec       outbox.Record
+	next      time.Time
+	published bool
+	lastErr   string
+}
+
+func newMemStore(envs ...events.Envelope) *memStore {
+	s := &memStore{now: time.Now()}
+	for _, env := range envs {
+		s.recs = append(s.recs, &storeRec{rec: outbox.Record{Envelope: env}})
+	}
+	return s
+}
+
+func (s *memStore) Claim(_ context.Context, limit int, lease time.Duration) ([]outbox.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []outbox.Record
+	for _, r := range s.recs {
+		if len(out) == limit {
+			break
+		}
+		if r.published || r.next.After(s.now) {
+			continue
+		}
+		r.next = s.now.Add(lease)
+		rec := r.rec
+		rec.Delivered = slices.Clone(r.rec.Delivered)
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+func (s *memStore) Save(_ context.Context, res outbox.Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.recs {
+		if r.rec.ID != res.ID {
+			continue
+		}
+		r.rec.Delivered = res.Delivered
+		r.published = res.Published
+		if !res.Published {
+			r.rec.Attempts++
+			r.next = res.NextAttempt
+			r.lastErr = res.LastError
+		}
+		return nil
+	}
+	return errors.New("unknown record")
+}
+
+// advance — сдвинуть часы стора (чтобы подошло время повтора).
+func (s *memStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+func (s *memStore) get(id string) storeRec {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.recs {
+		if r.rec.ID == id {
+			return *r
+		}
+	}
+	return storeRec{}
+}
+
+func mustEnv(t *testing.T, e events.Event) events.Envelope {
+	t.Helper()
+	env, err := events.New("room-service", e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return env
+}
+
+func TestRelay_PublishesToAllSinksInOrder(t *testing.T) {
+	e1 := mustEnv(t, events.RoomCreated{RoomID: "r-1", Name: "demo"})
+	e2 := mustEnv(t, events.ParticipantJoined{RoomID: "r-1", UserID: 7})
+	store := newMemStore(e1, e2)
+	a, b := events.NewMemorySink("a"), events.NewMemorySink("b")
+
+	n, err := outbox.NewRelay(store, a, b).RunOnce(context.Background())
+	if err != nil || n != 2 {
+		t.Fatalf("RunOnce = %d, %v", n, err)
+	}
+	for _, s := range []*events.MemorySink{a, b} {
+		got := s.Events()
+		if len(got) != 2 || got[0].ID != e1.ID || got[1].ID != e2.ID {
+			t.Fatalf("sink %s got %+v", s.Name(), got)
+		}
+	}
+	if !store.get(e1.ID).published || !store.get(e2.ID).published {
+		t.Fatal("events must be marked published")
+	}
+
+	// второй проход — нечего отдавать
+	if n, _ := outbox.NewRelay(store, a, b).RunOnce(context.Background()); n != 0 {
+		t.Fatalf("second pass claimed %d", n)
+	}
+}
+
+func TestRelay_RetriesOnlyFailedSink(t *testing.T) {
+	env := mustEnv(t, events.ChatMessage{MessageID: "m-1", RoomID: "r-1", UserID: 7, Text: "привет"})
+	store := newMemStore(env)
+	ok, flaky := events.NewMemorySink("ok"), events.NewMemorySink("flaky")
+	relay := outbox.NewRelay(store, ok, flaky)
+
+	flaky.FailWith(errors.New("broker down"))
+	before := time.Now()
+	if _, err := relay.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r := store.get(env.ID)
+	if r.published || r.rec.Attempts != 1 || !slices.Equal(r.rec.Delivered, []string{"ok"}) {
+		t.Fatalf("after failure: %+v", r)
+	}
+	if !strings.Contains(r.lastErr, "flaky") || !strings.Contains(r.lastErr, "broker down") {
+		t.Fatalf("last error = %q", r.lastErr)
+	}
+	if wait := r.next.Sub(before); wait < time.Second || wait > 2*time.Second {
+		t.Fatalf("first retry in %v, want ~1s", wait)
+	}
+
+	// время повтора ещё не пришло
+	if n, _ := relay.RunOnce(context.Background()); n != 0 {
+		t.Fatalf("claimed %d before backoff", n)
+	}
+
+	flaky.FailWith(nil)
+	store.advance(time.Minute)
+	if _, err := relay.RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !store.get(env.ID).published {
+		t.Fatal("event must be published after retry")
+	}
+	if len(ok.Events()) != 1 {
+		t.Fatalf("healthy sink got duplicates: %d", len(ok.Events()))
+	}
+	if len(flaky.Events()) != 1 {
+		t.Fatalf("flaky sink got %d events", len(flaky.Events()))
+	}
+}
+
+// flushSink — буферизующий sink, у которого не проходит Flush.
+type flushSink struct {
+	*events.MemorySink
+	err error
+}
+
+func (s *flushSink) Flush(context.Context) error { return s.err }
+
+func TestRelay_FailedFlushKeepsEventPending(t *testing.T) {
+	env := mustEnv(t, events.ParticipantLeft{RoomID: "r-1", UserID: 7})
+	store := newMemStore(env)
+	sink := &flushSink{MemorySink: events.NewMemorySink("buffered"), err: errors.New("no ack")}
+
+	if _, err := outbox.NewRelay(store, sink).RunOnce(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	r := store.get(env.ID)
+	if r.published || len(r.rec.Delivered) != 0 || !strings.Contains(r.lastErr, "flush") {
+		t.Fatalf("unacked delivery must not count: %+v", r)
+	}
+}
+
+func TestRelay_RunOnKick(t *testing.T) {
+	env := mustEnv(t, events.RoomCreated{RoomID: "r-2", Name: "kick"})
+	store := newMemStore(env)
+	sink := events.NewMemorySink("mem")
+	relay := outbox.NewRelay(store, sink)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go relay.Run(ctx, time.Hour)
+	relay.Kick()
+
+	if _, err := sink.Wait(ctx, 1); err != nil {
+		t.Fatalf("relay did not wake up on Kick: %v", err)
+	}
+}
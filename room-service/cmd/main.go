@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"log/slog"
 	"net"
@@ -10,21 +11,32 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // часовые пояса расписаний не должны зависеть от образа
 
+	"github.com/cwrk-planet/authz/pkg/authz"
+	"github.com/cwrk-planet/confload/pkg/confload"
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/natsink"
+	"github.com/cwrk-planet/events/pkg/outbox"
 	"github.com/cwrk-planet/logger/pkg/logger"
 	"github.com/cwrk-planet/room-service/config"
+	"github.com/cwrk-planet/room-service/internal/domain"
 	"github.com/cwrk-planet/room-service/internal/postgres"
 	"github.com/cwrk-planet/room-service/internal/service"
 	grpcx "github.com/cwrk-planet/room-service/internal/transport/grpc"
 	httpx "github.com/cwrk-planet/room-service/internal/transport/http"
 	"github.com/cwrk-planet/room-service/internal/transport/ws"
 
+	"github.com/nats-io/nats.go"
 	"google.golang.org/grpc"
 )
 
 func main() {
 	// --- config ---
-	cfg, err := config.LoadConfig()
+	cfg, err := config.LoadConfig(os.Args[1:])
+	if errors.Is(err, confload.ErrPrinted) {
+		os.Exit(0)
+	}
 	if err != nil {
 		log.Fatalf("load config: %v", err)
 	}
@@ -39,6 +51,7 @@ func main() {
 	})
 	slog.Info("starting room-service",
 		"env", cfg.Logging.Env, "version", cfg.Logging.Version)
+	slog.Info("effective config", "config", confload.Redacted(cfg)) // секреты заменены на ***
 
 	// --- postgres ---
 	ctx := context.Background()
@@ -52,19 +65,119 @@ func main() {
 	roomRepo := postgres.NewRoomRepository(db.Pool)
 	partRepo := postgres.NewParticipantRepository(db.Pool)
 	chatRepo := postgres.NewChatRepository(db.Pool)
+	attachmentRepo := postgres.NewAttachmentRepository(db.Pool)
+	readRepo := postgres.NewReadMarkerRepository(db.Pool)
+	presenceRepo := postgres.NewPresenceRepository(db.Pool)
+	handRepo := postgres.NewHandQueueRepository(db.Pool)
+	pollRepo := postgres.NewPollRepository(db.Pool)
+	scheduleRepo := postgres.NewScheduleRepository(db.Pool)
+	feedRepo := postgres.NewCalendarFeedRepository(db.Pool)
+	lobbyRepo := postgres.NewLobbyRepository(db.Pool)
+	breakoutRepo := postgres.NewBreakoutRepository(db.Pool)
+	planRepo := postgres.NewPlanRepository(db.Pool)
+	webhookRepo := postgres.NewWebhookRepository(db.Pool)
 
 	// --- services ---
-	roomSvc := service.NewRoomService(roomRepo)
+	roomSvc := service.NewRoomService(roomRepo, planRepo, domain.CapacityLimits{
+		Default:     cfg.Rooms.DefaultMax,
+		DefaultPlan: cfg.Rooms.DefaultPlan,
+		Plans:       cfg.Rooms.Plans,
+	})
 	memberSvc := service.NewMemberService(roomRepo, partRepo)
-	chatSvc := service.NewChatService(chatRepo)
+	chatSvc := service.NewChatService(chatRepo, readRepo)
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, partRepo, cfg.Attachments.RoomQuotaBytes)
+	presenceSvc := service.NewPresenceService(presenceRepo)
+	memberSvc.SetPresence(presenceSvc)
+	handSvc := service.NewHandQueueService(handRepo, roomRepo)
+	pollSvc := service.NewPollService(pollRepo, roomRepo)
+	scheduleSvc := service.NewScheduleService(scheduleRepo, roomRepo)
+	scheduleSvc.SetFeeds(feedRepo)
+	memberSvc.SetSchedule(scheduleSvc)
+	lobbySvc := service.NewLobbyService(lobbyRepo, roomRepo, partRepo)
+	memberSvc.SetLobby(lobbySvc)
+	breakoutSvc := service.NewBreakoutService(breakoutRepo, roomRepo, partRepo)
+	webhookSvc := service.NewWebhookService(
+		webhookRepo,
+		roomRepo,
+		service.NewWebhookSender(cfg.Webhooks.Timeout, cfg.Webhooks.AllowPrivateTargets),
+		domain.WebhookRetry{
+			Base:        cfg.Webhooks.RetryBase,
+			Max:         cfg.Webhooks.RetryMax,
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+		},
+		cfg.Webhooks.SystemOwners,
+	)
+
+	presenceCtx, stopPresence := context.WithCancel(ctx)
+	presenceDone := make(chan struct{})
+	go func() {
+		defer close(presenceDone)
+		presenceSvc.Run(presenceCtx, cfg.Presence.SnapshotInterval)
+	}()
 
 	// --- WS Hub & Server ---
 	hub := ws.NewHub()
-	wsServer := ws.NewServer(hub, memberSvc, chatSvc)
+	wsServer := ws.NewServer(hub, memberSvc, chatSvc, presenceSvc, handSvc, pollSvc, lobbySvc, breakoutSvc)
+	handSvc.SetOnChange(wsServer.BroadcastHandQueue)
+	if err := handSvc.Restore(ctx); err != nil {
+		log.Fatalf("restore speaking timers: %v", err)
+	}
+	pollSvc.SetOnClose(wsServer.BroadcastPollClosed)
+	if err := pollSvc.Restore(ctx); err != nil {
+		log.Fatalf("restore poll timers: %v", err)
+	}
+	lobbySvc.SetOnChange(wsServer.LobbyChanged)
+	breakoutSvc.SetOnChange(wsServer.BreakoutChanged)
+	if err := breakoutSvc.Restore(ctx); err != nil {
+		log.Fatalf("restore breakout timers: %v", err)
+	}
+	scheduleSvc.SetOnChange(func(roomID string, open bool, sess domain.Session) {
+		if !open {
+			// занятие кончилось: следующее снова начнётся через лобби
+			if err := lobbySvc.ResetSession(ctx, roomID); err != nil {
+				slog.Warn("lobby reset failed", "room", roomID, "err", err)
+			}
+		}
+		wsServer.SessionWindowChanged(roomID, open, sess)
+	})
+	scheduleCtx, stopSchedule := context.WithCancel(ctx)
+	defer stopSchedule()
+	go scheduleSvc.Run(scheduleCtx, cfg.Schedule.CheckInterval)
+
+	webhookCtx, stopWebhooks := context.WithCancel(ctx)
+	defer stopWebhooks()
+	go webhookSvc.Run(webhookCtx, cfg.Webhooks.DispatchInterval)
+
+	// --- events: outbox -> WS-хаб, вебхуки, NATS ---
+	sinks := []events.Sink{ws.NewHubSink(hub), webhookSvc}
+	if cfg.Events.NATS.URL != "" {
+		nc, err := nats.Connect(cfg.Events.NATS.URL, nats.Name("room-service"), nats.MaxReconnects(-1))
+		if err != nil {
+			log.Fatalf("nats: %v", err)
+		}
+		defer nc.Close()
+		sinks = append(sinks, natsink.New(nc, cfg.Events.NATS.SubjectPrefix))
+	}
+	relay := outbox.NewRelay(outbox.NewPgStore(db.Pool), sinks...)
+	relayCtx, stopRelay := context.WithCancel(ctx)
+	defer stopRelay()
+	go relay.Run(relayCtx, cfg.Events.RelayInterval)
+
+	// --- authz: подпись access-токена, роли и scopes ---
+	var verifier *authz.Verifier
+	if cfg.Auth.PublicKeyPath != "" {
+		verifier, err = authz.NewVerifierFromPEM(cfg.Auth.PublicKeyPath, cfg.Auth.Issuer, cfg.Auth.Audience, cfg.Auth.ClockSkew)
+		if err != nil {
+			log.Fatalf("authz: %v", err)
+		}
+	} else {
+		slog.Warn("auth.publicKeyPath is empty: access tokens are not verified, scopes are not enforced, WS and WatchRoom are closed")
+	}
+	wsServer.SetVerifier(verifier)
 
 	// --- HTTP ---
 	handler := httpx.NewHandler(roomSvc, memberSvc, chatSvc)
-	router := httpx.NewRouter(handler, memberSvc, wsServer)
+	router := httpx.NewRouter(handler, memberSvc, wsServer, verifier)
 	httpSrv := &http.Server{
 		Addr:         cfg.HTTP.Addr,
 		Handler:      router,
@@ -74,11 +187,18 @@ func main() {
 	}
 
 	// --- gRPC ---
+	unary := []grpc.UnaryServerInterceptor{grpcx.UnaryServerInterceptor()}
+	// стримы (WatchRoom) проверяют bearer сами и без verifier закрыты
+	stream := []grpc.StreamServerInterceptor{grpcx.StreamServerInterceptor(verifier)}
+	if verifier != nil {
+		unary = append(unary, authz.UnaryServerInterceptor(verifier, grpcx.ScopeRules))
+	}
 	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(grpcx.UnaryServerInterceptor()),
-		grpc.ChainStreamInterceptor(grpcx.StreamServerInterceptor()),
+		grpc.ChainUnaryInterceptor(unary...),
+		grpc.ChainStreamInterceptor(stream...),
 	)
-	grpcSrv := grpcx.NewServer(roomSvc, memberSvc, chatSvc)
+	grpcSrv := grpcx.NewServer(roomSvc, memberSvc, chatSvc, attachmentSvc, pollSvc, scheduleSvc, lobbySvc, breakoutSvc, webhookSvc)
+	grpcSrv.SetWatch(hub, memberSvc, chatSvc)
 	grpcx.Register(grpcServer, grpcSrv)
 
 	// --- run both servers ---
@@ -119,5 +239,9 @@ func main() {
 
 	grpcServer.GracefulStop()
 	_ = httpSrv.Shutdown(ctxShutdown)
+
+	// финальный снапшот presence
+	stopPresence()
+	<-presenceDone
 	slog.Info("stopped")
 }
@@ -2,89 +2,163 @@ package config
 
 import (
 	"errors"
-	"os"
+	"fmt"
 	"time"
 
-	"gopkg.in/yaml.v3"
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/cwrk-planet/confload/pkg/confload"
 )
 
 type GRPC struct {
-	Addr string `yaml:"addr"`
+	Addr string `yaml:"addr" default:":9092"`
 }
 
 type HTTP struct {
-	Addr string `yaml:"addr"`
+	Addr string `yaml:"addr" default:":8082"`
 }
 
 type Logging struct {
-	Env       string `yaml:"env"`       // dev|prod
-	Service   string `yaml:"service"`   // room-service
-	Version   string `yaml:"version"`   // v0.1.0
-	Backend   string `yaml:"backend"`   // std|zap
-	AddSource bool   `yaml:"addSource"` // false|true
-	Debug     bool   `yaml:"debug"`     // false|true
+	Env       string `yaml:"env" default:"dev"`              // dev|prod
+	Service   string `yaml:"service" default:"room-service"` // room-service
+	Version   string `yaml:"version" default:"v0.1.0"`       // v0.1.0
+	Backend   string `yaml:"backend" default:"std"`          // std|zap
+	AddSource bool   `yaml:"addSource"`                      // false|true
+	Debug     bool   `yaml:"debug"`                          // false|true
 }
 
 type Postgres struct {
-	DSN string `yaml:"dsn"`
+	DSN string `yaml:"dsn" secret:"true" required:"true" env:"PG_DSN"` // лучше из env: ROOM_POSTGRES_DSN(_FILE) или PG_DSN
+}
+
+type Attachments struct {
+	RoomQuotaBytes int64 `yaml:"roomQuotaBytes"` // суммарный объём вложений на комнату, 0 — без лимита
+}
+
+type Presence struct {
+	SnapshotInterval time.Duration `yaml:"snapshotInterval" default:"10s"` // как часто писать presence в БД, по умолчанию 10s
+}
+
+type Schedule struct {
+	CheckInterval time.Duration `yaml:"checkInterval" default:"30s"` // как часто сверять окна занятий, по умолчанию 30s
+}
+
+// Rooms — лимиты размера комнат по тарифам (тариф пользователя — в таблице user_plans).
+type Rooms struct {
+	DefaultMax  int64            `yaml:"defaultMax" default:"10"`    // если max не указан, по умолчанию 10
+	DefaultPlan string           `yaml:"defaultPlan" default:"free"` // у кого нет записи в user_plans, по умолчанию free
+	Plans       map[string]int64 `yaml:"plans"`                      // тариф -> максимальный max_participants
+}
+
+// Webhooks — исходящие вебхуки (см. WebhookService).
+type Webhooks struct {
+	DispatchInterval    time.Duration `yaml:"dispatchInterval" default:"2s"` // проход диспетчера, по умолчанию 2s
+	Timeout             time.Duration `yaml:"timeout" default:"10s"`         // на один запрос к получателю, по умолчанию 10s
+	RetryBase           time.Duration `yaml:"retryBase" default:"30s"`       // первая пауза между попытками, по умолчанию 30s
+	RetryMax            time.Duration `yaml:"retryMax" default:"6h"`         // потолок паузы, по умолчанию 6h
+	MaxAttempts         int           `yaml:"maxAttempts" default:"10"`      // после стольких неудач — dead, по умолчанию 10
+	AllowPrivateTargets bool          `yaml:"allowPrivateTargets"`           // разрешить адреса внутренней сети (для локальной разработки)
+	SystemOwners        []int64       `yaml:"systemOwners"`                  // кто может подписаться на системные события (user.registered, user.deleted)
+}
+
+// Events — relay доменных событий из outbox (WS-хаб, вебхуки, NATS).
+type Events struct {
+	RelayInterval time.Duration `yaml:"relayInterval" default:"200ms"` // как часто забирать outbox, по умолчанию 200ms
+	NATS          NATS          `yaml:"nats"`
+}
+
+type NATS struct {
+	URL           string `yaml:"url" secret:"true"`                   // пусто — NATS не используется; может содержать логин/токен
+	SubjectPrefix string `yaml:"subjectPrefix" default:"cwrk.events"` // по умолчанию cwrk.events
+}
+
+// Auth — проверка access-токенов auth-service: подпись, роли и scopes.
+type Auth struct {
+	PublicKeyPath string        `yaml:"publicKeyPath"`           // пусто — токен не проверяется, user_id берем из x-user-id (как раньше)
+	Issuer        string        `yaml:"issuer"`                  // = security.jwt.issuer auth-service, обязательно вместе с ключом
+	Audience      string        `yaml:"audience"`                // = security.jwt.audience auth-service, обязательно вместе с ключом
+	ClockSkew     time.Duration `yaml:"clockSkew" default:"30s"` // по умолчанию 30s
 }
 
 type Config struct {
-	HTTP     HTTP     `yaml:"http"`
-	GRPC     GRPC     `yaml:"grpc"`
-	Logging  Logging  `yaml:"logging"`
-	Postgres Postgres `yaml:"postgres"`
+	HTTP        HTTP        `yaml:"http"`
+	GRPC        GRPC        `yaml:"grpc"`
+	Logging     Logging     `yaml:"logging"`
+	Postgres    Postgres    `yaml:"postgres"`
+	Attachments Attachments `yaml:"attachments"`
+	Presence    Presence    `yaml:"presence"`
+	Schedule    Schedule    `yaml:"schedule"`
+	Rooms       Rooms       `yaml:"rooms"`
+	Webhooks    Webhooks    `yaml:"webhooks"`
+	Events      Events      `yaml:"events"`
+	Auth        Auth        `yaml:"auth"`
 }
 
-func LoadConfig() (*Config, error) {
-	path := os.Getenv("CONFIG_PATH")
-	if path == "" {
-		path = "./config/config.yaml"
-	}
-	data, err := os.ReadFile(path)
-	if err != nil {
-		return nil, err
-	}
+// LoadConfig — defaults из тегов, ./config/config.yaml (если есть), env ROOM_*, флаги из args; см. confload.
+func LoadConfig(args []string) (*Config, error) {
 	var cfg Config
-	if err := yaml.Unmarshal(data, &cfg); err != nil {
-		return nil, err
-	}
-	if err := cfg.validate(); err != nil {
+	if err := confload.Load(&cfg, confload.Options{
+		Name:        "room-service",
+		EnvPrefix:   "ROOM",
+		DefaultPath: "./config/config.yaml",
+		Args:        args,
+	}); err != nil {
 		return nil, err
 	}
 	return &cfg, nil
 }
 
-func (c *Config) validate() error {
+// Validate вызывается из LoadConfig после всех источников; ошибки собираются все разом.
+func (c *Config) Validate() error {
+	var errs []error
 	if c.HTTP.Addr == "" {
-		return errors.New("http.addr is required")
+		errs = append(errs, errors.New("http.addr is required"))
 	}
 	if c.GRPC.Addr == "" {
-		return errors.New("grpc.addr is required")
+		errs = append(errs, errors.New("grpc.addr is required"))
 	}
-	if c.Postgres.DSN == "" {
-		return errors.New("postgres.dsn is required")
+	// раньше нули заменялись дефолтами; теперь дефолты в тегах, а явный 0 — ошибка (тикер с 0 паникует)
+	for _, d := range []struct {
+		name string
+		v    time.Duration
+	}{
+		{"presence.snapshotInterval", c.Presence.SnapshotInterval},
+		{"schedule.checkInterval", c.Schedule.CheckInterval},
+		{"webhooks.dispatchInterval", c.Webhooks.DispatchInterval},
+		{"webhooks.timeout", c.Webhooks.Timeout},
+		{"webhooks.retryBase", c.Webhooks.RetryBase},
+		{"events.relayInterval", c.Events.RelayInterval},
+		{"auth.clockSkew", c.Auth.ClockSkew},
+	} {
+		if d.v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be > 0", d.name))
+		}
 	}
-	// установка дефолтов, если значения не указаны
-	if c.Logging.Service == "" {
-		c.Logging.Service = "room-service"
+	if c.Auth.PublicKeyPath != "" && (c.Auth.Issuer == "" || c.Auth.Audience == "") {
+		errs = append(errs, errors.New("auth.issuer and auth.audience are required with auth.publicKeyPath"))
 	}
-	if c.Logging.Env == "" {
-		c.Logging.Env = "dev"
+	if c.Webhooks.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("webhooks.maxAttempts must be > 0"))
 	}
-	if c.Logging.Version == "" {
-		c.Logging.Version = "v0.1.0"
+	if c.Rooms.DefaultMax <= 0 {
+		errs = append(errs, errors.New("rooms.defaultMax must be > 0"))
 	}
-	if c.Logging.Backend == "" {
-		c.Logging.Backend = "std"
+	if c.Attachments.RoomQuotaBytes < 0 {
+		errs = append(errs, errors.New("attachments.roomQuotaBytes must be >= 0"))
 	}
-	return nil
-}
-
-// helper для парсинга timeout-ов
-func parseDurationOr(def time.Duration, s string) time.Duration {
-	if d, err := time.ParseDuration(s); err == nil && d > 0 {
-		return d
+	if len(c.Rooms.Plans) == 0 {
+		c.Rooms.Plans = map[string]int64{c.Rooms.DefaultPlan: c.Rooms.DefaultMax}
+	}
+	if _, ok := c.Rooms.Plans[c.Rooms.DefaultPlan]; !ok {
+		errs = append(errs, fmt.Errorf("rooms.plans must contain default plan %q", c.Rooms.DefaultPlan))
+	}
+	for plan, n := range c.Rooms.Plans {
+		if n < 1 || n > domain.MaxRoomCapacity {
+			errs = append(errs, fmt.Errorf("rooms.plans.%s must be in [1..%d]", plan, domain.MaxRoomCapacity))
+		}
+	}
+	if c.Webhooks.RetryMax < c.Webhooks.RetryBase {
+		errs = append(errs, errors.New("webhooks.retryMax must be >= webhooks.retryBase"))
 	}
-	return def
+	return errors.Join(errs...)
 }
@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type Attachment struct {
+	ID         string    `db:"id"`
+	RoomID     string    `db:"room_id"`
+	UserID     int64     `db:"user_id"`
+	MessageID  *string   `db:"message_id"`
+	FileName   string    `db:"file_name"`
+	MimeType   string    `db:"mime_type"`
+	SizeBytes  int64     `db:"size_bytes"`
+	StorageKey string    `db:"storage_key"`
+	ThumbKey   *string   `db:"thumb_key"`
+	CreatedAt  time.Time `db:"created_at"`
+}
@@ -0,0 +1,75 @@
+package domain
+
+import "time"
+
+// Лимиты breakout-комнат.
+const (
+	MinBreakoutRooms    = 2
+	MaxBreakoutRooms    = 20
+	MinBreakoutDuration = time.Minute
+	MaxBreakoutDuration = 4 * time.Hour
+	MaxBreakoutNameLen  = 100
+)
+
+// Breakout — активное разбиение основной комнаты на дочерние.
+type Breakout struct {
+	ParentID  string
+	EndsAt    time.Time
+	CreatedBy *int64
+	CreatedAt time.Time
+	Rooms     []BreakoutRoom
+}
+
+type BreakoutRoom struct {
+	ID    string
+	Name  string
+	Users []int64 // распределённые сюда
+}
+
+// Children — id дочерних комнат.
+func (b *Breakout) Children() []string {
+	out := make([]string, 0, len(b.Rooms))
+	for _, r := range b.Rooms {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+// Has — roomID основная комната или одна из дочерних.
+func (b *Breakout) Has(roomID string) bool {
+	if roomID == b.ParentID {
+		return true
+	}
+	for _, r := range b.Rooms {
+		if r.ID == roomID {
+			return true
+		}
+	}
+	return false
+}
+
+// BreakoutInput — параметры запуска.
+type BreakoutInput struct {
+	Count    int
+	Names    []string      // необязательно, по умолчанию "Группа N"
+	Random   bool          // распределить участников основной комнаты случайно
+	Assign   map[int64]int // ручное распределение: user_id -> индекс комнаты
+	Duration time.Duration
+}
+
+// BreakoutEventKind — что произошло (для рассылок в WS).
+type BreakoutEventKind string
+
+const (
+	BreakoutStarted   BreakoutEventKind = "started"
+	BreakoutMoved     BreakoutEventKind = "moved"
+	BreakoutCountdown BreakoutEventKind = "countdown"
+	BreakoutEnded     BreakoutEventKind = "ended"
+)
+
+type BreakoutEvent struct {
+	Kind     BreakoutEventKind
+	Breakout *Breakout        // состояние после изменения (для ended — перед закрытием)
+	Moves    map[int64]string // user_id -> комната, куда перевести соединения
+	Left     time.Duration    // для countdown
+}
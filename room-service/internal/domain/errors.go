@@ -7,4 +7,42 @@ var (
 	ErrRoomFull      = errors.New("room is full")
 	ErrAlreadyJoined = errors.New("user already joined the room")
 	ErrNotInRoom     = errors.New("user not in the room")
+
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrAttachmentInvalid  = errors.New("invalid attachment")
+	ErrQuotaExceeded      = errors.New("room storage quota exceeded")
+	ErrEmptyMessage       = errors.New("empty message")
+	ErrMessageTooLong     = errors.New("message too long")
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrPresenceInvalid    = errors.New("invalid presence update")
+
+	ErrForbidden       = errors.New("not allowed")
+	ErrHandQueueEmpty  = errors.New("hand queue is empty")
+	ErrInvalidDuration = errors.New("invalid duration")
+
+	ErrPollNotFound = errors.New("poll not found")
+	ErrPollInvalid  = errors.New("invalid poll")
+	ErrPollClosed   = errors.New("poll is closed")
+	ErrVoteInvalid  = errors.New("invalid vote")
+	ErrAlreadyVoted = errors.New("already voted")
+
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrScheduleInvalid  = errors.New("invalid schedule")
+	ErrRoomClosed       = errors.New("room is closed outside of scheduled session")
+	ErrFeedNotFound     = errors.New("calendar feed not found")
+
+	// ErrInLobby — не сбой: пользователь поставлен в лобби и ждёт допуска.
+	ErrInLobby    = errors.New("waiting in lobby")
+	ErrNotInLobby = errors.New("user is not in lobby")
+
+	ErrInvalidCapacity = errors.New("invalid room capacity")
+	ErrRoomInvalid     = errors.New("invalid room")
+
+	ErrBreakoutNotFound = errors.New("breakout not found")
+	ErrBreakoutActive   = errors.New("breakout already running")
+	ErrBreakoutInvalid  = errors.New("invalid breakout")
+
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrWebhookInvalid   = errors.New("invalid webhook")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
 )
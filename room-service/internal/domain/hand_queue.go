@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// HandRaise — запись в очереди желающих выступить.
+type HandRaise struct {
+	RoomID   string    `db:"room_id"`
+	UserID   int64     `db:"user_id"`
+	RaisedAt time.Time `db:"raised_at"`
+}
+
+// Floor — текущий выступающий. ExpiresAt nil — без ограничения по времени.
+type Floor struct {
+	RoomID    string     `db:"room_id"`
+	UserID    int64      `db:"user_id"`
+	GrantedBy *int64     `db:"granted_by"`
+	GrantedAt time.Time  `db:"granted_at"`
+	ExpiresAt *time.Time `db:"expires_at"`
+}
+
+// HandQueue — состояние очереди комнаты целиком (для state и hand_queue).
+type HandQueue struct {
+	RoomID string
+	Queue  []HandRaise
+	Floor  *Floor
+}
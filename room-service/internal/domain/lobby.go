@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type LobbyEntry struct {
+	RoomID   string    `db:"room_id"`
+	UserID   int64     `db:"user_id"`
+	QueuedAt time.Time `db:"queued_at"`
+}
+
+// Lobby — настройки лобби комнаты и очередь ожидающих (FIFO).
+type Lobby struct {
+	RoomID           string
+	Enabled          bool
+	SessionStartedAt *time.Time // nil — хост ещё не начал занятие
+	Queue            []LobbyEntry
+}
+
+// Active — новых участников надо держать в лобби.
+func (l *Lobby) Active() bool {
+	return l.Enabled && l.SessionStartedAt == nil
+}
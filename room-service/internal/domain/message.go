@@ -5,8 +5,11 @@ import "time"
 type ChatMessage struct {
 	ID        string    `db:"id"`
 	RoomID    string    `db:"room_id"`
-	UserID    int64     `db:"user_id"`
+	UserID    int64     `db:"user_id"` // 0 — автор удалил аккаунт
 	Text      string    `db:"text"`
 	ReplyTo   *string   `db:"reply_to"`
 	CreatedAt time.Time `db:"created_at"`
+
+	// Attachments — id вложений, привязанных к сообщению.
+	Attachments []string `db:"-"`
 }
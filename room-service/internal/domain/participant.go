@@ -8,3 +8,14 @@ type Participant struct {
 	JoinedAt time.Time `db:"joined_at"`
 	LastSeen time.Time `db:"last_seen"`
 }
+
+// Membership — связь пользователя с комнатой для выгрузки персональных данных.
+// JoinedAt/LastSeen nil — сейчас не участник (только владелец или модератор).
+type Membership struct {
+	RoomID    string
+	RoomName  string
+	Owner     bool
+	Moderator bool
+	JoinedAt  *time.Time
+	LastSeen  *time.Time
+}
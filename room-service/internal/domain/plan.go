@@ -0,0 +1,19 @@
+package domain
+
+// MaxRoomCapacity — верхняя граница из CHECK в БД (migrations/0011), больше не даст ни один тариф.
+const MaxRoomCapacity = 10000
+
+// CapacityLimits — лимиты размера комнаты по тарифам (из конфига).
+type CapacityLimits struct {
+	Default     int64            // max, если при создании не указан (не больше лимита тарифа)
+	DefaultPlan string           // у кого нет записи в user_plans
+	Plans       map[string]int64 // тариф -> максимальный max_participants
+}
+
+// For — лимит тарифа; неизвестный тариф считаем тарифом по умолчанию.
+func (l CapacityLimits) For(plan string) int64 {
+	if n, ok := l.Plans[plan]; ok {
+		return n
+	}
+	return l.Plans[l.DefaultPlan]
+}
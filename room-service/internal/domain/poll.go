@@ -0,0 +1,94 @@
+package domain
+
+import "time"
+
+type PollResultsMode string
+
+const (
+	PollResultsLive    PollResultsMode = "live"     // результаты после каждого голоса
+	PollResultsOnClose PollResultsMode = "on_close" // только после закрытия
+)
+
+type PollStatus string
+
+const (
+	PollOpen   PollStatus = "open"
+	PollClosed PollStatus = "closed"
+)
+
+// Лимиты опросов.
+const (
+	MaxPollQuestionLen = 300
+	MaxPollOptionLen   = 200
+	MinPollOptions     = 2
+	MaxPollOptions     = 10
+	MaxPollDuration    = 24 * time.Hour
+)
+
+type Poll struct {
+	ID          string          `db:"id"`
+	RoomID      string          `db:"room_id"`
+	CreatedBy   *int64          `db:"created_by"`
+	Question    string          `db:"question"`
+	Multi       bool            `db:"multi"`
+	Quiz        bool            `db:"quiz"`
+	ResultsMode PollResultsMode `db:"results_mode"`
+	Status      PollStatus      `db:"status"`
+	ClosesAt    *time.Time      `db:"closes_at"`
+	CreatedAt   time.Time       `db:"created_at"`
+	ClosedAt    *time.Time      `db:"closed_at"`
+
+	Options []PollOption `db:"-"`
+}
+
+// PollInput — данные для создания опроса.
+type PollInput struct {
+	Question    string
+	Options     []PollOption // Idx проставляется по порядку
+	Multi       bool
+	Quiz        bool
+	ResultsMode PollResultsMode
+	Duration    time.Duration // 0 — без таймера
+}
+
+type PollOption struct {
+	Idx     int    `db:"idx"`
+	Text    string `db:"text"`
+	Correct bool   `db:"is_correct"`
+}
+
+type PollVote struct {
+	PollID    string    `db:"poll_id"`
+	UserID    int64     `db:"user_id"`
+	Options   []int     `db:"options"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// PollResults — число голосов по вариантам (индекс = PollOption.Idx).
+type PollResults struct {
+	PollID string
+	Voters int64
+	Counts []int64
+}
+
+// Correct — для квиза: выбран ровно набор правильных вариантов.
+func (p *Poll) Correct(selected []int) bool {
+	if !p.Quiz {
+		return false
+	}
+	want := make(map[int]bool)
+	for _, o := range p.Options {
+		if o.Correct {
+			want[o.Idx] = true
+		}
+	}
+	if len(selected) != len(want) {
+		return false
+	}
+	for _, i := range selected {
+		if !want[i] {
+			return false
+		}
+	}
+	return true
+}
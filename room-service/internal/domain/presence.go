@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+type PresenceStatus string
+
+const (
+	PresenceOnline  PresenceStatus = "online"
+	PresenceAway    PresenceStatus = "away"
+	PresenceBusy    PresenceStatus = "busy"
+	PresenceOffline PresenceStatus = "offline"
+)
+
+// MaxStatusTextLen — длина пользовательского статуса ("решаю 3-ю задачу").
+const MaxStatusTextLen = 100
+
+// Presence — состояние участника в комнате: подключение, медиа, поднятая рука, статус.
+type Presence struct {
+	RoomID       string         `db:"room_id"`
+	UserID       int64          `db:"user_id"`
+	Status       PresenceStatus `db:"presence_status"`
+	Mic          bool           `db:"mic_on"`
+	Cam          bool           `db:"cam_on"`
+	Screen       bool           `db:"screen_on"`
+	HandRaisedAt *time.Time     `db:"hand_raised_at"`
+	StatusText   string         `db:"status_text"`
+	UpdatedAt    time.Time      `db:"presence_updated_at"`
+}
+
+// PresenceUpdate — частичное обновление от клиента; nil — поле не меняется.
+type PresenceUpdate struct {
+	Status     *PresenceStatus
+	Mic        *bool
+	Cam        *bool
+	Screen     *bool
+	HandRaised *bool
+	StatusText *string
+}
+
+// PresenceDelta — что реально поменялось (уходит в presence_update).
+type PresenceDelta struct {
+	Status       *PresenceStatus
+	Mic          *bool
+	Cam          *bool
+	Screen       *bool
+	HandRaised   *bool
+	HandRaisedAt *time.Time
+	StatusText   *string
+}
+
+func (d PresenceDelta) Empty() bool {
+	return d.Status == nil && d.Mic == nil && d.Cam == nil && d.Screen == nil &&
+		d.HandRaised == nil && d.StatusText == nil
+}
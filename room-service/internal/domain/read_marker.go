@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ReadMarker — последнее прочитанное пользователем сообщение в комнате.
+type ReadMarker struct {
+	RoomID       string    `db:"room_id"`
+	UserID       int64     `db:"user_id"`
+	MsgID        string    `db:"msg_id"`
+	MsgCreatedAt time.Time `db:"msg_created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -6,5 +6,55 @@ type Room struct {
 	ID              string    `db:"id"`
 	Name            string    `db:"name"`
 	MaxParticipants int64     `db:"max_participants"`
+	OwnerID         *int64    `db:"owner_id"`
+	ParentID        *string   `db:"parent_id"` // у breakout-комнат — основная комната
 	CreatedAt       time.Time `db:"created_at"`
+
+	Description *string  `db:"description"`
+	Topic       *string  `db:"topic"`
+	Language    *string  `db:"language"`
+	Tags        []string `db:"tags"`
+
+	// заполняются только в списке (ListRooms)
+	Participants int64     // строк в room_participants
+	Online       int64     // из них со свежим heartbeat
+	ActivityAt   time.Time // последний heartbeat в комнате (или создание)
+}
+
+// Лимиты метаданных комнаты.
+const (
+	MaxRoomNameLen        = 100
+	MaxRoomDescriptionLen = 1000
+	MaxRoomTopicLen       = 100
+	MaxRoomTags           = 10
+	MaxRoomTagLen         = 32
+)
+
+// RoomInput — создание комнаты / изменение метаданных.
+// В UpdateRoom nil-поля не трогаем, пустая строка — очистить.
+type RoomInput struct {
+	Name        *string
+	Max         int64 // только при создании
+	Description *string
+	Topic       *string
+	Language    *string
+	Tags        *[]string
+}
+
+// Сортировка списка комнат.
+const (
+	RoomSortCreated  = "created"  // новые сверху (по умолчанию)
+	RoomSortActivity = "activity" // больше онлайн, затем недавняя активность
+)
+
+// RoomFilter — фильтры ListRooms.
+type RoomFilter struct {
+	Query     string // подстрока в названии или теме
+	Tag       string
+	Language  string
+	FreeSeats bool // есть свободные места
+	LiveNow   bool // кто-то онлайн прямо сейчас
+	Sort      string
+	Limit     int
+	Cursor    string
 }
@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Лимиты расписания.
+const (
+	MinSessionDuration = time.Minute
+	MaxSessionDuration = 24 * time.Hour
+	MaxOpenBefore      = time.Hour
+	MaxSessionsRange   = 366 * 24 * time.Hour // максимальное окно выборки занятий
+	MaxSessionsLimit   = 500
+)
+
+// Schedule — расписание комнаты. Вне окна занятия в комнату пускают только модераторов.
+type Schedule struct {
+	RoomID     string        `db:"room_id"`
+	RoomName   string        `db:"-"`         // заполняется при чтении (нужно для ленты и .ics)
+	StartsAt   time.Time     `db:"starts_at"` // первое занятие (DTSTART)
+	Duration   time.Duration `db:"duration_sec"`
+	RRule      string        `db:"rrule"`    // без DTSTART, пусто — разовое занятие
+	Timezone   string        `db:"timezone"` // IANA, в ней разворачивается повтор
+	OpenBefore time.Duration `db:"open_before_sec"`
+	CreatedBy  *int64        `db:"created_by"`
+	CreatedAt  time.Time     `db:"created_at"`
+	UpdatedAt  time.Time     `db:"updated_at"`
+}
+
+// Session — одно занятие из расписания.
+type Session struct {
+	RoomID   string
+	RoomName string
+	StartsAt time.Time
+	EndsAt   time.Time
+}
+
+const MaxCalendarFeedsPerUser = 20
+
+// CalendarFeed — лента .ics по ссылке с токеном (календарь не шлёт bearer).
+// В БД только хеш токена, удаление ленты отзывает ссылку.
+type CalendarFeed struct {
+	ID        string
+	UserID    int64
+	RoomID    string // пусто — по всем комнатам пользователя
+	Token     string // отдаётся только при создании
+	CreatedAt time.Time
+}
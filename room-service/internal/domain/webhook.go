@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Типы событий для вебхуков.
+const (
+	EventUserRegistered    = "user.registered" // системное, пишет auth-service
+	EventUserDeleted       = "user.deleted"    // системное, пишет auth-service
+	EventRoomCreated       = "room.created"
+	EventParticipantJoined = "participant.joined"
+	EventChatMessage       = "chat.message"
+)
+
+// WebhookEventTypes — на что можно подписаться.
+var WebhookEventTypes = []string{EventUserRegistered, EventUserDeleted, EventRoomCreated, EventParticipantJoined, EventChatMessage}
+
+// IsSystemWebhookEvent — событие не привязано к комнате, подписка только для systemOwners.
+func IsSystemWebhookEvent(t string) bool {
+	return t == EventUserRegistered || t == EventUserDeleted
+}
+
+const MaxWebhooksPerOwner = 20
+
+type WebhookSubscription struct {
+	ID        string
+	OwnerID   int64
+	URL       string
+	Secret    string // отдаётся только при создании
+	Events    []string
+	Active    bool
+	CreatedAt time.Time
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             string
+	SubscriptionID string
+	EventID        string
+	EventType      string
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatus     *int
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+
+	// для отправки (заполняются при захвате доставки диспетчером)
+	Payload        json.RawMessage
+	EventCreatedAt time.Time
+	URL            string
+	Secret         string
+}
+
+// WebhookRetry — экспоненциальные ретраи: Base, 2*Base, 4*Base... не больше Max,
+// после MaxAttempts неудачных попыток доставка уходит в dead.
+type WebhookRetry struct {
+	Base        time.Duration
+	Max         time.Duration
+	MaxAttempts int
+}
+
+// Next — через сколько повторить после attempts неудачных попыток; dead=true — больше не пробуем.
+func (p WebhookRetry) Next(attempts int) (wait time.Duration, dead bool) {
+	if attempts >= p.MaxAttempts {
+		return 0, true
+	}
+	wait = p.Base
+	for i := 1; i < attempts && wait < p.Max; i++ {
+		wait *= 2
+	}
+	return min(wait, p.Max), false
+}
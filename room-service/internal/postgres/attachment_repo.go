@@ -0,0 +1,88 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create сохраняет метаданные вложения с проверкой квоты комнаты.
+// quotaBytes <= 0 — без ограничения.
+func (r *AttachmentRepository) Create(ctx context.Context, a *domain.Attachment, quotaBytes int64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокируем комнату, чтобы параллельные загрузки не пробили квоту.
+	var one int
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM rooms WHERE id=$1 FOR UPDATE`, a.RoomID).Scan(&one); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrRoomNotFound
+		}
+		return err
+	}
+
+	if quotaBytes > 0 {
+		var used int64
+		if err := tx.QueryRow(ctx,
+			`SELECT COALESCE(SUM(size_bytes), 0) FROM room_attachments WHERE room_id=$1`,
+			a.RoomID).Scan(&used); err != nil {
+			return err
+		}
+		if used+a.SizeBytes > quotaBytes {
+			return domain.ErrQuotaExceeded
+		}
+	}
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO room_attachments (id, room_id, user_id, file_name, mime_type, size_bytes, storage_key, thumb_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`, a.ID, a.RoomID, a.UserID, a.FileName, a.MimeType, a.SizeBytes, a.StorageKey, a.ThumbKey).Scan(&a.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *AttachmentRepository) Get(ctx context.Context, roomID, id string) (*domain.Attachment, error) {
+	var a domain.Attachment
+	err := r.db.QueryRow(ctx, `
+		SELECT id, room_id, COALESCE(user_id, 0), message_id, file_name, mime_type, size_bytes, storage_key, thumb_key, created_at
+		FROM room_attachments
+		WHERE room_id=$1 AND id=$2
+	`, roomID, id).Scan(
+		&a.ID, &a.RoomID, &a.UserID, &a.MessageID, &a.FileName,
+		&a.MimeType, &a.SizeBytes, &a.StorageKey, &a.ThumbKey, &a.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		return nil, err
+	}
+	return &a, nil
+}
+
+// UsedBytes — сколько места комната уже заняла вложениями.
+func (r *AttachmentRepository) UsedBytes(ctx context.Context, roomID string) (int64, error) {
+	var used int64
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE(SUM(size_bytes), 0) FROM room_attachments WHERE room_id=$1`,
+		roomID).Scan(&used)
+	return used, err
+}
@@ -0,0 +1,282 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type BreakoutRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBreakoutRepository(db *pgxpool.Pool) *BreakoutRepository {
+	return &BreakoutRepository{db: db}
+}
+
+// todo: queries.go
+
+// Create — в одной транзакции: дочерние комнаты (лимит и владелец как у основной),
+// распределение и перенос строк участников. b.Rooms[i].Users — кого куда.
+func (r *BreakoutRepository) Create(ctx context.Context, b *domain.Breakout) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		max     int64
+		ownerID *int64
+	)
+	if err := tx.QueryRow(ctx,
+		`SELECT max_participants, owner_id FROM rooms WHERE id=$1 FOR UPDATE`,
+		b.ParentID).Scan(&max, &ownerID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrRoomNotFound
+		}
+		return err
+	}
+
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO room_breakouts (parent_id, ends_at, created_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+		RETURNING created_at
+	`, b.ParentID, b.EndsAt, b.CreatedBy).Scan(&b.CreatedAt); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrBreakoutActive
+		}
+		return err
+	}
+
+	for i := range b.Rooms {
+		br := &b.Rooms[i]
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO rooms (name, max_participants, owner_id, parent_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id
+		`, br.Name, max, ownerID, b.ParentID).Scan(&br.ID); err != nil {
+			return err
+		}
+		for _, uid := range br.Users {
+			if err := assignTx(ctx, tx, b.ParentID, uid, br.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Get — активный breakout основной комнаты с распределением.
+func (r *BreakoutRepository) Get(ctx context.Context, parentID string) (*domain.Breakout, error) {
+	b := domain.Breakout{ParentID: parentID}
+	err := r.db.QueryRow(ctx,
+		`SELECT ends_at, created_by, created_at FROM room_breakouts WHERE parent_id=$1`,
+		parentID).Scan(&b.EndsAt, &b.CreatedBy, &b.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrBreakoutNotFound
+		}
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT id, name FROM rooms WHERE parent_id=$1 ORDER BY created_at, name
+	`, parentID)
+	if err != nil {
+		return nil, err
+	}
+	idx := make(map[string]int)
+	for rows.Next() {
+		var br domain.BreakoutRoom
+		if err := rows.Scan(&br.ID, &br.Name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		idx[br.ID] = len(b.Rooms)
+		b.Rooms = append(b.Rooms, br)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = r.db.Query(ctx, `
+		SELECT user_id, room_id FROM room_breakout_assignments
+		WHERE parent_id=$1 ORDER BY user_id
+	`, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			uid    int64
+			roomID string
+		)
+		if err := rows.Scan(&uid, &roomID); err != nil {
+			return nil, err
+		}
+		if i, ok := idx[roomID]; ok {
+			b.Rooms[i].Users = append(b.Rooms[i].Users, uid)
+		}
+	}
+	return &b, rows.Err()
+}
+
+// ParentOf — основная комната для breakout-комнаты ("" — это не breakout).
+func (r *BreakoutRepository) ParentOf(ctx context.Context, roomID string) (string, error) {
+	var parentID *string
+	err := r.db.QueryRow(ctx, `SELECT parent_id FROM rooms WHERE id=$1`, roomID).Scan(&parentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrRoomNotFound
+		}
+		return "", err
+	}
+	if parentID == nil {
+		return "", nil
+	}
+	return *parentID, nil
+}
+
+// ListActive — все активные breakout'ы (для восстановления таймеров).
+func (r *BreakoutRepository) ListActive(ctx context.Context) ([]domain.Breakout, error) {
+	rows, err := r.db.Query(ctx, `SELECT parent_id, ends_at, created_by, created_at FROM room_breakouts`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Breakout, 0)
+	for rows.Next() {
+		var b domain.Breakout
+		if err := rows.Scan(&b.ParentID, &b.EndsAt, &b.CreatedBy, &b.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// Assigned — куда распределён пользователь (ok=false — никуда).
+func (r *BreakoutRepository) Assigned(ctx context.Context, parentID string, userID int64) (roomID string, ok bool, err error) {
+	err = r.db.QueryRow(ctx,
+		`SELECT room_id FROM room_breakout_assignments WHERE parent_id=$1 AND user_id=$2`,
+		parentID, userID).Scan(&roomID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return roomID, true, nil
+}
+
+// Assign — перевести пользователя в roomID (дочернюю или обратно в основную).
+// Строка участника переезжает вместе с ним, если он уже был в одной из комнат.
+func (r *BreakoutRepository) Assign(ctx context.Context, parentID string, userID int64, roomID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := assignTx(ctx, tx, parentID, userID, roomID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func assignTx(ctx context.Context, tx pgx.Tx, parentID string, userID int64, roomID string) error {
+	if roomID == parentID {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM room_breakout_assignments WHERE parent_id=$1 AND user_id=$2`,
+			parentID, userID); err != nil {
+			return err
+		}
+	} else if _, err := tx.Exec(ctx, `
+		INSERT INTO room_breakout_assignments (parent_id, user_id, room_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (parent_id, user_id) DO UPDATE SET room_id = EXCLUDED.room_id
+	`, parentID, userID, roomID); err != nil {
+		return err
+	}
+
+	// presence/heartbeat едут вместе со строкой; если в целевой комнате строка уже есть,
+	// лишние в остальных комнатах просто удаляем
+	if _, err := tx.Exec(ctx, `
+		UPDATE room_participants SET room_id=$3
+		WHERE (room_id, user_id) = (
+			SELECT room_id, user_id FROM room_participants
+			WHERE user_id=$2 AND room_id <> $3
+			  AND room_id IN (SELECT id FROM rooms WHERE id=$1 OR parent_id=$1)
+			  AND NOT EXISTS (SELECT 1 FROM room_participants WHERE room_id=$3 AND user_id=$2)
+			LIMIT 1
+		)
+	`, parentID, userID, roomID); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, `
+		DELETE FROM room_participants
+		WHERE user_id=$2 AND room_id <> $3
+		  AND room_id IN (SELECT id FROM rooms WHERE id=$1 OR parent_id=$1)
+	`, parentID, userID, roomID)
+	return err
+}
+
+// Close — завершить breakout: участники дочерних комнат возвращаются в основную,
+// дочерние комнаты удаляются. Возвращает id удалённых комнат; ok=false — уже закрыт.
+func (r *BreakoutRepository) Close(ctx context.Context, parentID string) (children []string, ok bool, err error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `DELETE FROM room_breakouts WHERE parent_id=$1`, parentID)
+	if err != nil {
+		return nil, false, err
+	}
+	if tag.RowsAffected() == 0 {
+		return nil, false, nil
+	}
+
+	// кто уже и так в основной (модератор успел вернуться) — просто удалится каскадом
+	if _, err := tx.Exec(ctx, `
+		UPDATE room_participants p SET room_id=$1
+		FROM rooms c
+		WHERE c.id = p.room_id AND c.parent_id=$1
+		  AND NOT EXISTS (SELECT 1 FROM room_participants x WHERE x.room_id=$1 AND x.user_id=p.user_id)
+	`, parentID); err != nil {
+		return nil, false, err
+	}
+
+	rows, err := tx.Query(ctx, `DELETE FROM rooms WHERE parent_id=$1 RETURNING id`, parentID)
+	if err != nil {
+		return nil, false, err
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, false, err
+		}
+		children = append(children, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, false, err
+	}
+	return children, true, nil
+}
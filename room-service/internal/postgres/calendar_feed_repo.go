@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CalendarFeedRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarFeedRepository(db *pgxpool.Pool) *CalendarFeedRepository {
+	return &CalendarFeedRepository{db: db}
+}
+
+// todo: queries.go
+
+// Create — tokenHash вместо самого токена: по утёкшей базе ленты не открыть.
+func (r *CalendarFeedRepository) Create(ctx context.Context, f *domain.CalendarFeed, tokenHash string) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO calendar_feeds (user_id, room_id, token_hash)
+		VALUES ($1, NULLIF($2, '')::uuid, $3)
+		RETURNING id, created_at
+	`, f.UserID, f.RoomID, tokenHash).Scan(&f.ID, &f.CreatedAt)
+}
+
+func (r *CalendarFeedRepository) Count(ctx context.Context, userID int64) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM calendar_feeds WHERE user_id=$1`, userID).Scan(&n)
+	return n, err
+}
+
+func (r *CalendarFeedRepository) List(ctx context.Context, userID int64) ([]domain.CalendarFeed, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, COALESCE(room_id::text, ''), created_at
+		FROM calendar_feeds
+		WHERE user_id=$1
+		ORDER BY created_at, id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.CalendarFeed
+	for rows.Next() {
+		var f domain.CalendarFeed
+		if err := rows.Scan(&f.ID, &f.UserID, &f.RoomID, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+func (r *CalendarFeedRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	var f domain.CalendarFeed
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, COALESCE(room_id::text, ''), created_at
+		FROM calendar_feeds
+		WHERE token_hash=$1
+	`, tokenHash).Scan(&f.ID, &f.UserID, &f.RoomID, &f.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrFeedNotFound
+		}
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (r *CalendarFeedRepository) Delete(ctx context.Context, userID int64, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM calendar_feeds WHERE id=$1 AND user_id=$2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrFeedNotFound
+	}
+	return nil
+}
@@ -6,6 +6,8 @@ import (
 
 	"github.com/cwrk-planet/room-service/internal/domain"
 
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,9 +19,17 @@ func NewChatRepository(db *pgxpool.Pool) *ChatRepository {
 	return &ChatRepository{db: db}
 }
 
-func (r *ChatRepository) Save(ctx context.Context, roomID string, userID int64, text string, replyTo *string) (*domain.ChatMessage, error) {
+// Save сохраняет сообщение и в той же транзакции привязывает к нему вложения.
+// Привязать можно только свои, ещё не использованные вложения этой комнаты.
+func (r *ChatRepository) Save(ctx context.Context, roomID string, userID int64, text string, replyTo *string, attachments []string) (*domain.ChatMessage, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
 	// todo: перенести в отдельный файл queries.go
-	row := r.db.QueryRow(ctx, `
+	row := tx.QueryRow(ctx, `
 		INSERT INTO room_messages (room_id, user_id, text, reply_to)
 		VALUES ($1, $2, $3, $4)
 		RETURNING id, room_id, user_id, text, reply_to, created_at
@@ -29,6 +39,39 @@ func (r *ChatRepository) Save(ctx context.Context, roomID string, userID int64,
 	if err := row.Scan(&m.ID, &m.RoomID, &m.UserID, &m.Text, &m.ReplyTo, &m.CreatedAt); err != nil {
 		return nil, err
 	}
+
+	if len(attachments) > 0 {
+		cmd, err := tx.Exec(ctx, `
+			UPDATE room_attachments
+			SET message_id = $1
+			WHERE id = ANY($2::uuid[])
+			  AND room_id = $3
+			  AND user_id = $4
+			  AND message_id IS NULL
+		`, m.ID, attachments, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if int(cmd.RowsAffected()) != len(attachments) {
+			return nil, domain.ErrAttachmentNotFound
+		}
+		m.Attachments = attachments
+	}
+
+	if _, err := outbox.Add(ctx, tx, EventSource, events.ChatMessage{
+		MessageID:   m.ID,
+		RoomID:      m.RoomID,
+		UserID:      m.UserID,
+		Text:        m.Text,
+		Attachments: m.Attachments,
+		CreatedAt:   m.CreatedAt,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 	return &m, nil
 }
 
@@ -46,15 +89,21 @@ func (r *ChatRepository) History(ctx context.Context, roomID, after string, limi
 	}
 	// todo: перенести в отдельный файл queries.go
 	const baseQuery = `
-		SELECT id, room_id, user_id, text, reply_to, created_at
-		FROM room_messages
-		WHERE room_id = $1
+		SELECT m.id, m.room_id, COALESCE(m.user_id, 0), m.text, m.reply_to, m.created_at,
+		       COALESCE(
+		         (SELECT array_agg(a.id::text ORDER BY a.created_at, a.id)
+		          FROM room_attachments a
+		          WHERE a.message_id = m.id),
+		         '{}'
+		       ) AS attachments
+		FROM room_messages m
+		WHERE m.room_id = $1
 		  AND (
 		    $2::timestamptz IS NULL
-		    OR created_at < $2
-		    OR (created_at = $2 AND id < $3)
+		    OR m.created_at < $2
+		    OR (m.created_at = $2 AND m.id < $3)
 		  )
-		ORDER BY created_at DESC, id DESC
+		ORDER BY m.created_at DESC, m.id DESC
 		LIMIT $4
 	`
 
@@ -74,7 +123,7 @@ func (r *ChatRepository) History(ctx context.Context, roomID, after string, limi
 	var out []domain.ChatMessage
 	for rows.Next() {
 		var m domain.ChatMessage
-		if err := rows.Scan(&m.ID, &m.RoomID, &m.UserID, &m.Text, &m.ReplyTo, &m.CreatedAt); err != nil {
+		if err := rows.Scan(&m.ID, &m.RoomID, &m.UserID, &m.Text, &m.ReplyTo, &m.CreatedAt, &m.Attachments); err != nil {
 			return nil, "", err
 		}
 		out = append(out, m)
@@ -89,3 +138,110 @@ func (r *ChatRepository) History(ctx context.Context, roomID, after string, limi
 	}
 	return out, next, nil
 }
+
+// ByUser — сообщения автора по всем комнатам, курсор как у History (created_at,id DESC).
+func (r *ChatRepository) ByUser(ctx context.Context, userID int64, after string, limit int) ([]domain.ChatMessage, string, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+	cur, err := DecodeCursor(after)
+	if err != nil {
+		return nil, "", fmt.Errorf("decode cursor: %w", err)
+	}
+	var createdAt, id any
+	if cur != nil {
+		createdAt = cur.CreatedAt
+		id = cur.ID
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT m.id, m.room_id, m.user_id, m.text, m.reply_to, m.created_at,
+		       COALESCE(
+		         (SELECT array_agg(a.id::text ORDER BY a.created_at, a.id)
+		          FROM room_attachments a
+		          WHERE a.message_id = m.id),
+		         '{}'
+		       ) AS attachments
+		FROM room_messages m
+		WHERE m.user_id = $1
+		  AND (
+		    $2::timestamptz IS NULL
+		    OR m.created_at < $2
+		    OR (m.created_at = $2 AND m.id < $3)
+		  )
+		ORDER BY m.created_at DESC, m.id DESC
+		LIMIT $4
+	`, userID, createdAt, id, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var out []domain.ChatMessage
+	for rows.Next() {
+		var m domain.ChatMessage
+		if err := rows.Scan(&m.ID, &m.RoomID, &m.UserID, &m.Text, &m.ReplyTo, &m.CreatedAt, &m.Attachments); err != nil {
+			return nil, "", err
+		}
+		out = append(out, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(out) == limit {
+		last := out[len(out)-1]
+		if c, e := EncodeCursor(Cursor{CreatedAt: last.CreatedAt, ID: last.ID}); e == nil {
+			next = c
+		}
+	}
+	return out, next, nil
+}
+
+// Since — сообщения после afterID по возрастанию (created_at,id ASC): догнать пропущенное.
+// ErrMessageNotFound, если afterID нет в этой комнате.
+func (r *ChatRepository) Since(ctx context.Context, roomID, afterID string, limit int) ([]domain.ChatMessage, error) {
+	var exists bool
+	if err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM room_messages WHERE id=$1 AND room_id=$2)`,
+		afterID, roomID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrMessageNotFound
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT m.id, m.room_id, COALESCE(m.user_id, 0), m.text, m.reply_to, m.created_at,
+		       COALESCE(
+		         (SELECT array_agg(a.id::text ORDER BY a.created_at, a.id)
+		          FROM room_attachments a
+		          WHERE a.message_id = m.id),
+		         '{}'
+		       ) AS attachments
+		FROM room_messages m, room_messages c
+		WHERE c.id = $2
+		  AND m.room_id = $1
+		  AND (m.created_at, m.id) > (c.created_at, c.id)
+		ORDER BY m.created_at, m.id
+		LIMIT $3
+	`, roomID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.ChatMessage
+	for rows.Next() {
+		var m domain.ChatMessage
+		if err := rows.Scan(&m.ID, &m.RoomID, &m.UserID, &m.Text, &m.ReplyTo, &m.CreatedAt, &m.Attachments); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
@@ -7,6 +7,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// EventSource — источник событий room-service в outbox.
+const EventSource = "room-service"
+
 type DB struct {
 	Pool *pgxpool.Pool
 }
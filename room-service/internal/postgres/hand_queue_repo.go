@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HandQueueRepository — очередь "поднятых рук" и текущий выступающий.
+// Всё в БД, чтобы пережить рестарт room-service.
+type HandQueueRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewHandQueueRepository(db *pgxpool.Pool) *HandQueueRepository {
+	return &HandQueueRepository{db: db}
+}
+
+// Raise — встать в конец очереди. false — уже стоит (место в очереди не теряется).
+func (r *HandQueueRepository) Raise(ctx context.Context, roomID string, userID int64) (bool, error) {
+	cmd, err := r.db.Exec(ctx, `
+		INSERT INTO room_hand_queue (room_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (r *HandQueueRepository) Lower(ctx context.Context, roomID string, userID int64) (bool, error) {
+	cmd, err := r.db.Exec(ctx, `DELETE FROM room_hand_queue WHERE room_id=$1 AND user_id=$2`, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (r *HandQueueRepository) Get(ctx context.Context, roomID string) (*domain.HandQueue, error) {
+	q := &domain.HandQueue{RoomID: roomID, Queue: []domain.HandRaise{}}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT room_id, user_id, raised_at
+		FROM room_hand_queue
+		WHERE room_id=$1
+		ORDER BY raised_at, user_id
+	`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h domain.HandRaise
+		if err := rows.Scan(&h.RoomID, &h.UserID, &h.RaisedAt); err != nil {
+			return nil, err
+		}
+		q.Queue = append(q.Queue, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var f domain.Floor
+	err = r.db.QueryRow(ctx, `
+		SELECT room_id, user_id, granted_by, granted_at, expires_at
+		FROM room_floor
+		WHERE room_id=$1
+	`, roomID).Scan(&f.RoomID, &f.UserID, &f.GrantedBy, &f.GrantedAt, &f.ExpiresAt)
+	switch {
+	case err == nil:
+		q.Floor = &f
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// GrantFloor отдаёт слово userID (убирая его из очереди). userID == 0 — первому в очереди.
+func (r *HandQueueRepository) GrantFloor(ctx context.Context, roomID string, userID, grantedBy int64, expiresAt *time.Time) (*domain.Floor, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// сериализуем операции над очередью комнаты
+	var one int
+	if err := tx.QueryRow(ctx, `SELECT 1 FROM rooms WHERE id=$1 FOR UPDATE`, roomID).Scan(&one); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRoomNotFound
+		}
+		return nil, err
+	}
+
+	if userID == 0 {
+		err := tx.QueryRow(ctx, `
+			SELECT user_id FROM room_hand_queue
+			WHERE room_id=$1
+			ORDER BY raised_at, user_id
+			LIMIT 1
+		`, roomID).Scan(&userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, domain.ErrHandQueueEmpty
+			}
+			return nil, err
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM room_hand_queue WHERE room_id=$1 AND user_id=$2`, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	var f domain.Floor
+	err = tx.QueryRow(ctx, `
+		INSERT INTO room_floor (room_id, user_id, granted_by, granted_at, expires_at)
+		VALUES ($1, $2, $3, now(), $4)
+		ON CONFLICT (room_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+		    granted_by = EXCLUDED.granted_by,
+		    granted_at = EXCLUDED.granted_at,
+		    expires_at = EXCLUDED.expires_at
+		RETURNING room_id, user_id, granted_by, granted_at, expires_at
+	`, roomID, userID, grantedBy, expiresAt).Scan(&f.RoomID, &f.UserID, &f.GrantedBy, &f.GrantedAt, &f.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// ReleaseFloor снимает выступающего. userID != 0 — только если слово всё ещё у него;
+// grantedAt != nil — только если это то же самое "выступление" (для таймера).
+func (r *HandQueueRepository) ReleaseFloor(ctx context.Context, roomID string, userID int64, grantedAt *time.Time) (bool, error) {
+	cmd, err := r.db.Exec(ctx, `
+		DELETE FROM room_floor
+		WHERE room_id=$1
+		  AND ($2::bigint = 0 OR user_id = $2)
+		  AND ($3::timestamptz IS NULL OR granted_at = $3)
+	`, roomID, userID, grantedAt)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+// TimedFloors — все выступления с таймером (для восстановления таймеров после рестарта).
+func (r *HandQueueRepository) TimedFloors(ctx context.Context) ([]domain.Floor, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT room_id, user_id, granted_by, granted_at, expires_at
+		FROM room_floor
+		WHERE expires_at IS NOT NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Floor
+	for rows.Next() {
+		var f domain.Floor
+		if err := rows.Scan(&f.RoomID, &f.UserID, &f.GrantedBy, &f.GrantedAt, &f.ExpiresAt); err != nil {
+			return nil, err
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
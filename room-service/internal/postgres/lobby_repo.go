@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LobbyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewLobbyRepository(db *pgxpool.Pool) *LobbyRepository {
+	return &LobbyRepository{db: db}
+}
+
+// todo: queries.go
+
+// Get — настройки лобби без очереди.
+func (r *LobbyRepository) Get(ctx context.Context, roomID string) (*domain.Lobby, error) {
+	l := domain.Lobby{RoomID: roomID}
+	err := r.db.QueryRow(ctx,
+		`SELECT lobby_enabled, session_started_at FROM rooms WHERE id=$1`,
+		roomID).Scan(&l.Enabled, &l.SessionStartedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRoomNotFound
+		}
+		return nil, err
+	}
+	return &l, nil
+}
+
+func (r *LobbyRepository) SetEnabled(ctx context.Context, roomID string, enabled bool) error {
+	tag, err := r.db.Exec(ctx, `UPDATE rooms SET lobby_enabled=$2 WHERE id=$1`, roomID, enabled)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRoomNotFound
+	}
+	return nil
+}
+
+// SetStarted — отметить начало занятия (started=false — сброс к следующему).
+func (r *LobbyRepository) SetStarted(ctx context.Context, roomID string, started bool) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE rooms
+		SET session_started_at = CASE WHEN $2 THEN COALESCE(session_started_at, now()) ELSE NULL END
+		WHERE id=$1
+	`, roomID, started)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRoomNotFound
+	}
+	return nil
+}
+
+// Enqueue — идемпотентно: повторный вход сохраняет место в очереди.
+func (r *LobbyRepository) Enqueue(ctx context.Context, roomID string, userID int64) (added bool, err error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO room_lobby (room_id, user_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Position — место в очереди (с 1); ok=false, если пользователя в лобби нет.
+func (r *LobbyRepository) Position(ctx context.Context, roomID string, userID int64) (pos int, ok bool, err error) {
+	err = r.db.QueryRow(ctx, `
+		SELECT (
+			SELECT COUNT(*) FROM room_lobby o
+			WHERE o.room_id = l.room_id AND (o.queued_at, o.user_id) < (l.queued_at, l.user_id)
+		) + 1
+		FROM room_lobby l
+		WHERE l.room_id=$1 AND l.user_id=$2
+	`, roomID, userID).Scan(&pos)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return pos, true, nil
+}
+
+func (r *LobbyRepository) List(ctx context.Context, roomID string) ([]domain.LobbyEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT room_id, user_id, queued_at
+		FROM room_lobby
+		WHERE room_id=$1
+		ORDER BY queued_at, user_id
+	`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.LobbyEntry, 0)
+	for rows.Next() {
+		var e domain.LobbyEntry
+		if err := rows.Scan(&e.RoomID, &e.UserID, &e.QueuedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (r *LobbyRepository) Remove(ctx context.Context, roomID string, userID int64) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM room_lobby WHERE room_id=$1 AND user_id=$2`, roomID, userID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Clear — очистить очередь, возвращает тех, кто в ней был.
+func (r *LobbyRepository) Clear(ctx context.Context, roomID string) ([]int64, error) {
+	rows, err := r.db.Query(ctx, `DELETE FROM room_lobby WHERE room_id=$1 RETURNING user_id`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []int64
+	for rows.Next() {
+		var uid int64
+		if err := rows.Scan(&uid); err != nil {
+			return nil, err
+		}
+		out = append(out, uid)
+	}
+	return out, rows.Err()
+}
+
+// Admit — перевести ожидающих в room_participants в порядке очереди, пока есть места.
+// userIDs == nil — всех. Лимит проверяется под блокировкой комнаты, как в ParticipantRepository.Join.
+func (r *LobbyRepository) Admit(ctx context.Context, roomID string, userIDs []int64) ([]int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var max int64
+	if err := tx.QueryRow(ctx, `SELECT max_participants FROM rooms WHERE id=$1 FOR UPDATE`, roomID).Scan(&max); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrRoomNotFound
+		}
+		return nil, err
+	}
+	var count int64
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM room_participants WHERE room_id=$1`, roomID).Scan(&count); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT user_id FROM room_lobby
+		WHERE room_id=$1 AND ($2::bigint[] IS NULL OR user_id = ANY($2))
+		ORDER BY queued_at, user_id
+	`, roomID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	var queue []int64
+	for rows.Next() {
+		var uid int64
+		if err := rows.Scan(&uid); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		queue = append(queue, uid)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	admitted := make([]int64, 0, len(queue))
+	for _, uid := range queue {
+		var exists bool
+		if err := tx.QueryRow(ctx,
+			`SELECT EXISTS(SELECT 1 FROM room_participants WHERE room_id=$1 AND user_id=$2)`,
+			roomID, uid).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			if count >= max {
+				break // остальные ждут дальше
+			}
+			var joinedAt time.Time
+			if err := tx.QueryRow(ctx,
+				`INSERT INTO room_participants (room_id, user_id) VALUES ($1, $2) RETURNING joined_at`,
+				roomID, uid).Scan(&joinedAt); err != nil {
+				return nil, err
+			}
+			if _, err := outbox.Add(ctx, tx, EventSource, events.ParticipantJoined{
+				RoomID: roomID, UserID: uid, JoinedAt: joinedAt,
+			}); err != nil {
+				return nil, err
+			}
+			count++
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM room_lobby WHERE room_id=$1 AND user_id=$2`, roomID, uid); err != nil {
+			return nil, err
+		}
+		admitted = append(admitted, uid)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return admitted, nil
+}
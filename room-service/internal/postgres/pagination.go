@@ -13,6 +13,12 @@ var ErrInvalidCursor = errors.New("invalid cursor")
 type Cursor struct {
 	CreatedAt time.Time `json:"created_at"`
 	ID        string    `json:"id"`
+
+	// для сортировки по активности (ListRooms sort=activity): сначала online и activity_at,
+	// при равенстве — (created_at, id), как в сортировке по умолчанию
+	Sort       string    `json:"sort,omitempty"`
+	Online     int64     `json:"online,omitempty"`
+	ActivityAt time.Time `json:"activity_at,omitempty"`
 }
 
 func EncodeCursor(c Cursor) (string, error) {
@@ -2,10 +2,14 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/cwrk-planet/room-service/internal/domain"
 
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -59,11 +63,23 @@ func (r *ParticipantRepository) Join(ctx context.Context, p *domain.Participant,
 		return domain.ErrRoomFull
 	}
 
-	if _, err := tx.Exec(ctx, `
+	// событие — только если строка действительно добавлена (повторный Join ничего не меняет)
+	err = tx.QueryRow(ctx, `
 		INSERT INTO room_participants (room_id, user_id)
 		VALUES ($1, $2)
 		ON CONFLICT DO NOTHING
-	`, p.RoomID, p.UserID); err != nil {
+		RETURNING joined_at
+	`, p.RoomID, p.UserID).Scan(&p.JoinedAt)
+	switch {
+	case err == nil:
+		if _, err := outbox.Add(ctx, tx, EventSource, events.ParticipantJoined{
+			RoomID:   p.RoomID,
+			UserID:   p.UserID,
+			JoinedAt: p.JoinedAt,
+		}); err != nil {
+			return err
+		}
+	case !errors.Is(err, pgx.ErrNoRows):
 		return err
 	}
 
@@ -71,15 +87,28 @@ func (r *ParticipantRepository) Join(ctx context.Context, p *domain.Participant,
 }
 
 func (r *ParticipantRepository) Leave(ctx context.Context, roomID string, userID int64) error {
-	cmd, err := r.db.Exec(ctx, `DELETE FROM room_participants WHERE room_id=$1 AND user_id=$2`, roomID, userID)
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `DELETE FROM room_participants WHERE room_id=$1 AND user_id=$2`, roomID, userID)
+	if err != nil {
+		return err
+	}
 	if cmd.RowsAffected() == 0 {
 		return domain.ErrNotInRoom
 	}
-	if err != nil {
+	if _, err := outbox.Add(ctx, tx, EventSource, events.ParticipantLeft{
+		RoomID: roomID,
+		UserID: userID,
+		LeftAt: time.Now().UTC(),
+	}); err != nil {
 		return err
 	}
 
-	return nil
+	return tx.Commit(ctx)
 }
 
 func (r *ParticipantRepository) ListByRoom(ctx context.Context, roomID string) ([]domain.Participant, error) {
@@ -115,31 +144,68 @@ func (r *ParticipantRepository) TouchHeartbeat(ctx context.Context, roomID strin
 	return nil
 }
 
+// ListByUser — комнаты, где пользователь участник, владелец или модератор.
+func (r *ParticipantRepository) ListByUser(ctx context.Context, userID int64) ([]domain.Membership, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT r.id, r.name,
+		       COALESCE(r.owner_id = $1, false),
+		       mo.user_id IS NOT NULL,
+		       p.joined_at, p.last_seen
+		FROM rooms r
+		LEFT JOIN room_participants p ON p.room_id = r.id AND p.user_id = $1
+		LEFT JOIN room_moderators mo ON mo.room_id = r.id AND mo.user_id = $1
+		WHERE r.owner_id = $1 OR p.user_id IS NOT NULL OR mo.user_id IS NOT NULL
+		ORDER BY r.created_at, r.id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Membership
+	for rows.Next() {
+		var m domain.Membership
+		if err := rows.Scan(&m.RoomID, &m.RoomName, &m.Owner, &m.Moderator, &m.JoinedAt, &m.LastSeen); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
+
 type ParticipantDetailedRow struct {
 	UserID      int64
 	DisplayName *string
 	AvatarURL   *string
 	JoinedAt    time.Time
 	LastSeen    time.Time
-}
 
-// activeWithin — окно «онлайн»; участники со свежим last_seen.
-func (r *ParticipantRepository) ListDetailed(ctx context.Context, roomID string, activeWithin time.Duration) ([]ParticipantDetailedRow, error) {
-	secs := int64(activeWithin / time.Second)
+	// снапшот presence (см. PresenceRepository)
+	Presence domain.Presence
+}
 
+// ListDetailed — все участники комнаты с последним сохранённым presence.
+// Онлайн/оффлайн решает сервис (presence + окно heartbeat).
+func (r *ParticipantRepository) ListDetailed(ctx context.Context, roomID string) ([]ParticipantDetailedRow, error) {
 	const q = `
 SELECT m.user_id,
        u.display_name,
        u.avatar_url,
        m.joined_at,
-       m.last_seen
+       m.last_seen,
+       m.presence_status,
+       m.mic_on,
+       m.cam_on,
+       m.screen_on,
+       m.hand_raised_at,
+       m.status_text,
+       m.presence_updated_at
 FROM public.room_participants AS m
 JOIN public.users AS u ON u.id = m.user_id
 WHERE m.room_id = $1
-  AND m.last_seen > NOW() - ($2::int * INTERVAL '1 second')
 ORDER BY u.display_name NULLS LAST, m.joined_at;
 `
-	rows, err := r.db.Query(ctx, q, roomID, secs)
+	rows, err := r.db.Query(ctx, q, roomID)
 	if err != nil {
 		return nil, err
 	}
@@ -154,9 +220,18 @@ ORDER BY u.display_name NULLS LAST, m.joined_at;
 			&row.AvatarURL,
 			&row.JoinedAt,
 			&row.LastSeen,
+			&row.Presence.Status,
+			&row.Presence.Mic,
+			&row.Presence.Cam,
+			&row.Presence.Screen,
+			&row.Presence.HandRaisedAt,
+			&row.Presence.StatusText,
+			&row.Presence.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
+		row.Presence.RoomID = roomID
+		row.Presence.UserID = row.UserID
 		out = append(out, row)
 	}
 
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PlanRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPlanRepository(db *pgxpool.Pool) *PlanRepository {
+	return &PlanRepository{db: db}
+}
+
+// Get — тариф пользователя; ok=false — записи нет.
+func (r *PlanRepository) Get(ctx context.Context, userID int64) (plan string, ok bool, err error) {
+	err = r.db.QueryRow(ctx, `SELECT plan FROM user_plans WHERE user_id=$1`, userID).Scan(&plan)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return plan, true, nil
+}
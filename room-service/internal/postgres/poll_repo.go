@@ -0,0 +1,280 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PollRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPollRepository(db *pgxpool.Pool) *PollRepository {
+	return &PollRepository{db: db}
+}
+
+// todo: перенести в отдельный файл queries.go
+const pollColumns = `id, room_id, created_by, question, multi, quiz, results_mode, status, closes_at, created_at, closed_at`
+
+func scanPoll(row pgx.Row, p *domain.Poll) error {
+	return row.Scan(&p.ID, &p.RoomID, &p.CreatedBy, &p.Question, &p.Multi, &p.Quiz,
+		&p.ResultsMode, &p.Status, &p.ClosesAt, &p.CreatedAt, &p.ClosedAt)
+}
+
+func (r *PollRepository) Create(ctx context.Context, p *domain.Poll) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = scanPoll(tx.QueryRow(ctx, `
+		INSERT INTO room_polls (room_id, created_by, question, multi, quiz, results_mode, closes_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+pollColumns,
+		p.RoomID, p.CreatedBy, p.Question, p.Multi, p.Quiz, p.ResultsMode, p.ClosesAt), p)
+	if err != nil {
+		return err
+	}
+	for _, o := range p.Options {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO room_poll_options (poll_id, idx, text, is_correct)
+			VALUES ($1, $2, $3, $4)
+		`, p.ID, o.Idx, o.Text, o.Correct); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (r *PollRepository) Get(ctx context.Context, id string) (*domain.Poll, error) {
+	var p domain.Poll
+	if err := scanPoll(r.db.QueryRow(ctx, `SELECT `+pollColumns+` FROM room_polls WHERE id=$1`, id), &p); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrPollNotFound
+		}
+		return nil, err
+	}
+	if err := r.loadOptions(ctx, []*domain.Poll{&p}); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListByRoom — опросы комнаты (onlyOpen — только открытые), от старых к новым.
+func (r *PollRepository) ListByRoom(ctx context.Context, roomID string, onlyOpen bool) ([]domain.Poll, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+pollColumns+`
+		FROM room_polls
+		WHERE room_id=$1 AND (NOT $2 OR status='open')
+		ORDER BY created_at, id
+	`, roomID, onlyOpen)
+	if err != nil {
+		return nil, err
+	}
+	return r.collect(ctx, rows)
+}
+
+// OpenTimed — открытые опросы с таймером (для восстановления таймеров после рестарта).
+func (r *PollRepository) OpenTimed(ctx context.Context) ([]domain.Poll, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+pollColumns+`
+		FROM room_polls
+		WHERE status='open' AND closes_at IS NOT NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	return r.collect(ctx, rows)
+}
+
+// Vote — один голос на пользователя (PK в room_poll_votes).
+// Опрос блокируем FOR SHARE, чтобы голос не проскочил параллельно с закрытием.
+func (r *PollRepository) Vote(ctx context.Context, pollID string, userID int64, options []int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		status   domain.PollStatus
+		closesAt *time.Time
+	)
+	err = tx.QueryRow(ctx, `SELECT status, closes_at FROM room_polls WHERE id=$1 FOR SHARE`, pollID).Scan(&status, &closesAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ErrPollNotFound
+		}
+		return err
+	}
+	if status != domain.PollOpen || (closesAt != nil && !time.Now().Before(*closesAt)) {
+		return domain.ErrPollClosed
+	}
+
+	cmd, err := tx.Exec(ctx, `
+		INSERT INTO room_poll_votes (poll_id, user_id, options)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (poll_id, user_id) DO NOTHING
+	`, pollID, userID, options)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrAlreadyVoted
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Close — false, если опрос уже был закрыт.
+func (r *PollRepository) Close(ctx context.Context, id string) (bool, error) {
+	cmd, err := r.db.Exec(ctx, `
+		UPDATE room_polls SET status='closed', closed_at=now()
+		WHERE id=$1 AND status='open'
+	`, id)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() > 0, nil
+}
+
+func (r *PollRepository) Results(ctx context.Context, p *domain.Poll) (*domain.PollResults, error) {
+	res := &domain.PollResults{PollID: p.ID, Counts: make([]int64, len(p.Options))}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT o.idx, count(v.user_id)
+		FROM room_poll_options o
+		LEFT JOIN room_poll_votes v ON v.poll_id = o.poll_id AND o.idx = ANY(v.options)
+		WHERE o.poll_id = $1
+		GROUP BY o.idx
+	`, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			idx int
+			n   int64
+		)
+		if err := rows.Scan(&idx, &n); err != nil {
+			return nil, err
+		}
+		if idx >= 0 && idx < len(res.Counts) {
+			res.Counts[idx] = n
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := r.db.QueryRow(ctx, `SELECT count(*) FROM room_poll_votes WHERE poll_id=$1`, p.ID).Scan(&res.Voters); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (r *PollRepository) Votes(ctx context.Context, pollID string) ([]domain.PollVote, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT poll_id, user_id, options, created_at
+		FROM room_poll_votes
+		WHERE poll_id=$1
+		ORDER BY created_at, user_id
+	`, pollID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.PollVote
+	for rows.Next() {
+		var v domain.PollVote
+		if err := rows.Scan(&v.PollID, &v.UserID, &v.Options, &v.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, rows.Err()
+}
+
+// UserVote — варианты, за которые пользователь уже проголосовал (nil — не голосовал).
+func (r *PollRepository) UserVote(ctx context.Context, pollID string, userID int64) ([]int, error) {
+	var opts []int
+	err := r.db.QueryRow(ctx, `SELECT options FROM room_poll_votes WHERE poll_id=$1 AND user_id=$2`, pollID, userID).Scan(&opts)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return opts, nil
+}
+
+func (r *PollRepository) collect(ctx context.Context, rows pgx.Rows) ([]domain.Poll, error) {
+	var out []domain.Poll
+	for rows.Next() {
+		var p domain.Poll
+		if err := scanPoll(rows, &p); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		out = append(out, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	ptrs := make([]*domain.Poll, len(out))
+	for i := range out {
+		ptrs[i] = &out[i]
+	}
+	if err := r.loadOptions(ctx, ptrs); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *PollRepository) loadOptions(ctx context.Context, polls []*domain.Poll) error {
+	if len(polls) == 0 {
+		return nil
+	}
+	ids := make([]string, 0, len(polls))
+	byID := make(map[string]*domain.Poll, len(polls))
+	for _, p := range polls {
+		ids = append(ids, p.ID)
+		byID[p.ID] = p
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT poll_id, idx, text, is_correct
+		FROM room_poll_options
+		WHERE poll_id = ANY($1::uuid[])
+		ORDER BY poll_id, idx
+	`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			pollID string
+			o      domain.PollOption
+		)
+		if err := rows.Scan(&pollID, &o.Idx, &o.Text, &o.Correct); err != nil {
+			return err
+		}
+		if p := byID[pollID]; p != nil {
+			p.Options = append(p.Options, o)
+		}
+	}
+	return rows.Err()
+}
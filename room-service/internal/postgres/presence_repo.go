@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PresenceRepository — снапшоты presence в колонках room_participants.
+type PresenceRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPresenceRepository(db *pgxpool.Pool) *PresenceRepository {
+	return &PresenceRepository{db: db}
+}
+
+func (r *PresenceRepository) Get(ctx context.Context, roomID string, userID int64) (*domain.Presence, error) {
+	var p domain.Presence
+	err := r.db.QueryRow(ctx, `
+		SELECT room_id, user_id, presence_status, mic_on, cam_on, screen_on,
+		       hand_raised_at, status_text, presence_updated_at
+		FROM room_participants
+		WHERE room_id=$1 AND user_id=$2
+	`, roomID, userID).Scan(
+		&p.RoomID, &p.UserID, &p.Status, &p.Mic, &p.Cam, &p.Screen,
+		&p.HandRaisedAt, &p.StatusText, &p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrNotInRoom
+		}
+		return nil, err
+	}
+	return &p, nil
+}
+
+// SaveBatch пишет снапшоты одним batch. Участника могли уже удалить (leave) — это не ошибка.
+// Более старый снапшот не перетирает более новый (периодический flush vs запись при disconnect).
+func (r *PresenceRepository) SaveBatch(ctx context.Context, items []domain.Presence) error {
+	if len(items) == 0 {
+		return nil
+	}
+	b := &pgx.Batch{}
+	for _, p := range items {
+		b.Queue(`
+			UPDATE room_participants
+			SET presence_status=$3, mic_on=$4, cam_on=$5, screen_on=$6,
+			    hand_raised_at=$7, status_text=$8, presence_updated_at=$9
+			WHERE room_id=$1 AND user_id=$2 AND presence_updated_at <= $9
+		`, p.RoomID, p.UserID, p.Status, p.Mic, p.Cam, p.Screen, p.HandRaisedAt, p.StatusText, p.UpdatedAt)
+	}
+	return r.db.SendBatch(ctx, b).Close()
+}
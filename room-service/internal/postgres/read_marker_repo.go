@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReadMarkerRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReadMarkerRepository(db *pgxpool.Pool) *ReadMarkerRepository {
+	return &ReadMarkerRepository{db: db}
+}
+
+// Set двигает маркер вперёд (по created_at,id — как в истории).
+// moved=false — маркер уже стоял на этом или более новом сообщении, рассылать нечего.
+func (r *ReadMarkerRepository) Set(ctx context.Context, roomID string, userID int64, msgID string) (m *domain.ReadMarker, moved bool, err error) {
+	// todo: перенести в отдельный файл queries.go
+	var rm domain.ReadMarker
+	err = r.db.QueryRow(ctx, `
+		INSERT INTO room_read_markers AS rm (room_id, user_id, msg_id, msg_created_at)
+		SELECT m.room_id, $2, m.id, m.created_at
+		FROM room_messages m
+		WHERE m.room_id = $1 AND m.id = $3
+		ON CONFLICT (room_id, user_id) DO UPDATE
+		SET msg_id = EXCLUDED.msg_id,
+		    msg_created_at = EXCLUDED.msg_created_at,
+		    updated_at = now()
+		WHERE (rm.msg_created_at, rm.msg_id) < (EXCLUDED.msg_created_at, EXCLUDED.msg_id)
+		RETURNING room_id, user_id, msg_id, msg_created_at, updated_at
+	`, roomID, userID, msgID).Scan(&rm.RoomID, &rm.UserID, &rm.MsgID, &rm.MsgCreatedAt, &rm.UpdatedAt)
+	if err == nil {
+		return &rm, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, err
+	}
+
+	// ничего не вставили/не обновили: либо сообщения нет, либо маркер уже новее
+	cur, err := r.Get(ctx, roomID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	if cur == nil {
+		return nil, false, domain.ErrMessageNotFound
+	}
+	var exists bool
+	if err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM room_messages WHERE room_id=$1 AND id=$2)`,
+		roomID, msgID).Scan(&exists); err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return nil, false, domain.ErrMessageNotFound
+	}
+
+	return cur, false, nil
+}
+
+// Get — маркер пользователя или nil, если он ещё ничего не читал.
+func (r *ReadMarkerRepository) Get(ctx context.Context, roomID string, userID int64) (*domain.ReadMarker, error) {
+	var rm domain.ReadMarker
+	err := r.db.QueryRow(ctx, `
+		SELECT room_id, user_id, msg_id, msg_created_at, updated_at
+		FROM room_read_markers
+		WHERE room_id=$1 AND user_id=$2
+	`, roomID, userID).Scan(&rm.RoomID, &rm.UserID, &rm.MsgID, &rm.MsgCreatedAt, &rm.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rm, nil
+}
+
+// List — маркеры всех, кто что-то читал в комнате ("seen by").
+func (r *ReadMarkerRepository) List(ctx context.Context, roomID string) ([]domain.ReadMarker, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT room_id, user_id, msg_id, msg_created_at, updated_at
+		FROM room_read_markers
+		WHERE room_id=$1
+		ORDER BY user_id
+	`, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.ReadMarker
+	for rows.Next() {
+		var rm domain.ReadMarker
+		if err := rows.Scan(&rm.RoomID, &rm.UserID, &rm.MsgID, &rm.MsgCreatedAt, &rm.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, rm)
+	}
+	return out, rows.Err()
+}
+
+// UnreadCount — сколько чужих сообщений новее маркера пользователя.
+func (r *ReadMarkerRepository) UnreadCount(ctx context.Context, roomID string, userID int64) (int64, error) {
+	var n int64
+	err := r.db.QueryRow(ctx, `
+		SELECT count(*)
+		FROM room_messages m
+		LEFT JOIN room_read_markers rm ON rm.room_id = m.room_id AND rm.user_id = $2
+		WHERE m.room_id = $1
+		  AND m.user_id IS DISTINCT FROM $2
+		  AND (rm.msg_id IS NULL OR (m.created_at, m.id) > (rm.msg_created_at, rm.msg_id))
+	`, roomID, userID).Scan(&n)
+	return n, err
+}
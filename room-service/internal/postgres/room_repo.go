@@ -2,9 +2,14 @@ package postgres
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/cwrk-planet/room-service/internal/domain"
 
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/cwrk-planet/events/pkg/outbox"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -17,23 +22,49 @@ func NewRoomRepository(db *pgxpool.Pool) *RoomRepository {
 	return &RoomRepository{db: db}
 }
 
+const roomColumns = `id, name, max_participants, owner_id, parent_id, created_at, description, topic, language, tags`
+
+func scanRoom(row pgx.Row, rm *domain.Room, extra ...any) error {
+	dst := append([]any{&rm.ID, &rm.Name, &rm.MaxParticipants, &rm.OwnerID, &rm.ParentID, &rm.CreatedAt,
+		&rm.Description, &rm.Topic, &rm.Language, &rm.Tags}, extra...)
+	return row.Scan(dst...)
+}
+
 func (r *RoomRepository) Create(ctx context.Context, room *domain.Room) error {
+	if room.Tags == nil {
+		room.Tags = []string{}
+	}
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	query := `
-		INSERT INTO rooms (name, max_participants)
-		VALUES ($1, $2)
+		INSERT INTO rooms (name, max_participants, owner_id, parent_id, description, topic, language, tags)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id, created_at`
-	err := r.db.QueryRow(ctx, query, room.Name, room.MaxParticipants).Scan(&room.ID, &room.CreatedAt)
+	err = tx.QueryRow(ctx, query, room.Name, room.MaxParticipants, room.OwnerID, room.ParentID,
+		room.Description, room.Topic, room.Language, room.Tags).Scan(&room.ID, &room.CreatedAt)
 	if err != nil {
 		return err
 	}
-	return nil
+	if _, err := outbox.Add(ctx, tx, EventSource, events.RoomCreated{
+		RoomID:          room.ID,
+		Name:            room.Name,
+		OwnerID:         room.OwnerID,
+		MaxParticipants: room.MaxParticipants,
+		CreatedAt:       room.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
 func (r *RoomRepository) Get(ctx context.Context, id string) (*domain.Room, error) {
 	var rm domain.Room
-	query := `SELECT id, name, max_participants, created_at FROM rooms WHERE id=$1`
-	err := r.db.QueryRow(ctx, query, id).
-		Scan(&rm.ID, &rm.Name, &rm.MaxParticipants, &rm.CreatedAt)
+	query := `SELECT ` + roomColumns + ` FROM rooms WHERE id=$1`
+	err := scanRoom(r.db.QueryRow(ctx, query, id), &rm)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, domain.ErrRoomNotFound
@@ -43,28 +74,79 @@ func (r *RoomRepository) Get(ctx context.Context, id string) (*domain.Room, erro
 	return &rm, nil
 }
 
-func (r *RoomRepository) List(ctx context.Context, limit int, cursorStr string) ([]domain.Room, string, error) {
-	cur, err := DecodeCursor(cursorStr)
+// UpdateMeta — название, описание, тема, язык, теги (только переданные поля).
+func (r *RoomRepository) UpdateMeta(ctx context.Context, rm *domain.Room) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE rooms
+		SET name=$2, description=$3, topic=$4, language=$5, tags=$6
+		WHERE id=$1
+	`, rm.ID, rm.Name, rm.Description, rm.Topic, rm.Language, rm.Tags)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrRoomNotFound
+	}
+	return nil
+}
+
+// List — комнаты верхнего уровня с фильтрами и заполненностью.
+// Счётчики участников считаются одним агрегатом по room_participants (без запроса на комнату);
+// онлайн — у кого heartbeat не старше onlineSince.
+func (r *RoomRepository) List(ctx context.Context, f domain.RoomFilter, onlineSince time.Time) ([]domain.Room, string, error) {
+	cur, err := DecodeCursor(f.Cursor)
 	if err != nil {
 		return nil, "", err
 	}
+	if cur != nil && cur.Sort != "" && cur.Sort != f.Sort {
+		return nil, "", fmt.Errorf("%w: cursor is for sort=%s", ErrInvalidCursor, cur.Sort)
+	}
 
-	query := `
-		SELECT id, name, max_participants, created_at
-		FROM rooms
-		WHERE ($1::timestamptz IS NULL OR created_at < $1
-		       OR (created_at = $1 AND id < $2))
-		ORDER BY created_at DESC, id DESC
-		LIMIT $3`
-
-	var createdAt any
-	var id any
-	if cur != nil {
-		createdAt = cur.CreatedAt
-		id = cur.ID
+	// $1..$6 — фильтры, $7 — limit, $8.. — курсор
+	args := []any{onlineSince, likePattern(f.Query), f.Tag, f.Language, f.FreeSeats, f.LiveNow, f.Limit}
+	after, order := "TRUE", "created_at DESC, id DESC"
+	if f.Sort == domain.RoomSortActivity {
+		// при равной активности — как в сортировке по созданию, (created_at, id) однозначен
+		order = "online DESC, activity_at DESC, created_at DESC, id DESC"
+		if cur != nil {
+			after = "(online, activity_at, created_at, id) < ($8, $9, $10, $11::uuid)"
+			args = append(args, cur.Online, cur.ActivityAt, cur.CreatedAt, cur.ID)
+		}
+	} else if cur != nil {
+		after = "(created_at, id) < ($8, $9::uuid)"
+		args = append(args, cur.CreatedAt, cur.ID)
 	}
 
-	rows, err := r.db.Query(ctx, query, createdAt, id, limit)
+	query := `
+		WITH stats AS (
+			SELECT room_id,
+			       COUNT(*) AS participants,
+			       COUNT(*) FILTER (WHERE last_seen >= $1) AS online,
+			       MAX(last_seen) AS last_seen
+			FROM room_participants
+			GROUP BY room_id
+		), items AS (
+			SELECT r.id, r.name, r.max_participants, r.owner_id, r.parent_id, r.created_at,
+			       r.description, r.topic, r.language, r.tags,
+			       COALESCE(s.participants, 0) AS participants,
+			       COALESCE(s.online, 0) AS online,
+			       GREATEST(r.created_at, COALESCE(s.last_seen, r.created_at)) AS activity_at
+			FROM rooms r
+			LEFT JOIN stats s ON s.room_id = r.id
+			WHERE r.parent_id IS NULL -- breakout-комнаты в общем списке не показываем
+		)
+		SELECT ` + roomColumns + `, participants, online, activity_at
+		FROM items
+		WHERE ($2 = '' OR name ILIKE $2 OR topic ILIKE $2)
+		  AND ($3 = '' OR $3 = ANY(tags))
+		  AND ($4 = '' OR language = $4)
+		  AND (NOT $5 OR participants < max_participants)
+		  AND (NOT $6 OR online > 0)
+		  AND ` + after + `
+		ORDER BY ` + order + `
+		LIMIT $7`
+
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, "", err
 	}
@@ -72,24 +154,69 @@ func (r *RoomRepository) List(ctx context.Context, limit int, cursorStr string)
 
 	var rooms []domain.Room
 	for rows.Next() {
-		var r domain.Room
-		if err := rows.Scan(&r.ID, &r.Name, &r.MaxParticipants, &r.CreatedAt); err != nil {
+		var rm domain.Room
+		if err := scanRoom(rows, &rm, &rm.Participants, &rm.Online, &rm.ActivityAt); err != nil {
 			return nil, "", err
 		}
-		rooms = append(rooms, r)
+		rooms = append(rooms, rm)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
 	}
 
 	var nextCursor string
-	if len(rooms) == limit {
+	if len(rooms) == f.Limit {
 		last := rooms[len(rooms)-1]
 		cur := Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if f.Sort == domain.RoomSortActivity {
+			cur.Sort, cur.Online, cur.ActivityAt = f.Sort, last.Online, last.ActivityAt
+		}
 		nextCursor, _ = EncodeCursor(cur)
 	}
 
 	return rooms, nextCursor, nil
 }
 
+// likePattern — подстрока для ILIKE с экранированием %, _ и \.
+func likePattern(q string) string {
+	if q == "" {
+		return ""
+	}
+	q = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(q)
+	return "%" + q + "%"
+}
+
 func (r *RoomRepository) Delete(ctx context.Context, id string) error {
 	_, err := r.db.Exec(ctx, `DELETE FROM rooms WHERE id=$1`, id)
 	return err
 }
+
+// IsModerator — владелец комнаты или назначенный модератор.
+// В breakout-комнате модераторы основной комнаты тоже модераторы.
+func (r *RoomRepository) IsModerator(ctx context.Context, roomID string, userID int64) (bool, error) {
+	var ok bool
+	err := r.db.QueryRow(ctx, `
+		WITH scope AS (
+			SELECT id FROM rooms WHERE id=$1
+			UNION
+			SELECT parent_id FROM rooms WHERE id=$1 AND parent_id IS NOT NULL
+		)
+		SELECT EXISTS (SELECT 1 FROM rooms WHERE id IN (SELECT id FROM scope) AND owner_id=$2)
+		    OR EXISTS (SELECT 1 FROM room_moderators WHERE room_id IN (SELECT id FROM scope) AND user_id=$2)
+	`, roomID, userID).Scan(&ok)
+	return ok, err
+}
+
+func (r *RoomRepository) AddModerator(ctx context.Context, roomID string, userID, grantedBy int64) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO room_moderators (room_id, user_id, granted_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, roomID, userID, grantedBy)
+	return err
+}
+
+func (r *RoomRepository) RemoveModerator(ctx context.Context, roomID string, userID int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM room_moderators WHERE room_id=$1 AND user_id=$2`, roomID, userID)
+	return err
+}
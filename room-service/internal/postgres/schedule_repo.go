@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ScheduleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScheduleRepository(db *pgxpool.Pool) *ScheduleRepository {
+	return &ScheduleRepository{db: db}
+}
+
+// todo: queries.go
+
+const scheduleColumns = `s.room_id, r.name, s.starts_at, s.duration_sec, s.rrule, s.timezone,
+	s.open_before_sec, s.created_by, s.created_at, s.updated_at`
+
+// Upsert — одна серия на комнату, повторный вызов заменяет расписание.
+func (r *ScheduleRepository) Upsert(ctx context.Context, sc *domain.Schedule) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO room_schedules (room_id, starts_at, duration_sec, rrule, timezone, open_before_sec, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (room_id) DO UPDATE
+		SET starts_at = EXCLUDED.starts_at,
+		    duration_sec = EXCLUDED.duration_sec,
+		    rrule = EXCLUDED.rrule,
+		    timezone = EXCLUDED.timezone,
+		    open_before_sec = EXCLUDED.open_before_sec,
+		    updated_at = now()
+		RETURNING created_by, created_at, updated_at
+	`, sc.RoomID, sc.StartsAt, int64(sc.Duration/time.Second), sc.RRule, sc.Timezone,
+		int64(sc.OpenBefore/time.Second), sc.CreatedBy,
+	).Scan(&sc.CreatedBy, &sc.CreatedAt, &sc.UpdatedAt)
+}
+
+func (r *ScheduleRepository) Get(ctx context.Context, roomID string) (*domain.Schedule, error) {
+	row := r.db.QueryRow(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM room_schedules s
+		JOIN rooms r ON r.id = s.room_id
+		WHERE s.room_id = $1
+	`, roomID)
+	sc, err := scanSchedule(row)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return sc, nil
+}
+
+// List — все расписания (их немного: одна серия на комнату).
+func (r *ScheduleRepository) List(ctx context.Context) ([]domain.Schedule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM room_schedules s
+		JOIN rooms r ON r.id = s.room_id
+		ORDER BY s.room_id
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Schedule
+	for rows.Next() {
+		sc, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *sc)
+	}
+	return out, rows.Err()
+}
+
+// ListForUser — расписания комнат, где пользователь участник, владелец или модератор
+// (те же комнаты, что ParticipantRepository.ListByUser).
+func (r *ScheduleRepository) ListForUser(ctx context.Context, userID int64) ([]domain.Schedule, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+scheduleColumns+`
+		FROM room_schedules s
+		JOIN rooms r ON r.id = s.room_id
+		WHERE r.owner_id = $1
+		   OR EXISTS (SELECT 1 FROM room_participants p WHERE p.room_id = r.id AND p.user_id = $1)
+		   OR EXISTS (SELECT 1 FROM room_moderators mo WHERE mo.room_id = r.id AND mo.user_id = $1)
+		ORDER BY s.room_id
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.Schedule
+	for rows.Next() {
+		sc, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *sc)
+	}
+	return out, rows.Err()
+}
+
+func (r *ScheduleRepository) Delete(ctx context.Context, roomID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM room_schedules WHERE room_id = $1`, roomID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func scanSchedule(row pgx.Row) (*domain.Schedule, error) {
+	var (
+		sc                domain.Schedule
+		durSec, beforeSec int64
+	)
+	if err := row.Scan(&sc.RoomID, &sc.RoomName, &sc.StartsAt, &durSec, &sc.RRule, &sc.Timezone,
+		&beforeSec, &sc.CreatedBy, &sc.CreatedAt, &sc.UpdatedAt); err != nil {
+		return nil, err
+	}
+	sc.Duration = time.Duration(durSec) * time.Second
+	sc.OpenBefore = time.Duration(beforeSec) * time.Second
+	return &sc, nil
+}
@@ -0,0 +1,269 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// todo: queries.go
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error {
+	return r.db.QueryRow(ctx, `
+		INSERT INTO webhook_subscriptions (owner_id, url, secret, events)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, active, created_at
+	`, s.OwnerID, s.URL, s.Secret, s.Events).Scan(&s.ID, &s.Active, &s.CreatedAt)
+}
+
+func (r *WebhookRepository) CountSubscriptions(ctx context.Context, ownerID int64) (int, error) {
+	var n int
+	err := r.db.QueryRow(ctx, `SELECT count(*) FROM webhook_subscriptions WHERE owner_id=$1`, ownerID).Scan(&n)
+	return n, err
+}
+
+// ListSubscriptions — подписки владельца, без секретов.
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context, ownerID int64) ([]domain.WebhookSubscription, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, owner_id, url, events, active, created_at
+		FROM webhook_subscriptions
+		WHERE owner_id=$1
+		ORDER BY created_at, id
+	`, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookSubscription
+	for rows.Next() {
+		var s domain.WebhookSubscription
+		if err := rows.Scan(&s.ID, &s.OwnerID, &s.URL, &s.Events, &s.Active, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, ownerID int64, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id=$1 AND owner_id=$2`, id, ownerID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// AddEvent — событие для раскладки по подпискам. ownerID — владелец комнаты события,
+// nil — системное событие (см. WebhookService.Publish). Повтор с тем же id игнорируется.
+func (r *WebhookRepository) AddEvent(ctx context.Context, id, typ string, ownerID *int64, payload []byte, createdAt time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO webhook_events (id, type, owner_id, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO NOTHING
+	`, id, typ, ownerID, payload, createdAt)
+	return err
+}
+
+// FanOut — разложить новые события по доставкам активным подписчикам; возвращает число событий.
+// SKIP LOCKED — несколько инстансов не мешают друг другу.
+func (r *WebhookRepository) FanOut(ctx context.Context, limit int) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		WITH ev AS (
+		  SELECT id, type, owner_id
+		  FROM webhook_events
+		  WHERE dispatched_at IS NULL
+		  ORDER BY created_at
+		  LIMIT $1
+		  FOR UPDATE SKIP LOCKED
+		), ins AS (
+		  INSERT INTO webhook_deliveries (subscription_id, event_id)
+		  SELECT s.id, ev.id
+		  FROM ev
+		  JOIN webhook_subscriptions s
+		    ON s.active
+		   AND ev.type = ANY(s.events)
+		   AND (ev.owner_id IS NULL OR s.owner_id = ev.owner_id)
+		  ON CONFLICT (subscription_id, event_id) DO NOTHING
+		)
+		UPDATE webhook_events e
+		SET dispatched_at = now()
+		FROM ev
+		WHERE e.id = ev.id
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ClaimDue — забрать доставки, которым пора отправляться. next_attempt_at сдвигается на lease,
+// чтобы другой инстанс (или этот после падения) не отправил то же самое параллельно.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, `
+		WITH due AS (
+		  SELECT id
+		  FROM webhook_deliveries
+		  WHERE status = 'pending' AND next_attempt_at <= now()
+		  ORDER BY next_attempt_at
+		  LIMIT $1
+		  FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due, webhook_subscriptions s, webhook_events e
+		WHERE d.id = due.id AND s.id = d.subscription_id AND e.id = d.event_id
+		RETURNING d.id, d.subscription_id, d.event_id, e.type, e.payload, e.created_at,
+		          d.attempts, d.created_at, s.url, s.secret
+	`, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		d := domain.WebhookDelivery{Status: domain.DeliveryPending}
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.EventCreatedAt,
+			&d.Attempts, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+func (r *WebhookRepository) MarkDelivered(ctx context.Context, id string, code int) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status='delivered', attempts=attempts+1, last_status=$2, last_error=NULL, delivered_at=now()
+		WHERE id=$1
+	`, id, code)
+	return err
+}
+
+// MarkFailed — неудачная попытка: следующая в next, либо dead.
+func (r *WebhookRepository) MarkFailed(ctx context.Context, id string, code *int, msg string, next time.Time, dead bool) error {
+	status := domain.DeliveryPending
+	if dead {
+		status = domain.DeliveryDead
+	}
+	_, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status=$2, attempts=attempts+1, last_status=$3, last_error=$4, next_attempt_at=$5
+		WHERE id=$1
+	`, id, string(status), code, msg, next)
+	return err
+}
+
+// ListDeliveries — доставки подписки владельца, новые сверху; status пустой — все.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, ownerID int64, subID string, status domain.WebhookDeliveryStatus, after string, limit int) ([]domain.WebhookDelivery, string, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	cur, err := DecodeCursor(after)
+	if err != nil {
+		return nil, "", fmt.Errorf("decode cursor: %w", err)
+	}
+	var createdAt, id any
+	if cur != nil {
+		createdAt, id = cur.CreatedAt, cur.ID
+	}
+
+	var exists bool
+	if err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM webhook_subscriptions WHERE id=$1 AND owner_id=$2)`,
+		subID, ownerID).Scan(&exists); err != nil {
+		return nil, "", err
+	}
+	if !exists {
+		return nil, "", domain.ErrWebhookNotFound
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT d.id, d.subscription_id, d.event_id, e.type, d.status, d.attempts, d.next_attempt_at,
+		       d.last_status, d.last_error, d.created_at, d.delivered_at
+		FROM webhook_deliveries d
+		JOIN webhook_events e ON e.id = d.event_id
+		WHERE d.subscription_id = $1
+		  AND ($2::text = '' OR d.status = $2)
+		  AND (
+		    $3::timestamptz IS NULL
+		    OR d.created_at < $3
+		    OR (d.created_at = $3 AND d.id < $4::uuid)
+		  )
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $5
+	`, subID, string(status), createdAt, id, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		var st string
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &st, &d.Attempts, &d.NextAttemptAt,
+			&d.LastStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt); err != nil {
+			return nil, "", err
+		}
+		d.Status = domain.WebhookDeliveryStatus(st)
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var next string
+	if len(out) == limit {
+		last := out[len(out)-1]
+		if c, e := EncodeCursor(Cursor{CreatedAt: last.CreatedAt, ID: last.ID}); e == nil {
+			next = c
+		}
+	}
+	return out, next, nil
+}
+
+// Redeliver — поставить доставку в очередь заново (в том числе dead), счётчик попыток с нуля.
+func (r *WebhookRepository) Redeliver(ctx context.Context, ownerID int64, id string) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	var st string
+	err := r.db.QueryRow(ctx, `
+		UPDATE webhook_deliveries d
+		SET status='pending', attempts=0, next_attempt_at=now(), delivered_at=NULL
+		FROM webhook_subscriptions s, webhook_events e
+		WHERE d.id=$1 AND s.id=d.subscription_id AND s.owner_id=$2 AND e.id=d.event_id
+		RETURNING d.id, d.subscription_id, d.event_id, e.type, d.status, d.attempts, d.next_attempt_at,
+		          d.last_status, d.last_error, d.created_at, d.delivered_at
+	`, id, ownerID).Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &st, &d.Attempts, &d.NextAttemptAt,
+		&d.LastStatus, &d.LastError, &d.CreatedAt, &d.DeliveredAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrDeliveryNotFound
+		}
+		return nil, err
+	}
+	d.Status = domain.WebhookDeliveryStatus(st)
+	return &d, nil
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/postgres"
+)
+
+type AttachmentService struct {
+	attachmentRepo  *postgres.AttachmentRepository
+	participantRepo *postgres.ParticipantRepository
+
+	roomQuotaBytes int64
+}
+
+func NewAttachmentService(attachmentRepo *postgres.AttachmentRepository, participantRepo *postgres.ParticipantRepository, roomQuotaBytes int64) *AttachmentService {
+	return &AttachmentService{
+		attachmentRepo:  attachmentRepo,
+		participantRepo: participantRepo,
+		roomQuotaBytes:  roomQuotaBytes,
+	}
+}
+
+// Register записывает метаданные уже загруженного файла.
+// Загружать в комнату может только её участник, суммарный объём ограничен квотой.
+func (s *AttachmentService) Register(ctx context.Context, a *domain.Attachment) error {
+	a.FileName = strings.TrimSpace(a.FileName)
+	if a.ID == "" || a.FileName == "" || a.MimeType == "" || a.StorageKey == "" || a.SizeBytes <= 0 {
+		return domain.ErrAttachmentInvalid
+	}
+	if err := s.requireMember(ctx, a.RoomID, a.UserID); err != nil {
+		return err
+	}
+
+	return s.attachmentRepo.Create(ctx, a, s.roomQuotaBytes)
+}
+
+// Get возвращает вложение, если userID состоит в комнате.
+func (s *AttachmentService) Get(ctx context.Context, roomID, id string, userID int64) (*domain.Attachment, error) {
+	if err := s.requireMember(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.attachmentRepo.Get(ctx, roomID, id)
+}
+
+// Usage — занятое место и квота комнаты (0 — без ограничения).
+func (s *AttachmentService) Usage(ctx context.Context, roomID string) (used, quota int64, err error) {
+	used, err = s.attachmentRepo.UsedBytes(ctx, roomID)
+	return used, s.roomQuotaBytes, err
+}
+
+func (s *AttachmentService) requireMember(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.participantRepo.Exists(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrNotInRoom
+	}
+	return nil
+}
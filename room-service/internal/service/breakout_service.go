@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// BreakoutService — разбиение комнаты на дочерние на время: распределение участников,
+// обратный отсчёт и возврат всех в основную комнату. Запускают и ведут модераторы
+// основной комнаты, они же могут ходить между группами.
+// Таймеры отсчёта в памяти, после рестарта — Restore.
+type BreakoutService struct {
+	repo     BreakoutStore
+	roomRepo RoomModerators
+	partRepo BreakoutParticipants
+
+	mu       sync.Mutex
+	timers   map[string]*time.Timer // parentID -> ближайшая точка отсчёта
+	onChange func(ev domain.BreakoutEvent)
+}
+
+// BreakoutStore — разбиения и распределение (postgres.BreakoutRepository).
+type BreakoutStore interface {
+	Create(ctx context.Context, b *domain.Breakout) error
+	Get(ctx context.Context, parentID string) (*domain.Breakout, error)
+	ParentOf(ctx context.Context, roomID string) (string, error)
+	ListActive(ctx context.Context) ([]domain.Breakout, error)
+	Assigned(ctx context.Context, parentID string, userID int64) (string, bool, error)
+	Assign(ctx context.Context, parentID string, userID int64, roomID string) error
+	Close(ctx context.Context, parentID string) ([]string, bool, error)
+}
+
+// BreakoutParticipants — состав комнат (postgres.ParticipantRepository).
+type BreakoutParticipants interface {
+	RoomParticipants
+	ListByRoom(ctx context.Context, roomID string) ([]domain.Participant, error)
+}
+
+func NewBreakoutService(repo BreakoutStore, roomRepo RoomModerators, partRepo BreakoutParticipants) *BreakoutService {
+	return &BreakoutService{
+		repo:     repo,
+		roomRepo: roomRepo,
+		partRepo: partRepo,
+		timers:   make(map[string]*time.Timer),
+	}
+}
+
+func (s *BreakoutService) SetOnChange(fn func(ev domain.BreakoutEvent)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// Start — создать дочерние комнаты и распределить участников.
+// roomID может быть и дочерней комнатой — тогда ErrBreakoutInvalid (вложенных нет).
+func (s *BreakoutService) Start(ctx context.Context, actorID int64, roomID string, in domain.BreakoutInput) (*domain.Breakout, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+	if parent, err := s.repo.ParentOf(ctx, roomID); err != nil {
+		return nil, err
+	} else if parent != "" {
+		return nil, domain.ErrBreakoutInvalid
+	}
+
+	if in.Count < domain.MinBreakoutRooms || in.Count > domain.MaxBreakoutRooms || len(in.Names) > in.Count {
+		return nil, domain.ErrBreakoutInvalid
+	}
+	if in.Duration < domain.MinBreakoutDuration || in.Duration > domain.MaxBreakoutDuration {
+		return nil, domain.ErrInvalidDuration
+	}
+
+	b := &domain.Breakout{
+		ParentID:  roomID,
+		EndsAt:    time.Now().Add(in.Duration).Truncate(time.Second),
+		CreatedBy: &actorID,
+		Rooms:     make([]domain.BreakoutRoom, in.Count),
+	}
+	for i := range b.Rooms {
+		name := fmt.Sprintf("Группа %d", i+1)
+		if i < len(in.Names) {
+			if n := strings.TrimSpace(in.Names[i]); n != "" {
+				name = n
+			}
+		}
+		if utf8.RuneCountInString(name) > domain.MaxBreakoutNameLen {
+			return nil, domain.ErrBreakoutInvalid
+		}
+		b.Rooms[i].Name = name
+	}
+	for uid, idx := range in.Assign {
+		if uid <= 0 || idx < 0 || idx >= in.Count {
+			return nil, domain.ErrBreakoutInvalid
+		}
+		if err := s.requireParticipant(ctx, []string{roomID}, uid); err != nil {
+			return nil, err
+		}
+		b.Rooms[idx].Users = append(b.Rooms[idx].Users, uid)
+	}
+
+	if in.Random {
+		pool, err := s.unassigned(ctx, roomID, in.Assign)
+		if err != nil {
+			return nil, err
+		}
+		rand.Shuffle(len(pool), func(i, j int) { pool[i], pool[j] = pool[j], pool[i] })
+		// начинаем с самых пустых групп, чтобы ручное распределение не перекосило размеры
+		for _, uid := range pool {
+			idx := 0
+			for i := range b.Rooms {
+				if len(b.Rooms[i].Users) < len(b.Rooms[idx].Users) {
+					idx = i
+				}
+			}
+			b.Rooms[idx].Users = append(b.Rooms[idx].Users, uid)
+		}
+	}
+
+	if err := s.repo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	moves := make(map[int64]string)
+	for _, r := range b.Rooms {
+		for _, uid := range r.Users {
+			moves[uid] = r.ID
+		}
+	}
+	s.schedule(b.ParentID, b.EndsAt)
+	s.notify(domain.BreakoutEvent{Kind: domain.BreakoutStarted, Breakout: b, Moves: moves})
+	return b, nil
+}
+
+// Assign — перевести участника в группу (или обратно в основную комнату).
+// Модератор так же переходит сам: userID == actorID.
+func (s *BreakoutService) Assign(ctx context.Context, actorID int64, roomID string, userID int64, targetID string) (*domain.Breakout, error) {
+	b, err := s.ForRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireModerator(ctx, b.ParentID, actorID); err != nil {
+		return nil, err
+	}
+	if userID <= 0 || !b.Has(targetID) {
+		return nil, domain.ErrBreakoutInvalid
+	}
+	// во время breakout строка участника может быть в любой из комнат
+	if err := s.requireParticipant(ctx, append([]string{b.ParentID}, b.Children()...), userID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Assign(ctx, b.ParentID, userID, targetID); err != nil {
+		return nil, err
+	}
+	if b, err = s.repo.Get(ctx, b.ParentID); err != nil {
+		return nil, err
+	}
+	s.notify(domain.BreakoutEvent{Kind: domain.BreakoutMoved, Breakout: b, Moves: map[int64]string{userID: targetID}})
+	return b, nil
+}
+
+// End — досрочно вернуть всех в основную комнату.
+func (s *BreakoutService) End(ctx context.Context, actorID int64, roomID string) error {
+	b, err := s.ForRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if err := s.requireModerator(ctx, b.ParentID, actorID); err != nil {
+		return err
+	}
+	return s.end(ctx, b)
+}
+
+// ForRoom — активный breakout по основной или дочерней комнате.
+func (s *BreakoutService) ForRoom(ctx context.Context, roomID string) (*domain.Breakout, error) {
+	if _, err := uuid.Parse(roomID); err != nil {
+		return nil, domain.ErrRoomNotFound
+	}
+	parent, err := s.repo.ParentOf(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if parent == "" {
+		parent = roomID
+	}
+	return s.repo.Get(ctx, parent)
+}
+
+// Resolve — в какую комнату на самом деле подключать пользователя, пришедшего в roomID:
+// распределённых в группу переводим туда, нераспределённых из чужой группы — в основную.
+// Модераторы идут куда просили.
+func (s *BreakoutService) Resolve(ctx context.Context, roomID string, userID int64) (string, error) {
+	b, err := s.ForRoom(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, domain.ErrBreakoutNotFound) {
+			return roomID, nil
+		}
+		return "", err
+	}
+	if mod, err := s.roomRepo.IsModerator(ctx, b.ParentID, userID); err != nil || mod {
+		return roomID, err
+	}
+
+	target, ok, err := s.repo.Assigned(ctx, b.ParentID, userID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		target = b.ParentID
+	}
+	if target != roomID {
+		// строку участника переносим туда же
+		if err := s.repo.Assign(ctx, b.ParentID, userID, target); err != nil {
+			return "", err
+		}
+	}
+	return target, nil
+}
+
+// Restore — после рестарта: заводим отсчёт, просроченные закрываются сразу.
+func (s *BreakoutService) Restore(ctx context.Context) error {
+	list, err := s.repo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+	for _, b := range list {
+		s.schedule(b.ParentID, b.EndsAt)
+	}
+	return nil
+}
+
+func (s *BreakoutService) end(ctx context.Context, b *domain.Breakout) error {
+	s.stopTimer(b.ParentID)
+	_, ok, err := s.repo.Close(ctx, b.ParentID)
+	if err != nil || !ok {
+		return err
+	}
+
+	moves := make(map[int64]string)
+	for _, r := range b.Rooms {
+		for _, uid := range r.Users {
+			moves[uid] = b.ParentID
+		}
+	}
+	s.notify(domain.BreakoutEvent{Kind: domain.BreakoutEnded, Breakout: b, Moves: moves})
+	return nil
+}
+
+// unassigned — участники основной комнаты без модераторов и без ручного распределения.
+func (s *BreakoutService) unassigned(ctx context.Context, roomID string, assigned map[int64]int) ([]int64, error) {
+	parts, err := s.partRepo.ListByRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int64, 0, len(parts))
+	for _, p := range parts {
+		if _, ok := assigned[p.UserID]; ok {
+			continue
+		}
+		mod, err := s.roomRepo.IsModerator(ctx, roomID, p.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if !mod {
+			out = append(out, p.UserID)
+		}
+	}
+	return out, nil
+}
+
+// schedule — завести таймер до следующей точки отсчёта (см. nextCountdownPoint).
+func (s *BreakoutService) schedule(parentID string, endsAt time.Time) {
+	left := time.Until(endsAt)
+	wait := left - nextCountdownPoint(left)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timers[parentID]; ok {
+		t.Stop()
+	}
+	s.timers[parentID] = time.AfterFunc(wait, func() { s.tick(parentID, endsAt) })
+}
+
+func (s *BreakoutService) tick(parentID string, endsAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	b, err := s.repo.Get(ctx, parentID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrBreakoutNotFound) {
+			slog.Warn("breakout: load on timer failed", "room", parentID, "err", err)
+		}
+		return
+	}
+	if !b.EndsAt.Equal(endsAt) {
+		return // уже перезапущен, таймер устарел
+	}
+
+	left := time.Until(endsAt).Round(time.Second)
+	if left <= 0 {
+		if err := s.end(ctx, b); err != nil {
+			slog.Warn("breakout: close on timer failed", "room", parentID, "err", err)
+		}
+		return
+	}
+	s.notify(domain.BreakoutEvent{Kind: domain.BreakoutCountdown, Breakout: b, Left: left})
+	s.schedule(parentID, endsAt)
+}
+
+// nextCountdownPoint — следующий (меньший left) момент, когда рассылаем отсчёт:
+// целые минуты, затем 30s, 10s и последние 5 секунд поштучно; 0 — конец.
+func nextCountdownPoint(left time.Duration) time.Duration {
+	if left > 2*time.Minute {
+		return (left - time.Nanosecond).Truncate(time.Minute)
+	}
+	for _, p := range []time.Duration{time.Minute, 30 * time.Second, 10 * time.Second, 5 * time.Second, 4 * time.Second, 3 * time.Second, 2 * time.Second, time.Second} {
+		if p < left {
+			return p
+		}
+	}
+	return 0
+}
+
+func (s *BreakoutService) stopTimer(parentID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timers[parentID]; ok {
+		t.Stop()
+		delete(s.timers, parentID)
+	}
+}
+
+func (s *BreakoutService) notify(ev domain.BreakoutEvent) {
+	s.mu.Lock()
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil {
+		fn(ev)
+	}
+}
+
+// requireParticipant — распределять можно только тех, кто есть в одной из комнат.
+func (s *BreakoutService) requireParticipant(ctx context.Context, roomIDs []string, userID int64) error {
+	for _, roomID := range roomIDs {
+		in, err := s.partRepo.Exists(ctx, roomID, userID)
+		if err != nil {
+			return err
+		}
+		if in {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: user %d is not in the room", domain.ErrBreakoutInvalid, userID)
+}
+
+func (s *BreakoutService) requireModerator(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
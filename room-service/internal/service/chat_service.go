@@ -2,32 +2,54 @@ package service
 
 import (
 	"context"
-	"errors"
 	"strings"
 	"time"
 
 	"github.com/cwrk-planet/room-service/internal/domain"
-	"github.com/cwrk-planet/room-service/internal/postgres"
+
+	"github.com/google/uuid"
 )
 
 type ChatService struct {
-	chatRepo *postgres.ChatRepository
+	chatRepo ChatStore
+	readRepo ReadMarkerStore
+}
+
+// ChatStore — сообщения комнат (postgres.ChatRepository).
+type ChatStore interface {
+	Save(ctx context.Context, roomID string, userID int64, text string, replyTo *string, attachments []string) (*domain.ChatMessage, error)
+	History(ctx context.Context, roomID, after string, limit int) ([]domain.ChatMessage, string, error)
+	ByUser(ctx context.Context, userID int64, after string, limit int) ([]domain.ChatMessage, string, error)
+	Since(ctx context.Context, roomID, afterID string, limit int) ([]domain.ChatMessage, error)
+}
+
+// ReadMarkerStore — маркеры прочтения (postgres.ReadMarkerRepository).
+type ReadMarkerStore interface {
+	Set(ctx context.Context, roomID string, userID int64, msgID string) (m *domain.ReadMarker, moved bool, err error)
+	List(ctx context.Context, roomID string) ([]domain.ReadMarker, error)
+	UnreadCount(ctx context.Context, roomID string, userID int64) (int64, error)
 }
 
-func NewChatService(chatRepo *postgres.ChatRepository) *ChatService {
-	return &ChatService{chatRepo: chatRepo}
+func NewChatService(chatRepo ChatStore, readRepo ReadMarkerStore) *ChatService {
+	return &ChatService{chatRepo: chatRepo, readRepo: readRepo}
 }
 
-func (s *ChatService) Save(ctx context.Context, roomID string, userID int64, text string) (string, time.Time, error) {
+// maxAttachmentsPerMessage — сколько файлов можно приложить к одному сообщению.
+const maxAttachmentsPerMessage = 10
+
+func (s *ChatService) Save(ctx context.Context, roomID string, userID int64, text string, attachments []string) (string, time.Time, error) {
 	text = strings.TrimSpace(text)
-	if text == "" {
-		return "", time.Time{}, errors.New("empty message")
+	if text == "" && len(attachments) == 0 {
+		return "", time.Time{}, domain.ErrEmptyMessage
 	}
 	// todo: вынести в конфиг
 	if len(text) > 4000 {
-		return "", time.Time{}, errors.New("message too long")
+		return "", time.Time{}, domain.ErrMessageTooLong
+	}
+	if len(attachments) > maxAttachmentsPerMessage {
+		return "", time.Time{}, domain.ErrAttachmentInvalid
 	}
-	msg, err := s.chatRepo.Save(ctx, roomID, userID, text, nil)
+	msg, err := s.chatRepo.Save(ctx, roomID, userID, text, nil, attachments)
 	if err != nil {
 		return "", time.Time{}, err
 	}
@@ -37,3 +59,32 @@ func (s *ChatService) Save(ctx context.Context, roomID string, userID int64, tex
 func (s *ChatService) History(ctx context.Context, roomID, after string, limit int) ([]domain.ChatMessage, string, error) {
 	return s.chatRepo.History(ctx, roomID, after, limit)
 }
+
+// ByUser — свои сообщения по всем комнатам (выгрузка персональных данных).
+func (s *ChatService) ByUser(ctx context.Context, userID int64, after string, limit int) ([]domain.ChatMessage, string, error) {
+	return s.chatRepo.ByUser(ctx, userID, after, limit)
+}
+
+// Since — сообщения после afterID по возрастанию (для догоняющих подписчиков, см. WatchRoom).
+func (s *ChatService) Since(ctx context.Context, roomID, afterID string, limit int) ([]domain.ChatMessage, error) {
+	if _, err := uuid.Parse(afterID); err != nil {
+		return nil, domain.ErrMessageNotFound
+	}
+	return s.chatRepo.Since(ctx, roomID, afterID, limit)
+}
+
+// MarkRead — moved=false, если маркер не сдвинулся (повтор или более старое сообщение).
+func (s *ChatService) MarkRead(ctx context.Context, roomID string, userID int64, msgID string) (*domain.ReadMarker, bool, error) {
+	if _, err := uuid.Parse(msgID); err != nil {
+		return nil, false, domain.ErrMessageNotFound
+	}
+	return s.readRepo.Set(ctx, roomID, userID, msgID)
+}
+
+func (s *ChatService) ReadMarkers(ctx context.Context, roomID string) ([]domain.ReadMarker, error) {
+	return s.readRepo.List(ctx, roomID)
+}
+
+func (s *ChatService) UnreadCount(ctx context.Context, roomID string, userID int64) (int64, error) {
+	return s.readRepo.UnreadCount(ctx, roomID, userID)
+}
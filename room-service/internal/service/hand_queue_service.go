@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+)
+
+// maxSpeakingTime — верхняя граница таймера выступления.
+const maxSpeakingTime = time.Hour
+
+// HandQueueService — очередь "поднятых рук" и передача слова.
+// Состояние в БД, в памяти только таймеры выступлений (восстанавливаются Restore).
+type HandQueueService struct {
+	repo     HandQueueStore
+	roomRepo RoomModerators
+
+	mu       sync.Mutex
+	timers   map[string]*time.Timer // roomID -> таймер текущего выступления
+	onChange func(roomID string)
+}
+
+// HandQueueStore — очередь и трибуна в БД (postgres.HandQueueRepository).
+type HandQueueStore interface {
+	Raise(ctx context.Context, roomID string, userID int64) (bool, error)
+	Lower(ctx context.Context, roomID string, userID int64) (bool, error)
+	Get(ctx context.Context, roomID string) (*domain.HandQueue, error)
+	GrantFloor(ctx context.Context, roomID string, userID, grantedBy int64, expiresAt *time.Time) (*domain.Floor, error)
+	ReleaseFloor(ctx context.Context, roomID string, userID int64, grantedAt *time.Time) (bool, error)
+	TimedFloors(ctx context.Context) ([]domain.Floor, error)
+}
+
+// RoomModerators — кто модерирует комнату (postgres.RoomRepository).
+type RoomModerators interface {
+	IsModerator(ctx context.Context, roomID string, userID int64) (bool, error)
+}
+
+func NewHandQueueService(repo HandQueueStore, roomRepo RoomModerators) *HandQueueService {
+	return &HandQueueService{
+		repo:     repo,
+		roomRepo: roomRepo,
+		timers:   make(map[string]*time.Timer),
+	}
+}
+
+// SetOnChange — вызывается, когда очередь поменялась "сама" (истёк таймер).
+func (s *HandQueueService) SetOnChange(fn func(roomID string)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+func (s *HandQueueService) State(ctx context.Context, roomID string) (*domain.HandQueue, error) {
+	return s.repo.Get(ctx, roomID)
+}
+
+func (s *HandQueueService) Raise(ctx context.Context, roomID string, userID int64) (bool, error) {
+	return s.repo.Raise(ctx, roomID, userID)
+}
+
+// Lower — опустить свою руку; чужую — только модератор.
+func (s *HandQueueService) Lower(ctx context.Context, actorID int64, roomID string, userID int64) (bool, error) {
+	if actorID != userID {
+		if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+			return false, err
+		}
+	}
+	return s.repo.Lower(ctx, roomID, userID)
+}
+
+// Next — модератор вызывает первого из очереди. d == 0 — без таймера.
+func (s *HandQueueService) Next(ctx context.Context, actorID int64, roomID string, d time.Duration) (*domain.Floor, error) {
+	return s.grant(ctx, actorID, roomID, 0, d)
+}
+
+// Grant — модератор даёт слово конкретному пользователю (в очереди он или нет).
+func (s *HandQueueService) Grant(ctx context.Context, actorID int64, roomID string, userID int64, d time.Duration) (*domain.Floor, error) {
+	if userID <= 0 {
+		return nil, domain.ErrNotInRoom
+	}
+	return s.grant(ctx, actorID, roomID, userID, d)
+}
+
+// Release — закончить выступление: сам выступающий или модератор.
+func (s *HandQueueService) Release(ctx context.Context, actorID int64, roomID string) (bool, error) {
+	mod, err := s.roomRepo.IsModerator(ctx, roomID, actorID)
+	if err != nil {
+		return false, err
+	}
+	var only int64
+	if !mod {
+		only = actorID // не модератор может снять только себя
+	}
+	ok, err := s.repo.ReleaseFloor(ctx, roomID, only, nil)
+	if err != nil {
+		return false, err
+	}
+	if !ok && !mod {
+		return false, domain.ErrForbidden
+	}
+	if ok {
+		s.stopTimer(roomID)
+	}
+	return ok, nil
+}
+
+// ReleaseOwn — снять с трибуны userID, если слово сейчас у него (ушёл из комнаты).
+// В отличие от Release не смотрит на права: модератор, уходя, не должен обрывать чужое выступление.
+func (s *HandQueueService) ReleaseOwn(ctx context.Context, roomID string, userID int64) (bool, error) {
+	ok, err := s.repo.ReleaseFloor(ctx, roomID, userID, nil)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		s.stopTimer(roomID)
+	}
+	return ok, nil
+}
+
+// Restore — после рестарта заводим таймеры заново; просроченные снимаются сразу.
+func (s *HandQueueService) Restore(ctx context.Context) error {
+	floors, err := s.repo.TimedFloors(ctx)
+	if err != nil {
+		return err
+	}
+	for _, f := range floors {
+		s.schedule(f)
+	}
+	return nil
+}
+
+func (s *HandQueueService) grant(ctx context.Context, actorID int64, roomID string, userID int64, d time.Duration) (*domain.Floor, error) {
+	if d < 0 || d > maxSpeakingTime {
+		return nil, domain.ErrInvalidDuration
+	}
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+
+	var expiresAt *time.Time
+	if d > 0 {
+		t := time.Now().Add(d)
+		expiresAt = &t
+	}
+	f, err := s.repo.GrantFloor(ctx, roomID, userID, actorID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.stopTimer(roomID)
+	if f.ExpiresAt != nil {
+		s.schedule(*f)
+	}
+	return f, nil
+}
+
+func (s *HandQueueService) schedule(f domain.Floor) {
+	if f.ExpiresAt == nil {
+		return
+	}
+	grantedAt := f.GrantedAt
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timers[f.RoomID]; ok {
+		t.Stop()
+	}
+	var self *time.Timer
+	self = time.AfterFunc(time.Until(*f.ExpiresAt), func() {
+		// self читаем под s.mu: schedule держит его, пока не присвоит
+		s.mu.Lock()
+		t := self
+		s.mu.Unlock()
+		s.expire(f.RoomID, f.UserID, grantedAt, t)
+	})
+	s.timers[f.RoomID] = self
+}
+
+func (s *HandQueueService) expire(roomID string, userID int64, grantedAt time.Time, self *time.Timer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// снимаем только если это всё то же выступление (слово могли уже передать)
+	ok, err := s.repo.ReleaseFloor(ctx, roomID, userID, &grantedAt)
+	if err != nil {
+		slog.Warn("hand queue: expire floor failed", "room", roomID, "user", userID, "err", err)
+		return
+	}
+
+	s.mu.Lock()
+	if s.timers[roomID] == self {
+		delete(s.timers, roomID)
+	}
+	fn := s.onChange
+	s.mu.Unlock()
+
+	if ok && fn != nil {
+		fn(roomID)
+	}
+}
+
+func (s *HandQueueService) stopTimer(roomID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timers[roomID]; ok {
+		t.Stop()
+		delete(s.timers, roomID)
+	}
+}
+
+func (s *HandQueueService) requireModerator(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+)
+
+// LobbyService — зал ожидания: пока хост не начал занятие, вошедшие стоят
+// в очереди и не занимают места в комнате. Модераторы проходят сразу.
+type LobbyService struct {
+	repo     LobbyStore
+	roomRepo RoomModerators
+	partRepo RoomParticipants
+
+	mu       sync.Mutex
+	onChange func(roomID string, admitted, removed []int64)
+}
+
+// LobbyStore — очередь и настройки лобби (postgres.LobbyRepository).
+type LobbyStore interface {
+	Get(ctx context.Context, roomID string) (*domain.Lobby, error)
+	SetEnabled(ctx context.Context, roomID string, enabled bool) error
+	SetStarted(ctx context.Context, roomID string, started bool) error
+	Enqueue(ctx context.Context, roomID string, userID int64) (bool, error)
+	Position(ctx context.Context, roomID string, userID int64) (int, bool, error)
+	List(ctx context.Context, roomID string) ([]domain.LobbyEntry, error)
+	Remove(ctx context.Context, roomID string, userID int64) (bool, error)
+	Clear(ctx context.Context, roomID string) ([]int64, error)
+	Admit(ctx context.Context, roomID string, userIDs []int64) ([]int64, error)
+}
+
+// RoomParticipants — кто уже в комнате (postgres.ParticipantRepository).
+type RoomParticipants interface {
+	Exists(ctx context.Context, roomID string, userID int64) (bool, error)
+}
+
+func NewLobbyService(repo LobbyStore, roomRepo RoomModerators, partRepo RoomParticipants) *LobbyService {
+	return &LobbyService{repo: repo, roomRepo: roomRepo, partRepo: partRepo}
+}
+
+// SetOnChange — после любого изменения очереди: admitted переведены в комнату,
+// removed убраны из лобби без допуска (отказ, сброс занятия).
+func (s *LobbyService) SetOnChange(fn func(roomID string, admitted, removed []int64)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// Enter — поставить в лобби, если оно сейчас действует. waiting=false — можно входить сразу.
+func (s *LobbyService) Enter(ctx context.Context, roomID string, userID int64) (waiting bool, pos int, err error) {
+	l, err := s.repo.Get(ctx, roomID)
+	if err != nil {
+		return false, 0, err
+	}
+	if !l.Active() {
+		return false, 0, nil
+	}
+	if in, err := s.partRepo.Exists(ctx, roomID, userID); err != nil || in {
+		return false, 0, err
+	}
+	if mod, err := s.roomRepo.IsModerator(ctx, roomID, userID); err != nil || mod {
+		return false, 0, err
+	}
+
+	added, err := s.repo.Enqueue(ctx, roomID, userID)
+	if err != nil {
+		return false, 0, err
+	}
+	pos, _, err = s.repo.Position(ctx, roomID, userID)
+	if err != nil {
+		return false, 0, err
+	}
+	if added {
+		s.notify(roomID, nil, nil)
+	}
+	return true, pos, nil
+}
+
+// Position — место в очереди (с 1), ok=false — не в лобби.
+func (s *LobbyService) Position(ctx context.Context, roomID string, userID int64) (int, bool, error) {
+	return s.repo.Position(ctx, roomID, userID)
+}
+
+// Queue — ожидающие по порядку.
+func (s *LobbyService) Queue(ctx context.Context, roomID string) ([]domain.LobbyEntry, error) {
+	return s.repo.List(ctx, roomID)
+}
+
+// Get — настройки и очередь (модераторам).
+func (s *LobbyService) Get(ctx context.Context, actorID int64, roomID string) (*domain.Lobby, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+	l, err := s.repo.Get(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if l.Queue, err = s.repo.List(ctx, roomID); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// SetEnabled — включить/выключить лобби. При выключении ожидающих пускаем (сколько влезет).
+func (s *LobbyService) SetEnabled(ctx context.Context, actorID int64, roomID string, enabled bool) error {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return err
+	}
+	if err := s.repo.SetEnabled(ctx, roomID, enabled); err != nil {
+		return err
+	}
+	if enabled {
+		return nil
+	}
+	_, err := s.admit(ctx, roomID, nil)
+	return err
+}
+
+// Admit — допустить одного ожидающего.
+func (s *LobbyService) Admit(ctx context.Context, actorID int64, roomID string, userID int64) error {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return err
+	}
+	if _, ok, err := s.repo.Position(ctx, roomID, userID); err != nil {
+		return err
+	} else if !ok {
+		return domain.ErrNotInLobby
+	}
+	admitted, err := s.admit(ctx, roomID, []int64{userID})
+	if err != nil {
+		return err
+	}
+	if len(admitted) == 0 {
+		return domain.ErrRoomFull
+	}
+	return nil
+}
+
+// Start — хост начинает занятие: лобби до сброса больше не действует, очередь пускаем разом.
+// Возвращает допущенных; кому не хватило мест, остаются в очереди.
+func (s *LobbyService) Start(ctx context.Context, actorID int64, roomID string) ([]int64, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetStarted(ctx, roomID, true); err != nil {
+		return nil, err
+	}
+	return s.admit(ctx, roomID, nil)
+}
+
+// Deny — убрать из лобби без допуска.
+func (s *LobbyService) Deny(ctx context.Context, actorID int64, roomID string, userID int64) error {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return err
+	}
+	removed, err := s.repo.Remove(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return domain.ErrNotInLobby
+	}
+	s.notify(roomID, nil, []int64{userID})
+	return nil
+}
+
+// Leave — пользователь сам ушёл из лобби (закрыл WS / вызвал leave).
+func (s *LobbyService) Leave(ctx context.Context, roomID string, userID int64) (bool, error) {
+	removed, err := s.repo.Remove(ctx, roomID, userID)
+	if err != nil || !removed {
+		return false, err
+	}
+	s.notify(roomID, nil, nil)
+	return true, nil
+}
+
+// ResetSession — занятие закончилось (окно расписания закрылось): следующее снова через лобби,
+// текущая очередь распускается.
+func (s *LobbyService) ResetSession(ctx context.Context, roomID string) error {
+	if err := s.repo.SetStarted(ctx, roomID, false); err != nil {
+		return err
+	}
+	removed, err := s.repo.Clear(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		s.notify(roomID, nil, removed)
+	}
+	return nil
+}
+
+func (s *LobbyService) admit(ctx context.Context, roomID string, userIDs []int64) ([]int64, error) {
+	admitted, err := s.repo.Admit(ctx, roomID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(admitted) > 0 {
+		s.notify(roomID, admitted, nil)
+	}
+	return admitted, nil
+}
+
+func (s *LobbyService) notify(roomID string, admitted, removed []int64) {
+	s.mu.Lock()
+	fn := s.onChange
+	s.mu.Unlock()
+	if fn != nil {
+		fn(roomID, admitted, removed)
+	}
+}
+
+func (s *LobbyService) requireModerator(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
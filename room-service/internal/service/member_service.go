@@ -2,20 +2,58 @@ package service
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/cwrk-planet/room-service/internal/domain"
 	"github.com/cwrk-planet/room-service/internal/postgres"
+
+	"github.com/google/uuid"
 )
 
 type MemberService struct {
-	roomRepo        *postgres.RoomRepository
-	participantRepo *postgres.ParticipantRepository
+	roomRepo        MemberRooms
+	participantRepo MemberParticipants
 
 	heartbeatWindow time.Duration
+	presence        PresenceSource
+	schedule        ScheduleGate
+	lobby           LobbyGate
+}
+
+// MemberRooms — комнаты и права (postgres.RoomRepository).
+type MemberRooms interface {
+	RoomModerators
+	Get(ctx context.Context, id string) (*domain.Room, error)
+}
+
+// MemberParticipants — состав комнат (postgres.ParticipantRepository).
+type MemberParticipants interface {
+	BreakoutParticipants
+	Join(ctx context.Context, p *domain.Participant, maxParticipants int64) error
+	Leave(ctx context.Context, roomID string, userID int64) error
+	ListByUser(ctx context.Context, userID int64) ([]domain.Membership, error)
+	TouchHeartbeat(ctx context.Context, roomID string, userID int64) error
+	ListDetailed(ctx context.Context, roomID string) ([]postgres.ParticipantDetailedRow, error)
 }
 
-func NewMemberService(roomRepo *postgres.RoomRepository, participantRepo *postgres.ParticipantRepository) *MemberService {
+// LobbyGate — зал ожидания (см. LobbyService).
+type LobbyGate interface {
+	Enter(ctx context.Context, roomID string, userID int64) (waiting bool, pos int, err error)
+	Leave(ctx context.Context, roomID string, userID int64) (bool, error)
+}
+
+// ScheduleGate — окно входа по расписанию (см. ScheduleService).
+type ScheduleGate interface {
+	CheckWindow(ctx context.Context, roomID string, userID int64) error
+}
+
+// PresenceSource — живой presence подключённых по WS (см. PresenceService).
+type PresenceSource interface {
+	Live(roomID string, userID int64) (domain.Presence, bool)
+}
+
+func NewMemberService(roomRepo MemberRooms, participantRepo MemberParticipants) *MemberService {
 	return &MemberService{
 		roomRepo:        roomRepo,
 		participantRepo: participantRepo,
@@ -29,12 +67,47 @@ func (s *MemberService) SetHeartbeatWindow(d time.Duration) {
 	}
 }
 
+func (s *MemberService) SetPresence(p PresenceSource) {
+	s.presence = p
+}
+
+func (s *MemberService) SetSchedule(g ScheduleGate) {
+	s.schedule = g
+}
+
+func (s *MemberService) SetLobby(g LobbyGate) {
+	s.lobby = g
+}
+
+// CheckWindow — ErrRoomClosed, если по расписанию сейчас не время занятия
+// (модераторов пускаем всегда).
+func (s *MemberService) CheckWindow(ctx context.Context, roomID string, userID int64) error {
+	if s.schedule == nil {
+		return nil
+	}
+	return s.schedule.CheckWindow(ctx, roomID, userID)
+}
+
 func (s *MemberService) JoinRoom(ctx context.Context, roomID string, userID int64) (*domain.Participant, error) {
 	room, err := s.roomRepo.Get(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.CheckWindow(ctx, roomID, userID); err != nil {
+		return nil, err
+	}
+	// в лобби — ждём допуска, в room_participants не пишем
+	if s.lobby != nil {
+		waiting, _, err := s.lobby.Enter(ctx, roomID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if waiting {
+			return nil, domain.ErrInLobby
+		}
+	}
+
 	exists, err := s.participantRepo.Exists(ctx, roomID, userID)
 	if err != nil {
 		return nil, err
@@ -58,13 +131,48 @@ func (s *MemberService) JoinRoom(ctx context.Context, roomID string, userID int6
 }
 
 func (s *MemberService) LeaveRoom(ctx context.Context, roomID string, userID int64) error {
-	return s.participantRepo.Leave(ctx, roomID, userID)
+	err := s.participantRepo.Leave(ctx, roomID, userID)
+	if errors.Is(err, domain.ErrNotInRoom) && s.lobby != nil {
+		// мог ещё стоять в лобби
+		if left, lerr := s.lobby.Leave(ctx, roomID, userID); lerr != nil {
+			return lerr
+		} else if left {
+			return nil
+		}
+	}
+	return err
+}
+
+// CanWatch — подписаться на события комнаты (gRPC WatchRoom) могут участники и модераторы.
+func (s *MemberService) CanWatch(ctx context.Context, roomID string, userID int64) error {
+	if _, err := uuid.Parse(roomID); err != nil {
+		return domain.ErrRoomNotFound
+	}
+	if _, err := s.roomRepo.Get(ctx, roomID); err != nil {
+		return err
+	}
+	if in, err := s.participantRepo.Exists(ctx, roomID, userID); err != nil || in {
+		return err
+	}
+	mod, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !mod {
+		return domain.ErrForbidden
+	}
+	return nil
 }
 
 func (s *MemberService) ListParticipants(ctx context.Context, roomID string) ([]domain.Participant, error) {
 	return s.participantRepo.ListByRoom(ctx, roomID)
 }
 
+// Memberships — комнаты пользователя (выгрузка персональных данных).
+func (s *MemberService) Memberships(ctx context.Context, userID int64) ([]domain.Membership, error) {
+	return s.participantRepo.ListByUser(ctx, userID)
+}
+
 func (s *MemberService) TouchHeartbeat(ctx context.Context, roomID string, userID int64) error {
 	return s.participantRepo.TouchHeartbeat(ctx, roomID, userID)
 }
@@ -75,23 +183,43 @@ type ParticipantDetailed struct {
 	AvatarURL   *string
 	JoinedAt    time.Time
 	LastSeen    time.Time
+	Presence    domain.Presence
 }
 
+// ListParticipantsDetailed — участники с presence: для подключённых берём живое
+// состояние из памяти, для остальных — снапшот из БД, но без свежего heartbeat
+// такой участник считается offline.
 func (s *MemberService) ListParticipantsDetailed(ctx context.Context, roomID string) ([]ParticipantDetailed, error) {
-	rows, err := s.participantRepo.ListDetailed(ctx, roomID, s.heartbeatWindow)
+	rows, err := s.participantRepo.ListDetailed(ctx, roomID)
 	if err != nil {
 		return nil, err
 	}
 	out := make([]ParticipantDetailed, 0, len(rows))
+	now := time.Now()
 	for _, r := range rows {
+		p := r.Presence
+		if live, ok := s.livePresence(roomID, r.UserID); ok {
+			p = live
+		} else if now.Sub(r.LastSeen) > s.heartbeatWindow {
+			p.Status = domain.PresenceOffline
+			p.Mic, p.Cam, p.Screen = false, false, false
+		}
 		out = append(out, ParticipantDetailed{
 			UserID:      r.UserID,
 			DisplayName: r.DisplayName,
 			AvatarURL:   r.AvatarURL,
 			JoinedAt:    r.JoinedAt,
 			LastSeen:    r.LastSeen,
+			Presence:    p,
 		})
 	}
 
 	return out, nil
 }
+
+func (s *MemberService) livePresence(roomID string, userID int64) (domain.Presence, bool) {
+	if s.presence == nil {
+		return domain.Presence{}, false
+	}
+	return s.presence.Live(roomID, userID)
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// PollService — опросы и квизы. Создают и закрывают модераторы, голосуют все.
+// Таймеры закрытия живут в памяти и восстанавливаются Restore.
+type PollService struct {
+	repo     PollStore
+	roomRepo RoomModerators
+
+	mu      sync.Mutex
+	timers  map[string]*time.Timer // pollID -> таймер закрытия
+	onClose func(p *domain.Poll)
+}
+
+// PollStore — опросы и голоса в БД (postgres.PollRepository).
+type PollStore interface {
+	Create(ctx context.Context, p *domain.Poll) error
+	Get(ctx context.Context, id string) (*domain.Poll, error)
+	ListByRoom(ctx context.Context, roomID string, onlyOpen bool) ([]domain.Poll, error)
+	OpenTimed(ctx context.Context) ([]domain.Poll, error)
+	Vote(ctx context.Context, pollID string, userID int64, options []int) error
+	Close(ctx context.Context, id string) (bool, error)
+	Results(ctx context.Context, p *domain.Poll) (*domain.PollResults, error)
+	Votes(ctx context.Context, pollID string) ([]domain.PollVote, error)
+	UserVote(ctx context.Context, pollID string, userID int64) ([]int, error)
+}
+
+func NewPollService(repo PollStore, roomRepo RoomModerators) *PollService {
+	return &PollService{
+		repo:     repo,
+		roomRepo: roomRepo,
+		timers:   make(map[string]*time.Timer),
+	}
+}
+
+// SetOnClose — вызывается при закрытии по таймеру (модераторское закрытие возвращается из Close).
+func (s *PollService) SetOnClose(fn func(p *domain.Poll)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onClose = fn
+}
+
+func (s *PollService) Create(ctx context.Context, actorID int64, roomID string, in domain.PollInput) (*domain.Poll, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+
+	q := strings.TrimSpace(in.Question)
+	if q == "" || utf8.RuneCountInString(q) > domain.MaxPollQuestionLen {
+		return nil, domain.ErrPollInvalid
+	}
+	if len(in.Options) < domain.MinPollOptions || len(in.Options) > domain.MaxPollOptions {
+		return nil, domain.ErrPollInvalid
+	}
+	if in.Duration < 0 || in.Duration > domain.MaxPollDuration {
+		return nil, domain.ErrInvalidDuration
+	}
+	switch in.ResultsMode {
+	case "":
+		in.ResultsMode = domain.PollResultsLive
+	case domain.PollResultsLive, domain.PollResultsOnClose:
+	default:
+		return nil, domain.ErrPollInvalid
+	}
+
+	p := &domain.Poll{
+		RoomID:      roomID,
+		CreatedBy:   &actorID,
+		Question:    q,
+		Multi:       in.Multi,
+		Quiz:        in.Quiz,
+		ResultsMode: in.ResultsMode,
+	}
+	correct := 0
+	for i, o := range in.Options {
+		text := strings.TrimSpace(o.Text)
+		if text == "" || utf8.RuneCountInString(text) > domain.MaxPollOptionLen {
+			return nil, domain.ErrPollInvalid
+		}
+		if o.Correct {
+			correct++
+		}
+		p.Options = append(p.Options, domain.PollOption{Idx: i, Text: text, Correct: in.Quiz && o.Correct})
+	}
+	// у квиза должен быть правильный ответ; у single-квиза — ровно один
+	if in.Quiz && (correct == 0 || (!in.Multi && correct != 1)) {
+		return nil, domain.ErrPollInvalid
+	}
+	if in.Duration > 0 {
+		t := time.Now().Add(in.Duration)
+		p.ClosesAt = &t
+	}
+
+	if err := s.repo.Create(ctx, p); err != nil {
+		return nil, err
+	}
+	s.schedule(p)
+	return p, nil
+}
+
+// Vote — голос пользователя. Возвращает опрос (нужен транспорту, чтобы решить, рассылать ли результаты).
+func (s *PollService) Vote(ctx context.Context, roomID string, userID int64, pollID string, options []int) (*domain.Poll, error) {
+	p, err := s.get(ctx, roomID, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != domain.PollOpen {
+		return nil, domain.ErrPollClosed
+	}
+
+	opts := slices.Clone(options)
+	slices.Sort(opts)
+	opts = slices.Compact(opts)
+	if len(opts) == 0 || (!p.Multi && len(opts) != 1) {
+		return nil, domain.ErrVoteInvalid
+	}
+	for _, i := range opts {
+		if i < 0 || i >= len(p.Options) {
+			return nil, domain.ErrVoteInvalid
+		}
+	}
+
+	if err := s.repo.Vote(ctx, pollID, userID, opts); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Close — модератор закрывает опрос. false — уже был закрыт.
+func (s *PollService) Close(ctx context.Context, actorID int64, roomID, pollID string) (*domain.Poll, bool, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, false, err
+	}
+	if _, err := s.get(ctx, roomID, pollID); err != nil {
+		return nil, false, err
+	}
+	closed, err := s.repo.Close(ctx, pollID)
+	if err != nil {
+		return nil, false, err
+	}
+	s.stopTimer(pollID)
+
+	p, err := s.repo.Get(ctx, pollID)
+	if err != nil {
+		return nil, false, err
+	}
+	return p, closed, nil
+}
+
+func (s *PollService) Results(ctx context.Context, p *domain.Poll) (*domain.PollResults, error) {
+	return s.repo.Results(ctx, p)
+}
+
+// OpenPolls — открытые опросы комнаты (для state).
+func (s *PollService) OpenPolls(ctx context.Context, roomID string) ([]domain.Poll, error) {
+	return s.repo.ListByRoom(ctx, roomID, true)
+}
+
+func (s *PollService) UserVote(ctx context.Context, pollID string, userID int64) ([]int, error) {
+	return s.repo.UserVote(ctx, pollID, userID)
+}
+
+// PollExport — опрос со сводкой и всеми голосами.
+type PollExport struct {
+	Poll    domain.Poll
+	Results domain.PollResults
+	Votes   []domain.PollVote
+}
+
+// Export — выгрузка результатов всех опросов комнаты (только модераторам:
+// там видно, кто как голосовал).
+func (s *PollService) Export(ctx context.Context, actorID int64, roomID string) ([]PollExport, error) {
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return nil, err
+	}
+	polls, err := s.repo.ListByRoom(ctx, roomID, false)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]PollExport, 0, len(polls))
+	for i := range polls {
+		res, err := s.repo.Results(ctx, &polls[i])
+		if err != nil {
+			return nil, err
+		}
+		votes, err := s.repo.Votes(ctx, polls[i].ID)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, PollExport{Poll: polls[i], Results: *res, Votes: votes})
+	}
+	return out, nil
+}
+
+// Restore — после рестарта: заводим таймеры, просроченные опросы закрываются сразу.
+func (s *PollService) Restore(ctx context.Context) error {
+	polls, err := s.repo.OpenTimed(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range polls {
+		s.schedule(&polls[i])
+	}
+	return nil
+}
+
+func (s *PollService) get(ctx context.Context, roomID, pollID string) (*domain.Poll, error) {
+	if _, err := uuid.Parse(pollID); err != nil {
+		return nil, domain.ErrPollNotFound
+	}
+	p, err := s.repo.Get(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	if p.RoomID != roomID {
+		return nil, domain.ErrPollNotFound
+	}
+	return p, nil
+}
+
+func (s *PollService) schedule(p *domain.Poll) {
+	if p.ClosesAt == nil {
+		return
+	}
+	pollID := p.ID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timers[pollID] = time.AfterFunc(time.Until(*p.ClosesAt), func() { s.expire(pollID) })
+}
+
+func (s *PollService) expire(pollID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	delete(s.timers, pollID)
+	fn := s.onClose
+	s.mu.Unlock()
+
+	closed, err := s.repo.Close(ctx, pollID)
+	if err != nil {
+		slog.Warn("poll: close on timer failed", "poll", pollID, "err", err)
+		return
+	}
+	if !closed || fn == nil {
+		return
+	}
+	p, err := s.repo.Get(ctx, pollID)
+	if err != nil {
+		slog.Warn("poll: load closed poll failed", "poll", pollID, "err", err)
+		return
+	}
+	fn(p)
+}
+
+func (s *PollService) stopTimer(pollID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.timers[pollID]; ok {
+		t.Stop()
+		delete(s.timers, pollID)
+	}
+}
+
+func (s *PollService) requireModerator(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+)
+
+// PresenceService — presence участников в памяти (источник правды, пока есть WS),
+// в Postgres периодически пишется снапшот изменённых записей.
+type PresenceService struct {
+	repo PresenceStore
+
+	mu    sync.Mutex
+	rooms map[string]map[int64]*presenceEntry
+	dirty map[presenceKey]domain.Presence
+
+	now func() time.Time
+}
+
+type presenceEntry struct {
+	p     domain.Presence
+	conns int // одно и то же устройство/вкладки: offline только когда ушли все
+}
+
+type presenceKey struct {
+	roomID string
+	userID int64
+}
+
+// PresenceStore — снапшоты presence в БД (postgres.PresenceRepository).
+type PresenceStore interface {
+	Get(ctx context.Context, roomID string, userID int64) (*domain.Presence, error)
+	SaveBatch(ctx context.Context, items []domain.Presence) error
+}
+
+func NewPresenceService(repo PresenceStore) *PresenceService {
+	return &PresenceService{
+		repo:  repo,
+		rooms: make(map[string]map[int64]*presenceEntry),
+		dirty: make(map[presenceKey]domain.Presence),
+		now:   time.Now,
+	}
+}
+
+// Connect — новое WS-соединение. Первое соединение пользователя поднимает
+// снапшот из БД: статус/рука/текст сохраняются, медиа-флаги сбрасываются.
+func (s *PresenceService) Connect(ctx context.Context, roomID string, userID int64) (domain.Presence, domain.PresenceDelta, error) {
+	s.mu.Lock()
+	if e, ok := s.rooms[roomID][userID]; ok {
+		e.conns++
+		p := e.p
+		s.mu.Unlock()
+		return p, domain.PresenceDelta{}, nil
+	}
+	s.mu.Unlock()
+
+	// в БД идём без блокировки
+	prev := domain.Presence{RoomID: roomID, UserID: userID, Status: domain.PresenceOffline}
+	if snap, err := s.repo.Get(ctx, roomID, userID); err == nil {
+		prev = *snap
+	} else if !errors.Is(err, domain.ErrNotInRoom) {
+		return domain.Presence{}, domain.PresenceDelta{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// пока ходили в БД, могло подключиться второе соединение
+	if e, ok := s.rooms[roomID][userID]; ok {
+		e.conns++
+		return e.p, domain.PresenceDelta{}, nil
+	}
+
+	next := prev
+	if next.Status == domain.PresenceOffline || next.Status == "" {
+		next.Status = domain.PresenceOnline
+	}
+	next.Mic, next.Cam, next.Screen = false, false, false
+	next.UpdatedAt = s.now()
+
+	rs, ok := s.rooms[roomID]
+	if !ok {
+		rs = make(map[int64]*presenceEntry)
+		s.rooms[roomID] = rs
+	}
+	rs[userID] = &presenceEntry{p: next, conns: 1}
+	s.dirty[presenceKey{roomID, userID}] = next
+
+	return next, diffPresence(prev, next), nil
+}
+
+// Disconnect — закрылось соединение. last=true, если это было последнее:
+// тогда пользователь уходит в offline, запись пишется в БД и выгружается из памяти.
+func (s *PresenceService) Disconnect(ctx context.Context, roomID string, userID int64) (domain.PresenceDelta, bool) {
+	s.mu.Lock()
+	e, ok := s.rooms[roomID][userID]
+	if !ok {
+		s.mu.Unlock()
+		return domain.PresenceDelta{}, false
+	}
+	e.conns--
+	if e.conns > 0 {
+		s.mu.Unlock()
+		return domain.PresenceDelta{}, false
+	}
+
+	prev := e.p
+	next := prev
+	next.Status = domain.PresenceOffline
+	next.Mic, next.Cam, next.Screen = false, false, false
+	next.UpdatedAt = s.now()
+
+	delete(s.rooms[roomID], userID)
+	if len(s.rooms[roomID]) == 0 {
+		delete(s.rooms, roomID)
+	}
+	delete(s.dirty, presenceKey{roomID, userID})
+	s.mu.Unlock()
+
+	if err := s.repo.SaveBatch(ctx, []domain.Presence{next}); err != nil {
+		slog.Warn("presence: save on disconnect failed", "room", roomID, "user", userID, "err", err)
+	}
+
+	return diffPresence(prev, next), true
+}
+
+// Update — изменение от клиента. Пустая дельта — ничего не поменялось, рассылать нечего.
+func (s *PresenceService) Update(roomID string, userID int64, upd domain.PresenceUpdate) (domain.Presence, domain.PresenceDelta, error) {
+	if upd.Status != nil {
+		switch *upd.Status {
+		case domain.PresenceOnline, domain.PresenceAway, domain.PresenceBusy:
+		default:
+			// offline выставляет только сервер по отключению
+			return domain.Presence{}, domain.PresenceDelta{}, domain.ErrPresenceInvalid
+		}
+	}
+	if upd.StatusText != nil {
+		t := strings.TrimSpace(*upd.StatusText)
+		if utf8.RuneCountInString(t) > domain.MaxStatusTextLen {
+			return domain.Presence{}, domain.PresenceDelta{}, domain.ErrPresenceInvalid
+		}
+		upd.StatusText = &t
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.rooms[roomID][userID]
+	if !ok {
+		return domain.Presence{}, domain.PresenceDelta{}, domain.ErrNotInRoom
+	}
+
+	prev := e.p
+	next := prev
+	if upd.Status != nil {
+		next.Status = *upd.Status
+	}
+	if upd.Mic != nil {
+		next.Mic = *upd.Mic
+	}
+	if upd.Cam != nil {
+		next.Cam = *upd.Cam
+	}
+	if upd.Screen != nil {
+		next.Screen = *upd.Screen
+	}
+	if upd.HandRaised != nil {
+		switch {
+		case *upd.HandRaised && next.HandRaisedAt == nil:
+			t := s.now()
+			next.HandRaisedAt = &t
+		case !*upd.HandRaised:
+			next.HandRaisedAt = nil
+		}
+	}
+	if upd.StatusText != nil {
+		next.StatusText = *upd.StatusText
+	}
+
+	d := diffPresence(prev, next)
+	if d.Empty() {
+		return prev, d, nil
+	}
+	next.UpdatedAt = s.now()
+	e.p = next
+	s.dirty[presenceKey{roomID, userID}] = next
+
+	return next, d, nil
+}
+
+// Snapshot — presence всех подключённых в комнате.
+func (s *PresenceService) Snapshot(roomID string) map[int64]domain.Presence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[int64]domain.Presence, len(s.rooms[roomID]))
+	for uid, e := range s.rooms[roomID] {
+		out[uid] = e.p
+	}
+	return out
+}
+
+// Live — presence подключённого пользователя (для HTTP/gRPC списков участников).
+func (s *PresenceService) Live(roomID string, userID int64) (domain.Presence, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.rooms[roomID][userID]
+	if !ok {
+		return domain.Presence{}, false
+	}
+	return e.p, true
+}
+
+// Run — периодическая запись снапшотов, пока не отменён ctx. На выходе — финальный flush.
+func (s *PresenceService) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := s.Flush(ctx); err != nil {
+				slog.Warn("presence: snapshot flush failed", "err", err)
+			}
+		case <-ctx.Done():
+			if err := s.Flush(context.WithoutCancel(ctx)); err != nil {
+				slog.Warn("presence: final flush failed", "err", err)
+			}
+			return
+		}
+	}
+}
+
+func (s *PresenceService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	if len(s.dirty) == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	batch := make([]domain.Presence, 0, len(s.dirty))
+	for _, p := range s.dirty {
+		batch = append(batch, p)
+	}
+	s.dirty = make(map[presenceKey]domain.Presence)
+	s.mu.Unlock()
+
+	if err := s.repo.SaveBatch(ctx, batch); err != nil {
+		// вернём обратно, если за это время не появилось более свежих
+		s.mu.Lock()
+		for _, p := range batch {
+			k := presenceKey{p.RoomID, p.UserID}
+			if _, ok := s.dirty[k]; !ok {
+				if _, live := s.rooms[p.RoomID][p.UserID]; live {
+					s.dirty[k] = p
+				}
+			}
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func diffPresence(a, b domain.Presence) domain.PresenceDelta {
+	var d domain.PresenceDelta
+	if a.Status != b.Status {
+		d.Status = &b.Status
+	}
+	if a.Mic != b.Mic {
+		d.Mic = &b.Mic
+	}
+	if a.Cam != b.Cam {
+		d.Cam = &b.Cam
+	}
+	if a.Screen != b.Screen {
+		d.Screen = &b.Screen
+	}
+	if (a.HandRaisedAt == nil) != (b.HandRaisedAt == nil) {
+		raised := b.HandRaisedAt != nil
+		d.HandRaised = &raised
+		d.HandRaisedAt = b.HandRaisedAt
+	}
+	if a.StatusText != b.StatusText {
+		d.StatusText = &b.StatusText
+	}
+	return d
+}
@@ -4,28 +4,81 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/cwrk-planet/room-service/internal/domain"
-	"github.com/cwrk-planet/room-service/internal/postgres"
 )
 
 type RoomService struct {
-	roomRepo *postgres.RoomRepository
+	roomRepo RoomStore
+	planRepo PlanStore
+	limits   domain.CapacityLimits
+
+	onlineWindow time.Duration // как в MemberService: без heartbeat дольше — не онлайн
+}
+
+// RoomStore — комнаты и модераторы (postgres.RoomRepository).
+type RoomStore interface {
+	MemberRooms
+	Create(ctx context.Context, room *domain.Room) error
+	UpdateMeta(ctx context.Context, room *domain.Room) error
+	List(ctx context.Context, f domain.RoomFilter, onlineSince time.Time) ([]domain.Room, string, error)
+	Delete(ctx context.Context, id string) error
+	AddModerator(ctx context.Context, roomID string, userID, grantedBy int64) error
+	RemoveModerator(ctx context.Context, roomID string, userID int64) error
+}
+
+// PlanStore — тарифы пользователей (postgres.PlanRepository).
+type PlanStore interface {
+	Get(ctx context.Context, userID int64) (plan string, ok bool, err error)
+}
+
+func NewRoomService(roomRepo RoomStore, planRepo PlanStore, limits domain.CapacityLimits) *RoomService {
+	return &RoomService{
+		roomRepo:     roomRepo,
+		planRepo:     planRepo,
+		limits:       limits,
+		onlineWindow: 60 * time.Second,
+	}
 }
 
-func NewRoomService(roomRepo *postgres.RoomRepository) *RoomService {
-	return &RoomService{roomRepo: roomRepo}
+func (s *RoomService) SetOnlineWindow(d time.Duration) {
+	if d > 0 {
+		s.onlineWindow = d
+	}
 }
 
 // CreateRoom создаёт комнату с заданным именем и лимитом участников.
-func (s *RoomService) CreateRoom(ctx context.Context, name string, max int64) (*domain.Room, error) {
-	if max <= 0 || max > 10 {
-		max = 10
+// Создатель становится владельцем (и модератором) комнаты.
+// max == 0 — лимит по умолчанию; больше, чем позволяет тариф владельца, — ErrInvalidCapacity.
+func (s *RoomService) CreateRoom(ctx context.Context, ownerID int64, in domain.RoomInput) (*domain.Room, error) {
+	room := &domain.Room{Tags: []string{}}
+	if err := applyRoomInput(room, in); err != nil {
+		return nil, err
+	}
+	if room.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrRoomInvalid)
+	}
+
+	max := in.Max
+	limit, err := s.Capacity(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if max == 0 {
+		max = min(s.limits.Default, limit)
+	}
+	if max < 1 || max > limit {
+		return nil, fmt.Errorf("%w: max must be in [1..%d]", domain.ErrInvalidCapacity, limit)
 	}
 
-	room := &domain.Room{
-		Name:            name,
-		MaxParticipants: max,
+	room.MaxParticipants = max
+	if ownerID > 0 {
+		room.OwnerID = &ownerID
 	}
 
 	if err := s.roomRepo.Create(ctx, room); err != nil {
@@ -34,6 +87,113 @@ func (s *RoomService) CreateRoom(ctx context.Context, name string, max int64) (*
 	return room, nil
 }
 
+// UpdateRoom — изменить название и метаданные (владелец и модераторы).
+func (s *RoomService) UpdateRoom(ctx context.Context, actorID int64, roomID string, in domain.RoomInput) (*domain.Room, error) {
+	room, err := s.roomRepo.Get(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, domain.ErrForbidden
+	}
+	if err := applyRoomInput(room, in); err != nil {
+		return nil, err
+	}
+	if room.Name == "" {
+		return nil, fmt.Errorf("%w: name is required", domain.ErrRoomInvalid)
+	}
+	if err := s.roomRepo.UpdateMeta(ctx, room); err != nil {
+		return nil, err
+	}
+	return room, nil
+}
+
+// applyRoomInput — валидация и нормализация: теги в нижнем регистре без дублей,
+// язык — код вроде "en" / "pt-br", пустые строки очищают поле.
+func applyRoomInput(room *domain.Room, in domain.RoomInput) error {
+	if in.Name != nil {
+		name := strings.TrimSpace(*in.Name)
+		if utf8.RuneCountInString(name) > domain.MaxRoomNameLen {
+			return fmt.Errorf("%w: name is too long", domain.ErrRoomInvalid)
+		}
+		room.Name = name
+	}
+	if in.Description != nil {
+		v, err := optionalText(*in.Description, domain.MaxRoomDescriptionLen, "description")
+		if err != nil {
+			return err
+		}
+		room.Description = v
+	}
+	if in.Topic != nil {
+		v, err := optionalText(*in.Topic, domain.MaxRoomTopicLen, "topic")
+		if err != nil {
+			return err
+		}
+		room.Topic = v
+	}
+	if in.Language != nil {
+		lang := strings.ToLower(strings.TrimSpace(*in.Language))
+		if lang == "" {
+			room.Language = nil
+		} else if !languageRe.MatchString(lang) {
+			return fmt.Errorf("%w: language must be a code like en or pt-br", domain.ErrRoomInvalid)
+		} else {
+			room.Language = &lang
+		}
+	}
+	if in.Tags != nil {
+		tags := make([]string, 0, len(*in.Tags))
+		for _, t := range *in.Tags {
+			t = strings.ToLower(strings.TrimSpace(t))
+			if t == "" || slices.Contains(tags, t) {
+				continue
+			}
+			if utf8.RuneCountInString(t) > domain.MaxRoomTagLen {
+				return fmt.Errorf("%w: tag %q is too long", domain.ErrRoomInvalid, t)
+			}
+			tags = append(tags, t)
+		}
+		if len(tags) > domain.MaxRoomTags {
+			return fmt.Errorf("%w: at most %d tags", domain.ErrRoomInvalid, domain.MaxRoomTags)
+		}
+		room.Tags = tags
+	}
+	return nil
+}
+
+var languageRe = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+func optionalText(s string, max int, field string) (*string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if utf8.RuneCountInString(s) > max {
+		return nil, fmt.Errorf("%w: %s is too long", domain.ErrRoomInvalid, field)
+	}
+	return &s, nil
+}
+
+// Capacity — максимальный размер комнаты, который может создать пользователь.
+func (s *RoomService) Capacity(ctx context.Context, userID int64) (int64, error) {
+	plan := s.limits.DefaultPlan
+	if userID > 0 {
+		p, ok, err := s.planRepo.Get(ctx, userID)
+		if err != nil {
+			return 0, fmt.Errorf("planRepo.Get: %w", err)
+		}
+		if ok {
+			plan = p
+		}
+	}
+	return s.limits.For(plan), nil
+}
+
 // GetRoom возвращает комнату по ID.
 func (s *RoomService) GetRoom(ctx context.Context, id string) (*domain.Room, error) {
 	room, err := s.roomRepo.Get(ctx, id)
@@ -46,16 +206,26 @@ func (s *RoomService) GetRoom(ctx context.Context, id string) (*domain.Room, err
 	return room, nil
 }
 
-// ListRooms возвращает список комнат с курсорной пагинацией.
-func (s *RoomService) ListRooms(ctx context.Context, limit int, cursor string) ([]domain.Room, string, error) {
-	if limit <= 0 {
-		limit = 20
+// ListRooms возвращает список комнат с фильтрами и курсорной пагинацией.
+func (s *RoomService) ListRooms(ctx context.Context, f domain.RoomFilter) ([]domain.Room, string, error) {
+	if f.Limit <= 0 {
+		f.Limit = 20
+	}
+	if f.Limit > 50 {
+		f.Limit = 50
 	}
-	if limit > 50 {
-		limit = 50
+	switch f.Sort {
+	case "":
+		f.Sort = domain.RoomSortCreated
+	case domain.RoomSortCreated, domain.RoomSortActivity:
+	default:
+		return nil, "", fmt.Errorf("%w: sort must be created or activity", domain.ErrRoomInvalid)
 	}
+	f.Query = strings.TrimSpace(f.Query)
+	f.Tag = strings.ToLower(strings.TrimSpace(f.Tag))
+	f.Language = strings.ToLower(strings.TrimSpace(f.Language))
 
-	rooms, nextCursor, err := s.roomRepo.List(ctx, limit, cursor)
+	rooms, nextCursor, err := s.roomRepo.List(ctx, f, time.Now().Add(-s.onlineWindow))
 	if err != nil {
 		return nil, "", err
 	}
@@ -66,3 +236,23 @@ func (s *RoomService) ListRooms(ctx context.Context, limit int, cursor string) (
 func (s *RoomService) DeleteRoom(ctx context.Context, id string) error {
 	return s.roomRepo.Delete(ctx, id)
 }
+
+// IsModerator — может ли пользователь управлять комнатой (очередь, слово и т.п.).
+func (s *RoomService) IsModerator(ctx context.Context, roomID string, userID int64) (bool, error) {
+	return s.roomRepo.IsModerator(ctx, roomID, userID)
+}
+
+// SetModerator — назначать и снимать модераторов может только владелец.
+func (s *RoomService) SetModerator(ctx context.Context, actorID int64, roomID string, userID int64, grant bool) error {
+	room, err := s.roomRepo.Get(ctx, roomID)
+	if err != nil {
+		return err
+	}
+	if room.OwnerID == nil || *room.OwnerID != actorID {
+		return domain.ErrForbidden
+	}
+	if grant {
+		return s.roomRepo.AddModerator(ctx, roomID, userID, actorID)
+	}
+	return s.roomRepo.RemoveModerator(ctx, roomID, userID)
+}
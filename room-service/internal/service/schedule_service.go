@@ -0,0 +1,388 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/teambition/rrule-go"
+)
+
+// ScheduleService — расписание занятий комнат (RRULE) и окно входа.
+// Открытие/закрытие комнат отслеживает Run: раз в interval сверяет окна и
+// зовёт onChange на переходах.
+type ScheduleService struct {
+	repo     ScheduleStore
+	roomRepo ScheduleRooms
+	feeds    CalendarFeedStore // ленты .ics по токену (см. SetFeeds)
+
+	mu       sync.Mutex
+	open     map[string]bool // roomID -> окно открыто (по последней проверке Run)
+	onChange func(roomID string, open bool, sess domain.Session)
+}
+
+// ScheduleStore — расписания в БД (postgres.ScheduleRepository).
+type ScheduleStore interface {
+	Upsert(ctx context.Context, sc *domain.Schedule) error
+	Get(ctx context.Context, roomID string) (*domain.Schedule, error)
+	List(ctx context.Context) ([]domain.Schedule, error)
+	ListForUser(ctx context.Context, userID int64) ([]domain.Schedule, error)
+	Delete(ctx context.Context, roomID string) error
+}
+
+// CalendarFeedStore — ленты .ics (postgres.CalendarFeedRepository).
+type CalendarFeedStore interface {
+	Create(ctx context.Context, f *domain.CalendarFeed, tokenHash string) error
+	Count(ctx context.Context, userID int64) (int, error)
+	List(ctx context.Context, userID int64) ([]domain.CalendarFeed, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*domain.CalendarFeed, error)
+	Delete(ctx context.Context, userID int64, id string) error
+}
+
+// ScheduleRooms — комнаты и их модераторы (postgres.RoomRepository).
+type ScheduleRooms interface {
+	RoomModerators
+	Get(ctx context.Context, id string) (*domain.Room, error)
+}
+
+func NewScheduleService(repo ScheduleStore, roomRepo ScheduleRooms) *ScheduleService {
+	return &ScheduleService{
+		repo:     repo,
+		roomRepo: roomRepo,
+		open:     make(map[string]bool),
+	}
+}
+
+// SetOnChange — вызывается, когда окно занятия открылось или закрылось.
+func (s *ScheduleService) SetOnChange(fn func(roomID string, open bool, sess domain.Session)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onChange = fn
+}
+
+// SetFeeds — хранилище лент .ics; без него ленты не создаются и не открываются.
+func (s *ScheduleService) SetFeeds(store CalendarFeedStore) {
+	s.feeds = store
+}
+
+// Set — задать/заменить расписание комнаты (только модераторы).
+func (s *ScheduleService) Set(ctx context.Context, actorID int64, in domain.Schedule) (*domain.Schedule, error) {
+	room, err := s.roomRepo.Get(ctx, in.RoomID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireModerator(ctx, in.RoomID, actorID); err != nil {
+		return nil, err
+	}
+
+	in.RRule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(in.RRule)), "RRULE:")
+	if in.Timezone = strings.TrimSpace(in.Timezone); in.Timezone == "" {
+		in.Timezone = "UTC"
+	}
+	if in.StartsAt.IsZero() {
+		return nil, domain.ErrScheduleInvalid
+	}
+	if in.Duration < domain.MinSessionDuration || in.Duration > domain.MaxSessionDuration {
+		return nil, domain.ErrInvalidDuration
+	}
+	if in.OpenBefore < 0 || in.OpenBefore > domain.MaxOpenBefore {
+		return nil, domain.ErrInvalidDuration
+	}
+	in.StartsAt = in.StartsAt.Truncate(time.Second)
+	if _, err := compileSchedule(&in); err != nil {
+		return nil, err
+	}
+
+	in.RoomName = room.Name
+	in.CreatedBy = &actorID
+	if err := s.repo.Upsert(ctx, &in); err != nil {
+		return nil, err
+	}
+	return &in, nil
+}
+
+func (s *ScheduleService) Get(ctx context.Context, roomID string) (*domain.Schedule, error) {
+	return s.repo.Get(ctx, roomID)
+}
+
+// Delete — снять расписание: комната снова открыта всегда.
+func (s *ScheduleService) Delete(ctx context.Context, actorID int64, roomID string) error {
+	if _, err := s.roomRepo.Get(ctx, roomID); err != nil {
+		return err
+	}
+	if err := s.requireModerator(ctx, roomID, actorID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, roomID)
+}
+
+// Sessions — занятия, пересекающие [from, to), по времени начала.
+// roomID == "" — по комнатам пользователя (участник, владелец, модератор), а не по всем.
+func (s *ScheduleService) Sessions(ctx context.Context, userID int64, roomID string, from, to time.Time, limit int) ([]domain.Session, error) {
+	if !to.After(from) || to.Sub(from) > domain.MaxSessionsRange {
+		return nil, domain.ErrScheduleInvalid
+	}
+	if limit <= 0 || limit > domain.MaxSessionsLimit {
+		limit = domain.MaxSessionsLimit
+	}
+
+	var list []domain.Schedule
+	if roomID != "" {
+		sc, err := s.repo.Get(ctx, roomID)
+		if errors.Is(err, domain.ErrScheduleNotFound) {
+			return []domain.Session{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = []domain.Schedule{*sc}
+	} else {
+		var err error
+		if list, err = s.repo.ListForUser(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]domain.Session, 0)
+	for i := range list {
+		sc := &list[i]
+		r, err := compileSchedule(sc)
+		if err != nil {
+			slog.Warn("schedule: bad rule in db", "room", sc.RoomID, "err", err)
+			continue
+		}
+		for _, st := range r.Between(from.Add(-sc.Duration), to, true) {
+			end := st.Add(sc.Duration)
+			if !end.After(from) || !st.Before(to) {
+				continue
+			}
+			out = append(out, domain.Session{RoomID: sc.RoomID, RoomName: sc.RoomName, StartsAt: st, EndsAt: end})
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].StartsAt.Equal(out[j].StartsAt) {
+			return out[i].RoomID < out[j].RoomID
+		}
+		return out[i].StartsAt.Before(out[j].StartsAt)
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+// CreateFeed — лента .ics комнаты или (roomID == "") всех комнат пользователя.
+// Токен для ссылки возвращается только здесь, в БД — его sha256.
+func (s *ScheduleService) CreateFeed(ctx context.Context, userID int64, roomID string) (*domain.CalendarFeed, error) {
+	if s.feeds == nil {
+		return nil, domain.ErrFeedNotFound
+	}
+	if roomID = strings.TrimSpace(roomID); roomID != "" {
+		if _, err := uuid.Parse(roomID); err != nil {
+			return nil, domain.ErrRoomNotFound
+		}
+		if _, err := s.roomRepo.Get(ctx, roomID); err != nil {
+			return nil, err
+		}
+	}
+
+	n, err := s.feeds.Count(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= domain.MaxCalendarFeedsPerUser {
+		return nil, fmt.Errorf("%w: at most %d calendar feeds per user", domain.ErrScheduleInvalid, domain.MaxCalendarFeedsPerUser)
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	f := &domain.CalendarFeed{UserID: userID, RoomID: roomID, Token: hex.EncodeToString(raw)}
+	if err := s.feeds.Create(ctx, f, feedTokenHash(f.Token)); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *ScheduleService) ListFeeds(ctx context.Context, userID int64) ([]domain.CalendarFeed, error) {
+	if s.feeds == nil {
+		return []domain.CalendarFeed{}, nil
+	}
+	return s.feeds.List(ctx, userID)
+}
+
+// DeleteFeed — отозвать ссылку: календари с ней получат 404.
+func (s *ScheduleService) DeleteFeed(ctx context.Context, userID int64, id string) error {
+	if _, err := uuid.Parse(id); err != nil || s.feeds == nil {
+		return domain.ErrFeedNotFound
+	}
+	return s.feeds.Delete(ctx, userID, id)
+}
+
+// FeedSessions — занятия ленты по токену из ссылки: пользователь и комната берутся из ленты,
+// как будто её владелец сам запросил Sessions.
+func (s *ScheduleService) FeedSessions(ctx context.Context, token string, from, to time.Time) ([]domain.Session, error) {
+	if token == "" || s.feeds == nil {
+		return nil, domain.ErrFeedNotFound
+	}
+	f, err := s.feeds.GetByTokenHash(ctx, feedTokenHash(token))
+	if err != nil {
+		return nil, err
+	}
+	return s.Sessions(ctx, f.UserID, f.RoomID, from, to, 0)
+}
+
+func feedTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CheckWindow — можно ли войти в комнату сейчас. Комнаты без расписания открыты
+// всегда, модераторов пускаем и вне окна.
+func (s *ScheduleService) CheckWindow(ctx context.Context, roomID string, userID int64) error {
+	sc, err := s.repo.Get(ctx, roomID)
+	if errors.Is(err, domain.ErrScheduleNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, open := currentSession(sc, time.Now()); open {
+		return nil
+	}
+
+	mod, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !mod {
+		return domain.ErrRoomClosed
+	}
+	return nil
+}
+
+// Run — следит за окнами занятий до отмены ctx. Первая проверка только
+// запоминает состояние: рассылать некому, соединений после старта ещё нет.
+func (s *ScheduleService) Run(ctx context.Context, interval time.Duration) {
+	s.tick(ctx, false)
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			s.tick(ctx, true)
+		}
+	}
+}
+
+func (s *ScheduleService) tick(ctx context.Context, notify bool) {
+	list, err := s.repo.List(ctx)
+	if err != nil {
+		slog.Warn("schedule: list failed", "err", err)
+		return
+	}
+
+	now := time.Now()
+	type change struct {
+		roomID string
+		open   bool
+		sess   domain.Session
+	}
+	var changes []change
+
+	s.mu.Lock()
+	seen := make(map[string]bool, len(list))
+	for i := range list {
+		sc := &list[i]
+		seen[sc.RoomID] = true
+		sess, open := currentSession(sc, now)
+		// новое расписание тоже событие: закрытое окно выгонит тех, кто уже внутри
+		was, known := s.open[sc.RoomID]
+		s.open[sc.RoomID] = open
+		if notify && (!known || was != open) {
+			changes = append(changes, change{sc.RoomID, open, sess})
+		}
+	}
+	for roomID := range s.open {
+		if !seen[roomID] {
+			delete(s.open, roomID) // расписание сняли — комната открыта всегда
+		}
+	}
+	fn := s.onChange
+	s.mu.Unlock()
+
+	if fn == nil {
+		return
+	}
+	for _, c := range changes {
+		fn(c.roomID, c.open, c.sess)
+	}
+}
+
+func (s *ScheduleService) requireModerator(ctx context.Context, roomID string, userID int64) error {
+	ok, err := s.roomRepo.IsModerator(ctx, roomID, userID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+// compileSchedule — RRULE с DTSTART в часовом поясе расписания.
+// Пустое правило — одно занятие.
+func compileSchedule(sc *domain.Schedule) (*rrule.RRule, error) {
+	loc, err := time.LoadLocation(sc.Timezone)
+	if err != nil {
+		return nil, domain.ErrScheduleInvalid
+	}
+
+	opt := &rrule.ROption{Freq: rrule.DAILY, Count: 1}
+	if sc.RRule != "" {
+		if opt, err = rrule.StrToROptionInLocation(sc.RRule, loc); err != nil {
+			return nil, domain.ErrScheduleInvalid
+		}
+		// DTSTART хранится отдельно; поминутные повторы для занятий не нужны
+		if !opt.Dtstart.IsZero() || opt.Freq > rrule.HOURLY {
+			return nil, domain.ErrScheduleInvalid
+		}
+	}
+	opt.Dtstart = sc.StartsAt.In(loc)
+
+	r, err := rrule.NewRRule(*opt)
+	if err != nil {
+		return nil, domain.ErrScheduleInvalid
+	}
+	return r, nil
+}
+
+// currentSession — занятие, в окно которого (с учётом OpenBefore) попадает now.
+func currentSession(sc *domain.Schedule, now time.Time) (domain.Session, bool) {
+	r, err := compileSchedule(sc)
+	if err != nil {
+		return domain.Session{}, false
+	}
+	st := r.Before(now.Add(sc.OpenBefore), true)
+	if st.IsZero() {
+		return domain.Session{}, false
+	}
+	sess := domain.Session{RoomID: sc.RoomID, RoomName: sc.RoomName, StartsAt: st, EndsAt: st.Add(sc.Duration)}
+	return sess, now.Before(sess.EndsAt)
+}
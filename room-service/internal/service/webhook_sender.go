@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+)
+
+// Заголовки вебхука. Подпись: hex(HMAC-SHA256(secret, "<timestamp>.<body>")),
+// timestamp входит в подпись, чтобы запрос нельзя было переиграть позже.
+const (
+	HeaderWebhookID        = "X-Webhook-Id" // id доставки, одинаковый у повторов — для идемпотентности
+	HeaderWebhookEvent     = "X-Webhook-Event"
+	HeaderWebhookTimestamp = "X-Webhook-Timestamp"
+	HeaderWebhookSignature = "X-Webhook-Signature" // v1=<hex>
+)
+
+var ErrWebhookSignature = errors.New("invalid webhook signature")
+
+// WebhookEnvelope — тело запроса.
+type WebhookEnvelope struct {
+	ID        string          `json:"id"` // id события
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+func SignWebhook(secret string, ts int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook — проверка на стороне получателя; tolerance — допустимый возраст timestamp.
+func VerifyWebhook(secret, tsHeader, sigHeader string, body []byte, tolerance time.Duration, now time.Time) error {
+	ts, err := strconv.ParseInt(tsHeader, 10, 64)
+	if err != nil {
+		return ErrWebhookSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return fmt.Errorf("%w: timestamp out of tolerance", ErrWebhookSignature)
+	}
+	if !hmac.Equal([]byte(SignWebhook(secret, ts, body)), []byte(sigHeader)) {
+		return ErrWebhookSignature
+	}
+	return nil
+}
+
+// WebhookSender — одна попытка доставки по HTTP.
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookSender — allowPrivate=false запрещает адреса внутренней сети (loopback, 10/8 и т.п.),
+// проверка на уровне dial, так что не обходится DNS-ом. Редиректы не ходим: 3xx — неудача.
+func NewWebhookSender(timeout time.Duration, allowPrivate bool) *WebhookSender {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+	if !allowPrivate {
+		dialer.Control = denyPrivate
+	}
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				Proxy:               nil,
+				DialContext:         dialer.DialContext,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     30 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		now: time.Now,
+	}
+}
+
+// Send — code=0, если ответа не было (сеть, таймаут).
+func (s *WebhookSender) Send(ctx context.Context, d domain.WebhookDelivery) (code int, err error) {
+	body, err := json.Marshal(WebhookEnvelope{
+		ID:        d.EventID,
+		Type:      d.EventType,
+		CreatedAt: d.EventCreatedAt.UTC(),
+		Data:      d.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+	ts := s.now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "cwrk-planet-webhooks/1")
+	req.Header.Set(HeaderWebhookID, d.ID)
+	req.Header.Set(HeaderWebhookEvent, d.EventType)
+	req.Header.Set(HeaderWebhookTimestamp, strconv.FormatInt(ts, 10))
+	req.Header.Set(HeaderWebhookSignature, SignWebhook(d.Secret, ts, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// тело ответа не нужно, но дочитываем немного, чтобы соединение переиспользовалось
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func denyPrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("webhook target %s is not allowed", host)
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	"github.com/cwrk-planet/events/pkg/events"
+	"github.com/google/uuid"
+)
+
+const (
+	webhookBatch   = 50              // доставок за один проход диспетчера
+	webhookWorkers = 4               // параллельных отправок
+	webhookLease   = 2 * time.Minute // сколько доставка считается "в работе" у захватившего её инстанса
+	maxWebhookURL  = 2048
+)
+
+// WebhookService — подписки владельцев на события и их доставка через webhook_events:
+// relay outbox-а отдаёт сюда доменные события (WebhookService — events.Sink), диспетчер
+// раскладывает их по подписчикам и отправляет с ретраями.
+// Подписка получает события своих комнат; системные (user.registered) — только владельцы из systemOwners.
+type WebhookService struct {
+	repo   WebhookStore
+	rooms  WebhookRooms
+	sender *WebhookSender
+	retry  domain.WebhookRetry
+	system map[int64]struct{}
+}
+
+// WebhookStore — подписки, события и доставки (postgres.WebhookRepository).
+type WebhookStore interface {
+	CreateSubscription(ctx context.Context, s *domain.WebhookSubscription) error
+	CountSubscriptions(ctx context.Context, ownerID int64) (int, error)
+	ListSubscriptions(ctx context.Context, ownerID int64) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, ownerID int64, id string) error
+	AddEvent(ctx context.Context, id, typ string, ownerID *int64, payload []byte, createdAt time.Time) error
+	FanOut(ctx context.Context, limit int) (int64, error)
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id string, code int) error
+	MarkFailed(ctx context.Context, id string, code *int, msg string, next time.Time, dead bool) error
+	ListDeliveries(ctx context.Context, ownerID int64, subID string, status domain.WebhookDeliveryStatus, after string, limit int) ([]domain.WebhookDelivery, string, error)
+	Redeliver(ctx context.Context, ownerID int64, id string) (*domain.WebhookDelivery, error)
+}
+
+// WebhookRooms — владелец комнаты события (postgres.RoomRepository).
+type WebhookRooms interface {
+	Get(ctx context.Context, id string) (*domain.Room, error)
+}
+
+func NewWebhookService(repo WebhookStore, rooms WebhookRooms, sender *WebhookSender, retry domain.WebhookRetry, systemOwners []int64) *WebhookService {
+	system := make(map[int64]struct{}, len(systemOwners))
+	for _, id := range systemOwners {
+		system[id] = struct{}{}
+	}
+	return &WebhookService{repo: repo, rooms: rooms, sender: sender, retry: retry, system: system}
+}
+
+// CreateSubscription — секрет для проверки подписи возвращается только здесь.
+func (s *WebhookService) CreateSubscription(ctx context.Context, ownerID int64, rawURL string, events []string) (*domain.WebhookSubscription, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || len(rawURL) > maxWebhookURL {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", domain.ErrWebhookInvalid)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("%w: credentials in url are not allowed", domain.ErrWebhookInvalid)
+	}
+
+	var evs []string
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if !slices.Contains(domain.WebhookEventTypes, e) {
+			return nil, fmt.Errorf("%w: unknown event %q", domain.ErrWebhookInvalid, e)
+		}
+		if !slices.Contains(evs, e) {
+			evs = append(evs, e)
+		}
+	}
+	if len(evs) == 0 {
+		return nil, fmt.Errorf("%w: events are required", domain.ErrWebhookInvalid)
+	}
+	if slices.ContainsFunc(evs, domain.IsSystemWebhookEvent) {
+		if _, ok := s.system[ownerID]; !ok {
+			return nil, domain.ErrForbidden
+		}
+	}
+
+	n, err := s.repo.CountSubscriptions(ctx, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if n >= domain.MaxWebhooksPerOwner {
+		return nil, fmt.Errorf("%w: at most %d webhooks per owner", domain.ErrWebhookInvalid, domain.MaxWebhooksPerOwner)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	sub := &domain.WebhookSubscription{
+		OwnerID: ownerID,
+		URL:     u.String(),
+		Secret:  "whsec_" + hex.EncodeToString(secret),
+		Events:  evs,
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, ownerID int64) ([]domain.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx, ownerID)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, ownerID int64, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return domain.ErrWebhookNotFound
+	}
+	return s.repo.DeleteSubscription(ctx, ownerID, id)
+}
+
+func (s *WebhookService) ListDeliveries(ctx context.Context, ownerID int64, subID, status, after string, limit int) ([]domain.WebhookDelivery, string, error) {
+	if _, err := uuid.Parse(subID); err != nil {
+		return nil, "", domain.ErrWebhookNotFound
+	}
+	st := domain.WebhookDeliveryStatus(status)
+	switch st {
+	case "", domain.DeliveryPending, domain.DeliveryDelivered, domain.DeliveryDead:
+	default:
+		return nil, "", fmt.Errorf("%w: unknown status %q", domain.ErrWebhookInvalid, status)
+	}
+	return s.repo.ListDeliveries(ctx, ownerID, subID, st, after, limit)
+}
+
+// Redeliver — отправить доставку ещё раз (подхватит ближайший проход диспетчера).
+func (s *WebhookService) Redeliver(ctx context.Context, ownerID int64, deliveryID string) (*domain.WebhookDelivery, error) {
+	if _, err := uuid.Parse(deliveryID); err != nil {
+		return nil, domain.ErrDeliveryNotFound
+	}
+	return s.repo.Redeliver(ctx, ownerID, deliveryID)
+}
+
+func (s *WebhookService) Name() string { return "webhooks" }
+
+// Publish — событие outbox-а в webhook_events. id тот же, что у события, поэтому повтор
+// от relay ничего не дублирует. События комнат получают подписки владельца комнаты (Key — room_id).
+// Комната без владельца (или уже удалённая) — событие никому: owner_id NULL в webhook_events
+// значит "системное" и ушло бы всем подписчикам этого типа.
+func (s *WebhookService) Publish(ctx context.Context, env events.Envelope) error {
+	if !slices.Contains(domain.WebhookEventTypes, env.Type) {
+		return nil
+	}
+	var ownerID *int64
+	if !domain.IsSystemWebhookEvent(env.Type) {
+		if _, err := uuid.Parse(env.Key); err != nil {
+			return nil
+		}
+		room, err := s.rooms.Get(ctx, env.Key)
+		if errors.Is(err, domain.ErrRoomNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if room.OwnerID == nil {
+			return nil
+		}
+		ownerID = room.OwnerID
+	}
+	return s.repo.AddEvent(ctx, env.ID, env.Type, ownerID, env.Payload, env.OccurredAt)
+}
+
+// Run — диспетчер: раз в every раскладывает новые события и отправляет доставки, которым пора.
+func (s *WebhookService) Run(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := s.Dispatch(ctx); err != nil && ctx.Err() == nil {
+				slog.Warn("webhooks: dispatch failed", "err", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Dispatch — один проход: fan-out событий, затем отправка, пока есть что отправлять.
+func (s *WebhookService) Dispatch(ctx context.Context) error {
+	for {
+		n, err := s.repo.FanOut(ctx, webhookBatch)
+		if err != nil {
+			return err
+		}
+		if n < webhookBatch {
+			break
+		}
+	}
+
+	for {
+		due, err := s.repo.ClaimDue(ctx, webhookBatch, webhookLease)
+		if err != nil {
+			return err
+		}
+
+		jobs := make(chan domain.WebhookDelivery)
+		var wg sync.WaitGroup
+		for range min(webhookWorkers, len(due)) {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for d := range jobs {
+					s.deliver(ctx, d)
+				}
+			}()
+		}
+		for _, d := range due {
+			jobs <- d
+		}
+		close(jobs)
+		wg.Wait()
+
+		if len(due) < webhookBatch || ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+func (s *WebhookService) deliver(ctx context.Context, d domain.WebhookDelivery) {
+	code, err := s.sender.Send(ctx, d)
+	if err != nil && ctx.Err() != nil {
+		return // останавливаемся — доставку после lease заберёт следующий проход
+	}
+	dbCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err == nil {
+		if err := s.repo.MarkDelivered(dbCtx, d.ID, code); err != nil {
+			slog.Warn("webhooks: mark delivered failed", "delivery", d.ID, "err", err)
+		}
+		return
+	}
+
+	wait, dead := s.retry.Next(d.Attempts + 1)
+	var last *int
+	if code != 0 {
+		last = &code
+	}
+	if err := s.repo.MarkFailed(dbCtx, d.ID, last, truncateErr(err.Error(), 500), time.Now().Add(wait), dead); err != nil {
+		slog.Warn("webhooks: mark failed failed", "delivery", d.ID, "err", err)
+	}
+	if dead {
+		slog.Warn("webhooks: delivery is dead", "delivery", d.ID, "event", d.EventType, "attempts", d.Attempts+1, "err", err)
+	}
+}
+
+func truncateErr(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+)
+
+const breakoutRoomID = "4d3c2b1a-0f9e-4d8c-b7a6-5f4e3d2c1b0a"
+
+// в комнате breakoutRoomID модератор 10 и участники 1–4
+func newBreakouts(t *testing.T) (*service.BreakoutService, *memParticipants, chan domain.BreakoutEvent) {
+	t.Helper()
+	parts := newMemParticipants()
+	parts.add(breakoutRoomID, 10, 1, 2, 3, 4)
+	svc := service.NewBreakoutService(newMemBreakouts(parts), memModerators{breakoutRoomID: {10}}, parts)
+	events := make(chan domain.BreakoutEvent, 16)
+	svc.SetOnChange(func(ev domain.BreakoutEvent) { events <- ev })
+	return svc, parts, events
+}
+
+func nextBreakoutEvent(t *testing.T, ch chan domain.BreakoutEvent) domain.BreakoutEvent {
+	t.Helper()
+	select {
+	case ev := <-ch:
+		return ev
+	case <-time.After(3 * time.Second):
+		t.Fatal("no breakout event")
+		return domain.BreakoutEvent{}
+	}
+}
+
+func TestBreakout_StartValidation(t *testing.T) {
+	svc, _, _ := newBreakouts(t)
+	ctx := context.Background()
+	ok := domain.BreakoutInput{Count: 2, Duration: 10 * time.Minute}
+
+	with := func(f func(*domain.BreakoutInput)) domain.BreakoutInput {
+		in := ok
+		f(&in)
+		return in
+	}
+	cases := []struct {
+		name  string
+		actor int64
+		in    domain.BreakoutInput
+		want  error
+	}{
+		{"participant", 1, ok, domain.ErrForbidden},
+		{"one room", 10, with(func(in *domain.BreakoutInput) { in.Count = 1 }), domain.ErrBreakoutInvalid},
+		{"too short", 10, with(func(in *domain.BreakoutInput) { in.Duration = 10 * time.Second }), domain.ErrInvalidDuration},
+		{"index out of range", 10, with(func(in *domain.BreakoutInput) { in.Assign = map[int64]int{1: 2} }), domain.ErrBreakoutInvalid},
+		{"stranger assigned", 10, with(func(in *domain.BreakoutInput) { in.Assign = map[int64]int{1: 0, 99: 1} }), domain.ErrBreakoutInvalid},
+	}
+	for _, c := range cases {
+		if _, err := svc.Start(ctx, c.actor, breakoutRoomID, c.in); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	if _, err := svc.Start(ctx, 10, breakoutRoomID, ok); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Start(ctx, 10, breakoutRoomID, ok); !errors.Is(err, domain.ErrBreakoutActive) {
+		t.Fatalf("second start: err = %v", err)
+	}
+}
+
+func TestBreakout_AssignmentMovesParticipants(t *testing.T) {
+	svc, parts, events := newBreakouts(t)
+	ctx := context.Background()
+
+	b, err := svc.Start(ctx, 10, breakoutRoomID, domain.BreakoutInput{
+		Count: 2, Names: []string{" алгебра ", ""}, Random: true,
+		Assign: map[int64]int{1: 1}, Duration: 10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g1, g2 := b.Rooms[0], b.Rooms[1]
+	if g1.Name != "алгебра" || g2.Name != "Группа 2" {
+		t.Fatalf("names = %q %q", g1.Name, g2.Name)
+	}
+	// ручное сохранено, случайные 2–4 раскиданы по пустым группам, модератор остаётся
+	if !slices.Contains(g2.Users, 1) || len(g1.Users)+len(g2.Users) != 4 || len(g1.Users) != 2 {
+		t.Fatalf("groups = %v / %v", g1.Users, g2.Users)
+	}
+	if got := parts.in(breakoutRoomID); !slices.Equal(got, []int64{10}) {
+		t.Fatalf("left in main room: %v", got)
+	}
+	if ev := nextBreakoutEvent(t, events); ev.Kind != domain.BreakoutStarted || ev.Moves[1] != g2.ID || len(ev.Moves) != 4 {
+		t.Fatalf("started = %+v", ev)
+	}
+
+	// переводить можно только участников — из любой группы
+	if _, err := svc.Assign(ctx, 10, breakoutRoomID, 99, g1.ID); !errors.Is(err, domain.ErrBreakoutInvalid) {
+		t.Fatalf("assign stranger: err = %v", err)
+	}
+	if _, err := svc.Assign(ctx, 1, breakoutRoomID, 2, g1.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("assign by participant: err = %v", err)
+	}
+	if _, err := svc.Assign(ctx, 10, breakoutRoomID, 1, "4d3c2b1a-0000-4000-8000-000000000000"); !errors.Is(err, domain.ErrBreakoutInvalid) {
+		t.Fatalf("unknown target: err = %v", err)
+	}
+	b, err = svc.Assign(ctx, 10, g2.ID, 1, g1.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(b.Rooms[0].Users, 1) || !slices.Contains(parts.in(g1.ID), 1) {
+		t.Fatalf("after move: %v, in g1 %v", b.Rooms[0].Users, parts.in(g1.ID))
+	}
+	if ev := nextBreakoutEvent(t, events); ev.Kind != domain.BreakoutMoved || ev.Moves[1] != g1.ID {
+		t.Fatalf("moved = %+v", ev)
+	}
+
+	// переподключение к основной — сразу в свою группу, модератор — куда просил
+	if to, err := svc.Resolve(ctx, breakoutRoomID, 1); err != nil || to != g1.ID {
+		t.Fatalf("resolve participant: %s %v", to, err)
+	}
+	if to, _ := svc.Resolve(ctx, g2.ID, 10); to != g2.ID {
+		t.Fatalf("resolve moderator: %s", to)
+	}
+
+	if err := svc.End(ctx, 10, g1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := parts.in(breakoutRoomID); !slices.Equal(got, []int64{1, 2, 3, 4, 10}) {
+		t.Fatalf("after end: %v", got)
+	}
+	if ev := nextBreakoutEvent(t, events); ev.Kind != domain.BreakoutEnded || ev.Moves[1] != breakoutRoomID {
+		t.Fatalf("ended = %+v", ev)
+	}
+	if _, err := svc.ForRoom(ctx, breakoutRoomID); !errors.Is(err, domain.ErrBreakoutNotFound) {
+		t.Fatalf("after end: err = %v", err)
+	}
+}
+
+// Отсчёт и автовозврат: breakout заведён с коротким сроком напрямую в хранилище
+// (Start не даёт меньше минуты) и подхвачен Restore, как после рестарта.
+func TestBreakout_CountdownAndAutoReturn(t *testing.T) {
+	parts := newMemParticipants()
+	parts.add(breakoutRoomID, 10, 1, 2)
+	store := newMemBreakouts(parts)
+	svc := service.NewBreakoutService(store, memModerators{breakoutRoomID: {10}}, parts)
+	events := make(chan domain.BreakoutEvent, 16)
+	svc.SetOnChange(func(ev domain.BreakoutEvent) { events <- ev })
+	ctx := context.Background()
+
+	b := &domain.Breakout{
+		ParentID: breakoutRoomID,
+		EndsAt:   time.Now().Add(1500 * time.Millisecond),
+		Rooms:    []domain.BreakoutRoom{{Name: "A", Users: []int64{1}}, {Name: "B", Users: []int64{2}}},
+	}
+	if err := store.Create(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Restore(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	ev := nextBreakoutEvent(t, events)
+	if ev.Kind != domain.BreakoutCountdown || ev.Left != time.Second {
+		t.Fatalf("countdown = %+v", ev)
+	}
+	ev = nextBreakoutEvent(t, events)
+	if ev.Kind != domain.BreakoutEnded || ev.Moves[1] != breakoutRoomID || ev.Moves[2] != breakoutRoomID {
+		t.Fatalf("ended = %+v", ev)
+	}
+	if time.Now().Before(b.EndsAt) {
+		t.Fatal("ended before time")
+	}
+	if got := parts.in(breakoutRoomID); !slices.Equal(got, []int64{1, 2, 10}) {
+		t.Fatalf("after auto-return: %v", got)
+	}
+}
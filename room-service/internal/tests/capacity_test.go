@@ -0,0 +1,145 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+)
+
+var testLimits = domain.CapacityLimits{
+	Default:     10,
+	DefaultPlan: "free",
+	Plans:       map[string]int64{"free": 25, "pro": 300},
+}
+
+// 2 — pro, 3 — тариф, которого нет в конфиге, остальные без записи (free)
+func newRoomsWithPlans(limits domain.CapacityLimits) (*service.RoomService, *memRooms) {
+	rooms := newMemRooms()
+	return service.NewRoomService(rooms, memPlans{2: "pro", 3: "legacy"}, limits), rooms
+}
+
+func TestRoom_CapacityByPlan(t *testing.T) {
+	svc, _ := newRoomsWithPlans(testLimits)
+	ctx := context.Background()
+	name := ptr("урок")
+
+	cases := []struct {
+		name  string
+		owner int64
+		max   int64
+		want  int64 // 0 — ErrInvalidCapacity
+	}{
+		{"default max", 1, 0, 10},
+		{"free at limit", 1, 25, 25},
+		{"free over limit", 1, 26, 0},
+		{"negative", 1, -1, 0},
+		{"pro", 2, 300, 300},
+		{"pro over limit", 2, 301, 0},
+		{"unknown plan is free", 3, 26, 0},
+		{"ownerless is free", 0, 25, 25},
+	}
+	for _, c := range cases {
+		room, err := svc.CreateRoom(ctx, c.owner, domain.RoomInput{Name: name, Max: c.max})
+		if c.want == 0 {
+			if !errors.Is(err, domain.ErrInvalidCapacity) {
+				t.Errorf("%s: err = %v", c.name, err)
+			}
+			continue
+		}
+		if err != nil || room.MaxParticipants != c.want {
+			t.Errorf("%s: %+v %v", c.name, room, err)
+		}
+	}
+
+	for uid, want := range map[int64]int64{1: 25, 2: 300, 3: 25} {
+		if got, err := svc.Capacity(ctx, uid); err != nil || got != want {
+			t.Errorf("capacity(%d) = %d %v, want %d", uid, got, err, want)
+		}
+	}
+
+	// умолчание не больше лимита тарифа
+	small, _ := newRoomsWithPlans(domain.CapacityLimits{Default: 50, DefaultPlan: "free", Plans: map[string]int64{"free": 5}})
+	if room, err := small.CreateRoom(ctx, 1, domain.RoomInput{Name: name}); err != nil || room.MaxParticipants != 5 {
+		t.Fatalf("default above plan: %+v %v", room, err)
+	}
+}
+
+func TestMember_JoinRejectsOverCapacity(t *testing.T) {
+	rooms, store := newRoomsWithPlans(testLimits)
+	parts := newMemParticipants()
+	members := service.NewMemberService(store, parts)
+	ctx := context.Background()
+
+	room, err := rooms.CreateRoom(ctx, 1, domain.RoomInput{Name: ptr("семинар"), Max: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for uid := int64(1); uid <= 3; uid++ {
+		if _, err := members.JoinRoom(ctx, room.ID, uid); err != nil {
+			t.Fatalf("join %d: %v", uid, err)
+		}
+	}
+	if _, err := members.JoinRoom(ctx, room.ID, 4); !errors.Is(err, domain.ErrRoomFull) {
+		t.Fatalf("4th join: err = %v", err)
+	}
+	if _, err := members.JoinRoom(ctx, room.ID, 1); !errors.Is(err, domain.ErrAlreadyJoined) {
+		t.Fatalf("rejoin: err = %v", err)
+	}
+	// место освободилось
+	if err := members.LeaveRoom(ctx, room.ID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := members.JoinRoom(ctx, room.ID, 4); err != nil {
+		t.Fatalf("join after leave: %v", err)
+	}
+	if _, err := members.JoinRoom(ctx, "missing", 5); !errors.Is(err, domain.ErrRoomNotFound) {
+		t.Fatalf("unknown room: err = %v", err)
+	}
+}
+
+func TestMember_ConcurrentJoinsRespectLimit(t *testing.T) {
+	rooms, store := newRoomsWithPlans(testLimits)
+	parts := newMemParticipants()
+	members := service.NewMemberService(store, parts)
+	ctx := context.Background()
+
+	room, err := rooms.CreateRoom(ctx, 1, domain.RoomInput{Name: ptr("лекция"), Max: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		joined     atomic.Int32
+		full       atomic.Int32
+		unexpected atomic.Value
+	)
+	for uid := int64(1); uid <= 20; uid++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := members.JoinRoom(ctx, room.ID, uid)
+			switch {
+			case err == nil:
+				joined.Add(1)
+			case errors.Is(err, domain.ErrRoomFull):
+				full.Add(1)
+			default:
+				unexpected.Store(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := unexpected.Load(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if joined.Load() != 5 || full.Load() != 15 || len(parts.in(room.ID)) != 5 {
+		t.Fatalf("joined %d, full %d, in room %d", joined.Load(), full.Load(), len(parts.in(room.ID)))
+	}
+}
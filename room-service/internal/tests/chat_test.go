@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+)
+
+const chatRoomID = "0b7f5a2e-6c1d-4f3a-8e9b-1a2b3c4d5e6f"
+
+func TestChatService_Validation(t *testing.T) {
+	mem := newMemChat()
+	svc := service.NewChatService(mem, mem)
+	ctx := context.Background()
+
+	if _, _, err := svc.Save(ctx, chatRoomID, 1, "   ", nil); !errors.Is(err, domain.ErrEmptyMessage) {
+		t.Fatalf("empty: err = %v", err)
+	}
+	if _, _, err := svc.Save(ctx, chatRoomID, 1, strings.Repeat("a", 4001), nil); !errors.Is(err, domain.ErrMessageTooLong) {
+		t.Fatalf("too long: err = %v", err)
+	}
+	if _, _, err := svc.Save(ctx, chatRoomID, 1, "", make([]string, 11)); !errors.Is(err, domain.ErrAttachmentInvalid) {
+		t.Fatalf("attachments: err = %v", err)
+	}
+	if _, _, err := svc.MarkRead(ctx, chatRoomID, 1, "not-a-uuid"); !errors.Is(err, domain.ErrMessageNotFound) {
+		t.Fatalf("mark read: err = %v", err)
+	}
+	if len(mem.msgs) != 0 {
+		t.Fatalf("rejected messages were saved: %v", mem.msgs)
+	}
+}
+
+func TestWS_TypingThrottleAndExpiry(t *testing.T) {
+	mem := newMemChat()
+	env := newWSEnv(t, wsDeps{chat: service.NewChatService(mem, mem)})
+	env.srv.SetTypingTimeouts(400*time.Millisecond, 300*time.Millisecond)
+
+	a, b := env.dial(t, chatRoomID, 1), env.dial(t, chatRoomID, 2)
+	a.expect(ws.TypeState, nil)
+	b.expect(ws.TypeState, nil)
+
+	// три typing_start подряд — остальным уходит один, самому себе ничего
+	for i := 0; i < 3; i++ {
+		a.send(ws.TypeTypingStart, nil)
+	}
+	if n := b.count(ws.TypeTypingStart, 200*time.Millisecond); n != 1 {
+		t.Fatalf("b got %d typing_start, want 1 (throttled)", n)
+	}
+	// typing_stop клиент не прислал — гаснет по ttl
+	var stop ws.TypingPayload
+	b.expect(ws.TypeTypingStop, &stop)
+	if stop.UserID != "1" || stop.RoomID != chatRoomID {
+		t.Fatalf("typing_stop = %+v", stop)
+	}
+	if n := a.count(ws.TypeTypingStart, 50*time.Millisecond); n != 0 {
+		t.Fatalf("sender got its own typing_start")
+	}
+
+	// отправка сообщения гасит "печатает" сразу
+	a.send(ws.TypeTypingStart, nil)
+	b.expect(ws.TypeTypingStart, nil)
+	a.send(ws.TypeChat, ws.ChatPayload{Message: "готово"})
+	b.expect(ws.TypeTypingStop, nil)
+	b.expect(ws.TypeChat, nil)
+}
+
+func TestWS_ReadMarkers(t *testing.T) {
+	mem := newMemChat()
+	env := newWSEnv(t, wsDeps{chat: service.NewChatService(mem, mem)})
+
+	a, b := env.dial(t, chatRoomID, 1), env.dial(t, chatRoomID, 2)
+	a.expect(ws.TypeState, nil)
+	b.expect(ws.TypeState, nil)
+
+	var ids []string
+	for _, text := range []string{"раз", "два"} {
+		a.send(ws.TypeChat, ws.ChatPayload{Message: text})
+		var m ws.ChatPayload
+		b.expect(ws.TypeChat, &m)
+		ids = append(ids, m.MsgID)
+	}
+
+	b.send(ws.TypeReadMarker, ws.ReadMarkerPayload{MsgID: ids[1]})
+	var rm ws.ReadMarkerPayload
+	a.expect(ws.TypeReadMarker, &rm)
+	if rm.UserID != "2" || rm.MsgID != ids[1] {
+		t.Fatalf("read_marker = %+v", rm)
+	}
+	b.expect(ws.TypeReadMarker, nil)
+
+	// маркер назад не двигается и не рассылается
+	b.send(ws.TypeReadMarker, ws.ReadMarkerPayload{MsgID: ids[0]})
+	if n := a.count(ws.TypeReadMarker, 200*time.Millisecond); n != 0 {
+		t.Fatalf("older marker was broadcast")
+	}
+	b.send(ws.TypeReadMarker, ws.ReadMarkerPayload{MsgID: "nope"})
+	var e ws.ErrorPayload
+	b.expect(ws.TypeError, &e)
+	if e.Code != "read_marker_rejected" {
+		t.Fatalf("error = %+v", e)
+	}
+
+	// снапшот: кто что прочитал и сколько непрочитанного у получателя
+	var st ws.StatePayload
+	env.dial(t, chatRoomID, 2).expect(ws.TypeState, &st)
+	if st.UnreadCount != 0 || len(st.ReadMarkers) != 1 || st.ReadMarkers[0].MsgID != ids[1] {
+		t.Fatalf("state for reader: unread=%d markers=%+v", st.UnreadCount, st.ReadMarkers)
+	}
+	env.dial(t, chatRoomID, 3).expect(ws.TypeState, &st)
+	if st.UnreadCount != 2 {
+		t.Fatalf("state for newcomer: unread=%d, want 2", st.UnreadCount)
+	}
+	env.dial(t, chatRoomID, 1).expect(ws.TypeState, &st)
+	if st.UnreadCount != 0 {
+		t.Fatalf("own messages are unread: %d", st.UnreadCount)
+	}
+}
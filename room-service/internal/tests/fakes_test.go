@@ -0,0 +1,1069 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/postgres"
+	"github.com/cwrk-planet/room-service/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// memChat — сообщения и маркеры прочтения в памяти (service.ChatStore + service.ReadMarkerStore).
+// Порядок сообщений — порядок Save, как (created_at,id) в Postgres.
+type memChat struct {
+	service.ChatStore // History/ByUser тестам не нужны
+
+	mu      sync.Mutex
+	msgs    []domain.ChatMessage
+	markers map[string]map[int64]domain.ReadMarker
+}
+
+func newMemChat() *memChat {
+	return &memChat{markers: make(map[string]map[int64]domain.ReadMarker)}
+}
+
+func (m *memChat) Save(_ context.Context, roomID string, userID int64, text string, replyTo *string, attachments []string) (*domain.ChatMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msg := domain.ChatMessage{ID: uuid.NewString(), RoomID: roomID, UserID: userID, Text: text, ReplyTo: replyTo, CreatedAt: time.Now(), Attachments: attachments}
+	m.msgs = append(m.msgs, msg)
+	return &msg, nil
+}
+
+func (m *memChat) Since(_ context.Context, roomID, afterID string, limit int) ([]domain.ChatMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.index(roomID, afterID)
+	if i < 0 {
+		return nil, domain.ErrMessageNotFound
+	}
+	var out []domain.ChatMessage
+	for _, msg := range m.msgs[i+1:] {
+		if msg.RoomID == roomID && len(out) < limit {
+			out = append(out, msg)
+		}
+	}
+	return out, nil
+}
+
+func (m *memChat) index(roomID, id string) int {
+	for i, msg := range m.msgs {
+		if msg.ID == id && msg.RoomID == roomID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *memChat) Set(_ context.Context, roomID string, userID int64, msgID string) (*domain.ReadMarker, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := m.index(roomID, msgID)
+	if i < 0 {
+		return nil, false, domain.ErrMessageNotFound
+	}
+	cur, ok := m.markers[roomID][userID]
+	if ok && m.index(roomID, cur.MsgID) >= i {
+		return &cur, false, nil
+	}
+	rm := domain.ReadMarker{RoomID: roomID, UserID: userID, MsgID: msgID, MsgCreatedAt: m.msgs[i].CreatedAt, UpdatedAt: time.Now()}
+	if m.markers[roomID] == nil {
+		m.markers[roomID] = make(map[int64]domain.ReadMarker)
+	}
+	m.markers[roomID][userID] = rm
+	return &rm, true, nil
+}
+
+func (m *memChat) List(_ context.Context, roomID string) ([]domain.ReadMarker, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.ReadMarker
+	for _, rm := range m.markers[roomID] {
+		out = append(out, rm)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].UserID < out[j].UserID })
+	return out, nil
+}
+
+func (m *memChat) UnreadCount(_ context.Context, roomID string, userID int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from := -1
+	if rm, ok := m.markers[roomID][userID]; ok {
+		from = m.index(roomID, rm.MsgID)
+	}
+	var n int64
+	for _, msg := range m.msgs[from+1:] {
+		if msg.RoomID == roomID && msg.UserID != userID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// memPresence — снапшоты presence (service.PresenceStore); fail — следующая запись упадёт.
+type memPresence struct {
+	mu    sync.Mutex
+	snaps map[roomUser]domain.Presence
+	saves int
+	fail  error
+}
+
+func newMemPresence() *memPresence {
+	return &memPresence{snaps: make(map[roomUser]domain.Presence)}
+}
+
+// roomUser — ключ "пользователь в комнате" для фейков.
+type roomUser struct {
+	roomID string
+	userID int64
+}
+
+func (m *memPresence) Get(_ context.Context, roomID string, userID int64) (*domain.Presence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.snaps[roomUser{roomID, userID}]
+	if !ok {
+		return nil, domain.ErrNotInRoom
+	}
+	return &p, nil
+}
+
+func (m *memPresence) SaveBatch(_ context.Context, items []domain.Presence) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.fail; err != nil {
+		m.fail = nil
+		return err
+	}
+	m.saves++
+	for _, p := range items {
+		m.snaps[roomUser{p.RoomID, p.UserID}] = p
+	}
+	return nil
+}
+
+func (m *memPresence) get(roomID string, userID int64) domain.Presence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snaps[roomUser{roomID, userID}]
+}
+
+// memModerators — service.RoomModerators: модераторы по комнатам.
+type memModerators map[string][]int64
+
+func (m memModerators) IsModerator(_ context.Context, roomID string, userID int64) (bool, error) {
+	return slices.Contains(m[roomID], userID), nil
+}
+
+// memHands — очередь рук и трибуна (service.HandQueueStore).
+type memHands struct {
+	mu     sync.Mutex
+	queue  map[string][]domain.HandRaise
+	floors map[string]domain.Floor
+}
+
+func newMemHands() *memHands {
+	return &memHands{queue: make(map[string][]domain.HandRaise), floors: make(map[string]domain.Floor)}
+}
+
+func (m *memHands) Raise(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, h := range m.queue[roomID] {
+		if h.UserID == userID {
+			return false, nil
+		}
+	}
+	m.queue[roomID] = append(m.queue[roomID], domain.HandRaise{RoomID: roomID, UserID: userID, RaisedAt: time.Now()})
+	return true, nil
+}
+
+func (m *memHands) Lower(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lower(roomID, userID), nil
+}
+
+func (m *memHands) lower(roomID string, userID int64) bool {
+	q := m.queue[roomID]
+	for i, h := range q {
+		if h.UserID == userID {
+			m.queue[roomID] = slices.Delete(q, i, i+1)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memHands) Get(_ context.Context, roomID string) (*domain.HandQueue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	q := &domain.HandQueue{RoomID: roomID, Queue: slices.Clone(m.queue[roomID])}
+	if f, ok := m.floors[roomID]; ok {
+		q.Floor = &f
+	}
+	return q, nil
+}
+
+func (m *memHands) GrantFloor(_ context.Context, roomID string, userID, grantedBy int64, expiresAt *time.Time) (*domain.Floor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if userID == 0 {
+		if len(m.queue[roomID]) == 0 {
+			return nil, domain.ErrHandQueueEmpty
+		}
+		userID = m.queue[roomID][0].UserID
+	}
+	m.lower(roomID, userID)
+	f := domain.Floor{RoomID: roomID, UserID: userID, GrantedBy: &grantedBy, GrantedAt: time.Now(), ExpiresAt: expiresAt}
+	m.floors[roomID] = f
+	return &f, nil
+}
+
+func (m *memHands) ReleaseFloor(_ context.Context, roomID string, userID int64, grantedAt *time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.floors[roomID]
+	if !ok || (userID != 0 && f.UserID != userID) || (grantedAt != nil && !f.GrantedAt.Equal(*grantedAt)) {
+		return false, nil
+	}
+	delete(m.floors, roomID)
+	return true, nil
+}
+
+func (m *memHands) TimedFloors(context.Context) ([]domain.Floor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.Floor
+	for _, f := range m.floors {
+		if f.ExpiresAt != nil {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func (m *memHands) floor(roomID string) (domain.Floor, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.floors[roomID]
+	return f, ok
+}
+
+// memPolls — опросы и голоса (service.PollStore).
+type memPolls struct {
+	mu    sync.Mutex
+	polls map[string]*domain.Poll
+	votes map[string][]domain.PollVote
+}
+
+func newMemPolls() *memPolls {
+	return &memPolls{polls: make(map[string]*domain.Poll), votes: make(map[string][]domain.PollVote)}
+}
+
+func (m *memPolls) Create(_ context.Context, p *domain.Poll) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p.ID, p.Status, p.CreatedAt = uuid.NewString(), domain.PollOpen, time.Now()
+	cp := *p
+	m.polls[p.ID] = &cp
+	return nil
+}
+
+func (m *memPolls) Get(_ context.Context, id string) (*domain.Poll, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.polls[id]
+	if !ok {
+		return nil, domain.ErrPollNotFound
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (m *memPolls) ListByRoom(_ context.Context, roomID string, onlyOpen bool) ([]domain.Poll, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.Poll
+	for _, p := range m.polls {
+		if p.RoomID == roomID && (!onlyOpen || p.Status == domain.PollOpen) {
+			out = append(out, *p)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CreatedAt.Before(out[j].CreatedAt) })
+	return out, nil
+}
+
+func (m *memPolls) OpenTimed(context.Context) ([]domain.Poll, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.Poll
+	for _, p := range m.polls {
+		if p.Status == domain.PollOpen && p.ClosesAt != nil {
+			out = append(out, *p)
+		}
+	}
+	return out, nil
+}
+
+func (m *memPolls) Vote(_ context.Context, pollID string, userID int64, options []int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.polls[pollID]
+	if !ok {
+		return domain.ErrPollNotFound
+	}
+	if p.Status != domain.PollOpen {
+		return domain.ErrPollClosed
+	}
+	for _, v := range m.votes[pollID] {
+		if v.UserID == userID {
+			return domain.ErrAlreadyVoted
+		}
+	}
+	m.votes[pollID] = append(m.votes[pollID], domain.PollVote{PollID: pollID, UserID: userID, Options: options, CreatedAt: time.Now()})
+	return nil
+}
+
+func (m *memPolls) Close(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, ok := m.polls[id]
+	if !ok || p.Status != domain.PollOpen {
+		return false, nil
+	}
+	now := time.Now()
+	p.Status, p.ClosedAt = domain.PollClosed, &now
+	return true, nil
+}
+
+func (m *memPolls) Results(_ context.Context, p *domain.Poll) (*domain.PollResults, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	res := &domain.PollResults{PollID: p.ID, Counts: make([]int64, len(p.Options))}
+	for _, v := range m.votes[p.ID] {
+		res.Voters++
+		for _, i := range v.Options {
+			res.Counts[i]++
+		}
+	}
+	return res, nil
+}
+
+func (m *memPolls) Votes(_ context.Context, pollID string) ([]domain.PollVote, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Clone(m.votes[pollID]), nil
+}
+
+func (m *memPolls) UserVote(_ context.Context, pollID string, userID int64) ([]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, v := range m.votes[pollID] {
+		if v.UserID == userID {
+			return v.Options, nil
+		}
+	}
+	return nil, nil
+}
+
+// memRooms — комнаты и модераторы (RoomRepository в части, нужной сервисам).
+// Модератор breakout-комнаты — модератор основной, как в IsModerator.
+type memRooms struct {
+	service.RoomStore
+
+	mu    sync.Mutex
+	rooms map[string]*domain.Room
+	mods  memModerators
+}
+
+func newMemRooms(rooms ...domain.Room) *memRooms {
+	m := &memRooms{rooms: make(map[string]*domain.Room), mods: memModerators{}}
+	for i := range rooms {
+		m.rooms[rooms[i].ID] = &rooms[i]
+	}
+	return m
+}
+
+func (m *memRooms) Get(_ context.Context, id string) (*domain.Room, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	r, ok := m.rooms[id]
+	if !ok {
+		return nil, domain.ErrRoomNotFound
+	}
+	cp := *r
+	return &cp, nil
+}
+
+func (m *memRooms) Create(_ context.Context, room *domain.Room) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	room.ID, room.CreatedAt = uuid.NewString(), time.Now()
+	cp := *room
+	m.rooms[room.ID] = &cp
+	return nil
+}
+
+// List — фильтры и порядок как в postgres.RoomRepository.List; Participants, Online
+// и ActivityAt берём из самих комнат, onlineSince не нужен.
+func (m *memRooms) List(_ context.Context, f domain.RoomFilter, _ time.Time) ([]domain.Room, string, error) {
+	cur, err := postgres.DecodeCursor(f.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	if cur != nil && cur.Sort != "" && cur.Sort != f.Sort {
+		return nil, "", postgres.ErrInvalidCursor
+	}
+	activity := f.Sort == domain.RoomSortActivity
+
+	// key — ключ сортировки по убыванию, сравнивается покомпонентно
+	key := func(r domain.Room) []string {
+		k := []string{r.CreatedAt.UTC().Format(time.RFC3339Nano), r.ID}
+		if activity {
+			k = append([]string{fmt.Sprintf("%020d", r.Online), r.ActivityAt.UTC().Format(time.RFC3339Nano)}, k...)
+		}
+		return k
+	}
+	var after []string
+	if cur != nil {
+		after = key(domain.Room{ID: cur.ID, CreatedAt: cur.CreatedAt, Online: cur.Online, ActivityAt: cur.ActivityAt})
+	}
+	q := strings.ToLower(f.Query)
+
+	m.mu.Lock()
+	var out []domain.Room
+	for _, r := range m.rooms {
+		switch {
+		case r.ParentID != nil,
+			q != "" && !strings.Contains(strings.ToLower(r.Name), q) && (r.Topic == nil || !strings.Contains(strings.ToLower(*r.Topic), q)),
+			f.Tag != "" && !slices.Contains(r.Tags, f.Tag),
+			f.Language != "" && (r.Language == nil || *r.Language != f.Language),
+			f.FreeSeats && r.Participants >= r.MaxParticipants,
+			f.LiveNow && r.Online == 0,
+			after != nil && slices.Compare(key(*r), after) >= 0:
+			continue
+		}
+		out = append(out, *r)
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(out, func(a, b domain.Room) int { return slices.Compare(key(b), key(a)) })
+	if len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	var next string
+	if len(out) == f.Limit {
+		last := out[len(out)-1]
+		c := postgres.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		if activity {
+			c.Sort, c.Online, c.ActivityAt = f.Sort, last.Online, last.ActivityAt
+		}
+		next, _ = postgres.EncodeCursor(c)
+	}
+	return out, next, nil
+}
+
+func (m *memRooms) IsModerator(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	scope := []string{roomID}
+	if r, ok := m.rooms[roomID]; ok && r.ParentID != nil {
+		scope = append(scope, *r.ParentID)
+	}
+	for _, id := range scope {
+		if r, ok := m.rooms[id]; ok && r.OwnerID != nil && *r.OwnerID == userID {
+			return true, nil
+		}
+		if slices.Contains(m.mods[id], userID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// memSchedules — расписания (service.ScheduleStore); members — комнаты пользователя для ListForUser.
+type memSchedules struct {
+	mu      sync.Mutex
+	items   map[string]domain.Schedule
+	members map[int64][]string
+}
+
+func newMemSchedules() *memSchedules {
+	return &memSchedules{items: make(map[string]domain.Schedule), members: make(map[int64][]string)}
+}
+
+func (m *memSchedules) put(sc domain.Schedule) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[sc.RoomID] = sc
+}
+
+func (m *memSchedules) Upsert(_ context.Context, sc *domain.Schedule) error {
+	m.put(*sc)
+	return nil
+}
+
+func (m *memSchedules) Get(_ context.Context, roomID string) (*domain.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sc, ok := m.items[roomID]
+	if !ok {
+		return nil, domain.ErrScheduleNotFound
+	}
+	return &sc, nil
+}
+
+func (m *memSchedules) List(context.Context) ([]domain.Schedule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]domain.Schedule, 0, len(m.items))
+	for _, sc := range m.items {
+		out = append(out, sc)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].RoomID < out[j].RoomID })
+	return out, nil
+}
+
+func (m *memSchedules) ListForUser(ctx context.Context, userID int64) ([]domain.Schedule, error) {
+	all, _ := m.List(ctx)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.Schedule
+	for _, sc := range all {
+		if slices.Contains(m.members[userID], sc.RoomID) {
+			out = append(out, sc)
+		}
+	}
+	return out, nil
+}
+
+func (m *memSchedules) Delete(_ context.Context, roomID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.items[roomID]; !ok {
+		return domain.ErrScheduleNotFound
+	}
+	delete(m.items, roomID)
+	return nil
+}
+
+// memFeeds — ленты .ics (service.CalendarFeedStore); hashes — что сохранили вместо токенов.
+type memFeeds struct {
+	mu     sync.Mutex
+	items  []domain.CalendarFeed
+	hashes []string
+}
+
+func (m *memFeeds) Create(_ context.Context, f *domain.CalendarFeed, tokenHash string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f.ID, f.CreatedAt = uuid.NewString(), time.Now()
+	cp := *f
+	cp.Token = ""
+	m.items = append(m.items, cp)
+	m.hashes = append(m.hashes, tokenHash)
+	return nil
+}
+
+func (m *memFeeds) Count(ctx context.Context, userID int64) (int, error) {
+	list, _ := m.List(ctx, userID)
+	return len(list), nil
+}
+
+func (m *memFeeds) List(_ context.Context, userID int64) ([]domain.CalendarFeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []domain.CalendarFeed
+	for _, f := range m.items {
+		if f.UserID == userID {
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+func (m *memFeeds) GetByTokenHash(_ context.Context, tokenHash string) (*domain.CalendarFeed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if i := slices.Index(m.hashes, tokenHash); i >= 0 {
+		f := m.items[i]
+		return &f, nil
+	}
+	return nil, domain.ErrFeedNotFound
+}
+
+func (m *memFeeds) Delete(_ context.Context, userID int64, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.IndexFunc(m.items, func(f domain.CalendarFeed) bool { return f.ID == id && f.UserID == userID })
+	if i < 0 {
+		return domain.ErrFeedNotFound
+	}
+	m.items = slices.Delete(m.items, i, i+1)
+	m.hashes = slices.Delete(m.hashes, i, i+1)
+	return nil
+}
+
+// memLobby — лобби и состав комнат (service.LobbyStore и service.RoomParticipants).
+type memLobby struct {
+	mu      sync.Mutex
+	lobbies map[string]*domain.Lobby
+	queue   map[string][]int64
+	members map[string][]int64
+	max     int
+}
+
+func newMemLobby(max int) *memLobby {
+	return &memLobby{
+		lobbies: make(map[string]*domain.Lobby),
+		queue:   make(map[string][]int64),
+		members: make(map[string][]int64),
+		max:     max,
+	}
+}
+
+func (m *memLobby) lobby(roomID string) *domain.Lobby {
+	l, ok := m.lobbies[roomID]
+	if !ok {
+		l = &domain.Lobby{RoomID: roomID}
+		m.lobbies[roomID] = l
+	}
+	return l
+}
+
+func (m *memLobby) Exists(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Contains(m.members[roomID], userID), nil
+}
+
+func (m *memLobby) Get(_ context.Context, roomID string) (*domain.Lobby, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l := *m.lobby(roomID)
+	return &l, nil
+}
+
+func (m *memLobby) SetEnabled(_ context.Context, roomID string, enabled bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lobby(roomID).Enabled = enabled
+	return nil
+}
+
+func (m *memLobby) SetStarted(_ context.Context, roomID string, started bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l := m.lobby(roomID)
+	switch {
+	case !started:
+		l.SessionStartedAt = nil
+	case l.SessionStartedAt == nil:
+		now := time.Now()
+		l.SessionStartedAt = &now
+	}
+	return nil
+}
+
+func (m *memLobby) Enqueue(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slices.Contains(m.queue[roomID], userID) {
+		return false, nil
+	}
+	m.queue[roomID] = append(m.queue[roomID], userID)
+	return true, nil
+}
+
+func (m *memLobby) Position(_ context.Context, roomID string, userID int64) (int, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.Index(m.queue[roomID], userID)
+	return i + 1, i >= 0, nil
+}
+
+func (m *memLobby) List(_ context.Context, roomID string) ([]domain.LobbyEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]domain.LobbyEntry, 0, len(m.queue[roomID]))
+	for _, uid := range m.queue[roomID] {
+		out = append(out, domain.LobbyEntry{RoomID: roomID, UserID: uid})
+	}
+	return out, nil
+}
+
+func (m *memLobby) Remove(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.Index(m.queue[roomID], userID)
+	if i < 0 {
+		return false, nil
+	}
+	m.queue[roomID] = slices.Delete(m.queue[roomID], i, i+1)
+	return true, nil
+}
+
+func (m *memLobby) Clear(_ context.Context, roomID string) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := m.queue[roomID]
+	delete(m.queue, roomID)
+	return out, nil
+}
+
+// Admit — как в postgres: по очереди, пока есть места; остальные ждут.
+func (m *memLobby) Admit(_ context.Context, roomID string, userIDs []int64) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	admitted := make([]int64, 0)
+	var rest []int64
+	for _, uid := range m.queue[roomID] {
+		if (userIDs != nil && !slices.Contains(userIDs, uid)) || len(m.members[roomID]) >= m.max {
+			rest = append(rest, uid)
+			continue
+		}
+		m.members[roomID] = append(m.members[roomID], uid)
+		admitted = append(admitted, uid)
+	}
+	m.queue[roomID] = rest
+	return admitted, nil
+}
+
+// memParticipants — кто в какой комнате (service.MemberParticipants).
+type memParticipants struct {
+	service.MemberParticipants
+
+	mu    sync.Mutex
+	rooms map[string][]int64
+}
+
+func newMemParticipants() *memParticipants {
+	return &memParticipants{rooms: make(map[string][]int64)}
+}
+
+func (m *memParticipants) add(roomID string, userIDs ...int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rooms[roomID] = append(m.rooms[roomID], userIDs...)
+}
+
+func (m *memParticipants) in(roomID string) []int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Sorted(slices.Values(m.rooms[roomID]))
+}
+
+// move — строка участника переезжает в to из любой из комнат from.
+func (m *memParticipants) move(userID int64, from []string, to string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	found := false
+	for _, roomID := range from {
+		if i := slices.Index(m.rooms[roomID], userID); i >= 0 {
+			m.rooms[roomID] = slices.Delete(m.rooms[roomID], i, i+1)
+			found = true
+		}
+	}
+	if found && !slices.Contains(m.rooms[to], userID) {
+		m.rooms[to] = append(m.rooms[to], userID)
+	}
+}
+
+func (m *memParticipants) Exists(_ context.Context, roomID string, userID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Contains(m.rooms[roomID], userID), nil
+}
+
+// Join — лимит под общей блокировкой, как FOR UPDATE в postgres.
+func (m *memParticipants) Join(_ context.Context, p *domain.Participant, maxParticipants int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if int64(len(m.rooms[p.RoomID])) >= maxParticipants {
+		return domain.ErrRoomFull
+	}
+	if !slices.Contains(m.rooms[p.RoomID], p.UserID) {
+		m.rooms[p.RoomID] = append(m.rooms[p.RoomID], p.UserID)
+	}
+	return nil
+}
+
+func (m *memParticipants) Leave(_ context.Context, roomID string, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	i := slices.Index(m.rooms[roomID], userID)
+	if i < 0 {
+		return domain.ErrNotInRoom
+	}
+	m.rooms[roomID] = slices.Delete(m.rooms[roomID], i, i+1)
+	return nil
+}
+
+func (m *memParticipants) ListByRoom(_ context.Context, roomID string) ([]domain.Participant, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]domain.Participant, 0, len(m.rooms[roomID]))
+	for _, uid := range m.rooms[roomID] {
+		out = append(out, domain.Participant{RoomID: roomID, UserID: uid})
+	}
+	return out, nil
+}
+
+// memBreakouts — разбиения (service.BreakoutStore); строки участников двигает в parts, как postgres.
+type memBreakouts struct {
+	mu       sync.Mutex
+	active   map[string]domain.Breakout // parentID -> без распределения
+	assigned map[string]map[int64]string
+	parents  map[string]string // дочерняя -> основная
+	parts    *memParticipants
+}
+
+func newMemBreakouts(parts *memParticipants) *memBreakouts {
+	return &memBreakouts{
+		active:   make(map[string]domain.Breakout),
+		assigned: make(map[string]map[int64]string),
+		parents:  make(map[string]string),
+		parts:    parts,
+	}
+}
+
+func (m *memBreakouts) Create(_ context.Context, b *domain.Breakout) error {
+	m.mu.Lock()
+	if _, ok := m.active[b.ParentID]; ok {
+		m.mu.Unlock()
+		return domain.ErrBreakoutActive
+	}
+	b.CreatedAt = time.Now()
+	stored := *b
+	stored.Rooms = make([]domain.BreakoutRoom, len(b.Rooms))
+	m.assigned[b.ParentID] = make(map[int64]string)
+	for i := range b.Rooms {
+		b.Rooms[i].ID = uuid.NewString()
+		stored.Rooms[i] = domain.BreakoutRoom{ID: b.Rooms[i].ID, Name: b.Rooms[i].Name}
+		m.parents[b.Rooms[i].ID] = b.ParentID
+		for _, uid := range b.Rooms[i].Users {
+			m.assigned[b.ParentID][uid] = b.Rooms[i].ID
+		}
+	}
+	m.active[b.ParentID] = stored
+	m.mu.Unlock()
+
+	for _, r := range b.Rooms {
+		for _, uid := range r.Users {
+			m.parts.move(uid, []string{b.ParentID}, r.ID)
+		}
+	}
+	return nil
+}
+
+func (m *memBreakouts) Get(_ context.Context, parentID string) (*domain.Breakout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	b, ok := m.active[parentID]
+	if !ok {
+		return nil, domain.ErrBreakoutNotFound
+	}
+	b.Rooms = slices.Clone(b.Rooms)
+	for i := range b.Rooms {
+		for uid, roomID := range m.assigned[parentID] {
+			if roomID == b.Rooms[i].ID {
+				b.Rooms[i].Users = append(b.Rooms[i].Users, uid)
+			}
+		}
+		slices.Sort(b.Rooms[i].Users)
+	}
+	return &b, nil
+}
+
+func (m *memBreakouts) ParentOf(_ context.Context, roomID string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.parents[roomID], nil
+}
+
+func (m *memBreakouts) ListActive(context.Context) ([]domain.Breakout, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]domain.Breakout, 0, len(m.active))
+	for _, b := range m.active {
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (m *memBreakouts) Assigned(_ context.Context, parentID string, userID int64) (string, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	roomID, ok := m.assigned[parentID][userID]
+	return roomID, ok, nil
+}
+
+func (m *memBreakouts) Assign(ctx context.Context, parentID string, userID int64, roomID string) error {
+	b, err := m.Get(ctx, parentID)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	if roomID == parentID {
+		delete(m.assigned[parentID], userID)
+	} else {
+		m.assigned[parentID][userID] = roomID
+	}
+	m.mu.Unlock()
+	m.parts.move(userID, append([]string{parentID}, b.Children()...), roomID)
+	return nil
+}
+
+func (m *memBreakouts) Close(ctx context.Context, parentID string) ([]string, bool, error) {
+	b, err := m.Get(ctx, parentID)
+	if errors.Is(err, domain.ErrBreakoutNotFound) {
+		return nil, false, nil
+	}
+	m.mu.Lock()
+	delete(m.active, parentID)
+	delete(m.assigned, parentID)
+	for _, id := range b.Children() {
+		delete(m.parents, id)
+	}
+	m.mu.Unlock()
+
+	for _, r := range b.Rooms {
+		for _, uid := range m.parts.in(r.ID) {
+			m.parts.move(uid, []string{r.ID}, parentID)
+		}
+	}
+	return b.Children(), true, nil
+}
+
+// memPlans — тарифы пользователей (service.PlanStore).
+type memPlans map[int64]string
+
+func (m memPlans) Get(_ context.Context, userID int64) (string, bool, error) {
+	plan, ok := m[userID]
+	return plan, ok, nil
+}
+
+// memWebhooks — подписки, события и доставки (service.WebhookStore), раскладка как в FanOut:
+// owner_id события nil — всем подписчикам типа, иначе только подпискам владельца.
+type memWebhooks struct {
+	service.WebhookStore
+
+	mu         sync.Mutex
+	subs       []domain.WebhookSubscription
+	events     []webhookEvent
+	deliveries []domain.WebhookDelivery
+}
+
+type webhookEvent struct {
+	id, typ    string
+	ownerID    *int64
+	payload    []byte
+	createdAt  time.Time
+	dispatched bool
+}
+
+func (m *memWebhooks) CreateSubscription(_ context.Context, s *domain.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s.ID, s.Active, s.CreatedAt = uuid.NewString(), true, time.Now()
+	m.subs = append(m.subs, *s)
+	return nil
+}
+
+func (m *memWebhooks) CountSubscriptions(_ context.Context, ownerID int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, s := range m.subs {
+		if s.OwnerID == ownerID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (m *memWebhooks) AddEvent(_ context.Context, id, typ string, ownerID *int64, payload []byte, createdAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if slices.ContainsFunc(m.events, func(e webhookEvent) bool { return e.id == id }) {
+		return nil
+	}
+	m.events = append(m.events, webhookEvent{id: id, typ: typ, ownerID: ownerID, payload: payload, createdAt: createdAt})
+	return nil
+}
+
+func (m *memWebhooks) FanOut(_ context.Context, limit int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var n int64
+	for i := range m.events {
+		e := &m.events[i]
+		if e.dispatched || n == int64(limit) {
+			continue
+		}
+		for _, s := range m.subs {
+			if s.Active && slices.Contains(s.Events, e.typ) && (e.ownerID == nil || *e.ownerID == s.OwnerID) {
+				m.deliveries = append(m.deliveries, domain.WebhookDelivery{
+					ID: uuid.NewString(), SubscriptionID: s.ID, EventID: e.id, EventType: e.typ,
+					Status: domain.DeliveryPending, CreatedAt: time.Now(),
+					Payload: e.payload, EventCreatedAt: e.createdAt, URL: s.URL, Secret: s.Secret,
+				})
+			}
+		}
+		e.dispatched = true
+		n++
+	}
+	return n, nil
+}
+
+func (m *memWebhooks) ClaimDue(_ context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	var out []domain.WebhookDelivery
+	for i := range m.deliveries {
+		d := &m.deliveries[i]
+		if len(out) == limit || d.Status != domain.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		out = append(out, *d)
+	}
+	return out, nil
+}
+
+func (m *memWebhooks) MarkDelivered(_ context.Context, id string, code int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.deliveries {
+		if d := &m.deliveries[i]; d.ID == id {
+			d.Status, d.Attempts, d.LastStatus = domain.DeliveryDelivered, d.Attempts+1, &code
+		}
+	}
+	return nil
+}
+
+func (m *memWebhooks) MarkFailed(_ context.Context, id string, code *int, msg string, next time.Time, dead bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := range m.deliveries {
+		if d := &m.deliveries[i]; d.ID == id {
+			d.Attempts, d.LastStatus, d.LastError, d.NextAttemptAt = d.Attempts+1, code, &msg, next
+			if dead {
+				d.Status = domain.DeliveryDead
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+
+	"github.com/gorilla/websocket"
+)
+
+const handsRoomID = "3c9e1f7a-5b2d-4e8c-a1f0-6d7e8f9a0b1c"
+
+// в комнате handsRoomID модератор — 10
+func newHandQueue() (*service.HandQueueService, *memHands) {
+	store := newMemHands()
+	return service.NewHandQueueService(store, memModerators{handsRoomID: {10}}), store
+}
+
+func TestHandQueue_ModeratorLeavingKeepsSpeaker(t *testing.T) {
+	svc, store := newHandQueue()
+	ctx := context.Background()
+
+	if _, err := svc.Raise(ctx, handsRoomID, 1); err != nil {
+		t.Fatal(err)
+	}
+	f, err := svc.Next(ctx, 10, handsRoomID, 0)
+	if err != nil || f.UserID != 1 {
+		t.Fatalf("next: %+v %v", f, err)
+	}
+
+	// модератор ушёл — выступление участника продолжается
+	if released, err := svc.ReleaseOwn(ctx, handsRoomID, 10); err != nil || released {
+		t.Fatalf("moderator leave released the floor: %v %v", released, err)
+	}
+	if f, ok := store.floor(handsRoomID); !ok || f.UserID != 1 {
+		t.Fatalf("floor after moderator leave = %+v %v", f, ok)
+	}
+
+	// ушёл сам выступающий — трибуна свободна
+	if released, err := svc.ReleaseOwn(ctx, handsRoomID, 1); err != nil || !released {
+		t.Fatalf("speaker leave: %v %v", released, err)
+	}
+	if _, ok := store.floor(handsRoomID); ok {
+		t.Fatal("floor is still taken")
+	}
+}
+
+func TestHandQueue_ReleaseRights(t *testing.T) {
+	svc, _ := newHandQueue()
+	ctx := context.Background()
+
+	if _, err := svc.Grant(ctx, 1, handsRoomID, 2, 0); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("grant by participant: err = %v", err)
+	}
+	if _, err := svc.Next(ctx, 10, handsRoomID, 0); !errors.Is(err, domain.ErrHandQueueEmpty) {
+		t.Fatalf("next on empty queue: err = %v", err)
+	}
+	if _, err := svc.Grant(ctx, 10, handsRoomID, 2, 2*time.Hour); !errors.Is(err, domain.ErrInvalidDuration) {
+		t.Fatalf("too long: err = %v", err)
+	}
+	if _, err := svc.Grant(ctx, 10, handsRoomID, 2, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// чужое выступление участник не снимает, модератор — снимает
+	if _, err := svc.Release(ctx, 3, handsRoomID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("release by other participant: err = %v", err)
+	}
+	if ok, err := svc.Release(ctx, 10, handsRoomID); err != nil || !ok {
+		t.Fatalf("release by moderator: %v %v", ok, err)
+	}
+}
+
+func TestHandQueue_SpeakingTimerExpires(t *testing.T) {
+	svc, store := newHandQueue()
+	ctx := context.Background()
+	changed := make(chan string, 4)
+	svc.SetOnChange(func(roomID string) { changed <- roomID })
+
+	if _, err := svc.Grant(ctx, 10, handsRoomID, 1, 100*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	// слово передали до истечения — старый таймер второго не снимает
+	time.Sleep(20 * time.Millisecond)
+	if _, err := svc.Grant(ctx, 10, handsRoomID, 2, 300*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	if f, ok := store.floor(handsRoomID); !ok || f.UserID != 2 {
+		t.Fatalf("floor after stale timer = %+v %v", f, ok)
+	}
+
+	select {
+	case roomID := <-changed:
+		if roomID != handsRoomID {
+			t.Fatalf("onChange(%s)", roomID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("speaking timer did not fire")
+	}
+	if _, ok := store.floor(handsRoomID); ok {
+		t.Fatal("floor is still taken after timer")
+	}
+}
+
+func TestWS_ModeratorDisconnectKeepsFloor(t *testing.T) {
+	svc, store := newHandQueue()
+	env := newWSEnv(t, wsDeps{hands: svc})
+
+	mod := env.dial(t, handsRoomID, 10)
+	mod.expect(ws.TypeState, nil)
+	speaker := env.dial(t, handsRoomID, 1)
+	speaker.expect(ws.TypeState, nil)
+
+	speaker.send(ws.TypeHandRaise, nil)
+	mod.expect(ws.TypeHandQueue, nil)
+	mod.send(ws.TypeFloorNext, ws.HandCommandPayload{})
+	var hq ws.HandQueuePayload
+	speaker.expect(ws.TypeHandQueue, &hq)
+	for hq.Floor == nil {
+		speaker.expect(ws.TypeHandQueue, &hq)
+	}
+
+	mod.close()
+	speaker.expect(ws.TypePeerLeft, nil)
+	if f, ok := store.floor(handsRoomID); !ok || f.UserID != 1 {
+		t.Fatalf("moderator leaving ended the speech: %+v %v", f, ok)
+	}
+}
+
+// пользователь WS — только из access_token: user_id в query не делает участника модератором
+func TestWS_UserFromAccessTokenOnly(t *testing.T) {
+	svc, store := newHandQueue()
+	env := newWSEnv(t, wsDeps{hands: svc})
+
+	for name, query := range map[string]string{
+		"no token":    "user_id=10",
+		"unverified":  "access_token=test&user_id=10",
+		"another key": "access_token=" + forgedToken(t, 10),
+	} {
+		conn, resp, err := websocket.DefaultDialer.Dial(env.base+"/ws/rooms/"+handsRoomID+"?"+query, nil)
+		if err == nil {
+			_ = conn.Close()
+			t.Fatalf("%s: handshake succeeded", name)
+		}
+		if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("%s: want 401, got %v", name, resp)
+		}
+	}
+
+	speaker := env.dial(t, handsRoomID, 2)
+	speaker.expect(ws.TypeState, nil)
+	speaker.send(ws.TypeHandRaise, nil)
+	speaker.expect(ws.TypeHandQueue, nil)
+
+	// участник 1 выдает себя за модератора 10
+	forged := env.dialQuery(t, handsRoomID, "access_token="+accessToken(t, 1)+"&user_id=10")
+	forged.expect(ws.TypeState, nil)
+	forged.send(ws.TypeFloorNext, ws.HandCommandPayload{})
+	var e ws.ErrorPayload
+	forged.expect(ws.TypeError, &e)
+	if _, ok := store.floor(handsRoomID); ok {
+		t.Fatalf("floor granted by a forged moderator (error %+v)", e)
+	}
+}
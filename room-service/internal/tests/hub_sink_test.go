@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+func TestHubSink_MembershipEvents(t *testing.T) {
+	hub := ws.NewHub()
+	sub := hub.Subscribe("r-1", 8)
+	defer sub.Close()
+	sink := ws.NewHubSink(hub)
+
+	for _, e := range []events.Event{
+		events.RoomCreated{RoomID: "r-1", Name: "demo"}, // не для хаба
+		events.ParticipantJoined{RoomID: "r-1", UserID: 7},
+		events.ParticipantLeft{RoomID: "r-1", UserID: 7},
+		events.ParticipantJoined{RoomID: "r-2", UserID: 8}, // другая комната
+	} {
+		env, err := events.New("room-service", e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Publish(context.Background(), env); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []string{ws.TypeMemberJoined, ws.TypeMemberLeft} {
+		select {
+		case msg := <-sub.C:
+			p, ok := msg.Payload.(ws.PeerEventPayload)
+			if msg.Type != want || !ok || p.RoomID != "r-1" || p.UserID != "7" {
+				t.Fatalf("got %+v, want %s for user 7", msg, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("no %s", want)
+		}
+	}
+	select {
+	case msg := <-sub.C:
+		t.Fatalf("unexpected %+v", msg)
+	default:
+	}
+}
+
+type fakeConn struct {
+	roomID, userID string
+	closed         chan struct{}
+}
+
+func (c *fakeConn) Send(ws.Message) error { return nil }
+func (c *fakeConn) Close() error          { close(c.closed); return nil }
+func (c *fakeConn) UserID() string        { return c.userID }
+func (c *fakeConn) RoomID() string        { return c.roomID }
+
+func TestHubSink_UserDeleted(t *testing.T) {
+	hub := ws.NewHub()
+	sub := hub.Subscribe("r-1", 8)
+	defer sub.Close()
+	gone := &fakeConn{roomID: "r-1", userID: "7", closed: make(chan struct{})}
+	other := &fakeConn{roomID: "r-1", userID: "8", closed: make(chan struct{})}
+	hub.Add(gone)
+	hub.Add(other)
+
+	env, err := events.New("auth-service", events.UserDeleted{UserID: 7, DeletedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ws.NewHubSink(hub).Publish(context.Background(), env); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-gone.closed:
+	default:
+		t.Fatal("connection of deleted user is still open")
+	}
+	select {
+	case <-other.closed:
+		t.Fatal("foreign connection closed")
+	default:
+	}
+	select {
+	case msg := <-sub.C:
+		p, ok := msg.Payload.(ws.PeerEventPayload)
+		if msg.Type != ws.TypeMemberLeft || !ok || p.RoomID != "r-1" || p.UserID != "7" {
+			t.Fatalf("got %+v, want member_left for user 7", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no member_left")
+	}
+}
@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+)
+
+const lobbyRoomID = "7b6a5c4d-3e2f-4a1b-9c8d-0e1f2a3b4c5d"
+
+type lobbyChange struct {
+	admitted, removed []int64
+}
+
+// в комнате lobbyRoomID модератор — 10, мест — max
+func newLobby(max int) (*service.LobbyService, *memLobby, chan lobbyChange) {
+	store := newMemLobby(max)
+	svc := service.NewLobbyService(store, memModerators{lobbyRoomID: {10}}, store)
+	changes := make(chan lobbyChange, 16)
+	svc.SetOnChange(func(roomID string, admitted, removed []int64) {
+		if roomID == lobbyRoomID {
+			changes <- lobbyChange{admitted, removed}
+		}
+	})
+	return svc, store, changes
+}
+
+func lastChange(t *testing.T, ch chan lobbyChange) lobbyChange {
+	t.Helper()
+	var c lobbyChange
+	select {
+	case c = <-ch:
+	default:
+		t.Fatal("no lobby change")
+	}
+	for len(ch) > 0 {
+		c = <-ch
+	}
+	return c
+}
+
+func TestLobby_EnterQueuesUntilStart(t *testing.T) {
+	svc, store, changes := newLobby(10)
+	ctx := context.Background()
+
+	// лобби выключено — входят сразу
+	if waiting, _, err := svc.Enter(ctx, lobbyRoomID, 1); err != nil || waiting {
+		t.Fatalf("disabled lobby: %v %v", waiting, err)
+	}
+	if err := svc.SetEnabled(ctx, 1, lobbyRoomID, true); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("enable by participant: err = %v", err)
+	}
+	if err := svc.SetEnabled(ctx, 10, lobbyRoomID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, uid := range []int64{1, 2, 3} {
+		waiting, pos, err := svc.Enter(ctx, lobbyRoomID, uid)
+		if err != nil || !waiting || pos != i+1 {
+			t.Fatalf("enter %d: %v %d %v", uid, waiting, pos, err)
+		}
+	}
+	// повторный вход сохраняет место и не шумит
+	<-changes
+	<-changes
+	<-changes
+	if _, pos, _ := svc.Enter(ctx, lobbyRoomID, 1); pos != 1 || len(changes) != 0 {
+		t.Fatalf("re-enter: pos %d, %d changes", pos, len(changes))
+	}
+	// модератор и уже вошедший проходят мимо очереди
+	if waiting, _, _ := svc.Enter(ctx, lobbyRoomID, 10); waiting {
+		t.Fatal("moderator waits in lobby")
+	}
+	store.members[lobbyRoomID] = []int64{7}
+	if waiting, _, _ := svc.Enter(ctx, lobbyRoomID, 7); waiting {
+		t.Fatal("participant waits in lobby")
+	}
+
+	if _, err := svc.Start(ctx, 1, lobbyRoomID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("start by participant: err = %v", err)
+	}
+	admitted, err := svc.Start(ctx, 10, lobbyRoomID)
+	if err != nil || !slices.Equal(admitted, []int64{1, 2, 3}) {
+		t.Fatalf("start: %v %v", admitted, err)
+	}
+	if c := lastChange(t, changes); !slices.Equal(c.admitted, admitted) {
+		t.Fatalf("change = %+v", c)
+	}
+	// занятие идёт — лобби не действует
+	if waiting, _, _ := svc.Enter(ctx, lobbyRoomID, 4); waiting {
+		t.Fatal("lobby still active after start")
+	}
+
+	// сброс: следующее занятие снова через лобби, очередь распускается
+	if _, _, err := svc.Enter(ctx, lobbyRoomID, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ResetSession(ctx, lobbyRoomID); err != nil {
+		t.Fatal(err)
+	}
+	if waiting, _, _ := svc.Enter(ctx, lobbyRoomID, 5); !waiting {
+		t.Fatal("lobby inactive after reset")
+	}
+	if err := svc.ResetSession(ctx, lobbyRoomID); err != nil {
+		t.Fatal(err)
+	}
+	if c := lastChange(t, changes); !slices.Equal(c.removed, []int64{5}) {
+		t.Fatalf("reset change = %+v", c)
+	}
+}
+
+func TestLobby_AdmitDenyAndCapacity(t *testing.T) {
+	svc, store, changes := newLobby(2)
+	ctx := context.Background()
+	if err := svc.SetEnabled(ctx, 10, lobbyRoomID, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, uid := range []int64{1, 2, 3, 4} {
+		if _, _, err := svc.Enter(ctx, lobbyRoomID, uid); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := svc.Admit(ctx, 10, lobbyRoomID, 9); !errors.Is(err, domain.ErrNotInLobby) {
+		t.Fatalf("admit stranger: err = %v", err)
+	}
+	if err := svc.Admit(ctx, 10, lobbyRoomID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if pos, _, _ := svc.Position(ctx, lobbyRoomID, 4); pos != 3 {
+		t.Fatalf("position after admit = %d", pos)
+	}
+
+	if err := svc.Deny(ctx, 10, lobbyRoomID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if c := lastChange(t, changes); !slices.Equal(c.removed, []int64{1}) {
+		t.Fatalf("deny change = %+v", c)
+	}
+	if err := svc.Deny(ctx, 10, lobbyRoomID, 1); !errors.Is(err, domain.ErrNotInLobby) {
+		t.Fatalf("second deny: err = %v", err)
+	}
+
+	// мест осталось одно: выключение лобби пускает первого, второй ждёт
+	if err := svc.SetEnabled(ctx, 10, lobbyRoomID, false); err != nil {
+		t.Fatal(err)
+	}
+	if c := lastChange(t, changes); !slices.Equal(c.admitted, []int64{2}) {
+		t.Fatalf("disable change = %+v", c)
+	}
+	if err := svc.Admit(ctx, 10, lobbyRoomID, 4); !errors.Is(err, domain.ErrRoomFull) {
+		t.Fatalf("admit into full room: err = %v", err)
+	}
+	if q, _ := svc.Queue(ctx, lobbyRoomID); len(q) != 1 || q[0].UserID != 4 {
+		t.Fatalf("queue = %+v", q)
+	}
+
+	// сам ушёл из лобби
+	if left, err := svc.Leave(ctx, lobbyRoomID, 4); err != nil || !left {
+		t.Fatalf("leave: %v %v", left, err)
+	}
+	if left, _ := svc.Leave(ctx, lobbyRoomID, 4); left {
+		t.Fatal("second leave reported removal")
+	}
+	if !slices.Equal(store.members[lobbyRoomID], []int64{3, 2}) {
+		t.Fatalf("members = %v", store.members[lobbyRoomID])
+	}
+}
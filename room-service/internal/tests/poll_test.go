@@ -0,0 +1,177 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+)
+
+const pollRoomID = "9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d"
+
+// в комнате pollRoomID модератор — 10
+func newPolls() (*service.PollService, *memPolls) {
+	store := newMemPolls()
+	return service.NewPollService(store, memModerators{pollRoomID: {10}}), store
+}
+
+func options(texts ...string) []domain.PollOption {
+	out := make([]domain.PollOption, 0, len(texts))
+	for _, t := range texts {
+		out = append(out, domain.PollOption{Text: t})
+	}
+	return out
+}
+
+func TestPoll_CreateValidation(t *testing.T) {
+	svc, _ := newPolls()
+	ctx := context.Background()
+
+	quizTwoCorrect := options("2", "3", "4")
+	quizTwoCorrect[0].Correct, quizTwoCorrect[2].Correct = true, true
+
+	cases := []struct {
+		name  string
+		actor int64
+		in    domain.PollInput
+		want  error
+	}{
+		{"participant", 1, domain.PollInput{Question: "?", Options: options("a", "b")}, domain.ErrForbidden},
+		{"one option", 10, domain.PollInput{Question: "?", Options: options("a")}, domain.ErrPollInvalid},
+		{"blank question", 10, domain.PollInput{Question: "  ", Options: options("a", "b")}, domain.ErrPollInvalid},
+		{"blank option", 10, domain.PollInput{Question: "?", Options: options("a", " ")}, domain.ErrPollInvalid},
+		{"quiz without answer", 10, domain.PollInput{Question: "?", Quiz: true, Options: options("a", "b")}, domain.ErrPollInvalid},
+		{"single quiz, two answers", 10, domain.PollInput{Question: "2+2?", Quiz: true, Options: quizTwoCorrect}, domain.ErrPollInvalid},
+		{"too long", 10, domain.PollInput{Question: "?", Options: options("a", "b"), Duration: 25 * time.Hour}, domain.ErrInvalidDuration},
+		{"bad results mode", 10, domain.PollInput{Question: "?", Options: options("a", "b"), ResultsMode: "never"}, domain.ErrPollInvalid},
+	}
+	for _, c := range cases {
+		if _, err := svc.Create(ctx, c.actor, pollRoomID, c.in); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	// multi-квиз с двумя правильными — можно
+	in := domain.PollInput{Question: "чётные?", Quiz: true, Multi: true, Options: quizTwoCorrect}
+	p, err := svc.Create(ctx, 10, pollRoomID, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ResultsMode != domain.PollResultsLive || !p.Correct([]int{0, 2}) || p.Correct([]int{0}) {
+		t.Fatalf("poll = %+v", p)
+	}
+}
+
+func TestPoll_Vote(t *testing.T) {
+	svc, _ := newPolls()
+	ctx := context.Background()
+
+	p, err := svc.Create(ctx, 10, pollRoomID, domain.PollInput{Question: "когда созвон?", Options: options("пн", "вт", "ср")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, opts := range map[string][]int{"none": nil, "two in single": {0, 1}, "out of range": {3}, "negative": {-1}} {
+		if _, err := svc.Vote(ctx, pollRoomID, 1, p.ID, opts); !errors.Is(err, domain.ErrVoteInvalid) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+	// повтор одного варианта схлопывается
+	if _, err := svc.Vote(ctx, pollRoomID, 1, p.ID, []int{1, 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Vote(ctx, pollRoomID, 1, p.ID, []int{2}); !errors.Is(err, domain.ErrAlreadyVoted) {
+		t.Fatalf("second vote: err = %v", err)
+	}
+	if _, err := svc.Vote(ctx, "other-room", 2, p.ID, []int{0}); !errors.Is(err, domain.ErrPollNotFound) {
+		t.Fatalf("other room: err = %v", err)
+	}
+	if mine, _ := svc.UserVote(ctx, p.ID, 1); len(mine) != 1 || mine[0] != 1 {
+		t.Fatalf("user vote = %v", mine)
+	}
+
+	if _, _, err := svc.Close(ctx, 1, pollRoomID, p.ID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("close by participant: err = %v", err)
+	}
+	if _, closed, err := svc.Close(ctx, 10, pollRoomID, p.ID); err != nil || !closed {
+		t.Fatalf("close: %v %v", closed, err)
+	}
+	if _, closed, _ := svc.Close(ctx, 10, pollRoomID, p.ID); closed {
+		t.Fatal("second close must report closed=false")
+	}
+	if _, err := svc.Vote(ctx, pollRoomID, 2, p.ID, []int{0}); !errors.Is(err, domain.ErrPollClosed) {
+		t.Fatalf("vote after close: err = %v", err)
+	}
+
+	exp, err := svc.Export(ctx, 10, pollRoomID)
+	if err != nil || len(exp) != 1 || exp[0].Results.Voters != 1 || exp[0].Results.Counts[1] != 1 || len(exp[0].Votes) != 1 {
+		t.Fatalf("export = %+v %v", exp, err)
+	}
+	if _, err := svc.Export(ctx, 1, pollRoomID); !errors.Is(err, domain.ErrForbidden) {
+		t.Fatalf("export by participant: err = %v", err)
+	}
+}
+
+func TestPoll_ClosesOnTimer(t *testing.T) {
+	svc, _ := newPolls()
+	closed := make(chan *domain.Poll, 1)
+	svc.SetOnClose(func(p *domain.Poll) { closed <- p })
+
+	p, err := svc.Create(context.Background(), 10, pollRoomID, domain.PollInput{
+		Question: "?", Options: options("a", "b"), Duration: 100 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-closed:
+		if got.ID != p.ID || got.Status != domain.PollClosed {
+			t.Fatalf("closed poll = %+v", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("poll was not closed by timer")
+	}
+}
+
+func TestWS_QuizRevealsAnswersOnClose(t *testing.T) {
+	svc, _ := newPolls()
+	env := newWSEnv(t, wsDeps{polls: svc})
+
+	mod := env.dial(t, pollRoomID, 10)
+	mod.expect(ws.TypeState, nil)
+	user := env.dial(t, pollRoomID, 1)
+	user.expect(ws.TypeState, nil)
+
+	mod.send(ws.TypePollCreate, ws.PollCreatePayload{
+		Question: "2+2?",
+		Quiz:     true,
+		Options:  []ws.PollOptionPayload{{Text: "3"}, {Text: "4", Correct: ptr(true)}},
+	})
+	var created ws.PollPayload
+	user.expect(ws.TypePollCreated, &created)
+	for _, o := range created.Options {
+		if o.Correct != nil {
+			t.Fatalf("answer leaked before close: %+v", created.Options)
+		}
+	}
+
+	// results=live: после голоса всем уходят текущие цифры
+	user.send(ws.TypePollVote, ws.PollVotePayload{PollID: created.ID, Options: []int{1}})
+	user.expect(ws.TypePollVoted, nil)
+	var res ws.PollResultsPayload
+	mod.expect(ws.TypePollResults, &res)
+	if res.Voters != 1 || res.Counts[1] != 1 {
+		t.Fatalf("results = %+v", res)
+	}
+
+	mod.send(ws.TypePollClose, ws.PollClosePayload{PollID: created.ID})
+	var closed ws.PollPayload
+	user.expect(ws.TypePollClosed, &closed)
+	if closed.Status != string(domain.PollClosed) || closed.Options[1].Correct == nil || !*closed.Options[1].Correct || closed.ResultsData == nil {
+		t.Fatalf("closed = %+v", closed)
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+)
+
+const presenceRoomID = "5e1d9c3b-7a2f-4b6e-9c8d-2f3e4a5b6c7d"
+
+func ptr[T any](v T) *T { return &v }
+
+func TestPresence_ConnectRestoresSnapshot(t *testing.T) {
+	store := newMemPresence()
+	store.snaps[roomUser{presenceRoomID, 1}] = domain.Presence{
+		RoomID: presenceRoomID, UserID: 1, Status: domain.PresenceBusy,
+		Mic: true, StatusText: "решаю 3-ю", HandRaisedAt: ptr(time.Now().Add(-time.Minute)),
+	}
+	svc := service.NewPresenceService(store)
+	ctx := context.Background()
+
+	// статус, текст и рука переживают переподключение, медиа — нет
+	p, d, err := svc.Connect(ctx, presenceRoomID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Status != domain.PresenceBusy || p.StatusText != "решаю 3-ю" || p.HandRaisedAt == nil || p.Mic {
+		t.Fatalf("presence = %+v", p)
+	}
+	if d.Mic == nil || *d.Mic || d.Status != nil {
+		t.Fatalf("delta = %+v, want only mic=false", d)
+	}
+
+	// новичок без снапшота — online
+	p, d, err = svc.Connect(ctx, presenceRoomID, 2)
+	if err != nil || p.Status != domain.PresenceOnline || d.Status == nil {
+		t.Fatalf("newcomer: %+v %+v %v", p, d, err)
+	}
+}
+
+func TestPresence_LastConnectionGoesOffline(t *testing.T) {
+	store := newMemPresence()
+	svc := service.NewPresenceService(store)
+	ctx := context.Background()
+
+	if _, _, err := svc.Connect(ctx, presenceRoomID, 1); err != nil {
+		t.Fatal(err)
+	}
+	// вторая вкладка: дельты нет
+	if _, d, _ := svc.Connect(ctx, presenceRoomID, 1); !d.Empty() {
+		t.Fatalf("second connection delta = %+v", d)
+	}
+	if _, _, err := svc.Update(presenceRoomID, 1, domain.PresenceUpdate{Cam: ptr(true)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, last := svc.Disconnect(ctx, presenceRoomID, 1); last {
+		t.Fatal("first disconnect must not be the last")
+	}
+	d, last := svc.Disconnect(ctx, presenceRoomID, 1)
+	if !last || d.Status == nil || *d.Status != domain.PresenceOffline || d.Cam == nil || *d.Cam {
+		t.Fatalf("last disconnect: %+v %v", d, last)
+	}
+	if got := store.get(presenceRoomID, 1); got.Status != domain.PresenceOffline || got.Cam {
+		t.Fatalf("snapshot on disconnect = %+v", got)
+	}
+	if _, ok := svc.Live(presenceRoomID, 1); ok {
+		t.Fatal("disconnected user is still live")
+	}
+}
+
+func TestPresence_UpdateValidationAndDeltas(t *testing.T) {
+	svc := service.NewPresenceService(newMemPresence())
+	ctx := context.Background()
+
+	if _, _, err := svc.Update(presenceRoomID, 1, domain.PresenceUpdate{Mic: ptr(true)}); !errors.Is(err, domain.ErrNotInRoom) {
+		t.Fatalf("not connected: err = %v", err)
+	}
+	if _, _, err := svc.Connect(ctx, presenceRoomID, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, upd := range map[string]domain.PresenceUpdate{
+		"offline": {Status: ptr(domain.PresenceOffline)},
+		"unknown": {Status: ptr(domain.PresenceStatus("sleeping"))},
+		"long":    {StatusText: ptr(strings.Repeat("я", domain.MaxStatusTextLen+1))},
+	} {
+		if _, _, err := svc.Update(presenceRoomID, 1, upd); !errors.Is(err, domain.ErrPresenceInvalid) {
+			t.Errorf("%s: err = %v", name, err)
+		}
+	}
+
+	p, d, err := svc.Update(presenceRoomID, 1, domain.PresenceUpdate{HandRaised: ptr(true), StatusText: ptr("  вопрос  ")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.HandRaisedAt == nil || d.HandRaisedAt == nil || p.StatusText != "вопрос" || d.Mic != nil {
+		t.Fatalf("raise: %+v %+v", p, d)
+	}
+	// повторный подъём руки не сдвигает время и ничего не рассылает
+	p2, d, _ := svc.Update(presenceRoomID, 1, domain.PresenceUpdate{HandRaised: ptr(true)})
+	if !d.Empty() || !p2.HandRaisedAt.Equal(*p.HandRaisedAt) {
+		t.Fatalf("repeated raise: %+v %+v", p2, d)
+	}
+}
+
+func TestPresence_FlushRetriesFailedBatch(t *testing.T) {
+	store := newMemPresence()
+	svc := service.NewPresenceService(store)
+	ctx := context.Background()
+
+	if _, _, err := svc.Connect(ctx, presenceRoomID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.Update(presenceRoomID, 1, domain.PresenceUpdate{Status: ptr(domain.PresenceAway)}); err != nil {
+		t.Fatal(err)
+	}
+
+	store.fail = errors.New("db down")
+	if err := svc.Flush(ctx); err == nil {
+		t.Fatal("flush must report the failure")
+	}
+	if err := svc.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := store.get(presenceRoomID, 1); got.Status != domain.PresenceAway {
+		t.Fatalf("snapshot = %+v", got)
+	}
+	// записывать больше нечего
+	saves := store.saves
+	if err := svc.Flush(ctx); err != nil || store.saves != saves {
+		t.Fatalf("empty flush wrote again: %v", err)
+	}
+}
+
+func TestWS_PresenceDeltas(t *testing.T) {
+	env := newWSEnv(t, wsDeps{presence: service.NewPresenceService(newMemPresence())})
+	a := env.dial(t, presenceRoomID, 1)
+	a.expect(ws.TypeState, nil)
+	b := env.dial(t, presenceRoomID, 2)
+	b.expect(ws.TypeState, nil)
+
+	a.send(ws.TypePresenceUpdate, ws.PresenceUpdatePayload{Mic: ptr(true), StatusText: ptr("слушаю")})
+	d := presenceOf(b, "1")
+	if d.UserID != "1" || d.Mic == nil || !*d.Mic || d.StatusText == nil || d.Cam != nil || d.Status != nil {
+		t.Fatalf("delta = %+v, want only mic and status_text", d)
+	}
+
+	a.send(ws.TypePresenceUpdate, ws.PresenceUpdatePayload{Status: ptr("offline")})
+	var e ws.ErrorPayload
+	a.expect(ws.TypeError, &e)
+	if e.Code != "presence_rejected" {
+		t.Fatalf("error = %+v", e)
+	}
+
+	// ушёл последней вкладкой — остальным offline
+	a.close()
+	d = presenceOf(b, "1")
+	if d.Status == nil || *d.Status != string(domain.PresenceOffline) {
+		t.Fatalf("leave delta = %+v", d)
+	}
+}
+
+// presenceOf — следующая дельта presence пользователя userID (остальные пропускаем).
+func presenceOf(c *wsClient, userID string) ws.PresenceUpdatePayload {
+	c.t.Helper()
+	for {
+		var d ws.PresenceUpdatePayload
+		c.expect(ws.TypePresenceUpdate, &d)
+		if d.UserID == userID {
+			return d
+		}
+	}
+}
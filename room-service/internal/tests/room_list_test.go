@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/postgres"
+	"github.com/cwrk-planet/room-service/internal/service"
+)
+
+// listRoom — комната верхнего уровня с id вида 00000000-0000-4000-8000-0000000000NN.
+func listRoom(n int, name string, created time.Time) domain.Room {
+	return domain.Room{
+		ID:              fmt.Sprintf("00000000-0000-4000-8000-%012d", n),
+		Name:            name,
+		MaxParticipants: 10,
+		CreatedAt:       created,
+		ActivityAt:      created,
+		Tags:            []string{},
+	}
+}
+
+func roomIDs(rooms []domain.Room) []string {
+	out := make([]string, 0, len(rooms))
+	for _, r := range rooms {
+		out = append(out, r.ID)
+	}
+	return out
+}
+
+// pageAll — листаем по limit до пустого курсора.
+func pageAll(t *testing.T, svc *service.RoomService, f domain.RoomFilter) []string {
+	t.Helper()
+	var ids []string
+	for i := 0; ; i++ {
+		if i > 20 {
+			t.Fatal("paging does not end")
+		}
+		page, next, err := svc.ListRooms(context.Background(), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, roomIDs(page)...)
+		if next == "" {
+			return ids
+		}
+		f.Cursor = next
+	}
+}
+
+func TestRoom_ListFilters(t *testing.T) {
+	now := time.Now().Truncate(time.Microsecond)
+	algo := listRoom(1, "Алгоритмы", now.Add(-3*time.Minute))
+	algo.Tags, algo.Language, algo.Participants, algo.Online = []string{"cs", "go"}, ptr("ru"), 10, 2
+	eng := listRoom(2, "English club", now.Add(-2*time.Minute))
+	eng.Topic, eng.Language, eng.Participants = ptr("разговорный"), ptr("en"), 3
+	quiet := listRoom(3, "Пустая", now.Add(-time.Minute))
+	quiet.Tags = []string{"go"}
+	parent := algo.ID
+	child := listRoom(4, "Группа 1", now)
+	child.ParentID = &parent
+
+	svc := service.NewRoomService(newMemRooms(algo, eng, quiet, child), memPlans{}, testLimits)
+	list := func(f domain.RoomFilter) []string {
+		t.Helper()
+		rooms, _, err := svc.ListRooms(context.Background(), f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return roomIDs(rooms)
+	}
+
+	cases := []struct {
+		name string
+		f    domain.RoomFilter
+		want []domain.Room
+	}{
+		{"all, newest first, no breakouts", domain.RoomFilter{}, []domain.Room{quiet, eng, algo}},
+		{"query in name", domain.RoomFilter{Query: "  алго "}, []domain.Room{algo}},
+		{"query in topic", domain.RoomFilter{Query: "РАЗГОВОР"}, []domain.Room{eng}},
+		{"tag is lower-cased", domain.RoomFilter{Tag: " GO "}, []domain.Room{quiet, algo}},
+		{"language", domain.RoomFilter{Language: "EN"}, []domain.Room{eng}},
+		{"free seats", domain.RoomFilter{FreeSeats: true}, []domain.Room{quiet, eng}},
+		{"live now", domain.RoomFilter{LiveNow: true}, []domain.Room{algo}},
+		{"activity: online first", domain.RoomFilter{Sort: domain.RoomSortActivity}, []domain.Room{algo, quiet, eng}},
+	}
+	for _, c := range cases {
+		if got := list(c.f); !slices.Equal(got, roomIDs(c.want)) {
+			t.Errorf("%s: got %v, want %v", c.name, got, roomIDs(c.want))
+		}
+	}
+
+	if _, _, err := svc.ListRooms(context.Background(), domain.RoomFilter{Sort: "popular"}); !errors.Is(err, domain.ErrRoomInvalid) {
+		t.Fatalf("unknown sort: err = %v", err)
+	}
+}
+
+// Курсор (…, created_at, id): при одинаковом времени ничего не теряется и не повторяется.
+func TestRoom_ListPagingAcrossEqualTimestamps(t *testing.T) {
+	ts := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	var rooms []domain.Room
+	for i := 1; i <= 7; i++ {
+		created := ts
+		if i > 5 {
+			created = ts.Add(-time.Hour)
+		}
+		r := listRoom(i, fmt.Sprintf("room %d", i), created)
+		// у 2–6 одинаковая активность и онлайн, у 1 и 7 — свои
+		r.ActivityAt, r.Online = ts.Add(time.Minute), 1
+		rooms = append(rooms, r)
+	}
+	rooms[0].Online = 3
+	rooms[6].ActivityAt = ts.Add(-2 * time.Hour)
+	// у 1–5 совпадает и created_at — порядок между ними решает id
+	svc := service.NewRoomService(newMemRooms(rooms...), memPlans{}, testLimits)
+
+	byCreated := []string{rooms[4].ID, rooms[3].ID, rooms[2].ID, rooms[1].ID, rooms[0].ID, rooms[6].ID, rooms[5].ID}
+	byActivity := []string{rooms[0].ID, rooms[4].ID, rooms[3].ID, rooms[2].ID, rooms[1].ID, rooms[5].ID, rooms[6].ID}
+	for _, limit := range []int{1, 2, 3, 7} {
+		if got := pageAll(t, svc, domain.RoomFilter{Limit: limit}); !slices.Equal(got, byCreated) {
+			t.Errorf("created, limit %d: %v", limit, got)
+		}
+		if got := pageAll(t, svc, domain.RoomFilter{Limit: limit, Sort: domain.RoomSortActivity}); !slices.Equal(got, byActivity) {
+			t.Errorf("activity, limit %d: %v", limit, got)
+		}
+	}
+
+	// курсор одной сортировки в другой не принимается
+	_, next, err := svc.ListRooms(context.Background(), domain.RoomFilter{Limit: 2, Sort: domain.RoomSortActivity})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := svc.ListRooms(context.Background(), domain.RoomFilter{Limit: 2, Cursor: next}); !errors.Is(err, postgres.ErrInvalidCursor) {
+		t.Fatalf("cursor for other sort: err = %v", err)
+	}
+	if _, _, err := svc.ListRooms(context.Background(), domain.RoomFilter{Cursor: "%%%"}); !errors.Is(err, postgres.ErrInvalidCursor) {
+		t.Fatalf("garbage cursor: err = %v", err)
+	}
+}
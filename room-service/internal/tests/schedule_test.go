@@ -0,0 +1,318 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+)
+
+const (
+	schedRoomA = "a1000000-0000-4000-8000-000000000001"
+	schedRoomB = "b2000000-0000-4000-8000-000000000002"
+	schedRoomC = "c3000000-0000-4000-8000-000000000003"
+)
+
+// комнаты A, B, C; владелец всех — 10
+func newSchedule() (*service.ScheduleService, *memSchedules) {
+	owner := int64(10)
+	rooms := newMemRooms(
+		domain.Room{ID: schedRoomA, Name: "алгоритмы", OwnerID: &owner},
+		domain.Room{ID: schedRoomB, Name: "английский", OwnerID: &owner},
+		domain.Room{ID: schedRoomC, Name: "физика", OwnerID: &owner},
+	)
+	store := newMemSchedules()
+	return service.NewScheduleService(store, rooms), store
+}
+
+func mustLoc(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("no tzdata for %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestSchedule_SetValidation(t *testing.T) {
+	svc, _ := newSchedule()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 3, 18, 0, 0, 0, time.UTC)
+	base := domain.Schedule{RoomID: schedRoomA, StartsAt: start, Duration: 90 * time.Minute}
+
+	with := func(f func(*domain.Schedule)) domain.Schedule {
+		sc := base
+		f(&sc)
+		return sc
+	}
+	cases := []struct {
+		name  string
+		actor int64
+		in    domain.Schedule
+		want  error
+	}{
+		{"participant", 1, base, domain.ErrForbidden},
+		{"unknown room", 10, with(func(s *domain.Schedule) { s.RoomID = "nope" }), domain.ErrRoomNotFound},
+		{"bad tz", 10, with(func(s *domain.Schedule) { s.Timezone = "Mars/Olympus" }), domain.ErrScheduleInvalid},
+		{"minutely", 10, with(func(s *domain.Schedule) { s.RRule = "FREQ=MINUTELY" }), domain.ErrScheduleInvalid},
+		{"dtstart in rule", 10, with(func(s *domain.Schedule) { s.RRule = "DTSTART:20260303T180000Z\nRRULE:FREQ=DAILY" }), domain.ErrScheduleInvalid},
+		{"garbage rule", 10, with(func(s *domain.Schedule) { s.RRule = "FREQ=SOMETIMES" }), domain.ErrScheduleInvalid},
+		{"no start", 10, with(func(s *domain.Schedule) { s.StartsAt = time.Time{} }), domain.ErrScheduleInvalid},
+		{"too short", 10, with(func(s *domain.Schedule) { s.Duration = 30 * time.Second }), domain.ErrInvalidDuration},
+		{"open before too early", 10, with(func(s *domain.Schedule) { s.OpenBefore = 2 * time.Hour }), domain.ErrInvalidDuration},
+	}
+	for _, c := range cases {
+		if _, err := svc.Set(ctx, c.actor, c.in); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+
+	// префикс RRULE: и регистр нормализуются, пустой пояс — UTC
+	sc, err := svc.Set(ctx, 10, with(func(s *domain.Schedule) { s.RRule = " rrule:freq=weekly;byday=tu " }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.RRule != "FREQ=WEEKLY;BYDAY=TU" || sc.Timezone != "UTC" || sc.RoomName != "алгоритмы" {
+		t.Fatalf("schedule = %+v", sc)
+	}
+}
+
+// Повтор считается в поясе расписания: "вторник 19:00 по Берлину" остаётся 19:00
+// по обе стороны перехода на летнее/зимнее время, меняется только UTC.
+func TestSchedule_WeeklyAcrossDST(t *testing.T) {
+	berlin := mustLoc(t, "Europe/Berlin")
+	svc, store := newSchedule()
+	store.put(domain.Schedule{
+		RoomID: schedRoomA, RoomName: "алгоритмы",
+		StartsAt: time.Date(2026, 3, 3, 19, 0, 0, 0, berlin),
+		Duration: 90 * time.Minute, RRule: "FREQ=WEEKLY;BYDAY=TU", Timezone: "Europe/Berlin",
+	})
+	ctx := context.Background()
+
+	check := func(from, to time.Time, wantUTCHours []int) {
+		t.Helper()
+		got, err := svc.Sessions(ctx, 10, schedRoomA, from, to, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(wantUTCHours) {
+			t.Fatalf("%d sessions, want %d: %v", len(got), len(wantUTCHours), got)
+		}
+		for i, s := range got {
+			local := s.StartsAt.In(berlin)
+			if local.Weekday() != time.Tuesday || local.Hour() != 19 || s.StartsAt.UTC().Hour() != wantUTCHours[i] {
+				t.Errorf("session %d starts %v (UTC %v)", i, local, s.StartsAt.UTC())
+			}
+			if s.EndsAt.Sub(s.StartsAt) != 90*time.Minute || s.RoomName != "алгоритмы" {
+				t.Errorf("session %d = %+v", i, s)
+			}
+		}
+	}
+	// весна: 29 марта 2026 Берлин переходит на CEST (UTC+2)
+	check(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC),
+		[]int{18, 18, 18, 18, 17, 17, 17})
+	// осень: 25 октября 2026 — обратно на CET (UTC+1)
+	check(time.Date(2026, 10, 13, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC),
+		[]int{17, 17, 18, 18})
+}
+
+func TestSchedule_SessionsRangeAndRooms(t *testing.T) {
+	svc, store := newSchedule()
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: start, Duration: time.Hour, RRule: "FREQ=DAILY;COUNT=5", Timezone: "UTC"})
+	store.put(domain.Schedule{RoomID: schedRoomB, StartsAt: start.Add(30 * time.Minute), Duration: time.Hour, Timezone: "UTC"})
+	store.members[1] = []string{schedRoomA}
+	store.members[2] = []string{schedRoomA, schedRoomB}
+
+	// занятие, начавшееся до from, но ещё идущее, попадает в выборку
+	from := start.Add(30 * time.Minute)
+	got, err := svc.Sessions(ctx, 1, schedRoomA, from, from.Add(48*time.Hour), 0)
+	if err != nil || len(got) != 3 || !got[0].StartsAt.Equal(start) {
+		t.Fatalf("overlapping: %v %v", got, err)
+	}
+	// COUNT ограничивает серию
+	if got, _ := svc.Sessions(ctx, 1, schedRoomA, start, start.Add(30*24*time.Hour), 0); len(got) != 5 {
+		t.Fatalf("count: %d sessions", len(got))
+	}
+	if got, _ := svc.Sessions(ctx, 1, schedRoomA, start, start.Add(30*24*time.Hour), 2); len(got) != 2 {
+		t.Fatalf("limit: %d sessions", len(got))
+	}
+	if _, err := svc.Sessions(ctx, 1, "", start, start.Add(400*24*time.Hour), 0); !errors.Is(err, domain.ErrScheduleInvalid) {
+		t.Fatalf("range too wide: err = %v", err)
+	}
+	if got, err := svc.Sessions(ctx, 1, schedRoomC, start, start.Add(time.Hour), 0); err != nil || len(got) != 0 {
+		t.Fatalf("room without schedule: %v %v", got, err)
+	}
+
+	// без room_id — только комнаты пользователя, общий список по времени
+	day := start.Add(24 * time.Hour)
+	if got, _ := svc.Sessions(ctx, 1, "", start, day, 0); len(got) != 1 || got[0].RoomID != schedRoomA {
+		t.Fatalf("user 1 sees %v", got)
+	}
+	got, _ = svc.Sessions(ctx, 2, "", start, day, 0)
+	if len(got) != 2 || got[0].RoomID != schedRoomA || got[1].RoomID != schedRoomB {
+		t.Fatalf("user 2 sees %v", got)
+	}
+	if got, _ := svc.Sessions(ctx, 3, "", start, day, 0); len(got) != 0 {
+		t.Fatalf("stranger sees %v", got)
+	}
+}
+
+// ленты .ics открываются токеном из ссылки без bearer; удаление ленты отзывает ссылку
+func TestSchedule_CalendarFeeds(t *testing.T) {
+	svc, store := newSchedule()
+	feeds := &memFeeds{}
+	svc.SetFeeds(feeds)
+	ctx := context.Background()
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: start, Duration: time.Hour, RRule: "FREQ=DAILY;COUNT=3", Timezone: "UTC"})
+	store.put(domain.Schedule{RoomID: schedRoomB, StartsAt: start, Duration: time.Hour, Timezone: "UTC"})
+	store.members[2] = []string{schedRoomA, schedRoomB}
+	from, to := start, start.Add(7*24*time.Hour)
+
+	if _, err := svc.CreateFeed(ctx, 2, "nope"); !errors.Is(err, domain.ErrRoomNotFound) {
+		t.Fatalf("unknown room: err = %v", err)
+	}
+	room, err := svc.CreateFeed(ctx, 2, schedRoomB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := svc.CreateFeed(ctx, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.Token == "" || room.Token == all.Token || slices.Contains(feeds.hashes, room.Token) {
+		t.Fatalf("tokens must be unique and stored hashed: %+v %+v", room, all)
+	}
+	if list, _ := svc.ListFeeds(ctx, 2); len(list) != 2 || list[0].Token != "" {
+		t.Fatalf("list = %+v", list)
+	}
+
+	// пользователь ленты — из токена: все его комнаты или одна
+	if got, err := svc.FeedSessions(ctx, all.Token, from, to); err != nil || len(got) != 4 {
+		t.Fatalf("all rooms: %v %v", got, err)
+	}
+	if got, err := svc.FeedSessions(ctx, room.Token, from, to); err != nil || len(got) != 1 || got[0].RoomID != schedRoomB {
+		t.Fatalf("room feed: %v %v", got, err)
+	}
+	for _, token := range []string{"", "forged", strings.ToUpper(all.Token)} {
+		if _, err := svc.FeedSessions(ctx, token, from, to); !errors.Is(err, domain.ErrFeedNotFound) {
+			t.Errorf("token %q: err = %v", token, err)
+		}
+	}
+
+	// чужую ленту не удалить, свою — ссылка перестаёт работать
+	if err := svc.DeleteFeed(ctx, 3, all.ID); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Fatalf("delete by stranger: err = %v", err)
+	}
+	if err := svc.DeleteFeed(ctx, 2, all.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FeedSessions(ctx, all.Token, from, to); !errors.Is(err, domain.ErrFeedNotFound) {
+		t.Fatalf("revoked feed: err = %v", err)
+	}
+	if _, err := svc.FeedSessions(ctx, room.Token, from, to); err != nil {
+		t.Fatalf("other feed must keep working: %v", err)
+	}
+}
+
+func TestSchedule_CheckWindow(t *testing.T) {
+	svc, store := newSchedule()
+	ctx := context.Background()
+	now := time.Now()
+
+	if err := svc.CheckWindow(ctx, schedRoomC, 1); err != nil {
+		t.Fatalf("no schedule: %v", err)
+	}
+
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: now.Add(-10 * time.Minute), Duration: time.Hour, Timezone: "UTC"})
+	if err := svc.CheckWindow(ctx, schedRoomA, 1); err != nil {
+		t.Fatalf("during session: %v", err)
+	}
+
+	// до начала, но в пределах open_before — пускаем
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: now.Add(5 * time.Minute), Duration: time.Hour, OpenBefore: 10 * time.Minute, Timezone: "UTC"})
+	if err := svc.CheckWindow(ctx, schedRoomA, 1); err != nil {
+		t.Fatalf("within open_before: %v", err)
+	}
+
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: now.Add(30 * time.Minute), Duration: time.Hour, OpenBefore: 10 * time.Minute, Timezone: "UTC"})
+	if err := svc.CheckWindow(ctx, schedRoomA, 1); !errors.Is(err, domain.ErrRoomClosed) {
+		t.Fatalf("too early: err = %v", err)
+	}
+	if err := svc.CheckWindow(ctx, schedRoomA, 10); err != nil {
+		t.Fatalf("moderator outside window: %v", err)
+	}
+
+	// разовое занятие прошло
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: now.Add(-2 * time.Hour), Duration: time.Hour, Timezone: "UTC"})
+	if err := svc.CheckWindow(ctx, schedRoomA, 1); !errors.Is(err, domain.ErrRoomClosed) {
+		t.Fatalf("after session: err = %v", err)
+	}
+}
+
+type windowChange struct {
+	roomID string
+	open   bool
+	sess   domain.Session
+}
+
+func TestSchedule_RunNotifiesWindowChanges(t *testing.T) {
+	svc, store := newSchedule()
+	changes := make(chan windowChange, 8)
+	svc.SetOnChange(func(roomID string, open bool, sess domain.Session) {
+		changes <- windowChange{roomID, open, sess}
+	})
+
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour)
+	// rrule режет DTSTART до секунды — берём ближайшую целую секунду впереди
+	startA := now.Add(time.Second).Truncate(time.Second)
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: startA, Duration: time.Hour, Timezone: "UTC"})
+	store.put(domain.Schedule{RoomID: schedRoomB, StartsAt: tomorrow, Duration: time.Hour, Timezone: "UTC"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx, 20*time.Millisecond)
+
+	next := func() windowChange {
+		t.Helper()
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(3 * time.Second):
+			t.Fatal("no window change")
+			return windowChange{}
+		}
+	}
+
+	// первая проверка молчит, дальше — только переходы: открылась A, B не трогаем
+	c := next()
+	if c.roomID != schedRoomA || !c.open || !c.sess.StartsAt.Equal(startA) || !c.sess.EndsAt.Equal(startA.Add(time.Hour)) {
+		t.Fatalf("open change = %+v", c)
+	}
+
+	// занятие перенесли на завтра — окно закрылось
+	store.put(domain.Schedule{RoomID: schedRoomA, StartsAt: tomorrow, Duration: time.Hour, Timezone: "UTC"})
+	if c := next(); c.roomID != schedRoomA || c.open {
+		t.Fatalf("close change = %+v", c)
+	}
+
+	// новое расписание с закрытым окном — тоже событие (выгнать тех, кто уже внутри)
+	store.put(domain.Schedule{RoomID: schedRoomC, StartsAt: tomorrow, Duration: time.Hour, Timezone: "UTC"})
+	if c := next(); c.roomID != schedRoomC || c.open {
+		t.Fatalf("new schedule change = %+v", c)
+	}
+
+	select {
+	case c := <-changes:
+		t.Fatalf("unexpected change %+v", c)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
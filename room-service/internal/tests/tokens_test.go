@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	testIssuer   = "auth-test"
+	testAudience = "cwrk-test"
+)
+
+// testKey — ключ "auth-service" на весь прогон: RSA 2048 генерируется заметно долго
+var testKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+func testVerifier() *authz.Verifier {
+	return authz.NewVerifier(&testKey().PublicKey, testIssuer, testAudience, 0)
+}
+
+// accessToken — access-токен платформы для uid, как его выпускает auth-service
+func accessToken(t *testing.T, uid int64) string {
+	t.Helper()
+	return signAccess(t, testKey(), uid)
+}
+
+// forgedToken — такой же токен, но подписан чужим ключом
+func forgedToken(t *testing.T, uid int64) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signAccess(t, key, uid)
+}
+
+func signAccess(t *testing.T, key *rsa.PrivateKey, uid int64) string {
+	t.Helper()
+	now := time.Now()
+	tok, err := jwt.NewWithClaims(jwt.SigningMethodRS256, authz.Claims{
+		StandardClaims: jwt.StandardClaims{
+			Subject:   strconv.FormatInt(uid, 10),
+			Issuer:    testIssuer,
+			Audience:  testAudience,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(time.Minute).Unix(),
+		},
+		TokenUse: authz.TokenUseAccess,
+	}).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tok
+}
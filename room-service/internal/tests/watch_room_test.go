@@ -0,0 +1,256 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	grpcx "github.com/cwrk-planet/room-service/internal/transport/grpc"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+
+	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const watchRoomID = "8d3c3f7e-2a55-4c1e-9d4e-0c5a1b2f3e4d"
+
+type fakeMembers struct{}
+
+func (fakeMembers) CanWatch(_ context.Context, roomID string, userID int64) error {
+	if roomID != watchRoomID {
+		return domain.ErrRoomNotFound
+	}
+	if userID != 7 {
+		return domain.ErrForbidden
+	}
+	return nil
+}
+
+func (fakeMembers) ListParticipantsDetailed(context.Context, string) ([]service.ParticipantDetailed, error) {
+	now := time.Now()
+	return []service.ParticipantDetailed{{UserID: 7, JoinedAt: now, LastSeen: now}}, nil
+}
+
+type fakeChat struct{ msgs []domain.ChatMessage }
+
+func (f fakeChat) Since(_ context.Context, _ string, afterID string, limit int) ([]domain.ChatMessage, error) {
+	for i, m := range f.msgs {
+		if m.ID == afterID {
+			out := f.msgs[i+1:]
+			if len(out) > limit {
+				out = out[:limit]
+			}
+			return out, nil
+		}
+	}
+	return nil, domain.ErrMessageNotFound
+}
+
+func startWatchServer(t *testing.T, hub *ws.Hub, chat fakeChat) roomv1.RoomServiceClient {
+	t.Helper()
+	return startWatchServerWith(t, hub, chat, testVerifier())
+}
+
+func startWatchServerWith(t *testing.T, hub *ws.Hub, chat fakeChat, v *authz.Verifier) roomv1.RoomServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcx.NewServer(nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	srv.SetWatch(hub, fakeMembers{}, chat)
+
+	gs := grpc.NewServer(grpc.StreamInterceptor(grpcx.StreamServerInterceptor(v)))
+	grpcx.Register(gs, srv)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return roomv1.NewRoomServiceClient(conn)
+}
+
+// authCtx — bearer-токен пользователя uid
+func authCtx(t *testing.T, uid int64) context.Context {
+	return mdCtx(t, "authorization", "Bearer "+accessToken(t, uid))
+}
+
+func mdCtx(t *testing.T, kv ...string) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+func watchErr(cli roomv1.RoomServiceClient, ctx context.Context) error {
+	stream, err := cli.WatchRoom(ctx, &roomv1.WatchRoomRequest{RoomId: watchRoomID})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	return err
+}
+
+func TestWatchRoom_Unauthenticated(t *testing.T) {
+	cli := startWatchServer(t, ws.NewHub(), fakeChat{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := cli.WatchRoom(ctx, &roomv1.WatchRoomRequest{RoomId: watchRoomID})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated, got %v", err)
+	}
+}
+
+// пользователь — только из проверенного токена: x-user-id участника комнаты ничего не дает
+func TestWatchRoom_ForgedUserID(t *testing.T) {
+	cli := startWatchServer(t, ws.NewHub(), fakeChat{})
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want codes.Code
+	}{
+		"x-user-id only":         {mdCtx(t, "x-user-id", "7"), codes.Unauthenticated},
+		"unverified bearer":      {mdCtx(t, "authorization", "Bearer test", "x-user-id", "7"), codes.Unauthenticated},
+		"someone else's token":   {mdCtx(t, "authorization", "Bearer "+accessToken(t, 8), "x-user-id", "7"), codes.PermissionDenied},
+		"token from another key": {mdCtx(t, "authorization", "Bearer "+forgedToken(t, 7), "x-user-id", "7"), codes.Unauthenticated},
+	} {
+		if err := watchErr(cli, tc.ctx); status.Code(err) != tc.want {
+			t.Errorf("%s: want %v, got %v", name, tc.want, err)
+		}
+	}
+
+	// без verifier стримы закрыты даже с настоящим токеном
+	closed := startWatchServerWith(t, ws.NewHub(), fakeChat{}, nil)
+	if err := watchErr(closed, authCtx(t, 7)); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("no verifier: want Unauthenticated, got %v", err)
+	}
+}
+
+func TestWatchRoom_Forbidden(t *testing.T) {
+	cli := startWatchServer(t, ws.NewHub(), fakeChat{})
+
+	stream, err := cli.WatchRoom(authCtx(t, 8), &roomv1.WatchRoomRequest{RoomId: watchRoomID})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestWatchRoom_StateAndLiveEvents(t *testing.T) {
+	hub := ws.NewHub()
+	cli := startWatchServer(t, hub, fakeChat{})
+
+	stream, err := cli.WatchRoom(authCtx(t, 7), &roomv1.WatchRoomRequest{RoomId: watchRoomID})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv state: %v", err)
+	}
+	st := ev.GetState()
+	if st == nil || st.GetRoomId() != watchRoomID || len(st.GetParticipants()) != 1 {
+		t.Fatalf("expected state first, got %v", ev)
+	}
+
+	// снапшот отдан — подписка уже есть, рассылки хаба должны прийти
+	hub.Broadcast(watchRoomID, ws.Message{Type: ws.TypePeerJoined, Payload: ws.PeerEventPayload{RoomID: watchRoomID, UserID: "9"}})
+	hub.Broadcast(watchRoomID, ws.Message{Type: ws.TypeTypingStart, Payload: ws.PeerEventPayload{RoomID: watchRoomID, UserID: "9"}})
+	hub.Broadcast(watchRoomID, ws.Message{Type: ws.TypeChat, Payload: ws.ChatPayload{
+		RoomID: watchRoomID, UserID: "9", Message: "привет", MsgID: "m1", TSUnix: time.Now().Unix(),
+	}})
+
+	ev, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("recv peer: %v", err)
+	}
+	if p := ev.GetPeerJoined(); p == nil || p.GetUserId() != "9" {
+		t.Fatalf("expected peer_joined, got %v", ev)
+	}
+
+	// typing_start в WatchRoom не попадает
+	ev, err = stream.Recv()
+	if err != nil {
+		t.Fatalf("recv chat: %v", err)
+	}
+	if c := ev.GetChat(); c == nil || c.GetText() != "привет" || c.GetId() != "m1" {
+		t.Fatalf("expected chat, got %v", ev)
+	}
+	if ev.GetCursor() != "m1" {
+		t.Fatalf("expected cursor m1, got %q", ev.GetCursor())
+	}
+}
+
+func TestWatchRoom_ResumeFromCursor(t *testing.T) {
+	hub := ws.NewHub()
+	now := time.Now()
+	chat := fakeChat{msgs: []domain.ChatMessage{
+		{ID: "m1", RoomID: watchRoomID, UserID: 7, Text: "one", CreatedAt: now},
+		{ID: "m2", RoomID: watchRoomID, UserID: 7, Text: "two", CreatedAt: now},
+		{ID: "m3", RoomID: watchRoomID, UserID: 7, Text: "three", CreatedAt: now},
+	}}
+	cli := startWatchServer(t, hub, chat)
+
+	stream, err := cli.WatchRoom(authCtx(t, 7), &roomv1.WatchRoomRequest{RoomId: watchRoomID, Cursor: "m1"})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if ev, err := stream.Recv(); err != nil || ev.GetState() == nil {
+		t.Fatalf("expected state first, got %v, %v", ev, err)
+	}
+	for _, want := range []string{"m2", "m3"} {
+		ev, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("recv replay: %v", err)
+		}
+		if ev.GetChat().GetId() != want || ev.GetCursor() != want {
+			t.Fatalf("expected replayed %s, got %v", want, ev)
+		}
+	}
+
+	// m3 уже отдан догонялкой — повтор из хаба пропускается
+	hub.Broadcast(watchRoomID, ws.Message{Type: ws.TypeChat, Payload: ws.ChatPayload{RoomID: watchRoomID, UserID: "7", Message: "three", MsgID: "m3"}})
+	hub.Broadcast(watchRoomID, ws.Message{Type: ws.TypeChat, Payload: ws.ChatPayload{RoomID: watchRoomID, UserID: "7", Message: "four", MsgID: "m4"}})
+
+	ev, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("recv live: %v", err)
+	}
+	if ev.GetChat().GetId() != "m4" || ev.GetCursor() != "m4" {
+		t.Fatalf("expected m4, got %v", ev)
+	}
+}
+
+func TestWatchRoom_UnknownCursor(t *testing.T) {
+	cli := startWatchServer(t, ws.NewHub(), fakeChat{})
+
+	stream, err := cli.WatchRoom(authCtx(t, 7), &roomv1.WatchRoomRequest{RoomId: watchRoomID, Cursor: "nope"})
+	if err != nil {
+		t.Fatalf("watch: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("expected state before cursor error: %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
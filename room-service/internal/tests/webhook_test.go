@@ -0,0 +1,231 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+const webhookSecret = "whsec_test"
+
+func testDelivery(url string) domain.WebhookDelivery {
+	return domain.WebhookDelivery{
+		ID:             "d-1",
+		EventID:        "e-1",
+		EventType:      domain.EventChatMessage,
+		Payload:        json.RawMessage(`{"room_id":"r-1","text":"привет"}`),
+		EventCreatedAt: time.Unix(1700000000, 0),
+		URL:            url,
+		Secret:         webhookSecret,
+	}
+}
+
+func TestWebhookSender_SignedDelivery(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	code, err := service.NewWebhookSender(2*time.Second, true).Send(context.Background(), testDelivery(srv.URL))
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("send: code=%d err=%v", code, err)
+	}
+
+	rec := <-got
+	if rec.header.Get(service.HeaderWebhookID) != "d-1" || rec.header.Get(service.HeaderWebhookEvent) != domain.EventChatMessage {
+		t.Fatalf("unexpected headers: %v", rec.header)
+	}
+	err = service.VerifyWebhook(webhookSecret, rec.header.Get(service.HeaderWebhookTimestamp),
+		rec.header.Get(service.HeaderWebhookSignature), rec.body, 5*time.Minute, time.Now())
+	if err != nil {
+		t.Fatalf("signature must verify: %v", err)
+	}
+
+	var env service.WebhookEnvelope
+	if err := json.Unmarshal(rec.body, &env); err != nil {
+		t.Fatalf("body: %v", err)
+	}
+	if env.ID != "e-1" || env.Type != domain.EventChatMessage || string(env.Data) != `{"room_id":"r-1","text":"привет"}` {
+		t.Fatalf("unexpected envelope: %+v", env)
+	}
+
+	// подмена тела или чужой секрет — подпись не сходится
+	tampered := append([]byte{}, rec.body...)
+	tampered[len(tampered)-2] = 'X'
+	if err := service.VerifyWebhook(webhookSecret, rec.header.Get(service.HeaderWebhookTimestamp),
+		rec.header.Get(service.HeaderWebhookSignature), tampered, 5*time.Minute, time.Now()); !errors.Is(err, service.ErrWebhookSignature) {
+		t.Fatalf("tampered body must fail, got %v", err)
+	}
+	if err := service.VerifyWebhook("whsec_other", rec.header.Get(service.HeaderWebhookTimestamp),
+		rec.header.Get(service.HeaderWebhookSignature), rec.body, 5*time.Minute, time.Now()); !errors.Is(err, service.ErrWebhookSignature) {
+		t.Fatalf("wrong secret must fail, got %v", err)
+	}
+	// старый timestamp — повтор перехваченного запроса
+	if err := service.VerifyWebhook(webhookSecret, rec.header.Get(service.HeaderWebhookTimestamp),
+		rec.header.Get(service.HeaderWebhookSignature), rec.body, 5*time.Minute, time.Now().Add(time.Hour)); !errors.Is(err, service.ErrWebhookSignature) {
+		t.Fatalf("stale timestamp must fail, got %v", err)
+	}
+}
+
+func TestWebhookSender_Failures(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/fail":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "/redirect":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/slow":
+			time.Sleep(300 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer srv.Close()
+
+	sender := service.NewWebhookSender(100*time.Millisecond, true)
+	ctx := context.Background()
+
+	if code, err := sender.Send(ctx, testDelivery(srv.URL+"/fail")); err == nil || code != http.StatusInternalServerError {
+		t.Fatalf("5xx must fail with code, got code=%d err=%v", code, err)
+	}
+	// редиректы не ходим: иначе подписанный запрос уйдёт на чужой адрес
+	if code, err := sender.Send(ctx, testDelivery(srv.URL+"/redirect")); err == nil || code != http.StatusFound {
+		t.Fatalf("redirect must fail with 302, got code=%d err=%v", code, err)
+	}
+	if code, err := sender.Send(ctx, testDelivery(srv.URL+"/slow")); err == nil || code != 0 {
+		t.Fatalf("timeout must fail without code, got code=%d err=%v", code, err)
+	}
+	if n := hits.Load(); n != 3 {
+		t.Fatalf("expected 3 requests (redirect not followed), got %d", n)
+	}
+}
+
+func TestWebhookSender_DeniesPrivateTargets(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	code, err := service.NewWebhookSender(time.Second, false).Send(context.Background(), testDelivery(srv.URL))
+	if err == nil || code != 0 {
+		t.Fatalf("loopback target must be denied, got code=%d err=%v", code, err)
+	}
+	if hits.Load() != 0 {
+		t.Fatalf("receiver must not be hit")
+	}
+}
+
+func TestWebhookRetry_BackoffAndDeadLetter(t *testing.T) {
+	p := domain.WebhookRetry{Base: 30 * time.Second, Max: 10 * time.Minute, MaxAttempts: 6}
+
+	want := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute}
+	for i, w := range want {
+		got, dead := p.Next(i + 1)
+		if dead || got != w {
+			t.Fatalf("attempt %d: got %v dead=%v, want %v", i+1, got, dead, w)
+		}
+	}
+	if _, dead := p.Next(6); !dead {
+		t.Fatalf("attempt 6 must be dead")
+	}
+
+	// потолок
+	p.MaxAttempts = 100
+	if got, _ := p.Next(50); got != p.Max {
+		t.Fatalf("expected cap %v, got %v", p.Max, got)
+	}
+}
+
+// Событие комнаты без владельца (или удалённой) не должно никуда уйти: owner_id NULL
+// у события значит "системное", и FanOut разложил бы его всем подписчикам типа.
+func TestWebhook_OwnerlessRoomEventsAreDropped(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		hits = map[string][]string{} // путь -> события
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path] = append(hits[r.URL.Path], r.Header.Get(service.HeaderWebhookEvent))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	owner := int64(1)
+	owned := domain.Room{ID: "0a000000-0000-4000-8000-000000000001", Name: "своя", OwnerID: &owner}
+	ownerless := domain.Room{ID: "0b000000-0000-4000-8000-000000000002", Name: "ничья"}
+	store := &memWebhooks{}
+	svc := service.NewWebhookService(store, newMemRooms(owned, ownerless),
+		service.NewWebhookSender(2*time.Second, true),
+		domain.WebhookRetry{Base: time.Second, Max: time.Minute, MaxAttempts: 3}, []int64{9})
+	ctx := context.Background()
+
+	for uid, types := range map[int64][]string{
+		1: {domain.EventParticipantJoined},
+		2: {domain.EventParticipantJoined},
+		9: {domain.EventParticipantJoined, domain.EventUserRegistered},
+	} {
+		if _, err := svc.CreateSubscription(ctx, uid, fmt.Sprintf("%s/%d", srv.URL, uid), types); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publish := func(id, typ, key string) {
+		t.Helper()
+		err := svc.Publish(ctx, events.Envelope{ID: id, Type: typ, Key: key, OccurredAt: time.Now(), Payload: json.RawMessage(`{}`)})
+		if err != nil {
+			t.Fatalf("publish %s: %v", id, err)
+		}
+	}
+	publish("e-owned", domain.EventParticipantJoined, owned.ID)
+	publish("e-ownerless", domain.EventParticipantJoined, ownerless.ID)
+	publish("e-deleted", domain.EventParticipantJoined, "0c000000-0000-4000-8000-000000000003")
+	publish("e-bad-key", domain.EventParticipantJoined, "not-a-room")
+	publish("e-system", domain.EventUserRegistered, "")
+
+	if len(store.events) != 2 {
+		t.Fatalf("stored events = %+v", store.events)
+	}
+	if err := svc.Dispatch(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[string][]string{
+		"/1": {domain.EventParticipantJoined},
+		"/9": {domain.EventUserRegistered},
+	}
+	if len(hits) != len(want) {
+		t.Fatalf("deliveries = %v, want %v", hits, want)
+	}
+	for path, evs := range want {
+		if !slices.Equal(hits[path], evs) {
+			t.Fatalf("deliveries = %v, want %v", hits, want)
+		}
+	}
+}
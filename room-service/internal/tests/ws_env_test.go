@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/gorilla/websocket"
+)
+
+// wsDeps — сервисы для ws.Server; nil заменяется заглушкой, которая ничего не делает.
+type wsDeps struct {
+	chat      ws.ChatSvc
+	presence  ws.PresenceSvc
+	hands     ws.HandQueueSvc
+	polls     ws.PollSvc
+	lobby     ws.LobbySvc
+	breakouts ws.BreakoutSvc
+}
+
+type wsEnv struct {
+	srv     *ws.Server
+	hub     *ws.Hub
+	members *stubMembers
+	base    string
+}
+
+func newWSEnv(t *testing.T, deps wsDeps) *wsEnv {
+	t.Helper()
+	if deps.presence == nil {
+		deps.presence = stubPresence{}
+	}
+	if deps.hands == nil {
+		deps.hands = stubHands{}
+	}
+	if deps.polls == nil {
+		deps.polls = stubPolls{}
+	}
+	if deps.lobby == nil {
+		deps.lobby = stubLobby{}
+	}
+	if deps.breakouts == nil {
+		deps.breakouts = stubBreakouts{}
+	}
+	members := &stubMembers{left: make(map[int64]bool)}
+	hub := ws.NewHub()
+	srv := ws.NewServer(hub, members, deps.chat, deps.presence, deps.hands, deps.polls, deps.lobby, deps.breakouts)
+	srv.SetVerifier(testVerifier())
+
+	r := chi.NewRouter()
+	r.Get("/ws/rooms/{id}", srv.HandleWS)
+	hs := httptest.NewServer(r)
+	t.Cleanup(hs.Close)
+
+	return &wsEnv{srv: srv, hub: hub, members: members, base: "ws" + strings.TrimPrefix(hs.URL, "http")}
+}
+
+// wsClient — подключение участника; все входящие складываются в канал.
+type wsClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+	in   chan wsIn
+}
+
+type wsIn struct {
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+func (e *wsEnv) dial(t *testing.T, roomID string, userID int64) *wsClient {
+	t.Helper()
+	return e.dialQuery(t, roomID, "access_token="+accessToken(t, userID))
+}
+
+// dialQuery — подключение с произвольной query-строкой; рукопожатие обязано пройти
+func (e *wsEnv) dialQuery(t *testing.T, roomID, query string) *wsClient {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial(e.base+"/ws/rooms/"+roomID+"?"+query, nil)
+	if err != nil {
+		t.Fatalf("dial %s: %v (%v)", query, err, resp)
+	}
+	c := &wsClient{t: t, conn: conn, in: make(chan wsIn, 64)}
+	go func() {
+		defer close(c.in)
+		for {
+			var m wsIn
+			if err := conn.ReadJSON(&m); err != nil {
+				return
+			}
+			c.in <- m
+		}
+	}()
+	t.Cleanup(func() { _ = conn.Close() })
+	return c
+}
+
+func (c *wsClient) send(typ string, payload any) {
+	c.t.Helper()
+	if err := c.conn.WriteJSON(ws.Message{Type: typ, Payload: payload}); err != nil {
+		c.t.Fatalf("send %s: %v", typ, err)
+	}
+}
+
+// expect — ждёт событие typ (остальные пропускает) и раскладывает payload в dst.
+func (c *wsClient) expect(typ string, dst any) {
+	c.t.Helper()
+	timeout := time.After(3 * time.Second)
+	for {
+		select {
+		case m, ok := <-c.in:
+			if !ok {
+				c.t.Fatalf("connection closed while waiting for %s", typ)
+			}
+			if m.Type != typ {
+				continue
+			}
+			if dst != nil {
+				if err := json.Unmarshal(m.Payload, dst); err != nil {
+					c.t.Fatalf("%s payload: %v", typ, err)
+				}
+			}
+			return
+		case <-timeout:
+			c.t.Fatalf("no %s within 3s", typ)
+		}
+	}
+}
+
+// count — сколько событий typ пришло за d.
+func (c *wsClient) count(typ string, d time.Duration) int {
+	n := 0
+	timeout := time.After(d)
+	for {
+		select {
+		case m, ok := <-c.in:
+			if !ok {
+				return n
+			}
+			if m.Type == typ {
+				n++
+			}
+		case <-timeout:
+			return n
+		}
+	}
+}
+
+func (c *wsClient) close() { _ = c.conn.Close() }
+
+type stubMembers struct {
+	mu   sync.Mutex
+	left map[int64]bool
+}
+
+func (m *stubMembers) ListParticipants(context.Context, string) ([]domain.Participant, error) {
+	return nil, nil
+}
+func (m *stubMembers) TouchHeartbeat(context.Context, string, int64) error { return nil }
+func (m *stubMembers) CheckWindow(context.Context, string, int64) error    { return nil }
+func (m *stubMembers) LeaveRoom(_ context.Context, _ string, userID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.left[userID] = true
+	return nil
+}
+
+type stubPresence struct{}
+
+func (stubPresence) Connect(_ context.Context, roomID string, userID int64) (domain.Presence, domain.PresenceDelta, error) {
+	return domain.Presence{RoomID: roomID, UserID: userID, Status: domain.PresenceOnline}, domain.PresenceDelta{}, nil
+}
+func (stubPresence) Disconnect(context.Context, string, int64) (domain.PresenceDelta, bool) {
+	return domain.PresenceDelta{}, true
+}
+func (stubPresence) Update(roomID string, userID int64, _ domain.PresenceUpdate) (domain.Presence, domain.PresenceDelta, error) {
+	return domain.Presence{RoomID: roomID, UserID: userID}, domain.PresenceDelta{}, nil
+}
+func (stubPresence) Snapshot(string) map[int64]domain.Presence { return nil }
+
+type stubHands struct{ ws.HandQueueSvc }
+
+func (stubHands) State(_ context.Context, roomID string) (*domain.HandQueue, error) {
+	return &domain.HandQueue{RoomID: roomID}, nil
+}
+func (stubHands) Lower(context.Context, int64, string, int64) (bool, error) { return false, nil }
+func (stubHands) ReleaseOwn(context.Context, string, int64) (bool, error)   { return false, nil }
+
+type stubPolls struct{ ws.PollSvc }
+
+func (stubPolls) OpenPolls(context.Context, string) ([]domain.Poll, error) { return nil, nil }
+
+type stubLobby struct{ ws.LobbySvc }
+
+func (stubLobby) Enter(context.Context, string, int64) (bool, int, error)    { return false, 0, nil }
+func (stubLobby) Queue(context.Context, string) ([]domain.LobbyEntry, error) { return nil, nil }
+
+type stubBreakouts struct{ ws.BreakoutSvc }
+
+func (stubBreakouts) Resolve(_ context.Context, roomID string, _ int64) (string, error) {
+	return roomID, nil
+}
+func (stubBreakouts) ForRoom(context.Context, string) (*domain.Breakout, error) {
+	return nil, domain.ErrBreakoutNotFound
+}
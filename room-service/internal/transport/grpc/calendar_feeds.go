@@ -0,0 +1,72 @@
+package grpcx
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) CreateCalendarFeed(ctx context.Context, in *roomv1.CreateCalendarFeedRequest) (*roomv1.CreateCalendarFeedResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	f, err := s.scheduleSvc.CreateFeed(ctx, uid, in.GetRoomId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.CreateCalendarFeedResponse{Feed: mapFeed(*f), Token: f.Token}, nil
+}
+
+func (s *Server) ListCalendarFeeds(ctx context.Context, _ *roomv1.ListCalendarFeedsRequest) (*roomv1.ListCalendarFeedsResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	feeds, err := s.scheduleSvc.ListFeeds(ctx, uid)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListCalendarFeedsResponse{Items: make([]*roomv1.CalendarFeed, 0, len(feeds))}
+	for _, f := range feeds {
+		out.Items = append(out.Items, mapFeed(f))
+	}
+	return out, nil
+}
+
+func (s *Server) DeleteCalendarFeed(ctx context.Context, in *roomv1.DeleteCalendarFeedRequest) (*roomv1.DeleteCalendarFeedResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.scheduleSvc.DeleteFeed(ctx, uid, in.GetId()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.DeleteCalendarFeedResponse{}, nil
+}
+
+// GetFeedSessions — без bearer и x-user-id: кто и какая комната — решает токен ленты.
+func (s *Server) GetFeedSessions(ctx context.Context, in *roomv1.GetFeedSessionsRequest) (*roomv1.GetFeedSessionsResponse, error) {
+	items, err := s.scheduleSvc.FeedSessions(ctx, in.GetToken(), in.GetFrom().AsTime(), in.GetTo().AsTime())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.GetFeedSessionsResponse{Items: make([]*roomv1.Session, 0, len(items))}
+	for _, it := range items {
+		out.Items = append(out.Items, &roomv1.Session{
+			RoomId:   it.RoomID,
+			RoomName: it.RoomName,
+			StartsAt: timestamppb.New(it.StartsAt),
+			EndsAt:   timestamppb.New(it.EndsAt),
+		})
+	}
+	return out, nil
+}
+
+func mapFeed(f domain.CalendarFeed) *roomv1.CalendarFeed {
+	return &roomv1.CalendarFeed{Id: f.ID, RoomId: f.RoomID, CreatedAt: timestamppb.New(f.CreatedAt)}
+}
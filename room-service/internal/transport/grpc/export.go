@@ -0,0 +1,62 @@
+package grpcx
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) ListMyMemberships(ctx context.Context, _ *roomv1.ListMyMembershipsRequest) (*roomv1.ListMyMembershipsResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.memberSvc.Memberships(ctx, uid)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListMyMembershipsResponse{Items: make([]*roomv1.Membership, 0, len(items))}
+	for _, m := range items {
+		out.Items = append(out.Items, mapMembership(m))
+	}
+	return out, nil
+}
+
+func (s *Server) ListMyMessages(ctx context.Context, in *roomv1.ListMyMessagesRequest) (*roomv1.ListMyMessagesResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.chatSvc.ByUser(ctx, uid, in.GetCursor(), int(in.GetLimit()))
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListMyMessagesResponse{
+		Items:      make([]*roomv1.ChatMessage, 0, len(items)),
+		NextCursor: next,
+	}
+	for _, m := range items {
+		out.Items = append(out.Items, mapChat(m))
+	}
+	return out, nil
+}
+
+func mapMembership(m domain.Membership) *roomv1.Membership {
+	out := &roomv1.Membership{
+		RoomId:    m.RoomID,
+		RoomName:  m.RoomName,
+		Owner:     m.Owner,
+		Moderator: m.Moderator,
+	}
+	if m.JoinedAt != nil {
+		out.JoinedAt = timestamppb.New(*m.JoinedAt)
+	}
+	if m.LastSeen != nil {
+		out.LastSeen = timestamppb.New(*m.LastSeen)
+	}
+	return out
+}
@@ -6,8 +6,11 @@ import (
 	"runtime/debug"
 	"time"
 
+	"github.com/cwrk-planet/authz/pkg/authz"
+
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -46,7 +49,10 @@ func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
-func StreamServerInterceptor() grpc.StreamServerInterceptor {
+// Stream logging + recovery + авторизация: стримы живут долго, поэтому bearer проверяем один раз
+// до хендлера, а Principal кладём в контекст стрима. x-user-id для стримов не читается вовсе:
+// WatchRoom отдаёт чат и присутствие, доверять заявленному id нельзя. Без verifier стримы закрыты.
+func StreamServerInterceptor(v *authz.Verifier) grpc.StreamServerInterceptor {
 	return func(
 		srv any,
 		ss grpc.ServerStream,
@@ -69,8 +75,45 @@ func StreamServerInterceptor() grpc.StreamServerInterceptor {
 				"err", errString(err))
 		}()
 
-		return handler(srv, ss)
+		p, err := streamPrincipal(ss.Context(), v)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authStream{
+			ServerStream: ss,
+			ctx:          authz.WithPrincipal(ss.Context(), p),
+		})
+	}
+}
+
+// streamPrincipal — пользователь из проверенного "authorization: Bearer <access_token>".
+func streamPrincipal(ctx context.Context, v *authz.Verifier) (*authz.Principal, error) {
+	if v == nil {
+		return nil, status.Error(codes.Unauthenticated, "access tokens are not verified: auth.publicKeyPath is not set")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := v.VerifyHeader(first(md.Get(mdAuthorization)))
+	if err != nil {
+		return nil, authz.StatusError(err)
+	}
+	return p, nil
+}
+
+// authStream — ServerStream с Principal в контексте.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authStream) Context() context.Context { return s.ctx }
+
+// streamUID — user_id из Principal, который положил StreamServerInterceptor.
+func streamUID(ctx context.Context) (int64, error) {
+	p, ok := authz.FromContext(ctx)
+	if !ok {
+		return 0, status.Error(codes.Unauthenticated, "unauthenticated")
 	}
+	return p.UserID, nil
 }
 
 func errString(err error) string {
@@ -5,9 +5,13 @@ import (
 	"errors"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cwrk-planet/authz/pkg/authz"
 	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/postgres"
 	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
 
 	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
 
@@ -23,23 +27,51 @@ const (
 	mdUserID        = "x-user-id"
 )
 
+// ScopeRules — какие scopes нужны методам (см. authz.UnaryServerInterceptor)
+var ScopeRules = authz.Rules{
+	roomv1.RoomService_CreateRoom_FullMethodName: {authz.ScopeRoomsCreate},
+}
+
 type Server struct {
 	roomv1.UnimplementedRoomServiceServer
 
-	roomSvc   *service.RoomService
-	memberSvc *service.MemberService
-	chatSvc   *service.ChatService
+	roomSvc       *service.RoomService
+	memberSvc     *service.MemberService
+	chatSvc       *service.ChatService
+	attachmentSvc *service.AttachmentService
+	pollSvc       *service.PollService
+	scheduleSvc   *service.ScheduleService
+	lobbySvc      *service.LobbyService
+	breakoutSvc   *service.BreakoutService
+	webhookSvc    *service.WebhookService
+
+	// WatchRoom (см. SetWatch)
+	hub          *ws.Hub
+	watchMembers WatchMembers
+	watchChat    WatchChat
 }
 
 func NewServer(
 	roomSvc *service.RoomService,
 	memberSvc *service.MemberService,
 	chatSvc *service.ChatService,
+	attachmentSvc *service.AttachmentService,
+	pollSvc *service.PollService,
+	scheduleSvc *service.ScheduleService,
+	lobbySvc *service.LobbyService,
+	breakoutSvc *service.BreakoutService,
+	webhookSvc *service.WebhookService,
 ) *Server {
 	return &Server{
-		roomSvc:   roomSvc,
-		memberSvc: memberSvc,
-		chatSvc:   chatSvc,
+		roomSvc:       roomSvc,
+		memberSvc:     memberSvc,
+		chatSvc:       chatSvc,
+		attachmentSvc: attachmentSvc,
+		pollSvc:       pollSvc,
+		scheduleSvc:   scheduleSvc,
+		lobbySvc:      lobbySvc,
+		breakoutSvc:   breakoutSvc,
+		webhookSvc:    webhookSvc,
 	}
 }
 
@@ -64,6 +96,11 @@ func userFromMD(ctx context.Context) (token, userID string, _ error) {
 	}
 	token = strings.TrimSpace(auth[7:])
 
+	// токен уже проверен authz-интерсептором — пользователь из него, x-user-id не нужен
+	if p, ok := authz.FromContext(ctx); ok {
+		return token, strconv.FormatInt(p.UserID, 10), nil
+	}
+
 	userID = first(md.Get(mdUserID))
 	if userID == "" {
 		return "", "", status.Error(codes.Unauthenticated, "missing x-user-id")
@@ -87,13 +124,57 @@ func valueOrEmpty(p *string) string {
 	return *p
 }
 
+// authorID — 0 у сообщений удалённого аккаунта, наружу отдаём пустой id.
+func authorID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func mapPresence(p domain.Presence) *roomv1.Presence {
+	out := &roomv1.Presence{
+		Status:     string(p.Status),
+		Mic:        p.Mic,
+		Cam:        p.Cam,
+		Screen:     p.Screen,
+		StatusText: p.StatusText,
+	}
+	if p.HandRaisedAt != nil {
+		out.HandRaisedAt = timestamppb.New(*p.HandRaisedAt)
+	}
+	return out
+}
+
 func mapRoom(r *domain.Room) *roomv1.Room {
-	return &roomv1.Room{
+	out := &roomv1.Room{
 		Id:              r.ID,
 		Name:            r.Name,
 		MaxParticipants: r.MaxParticipants,
 		CreatedAt:       timestamppb.New(r.CreatedAt),
 	}
+	if r.OwnerID != nil {
+		out.OwnerId = strconv.FormatInt(*r.OwnerID, 10)
+	}
+	if r.ParentID != nil {
+		out.ParentId = *r.ParentID
+	}
+	if r.Description != nil {
+		out.Description = *r.Description
+	}
+	if r.Topic != nil {
+		out.Topic = *r.Topic
+	}
+	if r.Language != nil {
+		out.Language = *r.Language
+	}
+	out.Tags = r.Tags
+	if !r.ActivityAt.IsZero() {
+		out.Participants = r.Participants
+		out.Online = r.Online
+		out.ActivityAt = timestamppb.New(r.ActivityAt)
+	}
+	return out
 }
 
 func mapErr(err error) error {
@@ -109,6 +190,48 @@ func mapErr(err error) error {
 		return status.Error(codes.FailedPrecondition, err.Error())
 	case errors.Is(err, domain.ErrNotInRoom):
 		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrAttachmentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrAttachmentInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrMessageNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrHandQueueEmpty):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrInvalidDuration):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrPollNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrPollInvalid), errors.Is(err, domain.ErrVoteInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrPollClosed), errors.Is(err, domain.ErrAlreadyVoted):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrScheduleNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrScheduleInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrRoomClosed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, domain.ErrNotInLobby):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrRoomInvalid), errors.Is(err, postgres.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrInvalidCapacity):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrBreakoutNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrBreakoutInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrBreakoutActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, domain.ErrWebhookNotFound), errors.Is(err, domain.ErrDeliveryNotFound), errors.Is(err, domain.ErrFeedNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrWebhookInvalid):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
 	default:
 		return status.Error(codes.Internal, err.Error())
 	}
@@ -118,20 +241,59 @@ func mapChat(m domain.ChatMessage) *roomv1.ChatMessage {
 	return &roomv1.ChatMessage{
 		Id:        m.ID,
 		RoomId:    m.RoomID,
-		UserId:    strconv.FormatInt(m.UserID, 10),
+		UserId:    authorID(m.UserID),
 		Text:      m.Text,
 		CreatedAt: timestamppb.New(m.CreatedAt),
 		ReplyTo:   valueOrEmpty(m.ReplyTo),
+
+		AttachmentIds: m.Attachments,
+	}
+}
+
+func mapAttachment(a *domain.Attachment) *roomv1.Attachment {
+	return &roomv1.Attachment{
+		Id:         a.ID,
+		RoomId:     a.RoomID,
+		UserId:     authorID(a.UserID),
+		MessageId:  valueOrEmpty(a.MessageID),
+		FileName:   a.FileName,
+		MimeType:   a.MimeType,
+		SizeBytes:  a.SizeBytes,
+		StorageKey: a.StorageKey,
+		ThumbKey:   valueOrEmpty(a.ThumbKey),
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+	}
+}
+
+// uidFromMD — userFromMD + разбор x-user-id в int64.
+func uidFromMD(ctx context.Context) (int64, error) {
+	_, userID, err := userFromMD(ctx)
+	if err != nil {
+		return 0, err
+	}
+	uid, err := strconv.ParseInt(userID, 10, 64)
+	if err != nil {
+		return 0, status.Error(codes.Unauthenticated, "invalid x-user-id")
 	}
+	return uid, nil
 }
 
 // -------- methods --------
 
 func (s *Server) CreateRoom(ctx context.Context, in *roomv1.CreateRoomRequest) (*roomv1.CreateRoomResponse, error) {
-	if _, _, err := userFromMD(ctx); err != nil {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
 		return nil, err
 	}
-	room, err := s.roomSvc.CreateRoom(ctx, in.GetName(), in.GetMax())
+	name, desc, topic, lang, tags := in.GetName(), in.GetDescription(), in.GetTopic(), in.GetLanguage(), in.GetTags()
+	room, err := s.roomSvc.CreateRoom(ctx, uid, domain.RoomInput{
+		Name:        &name,
+		Max:         in.GetMax(),
+		Description: &desc,
+		Topic:       &topic,
+		Language:    &lang,
+		Tags:        &tags,
+	})
 	if err != nil {
 		return nil, mapErr(err)
 	}
@@ -139,18 +301,43 @@ func (s *Server) CreateRoom(ctx context.Context, in *roomv1.CreateRoomRequest) (
 	return &roomv1.CreateRoomResponse{Room: mapRoom(room)}, nil
 }
 
-func (s *Server) ListRooms(ctx context.Context, in *roomv1.ListRoomsRequest) (*roomv1.ListRoomsResponse, error) {
-	if _, _, err := userFromMD(ctx); err != nil {
+func (s *Server) UpdateRoom(ctx context.Context, in *roomv1.UpdateRoomRequest) (*roomv1.UpdateRoomResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
 		return nil, err
 	}
-	limit := int(in.GetLimit())
-	if limit <= 0 {
-		limit = 20
+	upd := domain.RoomInput{
+		Name:        in.Name,
+		Description: in.Description,
+		Topic:       in.Topic,
+		Language:    in.Language,
 	}
-	if limit > 50 {
-		limit = 50
+	if in.GetSetTags() {
+		tags := in.GetTags()
+		upd.Tags = &tags
 	}
-	items, cursor, err := s.roomSvc.ListRooms(ctx, limit, in.GetCursor())
+	room, err := s.roomSvc.UpdateRoom(ctx, uid, in.GetId(), upd)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.UpdateRoomResponse{Room: mapRoom(room)}, nil
+}
+
+func (s *Server) ListRooms(ctx context.Context, in *roomv1.ListRoomsRequest) (*roomv1.ListRoomsResponse, error) {
+	if _, _, err := userFromMD(ctx); err != nil {
+		return nil, err
+	}
+	items, cursor, err := s.roomSvc.ListRooms(ctx, domain.RoomFilter{
+		Query:     in.GetQuery(),
+		Tag:       in.GetTag(),
+		Language:  in.GetLanguage(),
+		FreeSeats: in.GetFreeSeats(),
+		LiveNow:   in.GetLive(),
+		Sort:      in.GetSort(),
+		Limit:     int(in.GetLimit()),
+		Cursor:    in.GetCursor(),
+	})
 	if err != nil {
 		return nil, mapErr(err)
 	}
@@ -187,7 +374,20 @@ func (s *Server) JoinRoom(ctx context.Context, in *roomv1.JoinRoomRequest) (*roo
 		return nil, status.Error(codes.Unauthenticated, "invalid x-user-id")
 	}
 
-	if _, err := s.memberSvc.JoinRoom(ctx, in.GetId(), uid); err != nil && !errors.Is(err, domain.ErrAlreadyJoined) {
+	_, err = s.memberSvc.JoinRoom(ctx, in.GetId(), uid)
+	if errors.Is(err, domain.ErrInLobby) {
+		pos, _, perr := s.lobbySvc.Position(ctx, in.GetId(), uid)
+		if perr != nil {
+			return nil, mapErr(perr)
+		}
+		return &roomv1.JoinRoomResponse{
+			RoomId:        in.GetId(),
+			PeerId:        userID,
+			Lobby:         true,
+			LobbyPosition: int32(pos),
+		}, nil
+	}
+	if err != nil && !errors.Is(err, domain.ErrAlreadyJoined) {
 		return nil, mapErr(err)
 	}
 
@@ -217,7 +417,7 @@ func (s *Server) ListParticipants(ctx context.Context, in *roomv1.ListParticipan
 	if _, _, err := userFromMD(ctx); err != nil {
 		return nil, err
 	}
-	parts, err := s.memberSvc.ListParticipants(ctx, in.GetId())
+	parts, err := s.memberSvc.ListParticipantsDetailed(ctx, in.GetId())
 	if err != nil {
 		return nil, mapErr(err)
 	}
@@ -225,11 +425,7 @@ func (s *Server) ListParticipants(ctx context.Context, in *roomv1.ListParticipan
 		Items: make([]*roomv1.Participant, 0, len(parts)),
 	}
 	for _, p := range parts {
-		out.Items = append(out.Items, &roomv1.Participant{
-			UserId:   strconv.FormatInt(p.UserID, 10),
-			JoinedAt: timestamppb.New(p.JoinedAt),
-			LastSeen: timestamppb.New(p.LastSeen),
-		})
+		out.Items = append(out.Items, mapParticipant(p))
 	}
 
 	return out, nil
@@ -264,3 +460,307 @@ func (s *Server) GetChatHistory(ctx context.Context, in *roomv1.GetChatHistoryRe
 
 	return out, nil
 }
+
+func (s *Server) CreateAttachment(ctx context.Context, in *roomv1.CreateAttachmentRequest) (*roomv1.CreateAttachmentResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a := &domain.Attachment{
+		ID:         in.GetId(),
+		RoomID:     in.GetRoomId(),
+		UserID:     uid,
+		FileName:   in.GetFileName(),
+		MimeType:   in.GetMimeType(),
+		SizeBytes:  in.GetSizeBytes(),
+		StorageKey: in.GetStorageKey(),
+	}
+	if tk := strings.TrimSpace(in.GetThumbKey()); tk != "" {
+		a.ThumbKey = &tk
+	}
+	if err := s.attachmentSvc.Register(ctx, a); err != nil {
+		if errors.Is(err, domain.ErrNotInRoom) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, mapErr(err)
+	}
+	used, quota, err := s.attachmentSvc.Usage(ctx, a.RoomID)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.CreateAttachmentResponse{
+		Attachment: mapAttachment(a),
+		UsedBytes:  used,
+		QuotaBytes: quota,
+	}, nil
+}
+
+func (s *Server) GetAttachment(ctx context.Context, in *roomv1.GetAttachmentRequest) (*roomv1.GetAttachmentResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.attachmentSvc.Get(ctx, in.GetRoomId(), in.GetId(), uid)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotInRoom) {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.GetAttachmentResponse{Attachment: mapAttachment(a)}, nil
+}
+
+func (s *Server) SetModerator(ctx context.Context, in *roomv1.SetModeratorRequest) (*roomv1.SetModeratorResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	target, err := strconv.ParseInt(in.GetUserId(), 10, 64)
+	if err != nil || target <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	if err := s.roomSvc.SetModerator(ctx, uid, in.GetRoomId(), target, in.GetGrant()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.SetModeratorResponse{}, nil
+}
+
+func (s *Server) ExportPolls(ctx context.Context, in *roomv1.ExportPollsRequest) (*roomv1.ExportPollsResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.pollSvc.Export(ctx, uid, in.GetRoomId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := &roomv1.ExportPollsResponse{Polls: make([]*roomv1.PollExport, 0, len(items))}
+	for _, it := range items {
+		p := &it.Poll
+		pe := &roomv1.PollExport{
+			Id:        p.ID,
+			Question:  p.Question,
+			Multi:     p.Multi,
+			Quiz:      p.Quiz,
+			Status:    string(p.Status),
+			CreatedAt: timestamppb.New(p.CreatedAt),
+			Voters:    it.Results.Voters,
+		}
+		if p.ClosedAt != nil {
+			pe.ClosedAt = timestamppb.New(*p.ClosedAt)
+		}
+		for _, o := range p.Options {
+			po := &roomv1.PollOption{Idx: int32(o.Idx), Text: o.Text, Correct: o.Correct}
+			if o.Idx < len(it.Results.Counts) {
+				po.Votes = it.Results.Counts[o.Idx]
+			}
+			pe.Options = append(pe.Options, po)
+		}
+		for _, v := range it.Votes {
+			pv := &roomv1.PollVote{
+				UserId:  strconv.FormatInt(v.UserID, 10),
+				Correct: p.Correct(v.Options),
+				VotedAt: timestamppb.New(v.CreatedAt),
+			}
+			for _, i := range v.Options {
+				pv.Options = append(pv.Options, int32(i))
+			}
+			pe.Votes = append(pe.Votes, pv)
+		}
+		out.Polls = append(out.Polls, pe)
+	}
+
+	return out, nil
+}
+
+func (s *Server) SetSchedule(ctx context.Context, in *roomv1.SetScheduleRequest) (*roomv1.SetScheduleResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if in.GetStartsAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "starts_at is required")
+	}
+	sc, err := s.scheduleSvc.Set(ctx, uid, domain.Schedule{
+		RoomID:     in.GetRoomId(),
+		StartsAt:   in.GetStartsAt().AsTime(),
+		Duration:   time.Duration(in.GetDurationSec()) * time.Second,
+		RRule:      in.GetRrule(),
+		Timezone:   in.GetTimezone(),
+		OpenBefore: time.Duration(in.GetOpenBeforeSec()) * time.Second,
+	})
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.SetScheduleResponse{Schedule: mapSchedule(sc)}, nil
+}
+
+func (s *Server) GetSchedule(ctx context.Context, in *roomv1.GetScheduleRequest) (*roomv1.GetScheduleResponse, error) {
+	if _, _, err := userFromMD(ctx); err != nil {
+		return nil, err
+	}
+	sc, err := s.scheduleSvc.Get(ctx, in.GetRoomId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.GetScheduleResponse{Schedule: mapSchedule(sc)}, nil
+}
+
+func (s *Server) DeleteSchedule(ctx context.Context, in *roomv1.DeleteScheduleRequest) (*roomv1.DeleteScheduleResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.scheduleSvc.Delete(ctx, uid, in.GetRoomId()); err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.DeleteScheduleResponse{}, nil
+}
+
+func (s *Server) ListSessions(ctx context.Context, in *roomv1.ListSessionsRequest) (*roomv1.ListSessionsResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// по умолчанию — ближайшие 30 дней
+	from := time.Now()
+	if in.GetFrom() != nil {
+		from = in.GetFrom().AsTime()
+	}
+	to := from.Add(30 * 24 * time.Hour)
+	if in.GetTo() != nil {
+		to = in.GetTo().AsTime()
+	}
+
+	items, err := s.scheduleSvc.Sessions(ctx, uid, in.GetRoomId(), from, to, int(in.GetLimit()))
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListSessionsResponse{Items: make([]*roomv1.Session, 0, len(items))}
+	for _, it := range items {
+		out.Items = append(out.Items, &roomv1.Session{
+			RoomId:   it.RoomID,
+			RoomName: it.RoomName,
+			StartsAt: timestamppb.New(it.StartsAt),
+			EndsAt:   timestamppb.New(it.EndsAt),
+		})
+	}
+
+	return out, nil
+}
+
+func mapSchedule(sc *domain.Schedule) *roomv1.Schedule {
+	out := &roomv1.Schedule{
+		RoomId:        sc.RoomID,
+		RoomName:      sc.RoomName,
+		StartsAt:      timestamppb.New(sc.StartsAt),
+		DurationSec:   int64(sc.Duration / time.Second),
+		Rrule:         sc.RRule,
+		Timezone:      sc.Timezone,
+		OpenBeforeSec: int64(sc.OpenBefore / time.Second),
+		UpdatedAt:     timestamppb.New(sc.UpdatedAt),
+	}
+	if sc.CreatedBy != nil {
+		out.CreatedBy = strconv.FormatInt(*sc.CreatedBy, 10)
+	}
+	return out
+}
+
+func (s *Server) SetLobby(ctx context.Context, in *roomv1.SetLobbyRequest) (*roomv1.SetLobbyResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.lobbySvc.SetEnabled(ctx, uid, in.GetRoomId(), in.GetEnabled()); err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.SetLobbyResponse{}, nil
+}
+
+func (s *Server) GetLobby(ctx context.Context, in *roomv1.GetLobbyRequest) (*roomv1.GetLobbyResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l, err := s.lobbySvc.Get(ctx, uid, in.GetRoomId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := &roomv1.GetLobbyResponse{
+		Enabled: l.Enabled,
+		Queue:   make([]*roomv1.LobbyEntry, 0, len(l.Queue)),
+	}
+	if l.SessionStartedAt != nil {
+		out.SessionStartedAt = timestamppb.New(*l.SessionStartedAt)
+	}
+	for _, e := range l.Queue {
+		out.Queue = append(out.Queue, &roomv1.LobbyEntry{
+			UserId:   strconv.FormatInt(e.UserID, 10),
+			QueuedAt: timestamppb.New(e.QueuedAt),
+		})
+	}
+
+	return out, nil
+}
+
+func (s *Server) AdmitLobby(ctx context.Context, in *roomv1.AdmitLobbyRequest) (*roomv1.AdmitLobbyResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.GetAll() {
+		admitted, err := s.lobbySvc.Start(ctx, uid, in.GetRoomId())
+		if err != nil {
+			return nil, mapErr(err)
+		}
+		out := &roomv1.AdmitLobbyResponse{Admitted: make([]string, 0, len(admitted))}
+		for _, id := range admitted {
+			out.Admitted = append(out.Admitted, strconv.FormatInt(id, 10))
+		}
+		return out, nil
+	}
+
+	target, err := strconv.ParseInt(in.GetUserId(), 10, 64)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+	if err := s.lobbySvc.Admit(ctx, uid, in.GetRoomId(), target); err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &roomv1.AdmitLobbyResponse{Admitted: []string{in.GetUserId()}}, nil
+}
+
+func (s *Server) GetBreakout(ctx context.Context, in *roomv1.GetBreakoutRequest) (*roomv1.GetBreakoutResponse, error) {
+	if _, err := uidFromMD(ctx); err != nil {
+		return nil, err
+	}
+	b, err := s.breakoutSvc.ForRoom(ctx, in.GetRoomId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	out := &roomv1.GetBreakoutResponse{
+		ParentId: b.ParentID,
+		EndsAt:   timestamppb.New(b.EndsAt),
+		Rooms:    make([]*roomv1.BreakoutRoom, 0, len(b.Rooms)),
+	}
+	for _, r := range b.Rooms {
+		item := &roomv1.BreakoutRoom{Id: r.ID, Name: r.Name, UserIds: make([]string, 0, len(r.Users))}
+		for _, uid := range r.Users {
+			item.UserIds = append(item.UserIds, strconv.FormatInt(uid, 10))
+		}
+		out.Rooms = append(out.Rooms, item)
+	}
+
+	return out, nil
+}
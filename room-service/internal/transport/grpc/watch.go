@@ -0,0 +1,176 @@
+package grpcx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+	"github.com/cwrk-planet/room-service/internal/service"
+	"github.com/cwrk-planet/room-service/internal/transport/ws"
+
+	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	watchBuffer    = 256 // событий в очереди подписчика, дальше — ResourceExhausted
+	watchPageLimit = 100 // сколько пропущенных сообщений догружаем за раз
+)
+
+// WatchMembers — доступ и снапшот участников (MemberService).
+type WatchMembers interface {
+	CanWatch(ctx context.Context, roomID string, userID int64) error
+	ListParticipantsDetailed(ctx context.Context, roomID string) ([]service.ParticipantDetailed, error)
+}
+
+// WatchChat — пропущенные сообщения для resume (ChatService).
+type WatchChat interface {
+	Since(ctx context.Context, roomID, afterID string, limit int) ([]domain.ChatMessage, error)
+}
+
+// SetWatch — включает WatchRoom: события берём из того же хаба, что и WS.
+func (s *Server) SetWatch(hub *ws.Hub, members WatchMembers, chat WatchChat) {
+	s.hub = hub
+	s.watchMembers = members
+	s.watchChat = chat
+}
+
+func (s *Server) WatchRoom(in *roomv1.WatchRoomRequest, stream roomv1.RoomService_WatchRoomServer) error {
+	if s.hub == nil {
+		return status.Error(codes.Unimplemented, "watch is disabled")
+	}
+	ctx := stream.Context()
+	uid, err := streamUID(ctx)
+	if err != nil {
+		return err
+	}
+	roomID := in.GetRoomId()
+	if err := s.watchMembers.CanWatch(ctx, roomID, uid); err != nil {
+		return mapErr(err)
+	}
+
+	// подписываемся до снапшота и догонялки, чтобы ничего не упало в щель между ними
+	sub := s.hub.Subscribe(roomID, watchBuffer)
+	defer sub.Close()
+
+	parts, err := s.watchMembers.ListParticipantsDetailed(ctx, roomID)
+	if err != nil {
+		return mapErr(err)
+	}
+	cursor := in.GetCursor()
+	snap := &roomv1.RoomSnapshot{RoomId: roomID, Participants: make([]*roomv1.Participant, 0, len(parts))}
+	for _, p := range parts {
+		snap.Participants = append(snap.Participants, mapParticipant(p))
+	}
+	if err := stream.Send(&roomv1.RoomEvent{
+		Cursor: cursor,
+		At:     timestamppb.Now(),
+		Event:  &roomv1.RoomEvent_State{State: snap},
+	}); err != nil {
+		return err
+	}
+
+	// догоняем чат после cursor; отданные id запоминаем, чтобы не повторить их из подписки
+	replayed := make(map[string]struct{})
+	if cursor != "" {
+		if s.watchChat == nil {
+			return status.Error(codes.Unimplemented, "chat service disabled")
+		}
+		for {
+			msgs, err := s.watchChat.Since(ctx, roomID, cursor, watchPageLimit)
+			if err != nil {
+				if errors.Is(err, domain.ErrMessageNotFound) {
+					return status.Error(codes.InvalidArgument, "unknown cursor")
+				}
+				return mapErr(err)
+			}
+			for _, m := range msgs {
+				cursor = m.ID
+				replayed[m.ID] = struct{}{}
+				if err := stream.Send(&roomv1.RoomEvent{
+					Cursor: cursor,
+					At:     timestamppb.New(m.CreatedAt),
+					Event:  &roomv1.RoomEvent_Chat{Chat: mapChat(m)},
+				}); err != nil {
+					return err
+				}
+			}
+			if len(msgs) < watchPageLimit {
+				break
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Dropped:
+			return status.Error(codes.ResourceExhausted, "watcher is too slow, resume from cursor")
+		case msg := <-sub.C:
+			ev := mapHubEvent(msg)
+			if ev == nil {
+				continue
+			}
+			if chat := ev.GetChat(); chat != nil && chat.GetId() != "" {
+				if _, ok := replayed[chat.GetId()]; ok {
+					continue
+				}
+				cursor = chat.GetId()
+			}
+			ev.Cursor = cursor
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// mapHubEvent — WS-сообщение хаба в protobuf; nil — событие не для WatchRoom.
+func mapHubEvent(msg ws.Message) *roomv1.RoomEvent {
+	ev := &roomv1.RoomEvent{At: timestamppb.Now()}
+	switch p := msg.Payload.(type) {
+	case ws.PeerEventPayload:
+		peer := &roomv1.PeerEvent{RoomId: p.RoomID, UserId: p.UserID}
+		switch msg.Type {
+		case ws.TypePeerJoined:
+			ev.Event = &roomv1.RoomEvent_PeerJoined{PeerJoined: peer}
+		case ws.TypePeerLeft:
+			ev.Event = &roomv1.RoomEvent_PeerLeft{PeerLeft: peer}
+		default:
+			return nil
+		}
+	case ws.ChatPayload:
+		if msg.Type != ws.TypeChat {
+			return nil
+		}
+		if p.TSUnix > 0 {
+			ev.At = timestamppb.New(time.Unix(p.TSUnix, 0))
+		}
+		ev.Event = &roomv1.RoomEvent_Chat{Chat: &roomv1.ChatMessage{
+			Id:            p.MsgID,
+			RoomId:        p.RoomID,
+			UserId:        p.UserID,
+			Text:          p.Message,
+			CreatedAt:     ev.At,
+			AttachmentIds: p.Attachments,
+		}}
+	default:
+		return nil
+	}
+	return ev
+}
+
+func mapParticipant(p service.ParticipantDetailed) *roomv1.Participant {
+	return &roomv1.Participant{
+		UserId:   strconv.FormatInt(p.UserID, 10),
+		JoinedAt: timestamppb.New(p.JoinedAt),
+		LastSeen: timestamppb.New(p.LastSeen),
+		Presence: mapPresence(p.Presence),
+	}
+}
@@ -0,0 +1,109 @@
+package grpcx
+
+import (
+	"context"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+
+	roomv1 "github.com/cwrk-planet/room-service/proto/gen/room/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func (s *Server) CreateWebhook(ctx context.Context, in *roomv1.CreateWebhookRequest) (*roomv1.CreateWebhookResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := s.webhookSvc.CreateSubscription(ctx, uid, in.GetUrl(), in.GetEvents())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.CreateWebhookResponse{Webhook: mapWebhook(*sub), Secret: sub.Secret}, nil
+}
+
+func (s *Server) ListWebhooks(ctx context.Context, _ *roomv1.ListWebhooksRequest) (*roomv1.ListWebhooksResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subs, err := s.webhookSvc.ListSubscriptions(ctx, uid)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListWebhooksResponse{Items: make([]*roomv1.Webhook, 0, len(subs))}
+	for _, sub := range subs {
+		out.Items = append(out.Items, mapWebhook(sub))
+	}
+	return out, nil
+}
+
+func (s *Server) DeleteWebhook(ctx context.Context, in *roomv1.DeleteWebhookRequest) (*roomv1.DeleteWebhookResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookSvc.DeleteSubscription(ctx, uid, in.GetId()); err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.DeleteWebhookResponse{}, nil
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, in *roomv1.ListWebhookDeliveriesRequest) (*roomv1.ListWebhookDeliveriesResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, next, err := s.webhookSvc.ListDeliveries(ctx, uid, in.GetWebhookId(), in.GetStatus(), in.GetCursor(), int(in.GetLimit()))
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	out := &roomv1.ListWebhookDeliveriesResponse{Items: make([]*roomv1.WebhookDelivery, 0, len(items)), NextCursor: next}
+	for _, d := range items {
+		out.Items = append(out.Items, mapDelivery(d))
+	}
+	return out, nil
+}
+
+func (s *Server) RedeliverWebhook(ctx context.Context, in *roomv1.RedeliverWebhookRequest) (*roomv1.RedeliverWebhookResponse, error) {
+	uid, err := uidFromMD(ctx)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.webhookSvc.Redeliver(ctx, uid, in.GetDeliveryId())
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	return &roomv1.RedeliverWebhookResponse{Delivery: mapDelivery(*d)}, nil
+}
+
+func mapWebhook(sub domain.WebhookSubscription) *roomv1.Webhook {
+	return &roomv1.Webhook{
+		Id:        sub.ID,
+		Url:       sub.URL,
+		Events:    sub.Events,
+		Active:    sub.Active,
+		CreatedAt: timestamppb.New(sub.CreatedAt),
+	}
+}
+
+func mapDelivery(d domain.WebhookDelivery) *roomv1.WebhookDelivery {
+	out := &roomv1.WebhookDelivery{
+		Id:            d.ID,
+		WebhookId:     d.SubscriptionID,
+		EventId:       d.EventID,
+		EventType:     d.EventType,
+		Status:        string(d.Status),
+		Attempts:      int32(d.Attempts),
+		NextAttemptAt: timestamppb.New(d.NextAttemptAt),
+		LastError:     valueOrEmpty(d.LastError),
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+	}
+	if d.LastStatus != nil {
+		out.LastStatus = int32(*d.LastStatus)
+	}
+	if d.DeliveredAt != nil {
+		out.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+	return out
+}
@@ -3,15 +3,31 @@ package http
 import "time"
 
 type CreateRoomRequest struct {
-	Name string `json:"name" validate:"required,min=1,max=100"`
-	Max  int64  `json:"max,omitempty"`
+	Name        string   `json:"name" validate:"required,min=1,max=100"`
+	Max         int64    `json:"max,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Topic       string   `json:"topic,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 type RoomItem struct {
 	ID              string    `json:"id"`
 	Name            string    `json:"name"`
 	MaxParticipants int64     `json:"max_participants"`
+	OwnerID         string    `json:"owner_id,omitempty"`
+	ParentID        string    `json:"parent_id,omitempty"`
 	CreatedAt       time.Time `json:"created_at"`
+
+	Description string   `json:"description,omitempty"`
+	Topic       string   `json:"topic,omitempty"`
+	Language    string   `json:"language,omitempty"`
+	Tags        []string `json:"tags"`
+
+	// только в списке
+	Participants *int64     `json:"participants,omitempty"`
+	Online       *int64     `json:"online,omitempty"`
+	ActivityAt   *time.Time `json:"activity_at,omitempty"`
 }
 
 type RoomsListResponse struct {
@@ -22,6 +38,7 @@ type RoomsListResponse struct {
 type JoinRoomResponse struct {
 	RoomID string `json:"room_id"`
 	PeerID string `json:"peer_id"`
+	Lobby  bool   `json:"lobby,omitempty"` // ждёт допуска в лобби
 }
 
 type ParticipantItem struct {
@@ -30,6 +47,17 @@ type ParticipantItem struct {
 	AvatarURL   *string   `json:"avatar_url,omitempty"`
 	JoinedAt    time.Time `json:"joined_at"`
 	LastSeen    time.Time `json:"last_seen"`
+
+	Presence PresenceItem `json:"presence"`
+}
+
+type PresenceItem struct {
+	Status       string     `json:"status"` // online|away|busy|offline
+	Mic          bool       `json:"mic"`
+	Cam          bool       `json:"cam"`
+	Screen       bool       `json:"screen"`
+	HandRaisedAt *time.Time `json:"hand_raised_at,omitempty"`
+	StatusText   string     `json:"status_text,omitempty"`
 }
 
 type ParticipantsResponse struct {
@@ -43,6 +71,8 @@ type ChatMessageItem struct {
 	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
 	ReplyTo   *string   `json:"reply_to,omitempty"`
+
+	Attachments []string `json:"attachments,omitempty"`
 }
 
 type ChatHistoryResponse struct {
@@ -36,6 +36,38 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+func toRoomItem(room *domain.Room) RoomItem {
+	out := RoomItem{
+		ID:              room.ID,
+		Name:            room.Name,
+		MaxParticipants: room.MaxParticipants,
+		CreatedAt:       room.CreatedAt,
+	}
+	if room.OwnerID != nil {
+		out.OwnerID = strconv.FormatInt(*room.OwnerID, 10)
+	}
+	if room.ParentID != nil {
+		out.ParentID = *room.ParentID
+	}
+	if room.Description != nil {
+		out.Description = *room.Description
+	}
+	if room.Topic != nil {
+		out.Topic = *room.Topic
+	}
+	if room.Language != nil {
+		out.Language = *room.Language
+	}
+	out.Tags = room.Tags
+	if out.Tags == nil {
+		out.Tags = []string{}
+	}
+	if !room.ActivityAt.IsZero() {
+		out.Participants, out.Online, out.ActivityAt = &room.Participants, &room.Online, &room.ActivityAt
+	}
+	return out
+}
+
 // POST /rooms
 func (h *Handler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 	var req CreateRoomRequest
@@ -44,49 +76,63 @@ func (h *Handler) CreateRoom(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid json"})
 		return
 	}
-	room, err := h.roomSvc.CreateRoom(r.Context(), req.Name, req.Max)
+	room, err := h.roomSvc.CreateRoom(r.Context(), httpmw.UserIDFromCtx(r.Context()), domain.RoomInput{
+		Name:        &req.Name,
+		Max:         req.Max,
+		Description: &req.Description,
+		Topic:       &req.Topic,
+		Language:    &req.Language,
+		Tags:        &req.Tags,
+	})
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCapacity) || errors.Is(err, domain.ErrRoomInvalid) {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		slog.Error("handler.CreateRoom:", slog.Any("err", err))
 		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, RoomItem{
-		ID:              room.ID,
-		Name:            room.Name,
-		MaxParticipants: room.MaxParticipants,
-		CreatedAt:       room.CreatedAt,
-	})
+	writeJSON(w, http.StatusCreated, toRoomItem(room))
 }
 
-// GET /rooms?limit=&cursor=
+// GET /rooms?limit=&cursor=&q=&tag=&lang=&free=&live=&sort=
 func (h *Handler) ListRooms(w http.ResponseWriter, r *http.Request) {
-	limit := 20
-	if s := r.URL.Query().Get("limit"); s != "" {
+	q := r.URL.Query()
+	f := domain.RoomFilter{
+		Limit:     20,
+		Cursor:    q.Get("cursor"),
+		Query:     q.Get("q"),
+		Tag:       q.Get("tag"),
+		Language:  q.Get("lang"),
+		FreeSeats: q.Get("free") == "true",
+		LiveNow:   q.Get("live") == "true",
+		Sort:      q.Get("sort"),
+	}
+	if s := q.Get("limit"); s != "" {
 		if n, err := strconv.Atoi(s); err == nil {
-			limit = n
+			f.Limit = n
 		}
 	}
-	cursor := r.URL.Query().Get("cursor")
 
-	rooms, next, err := h.roomSvc.ListRooms(r.Context(), limit, cursor)
+	rooms, next, err := h.roomSvc.ListRooms(r.Context(), f)
 	if err != nil {
 		if errors.Is(err, postgres.ErrInvalidCursor) {
 			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: "invalid_cursor"})
 			return
 		}
+		if errors.Is(err, domain.ErrRoomInvalid) {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
 		slog.Error("handler.ListRooms:", slog.Any("err", err))
 		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
 		return
 	}
 	resp := RoomsListResponse{Items: make([]RoomItem, 0, len(rooms)), NextCursor: next}
 	for _, rm := range rooms {
-		resp.Items = append(resp.Items, RoomItem{
-			ID:              rm.ID,
-			Name:            rm.Name,
-			MaxParticipants: rm.MaxParticipants,
-			CreatedAt:       rm.CreatedAt,
-		})
+		resp.Items = append(resp.Items, toRoomItem(&rm))
 	}
 
 	writeJSON(w, http.StatusOK, resp)
@@ -106,12 +152,7 @@ func (h *Handler) GetRoom(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, RoomItem{
-		ID:              room.ID,
-		Name:            room.Name,
-		MaxParticipants: room.MaxParticipants,
-		CreatedAt:       room.CreatedAt,
-	})
+	writeJSON(w, http.StatusOK, toRoomItem(room))
 }
 
 // POST /rooms/{id}/join
@@ -132,6 +173,16 @@ func (h *Handler) JoinRoom(w http.ResponseWriter, r *http.Request) {
 		case errors.Is(err, domain.ErrRoomFull):
 			writeJSON(w, http.StatusConflict, ErrorResponse{Error: "room full"})
 			return
+		case errors.Is(err, domain.ErrRoomClosed):
+			writeJSON(w, http.StatusForbidden, ErrorResponse{Error: "room is closed"})
+			return
+		case errors.Is(err, domain.ErrInLobby):
+			writeJSON(w, http.StatusAccepted, JoinRoomResponse{
+				RoomID: roomID,
+				PeerID: strconv.FormatInt(userID, 10),
+				Lobby:  true,
+			})
+			return
 		case errors.Is(err, domain.ErrAlreadyJoined):
 			// участник уже в комнате
 		default:
@@ -194,6 +245,14 @@ func (h *Handler) GetParticipants(w http.ResponseWriter, r *http.Request) {
 			AvatarURL:   it.AvatarURL,
 			JoinedAt:    it.JoinedAt,
 			LastSeen:    it.LastSeen,
+			Presence: PresenceItem{
+				Status:       string(it.Presence.Status),
+				Mic:          it.Presence.Mic,
+				Cam:          it.Presence.Cam,
+				Screen:       it.Presence.Screen,
+				HandRaisedAt: it.Presence.HandRaisedAt,
+				StatusText:   it.Presence.StatusText,
+			},
 		})
 	}
 
@@ -229,6 +288,8 @@ func (h *Handler) GetChatHistory(w http.ResponseWriter, r *http.Request) {
 			Text:      m.Text,
 			CreatedAt: m.CreatedAt.Truncate(time.Millisecond),
 			ReplyTo:   m.ReplyTo,
+
+			Attachments: m.Attachments,
 		})
 	}
 	writeJSON(w, http.StatusOK, resp)
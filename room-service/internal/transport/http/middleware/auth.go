@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+
+	"github.com/cwrk-planet/authz/pkg/authz"
 )
 
 type ctxKey string
@@ -14,7 +16,8 @@ const (
 	ctxKeyUserID ctxKey = "user_id"
 )
 
-// простая авторизация: требуем Bearer + X-User-ID (UUID), без валидации токена
+// простая авторизация: требуем Bearer + X-User-ID (UUID), без валидации токена.
+// Если перед ним authz.Verifier.Middleware проверил токен — user_id берем из токена
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
@@ -24,6 +27,13 @@ func AuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if p, ok := authz.FromContext(r.Context()); ok {
+			ctx := context.WithValue(r.Context(), ctxKeyToken, strings.TrimSpace(auth[7:]))
+			ctx = context.WithValue(ctx, ctxKeyUserID, p.UserID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		uidHeader := r.Header.Get("X-User-ID")
 		if uidHeader == "" {
 			http.Error(w, `{"error":"missing X-User-ID"}`, http.StatusUnauthorized)
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/cwrk-planet/authz/pkg/authz"
 	"github.com/cwrk-planet/room-service/internal/service"
 	httpmw "github.com/cwrk-planet/room-service/internal/transport/http/middleware"
 	"github.com/cwrk-planet/room-service/internal/transport/ws"
@@ -12,23 +13,28 @@ import (
 	middlewareChi "github.com/go-chi/chi/v5/middleware"
 )
 
-func NewRouter(h *Handler, memberSvc *service.MemberService, wsServer *ws.Server) http.Handler {
+// verifier == nil — токен не проверяется, пользователь из X-User-ID (за gateway)
+func NewRouter(h *Handler, memberSvc *service.MemberService, wsServer *ws.Server, verifier *authz.Verifier) http.Handler {
 	r := chi.NewRouter()
 	r.Use(middlewareChi.RequestID)
 	r.Use(middlewareChi.RealIP)
 	r.Use(middlewareChi.Recoverer)
 
-	// WS endpoint
+	// WS endpoint: access_token из query проверяет сам HandleWS (заголовков у браузерного WS нет)
 	r.Get("/ws/rooms/{id}", wsServer.HandleWS)
 
 	// Все маршруты требуют access_token и user_id
 	r.Group(func(pr chi.Router) {
+		if verifier != nil {
+			// RequireScopes без scopes — просто валидный токен
+			pr.Use(verifier.Middleware, authz.RequireScopes(nil))
+		}
 		pr.Use(httpmw.AuthMiddleware)
 		pr.Use(httpmw.HeartbeatMiddleware(memberSvc))
 		pr.Use(middlewareChi.Timeout(30 * time.Second))
 
 		pr.Route("/rooms", func(rm chi.Router) {
-			rm.Post("/", h.CreateRoom)
+			rm.With(requireScopes(verifier, authz.ScopeRoomsCreate)).Post("/", h.CreateRoom)
 			rm.Get("/", h.ListRooms)
 
 			rm.Route("/{id}", func(rr chi.Router) {
@@ -49,3 +55,11 @@ func NewRouter(h *Handler, memberSvc *service.MemberService, wsServer *ws.Server
 
 	return r
 }
+
+// requireScopes — без verifier проверять нечего, пропускаем
+func requireScopes(verifier *authz.Verifier, scopes ...string) func(http.Handler) http.Handler {
+	if verifier == nil {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	return authz.RequireScopes(nil, scopes...)
+}
@@ -0,0 +1,143 @@
+package ws
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/cwrk-planet/room-service/internal/domain"
+)
+
+// handleBreakout — команды модератора из WS; комната берётся из соединения
+// (основная или любая из групп).
+func (s *Server) handleBreakout(ctx context.Context, c *wsConn, msg Message) error {
+	switch msg.Type {
+	case TypeBreakoutStart:
+		var p BreakoutStartPayload
+		if err := decode(msg.Payload, &p); err != nil {
+			return domain.ErrBreakoutInvalid
+		}
+		in := domain.BreakoutInput{
+			Count:    p.Count,
+			Names:    p.Names,
+			Random:   p.Random,
+			Assign:   make(map[int64]int, len(p.Assign)),
+			Duration: time.Duration(p.DurationSec) * time.Second,
+		}
+		for uidStr, idx := range p.Assign {
+			uid, err := strconv.ParseInt(uidStr, 10, 64)
+			if err != nil {
+				return domain.ErrBreakoutInvalid
+			}
+			in.Assign[uid] = idx
+		}
+		_, err := s.breakouts.Start(ctx, c.userID, c.RoomID(), in)
+		return err
+	case TypeBreakoutAssign, TypeBreakoutJoin:
+		var p BreakoutAssignPayload
+		if err := decode(msg.Payload, &p); err != nil {
+			return domain.ErrBreakoutInvalid
+		}
+		uid := c.userID
+		if msg.Type == TypeBreakoutAssign {
+			var err error
+			if uid, err = strconv.ParseInt(p.UserID, 10, 64); err != nil {
+				return domain.ErrBreakoutInvalid
+			}
+		}
+		_, err := s.breakouts.Assign(ctx, c.userID, c.RoomID(), uid, p.RoomID)
+		return err
+	case TypeBreakoutEnd:
+		return s.breakouts.End(ctx, c.userID, c.RoomID())
+	}
+	return nil
+}
+
+// BreakoutChanged — событие из BreakoutService: переводим соединения и рассылаем состояние.
+func (s *Server) BreakoutChanged(ev domain.BreakoutEvent) {
+	b := ev.Breakout
+	family := append([]string{b.ParentID}, b.Children()...)
+	ctx := context.Background()
+
+	switch ev.Kind {
+	case domain.BreakoutStarted:
+		s.hub.Broadcast(b.ParentID, Message{Type: TypeBreakoutStarted, Payload: mapBreakout(b)})
+		s.moveUsers(ctx, family, ev.Moves)
+	case domain.BreakoutMoved:
+		s.moveUsers(ctx, family, ev.Moves)
+		s.broadcastFamily(family, Message{Type: TypeBreakoutState, Payload: mapBreakout(b)})
+	case domain.BreakoutCountdown:
+		s.broadcastFamily(family, Message{Type: TypeBreakoutCountdown, Payload: BreakoutCountdownPayload{
+			ParentID:    b.ParentID,
+			SecondsLeft: int64(ev.Left / time.Second),
+			EndsAt:      b.EndsAt.Unix(),
+		}})
+	case domain.BreakoutEnded:
+		// возвращаем всех, кто сейчас в группах, а не только распределённых (модераторы ходят сами)
+		for _, roomID := range b.Children() {
+			for _, c := range s.hub.Conns(roomID) {
+				if wc, ok := c.(*wsConn); ok {
+					s.moveConn(ctx, wc, b.ParentID)
+				}
+			}
+		}
+		s.hub.Broadcast(b.ParentID, Message{Type: TypeBreakoutEnded, Payload: PeerEventPayload{RoomID: b.ParentID}})
+	}
+}
+
+// moveUsers — соединения пользователей из moves (где бы в семье комнат они ни были) — в их комнаты.
+func (s *Server) moveUsers(ctx context.Context, family []string, moves map[int64]string) {
+	if len(moves) == 0 {
+		return
+	}
+	for _, roomID := range family {
+		for _, c := range s.hub.Conns(roomID) {
+			wc, ok := c.(*wsConn)
+			if !ok {
+				continue
+			}
+			if to, ok := moves[wc.userID]; ok && slices.Contains(family, to) {
+				s.moveConn(ctx, wc, to)
+			}
+		}
+	}
+}
+
+// moveConn — перевести живое соединение в другую комнату: для остальных это
+// peer_left в старой и peer_joined в новой, самому — breakout_moved и свежий state.
+func (s *Server) moveConn(ctx context.Context, c *wsConn, to string) {
+	c.moveMu.Lock()
+	defer c.moveMu.Unlock()
+
+	from := c.RoomID()
+	if c.isClosed() || from == to {
+		return
+	}
+	s.exitRoom(ctx, c)
+	c.setRoom(to)
+	_ = c.Send(Message{Type: TypeBreakoutMoved, Payload: BreakoutMovedPayload{From: from, To: to}})
+	s.enterRoom(ctx, c)
+}
+
+func (s *Server) broadcastFamily(family []string, msg Message) {
+	for _, roomID := range family {
+		s.hub.Broadcast(roomID, msg)
+	}
+}
+
+func mapBreakout(b *domain.Breakout) BreakoutPayload {
+	out := BreakoutPayload{
+		ParentID: b.ParentID,
+		EndsAt:   b.EndsAt.Unix(),
+		Rooms:    make([]BreakoutRoomPayload, 0, len(b.Rooms)),
+	}
+	for _, r := range b.Rooms {
+		item := BreakoutRoomPayload{ID: r.ID, Name: r.Name, Users: make([]string, 0, len(r.Users))}
+		for _, uid := range r.Users {
+			item.Users = append(item.Users, strconv.FormatInt(uid, 10))
+		}
+		out.Rooms = append(out.Rooms, item)
+	}
+	return out
+}
@@ -0,0 +1,58 @@
+package ws
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/cwrk-planet/events/pkg/events"
+)
+
+// HubSink — доменные события из outbox в WS-хаб. В отличие от peer_joined/peer_left
+// (подключения), member_joined/member_left — факт членства, рассылаются после коммита.
+type HubSink struct {
+	hub *Hub
+}
+
+func NewHubSink(hub *Hub) *HubSink {
+	return &HubSink{hub: hub}
+}
+
+func (s *HubSink) Name() string { return "ws" }
+
+func (s *HubSink) Publish(_ context.Context, env events.Envelope) error {
+	switch env.Type {
+	case events.TypeParticipantJoined:
+		ev, err := events.Decode[events.ParticipantJoined](env)
+		if err != nil {
+			return err
+		}
+		s.hub.Broadcast(ev.RoomID, Message{Type: TypeMemberJoined, Payload: PeerEventPayload{
+			RoomID: ev.RoomID,
+			UserID: strconv.FormatInt(ev.UserID, 10),
+		}})
+	case events.TypeParticipantLeft:
+		ev, err := events.Decode[events.ParticipantLeft](env)
+		if err != nil {
+			return err
+		}
+		s.hub.Broadcast(ev.RoomID, Message{Type: TypeMemberLeft, Payload: PeerEventPayload{
+			RoomID: ev.RoomID,
+			UserID: strconv.FormatInt(ev.UserID, 10),
+		}})
+	case events.TypeUserDeleted:
+		// строки участников уже ушли каскадом вместе с users — member_left шлём
+		// в комнаты, где пользователь был подключён, остальные увидят при следующем state
+		ev, err := events.Decode[events.UserDeleted](env)
+		if err != nil {
+			return err
+		}
+		uid := strconv.FormatInt(ev.UserID, 10)
+		for _, roomID := range s.hub.DisconnectUser(uid) {
+			s.hub.Broadcast(roomID, Message{Type: TypeMemberLeft, Payload: PeerEventPayload{
+				RoomID: roomID,
+				UserID: uid,
+			}})
+		}
+	}
+	return nil
+}
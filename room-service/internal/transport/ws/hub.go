@@ -54,3 +54,101 @@ func (h *Hub) Broadcast(roomID string, msg Message) {
 		}
 	}
 }
+
+// Conns — снимок соединений комнаты.
+func (h *Hub) Conns(roomID string) []Conn {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	out := make([]Conn, 0, len(h.rooms[roomID]))
+	for c := range h.rooms[roomID] {
+		out = append(out, c)
+	}
+	return out
+}
+
+// BroadcastExcept — всем в комнате, кроме соединений пользователя userID
+// (эфемерные события вроде typing самому себе не нужны).
+func (h *Hub) BroadcastExcept(roomID, userID string, msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if rs, ok := h.rooms[roomID]; ok {
+		for c := range rs {
+			if c.UserID() == userID {
+				continue
+			}
+			_ = c.Send(msg) // best-effort
+		}
+	}
+}
+
+// DisconnectUser — закрыть все соединения пользователя во всех комнатах.
+// Возвращает комнаты, где он был подключён; уборку (peer_left, presence) делает readLoop.
+func (h *Hub) DisconnectUser(userID string) []string {
+	h.mu.RLock()
+	var conns []Conn
+	rooms := make(map[string]struct{})
+	for roomID, rs := range h.rooms {
+		for c := range rs {
+			if c.UserID() == userID {
+				conns = append(conns, c)
+				rooms[roomID] = struct{}{}
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range conns {
+		_ = c.Close()
+	}
+	out := make([]string, 0, len(rooms))
+	for roomID := range rooms {
+		out = append(out, roomID)
+	}
+	return out
+}
+
+// Subscription — подписка на рассылки комнаты в обход WS (gRPC WatchRoom).
+// Получает всё, что уходит через Broadcast/BroadcastExcept.
+type Subscription struct {
+	C       <-chan Message
+	Dropped <-chan struct{} // закрывается, если подписчик не успевал читать и события терялись
+
+	cancel func()
+}
+
+// Close — отписаться. C после этого не закрывается, просто больше ничего не придёт.
+func (s *Subscription) Close() { s.cancel() }
+
+// Subscribe — buf событий в очереди; переполнение не тормозит рассылку, а закрывает Dropped.
+func (h *Hub) Subscribe(roomID string, buf int) *Subscription {
+	t := &tap{
+		roomID:  roomID,
+		ch:      make(chan Message, buf),
+		dropped: make(chan struct{}),
+	}
+	h.Add(t)
+	return &Subscription{C: t.ch, Dropped: t.dropped, cancel: func() { h.Remove(t) }}
+}
+
+// tap — Conn без сокета: складывает рассылки в канал подписки.
+type tap struct {
+	roomID  string
+	ch      chan Message
+	dropped chan struct{}
+	once    sync.Once
+}
+
+func (t *tap) Send(msg Message) error {
+	select {
+	case t.ch <- msg:
+	default:
+		t.once.Do(func() { close(t.dropped) })
+	}
+	return nil
+}
+
+func (t *tap) Close() error   { return nil }
+func (t *tap) UserID() string { return "" }
+func (t *tap) RoomID() string { return t.roomID }
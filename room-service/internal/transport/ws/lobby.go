@@ -0,0 +1,126 @@
+package ws
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+type connState int
+
+const (
+	connInRoom  connState = iota
+	connWaiting           // в лобби
+	connDropped           // лобби отказало / распущено
+)
+
+func (s *Server) addWaiting(c *wsConn) {
+	s.lobbyMu.Lock()
+	defer s.lobbyMu.Unlock()
+
+	c.state = connWaiting
+	rs, ok := s.waiting[c.RoomID()]
+	if !ok {
+		rs = make(map[*wsConn]struct{})
+		s.waiting[c.RoomID()] = rs
+	}
+	rs[c] = struct{}{}
+}
+
+func (s *Server) isWaiting(c *wsConn) bool {
+	s.lobbyMu.Lock()
+	defer s.lobbyMu.Unlock()
+	return c.state == connWaiting
+}
+
+// detach — снять соединение из лобби при отключении; возвращает, в каком состоянии оно было.
+func (s *Server) detach(c *wsConn) connState {
+	s.lobbyMu.Lock()
+	defer s.lobbyMu.Unlock()
+
+	st := c.state
+	if st == connWaiting {
+		s.removeWaitingLocked(c)
+		c.state = connDropped
+	}
+	return st
+}
+
+func (s *Server) removeWaitingLocked(c *wsConn) {
+	if rs, ok := s.waiting[c.RoomID()]; ok {
+		delete(rs, c)
+		if len(rs) == 0 {
+			delete(s.waiting, c.RoomID())
+		}
+	}
+}
+
+// LobbyChanged — очередь лобби изменилась (из LobbyService): допущенных переводим
+// в комнату, убранных отключаем, остальным рассылаем новые позиции.
+func (s *Server) LobbyChanged(roomID string, admitted, removed []int64) {
+	var enter, drop []*wsConn
+
+	s.lobbyMu.Lock()
+	for c := range s.waiting[roomID] {
+		switch {
+		case slices.Contains(admitted, c.userID):
+			c.state = connInRoom
+			enter = append(enter, c)
+		case slices.Contains(removed, c.userID):
+			c.state = connDropped
+			drop = append(drop, c)
+		default:
+			continue
+		}
+		s.removeWaitingLocked(c)
+	}
+	s.lobbyMu.Unlock()
+
+	ctx := context.Background()
+	for _, c := range enter {
+		_ = c.Send(Message{Type: TypeLobbyAdmitted, Payload: PeerEventPayload{RoomID: roomID, UserID: c.UserID()}})
+		s.enterRoom(ctx, c)
+	}
+	for _, c := range drop {
+		_ = c.Send(Message{Type: TypeLobbyDenied, Payload: PeerEventPayload{RoomID: roomID, UserID: c.UserID()}})
+		_ = c.Close()
+	}
+
+	s.broadcastLobby(ctx, roomID)
+}
+
+// broadcastLobby — ожидающим их позиции, комнате — очередь целиком.
+func (s *Server) broadcastLobby(ctx context.Context, roomID string) {
+	queue, err := s.lobby.Queue(ctx, roomID)
+	if err != nil {
+		slog.Warn("ws lobby queue failed", "room", roomID, "err", err)
+		return
+	}
+
+	pos := make(map[int64]int, len(queue))
+	out := LobbyQueuePayload{RoomID: roomID, Queue: make([]LobbyQueueItem, 0, len(queue))}
+	for i, e := range queue {
+		pos[e.UserID] = i + 1
+		out.Queue = append(out.Queue, LobbyQueueItem{
+			UserID:   strconv.FormatInt(e.UserID, 10),
+			QueuedAt: e.QueuedAt.Unix(),
+		})
+	}
+
+	s.lobbyMu.Lock()
+	conns := make([]*wsConn, 0, len(s.waiting[roomID]))
+	for c := range s.waiting[roomID] {
+		conns = append(conns, c)
+	}
+	s.lobbyMu.Unlock()
+
+	for _, c := range conns {
+		_ = c.Send(Message{Type: TypeLobbyState, Payload: LobbyStatePayload{
+			RoomID:   roomID,
+			Position: pos[c.userID],
+			Waiting:  len(queue),
+		}})
+	}
+	s.hub.Broadcast(roomID, Message{Type: TypeLobbyQueue, Payload: out})
+}
@@ -2,11 +2,61 @@ package ws
 
 // Типы событий, которые поступают в WS
 const (
-	TypeState      = "state"       // снапшот всех участников
-	TypePeerJoined = "peer_joined" // пользователь присоединился
-	TypePeerLeft   = "peer_left"   // пользователь покинул
-	TypeChat       = "chat"        // чат-сообщение
-	TypeChatAck    = "chat_ack"    // подтверждение отправки (НЕ сообщение)
+	TypeState        = "state"         // снапшот всех участников
+	TypePeerJoined   = "peer_joined"   // пользователь присоединился
+	TypePeerLeft     = "peer_left"     // пользователь покинул
+	TypeMemberJoined = "member_joined" // стал участником комнаты (room_participants, из outbox)
+	TypeMemberLeft   = "member_left"   // перестал быть участником комнаты
+	TypeChat         = "chat"          // чат-сообщение
+	TypeChatAck      = "chat_ack"      // подтверждение отправки (НЕ сообщение)
+	TypeError        = "error"         // ошибка обработки события клиента (только отправителю)
+
+	TypeTypingStart = "typing_start" // начал печатать (эфемерно, не сохраняется)
+	TypeTypingStop  = "typing_stop"  // перестал печатать / истёк таймаут
+	TypeReadMarker  = "read_marker"  // клиент: "прочитал до msg_id"; сервер: рассылка маркера
+
+	TypePresenceUpdate = "presence_update" // клиент: изменить свой presence; сервер: дельта всем
+
+	// очередь "поднятых рук"
+	TypeHandRaise    = "hand_raise"    // клиент: встать в очередь
+	TypeHandLower    = "hand_lower"    // клиент: выйти из очереди (модератор — убрать user_id)
+	TypeFloorNext    = "floor_next"    // модератор: слово первому в очереди
+	TypeFloorGrant   = "floor_grant"   // модератор: слово конкретному user_id
+	TypeFloorRelease = "floor_release" // выступающий/модератор: закончить выступление
+	TypeHandQueue    = "hand_queue"    // сервер: состояние очереди целиком после любого изменения
+
+	// опросы/квизы
+	TypePollCreate  = "poll_create"  // модератор: создать опрос
+	TypePollVote    = "poll_vote"    // участник: проголосовать
+	TypePollClose   = "poll_close"   // модератор: закрыть опрос
+	TypePollCreated = "poll_created" // сервер: новый опрос (без правильных ответов)
+	TypePollVoted   = "poll_voted"   // сервер: голос принят (только голосовавшему)
+	TypePollResults = "poll_results" // сервер: текущие результаты (results=live)
+	TypePollClosed  = "poll_closed"  // сервер: опрос закрыт + итоги и правильные ответы
+
+	// лобби
+	TypeLobbyState    = "lobby_state"     // сервер: ожидающему — его место в очереди
+	TypeLobbyQueue    = "lobby_queue"     // сервер: участникам комнаты — кто ждёт
+	TypeLobbyAdmit    = "lobby_admit"     // модератор: пустить user_id
+	TypeLobbyAdmitAll = "lobby_admit_all" // модератор: начать занятие и пустить всех
+	TypeLobbyDeny     = "lobby_deny"      // модератор: убрать user_id из лобби
+	TypeLobbyAdmitted = "lobby_admitted"  // сервер: ожидающего пустили, дальше придёт state
+	TypeLobbyDenied   = "lobby_denied"    // сервер: ожидающему отказали, соединение закрывается
+
+	// breakout-комнаты
+	TypeBreakoutStart     = "breakout_start"     // модератор: разбить комнату на группы
+	TypeBreakoutAssign    = "breakout_assign"    // модератор: перевести user_id в группу (room_id основной — вернуть)
+	TypeBreakoutJoin      = "breakout_join"      // модератор: перейти самому в группу / основную комнату
+	TypeBreakoutEnd       = "breakout_end"       // модератор: досрочно вернуть всех
+	TypeBreakoutStarted   = "breakout_started"   // сервер: группы созданы, дальше переводы
+	TypeBreakoutState     = "breakout_state"     // сервер: распределение изменилось
+	TypeBreakoutMoved     = "breakout_moved"     // сервер: это соединение переведено в room_id, дальше придёт state
+	TypeBreakoutCountdown = "breakout_countdown" // сервер: сколько осталось до возврата
+	TypeBreakoutEnded     = "breakout_ended"     // сервер: группы закрыты, все в основной комнате
+
+	// расписание
+	TypeSessionOpened = "session_opened" // сервер: началось окно занятия
+	TypeSessionClosed = "session_closed" // сервер: занятие закончилось, не-модераторы отключаются
 )
 
 type Message struct {
@@ -17,12 +67,32 @@ type Message struct {
 type StatePayload struct {
 	RoomID       string                 `json:"room_id"`
 	Participants []ParticipantStateItem `json:"participants"`
+
+	ReadMarkers []ReadMarkerPayload `json:"read_markers"`
+	HandQueue   HandQueuePayload    `json:"hand_queue"`
+	Polls       []PollPayload       `json:"polls"`    // открытые опросы
+	Lobby       []LobbyQueueItem    `json:"lobby"`    // ожидающие допуска
+	Breakout    *BreakoutPayload    `json:"breakout"` // null — группы не запущены
+	// UnreadCount — непрочитанные чужие сообщения для получателя снапшота.
+	UnreadCount int64 `json:"unread_count"`
 }
 
 type ParticipantStateItem struct {
 	UserID   string `json:"user_id"`
 	JoinedAt int64  `json:"joined_at_unix"`
 	LastSeen int64  `json:"last_seen_unix"`
+
+	Presence PresenceState `json:"presence"`
+}
+
+// PresenceState — полный presence (в снапшоте state).
+type PresenceState struct {
+	Status       string `json:"status"`
+	Mic          bool   `json:"mic"`
+	Cam          bool   `json:"cam"`
+	Screen       bool   `json:"screen"`
+	HandRaisedAt int64  `json:"hand_raised_at_unix,omitempty"`
+	StatusText   string `json:"status_text,omitempty"`
 }
 
 type PeerEventPayload struct {
@@ -37,9 +107,170 @@ type ChatPayload struct {
 
 	MsgID  string `json:"msg_id,omitempty"`
 	TSUnix int64  `json:"ts_unix,omitempty"`
+
+	// id вложений, загруженных через api-gateway
+	Attachments []string `json:"attachments,omitempty"`
 }
 
 // для client: использует для снятия pending и дедупликации;
 type ChatAckPayload struct {
 	MsgID string `json:"msg_id"`
 }
+
+type ErrorPayload struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type TypingPayload struct {
+	RoomID string `json:"room_id"`
+	UserID string `json:"user_id"`
+}
+
+type ReadMarkerPayload struct {
+	RoomID string `json:"room_id,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+	MsgID  string `json:"msg_id"`
+	TSUnix int64  `json:"ts_unix,omitempty"`
+}
+
+// PresenceUpdatePayload — дельта: присутствуют только изменившиеся поля.
+// От клиента приходит то же самое без room_id/user_id/hand_raised_at_unix.
+type PresenceUpdatePayload struct {
+	RoomID string `json:"room_id,omitempty"`
+	UserID string `json:"user_id,omitempty"`
+
+	Status       *string `json:"status,omitempty"`
+	Mic          *bool   `json:"mic,omitempty"`
+	Cam          *bool   `json:"cam,omitempty"`
+	Screen       *bool   `json:"screen,omitempty"`
+	HandRaised   *bool   `json:"hand_raised,omitempty"`
+	HandRaisedAt *int64  `json:"hand_raised_at_unix,omitempty"`
+	StatusText   *string `json:"status_text,omitempty"`
+}
+
+type HandQueuePayload struct {
+	RoomID string          `json:"room_id"`
+	Queue  []HandQueueItem `json:"queue"`
+	Floor  *FloorPayload   `json:"floor"` // null — никто не выступает
+}
+
+type HandQueueItem struct {
+	UserID   string `json:"user_id"`
+	RaisedAt int64  `json:"raised_at_unix"`
+}
+
+type FloorPayload struct {
+	UserID    string `json:"user_id"`
+	GrantedAt int64  `json:"granted_at_unix"`
+	ExpiresAt int64  `json:"expires_at_unix,omitempty"`
+}
+
+// HandCommandPayload — параметры hand_lower / floor_next / floor_grant.
+type HandCommandPayload struct {
+	UserID      string `json:"user_id,omitempty"`
+	DurationSec int64  `json:"duration_sec,omitempty"` // 0 — без таймера
+}
+
+type PollCreatePayload struct {
+	Question    string              `json:"question"`
+	Options     []PollOptionPayload `json:"options"`
+	Multi       bool                `json:"multi,omitempty"`
+	Quiz        bool                `json:"quiz,omitempty"`
+	Results     string              `json:"results,omitempty"` // live|on_close
+	DurationSec int64               `json:"duration_sec,omitempty"`
+}
+
+type PollPayload struct {
+	ID          string              `json:"poll_id"`
+	RoomID      string              `json:"room_id"`
+	Question    string              `json:"question"`
+	Options     []PollOptionPayload `json:"options"`
+	Multi       bool                `json:"multi"`
+	Quiz        bool                `json:"quiz"`
+	Results     string              `json:"results"`
+	Status      string              `json:"status"`
+	ClosesAt    int64               `json:"closes_at_unix,omitempty"`
+	CreatedAt   int64               `json:"created_at_unix"`
+	MyVote      []int               `json:"my_vote,omitempty"` // только в state
+	ResultsData *PollResultsPayload `json:"results_data,omitempty"`
+}
+
+type PollOptionPayload struct {
+	Text    string `json:"text"`
+	Correct *bool  `json:"correct,omitempty"` // от модератора при создании; клиентам — только после закрытия
+}
+
+type PollVotePayload struct {
+	PollID  string `json:"poll_id"`
+	Options []int  `json:"options"`
+}
+
+type PollClosePayload struct {
+	PollID string `json:"poll_id"`
+}
+
+type PollResultsPayload struct {
+	PollID string  `json:"poll_id"`
+	Voters int64   `json:"voters"`
+	Counts []int64 `json:"counts"` // по индексу варианта
+}
+
+type SessionPayload struct {
+	RoomID   string `json:"room_id"`
+	StartsAt int64  `json:"starts_at_unix,omitempty"`
+	EndsAt   int64  `json:"ends_at_unix,omitempty"`
+}
+
+type LobbyStatePayload struct {
+	RoomID   string `json:"room_id"`
+	Position int    `json:"position"` // с 1
+	Waiting  int    `json:"waiting"`  // всего в очереди
+}
+
+type LobbyQueuePayload struct {
+	RoomID string           `json:"room_id"`
+	Queue  []LobbyQueueItem `json:"queue"`
+}
+
+type LobbyQueueItem struct {
+	UserID   string `json:"user_id"`
+	QueuedAt int64  `json:"queued_at_unix"`
+}
+
+type BreakoutStartPayload struct {
+	Count       int            `json:"count"`
+	Names       []string       `json:"names,omitempty"`
+	Random      bool           `json:"random,omitempty"`
+	Assign      map[string]int `json:"assign,omitempty"` // user_id -> индекс группы
+	DurationSec int64          `json:"duration_sec"`
+}
+
+// BreakoutAssignPayload — breakout_assign (user_id + room_id) и breakout_join (только room_id).
+type BreakoutAssignPayload struct {
+	UserID string `json:"user_id,omitempty"`
+	RoomID string `json:"room_id"`
+}
+
+type BreakoutPayload struct {
+	ParentID string                `json:"parent_id"`
+	EndsAt   int64                 `json:"ends_at_unix"`
+	Rooms    []BreakoutRoomPayload `json:"rooms"`
+}
+
+type BreakoutRoomPayload struct {
+	ID    string   `json:"room_id"`
+	Name  string   `json:"name"`
+	Users []string `json:"users"`
+}
+
+type BreakoutMovedPayload struct {
+	From string `json:"from_room_id"`
+	To   string `json:"room_id"`
+}
+
+type BreakoutCountdownPayload struct {
+	ParentID    string `json:"parent_id"`
+	SecondsLeft int64  `json:"seconds_left"`
+	EndsAt      int64  `json:"ends_at_unix"`
+}
@@ -3,12 +3,15 @@ package ws
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/cwrk-planet/authz/pkg/authz"
 	"github.com/cwrk-planet/room-service/internal/domain"
 
 	"github.com/go-chi/chi/v5"
@@ -19,26 +22,94 @@ type MemberSvc interface {
 	ListParticipants(ctx context.Context, roomID string) ([]domain.Participant, error)
 	TouchHeartbeat(ctx context.Context, roomID string, userID int64) error
 	LeaveRoom(ctx context.Context, roomID string, userID int64) error
+	CheckWindow(ctx context.Context, roomID string, userID int64) error
 }
 
 type ChatSvc interface {
-	Save(ctx context.Context, roomID string, userID int64, text string) (msgID string, createdAt time.Time, err error)
+	Save(ctx context.Context, roomID string, userID int64, text string, attachments []string) (msgID string, createdAt time.Time, err error)
+	MarkRead(ctx context.Context, roomID string, userID int64, msgID string) (*domain.ReadMarker, bool, error)
+	ReadMarkers(ctx context.Context, roomID string) ([]domain.ReadMarker, error)
+	UnreadCount(ctx context.Context, roomID string, userID int64) (int64, error)
 }
 
+type PresenceSvc interface {
+	Connect(ctx context.Context, roomID string, userID int64) (domain.Presence, domain.PresenceDelta, error)
+	Disconnect(ctx context.Context, roomID string, userID int64) (delta domain.PresenceDelta, last bool)
+	Update(roomID string, userID int64, upd domain.PresenceUpdate) (domain.Presence, domain.PresenceDelta, error)
+	Snapshot(roomID string) map[int64]domain.Presence
+}
+
+type HandQueueSvc interface {
+	State(ctx context.Context, roomID string) (*domain.HandQueue, error)
+	Raise(ctx context.Context, roomID string, userID int64) (bool, error)
+	Lower(ctx context.Context, actorID int64, roomID string, userID int64) (bool, error)
+	Next(ctx context.Context, actorID int64, roomID string, d time.Duration) (*domain.Floor, error)
+	Grant(ctx context.Context, actorID int64, roomID string, userID int64, d time.Duration) (*domain.Floor, error)
+	Release(ctx context.Context, actorID int64, roomID string) (bool, error)
+	ReleaseOwn(ctx context.Context, roomID string, userID int64) (bool, error)
+}
+
+type PollSvc interface {
+	Create(ctx context.Context, actorID int64, roomID string, in domain.PollInput) (*domain.Poll, error)
+	Vote(ctx context.Context, roomID string, userID int64, pollID string, options []int) (*domain.Poll, error)
+	Close(ctx context.Context, actorID int64, roomID, pollID string) (*domain.Poll, bool, error)
+	Results(ctx context.Context, p *domain.Poll) (*domain.PollResults, error)
+	OpenPolls(ctx context.Context, roomID string) ([]domain.Poll, error)
+	UserVote(ctx context.Context, pollID string, userID int64) ([]int, error)
+}
+
+type LobbySvc interface {
+	Enter(ctx context.Context, roomID string, userID int64) (waiting bool, pos int, err error)
+	Queue(ctx context.Context, roomID string) ([]domain.LobbyEntry, error)
+	Admit(ctx context.Context, actorID int64, roomID string, userID int64) error
+	Start(ctx context.Context, actorID int64, roomID string) ([]int64, error)
+	Deny(ctx context.Context, actorID int64, roomID string, userID int64) error
+}
+
+type BreakoutSvc interface {
+	Start(ctx context.Context, actorID int64, roomID string, in domain.BreakoutInput) (*domain.Breakout, error)
+	Assign(ctx context.Context, actorID int64, roomID string, userID int64, targetID string) (*domain.Breakout, error)
+	End(ctx context.Context, actorID int64, roomID string) error
+	ForRoom(ctx context.Context, roomID string) (*domain.Breakout, error)
+	Resolve(ctx context.Context, roomID string, userID int64) (string, error)
+}
+
+const (
+	typingTTL      = 6 * time.Second // клиент шлёт typing_start раз в ~3s, пока печатает
+	typingThrottle = 3 * time.Second
+)
+
 type Server struct {
 	upgrader  websocket.Upgrader
 	hub       *Hub
 	memberSvc MemberSvc
 	chatSvc   ChatSvc
+	presence  PresenceSvc
+	hands     HandQueueSvc
+	polls     PollSvc
+	lobby     LobbySvc
+	breakouts BreakoutSvc
+	typing    *typingTracker
+	verifier  *authz.Verifier // nil — WS закрыт, см. SetVerifier
+
+	// соединения из лобби: в hub их нет, пока не допустят
+	lobbyMu sync.Mutex
+	waiting map[string]map[*wsConn]struct{}
 
 	pingEvery time.Duration
 }
 
-func NewServer(hub *Hub, member MemberSvc, chat ChatSvc) *Server {
-	return &Server{
+func NewServer(hub *Hub, member MemberSvc, chat ChatSvc, presence PresenceSvc, hands HandQueueSvc, polls PollSvc, lobby LobbySvc, breakouts BreakoutSvc) *Server {
+	s := &Server{
 		hub:       hub,
 		memberSvc: member,
 		chatSvc:   chat,
+		presence:  presence,
+		hands:     hands,
+		polls:     polls,
+		lobby:     lobby,
+		breakouts: breakouts,
+		waiting:   make(map[string]map[*wsConn]struct{}),
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
 			WriteBufferSize: 1024,
@@ -46,28 +117,65 @@ func NewServer(hub *Hub, member MemberSvc, chat ChatSvc) *Server {
 		},
 		pingEvery: 15 * time.Second,
 	}
+	s.typing = newTypingTracker(typingTTL, typingThrottle, func(roomID string, userID int64) {
+		s.broadcastTyping(TypeTypingStop, roomID, userID)
+	})
+
+	return s
 }
 
-// WS endpoint: GET /ws/rooms/{id}?access_token=...&user_id=...
+// SetTypingTimeouts — через сколько гасить "печатает" без typing_stop и как часто рассылать typing_start.
+func (s *Server) SetTypingTimeouts(ttl, throttle time.Duration) {
+	if ttl > 0 {
+		s.typing.ttl = ttl
+	}
+	if throttle >= 0 {
+		s.typing.throttle = throttle
+	}
+}
+
+// SetVerifier — проверка access_token при подключении. Без него HandleWS отвечает 401:
+// модераторские команды (слово, лобби, опросы, breakout) авторизуются по пользователю соединения.
+func (s *Server) SetVerifier(v *authz.Verifier) {
+	s.verifier = v
+}
+
+// WS endpoint: GET /ws/rooms/{id}?access_token=...
+// Браузер не умеет заголовки в WebSocket, поэтому токен в query; пользователь — только из него.
 func (s *Server) HandleWS(w http.ResponseWriter, r *http.Request) {
-	q := r.URL.Query()
-	accessToken := strings.TrimSpace(q.Get("access_token"))
-	userIDStr := strings.TrimSpace(q.Get("user_id"))
-	if accessToken == "" {
-		http.Error(w, "missing access_token", http.StatusUnauthorized)
+	if s.verifier == nil {
+		http.Error(w, "access tokens are not verified", http.StatusUnauthorized)
 		return
 	}
-	uid, err := strconv.ParseInt(userIDStr, 10, 64)
-	if err != nil || uid <= 0 {
-		http.Error(w, "invalid user_id", http.StatusUnauthorized)
+	p, err := s.verifier.Verify(strings.TrimSpace(r.URL.Query().Get("access_token")))
+	if err != nil {
+		http.Error(w, "invalid access_token", http.StatusUnauthorized)
 		return
 	}
+	uid := p.UserID
 	roomID := chi.URLParam(r, "id")
 	if roomID == "" {
 		http.Error(w, "missing room id", http.StatusBadRequest)
 		return
 	}
 
+	if err := s.memberSvc.CheckWindow(r.Context(), roomID, uid); err != nil {
+		if errors.Is(err, domain.ErrRoomClosed) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		slog.Warn("ws schedule check failed", "room", roomID, "user", uid, "err", err)
+	}
+	// идёт breakout: подключаем сразу в свою группу (или обратно в основную)
+	if to, err := s.breakouts.Resolve(r.Context(), roomID, uid); err == nil {
+		roomID = to
+	} else if errors.Is(err, domain.ErrRoomNotFound) {
+		http.Error(w, "room not found", http.StatusNotFound)
+		return
+	} else {
+		slog.Warn("ws breakout resolve failed", "room", roomID, "user", uid, "err", err)
+	}
+
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		slog.Warn("ws upgrade failed", "err", err)
@@ -76,77 +184,193 @@ func (s *Server) HandleWS(w http.ResponseWriter, r *http.Request) {
 	}
 
 	c := newWsConn(conn, roomID, uid)
+
+	waiting, _, err := s.lobby.Enter(r.Context(), roomID, uid)
+	if err != nil {
+		slog.Warn("ws lobby enter failed", "room", roomID, "user", uid, "err", err)
+	}
+	if waiting {
+		s.addWaiting(c)
+		// могли пустить между Enter и addWaiting — тогда LobbyChanged нас не нашёл
+		if still, _, err := s.lobby.Enter(r.Context(), roomID, uid); err == nil && !still {
+			s.LobbyChanged(roomID, []int64{uid}, nil)
+		} else {
+			s.broadcastLobby(r.Context(), roomID)
+		}
+	} else {
+		s.enterRoom(r.Context(), c)
+	}
+
+	go s.writeLoop(r.Context(), c)
+	s.readLoop(r.Context(), c)
+
+	// r.Context() к этому моменту может быть уже отменён
+	ctx := context.WithoutCancel(r.Context())
+	c.moveMu.Lock() // дожидаемся перехода между комнатами, если он сейчас идёт
+	defer c.moveMu.Unlock()
+	switch s.detach(c) {
+	case connWaiting:
+		// ушёл из лобби, не дождавшись: очередь сдвинется через LobbyChanged
+		if err := s.memberSvc.LeaveRoom(ctx, roomID, uid); err != nil {
+			slog.Debug("ws leave lobby failed", "room", roomID, "user", uid, "err", err)
+		}
+	case connInRoom:
+		<-c.entered
+		s.leaveRoom(ctx, c)
+	}
+
+	if err := c.Close(); err != nil {
+		slog.Debug("ws close failed", "room", roomID, "user", uid, "err", err)
+	}
+}
+
+// enterRoom — подключение становится участником: hub, presence, state и рассылки.
+func (s *Server) enterRoom(ctx context.Context, c *wsConn) {
+	defer c.markEntered()
+	idStr := strconv.FormatInt(c.userID, 10)
 	s.hub.Add(c)
 
-	if err := s.sendState(r.Context(), c); err != nil {
-		slog.Warn("ws send initial state failed", "room", roomID, "user", uid, "err", err)
+	_, joinDelta, err := s.presence.Connect(ctx, c.RoomID(), c.userID)
+	if err != nil {
+		slog.Warn("ws presence connect failed", "room", c.RoomID(), "user", c.userID, "err", err)
+	}
+
+	if err := s.sendState(ctx, c); err != nil {
+		slog.Warn("ws send initial state failed", "room", c.RoomID(), "user", c.userID, "err", err)
 	}
 
 	// peer_joined
-	s.hub.Broadcast(roomID, Message{
+	s.hub.Broadcast(c.RoomID(), Message{
 		Type: TypePeerJoined,
 		Payload: PeerEventPayload{
-			RoomID: roomID,
-			UserID: userIDStr,
+			RoomID: c.RoomID(),
+			UserID: idStr,
 		},
 	})
 
-	go s.writeLoop(r.Context(), c)
-	s.readLoop(r.Context(), c)
+	s.broadcastPresence(c.RoomID(), c.userID, joinDelta)
+}
 
-	s.hub.Remove(c)
+func (s *Server) leaveRoom(ctx context.Context, c *wsConn) {
+	s.exitRoom(ctx, c)
+	if err := s.memberSvc.LeaveRoom(ctx, c.RoomID(), c.userID); err != nil {
+		slog.Debug("ws leave room failed", "room", c.RoomID(), "user", c.userID, "err", err)
+	}
+}
 
-	if err := s.memberSvc.LeaveRoom(r.Context(), roomID, uid); err != nil {
-		slog.Debug("ws leave room failed", "room", roomID, "user", uid, "err", err)
+// exitRoom — убрать подключение из комнаты без удаления участника из room_participants
+// (при переходе в breakout строку переносит BreakoutService).
+func (s *Server) exitRoom(ctx context.Context, c *wsConn) {
+	roomID, uid := c.RoomID(), c.userID
+	s.hub.Remove(c)
+	if s.typing.stop(roomID, uid) {
+		s.broadcastTyping(TypeTypingStop, roomID, uid)
+	}
+	if leftDelta, last := s.presence.Disconnect(ctx, roomID, uid); last {
+		s.broadcastPresence(roomID, uid, leftDelta)
+		s.leaveHandQueue(ctx, roomID, uid)
 	}
 	s.hub.Broadcast(roomID, Message{
 		Type: TypePeerLeft,
 		Payload: PeerEventPayload{
 			RoomID: roomID,
-			UserID: userIDStr,
+			UserID: strconv.FormatInt(uid, 10),
 		},
 	})
-
-	if err := c.Close(); err != nil {
-		slog.Debug("ws close failed", "room", roomID, "user", uid, "err", err)
-	}
 }
 
 func (s *Server) sendState(ctx context.Context, c *wsConn) error {
-	parts, err := s.memberSvc.ListParticipants(ctx, c.roomID)
+	parts, err := s.memberSvc.ListParticipants(ctx, c.RoomID())
 	if err != nil {
 		return err
 	}
+	live := s.presence.Snapshot(c.RoomID())
 	items := make([]ParticipantStateItem, 0, len(parts))
 	for _, p := range parts {
 		idStr := strconv.FormatInt(p.UserID, 10)
+		pr, ok := live[p.UserID]
+		if !ok {
+			pr = domain.Presence{Status: domain.PresenceOffline}
+		}
 		items = append(items, ParticipantStateItem{
 			UserID:   idStr,
 			JoinedAt: p.JoinedAt.Unix(),
 			LastSeen: p.LastSeen.Unix(),
+			Presence: mapPresenceState(pr),
 		})
 	}
 
-	return c.Send(Message{
-		Type: TypeState,
-		Payload: StatePayload{
-			RoomID:       c.roomID,
-			Participants: items,
-		},
-	})
+	state := StatePayload{
+		RoomID:       c.RoomID(),
+		Participants: items,
+		ReadMarkers:  []ReadMarkerPayload{},
+	}
+	if s.chatSvc != nil {
+		markers, err := s.chatSvc.ReadMarkers(ctx, c.RoomID())
+		if err != nil {
+			return err
+		}
+		for _, m := range markers {
+			state.ReadMarkers = append(state.ReadMarkers, mapReadMarker(m))
+		}
+		if state.UnreadCount, err = s.chatSvc.UnreadCount(ctx, c.RoomID(), c.userID); err != nil {
+			return err
+		}
+	}
+	hq, err := s.hands.State(ctx, c.RoomID())
+	if err != nil {
+		return err
+	}
+	state.HandQueue = mapHandQueue(hq)
+
+	lobby, err := s.lobby.Queue(ctx, c.RoomID())
+	if err != nil {
+		return err
+	}
+	state.Lobby = make([]LobbyQueueItem, 0, len(lobby))
+	for _, e := range lobby {
+		state.Lobby = append(state.Lobby, LobbyQueueItem{UserID: strconv.FormatInt(e.UserID, 10), QueuedAt: e.QueuedAt.Unix()})
+	}
+
+	if b, err := s.breakouts.ForRoom(ctx, c.RoomID()); err == nil {
+		bp := mapBreakout(b)
+		state.Breakout = &bp
+	} else if !errors.Is(err, domain.ErrBreakoutNotFound) {
+		return err
+	}
+
+	polls, err := s.polls.OpenPolls(ctx, c.RoomID())
+	if err != nil {
+		return err
+	}
+	state.Polls = make([]PollPayload, 0, len(polls))
+	for i := range polls {
+		pp := mapPoll(&polls[i])
+		if pp.MyVote, err = s.polls.UserVote(ctx, polls[i].ID, c.userID); err != nil {
+			return err
+		}
+		if polls[i].ResultsMode == domain.PollResultsLive {
+			if res, err := s.polls.Results(ctx, &polls[i]); err == nil {
+				pp.ResultsData = mapPollResults(res)
+			}
+		}
+		state.Polls = append(state.Polls, pp)
+	}
+
+	return c.Send(Message{Type: TypeState, Payload: state})
 }
 
 func (s *Server) readLoop(ctx context.Context, c *wsConn) {
 	defer func() { _ = c.Close() }()
 	idStr := strconv.FormatInt(c.userID, 10)
 
-	_ = s.memberSvc.TouchHeartbeat(ctx, c.roomID, c.userID)
+	_ = s.memberSvc.TouchHeartbeat(ctx, c.RoomID(), c.userID)
 
 	c.conn.SetReadLimit(1 << 20)
 	c.conn.SetReadDeadline(time.Now().Add(2 * s.pingEvery))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(2 * s.pingEvery))
-		_ = s.memberSvc.TouchHeartbeat(ctx, c.roomID, c.userID)
+		_ = s.memberSvc.TouchHeartbeat(ctx, c.RoomID(), c.userID)
 		return nil
 	})
 
@@ -159,27 +383,40 @@ func (s *Server) readLoop(ctx context.Context, c *wsConn) {
 		if err := json.Unmarshal(data, &msg); err != nil {
 			continue
 		}
+		if s.isWaiting(c) {
+			s.sendError(c, "in_lobby", domain.ErrInLobby)
+			continue
+		}
 
 		switch msg.Type {
 		case TypeChat:
 			var p ChatPayload
 			if decode(msg.Payload, &p) == nil {
-				p.RoomID = c.roomID
+				p.RoomID = c.RoomID()
 				p.UserID = idStr
 				text := strings.TrimSpace(p.Message)
-				if text == "" {
+				if text == "" && len(p.Attachments) == 0 {
 					continue
 				}
+				// отправил — значит больше не печатает
+				if s.typing.stop(c.RoomID(), c.userID) {
+					s.broadcastTyping(TypeTypingStop, c.RoomID(), c.userID)
+				}
 
 				var (
 					msgID string
 					ts    time.Time
 				)
 				if s.chatSvc != nil {
-					if id, createdAt, err := s.chatSvc.Save(ctx, c.roomID, c.userID, text); err == nil {
+					if id, createdAt, err := s.chatSvc.Save(ctx, c.RoomID(), c.userID, text, p.Attachments); err == nil {
 						msgID, ts = id, createdAt
+					} else if len(p.Attachments) > 0 {
+						// вложения не привязались — не рассылаем сообщение со "битыми" ссылками
+						slog.Warn("ws chat save with attachments failed", "room", c.RoomID(), "user", c.userID, "err", err)
+						_ = c.Send(Message{Type: TypeError, Payload: ErrorPayload{Code: "chat_rejected", Message: err.Error()}})
+						continue
 					} else {
-						slog.Warn("ws chat save failed", "room", c.roomID, "user", c.userID, "err", err)
+						slog.Warn("ws chat save failed", "room", c.RoomID(), "user", c.userID, "err", err)
 						ts = time.Now()
 					}
 				} else {
@@ -189,9 +426,10 @@ func (s *Server) readLoop(ctx context.Context, c *wsConn) {
 
 				// ЕДИНЫЙ broadcast всем (включая отправителя). Никаких c.Send(...) такого же TypeChat.
 				out := ChatPayload{
-					RoomID:  c.roomID,
-					UserID:  idStr,
-					Message: text,
+					RoomID:      c.RoomID(),
+					UserID:      idStr,
+					Message:     text,
+					Attachments: p.Attachments,
 				}
 				if msgID != "" {
 					out.MsgID = msgID
@@ -199,7 +437,7 @@ func (s *Server) readLoop(ctx context.Context, c *wsConn) {
 				if !ts.IsZero() {
 					out.TSUnix = ts.Unix()
 				}
-				s.hub.Broadcast(c.roomID, Message{Type: TypeChat, Payload: out})
+				s.hub.Broadcast(c.RoomID(), Message{Type: TypeChat, Payload: out})
 
 				// Лёгкий ACK только отправителю, чтобы снять pending на клиенте.
 				if msgID != "" {
@@ -209,6 +447,193 @@ func (s *Server) readLoop(ctx context.Context, c *wsConn) {
 					})
 				}
 			}
+		case TypeTypingStart:
+			if s.typing.start(c.RoomID(), c.userID) {
+				s.broadcastTyping(TypeTypingStart, c.RoomID(), c.userID)
+			}
+		case TypeTypingStop:
+			if s.typing.stop(c.RoomID(), c.userID) {
+				s.broadcastTyping(TypeTypingStop, c.RoomID(), c.userID)
+			}
+		case TypePresenceUpdate:
+			var p PresenceUpdatePayload
+			if decode(msg.Payload, &p) != nil {
+				continue
+			}
+			upd := domain.PresenceUpdate{
+				Mic:        p.Mic,
+				Cam:        p.Cam,
+				Screen:     p.Screen,
+				HandRaised: p.HandRaised,
+				StatusText: p.StatusText,
+			}
+			if p.Status != nil {
+				st := domain.PresenceStatus(*p.Status)
+				upd.Status = &st
+			}
+			// рука в presence и место в очереди — одно и то же
+			if p.HandRaised != nil {
+				if err := s.toggleHand(ctx, c, *p.HandRaised); err != nil {
+					s.sendError(c, "hand_rejected", err)
+					continue
+				}
+			}
+			_, delta, err := s.presence.Update(c.RoomID(), c.userID, upd)
+			if err != nil {
+				_ = c.Send(Message{Type: TypeError, Payload: ErrorPayload{Code: "presence_rejected", Message: err.Error()}})
+				continue
+			}
+			s.broadcastPresence(c.RoomID(), c.userID, delta)
+		case TypeHandRaise:
+			if err := s.toggleHand(ctx, c, true); err != nil {
+				s.sendError(c, "hand_rejected", err)
+				continue
+			}
+			s.syncHandPresence(c.RoomID(), c.userID, true)
+		case TypeHandLower:
+			var p HandCommandPayload
+			_ = decode(msg.Payload, &p)
+			target := c.userID
+			if p.UserID != "" {
+				if target, err = strconv.ParseInt(p.UserID, 10, 64); err != nil {
+					continue
+				}
+			}
+			if target == c.userID {
+				if err := s.toggleHand(ctx, c, false); err != nil {
+					s.sendError(c, "hand_rejected", err)
+					continue
+				}
+			} else {
+				lowered, err := s.hands.Lower(ctx, c.userID, c.RoomID(), target)
+				if err != nil {
+					s.sendError(c, "hand_rejected", err)
+					continue
+				}
+				if lowered {
+					s.BroadcastHandQueue(c.RoomID())
+				}
+			}
+			s.syncHandPresence(c.RoomID(), target, false)
+		case TypeFloorNext, TypeFloorGrant:
+			var p HandCommandPayload
+			_ = decode(msg.Payload, &p)
+			d := time.Duration(p.DurationSec) * time.Second
+			var (
+				f   *domain.Floor
+				err error
+			)
+			if msg.Type == TypeFloorNext {
+				f, err = s.hands.Next(ctx, c.userID, c.RoomID(), d)
+			} else {
+				target, perr := strconv.ParseInt(p.UserID, 10, 64)
+				if perr != nil {
+					s.sendError(c, "floor_rejected", domain.ErrNotInRoom)
+					continue
+				}
+				f, err = s.hands.Grant(ctx, c.userID, c.RoomID(), target, d)
+			}
+			if err != nil {
+				s.sendError(c, "floor_rejected", err)
+				continue
+			}
+			s.BroadcastHandQueue(c.RoomID())
+			s.syncHandPresence(c.RoomID(), f.UserID, false)
+		case TypeFloorRelease:
+			released, err := s.hands.Release(ctx, c.userID, c.RoomID())
+			if err != nil {
+				s.sendError(c, "floor_rejected", err)
+				continue
+			}
+			if released {
+				s.BroadcastHandQueue(c.RoomID())
+			}
+		case TypeLobbyAdmit, TypeLobbyDeny:
+			var p HandCommandPayload
+			if decode(msg.Payload, &p) != nil {
+				continue
+			}
+			target, err := strconv.ParseInt(p.UserID, 10, 64)
+			if err != nil {
+				continue
+			}
+			if msg.Type == TypeLobbyAdmit {
+				err = s.lobby.Admit(ctx, c.userID, c.RoomID(), target)
+			} else {
+				err = s.lobby.Deny(ctx, c.userID, c.RoomID(), target)
+			}
+			if err != nil {
+				s.sendError(c, "lobby_rejected", err)
+			}
+		case TypeLobbyAdmitAll:
+			if _, err := s.lobby.Start(ctx, c.userID, c.RoomID()); err != nil {
+				s.sendError(c, "lobby_rejected", err)
+			}
+		case TypePollCreate:
+			var p PollCreatePayload
+			if decode(msg.Payload, &p) != nil {
+				continue
+			}
+			in := domain.PollInput{
+				Question:    p.Question,
+				Multi:       p.Multi,
+				Quiz:        p.Quiz,
+				ResultsMode: domain.PollResultsMode(p.Results),
+				Duration:    time.Duration(p.DurationSec) * time.Second,
+			}
+			for _, o := range p.Options {
+				in.Options = append(in.Options, domain.PollOption{Text: o.Text, Correct: o.Correct != nil && *o.Correct})
+			}
+			poll, err := s.polls.Create(ctx, c.userID, c.RoomID(), in)
+			if err != nil {
+				s.sendError(c, "poll_rejected", err)
+				continue
+			}
+			s.hub.Broadcast(c.RoomID(), Message{Type: TypePollCreated, Payload: mapPoll(poll)})
+		case TypePollVote:
+			var p PollVotePayload
+			if decode(msg.Payload, &p) != nil {
+				continue
+			}
+			poll, err := s.polls.Vote(ctx, c.RoomID(), c.userID, p.PollID, p.Options)
+			if err != nil {
+				s.sendError(c, "vote_rejected", err)
+				continue
+			}
+			_ = c.Send(Message{Type: TypePollVoted, Payload: PollClosePayload{PollID: poll.ID}})
+			if poll.ResultsMode == domain.PollResultsLive {
+				s.broadcastPollResults(ctx, poll)
+			}
+		case TypePollClose:
+			var p PollClosePayload
+			if decode(msg.Payload, &p) != nil {
+				continue
+			}
+			poll, closed, err := s.polls.Close(ctx, c.userID, c.RoomID(), p.PollID)
+			if err != nil {
+				s.sendError(c, "poll_rejected", err)
+				continue
+			}
+			if closed {
+				s.BroadcastPollClosed(poll)
+			}
+		case TypeBreakoutStart, TypeBreakoutAssign, TypeBreakoutJoin, TypeBreakoutEnd:
+			if err := s.handleBreakout(ctx, c, msg); err != nil {
+				s.sendError(c, "breakout_rejected", err)
+			}
+		case TypeReadMarker:
+			var p ReadMarkerPayload
+			if decode(msg.Payload, &p) != nil || s.chatSvc == nil {
+				continue
+			}
+			m, moved, err := s.chatSvc.MarkRead(ctx, c.RoomID(), c.userID, p.MsgID)
+			if err != nil {
+				_ = c.Send(Message{Type: TypeError, Payload: ErrorPayload{Code: "read_marker_rejected", Message: err.Error()}})
+				continue
+			}
+			if moved {
+				s.hub.Broadcast(c.RoomID(), Message{Type: TypeReadMarker, Payload: mapReadMarker(*m)})
+			}
 		default:
 			// ignore
 		}
@@ -223,7 +648,7 @@ func (s *Server) writeLoop(ctx context.Context, c *wsConn) {
 		select {
 		case <-ticker.C:
 			_ = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second))
-			_ = s.memberSvc.TouchHeartbeat(ctx, c.roomID, c.userID)
+			_ = s.memberSvc.TouchHeartbeat(ctx, c.RoomID(), c.userID)
 		case <-ctx.Done():
 			return
 		case <-c.closed:
@@ -234,6 +659,221 @@ func (s *Server) writeLoop(ctx context.Context, c *wsConn) {
 
 // --- helpers ---
 
+func (s *Server) broadcastTyping(typ, roomID string, userID int64) {
+	idStr := strconv.FormatInt(userID, 10)
+	s.hub.BroadcastExcept(roomID, idStr, Message{
+		Type:    typ,
+		Payload: TypingPayload{RoomID: roomID, UserID: idStr},
+	})
+}
+
+// BroadcastPollClosed — итоги закрытого опроса всем в комнате (и по таймеру из PollService).
+func (s *Server) BroadcastPollClosed(p *domain.Poll) {
+	out := mapPoll(p)
+	if res, err := s.polls.Results(context.Background(), p); err == nil {
+		out.ResultsData = mapPollResults(res)
+	} else {
+		slog.Warn("ws poll results failed", "poll", p.ID, "err", err)
+	}
+	s.hub.Broadcast(p.RoomID, Message{Type: TypePollClosed, Payload: out})
+}
+
+func (s *Server) broadcastPollResults(ctx context.Context, p *domain.Poll) {
+	res, err := s.polls.Results(ctx, p)
+	if err != nil {
+		slog.Warn("ws poll results failed", "poll", p.ID, "err", err)
+		return
+	}
+	s.hub.Broadcast(p.RoomID, Message{Type: TypePollResults, Payload: mapPollResults(res)})
+}
+
+// mapPoll — правильные ответы квиза раскрываем только после закрытия.
+func mapPoll(p *domain.Poll) PollPayload {
+	out := PollPayload{
+		ID:        p.ID,
+		RoomID:    p.RoomID,
+		Question:  p.Question,
+		Options:   make([]PollOptionPayload, 0, len(p.Options)),
+		Multi:     p.Multi,
+		Quiz:      p.Quiz,
+		Results:   string(p.ResultsMode),
+		Status:    string(p.Status),
+		CreatedAt: p.CreatedAt.Unix(),
+	}
+	if p.ClosesAt != nil {
+		out.ClosesAt = p.ClosesAt.Unix()
+	}
+	reveal := p.Quiz && p.Status == domain.PollClosed
+	for _, o := range p.Options {
+		item := PollOptionPayload{Text: o.Text}
+		if reveal {
+			correct := o.Correct
+			item.Correct = &correct
+		}
+		out.Options = append(out.Options, item)
+	}
+	return out
+}
+
+func mapPollResults(r *domain.PollResults) *PollResultsPayload {
+	return &PollResultsPayload{PollID: r.PollID, Voters: r.Voters, Counts: r.Counts}
+}
+
+// SessionWindowChanged — окно занятия открылось/закрылось (из ScheduleService).
+// При закрытии отключаем всех, кому вне окна быть нельзя (модераторы остаются).
+func (s *Server) SessionWindowChanged(roomID string, open bool, sess domain.Session) {
+	typ := TypeSessionClosed
+	if open {
+		typ = TypeSessionOpened
+	}
+	p := SessionPayload{RoomID: roomID}
+	if !sess.StartsAt.IsZero() {
+		p.StartsAt, p.EndsAt = sess.StartsAt.Unix(), sess.EndsAt.Unix()
+	}
+	s.hub.Broadcast(roomID, Message{Type: typ, Payload: p})
+	if open {
+		return
+	}
+
+	ctx := context.Background()
+	for _, c := range s.hub.Conns(roomID) {
+		wc, ok := c.(*wsConn)
+		if !ok {
+			continue
+		}
+		if err := s.memberSvc.CheckWindow(ctx, roomID, wc.userID); errors.Is(err, domain.ErrRoomClosed) {
+			_ = wc.Close() // readLoop завершится и уберёт участника как обычно
+		}
+	}
+}
+
+// BroadcastHandQueue — текущее состояние очереди всем в комнате.
+// Вызывается и из HandQueueService (истёк таймер выступления).
+func (s *Server) BroadcastHandQueue(roomID string) {
+	hq, err := s.hands.State(context.Background(), roomID)
+	if err != nil {
+		slog.Warn("ws hand queue state failed", "room", roomID, "err", err)
+		return
+	}
+	s.hub.Broadcast(roomID, Message{Type: TypeHandQueue, Payload: mapHandQueue(hq)})
+}
+
+// toggleHand — поднять/опустить свою руку в очереди.
+func (s *Server) toggleHand(ctx context.Context, c *wsConn, raised bool) error {
+	var (
+		changed bool
+		err     error
+	)
+	if raised {
+		changed, err = s.hands.Raise(ctx, c.RoomID(), c.userID)
+	} else {
+		changed, err = s.hands.Lower(ctx, c.userID, c.RoomID(), c.userID)
+	}
+	if err != nil {
+		return err
+	}
+	if changed {
+		s.BroadcastHandQueue(c.RoomID())
+	}
+	return nil
+}
+
+// syncHandPresence — отразить очередь в presence пользователя (если он подключён).
+func (s *Server) syncHandPresence(roomID string, userID int64, raised bool) {
+	_, d, err := s.presence.Update(roomID, userID, domain.PresenceUpdate{HandRaised: &raised})
+	if err != nil {
+		return
+	}
+	s.broadcastPresence(roomID, userID, d)
+}
+
+// leaveHandQueue — ушедший совсем пользователь покидает очередь и трибуну.
+func (s *Server) leaveHandQueue(ctx context.Context, roomID string, userID int64) {
+	lowered, err := s.hands.Lower(ctx, userID, roomID, userID)
+	if err != nil {
+		slog.Debug("ws hand lower on leave failed", "room", roomID, "user", userID, "err", err)
+	}
+	released, err := s.hands.ReleaseOwn(ctx, roomID, userID)
+	if err != nil {
+		slog.Debug("ws floor release on leave failed", "room", roomID, "user", userID, "err", err)
+	}
+	if lowered || released {
+		s.BroadcastHandQueue(roomID)
+	}
+}
+
+func (s *Server) sendError(c *wsConn, code string, err error) {
+	_ = c.Send(Message{Type: TypeError, Payload: ErrorPayload{Code: code, Message: err.Error()}})
+}
+
+func mapHandQueue(hq *domain.HandQueue) HandQueuePayload {
+	out := HandQueuePayload{RoomID: hq.RoomID, Queue: make([]HandQueueItem, 0, len(hq.Queue))}
+	for _, h := range hq.Queue {
+		out.Queue = append(out.Queue, HandQueueItem{
+			UserID:   strconv.FormatInt(h.UserID, 10),
+			RaisedAt: h.RaisedAt.Unix(),
+		})
+	}
+	if f := hq.Floor; f != nil {
+		out.Floor = &FloorPayload{
+			UserID:    strconv.FormatInt(f.UserID, 10),
+			GrantedAt: f.GrantedAt.Unix(),
+		}
+		if f.ExpiresAt != nil {
+			out.Floor.ExpiresAt = f.ExpiresAt.Unix()
+		}
+	}
+	return out
+}
+
+// broadcastPresence рассылает дельту всем, включая самого пользователя (другие его вкладки).
+func (s *Server) broadcastPresence(roomID string, userID int64, d domain.PresenceDelta) {
+	if d.Empty() {
+		return
+	}
+	out := PresenceUpdatePayload{
+		RoomID:     roomID,
+		UserID:     strconv.FormatInt(userID, 10),
+		Mic:        d.Mic,
+		Cam:        d.Cam,
+		Screen:     d.Screen,
+		HandRaised: d.HandRaised,
+		StatusText: d.StatusText,
+	}
+	if d.Status != nil {
+		st := string(*d.Status)
+		out.Status = &st
+	}
+	if d.HandRaisedAt != nil {
+		ts := d.HandRaisedAt.Unix()
+		out.HandRaisedAt = &ts
+	}
+	s.hub.Broadcast(roomID, Message{Type: TypePresenceUpdate, Payload: out})
+}
+
+func mapPresenceState(p domain.Presence) PresenceState {
+	out := PresenceState{
+		Status:     string(p.Status),
+		Mic:        p.Mic,
+		Cam:        p.Cam,
+		Screen:     p.Screen,
+		StatusText: p.StatusText,
+	}
+	if p.HandRaisedAt != nil {
+		out.HandRaisedAt = p.HandRaisedAt.Unix()
+	}
+	return out
+}
+
+func mapReadMarker(m domain.ReadMarker) ReadMarkerPayload {
+	return ReadMarkerPayload{
+		RoomID: m.RoomID,
+		UserID: strconv.FormatInt(m.UserID, 10),
+		MsgID:  m.MsgID,
+		TSUnix: m.UpdatedAt.Unix(),
+	}
+}
+
 func decode(payload interface{}, dst interface{}) error {
 	b, err := json.Marshal(payload)
 	if err != nil {
@@ -245,19 +885,28 @@ func decode(payload interface{}, dst interface{}) error {
 
 type wsConn struct {
 	conn   *websocket.Conn
-	roomID string
 	userID int64
 	sendMu chan struct{}
 	closed chan struct{}
+
+	// комната меняется при переходах между breakout-комнатами
+	roomMu sync.RWMutex
+	roomID string
+	moveMu sync.Mutex // переход и финальная уборка не должны пересекаться
+
+	state       connState     // под Server.lobbyMu
+	entered     chan struct{} // закрывается, когда подключение впервые вошло в комнату
+	enteredOnce sync.Once
 }
 
 func newWsConn(c *websocket.Conn, roomID string, userID int64) *wsConn {
 	return &wsConn{
-		conn:   c,
-		roomID: roomID,
-		userID: userID,
-		sendMu: make(chan struct{}, 1),
-		closed: make(chan struct{}),
+		conn:    c,
+		roomID:  roomID,
+		userID:  userID,
+		sendMu:  make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		entered: make(chan struct{}),
 	}
 }
 
@@ -280,4 +929,27 @@ func (c *wsConn) Close() error {
 }
 
 func (c *wsConn) UserID() string { return strconv.FormatInt(c.userID, 10) }
-func (c *wsConn) RoomID() string { return c.roomID }
+func (c *wsConn) RoomID() string {
+	c.roomMu.RLock()
+	defer c.roomMu.RUnlock()
+	return c.roomID
+}
+
+func (c *wsConn) setRoom(roomID string) {
+	c.roomMu.Lock()
+	defer c.roomMu.Unlock()
+	c.roomID = roomID
+}
+
+func (c *wsConn) markEntered() {
+	c.enteredOnce.Do(func() { close(c.entered) })
+}
+
+func (c *wsConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
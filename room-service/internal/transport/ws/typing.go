@@ -0,0 +1,93 @@
+package ws
+
+import (
+	"sync"
+	"time"
+)
+
+// typingTracker — кто сейчас печатает. Эфемерно, только в памяти.
+// typing_start от одного пользователя рассылаем не чаще throttle,
+// а если клиент пропал и не прислал typing_stop — гасим сами через ttl.
+type typingTracker struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	throttle time.Duration
+	entries  map[typingKey]*typingEntry
+	onExpire func(roomID string, userID int64)
+}
+
+type typingKey struct {
+	roomID string
+	userID int64
+}
+
+type typingEntry struct {
+	lastSent time.Time
+	timer    *time.Timer
+	gen      uint64
+}
+
+func newTypingTracker(ttl, throttle time.Duration, onExpire func(roomID string, userID int64)) *typingTracker {
+	return &typingTracker{
+		ttl:      ttl,
+		throttle: throttle,
+		entries:  make(map[typingKey]*typingEntry),
+		onExpire: onExpire,
+	}
+}
+
+// start продлевает "печатает" и говорит, надо ли рассылать событие.
+func (t *typingTracker) start(roomID string, userID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := typingKey{roomID: roomID, userID: userID}
+	now := time.Now()
+	e, ok := t.entries[k]
+	if !ok {
+		e = &typingEntry{}
+		t.entries[k] = e
+	} else {
+		e.timer.Stop()
+	}
+	e.gen++
+	gen := e.gen
+	e.timer = time.AfterFunc(t.ttl, func() { t.expire(k, gen) })
+
+	if ok && now.Sub(e.lastSent) < t.throttle {
+		return false
+	}
+	e.lastSent = now
+	return true
+}
+
+// stop — true, если пользователь действительно "печатал" (т.е. надо разослать typing_stop).
+func (t *typingTracker) stop(roomID string, userID int64) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	k := typingKey{roomID: roomID, userID: userID}
+	e, ok := t.entries[k]
+	if !ok {
+		return false
+	}
+	e.timer.Stop()
+	delete(t.entries, k)
+	return true
+}
+
+func (t *typingTracker) expire(k typingKey, gen uint64) {
+	t.mu.Lock()
+	e, ok := t.entries[k]
+	// таймер мог сработать одновременно с продлением — проверяем поколение
+	if !ok || e.gen != gen {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.entries, k)
+	t.mu.Unlock()
+
+	if t.onExpire != nil {
+		t.onExpire(k.roomID, k.userID)
+	}
+}
@@ -0,0 +1,27 @@
+-- Вложения (файлы) в чатах комнат.
+-- Сами байты лежат в storage api-gateway, здесь только метаданные и квота.
+
+CREATE TABLE IF NOT EXISTS public.room_attachments (
+  id          uuid PRIMARY KEY,
+  room_id     uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id     bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  message_id  uuid       NULL REFERENCES public.room_messages(id) ON DELETE SET NULL,
+  file_name   text   NOT NULL CHECK (char_length(file_name) BETWEEN 1 AND 255),
+  mime_type   text   NOT NULL CHECK (char_length(mime_type) BETWEEN 1 AND 255),
+  size_bytes  bigint NOT NULL CHECK (size_bytes > 0),
+  storage_key text   NOT NULL,
+  thumb_key   text       NULL,
+  created_at  timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_attachments_room_created
+  ON public.room_attachments (room_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_room_attachments_message
+  ON public.room_attachments (message_id) WHERE message_id IS NOT NULL;
+
+-- Сообщение может состоять только из вложений, поэтому пустой текст теперь допустим.
+-- Проверка "текст или вложения" живёт в ChatService.
+ALTER TABLE public.room_messages DROP CONSTRAINT IF EXISTS room_messages_text_check;
+ALTER TABLE public.room_messages
+  ADD CONSTRAINT room_messages_text_check CHECK (char_length(text) <= 4000);
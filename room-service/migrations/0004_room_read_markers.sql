@@ -0,0 +1,11 @@
+-- Маркеры прочтения: последнее прочитанное сообщение пользователя в комнате.
+-- msg_created_at дублируем, чтобы маркер двигался только вперёд и unread считался без join.
+
+CREATE TABLE IF NOT EXISTS public.room_read_markers (
+  room_id        uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id        bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  msg_id         uuid   NOT NULL REFERENCES public.room_messages(id) ON DELETE CASCADE,
+  msg_created_at timestamptz NOT NULL,
+  updated_at     timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (room_id, user_id)
+);
@@ -0,0 +1,13 @@
+-- Расширенный presence участника. Живёт в памяти room-service,
+-- сюда периодически пишется снапшот (для списков участников и рестартов).
+
+ALTER TABLE public.room_participants
+  ADD COLUMN IF NOT EXISTS presence_status     text        NOT NULL DEFAULT 'offline'
+    CHECK (presence_status IN ('online', 'away', 'busy', 'offline')),
+  ADD COLUMN IF NOT EXISTS mic_on              boolean     NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS cam_on              boolean     NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS screen_on           boolean     NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS hand_raised_at      timestamptz     NULL,
+  ADD COLUMN IF NOT EXISTS status_text         text        NOT NULL DEFAULT ''
+    CHECK (char_length(status_text) <= 100),
+  ADD COLUMN IF NOT EXISTS presence_updated_at timestamptz NOT NULL DEFAULT now();
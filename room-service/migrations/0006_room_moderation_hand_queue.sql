@@ -0,0 +1,34 @@
+-- Владелец комнаты и модераторы (нужны для очереди "поднятых рук" и дальше).
+-- owner_id NULL — старые комнаты, созданные до этой миграции.
+ALTER TABLE public.rooms
+  ADD COLUMN IF NOT EXISTS owner_id bigint NULL REFERENCES public.users(id) ON DELETE SET NULL;
+
+-- Модераторы отдельно от room_participants: участник удаляется при выходе из WS,
+-- а права должны переживать переподключение.
+CREATE TABLE IF NOT EXISTS public.room_moderators (
+  room_id    uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id    bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  granted_by bigint     NULL REFERENCES public.users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (room_id, user_id)
+);
+
+-- FIFO-очередь желающих выступить.
+CREATE TABLE IF NOT EXISTS public.room_hand_queue (
+  room_id   uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id   bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  raised_at timestamptz NOT NULL DEFAULT clock_timestamp(),
+  PRIMARY KEY (room_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_hand_queue_order
+  ON public.room_hand_queue (room_id, raised_at, user_id);
+
+-- Кто сейчас "у микрофона". expires_at NULL — без таймера.
+CREATE TABLE IF NOT EXISTS public.room_floor (
+  room_id    uuid PRIMARY KEY REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id    bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  granted_by bigint     NULL REFERENCES public.users(id) ON DELETE SET NULL,
+  granted_at timestamptz NOT NULL DEFAULT now(),
+  expires_at timestamptz NULL
+);
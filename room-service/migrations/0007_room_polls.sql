@@ -0,0 +1,38 @@
+-- Опросы и квизы в комнатах.
+
+CREATE TABLE IF NOT EXISTS public.room_polls (
+  id           uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+  room_id      uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  created_by   bigint     NULL REFERENCES public.users(id) ON DELETE SET NULL,
+  question     text   NOT NULL CHECK (char_length(question) BETWEEN 1 AND 300),
+  multi        boolean NOT NULL DEFAULT false, -- можно выбрать несколько вариантов
+  quiz         boolean NOT NULL DEFAULT false, -- есть правильные ответы
+  results_mode text   NOT NULL DEFAULT 'live' CHECK (results_mode IN ('live', 'on_close')),
+  status       text   NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+  closes_at    timestamptz NULL, -- таймер; NULL — закрывает модератор
+  created_at   timestamptz NOT NULL DEFAULT now(),
+  closed_at    timestamptz NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_polls_room_created
+  ON public.room_polls (room_id, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_room_polls_open_timed
+  ON public.room_polls (closes_at) WHERE status = 'open' AND closes_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.room_poll_options (
+  poll_id    uuid     NOT NULL REFERENCES public.room_polls(id) ON DELETE CASCADE,
+  idx        smallint NOT NULL CHECK (idx >= 0),
+  text       text     NOT NULL CHECK (char_length(text) BETWEEN 1 AND 200),
+  is_correct boolean  NOT NULL DEFAULT false,
+  PRIMARY KEY (poll_id, idx)
+);
+
+-- Один голос на пользователя — первичный ключ. Для multi выбранные варианты в массиве.
+CREATE TABLE IF NOT EXISTS public.room_poll_votes (
+  poll_id    uuid       NOT NULL REFERENCES public.room_polls(id) ON DELETE CASCADE,
+  user_id    bigint     NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  options    smallint[] NOT NULL CHECK (cardinality(options) >= 1),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (poll_id, user_id)
+);
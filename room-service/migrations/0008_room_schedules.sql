@@ -0,0 +1,14 @@
+-- Расписание занятий комнаты: одна серия на комнату.
+-- rrule — правило повтора без DTSTART (напр. FREQ=WEEKLY;BYDAY=TU), пусто — разовое занятие.
+-- Повтор разворачивается в timezone, чтобы "вторник 19:00" не съезжал при переходе на летнее время.
+CREATE TABLE IF NOT EXISTS public.room_schedules (
+  room_id         uuid PRIMARY KEY REFERENCES public.rooms(id) ON DELETE CASCADE,
+  starts_at       timestamptz NOT NULL,
+  duration_sec    integer NOT NULL CHECK (duration_sec BETWEEN 60 AND 86400),
+  rrule           text NOT NULL DEFAULT '',
+  timezone        text NOT NULL DEFAULT 'UTC',
+  open_before_sec integer NOT NULL DEFAULT 0 CHECK (open_before_sec BETWEEN 0 AND 3600), -- за сколько до начала пускаем
+  created_by      bigint NULL REFERENCES public.users(id) ON DELETE SET NULL,
+  created_at      timestamptz NOT NULL DEFAULT now(),
+  updated_at      timestamptz NOT NULL DEFAULT now()
+);
@@ -0,0 +1,15 @@
+-- Лобби: пока хост не начал занятие, вошедшие ждут отдельно и не занимают места в комнате.
+-- session_started_at NULL — занятие не начато (сбрасывается при закрытии окна расписания).
+ALTER TABLE public.rooms
+  ADD COLUMN IF NOT EXISTS lobby_enabled      boolean     NOT NULL DEFAULT false,
+  ADD COLUMN IF NOT EXISTS session_started_at timestamptz NULL;
+
+CREATE TABLE IF NOT EXISTS public.room_lobby (
+  room_id   uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  user_id   bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  queued_at timestamptz NOT NULL DEFAULT clock_timestamp(),
+  PRIMARY KEY (room_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_room_lobby_order
+  ON public.room_lobby (room_id, queued_at, user_id);
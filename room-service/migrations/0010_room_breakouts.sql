@@ -0,0 +1,21 @@
+-- Breakout-комнаты: дочерние комнаты ссылаются на основную через rooms.parent_id.
+ALTER TABLE public.rooms
+  ADD COLUMN IF NOT EXISTS parent_id uuid NULL REFERENCES public.rooms(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_rooms_parent ON public.rooms (parent_id) WHERE parent_id IS NOT NULL;
+
+-- Активная сессия breakout'ов основной комнаты (одна за раз). По ends_at всех возвращают обратно.
+CREATE TABLE IF NOT EXISTS public.room_breakouts (
+  parent_id  uuid PRIMARY KEY REFERENCES public.rooms(id) ON DELETE CASCADE,
+  ends_at    timestamptz NOT NULL,
+  created_by bigint NULL REFERENCES public.users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+-- Кто в какую дочернюю комнату распределён: по ней же возвращаем при переподключении.
+CREATE TABLE IF NOT EXISTS public.room_breakout_assignments (
+  parent_id uuid   NOT NULL REFERENCES public.room_breakouts(parent_id) ON DELETE CASCADE,
+  user_id   bigint NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  room_id   uuid   NOT NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  PRIMARY KEY (parent_id, user_id)
+);
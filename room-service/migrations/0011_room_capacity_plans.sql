@@ -0,0 +1,12 @@
+-- Лимит участников больше не зашит в схему: его задаёт конфиг и тариф пользователя,
+-- в БД остаётся только верхняя граница на всякий случай.
+ALTER TABLE public.rooms DROP CONSTRAINT IF EXISTS rooms_max_participants_check;
+ALTER TABLE public.rooms
+  ADD CONSTRAINT rooms_max_participants_check CHECK (max_participants >= 1 AND max_participants <= 10000);
+
+-- Тариф пользователя. Нет строки — тариф по умолчанию из конфига (rooms.defaultPlan).
+CREATE TABLE IF NOT EXISTS public.user_plans (
+  user_id    bigint PRIMARY KEY REFERENCES public.users(id) ON DELETE CASCADE,
+  plan       text NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
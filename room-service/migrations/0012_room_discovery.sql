@@ -0,0 +1,9 @@
+-- Поиск комнат: описание, тема, язык и теги.
+ALTER TABLE public.rooms
+  ADD COLUMN IF NOT EXISTS description text NULL,
+  ADD COLUMN IF NOT EXISTS topic       text NULL,
+  ADD COLUMN IF NOT EXISTS language    text NULL,           -- ISO 639-1, в нижнем регистре
+  ADD COLUMN IF NOT EXISTS tags        text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_rooms_tags     ON public.rooms USING gin (tags);
+CREATE INDEX IF NOT EXISTS idx_rooms_language ON public.rooms (language) WHERE language IS NOT NULL;
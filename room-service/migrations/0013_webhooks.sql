@@ -0,0 +1,49 @@
+-- Исходящие вебхуки: подписки владельцев, outbox событий и доставки с ретраями.
+-- webhook_events пишут и room-service, и auth-service (user.registered) — база общая.
+-- owner_id NULL — системное событие, уходит всем подписчикам этого типа.
+CREATE TABLE IF NOT EXISTS public.webhook_subscriptions (
+  id         uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+  owner_id   bigint      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  url        text        NOT NULL,
+  secret     text        NOT NULL,
+  events     text[]      NOT NULL,
+  active     boolean     NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_owner
+  ON public.webhook_subscriptions (owner_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_events
+  ON public.webhook_subscriptions USING gin (events) WHERE active;
+
+CREATE TABLE IF NOT EXISTS public.webhook_events (
+  id            uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+  type          text        NOT NULL,
+  owner_id      bigint      NULL,
+  payload       jsonb       NOT NULL,
+  created_at    timestamptz NOT NULL DEFAULT clock_timestamp(),
+  dispatched_at timestamptz NULL -- разложено по доставкам
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_events_pending
+  ON public.webhook_events (created_at) WHERE dispatched_at IS NULL;
+
+-- status: pending -> delivered | dead (исчерпаны попытки, можно переотправить вручную)
+CREATE TABLE IF NOT EXISTS public.webhook_deliveries (
+  id              uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+  subscription_id uuid        NOT NULL REFERENCES public.webhook_subscriptions(id) ON DELETE CASCADE,
+  event_id        uuid        NOT NULL REFERENCES public.webhook_events(id) ON DELETE CASCADE,
+  status          text        NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'dead')),
+  attempts        int         NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT now(),
+  last_status     int         NULL, -- HTTP-код последней попытки
+  last_error      text        NULL,
+  created_at      timestamptz NOT NULL DEFAULT clock_timestamp(),
+  delivered_at    timestamptz NULL,
+  UNIQUE (subscription_id, event_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due
+  ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_sub
+  ON public.webhook_deliveries (subscription_id, created_at DESC, id DESC);
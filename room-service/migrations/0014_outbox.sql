@@ -0,0 +1,22 @@
+-- Transactional outbox доменных событий (github.com/cwrk-planet/events/pkg/outbox).
+-- Пишут room-service и auth-service в своих транзакциях, relay room-service раскладывает
+-- по sink-ам (WS-хаб, вебхуки, NATS). delivered_to — кому уже доставлено, повтор идёт только остальным.
+CREATE TABLE IF NOT EXISTS public.outbox (
+  seq             bigserial   NOT NULL,
+  id              uuid        PRIMARY KEY,
+  type            text        NOT NULL,
+  source          text        NOT NULL,
+  key             text        NOT NULL DEFAULT '',
+  payload         jsonb       NOT NULL,
+  occurred_at     timestamptz NOT NULL,
+  delivered_to    text[]      NOT NULL DEFAULT '{}',
+  attempts        int         NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT now(),
+  last_error      text,
+  published_at    timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending
+  ON public.outbox (next_attempt_at, seq) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_published
+  ON public.outbox (published_at) WHERE published_at IS NOT NULL;
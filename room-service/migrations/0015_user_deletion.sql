@@ -0,0 +1,17 @@
+-- Удаление аккаунта (auth-service): сообщения и вложения остаются в комнатах,
+-- но без автора — user_id обнуляется вместо каскадного удаления.
+ALTER TABLE public.room_messages ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE public.room_messages DROP CONSTRAINT IF EXISTS room_messages_user_id_fkey;
+ALTER TABLE public.room_messages
+  ADD CONSTRAINT room_messages_user_id_fkey
+  FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+ALTER TABLE public.room_attachments ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE public.room_attachments DROP CONSTRAINT IF EXISTS room_attachments_user_id_fkey;
+ALTER TABLE public.room_attachments
+  ADD CONSTRAINT room_attachments_user_id_fkey
+  FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE SET NULL;
+
+-- экспорт своих сообщений по всем комнатам
+CREATE INDEX IF NOT EXISTS idx_room_messages_user_created_desc
+  ON public.room_messages (user_id, created_at DESC, id DESC) WHERE user_id IS NOT NULL;
@@ -0,0 +1,13 @@
+-- Ленты .ics для календарей: календарь не умеет слать Authorization, поэтому доступ — по токену в ссылке.
+-- Хранится только sha256 токена; удаление строки отзывает ссылку.
+-- room_id NULL — занятия по всем комнатам пользователя.
+CREATE TABLE IF NOT EXISTS public.calendar_feeds (
+  id         uuid        PRIMARY KEY DEFAULT gen_random_uuid(),
+  user_id    bigint      NOT NULL REFERENCES public.users(id) ON DELETE CASCADE,
+  room_id    uuid        NULL REFERENCES public.rooms(id) ON DELETE CASCADE,
+  token_hash text        NOT NULL UNIQUE,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feeds_user
+  ON public.calendar_feeds (user_id, created_at);